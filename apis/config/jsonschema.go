@@ -0,0 +1,326 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+//go:generate go run ./schemagen
+
+// JSONSchemaDir is the directory of this package holding the JSON schemas of the plugin args, one
+// <version>/<kind>.json file per args kind, generated from the versioned types.
+const JSONSchemaDir = "schema"
+
+const (
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	validationMarker = "+kubebuilder:validation:"
+)
+
+//go:embed schema
+var jsonSchemas embed.FS
+
+// JSONSchema is the subset of the JSON schema describing plugin args.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+}
+
+// ArgsJSONSchema returns the JSON schema of the args of a plugin, e.g. CoschedulingArgs, in version,
+// e.g. v1beta3, for config validation tools and editors to validate scheduler configurations.
+func ArgsJSONSchema(version, kind string) ([]byte, error) {
+	data, err := jsonSchemas.ReadFile(path.Join(JSONSchemaDir, version, kind+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no JSON schema of %s in version %q", kind, version)
+	}
+	return data, nil
+}
+
+// ArgsJSONSchemaKinds returns the kinds of the plugin args with a JSON schema in version.
+func ArgsJSONSchemaKinds(version string) ([]string, error) {
+	entries, err := jsonSchemas.ReadDir(path.Join(JSONSchemaDir, version))
+	if err != nil {
+		return nil, fmt.Errorf("no JSON schemas in version %q", version)
+	}
+	var kinds []string
+	for _, e := range entries {
+		kinds = append(kinds, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	return kinds, nil
+}
+
+// GenerateArgsJSONSchemas generates the JSON schemas of the plugin args of this repository registered
+// in scheme for gv, keyed by kind. The descriptions, enumerations and validation markers are parsed
+// from typesFile, the source of the versioned types, and the defaults are the ones set by scheme.
+func GenerateArgsJSONSchemas(scheme *runtime.Scheme, gv schema.GroupVersion, typesFile string) (map[string][]byte, error) {
+	pkgPath := path.Join(reflect.TypeOf(CoschedulingArgs{}).PkgPath(), gv.Version)
+	docs, err := parseTypeDocs(typesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string][]byte)
+	for kind, t := range scheme.KnownTypes(gv) {
+		if t.PkgPath() != pkgPath || !strings.HasSuffix(kind, "Args") {
+			continue
+		}
+		defaulted, ok := reflect.New(t).Interface().(runtime.Object)
+		if !ok {
+			continue
+		}
+		scheme.Default(defaulted)
+		defaultsJSON, err := json.Marshal(defaulted)
+		if err != nil {
+			return nil, err
+		}
+		var defaults interface{}
+		if err := json.Unmarshal(defaultsJSON, &defaults); err != nil {
+			return nil, err
+		}
+
+		g := &schemaGenerator{pkgPath: pkgPath, docs: docs}
+		s := g.schema(t, defaults, "")
+		s.Schema = jsonSchemaDraft
+		s.Title = kind
+		s.Description, _ = splitMarkers(docs.types[t.Name()])
+		if schemas[kind], err = json.MarshalIndent(s, "", "  "); err != nil {
+			return nil, err
+		}
+		schemas[kind] = append(schemas[kind], '\n')
+	}
+	return schemas, nil
+}
+
+// typeDocs holds what the source of the versioned types tells beyond their Go types.
+type typeDocs struct {
+	// types are the doc comments of the types, by type name.
+	types map[string]string
+	// fields are the doc comments of the fields, by type name and field name.
+	fields map[string]string
+	// enums are the constants of the string types, by type name.
+	enums map[string][]string
+}
+
+// parseTypeDocs parses the doc comments and the string constants of the types declared in filename.
+func parseTypeDocs(filename string) (*typeDocs, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	docs := &typeDocs{types: map[string]string{}, fields: map[string]string{}, enums: map[string][]string{}}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				doc := spec.Doc
+				if doc == nil {
+					doc = gen.Doc
+				}
+				docs.types[spec.Name.Name] = doc.Text()
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range field.Names {
+						docs.fields[spec.Name.Name+"."+name.Name] = field.Doc.Text()
+					}
+				}
+			case *ast.ValueSpec:
+				ident, ok := spec.Type.(*ast.Ident)
+				if !ok || gen.Tok != token.CONST {
+					continue
+				}
+				for _, value := range spec.Values {
+					if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if v, err := strconv.Unquote(lit.Value); err == nil {
+							docs.enums[ident.Name] = append(docs.enums[ident.Name], v)
+						}
+					}
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// schemaGenerator generates the JSON schemas of the Go types by reflection.
+type schemaGenerator struct {
+	pkgPath string
+	docs    *typeDocs
+}
+
+// schema returns the schema of t, whose default value is defaults, documented by doc.
+func (g *schemaGenerator) schema(t reflect.Type, defaults interface{}, doc string) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := &JSONSchema{}
+	description, markers := splitMarkers(doc)
+	s.Description = description
+
+	switch {
+	case t.PkgPath() == "k8s.io/apimachinery/pkg/api/resource" && t.Name() == "Quantity":
+		s.AnyOf = []*JSONSchema{{Type: "integer"}, {Type: "string"}}
+	case t.PkgPath() == "k8s.io/apimachinery/pkg/apis/meta/v1" && t.Name() == "Duration":
+		s.Type = "string"
+	default:
+		switch t.Kind() {
+		case reflect.Struct:
+			s.Type = "object"
+			s.Properties = make(map[string]*JSONSchema)
+			s.AdditionalProperties = false
+			g.addProperties(s, t, defaults)
+			defaults = nil
+		case reflect.Map:
+			s.Type = "object"
+			s.AdditionalProperties = g.schema(t.Elem(), nil, "")
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				s.Type = "string"
+				break
+			}
+			s.Type = "array"
+			s.Items = g.schema(t.Elem(), nil, "")
+		case reflect.String:
+			s.Type = "string"
+			if t.PkgPath() == g.pkgPath {
+				s.Enum = g.docs.enums[t.Name()]
+			}
+		case reflect.Bool:
+			s.Type = "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s.Type = "integer"
+			if t.Kind() == reflect.Int32 || t.Kind() == reflect.Int64 {
+				s.Format = t.Kind().String()
+			}
+		case reflect.Float32, reflect.Float64:
+			s.Type = "number"
+		}
+	}
+
+	s.Default = defaults
+	for name, value := range markers {
+		switch name {
+		case "Minimum", "Maximum":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if name == "Minimum" {
+				s.Minimum = &v
+			} else {
+				s.Maximum = &v
+			}
+		case "Enum":
+			s.Enum = strings.Split(value, ";")
+		}
+	}
+	return s
+}
+
+// addProperties adds the JSON fields of struct t, inlined ones included, to the properties of s.
+func (g *schemaGenerator) addProperties(s *JSONSchema, t reflect.Type, defaults interface{}) {
+	defaultValues, _ := defaults.(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, opts := parseJSONTag(field.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && (name == "" || opts["inline"]) {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addProperties(s, embedded, defaults)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		var doc string
+		if t.PkgPath() == g.pkgPath {
+			doc = g.docs.fields[t.Name()+"."+field.Name]
+		}
+		s.Properties[name] = g.schema(field.Type, defaultValues[name], doc)
+	}
+}
+
+// splitMarkers splits a doc comment into its description and its validation markers.
+func splitMarkers(doc string) (string, map[string]string) {
+	markers := make(map[string]string)
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, validationMarker) {
+			marker := strings.SplitN(strings.TrimPrefix(trimmed, validationMarker), "=", 2)
+			if len(marker) == 2 {
+				markers[marker[0]] = marker[1]
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "+") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return strings.Join(lines, " "), markers
+}
+
+func parseJSONTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	opts := make(map[string]bool)
+	for _, o := range parts[1:] {
+		opts[o] = true
+	}
+	return parts[0], opts
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/scheme"
+	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta2"
+	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta3"
+)
+
+func TestArgsJSONSchemasUpToDate(t *testing.T) {
+	for _, gv := range []schema.GroupVersion{v1beta2.SchemeGroupVersion, v1beta3.SchemeGroupVersion} {
+		t.Run(gv.Version, func(t *testing.T) {
+			generated, err := config.GenerateArgsJSONSchemas(scheme.Scheme, gv, filepath.Join(gv.Version, "types.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var generatedKinds []string
+			for kind := range generated {
+				generatedKinds = append(generatedKinds, kind)
+			}
+			sort.Strings(generatedKinds)
+			kinds, err := config.ArgsJSONSchemaKinds(gv.Version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(generatedKinds, kinds); diff != "" {
+				t.Fatalf("unexpected JSON schemas (-generated, +embedded), run go generate ./apis/config:\n%s", diff)
+			}
+			for _, kind := range kinds {
+				embedded, err := config.ArgsJSONSchema(gv.Version, kind)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(string(generated[kind]), string(embedded)); diff != "" {
+					t.Errorf("JSON schema of %s out of date (-generated, +embedded), run go generate ./apis/config:\n%s", kind, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestArgsJSONSchema(t *testing.T) {
+	data, err := config.ArgsJSONSchema("v1beta3", "TargetLoadPackingArgs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s config.JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.AdditionalProperties != false {
+		t.Errorf("expected the unknown fields to be rejected, got additionalProperties %v", s.AdditionalProperties)
+	}
+	for _, name := range []string{"apiVersion", "kind", "defaultRequests", "targetUtilization", "coldStartPolicy"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("expected property %q in %v", name, s.Properties)
+		}
+	}
+	pressure := s.Properties["pressureThresholdPercent"]
+	if pressure == nil || pressure.Minimum == nil || *pressure.Minimum != 0 || pressure.Maximum == nil || *pressure.Maximum != 99 {
+		t.Errorf("expected pressureThresholdPercent within [0, 99], got %+v", pressure)
+	}
+	if diff := cmp.Diff([]string{"MinScore", "RequestsBased"}, s.Properties["coldStartPolicy"].Enum); diff != "" {
+		t.Errorf("unexpected coldStartPolicy enum (-want, +got):\n%s", diff)
+	}
+	if got := s.Properties["targetUtilization"].Default; got != float64(40) {
+		t.Errorf("expected targetUtilization to default to 40, got %v", got)
+	}
+
+	if _, err := config.ArgsJSONSchema("v1beta3", "UnknownArgs"); err == nil {
+		t.Error("expected an error for unknown args")
+	}
+}
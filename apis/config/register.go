@@ -40,6 +40,18 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&LoadVariationRiskBalancingArgs{},
 		&NodeResourceTopologyMatchArgs{},
 		&PreemptionTolerationArgs{},
+		&TopologicalImageLocalityArgs{},
+		&CELPolicyArgs{},
+		&SpotAwarenessArgs{},
+		&CapacitySchedulingArgs{},
+		&CrossRegionRateLimitArgs{},
+		&TopologicalSortArgs{},
+		&LinkHeadroomArgs{},
+		&WarmPeersArgs{},
+		&BackfillArgs{},
+		&NoisyNeighborArgs{},
+		&PlacementLabelsArgs{},
+		&ZoneLimitArgs{},
 	)
 	return nil
 }
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// schemagen writes the JSON schemas of the plugin args embedded in the config package. It is run
+// by go generate from the directory of the config package.
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/scheme"
+	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta2"
+	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta3"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the config package")
+	flag.Parse()
+
+	for _, gv := range []schema.GroupVersion{v1beta2.SchemeGroupVersion, v1beta3.SchemeGroupVersion} {
+		if err := writeSchemas(*dir, gv); err != nil {
+			klog.ErrorS(err, "Cannot generate the JSON schemas", "version", gv.Version)
+			os.Exit(1)
+		}
+	}
+}
+
+func writeSchemas(dir string, gv schema.GroupVersion) error {
+	schemas, err := config.GenerateArgsJSONSchemas(scheme.Scheme, gv, filepath.Join(dir, gv.Version, "types.go"))
+	if err != nil {
+		return err
+	}
+	out := filepath.Join(dir, config.JSONSchemaDir, gv.Version)
+	// Start afresh so that the schemas of the removed args do not linger.
+	if err := os.RemoveAll(out); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+	for kind, data := range schemas {
+		if err := os.WriteFile(filepath.Join(out, kind+".json"), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
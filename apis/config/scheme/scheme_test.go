@@ -105,12 +105,14 @@ profiles:
 							Args: &config.CoschedulingArgs{
 								PermitWaitingTimeSeconds:      10,
 								DeniedPGExpirationTimeSeconds: 3,
+								BindParallelism:               16,
 							},
 						},
 						{
 							Name: noderesources.AllocatableName,
 							Args: &config.NodeResourcesAllocatableArgs{
-								Mode: config.Least,
+								Mode:            config.Least,
+								GPUResourceName: "nvidia.com/gpu",
 								Resources: []schedconfig.ResourceSpec{
 									{Name: string(corev1.ResourceCPU), Weight: 1000000},
 									{Name: string(corev1.ResourceMemory), Weight: 1},
@@ -216,12 +218,14 @@ profiles:
 							Args: &config.CoschedulingArgs{
 								PermitWaitingTimeSeconds:      60,
 								DeniedPGExpirationTimeSeconds: 20,
+								BindParallelism:               16,
 							},
 						},
 						{
 							Name: noderesources.AllocatableName,
 							Args: &config.NodeResourcesAllocatableArgs{
-								Mode: config.Least,
+								Mode:            config.Least,
+								GPUResourceName: "nvidia.com/gpu",
 								Resources: []schedconfig.ResourceSpec{
 									{Name: string(corev1.ResourceCPU), Weight: 1048576},
 									{Name: string(corev1.ResourceMemory), Weight: 1},
@@ -430,13 +434,19 @@ profiles:
 - pluginConfig:
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1beta2
+      bindParallelism: 0
       deniedPGExpirationTimeSeconds: 3
       kind: CoschedulingArgs
+      kubeAPIBurst: 0
+      kubeAPIQPS: 0
+      maxWaitingPods: 0
       permitWaitingTimeSeconds: 10
     name: Coscheduling
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1beta2
       kind: NodeResourcesAllocatableArgs
+      kubeAPIBurst: 0
+      kubeAPIQPS: 0
       mode: Least
       resources:
       - name: cpu
@@ -28,9 +28,41 @@ import (
 var (
 	defaultPermitWaitingTimeSeconds      int64 = 60
 	defaultDeniedPGExpirationTimeSeconds int64 = 20
+	defaultBindParallelism               int64 = 16
+
+	defaultNetworkTopologyNamespace = "default"
+	defaultWeightsName              = "UserDefined"
+
+	defaultPreemptionDryRun = false
+
+	defaultCELPolicyDefaultAction = CELPolicyAllow
+
+	defaultCrossRegionMaxPlacementsPerMinute int32 = 60
+	defaultCrossRegionBurst                  int32 = 10
+	defaultCrossRegionMaxWaitSeconds         int64 = 60
+
+	defaultCrossRegionIgnoreNotReadyNodes      = false
+	defaultCrossRegionIgnoreUnschedulableNodes = false
+
+	defaultStarvationThresholdSeconds int64 = 300
+	defaultStarvationIndexBoost       int32 = 1
+
+	// defaultSpotNodeLabels mark the spot nodes of AWS, Azure and GCP node pools, and of Karpenter.
+	defaultSpotNodeLabels = map[string]string{
+		"eks.amazonaws.com/capacityType":        "SPOT",
+		"karpenter.sh/capacity-type":            "spot",
+		"kubernetes.azure.com/scalesetpriority": "spot",
+		"cloud.google.com/gke-spot":             "true",
+		"cloud.google.com/gke-preemptible":      "true",
+	}
+	defaultSpotInterruptionRisk int64 = 50
 
 	defaultNodeResourcesAllocatableMode = Least
 
+	// defaultNodeResourcesAllocatableGPUResourceName is the resource physical GPUs are exposed as
+	// by the NVIDIA device plugin.
+	defaultNodeResourcesAllocatableGPUResourceName = "nvidia.com/gpu"
+
 	// defaultResourcesToWeightMap is used to set the default resourceToWeight map for CPU and memory
 	// used by the NodeResourcesAllocatable scoring plugin.
 	// The base unit for CPU is millicore, while the base using for memory is a byte.
@@ -81,6 +113,9 @@ func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
 	if obj.DeniedPGExpirationTimeSeconds == nil {
 		obj.DeniedPGExpirationTimeSeconds = &defaultDeniedPGExpirationTimeSeconds
 	}
+	if obj.BindParallelism == nil {
+		obj.BindParallelism = &defaultBindParallelism
+	}
 }
 
 // SetDefaults_NodeResourcesAllocatableArgs sets the defaults parameters for NodeResourceAllocatable.
@@ -92,6 +127,10 @@ func SetDefaults_NodeResourcesAllocatableArgs(obj *NodeResourcesAllocatableArgs)
 	if obj.Mode == "" {
 		obj.Mode = defaultNodeResourcesAllocatableMode
 	}
+
+	if obj.GPUResourceName == "" {
+		obj.GPUResourceName = defaultNodeResourcesAllocatableGPUResourceName
+	}
 }
 
 // SetDefaults_TargetLoadPackingArgs sets the default parameters for TargetLoadPacking plugin
@@ -155,3 +194,95 @@ func SetDefaults_NodeResourceTopologyMatchArgs(obj *NodeResourceTopologyMatchArg
 func SetDefaults_PreemptionTolerationArgs(obj *PreemptionTolerationArgs) {
 	k8sschedulerconfigv1beta2.SetDefaults_DefaultPreemptionArgs((*schedulerconfigv1beta2.DefaultPreemptionArgs)(obj))
 }
+
+// SetDefaults_TopologicalImageLocalityArgs sets the default parameters for the TopologicalImageLocality plugin.
+func SetDefaults_TopologicalImageLocalityArgs(obj *TopologicalImageLocalityArgs) {
+	if obj.NetworkTopologyNamespace == nil {
+		obj.NetworkTopologyNamespace = &defaultNetworkTopologyNamespace
+	}
+	if obj.WeightsName == nil {
+		obj.WeightsName = &defaultWeightsName
+	}
+}
+
+// SetDefaults_CELPolicyArgs sets the default parameters for the CELPolicy plugin.
+func SetDefaults_CELPolicyArgs(obj *CELPolicyArgs) {
+	if obj.DefaultAction == "" {
+		obj.DefaultAction = defaultCELPolicyDefaultAction
+	}
+	if obj.NetworkTopologyNamespace == nil {
+		obj.NetworkTopologyNamespace = &defaultNetworkTopologyNamespace
+	}
+	if obj.WeightsName == nil {
+		obj.WeightsName = &defaultWeightsName
+	}
+}
+
+// SetDefaults_SpotAwarenessArgs sets the default parameters for the SpotAwareness plugin.
+func SetDefaults_SpotAwarenessArgs(obj *SpotAwarenessArgs) {
+	if obj.SpotNodeLabels == nil {
+		obj.SpotNodeLabels = make(map[string]string, len(defaultSpotNodeLabels))
+		for k, v := range defaultSpotNodeLabels {
+			obj.SpotNodeLabels[k] = v
+		}
+	}
+	if obj.DefaultSpotInterruptionRisk == nil {
+		obj.DefaultSpotInterruptionRisk = &defaultSpotInterruptionRisk
+	}
+}
+
+// SetDefaults_CapacitySchedulingArgs sets the default parameters for the CapacityScheduling plugin.
+func SetDefaults_CapacitySchedulingArgs(obj *CapacitySchedulingArgs) {
+	if obj.PreemptionDryRun == nil {
+		obj.PreemptionDryRun = &defaultPreemptionDryRun
+	}
+}
+
+// SetDefaults_CrossRegionRateLimitArgs sets the default parameters for the CrossRegionRateLimit plugin.
+func SetDefaults_CrossRegionRateLimitArgs(obj *CrossRegionRateLimitArgs) {
+	if obj.MaxPlacementsPerMinute == nil {
+		obj.MaxPlacementsPerMinute = &defaultCrossRegionMaxPlacementsPerMinute
+	}
+	if obj.Burst == nil {
+		obj.Burst = &defaultCrossRegionBurst
+	}
+	if obj.MaxWaitSeconds == nil {
+		obj.MaxWaitSeconds = &defaultCrossRegionMaxWaitSeconds
+	}
+	if obj.IgnoreNotReadyNodes == nil {
+		obj.IgnoreNotReadyNodes = &defaultCrossRegionIgnoreNotReadyNodes
+	}
+	if obj.IgnoreUnschedulableNodes == nil {
+		obj.IgnoreUnschedulableNodes = &defaultCrossRegionIgnoreUnschedulableNodes
+	}
+}
+
+// SetDefaults_LinkHeadroomArgs sets the default parameters for the LinkHeadroom plugin.
+func SetDefaults_LinkHeadroomArgs(obj *LinkHeadroomArgs) {
+	if obj.NetworkTopologyNamespace == nil {
+		obj.NetworkTopologyNamespace = &defaultNetworkTopologyNamespace
+	}
+	if obj.WeightsName == nil {
+		obj.WeightsName = &defaultWeightsName
+	}
+}
+
+// SetDefaults_TopologicalSortArgs sets the default parameters for the TopologicalSort plugin.
+func SetDefaults_TopologicalSortArgs(obj *TopologicalSortArgs) {
+	if obj.StarvationThresholdSeconds == nil {
+		obj.StarvationThresholdSeconds = &defaultStarvationThresholdSeconds
+	}
+	if obj.StarvationIndexBoost == nil {
+		obj.StarvationIndexBoost = &defaultStarvationIndexBoost
+	}
+}
+
+// SetDefaults_WarmPeersArgs sets the default parameters for the WarmPeers plugin.
+func SetDefaults_WarmPeersArgs(obj *WarmPeersArgs) {
+	if obj.NetworkTopologyNamespace == nil {
+		obj.NetworkTopologyNamespace = &defaultNetworkTopologyNamespace
+	}
+	if obj.WeightsName == nil {
+		obj.WeightsName = &defaultWeightsName
+	}
+}
@@ -40,6 +40,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*BackfillArgs)(nil), (*config.BackfillArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BackfillArgs_To_config_BackfillArgs(a.(*BackfillArgs), b.(*config.BackfillArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackfillArgs)(nil), (*BackfillArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackfillArgs_To_v1beta2_BackfillArgs(a.(*config.BackfillArgs), b.(*BackfillArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CELPolicyArgs)(nil), (*config.CELPolicyArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CELPolicyArgs_To_config_CELPolicyArgs(a.(*CELPolicyArgs), b.(*config.CELPolicyArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CELPolicyArgs)(nil), (*CELPolicyArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CELPolicyArgs_To_v1beta2_CELPolicyArgs(a.(*config.CELPolicyArgs), b.(*CELPolicyArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CELPolicyRule)(nil), (*config.CELPolicyRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CELPolicyRule_To_config_CELPolicyRule(a.(*CELPolicyRule), b.(*config.CELPolicyRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CELPolicyRule)(nil), (*CELPolicyRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CELPolicyRule_To_v1beta2_CELPolicyRule(a.(*config.CELPolicyRule), b.(*CELPolicyRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CapacitySchedulingArgs)(nil), (*config.CapacitySchedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(a.(*CapacitySchedulingArgs), b.(*config.CapacitySchedulingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CapacitySchedulingArgs)(nil), (*CapacitySchedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CapacitySchedulingArgs_To_v1beta2_CapacitySchedulingArgs(a.(*config.CapacitySchedulingArgs), b.(*CapacitySchedulingArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CoschedulingArgs)(nil), (*config.CoschedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_CoschedulingArgs_To_config_CoschedulingArgs(a.(*CoschedulingArgs), b.(*config.CoschedulingArgs), scope)
 	}); err != nil {
@@ -50,6 +90,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CrossRegionRateLimitArgs)(nil), (*config.CrossRegionRateLimitArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CrossRegionRateLimitArgs_To_config_CrossRegionRateLimitArgs(a.(*CrossRegionRateLimitArgs), b.(*config.CrossRegionRateLimitArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CrossRegionRateLimitArgs)(nil), (*CrossRegionRateLimitArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CrossRegionRateLimitArgs_To_v1beta2_CrossRegionRateLimitArgs(a.(*config.CrossRegionRateLimitArgs), b.(*CrossRegionRateLimitArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GPUResourceFraction)(nil), (*config.GPUResourceFraction)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_GPUResourceFraction_To_config_GPUResourceFraction(a.(*GPUResourceFraction), b.(*config.GPUResourceFraction), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GPUResourceFraction)(nil), (*GPUResourceFraction)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GPUResourceFraction_To_v1beta2_GPUResourceFraction(a.(*config.GPUResourceFraction), b.(*GPUResourceFraction), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LinkHeadroomArgs)(nil), (*config.LinkHeadroomArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LinkHeadroomArgs_To_config_LinkHeadroomArgs(a.(*LinkHeadroomArgs), b.(*config.LinkHeadroomArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LinkHeadroomArgs)(nil), (*LinkHeadroomArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LinkHeadroomArgs_To_v1beta2_LinkHeadroomArgs(a.(*config.LinkHeadroomArgs), b.(*LinkHeadroomArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadVariationRiskBalancingArgs)(nil), (*config.LoadVariationRiskBalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(a.(*LoadVariationRiskBalancingArgs), b.(*config.LoadVariationRiskBalancingArgs), scope)
 	}); err != nil {
@@ -90,6 +160,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NoisyNeighborArgs)(nil), (*config.NoisyNeighborArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NoisyNeighborArgs_To_config_NoisyNeighborArgs(a.(*NoisyNeighborArgs), b.(*config.NoisyNeighborArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NoisyNeighborArgs)(nil), (*NoisyNeighborArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NoisyNeighborArgs_To_v1beta2_NoisyNeighborArgs(a.(*config.NoisyNeighborArgs), b.(*NoisyNeighborArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PlacementLabelsArgs)(nil), (*config.PlacementLabelsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PlacementLabelsArgs_To_config_PlacementLabelsArgs(a.(*PlacementLabelsArgs), b.(*config.PlacementLabelsArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PlacementLabelsArgs)(nil), (*PlacementLabelsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PlacementLabelsArgs_To_v1beta2_PlacementLabelsArgs(a.(*config.PlacementLabelsArgs), b.(*PlacementLabelsArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PreemptionTolerationArgs)(nil), (*config.PreemptionTolerationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(a.(*PreemptionTolerationArgs), b.(*config.PreemptionTolerationArgs), scope)
 	}); err != nil {
@@ -110,6 +200,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SoftEvictionArgs)(nil), (*config.SoftEvictionArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SoftEvictionArgs_To_config_SoftEvictionArgs(a.(*SoftEvictionArgs), b.(*config.SoftEvictionArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SoftEvictionArgs)(nil), (*SoftEvictionArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SoftEvictionArgs_To_v1beta2_SoftEvictionArgs(a.(*config.SoftEvictionArgs), b.(*SoftEvictionArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SpotAwarenessArgs)(nil), (*config.SpotAwarenessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SpotAwarenessArgs_To_config_SpotAwarenessArgs(a.(*SpotAwarenessArgs), b.(*config.SpotAwarenessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SpotAwarenessArgs)(nil), (*SpotAwarenessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SpotAwarenessArgs_To_v1beta2_SpotAwarenessArgs(a.(*config.SpotAwarenessArgs), b.(*SpotAwarenessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SpotPriorityClassPreference)(nil), (*config.SpotPriorityClassPreference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SpotPriorityClassPreference_To_config_SpotPriorityClassPreference(a.(*SpotPriorityClassPreference), b.(*config.SpotPriorityClassPreference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SpotPriorityClassPreference)(nil), (*SpotPriorityClassPreference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SpotPriorityClassPreference_To_v1beta2_SpotPriorityClassPreference(a.(*config.SpotPriorityClassPreference), b.(*SpotPriorityClassPreference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetLoadPackingArgs)(nil), (*config.TargetLoadPackingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_TargetLoadPackingArgs_To_config_TargetLoadPackingArgs(a.(*TargetLoadPackingArgs), b.(*config.TargetLoadPackingArgs), scope)
 	}); err != nil {
@@ -120,9 +240,193 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TopologicalImageLocalityArgs)(nil), (*config.TopologicalImageLocalityArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_TopologicalImageLocalityArgs_To_config_TopologicalImageLocalityArgs(a.(*TopologicalImageLocalityArgs), b.(*config.TopologicalImageLocalityArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TopologicalImageLocalityArgs)(nil), (*TopologicalImageLocalityArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TopologicalImageLocalityArgs_To_v1beta2_TopologicalImageLocalityArgs(a.(*config.TopologicalImageLocalityArgs), b.(*TopologicalImageLocalityArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TopologicalSortArgs)(nil), (*config.TopologicalSortArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_TopologicalSortArgs_To_config_TopologicalSortArgs(a.(*TopologicalSortArgs), b.(*config.TopologicalSortArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TopologicalSortArgs)(nil), (*TopologicalSortArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TopologicalSortArgs_To_v1beta2_TopologicalSortArgs(a.(*config.TopologicalSortArgs), b.(*TopologicalSortArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WarmPeersArgs)(nil), (*config.WarmPeersArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_WarmPeersArgs_To_config_WarmPeersArgs(a.(*WarmPeersArgs), b.(*config.WarmPeersArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.WarmPeersArgs)(nil), (*WarmPeersArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_WarmPeersArgs_To_v1beta2_WarmPeersArgs(a.(*config.WarmPeersArgs), b.(*WarmPeersArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ZoneLimitArgs)(nil), (*config.ZoneLimitArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ZoneLimitArgs_To_config_ZoneLimitArgs(a.(*ZoneLimitArgs), b.(*config.ZoneLimitArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ZoneLimitArgs)(nil), (*ZoneLimitArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ZoneLimitArgs_To_v1beta2_ZoneLimitArgs(a.(*config.ZoneLimitArgs), b.(*ZoneLimitArgs), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta2_BackfillArgs_To_config_BackfillArgs(in *BackfillArgs, out *config.BackfillArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_BackfillArgs_To_config_BackfillArgs is an autogenerated conversion function.
+func Convert_v1beta2_BackfillArgs_To_config_BackfillArgs(in *BackfillArgs, out *config.BackfillArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_BackfillArgs_To_config_BackfillArgs(in, out, s)
+}
+
+func autoConvert_config_BackfillArgs_To_v1beta2_BackfillArgs(in *config.BackfillArgs, out *BackfillArgs, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_BackfillArgs_To_v1beta2_BackfillArgs is an autogenerated conversion function.
+func Convert_config_BackfillArgs_To_v1beta2_BackfillArgs(in *config.BackfillArgs, out *BackfillArgs, s conversion.Scope) error {
+	return autoConvert_config_BackfillArgs_To_v1beta2_BackfillArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_CELPolicyArgs_To_config_CELPolicyArgs(in *CELPolicyArgs, out *config.CELPolicyArgs, s conversion.Scope) error {
+	out.DefaultAction = config.CELPolicyAction(in.DefaultAction)
+	out.Rules = *(*[]config.CELPolicyRule)(unsafe.Pointer(&in.Rules))
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_CELPolicyArgs_To_config_CELPolicyArgs is an autogenerated conversion function.
+func Convert_v1beta2_CELPolicyArgs_To_config_CELPolicyArgs(in *CELPolicyArgs, out *config.CELPolicyArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_CELPolicyArgs_To_config_CELPolicyArgs(in, out, s)
+}
+
+func autoConvert_config_CELPolicyArgs_To_v1beta2_CELPolicyArgs(in *config.CELPolicyArgs, out *CELPolicyArgs, s conversion.Scope) error {
+	out.DefaultAction = CELPolicyAction(in.DefaultAction)
+	out.Rules = *(*[]CELPolicyRule)(unsafe.Pointer(&in.Rules))
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
 	return nil
 }
 
+// Convert_config_CELPolicyArgs_To_v1beta2_CELPolicyArgs is an autogenerated conversion function.
+func Convert_config_CELPolicyArgs_To_v1beta2_CELPolicyArgs(in *config.CELPolicyArgs, out *CELPolicyArgs, s conversion.Scope) error {
+	return autoConvert_config_CELPolicyArgs_To_v1beta2_CELPolicyArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_CELPolicyRule_To_config_CELPolicyRule(in *CELPolicyRule, out *config.CELPolicyRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Expression = in.Expression
+	out.Action = config.CELPolicyAction(in.Action)
+	out.Score = in.Score
+	return nil
+}
+
+// Convert_v1beta2_CELPolicyRule_To_config_CELPolicyRule is an autogenerated conversion function.
+func Convert_v1beta2_CELPolicyRule_To_config_CELPolicyRule(in *CELPolicyRule, out *config.CELPolicyRule, s conversion.Scope) error {
+	return autoConvert_v1beta2_CELPolicyRule_To_config_CELPolicyRule(in, out, s)
+}
+
+func autoConvert_config_CELPolicyRule_To_v1beta2_CELPolicyRule(in *config.CELPolicyRule, out *CELPolicyRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Expression = in.Expression
+	out.Action = CELPolicyAction(in.Action)
+	out.Score = in.Score
+	return nil
+}
+
+// Convert_config_CELPolicyRule_To_v1beta2_CELPolicyRule is an autogenerated conversion function.
+func Convert_config_CELPolicyRule_To_v1beta2_CELPolicyRule(in *config.CELPolicyRule, out *CELPolicyRule, s conversion.Scope) error {
+	return autoConvert_config_CELPolicyRule_To_v1beta2_CELPolicyRule(in, out, s)
+}
+
+func autoConvert_v1beta2_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in *CapacitySchedulingArgs, out *config.CapacitySchedulingArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.PreemptionDryRun, &out.PreemptionDryRun, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs is an autogenerated conversion function.
+func Convert_v1beta2_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in *CapacitySchedulingArgs, out *config.CapacitySchedulingArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in, out, s)
+}
+
+func autoConvert_config_CapacitySchedulingArgs_To_v1beta2_CapacitySchedulingArgs(in *config.CapacitySchedulingArgs, out *CapacitySchedulingArgs, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.PreemptionDryRun, &out.PreemptionDryRun, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_CapacitySchedulingArgs_To_v1beta2_CapacitySchedulingArgs is an autogenerated conversion function.
+func Convert_config_CapacitySchedulingArgs_To_v1beta2_CapacitySchedulingArgs(in *config.CapacitySchedulingArgs, out *CapacitySchedulingArgs, s conversion.Scope) error {
+	return autoConvert_config_CapacitySchedulingArgs_To_v1beta2_CapacitySchedulingArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_CoschedulingArgs_To_config_CoschedulingArgs(in *CoschedulingArgs, out *config.CoschedulingArgs, s conversion.Scope) error {
 	if err := v1.Convert_Pointer_int64_To_int64(&in.PermitWaitingTimeSeconds, &out.PermitWaitingTimeSeconds, s); err != nil {
 		return err
@@ -130,6 +434,18 @@ func autoConvert_v1beta2_CoschedulingArgs_To_config_CoschedulingArgs(in *Cosched
 	if err := v1.Convert_Pointer_int64_To_int64(&in.DeniedPGExpirationTimeSeconds, &out.DeniedPGExpirationTimeSeconds, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.BindParallelism, &out.BindParallelism, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.MaxWaitingPods, &out.MaxWaitingPods, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -145,6 +461,18 @@ func autoConvert_config_CoschedulingArgs_To_v1beta2_CoschedulingArgs(in *config.
 	if err := v1.Convert_int64_To_Pointer_int64(&in.DeniedPGExpirationTimeSeconds, &out.DeniedPGExpirationTimeSeconds, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.BindParallelism, &out.BindParallelism, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.MaxWaitingPods, &out.MaxWaitingPods, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -153,6 +481,126 @@ func Convert_config_CoschedulingArgs_To_v1beta2_CoschedulingArgs(in *config.Cosc
 	return autoConvert_config_CoschedulingArgs_To_v1beta2_CoschedulingArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_CrossRegionRateLimitArgs_To_config_CrossRegionRateLimitArgs(in *CrossRegionRateLimitArgs, out *config.CrossRegionRateLimitArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPlacementsPerMinute, &out.MaxPlacementsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.Burst, &out.Burst, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.MaxWaitSeconds, &out.MaxWaitSeconds, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.IgnoreNotReadyNodes, &out.IgnoreNotReadyNodes, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.IgnoreUnschedulableNodes, &out.IgnoreUnschedulableNodes, s); err != nil {
+		return err
+	}
+	out.IgnoredTaintKeys = *(*[]string)(unsafe.Pointer(&in.IgnoredTaintKeys))
+	return nil
+}
+
+// Convert_v1beta2_CrossRegionRateLimitArgs_To_config_CrossRegionRateLimitArgs is an autogenerated conversion function.
+func Convert_v1beta2_CrossRegionRateLimitArgs_To_config_CrossRegionRateLimitArgs(in *CrossRegionRateLimitArgs, out *config.CrossRegionRateLimitArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_CrossRegionRateLimitArgs_To_config_CrossRegionRateLimitArgs(in, out, s)
+}
+
+func autoConvert_config_CrossRegionRateLimitArgs_To_v1beta2_CrossRegionRateLimitArgs(in *config.CrossRegionRateLimitArgs, out *CrossRegionRateLimitArgs, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPlacementsPerMinute, &out.MaxPlacementsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.Burst, &out.Burst, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.MaxWaitSeconds, &out.MaxWaitSeconds, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.IgnoreNotReadyNodes, &out.IgnoreNotReadyNodes, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.IgnoreUnschedulableNodes, &out.IgnoreUnschedulableNodes, s); err != nil {
+		return err
+	}
+	out.IgnoredTaintKeys = *(*[]string)(unsafe.Pointer(&in.IgnoredTaintKeys))
+	return nil
+}
+
+// Convert_config_CrossRegionRateLimitArgs_To_v1beta2_CrossRegionRateLimitArgs is an autogenerated conversion function.
+func Convert_config_CrossRegionRateLimitArgs_To_v1beta2_CrossRegionRateLimitArgs(in *config.CrossRegionRateLimitArgs, out *CrossRegionRateLimitArgs, s conversion.Scope) error {
+	return autoConvert_config_CrossRegionRateLimitArgs_To_v1beta2_CrossRegionRateLimitArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_GPUResourceFraction_To_config_GPUResourceFraction(in *GPUResourceFraction, out *config.GPUResourceFraction, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Fraction = in.Fraction
+	return nil
+}
+
+// Convert_v1beta2_GPUResourceFraction_To_config_GPUResourceFraction is an autogenerated conversion function.
+func Convert_v1beta2_GPUResourceFraction_To_config_GPUResourceFraction(in *GPUResourceFraction, out *config.GPUResourceFraction, s conversion.Scope) error {
+	return autoConvert_v1beta2_GPUResourceFraction_To_config_GPUResourceFraction(in, out, s)
+}
+
+func autoConvert_config_GPUResourceFraction_To_v1beta2_GPUResourceFraction(in *config.GPUResourceFraction, out *GPUResourceFraction, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Fraction = in.Fraction
+	return nil
+}
+
+// Convert_config_GPUResourceFraction_To_v1beta2_GPUResourceFraction is an autogenerated conversion function.
+func Convert_config_GPUResourceFraction_To_v1beta2_GPUResourceFraction(in *config.GPUResourceFraction, out *GPUResourceFraction, s conversion.Scope) error {
+	return autoConvert_config_GPUResourceFraction_To_v1beta2_GPUResourceFraction(in, out, s)
+}
+
+func autoConvert_v1beta2_LinkHeadroomArgs_To_config_LinkHeadroomArgs(in *LinkHeadroomArgs, out *config.LinkHeadroomArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_LinkHeadroomArgs_To_config_LinkHeadroomArgs is an autogenerated conversion function.
+func Convert_v1beta2_LinkHeadroomArgs_To_config_LinkHeadroomArgs(in *LinkHeadroomArgs, out *config.LinkHeadroomArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_LinkHeadroomArgs_To_config_LinkHeadroomArgs(in, out, s)
+}
+
+func autoConvert_config_LinkHeadroomArgs_To_v1beta2_LinkHeadroomArgs(in *config.LinkHeadroomArgs, out *LinkHeadroomArgs, s conversion.Scope) error {
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_LinkHeadroomArgs_To_v1beta2_LinkHeadroomArgs is an autogenerated conversion function.
+func Convert_config_LinkHeadroomArgs_To_v1beta2_LinkHeadroomArgs(in *config.LinkHeadroomArgs, out *LinkHeadroomArgs, s conversion.Scope) error {
+	return autoConvert_config_LinkHeadroomArgs_To_v1beta2_LinkHeadroomArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs, out *config.LoadVariationRiskBalancingArgs, s conversion.Scope) error {
 	if err := Convert_v1beta2_MetricProviderSpec_To_config_MetricProviderSpec(&in.MetricProvider, &out.MetricProvider, s); err != nil {
 		return err
@@ -166,6 +614,10 @@ func autoConvert_v1beta2_LoadVariationRiskBalancingArgs_To_config_LoadVariationR
 	if err := v1.Convert_Pointer_float64_To_float64(&in.SafeVarianceSensitivity, &out.SafeVarianceSensitivity, s); err != nil {
 		return err
 	}
+	out.IncludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.IncludedOperatingSystems))
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ColdStartPolicy = config.ColdStartPolicy(in.ColdStartPolicy)
+	out.SoftEviction = (*config.SoftEvictionArgs)(unsafe.Pointer(in.SoftEviction))
 	return nil
 }
 
@@ -187,6 +639,10 @@ func autoConvert_config_LoadVariationRiskBalancingArgs_To_v1beta2_LoadVariationR
 	if err := v1.Convert_float64_To_Pointer_float64(&in.SafeVarianceSensitivity, &out.SafeVarianceSensitivity, s); err != nil {
 		return err
 	}
+	out.IncludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.IncludedOperatingSystems))
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ColdStartPolicy = ColdStartPolicy(in.ColdStartPolicy)
+	out.SoftEviction = (*SoftEvictionArgs)(unsafe.Pointer(in.SoftEviction))
 	return nil
 }
 
@@ -237,6 +693,12 @@ func autoConvert_v1beta2_NodeResourceTopologyMatchArgs_To_config_NodeResourceTop
 	// WARNING: in.ScoringStrategy requires manual conversion: inconvertible types (*sigs.k8s.io/scheduler-plugins/apis/config/v1beta2.ScoringStrategy vs sigs.k8s.io/scheduler-plugins/apis/config.ScoringStrategy)
 	// Added manually
 	out.ScoringStrategy = *(*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -249,6 +711,12 @@ func autoConvert_config_NodeResourceTopologyMatchArgs_To_v1beta2_NodeResourceTop
 	// WARNING: in.ScoringStrategy requires manual conversion: inconvertible types (sigs.k8s.io/scheduler-plugins/apis/config.ScoringStrategy vs *sigs.k8s.io/scheduler-plugins/apis/config/v1beta2.ScoringStrategy)
 	// Added manually
 	out.ScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(&in.ScoringStrategy))
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -260,6 +728,17 @@ func Convert_config_NodeResourceTopologyMatchArgs_To_v1beta2_NodeResourceTopolog
 func autoConvert_v1beta2_NodeResourcesAllocatableArgs_To_config_NodeResourcesAllocatableArgs(in *NodeResourcesAllocatableArgs, out *config.NodeResourcesAllocatableArgs, s conversion.Scope) error {
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
 	out.Mode = config.ModeType(in.Mode)
+	out.GPUResourceName = in.GPUResourceName
+	out.GPUResourceFractions = *(*[]config.GPUResourceFraction)(unsafe.Pointer(&in.GPUResourceFractions))
+	out.DiskPressureThresholdPercent = in.DiskPressureThresholdPercent
+	out.VirtualNodeProfiles = in.VirtualNodeProfiles
+	out.NodeGroupLabel = in.NodeGroupLabel
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -271,6 +750,17 @@ func Convert_v1beta2_NodeResourcesAllocatableArgs_To_config_NodeResourcesAllocat
 func autoConvert_config_NodeResourcesAllocatableArgs_To_v1beta2_NodeResourcesAllocatableArgs(in *config.NodeResourcesAllocatableArgs, out *NodeResourcesAllocatableArgs, s conversion.Scope) error {
 	out.Resources = *(*[]configv1beta2.ResourceSpec)(unsafe.Pointer(&in.Resources))
 	out.Mode = ModeType(in.Mode)
+	out.GPUResourceName = in.GPUResourceName
+	out.GPUResourceFractions = *(*[]GPUResourceFraction)(unsafe.Pointer(&in.GPUResourceFractions))
+	out.DiskPressureThresholdPercent = in.DiskPressureThresholdPercent
+	out.VirtualNodeProfiles = in.VirtualNodeProfiles
+	out.NodeGroupLabel = in.NodeGroupLabel
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -279,6 +769,66 @@ func Convert_config_NodeResourcesAllocatableArgs_To_v1beta2_NodeResourcesAllocat
 	return autoConvert_config_NodeResourcesAllocatableArgs_To_v1beta2_NodeResourcesAllocatableArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_NoisyNeighborArgs_To_config_NoisyNeighborArgs(in *NoisyNeighborArgs, out *config.NoisyNeighborArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NoisyNeighborArgs_To_config_NoisyNeighborArgs is an autogenerated conversion function.
+func Convert_v1beta2_NoisyNeighborArgs_To_config_NoisyNeighborArgs(in *NoisyNeighborArgs, out *config.NoisyNeighborArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_NoisyNeighborArgs_To_config_NoisyNeighborArgs(in, out, s)
+}
+
+func autoConvert_config_NoisyNeighborArgs_To_v1beta2_NoisyNeighborArgs(in *config.NoisyNeighborArgs, out *NoisyNeighborArgs, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_NoisyNeighborArgs_To_v1beta2_NoisyNeighborArgs is an autogenerated conversion function.
+func Convert_config_NoisyNeighborArgs_To_v1beta2_NoisyNeighborArgs(in *config.NoisyNeighborArgs, out *NoisyNeighborArgs, s conversion.Scope) error {
+	return autoConvert_config_NoisyNeighborArgs_To_v1beta2_NoisyNeighborArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_PlacementLabelsArgs_To_config_PlacementLabelsArgs(in *PlacementLabelsArgs, out *config.PlacementLabelsArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_PlacementLabelsArgs_To_config_PlacementLabelsArgs is an autogenerated conversion function.
+func Convert_v1beta2_PlacementLabelsArgs_To_config_PlacementLabelsArgs(in *PlacementLabelsArgs, out *config.PlacementLabelsArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_PlacementLabelsArgs_To_config_PlacementLabelsArgs(in, out, s)
+}
+
+func autoConvert_config_PlacementLabelsArgs_To_v1beta2_PlacementLabelsArgs(in *config.PlacementLabelsArgs, out *PlacementLabelsArgs, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_PlacementLabelsArgs_To_v1beta2_PlacementLabelsArgs is an autogenerated conversion function.
+func Convert_config_PlacementLabelsArgs_To_v1beta2_PlacementLabelsArgs(in *config.PlacementLabelsArgs, out *PlacementLabelsArgs, s conversion.Scope) error {
+	return autoConvert_config_PlacementLabelsArgs_To_v1beta2_PlacementLabelsArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(in *PreemptionTolerationArgs, out *config.PreemptionTolerationArgs, s conversion.Scope) error {
 	if err := v1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
 		return err
@@ -331,6 +881,80 @@ func Convert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in *config.Scorin
 	return autoConvert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in, out, s)
 }
 
+func autoConvert_v1beta2_SoftEvictionArgs_To_config_SoftEvictionArgs(in *SoftEvictionArgs, out *config.SoftEvictionArgs, s conversion.Scope) error {
+	out.RiskThreshold = in.RiskThreshold
+	out.ConsecutiveUpdates = in.ConsecutiveUpdates
+	out.MaxPods = in.MaxPods
+	return nil
+}
+
+// Convert_v1beta2_SoftEvictionArgs_To_config_SoftEvictionArgs is an autogenerated conversion function.
+func Convert_v1beta2_SoftEvictionArgs_To_config_SoftEvictionArgs(in *SoftEvictionArgs, out *config.SoftEvictionArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_SoftEvictionArgs_To_config_SoftEvictionArgs(in, out, s)
+}
+
+func autoConvert_config_SoftEvictionArgs_To_v1beta2_SoftEvictionArgs(in *config.SoftEvictionArgs, out *SoftEvictionArgs, s conversion.Scope) error {
+	out.RiskThreshold = in.RiskThreshold
+	out.ConsecutiveUpdates = in.ConsecutiveUpdates
+	out.MaxPods = in.MaxPods
+	return nil
+}
+
+// Convert_config_SoftEvictionArgs_To_v1beta2_SoftEvictionArgs is an autogenerated conversion function.
+func Convert_config_SoftEvictionArgs_To_v1beta2_SoftEvictionArgs(in *config.SoftEvictionArgs, out *SoftEvictionArgs, s conversion.Scope) error {
+	return autoConvert_config_SoftEvictionArgs_To_v1beta2_SoftEvictionArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_SpotAwarenessArgs_To_config_SpotAwarenessArgs(in *SpotAwarenessArgs, out *config.SpotAwarenessArgs, s conversion.Scope) error {
+	out.SpotNodeLabels = *(*map[string]string)(unsafe.Pointer(&in.SpotNodeLabels))
+	if err := v1.Convert_Pointer_int64_To_int64(&in.DefaultSpotInterruptionRisk, &out.DefaultSpotInterruptionRisk, s); err != nil {
+		return err
+	}
+	out.PriorityClassPreferences = *(*[]config.SpotPriorityClassPreference)(unsafe.Pointer(&in.PriorityClassPreferences))
+	return nil
+}
+
+// Convert_v1beta2_SpotAwarenessArgs_To_config_SpotAwarenessArgs is an autogenerated conversion function.
+func Convert_v1beta2_SpotAwarenessArgs_To_config_SpotAwarenessArgs(in *SpotAwarenessArgs, out *config.SpotAwarenessArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_SpotAwarenessArgs_To_config_SpotAwarenessArgs(in, out, s)
+}
+
+func autoConvert_config_SpotAwarenessArgs_To_v1beta2_SpotAwarenessArgs(in *config.SpotAwarenessArgs, out *SpotAwarenessArgs, s conversion.Scope) error {
+	out.SpotNodeLabels = *(*map[string]string)(unsafe.Pointer(&in.SpotNodeLabels))
+	if err := v1.Convert_int64_To_Pointer_int64(&in.DefaultSpotInterruptionRisk, &out.DefaultSpotInterruptionRisk, s); err != nil {
+		return err
+	}
+	out.PriorityClassPreferences = *(*[]SpotPriorityClassPreference)(unsafe.Pointer(&in.PriorityClassPreferences))
+	return nil
+}
+
+// Convert_config_SpotAwarenessArgs_To_v1beta2_SpotAwarenessArgs is an autogenerated conversion function.
+func Convert_config_SpotAwarenessArgs_To_v1beta2_SpotAwarenessArgs(in *config.SpotAwarenessArgs, out *SpotAwarenessArgs, s conversion.Scope) error {
+	return autoConvert_config_SpotAwarenessArgs_To_v1beta2_SpotAwarenessArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_SpotPriorityClassPreference_To_config_SpotPriorityClassPreference(in *SpotPriorityClassPreference, out *config.SpotPriorityClassPreference, s conversion.Scope) error {
+	out.PriorityClassName = in.PriorityClassName
+	out.Preference = config.CapacityPreference(in.Preference)
+	return nil
+}
+
+// Convert_v1beta2_SpotPriorityClassPreference_To_config_SpotPriorityClassPreference is an autogenerated conversion function.
+func Convert_v1beta2_SpotPriorityClassPreference_To_config_SpotPriorityClassPreference(in *SpotPriorityClassPreference, out *config.SpotPriorityClassPreference, s conversion.Scope) error {
+	return autoConvert_v1beta2_SpotPriorityClassPreference_To_config_SpotPriorityClassPreference(in, out, s)
+}
+
+func autoConvert_config_SpotPriorityClassPreference_To_v1beta2_SpotPriorityClassPreference(in *config.SpotPriorityClassPreference, out *SpotPriorityClassPreference, s conversion.Scope) error {
+	out.PriorityClassName = in.PriorityClassName
+	out.Preference = CapacityPreference(in.Preference)
+	return nil
+}
+
+// Convert_config_SpotPriorityClassPreference_To_v1beta2_SpotPriorityClassPreference is an autogenerated conversion function.
+func Convert_config_SpotPriorityClassPreference_To_v1beta2_SpotPriorityClassPreference(in *config.SpotPriorityClassPreference, out *SpotPriorityClassPreference, s conversion.Scope) error {
+	return autoConvert_config_SpotPriorityClassPreference_To_v1beta2_SpotPriorityClassPreference(in, out, s)
+}
+
 func autoConvert_v1beta2_TargetLoadPackingArgs_To_config_TargetLoadPackingArgs(in *TargetLoadPackingArgs, out *config.TargetLoadPackingArgs, s conversion.Scope) error {
 	out.DefaultRequests = *(*corev1.ResourceList)(unsafe.Pointer(&in.DefaultRequests))
 	if err := v1.Convert_Pointer_string_To_string(&in.DefaultRequestsMultiplier, &out.DefaultRequestsMultiplier, s); err != nil {
@@ -345,6 +969,12 @@ func autoConvert_v1beta2_TargetLoadPackingArgs_To_config_TargetLoadPackingArgs(i
 	if err := v1.Convert_Pointer_string_To_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	out.ScoreBudgetMilliseconds = in.ScoreBudgetMilliseconds
+	out.PressureThresholdPercent = in.PressureThresholdPercent
+	out.TargetUtilizationPerOperatingSystem = *(*map[string]int64)(unsafe.Pointer(&in.TargetUtilizationPerOperatingSystem))
+	out.IncludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.IncludedOperatingSystems))
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ColdStartPolicy = config.ColdStartPolicy(in.ColdStartPolicy)
 	return nil
 }
 
@@ -367,6 +997,12 @@ func autoConvert_config_TargetLoadPackingArgs_To_v1beta2_TargetLoadPackingArgs(i
 	if err := v1.Convert_string_To_Pointer_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	out.ScoreBudgetMilliseconds = in.ScoreBudgetMilliseconds
+	out.PressureThresholdPercent = in.PressureThresholdPercent
+	out.TargetUtilizationPerOperatingSystem = *(*map[string]int64)(unsafe.Pointer(&in.TargetUtilizationPerOperatingSystem))
+	out.IncludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.IncludedOperatingSystems))
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ColdStartPolicy = ColdStartPolicy(in.ColdStartPolicy)
 	return nil
 }
 
@@ -374,3 +1010,177 @@ func autoConvert_config_TargetLoadPackingArgs_To_v1beta2_TargetLoadPackingArgs(i
 func Convert_config_TargetLoadPackingArgs_To_v1beta2_TargetLoadPackingArgs(in *config.TargetLoadPackingArgs, out *TargetLoadPackingArgs, s conversion.Scope) error {
 	return autoConvert_config_TargetLoadPackingArgs_To_v1beta2_TargetLoadPackingArgs(in, out, s)
 }
+
+func autoConvert_v1beta2_TopologicalImageLocalityArgs_To_config_TopologicalImageLocalityArgs(in *TopologicalImageLocalityArgs, out *config.TopologicalImageLocalityArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	out.ScoreBudgetMilliseconds = in.ScoreBudgetMilliseconds
+	out.VirtualNodeProfiles = in.VirtualNodeProfiles
+	out.StaleCostThresholdSeconds = in.StaleCostThresholdSeconds
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_TopologicalImageLocalityArgs_To_config_TopologicalImageLocalityArgs is an autogenerated conversion function.
+func Convert_v1beta2_TopologicalImageLocalityArgs_To_config_TopologicalImageLocalityArgs(in *TopologicalImageLocalityArgs, out *config.TopologicalImageLocalityArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_TopologicalImageLocalityArgs_To_config_TopologicalImageLocalityArgs(in, out, s)
+}
+
+func autoConvert_config_TopologicalImageLocalityArgs_To_v1beta2_TopologicalImageLocalityArgs(in *config.TopologicalImageLocalityArgs, out *TopologicalImageLocalityArgs, s conversion.Scope) error {
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	out.ScoreBudgetMilliseconds = in.ScoreBudgetMilliseconds
+	out.VirtualNodeProfiles = in.VirtualNodeProfiles
+	out.StaleCostThresholdSeconds = in.StaleCostThresholdSeconds
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_TopologicalImageLocalityArgs_To_v1beta2_TopologicalImageLocalityArgs is an autogenerated conversion function.
+func Convert_config_TopologicalImageLocalityArgs_To_v1beta2_TopologicalImageLocalityArgs(in *config.TopologicalImageLocalityArgs, out *TopologicalImageLocalityArgs, s conversion.Scope) error {
+	return autoConvert_config_TopologicalImageLocalityArgs_To_v1beta2_TopologicalImageLocalityArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_TopologicalSortArgs_To_config_TopologicalSortArgs(in *TopologicalSortArgs, out *config.TopologicalSortArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int64_To_int64(&in.StarvationThresholdSeconds, &out.StarvationThresholdSeconds, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.StarvationIndexBoost, &out.StarvationIndexBoost, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_TopologicalSortArgs_To_config_TopologicalSortArgs is an autogenerated conversion function.
+func Convert_v1beta2_TopologicalSortArgs_To_config_TopologicalSortArgs(in *TopologicalSortArgs, out *config.TopologicalSortArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_TopologicalSortArgs_To_config_TopologicalSortArgs(in, out, s)
+}
+
+func autoConvert_config_TopologicalSortArgs_To_v1beta2_TopologicalSortArgs(in *config.TopologicalSortArgs, out *TopologicalSortArgs, s conversion.Scope) error {
+	if err := v1.Convert_int64_To_Pointer_int64(&in.StarvationThresholdSeconds, &out.StarvationThresholdSeconds, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.StarvationIndexBoost, &out.StarvationIndexBoost, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_TopologicalSortArgs_To_v1beta2_TopologicalSortArgs is an autogenerated conversion function.
+func Convert_config_TopologicalSortArgs_To_v1beta2_TopologicalSortArgs(in *config.TopologicalSortArgs, out *TopologicalSortArgs, s conversion.Scope) error {
+	return autoConvert_config_TopologicalSortArgs_To_v1beta2_TopologicalSortArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_WarmPeersArgs_To_config_WarmPeersArgs(in *WarmPeersArgs, out *config.WarmPeersArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_WarmPeersArgs_To_config_WarmPeersArgs is an autogenerated conversion function.
+func Convert_v1beta2_WarmPeersArgs_To_config_WarmPeersArgs(in *WarmPeersArgs, out *config.WarmPeersArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_WarmPeersArgs_To_config_WarmPeersArgs(in, out, s)
+}
+
+func autoConvert_config_WarmPeersArgs_To_v1beta2_WarmPeersArgs(in *config.WarmPeersArgs, out *WarmPeersArgs, s conversion.Scope) error {
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.NetworkTopologyNamespace, &out.NetworkTopologyNamespace, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_WarmPeersArgs_To_v1beta2_WarmPeersArgs is an autogenerated conversion function.
+func Convert_config_WarmPeersArgs_To_v1beta2_WarmPeersArgs(in *config.WarmPeersArgs, out *WarmPeersArgs, s conversion.Scope) error {
+	return autoConvert_config_WarmPeersArgs_To_v1beta2_WarmPeersArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_ZoneLimitArgs_To_config_ZoneLimitArgs(in *ZoneLimitArgs, out *config.ZoneLimitArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_ZoneLimitArgs_To_config_ZoneLimitArgs is an autogenerated conversion function.
+func Convert_v1beta2_ZoneLimitArgs_To_config_ZoneLimitArgs(in *ZoneLimitArgs, out *config.ZoneLimitArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_ZoneLimitArgs_To_config_ZoneLimitArgs(in, out, s)
+}
+
+func autoConvert_config_ZoneLimitArgs_To_v1beta2_ZoneLimitArgs(in *config.ZoneLimitArgs, out *ZoneLimitArgs, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIQPS, &out.KubeAPIQPS, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.KubeAPIBurst, &out.KubeAPIBurst, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ZoneLimitArgs_To_v1beta2_ZoneLimitArgs is an autogenerated conversion function.
+func Convert_config_ZoneLimitArgs_To_v1beta2_ZoneLimitArgs(in *config.ZoneLimitArgs, out *ZoneLimitArgs, s conversion.Scope) error {
+	return autoConvert_config_ZoneLimitArgs_To_v1beta2_ZoneLimitArgs(in, out, s)
+}
@@ -29,7 +29,11 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&CELPolicyArgs{}, func(obj interface{}) { SetObjectDefaults_CELPolicyArgs(obj.(*CELPolicyArgs)) })
+	scheme.AddTypeDefaultingFunc(&CapacitySchedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CapacitySchedulingArgs(obj.(*CapacitySchedulingArgs)) })
 	scheme.AddTypeDefaultingFunc(&CoschedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CoschedulingArgs(obj.(*CoschedulingArgs)) })
+	scheme.AddTypeDefaultingFunc(&CrossRegionRateLimitArgs{}, func(obj interface{}) { SetObjectDefaults_CrossRegionRateLimitArgs(obj.(*CrossRegionRateLimitArgs)) })
+	scheme.AddTypeDefaultingFunc(&LinkHeadroomArgs{}, func(obj interface{}) { SetObjectDefaults_LinkHeadroomArgs(obj.(*LinkHeadroomArgs)) })
 	scheme.AddTypeDefaultingFunc(&LoadVariationRiskBalancingArgs{}, func(obj interface{}) {
 		SetObjectDefaults_LoadVariationRiskBalancingArgs(obj.(*LoadVariationRiskBalancingArgs))
 	})
@@ -40,14 +44,36 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 		SetObjectDefaults_NodeResourcesAllocatableArgs(obj.(*NodeResourcesAllocatableArgs))
 	})
 	scheme.AddTypeDefaultingFunc(&PreemptionTolerationArgs{}, func(obj interface{}) { SetObjectDefaults_PreemptionTolerationArgs(obj.(*PreemptionTolerationArgs)) })
+	scheme.AddTypeDefaultingFunc(&SpotAwarenessArgs{}, func(obj interface{}) { SetObjectDefaults_SpotAwarenessArgs(obj.(*SpotAwarenessArgs)) })
 	scheme.AddTypeDefaultingFunc(&TargetLoadPackingArgs{}, func(obj interface{}) { SetObjectDefaults_TargetLoadPackingArgs(obj.(*TargetLoadPackingArgs)) })
+	scheme.AddTypeDefaultingFunc(&TopologicalImageLocalityArgs{}, func(obj interface{}) {
+		SetObjectDefaults_TopologicalImageLocalityArgs(obj.(*TopologicalImageLocalityArgs))
+	})
+	scheme.AddTypeDefaultingFunc(&TopologicalSortArgs{}, func(obj interface{}) { SetObjectDefaults_TopologicalSortArgs(obj.(*TopologicalSortArgs)) })
+	scheme.AddTypeDefaultingFunc(&WarmPeersArgs{}, func(obj interface{}) { SetObjectDefaults_WarmPeersArgs(obj.(*WarmPeersArgs)) })
 	return nil
 }
 
+func SetObjectDefaults_CELPolicyArgs(in *CELPolicyArgs) {
+	SetDefaults_CELPolicyArgs(in)
+}
+
+func SetObjectDefaults_CapacitySchedulingArgs(in *CapacitySchedulingArgs) {
+	SetDefaults_CapacitySchedulingArgs(in)
+}
+
 func SetObjectDefaults_CoschedulingArgs(in *CoschedulingArgs) {
 	SetDefaults_CoschedulingArgs(in)
 }
 
+func SetObjectDefaults_CrossRegionRateLimitArgs(in *CrossRegionRateLimitArgs) {
+	SetDefaults_CrossRegionRateLimitArgs(in)
+}
+
+func SetObjectDefaults_LinkHeadroomArgs(in *LinkHeadroomArgs) {
+	SetDefaults_LinkHeadroomArgs(in)
+}
+
 func SetObjectDefaults_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs) {
 	SetDefaults_LoadVariationRiskBalancingArgs(in)
 }
@@ -64,6 +90,22 @@ func SetObjectDefaults_PreemptionTolerationArgs(in *PreemptionTolerationArgs) {
 	SetDefaults_PreemptionTolerationArgs(in)
 }
 
+func SetObjectDefaults_SpotAwarenessArgs(in *SpotAwarenessArgs) {
+	SetDefaults_SpotAwarenessArgs(in)
+}
+
 func SetObjectDefaults_TargetLoadPackingArgs(in *TargetLoadPackingArgs) {
 	SetDefaults_TargetLoadPackingArgs(in)
 }
+
+func SetObjectDefaults_TopologicalImageLocalityArgs(in *TopologicalImageLocalityArgs) {
+	SetDefaults_TopologicalImageLocalityArgs(in)
+}
+
+func SetObjectDefaults_TopologicalSortArgs(in *TopologicalSortArgs) {
+	SetDefaults_TopologicalSortArgs(in)
+}
+
+func SetObjectDefaults_WarmPeersArgs(in *WarmPeersArgs) {
+	SetDefaults_WarmPeersArgs(in)
+}
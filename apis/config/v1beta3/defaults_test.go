@@ -41,6 +41,7 @@ func TestSchedulingDefaults(t *testing.T) {
 			expect: &CoschedulingArgs{
 				PermitWaitingTimeSeconds:      pointer.Int64Ptr(60),
 				DeniedPGExpirationTimeSeconds: pointer.Int64Ptr(20),
+				BindParallelism:               pointer.Int64Ptr(16),
 			},
 		},
 		{
@@ -48,10 +49,12 @@ func TestSchedulingDefaults(t *testing.T) {
 			config: &CoschedulingArgs{
 				PermitWaitingTimeSeconds:      pointer.Int64Ptr(60),
 				DeniedPGExpirationTimeSeconds: pointer.Int64Ptr(10),
+				BindParallelism:               pointer.Int64Ptr(4),
 			},
 			expect: &CoschedulingArgs{
 				PermitWaitingTimeSeconds:      pointer.Int64Ptr(60),
 				DeniedPGExpirationTimeSeconds: pointer.Int64Ptr(10),
+				BindParallelism:               pointer.Int64Ptr(4),
 			},
 		},
 		{
@@ -61,7 +64,8 @@ func TestSchedulingDefaults(t *testing.T) {
 				Resources: []schedulerconfigv1beta3.ResourceSpec{
 					{Name: "cpu", Weight: 1 << 20}, {Name: "memory", Weight: 1},
 				},
-				Mode: Least,
+				Mode:            Least,
+				GPUResourceName: "nvidia.com/gpu",
 			},
 		},
 		{
@@ -70,13 +74,21 @@ func TestSchedulingDefaults(t *testing.T) {
 				Resources: []schedulerconfigv1beta3.ResourceSpec{
 					{Name: "cpu", Weight: 1 << 10}, {Name: "memory", Weight: 2},
 				},
-				Mode: Most,
+				Mode:            Most,
+				GPUResourceName: "amd.com/gpu",
+				GPUResourceFractions: []GPUResourceFraction{
+					{Name: "amd.com/gpu.shared", Fraction: 0.25},
+				},
 			},
 			expect: &NodeResourcesAllocatableArgs{
 				Resources: []schedulerconfigv1beta3.ResourceSpec{
 					{Name: "cpu", Weight: 1 << 10}, {Name: "memory", Weight: 2},
 				},
-				Mode: Most,
+				Mode:            Most,
+				GPUResourceName: "amd.com/gpu",
+				GPUResourceFractions: []GPUResourceFraction{
+					{Name: "amd.com/gpu.shared", Fraction: 0.25},
+				},
 			},
 		},
 		{
@@ -154,6 +166,185 @@ func TestSchedulingDefaults(t *testing.T) {
 				MinCandidateNodesAbsolute:   pointer.Int32Ptr(100),
 			},
 		},
+		{
+			name:   "empty config TopologicalImageLocalityArgs",
+			config: &TopologicalImageLocalityArgs{},
+			expect: &TopologicalImageLocalityArgs{
+				NetworkTopologyNamespace: pointer.StringPtr("default"),
+				WeightsName:              pointer.StringPtr("UserDefined"),
+			},
+		},
+		{
+			name: "set non default TopologicalImageLocalityArgs",
+			config: &TopologicalImageLocalityArgs{
+				NetworkTopologyName:      pointer.StringPtr("net-topology"),
+				NetworkTopologyNamespace: pointer.StringPtr("kube-system"),
+				WeightsName:              pointer.StringPtr("Dijkstra"),
+			},
+			expect: &TopologicalImageLocalityArgs{
+				NetworkTopologyName:      pointer.StringPtr("net-topology"),
+				NetworkTopologyNamespace: pointer.StringPtr("kube-system"),
+				WeightsName:              pointer.StringPtr("Dijkstra"),
+			},
+		},
+		{
+			name:   "empty config CELPolicyArgs",
+			config: &CELPolicyArgs{},
+			expect: &CELPolicyArgs{
+				DefaultAction:            CELPolicyAllow,
+				NetworkTopologyNamespace: pointer.StringPtr("default"),
+				WeightsName:              pointer.StringPtr("UserDefined"),
+			},
+		},
+		{
+			name: "set non default CELPolicyArgs",
+			config: &CELPolicyArgs{
+				DefaultAction:            CELPolicyDeny,
+				NetworkTopologyName:      pointer.StringPtr("net-topology"),
+				NetworkTopologyNamespace: pointer.StringPtr("kube-system"),
+				WeightsName:              pointer.StringPtr("Dijkstra"),
+			},
+			expect: &CELPolicyArgs{
+				DefaultAction:            CELPolicyDeny,
+				NetworkTopologyName:      pointer.StringPtr("net-topology"),
+				NetworkTopologyNamespace: pointer.StringPtr("kube-system"),
+				WeightsName:              pointer.StringPtr("Dijkstra"),
+			},
+		},
+		{
+			name:   "empty config SpotAwarenessArgs",
+			config: &SpotAwarenessArgs{},
+			expect: &SpotAwarenessArgs{
+				SpotNodeLabels: map[string]string{
+					"eks.amazonaws.com/capacityType":        "SPOT",
+					"karpenter.sh/capacity-type":            "spot",
+					"kubernetes.azure.com/scalesetpriority": "spot",
+					"cloud.google.com/gke-spot":             "true",
+					"cloud.google.com/gke-preemptible":      "true",
+				},
+				DefaultSpotInterruptionRisk: pointer.Int64Ptr(50),
+			},
+		},
+		{
+			name: "set non default SpotAwarenessArgs",
+			config: &SpotAwarenessArgs{
+				SpotNodeLabels:              map[string]string{"node.example.com/lifecycle": "spot"},
+				DefaultSpotInterruptionRisk: pointer.Int64Ptr(20),
+				PriorityClassPreferences:    []SpotPriorityClassPreference{{PriorityClassName: "batch", Preference: CapacityPreferenceSpot}},
+			},
+			expect: &SpotAwarenessArgs{
+				SpotNodeLabels:              map[string]string{"node.example.com/lifecycle": "spot"},
+				DefaultSpotInterruptionRisk: pointer.Int64Ptr(20),
+				PriorityClassPreferences:    []SpotPriorityClassPreference{{PriorityClassName: "batch", Preference: CapacityPreferenceSpot}},
+			},
+		},
+		{
+			name:   "empty config CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{},
+			expect: &CapacitySchedulingArgs{
+				PreemptionDryRun: pointer.BoolPtr(false),
+			},
+		},
+		{
+			name: "set non default CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{
+				PreemptionDryRun: pointer.BoolPtr(true),
+			},
+			expect: &CapacitySchedulingArgs{
+				PreemptionDryRun: pointer.BoolPtr(true),
+			},
+		},
+		{
+			name:   "empty config CrossRegionRateLimitArgs",
+			config: &CrossRegionRateLimitArgs{},
+			expect: &CrossRegionRateLimitArgs{
+				MaxPlacementsPerMinute:   pointer.Int32Ptr(60),
+				Burst:                    pointer.Int32Ptr(10),
+				MaxWaitSeconds:           pointer.Int64Ptr(60),
+				IgnoreNotReadyNodes:      pointer.BoolPtr(false),
+				IgnoreUnschedulableNodes: pointer.BoolPtr(false),
+			},
+		},
+		{
+			name: "set non default CrossRegionRateLimitArgs",
+			config: &CrossRegionRateLimitArgs{
+				MaxPlacementsPerMinute:   pointer.Int32Ptr(6),
+				Burst:                    pointer.Int32Ptr(1),
+				MaxWaitSeconds:           pointer.Int64Ptr(120),
+				IgnoreNotReadyNodes:      pointer.BoolPtr(true),
+				IgnoreUnschedulableNodes: pointer.BoolPtr(true),
+				IgnoredTaintKeys:         []string{"maintenance"},
+			},
+			expect: &CrossRegionRateLimitArgs{
+				MaxPlacementsPerMinute:   pointer.Int32Ptr(6),
+				Burst:                    pointer.Int32Ptr(1),
+				MaxWaitSeconds:           pointer.Int64Ptr(120),
+				IgnoreNotReadyNodes:      pointer.BoolPtr(true),
+				IgnoreUnschedulableNodes: pointer.BoolPtr(true),
+				IgnoredTaintKeys:         []string{"maintenance"},
+			},
+		},
+		{
+			name:   "empty config TopologicalSortArgs",
+			config: &TopologicalSortArgs{},
+			expect: &TopologicalSortArgs{
+				StarvationThresholdSeconds: pointer.Int64Ptr(300),
+				StarvationIndexBoost:       pointer.Int32Ptr(1),
+			},
+		},
+		{
+			name: "set non default TopologicalSortArgs",
+			config: &TopologicalSortArgs{
+				StarvationThresholdSeconds: pointer.Int64Ptr(0),
+				StarvationIndexBoost:       pointer.Int32Ptr(2),
+			},
+			expect: &TopologicalSortArgs{
+				StarvationThresholdSeconds: pointer.Int64Ptr(0),
+				StarvationIndexBoost:       pointer.Int32Ptr(2),
+			},
+		},
+		{
+			name:   "empty config LinkHeadroomArgs",
+			config: &LinkHeadroomArgs{},
+			expect: &LinkHeadroomArgs{
+				NetworkTopologyNamespace: pointer.StringPtr("default"),
+				WeightsName:              pointer.StringPtr("UserDefined"),
+			},
+		},
+		{
+			name: "set non default LinkHeadroomArgs",
+			config: &LinkHeadroomArgs{
+				NetworkTopologyName:      pointer.StringPtr("nt"),
+				NetworkTopologyNamespace: pointer.StringPtr("network"),
+				WeightsName:              pointer.StringPtr("NetperfCosts"),
+			},
+			expect: &LinkHeadroomArgs{
+				NetworkTopologyName:      pointer.StringPtr("nt"),
+				NetworkTopologyNamespace: pointer.StringPtr("network"),
+				WeightsName:              pointer.StringPtr("NetperfCosts"),
+			},
+		},
+		{
+			name:   "empty config WarmPeersArgs",
+			config: &WarmPeersArgs{},
+			expect: &WarmPeersArgs{
+				NetworkTopologyNamespace: pointer.StringPtr("default"),
+				WeightsName:              pointer.StringPtr("UserDefined"),
+			},
+		},
+		{
+			name: "set non default WarmPeersArgs",
+			config: &WarmPeersArgs{
+				NetworkTopologyName:      pointer.StringPtr("nt"),
+				NetworkTopologyNamespace: pointer.StringPtr("network"),
+				WeightsName:              pointer.StringPtr("NetperfCosts"),
+			},
+			expect: &WarmPeersArgs{
+				NetworkTopologyName:      pointer.StringPtr("nt"),
+				NetworkTopologyNamespace: pointer.StringPtr("network"),
+				WeightsName:              pointer.StringPtr("NetperfCosts"),
+			},
+		},
 	}
 
 	for _, tc := range tests {
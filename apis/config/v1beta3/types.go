@@ -33,6 +33,16 @@ type CoschedulingArgs struct {
 
 	// DeniedPGExpirationTimeSeconds is the expiration time of the denied podgroup store.
 	DeniedPGExpirationTimeSeconds *int64 `json:"deniedPGExpirationTimeSeconds,omitempty"`
+	// BindParallelism is the maximum number of members of a permitted PodGroup bound
+	// concurrently when Coscheduling is enabled at the Bind extension point.
+	BindParallelism *int64 `json:"bindParallelism,omitempty"`
+	// KubeAPIQPS is the QPS of the PodGroup client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the PodGroup client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+	// MaxWaitingPods is the maximum number of pods waiting at Permit for the rest of their gang. Past it, the
+	// waiting gang of lowest priority, then created last, is rejected. Unbounded if not set.
+	MaxWaitingPods *int64 `json:"maxWaitingPods,omitempty"`
 }
 
 // ModeType is a type "string".
@@ -60,6 +70,47 @@ type NodeResourcesAllocatableArgs struct {
 
 	// Whether to prioritize nodes with least or most allocatable resources.
 	Mode ModeType `json:"mode,omitempty"`
+
+	// GPUResourceName is the resource physical GPUs are exposed as, e.g. nvidia.com/gpu.
+	GPUResourceName string `json:"gpuResourceName,omitempty"`
+
+	// GPUResourceFractions lists the extended resources sharing physical GPUs, e.g. MIG profiles
+	// or time-slicing replicas. When set, the amounts of these resources are accounted, in
+	// thousandths of a physical GPU, as GPUResourceName when scoring.
+	GPUResourceFractions []GPUResourceFraction `json:"gpuResourceFractions,omitempty"`
+
+	// DiskPressureThresholdPercent is the usage, in percent of the node ephemeral-storage or image
+	// filesystem, above which the score of the node is lowered proportionally to its remaining disk
+	// space, to keep pods away from nodes close to disk pressure eviction. 0 disables the penalty.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	DiskPressureThresholdPercent int32 `json:"diskPressureThresholdPercent,omitempty"`
+
+	// VirtualNodeProfiles makes the plugin score the virtual nodes, labeled scheduling.sigs.k8s.io/virtual-node,
+	// by the allocatable of their VirtualNodeProfile, i.e. of their downstream capacity, rather than by the
+	// allocatable they report.
+	VirtualNodeProfiles bool `json:"virtualNodeProfiles,omitempty"`
+
+	// NodeGroupLabel is the label grouping the nodes, e.g. by rack or chassis. When set, the nodes are
+	// scored by the headroom, allocatable minus requested, aggregated across the nodes of their group,
+	// so that Mode prefers the groups with the least or the most aggregate headroom. The nodes without
+	// the label are groups of their own.
+	NodeGroupLabel string `json:"nodeGroupLabel,omitempty"`
+	// KubeAPIQPS is the QPS of the VirtualNodeProfile client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the VirtualNodeProfile client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// GPUResourceFraction maps an extended resource sharing a physical GPU to the fraction
+// of the GPU one unit of the resource stands for.
+type GPUResourceFraction struct {
+	// Name of the extended resource, e.g. nvidia.com/mig-1g.5gb.
+	Name string `json:"name"`
+
+	// Fraction of a physical GPU one unit of the resource stands for, in (0, 1],
+	// e.g. 0.142857 for a 1g.5gb MIG profile of an A100.
+	Fraction float64 `json:"fraction"`
 }
 
 // MetricProviderType is a "string" type.
@@ -99,6 +150,31 @@ type TargetLoadPackingArgs struct {
 	MetricProvider MetricProviderSpec `json:"metricProvider,omitempty"`
 	// Address of load watcher service
 	WatcherAddress *string `json:"watcherAddress,omitempty"`
+	// ScoreBudgetMilliseconds is the time the plugin may spend scoring the nodes of a scheduling cycle.
+	// Past it, the plugin gives every node the same score instead of slowing the cycle down. 0 disables
+	// the budget.
+	// +kubebuilder:validation:Minimum=0
+	ScoreBudgetMilliseconds int64 `json:"scoreBudgetMilliseconds,omitempty"`
+	// PressureThresholdPercent is the pressure stall information (PSI) of the CPU, memory or IO of a node,
+	// the share of time some tasks stalled over the last minute in percent, above which the node score
+	// is penalized, even if its utilization is below the target. 0 disables the penalty.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	PressureThresholdPercent int64 `json:"pressureThresholdPercent,omitempty"`
+	// TargetUtilizationPerOperatingSystem overrides TargetUtilization for the nodes of the given operating
+	// systems, as in the kubernetes.io/os label of the nodes, so that they are packed more or less aggressively.
+	TargetUtilizationPerOperatingSystem map[string]int64 `json:"targetUtilizationPerOperatingSystem,omitempty"`
+	// IncludedOperatingSystems restricts the nodes scored by the plugin to those of the given operating
+	// systems, as in the kubernetes.io/os label of the nodes. All the nodes are scored if empty.
+	// The nodes not scored get the minimum score.
+	IncludedOperatingSystems []string `json:"includedOperatingSystems,omitempty"`
+	// ExcludedOperatingSystems are the operating systems whose nodes are not scored by the plugin, e.g. the
+	// windows nodes of a mixed cluster. The nodes not scored get the minimum score.
+	ExcludedOperatingSystems []string `json:"excludedOperatingSystems,omitempty"`
+	// ColdStartPolicy is how the nodes without metrics history, e.g. on fresh clusters or nodes, are scored:
+	// MinScore, the default, gives them the minimum score, RequestsBased estimates their utilization from
+	// the requests of their pods. The plugin switches to the metrics of a node as soon as it has some.
+	ColdStartPolicy ColdStartPolicy `json:"coldStartPolicy,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -115,8 +191,46 @@ type LoadVariationRiskBalancingArgs struct {
 	SafeVarianceMargin *float64 `json:"safeVarianceMargin,omitempty"`
 	// Root power of standard deviation in risk value
 	SafeVarianceSensitivity *float64 `json:"safeVarianceSensitivity,omitempty"`
+	// IncludedOperatingSystems restricts the nodes scored by the plugin to those of the given operating
+	// systems, as in the kubernetes.io/os label of the nodes. All the nodes are scored if empty.
+	// The nodes not scored get the minimum score.
+	IncludedOperatingSystems []string `json:"includedOperatingSystems,omitempty"`
+	// ExcludedOperatingSystems are the operating systems whose nodes are not scored by the plugin, e.g. the
+	// windows nodes of a mixed cluster. The nodes not scored get the minimum score.
+	ExcludedOperatingSystems []string `json:"excludedOperatingSystems,omitempty"`
+	// ColdStartPolicy is how the nodes without metrics history, e.g. on fresh clusters or nodes, are scored:
+	// MinScore, the default, gives them the minimum score, RequestsBased estimates their utilization from
+	// the requests of their pods. The plugin switches to the metrics of a node as soon as it has some.
+	ColdStartPolicy ColdStartPolicy `json:"coldStartPolicy,omitempty"`
+	// SoftEviction, if set, has the pods most likely to over-consume on the nodes whose risk stays above a
+	// threshold annotated with a soft eviction hint, for deschedulers or operators to act on.
+	SoftEviction *SoftEvictionArgs `json:"softEviction,omitempty"`
+}
+
+// SoftEvictionArgs configures the soft eviction hints written by a load-aware plugin on the pods of the
+// nodes it observes consistently overloaded.
+type SoftEvictionArgs struct {
+	// RiskThreshold is the risk, in [0, 1], of the CPU or memory of a node above which the node is
+	// overloaded. Defaults to 0.8.
+	RiskThreshold float64 `json:"riskThreshold,omitempty"`
+	// ConsecutiveUpdates is the number of consecutive metrics updates a node stays overloaded for before
+	// its pods are annotated. Defaults to 3.
+	ConsecutiveUpdates int32 `json:"consecutiveUpdates,omitempty"`
+	// MaxPods is the number of pods annotated on an overloaded node. Defaults to 1.
+	MaxPods int32 `json:"maxPods,omitempty"`
 }
 
+// ColdStartPolicy is how the Trimaran plugins score the nodes without metrics history.
+type ColdStartPolicy string
+
+const (
+	// ColdStartMinScore gives the nodes without metrics history the minimum score.
+	ColdStartMinScore ColdStartPolicy = "MinScore"
+	// ColdStartRequestsBased estimates the utilization of the nodes without metrics history from the
+	// requests of their pods.
+	ColdStartRequestsBased ColdStartPolicy = "RequestsBased"
+)
+
 // ScoringStrategyType is a "string" type.
 type ScoringStrategyType string
 
@@ -141,9 +255,293 @@ type NodeResourceTopologyMatchArgs struct {
 	metav1.TypeMeta `json:",inline"`
 
 	ScoringStrategy *ScoringStrategy `json:"scoringStrategy,omitempty"`
+	// KubeAPIQPS is the QPS of the NodeResourceTopology client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the NodeResourceTopology client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PreemptionTolerationArgs reuses DefaultPluginArgs.
 type PreemptionTolerationArgs schedulerconfigv1beta3.DefaultPreemptionArgs
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TopologicalImageLocalityArgs holds arguments used to configure the TopologicalImageLocality plugin.
+type TopologicalImageLocalityArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// NetworkTopologyName is the name of the NetworkTopology providing the costs between zones.
+	// Zones are assumed equidistant if not set.
+	NetworkTopologyName *string `json:"networkTopologyName,omitempty"`
+	// NetworkTopologyNamespace is the namespace of the NetworkTopology.
+	NetworkTopologyNamespace *string `json:"networkTopologyNamespace,omitempty"`
+	// WeightsName is the name of the weights of the NetworkTopology to use (e.g., UserDefined).
+	// Comma-separated weights are blended, normalized across their cost units if these differ, and
+	// weighted by the factor following their name, if any, e.g. UserDefined:0.7,Dijkstra:0.3.
+	WeightsName *string `json:"weightsName,omitempty"`
+	// ScoreBudgetMilliseconds is the time the plugin may spend scoring the nodes of a scheduling cycle.
+	// Past it, the plugin gives every node the same score instead of slowing the cycle down. 0 disables
+	// the budget.
+	// +kubebuilder:validation:Minimum=0
+	ScoreBudgetMilliseconds int64 `json:"scoreBudgetMilliseconds,omitempty"`
+	// VirtualNodeProfiles makes the plugin place the virtual nodes, labeled scheduling.sigs.k8s.io/virtual-node,
+	// in the topology tier of their VirtualNodeProfile, at its network cost if the NetworkTopology has none.
+	VirtualNodeProfiles bool `json:"virtualNodeProfiles,omitempty"`
+	// StaleCostThresholdSeconds is the age of the network costs, since the weightCalculationTime of the
+	// NetworkTopology, past which their weight in the score decays, down to none at twice the age, so
+	// that stale costs do not dominate the placement after the controller computing them has been down.
+	// 0 disables the decay.
+	// +kubebuilder:validation:Minimum=0
+	StaleCostThresholdSeconds int64 `json:"staleCostThresholdSeconds,omitempty"`
+	// KubeAPIQPS is the QPS of the RegistryMirror, NetworkTopology and VirtualNodeProfile client.
+	// The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the RegistryMirror, NetworkTopology and VirtualNodeProfile client.
+	// The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CELPolicyArgs holds arguments used to configure the CELPolicy plugin.
+type CELPolicyArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// DefaultAction is the action taken on the nodes matched by no Allow or Deny rule.
+	// Defaults to Allow.
+	DefaultAction CELPolicyAction `json:"defaultAction,omitempty"`
+	// Rules are CEL expressions over the pod, the node and the network costs, evaluated
+	// for every node.
+	Rules []CELPolicyRule `json:"rules,omitempty"`
+	// NetworkTopologyName is the name of the NetworkTopology providing the network costs.
+	// networkCost() is unavailable to the rules if not set.
+	NetworkTopologyName *string `json:"networkTopologyName,omitempty"`
+	// NetworkTopologyNamespace is the namespace of the NetworkTopology.
+	NetworkTopologyNamespace *string `json:"networkTopologyNamespace,omitempty"`
+	// WeightsName is the name of the weights of the NetworkTopology to use (e.g., UserDefined).
+	// Comma-separated weights are blended, normalized across their cost units if these differ, and
+	// weighted by the factor following their name, if any, e.g. UserDefined:0.7,Dijkstra:0.3.
+	WeightsName *string `json:"weightsName,omitempty"`
+	// KubeAPIQPS is the QPS of the NetworkTopology client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the NetworkTopology client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// CELPolicyAction is the outcome of a CELPolicy rule matching a node.
+type CELPolicyAction string
+
+const (
+	// CELPolicyAllow lets the node pass Filter.
+	CELPolicyAllow CELPolicyAction = "Allow"
+	// CELPolicyDeny filters the node out.
+	CELPolicyDeny CELPolicyAction = "Deny"
+	// CELPolicyScore adds the score of the rule to the node.
+	CELPolicyScore CELPolicyAction = "Score"
+)
+
+// CELPolicyRule is a CEL expression evaluating to a bool, and the action taken on the nodes it
+// evaluates to true for.
+type CELPolicyRule struct {
+	// Name identifies the rule in the statuses and logs.
+	Name string `json:"name"`
+	// Expression is a CEL expression evaluating to a bool.
+	Expression string `json:"expression"`
+	// Action taken on the nodes the expression evaluates to true for: Allow, Deny or Score.
+	Action CELPolicyAction `json:"action"`
+	// Score added to the nodes matched by a Score rule, in [0, 100].
+	Score int64 `json:"score,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SpotAwarenessArgs holds arguments used to configure the SpotAwareness plugin.
+type SpotAwarenessArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// SpotNodeLabels are the labels, with their value, marking the nodes of spot or preemptible capacity.
+	// Defaults to the labels of the AWS, Azure and GCP node pools and of Karpenter.
+	SpotNodeLabels map[string]string `json:"spotNodeLabels,omitempty"`
+	// DefaultSpotInterruptionRisk is the interruption risk, in [0, 100], of the spot nodes without
+	// interruption risk annotation. Defaults to 50.
+	DefaultSpotInterruptionRisk *int64 `json:"defaultSpotInterruptionRisk,omitempty"`
+	// PriorityClassPreferences override the capacity preferred by the pods of some priority classes.
+	PriorityClassPreferences []SpotPriorityClassPreference `json:"priorityClassPreferences,omitempty"`
+}
+
+// CapacityPreference is the kind of capacity a pod prefers to run on.
+type CapacityPreference string
+
+const (
+	// CapacityPreferenceSpot favors spot nodes, the least likely to be interrupted first.
+	CapacityPreferenceSpot CapacityPreference = "Spot"
+	// CapacityPreferenceOnDemand favors the nodes the least likely to be interrupted.
+	CapacityPreferenceOnDemand CapacityPreference = "OnDemand"
+	// CapacityPreferenceNone scores all nodes evenly.
+	CapacityPreferenceNone CapacityPreference = "None"
+)
+
+// SpotPriorityClassPreference is the capacity preferred by the pods of a priority class.
+type SpotPriorityClassPreference struct {
+	// PriorityClassName is the name of the priority class.
+	PriorityClassName string `json:"priorityClassName"`
+	// Preference is the capacity preferred by its pods: Spot, OnDemand or None.
+	Preference CapacityPreference `json:"preference"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CapacitySchedulingArgs holds arguments used to configure the CapacityScheduling plugin.
+type CapacitySchedulingArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// PreemptionDryRun makes PostFilter only report the pods it would preempt, through an event,
+	// a metric and the status of the preemptor's ElasticQuota, without evicting them.
+	PreemptionDryRun *bool `json:"preemptionDryRun,omitempty"`
+	// KubeAPIQPS is the QPS of the ElasticQuota client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the ElasticQuota client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CrossRegionRateLimitArgs holds arguments used to configure the CrossRegionRateLimit plugin.
+type CrossRegionRateLimitArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// MaxPlacementsPerMinute is the number of pods per minute placed in another region than the
+	// pods of their AppGroup. Defaults to 60.
+	// +kubebuilder:validation:Minimum=1
+	MaxPlacementsPerMinute *int32 `json:"maxPlacementsPerMinute,omitempty"`
+	// Burst is the number of such placements allowed at once. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	Burst *int32 `json:"burst,omitempty"`
+	// MaxWaitSeconds is the longest a pod waits in Permit for its placement to be allowed. Pods
+	// that would wait longer are rejected. Defaults to 60.
+	// +kubebuilder:validation:Minimum=0
+	MaxWaitSeconds *int64 `json:"maxWaitSeconds,omitempty"`
+	// IgnoreNotReadyNodes leaves the pods of the AppGroup running on NotReady nodes out of its regions.
+	// Defaults to false.
+	IgnoreNotReadyNodes *bool `json:"ignoreNotReadyNodes,omitempty"`
+	// IgnoreUnschedulableNodes leaves the pods of the AppGroup running on cordoned nodes out of its regions.
+	// Defaults to false.
+	IgnoreUnschedulableNodes *bool `json:"ignoreUnschedulableNodes,omitempty"`
+	// IgnoredTaintKeys leaves the pods of the AppGroup running on nodes with a taint of one of these keys
+	// out of its regions.
+	IgnoredTaintKeys []string `json:"ignoredTaintKeys,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TopologicalSortArgs holds arguments used to configure the TopologicalSort plugin.
+type TopologicalSortArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// StarvationThresholdSeconds is the time a pod waits in the queue after which, and every such
+	// period again, its topology index improves by StarvationIndexBoost. 0 disables the boost.
+	// Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	StarvationThresholdSeconds *int64 `json:"starvationThresholdSeconds,omitempty"`
+	// StarvationIndexBoost is the improvement of the topology index of a pod per
+	// StarvationThresholdSeconds waited. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	StarvationIndexBoost *int32 `json:"starvationIndexBoost,omitempty"`
+	// KubeAPIQPS is the QPS of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LinkHeadroomArgs holds arguments used to configure the LinkHeadroom plugin.
+type LinkHeadroomArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// NetworkTopologyName is the name of the NetworkTopology providing the capacity, the allocated
+	// bandwidth and the link policies of the links.
+	NetworkTopologyName *string `json:"networkTopologyName,omitempty"`
+	// NetworkTopologyNamespace is the namespace of the NetworkTopology.
+	NetworkTopologyNamespace *string `json:"networkTopologyNamespace,omitempty"`
+	// WeightsName is the name of the weights of the NetworkTopology to use (e.g., UserDefined).
+	// The bandwidth of a link is the one of the first of comma-separated weights knowing it.
+	WeightsName *string `json:"weightsName,omitempty"`
+	// KubeAPIQPS is the QPS of the AppGroup and NetworkTopology client.
+	// The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the AppGroup and NetworkTopology client.
+	// The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WarmPeersArgs holds arguments used to configure the WarmPeers plugin.
+type WarmPeersArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// NetworkTopologyName is the name of the NetworkTopology whose draining zones score the lowest.
+	// The draining zones are not taken into account if not set.
+	NetworkTopologyName *string `json:"networkTopologyName,omitempty"`
+	// NetworkTopologyNamespace is the namespace of the NetworkTopology.
+	NetworkTopologyNamespace *string `json:"networkTopologyNamespace,omitempty"`
+	// WeightsName is the name of the weights of the NetworkTopology to use (e.g., UserDefined).
+	WeightsName *string `json:"weightsName,omitempty"`
+	// KubeAPIQPS is the QPS of the AppGroup and NetworkTopology client.
+	// The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the AppGroup and NetworkTopology client.
+	// The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackfillArgs holds arguments used to configure the Backfill plugin.
+type BackfillArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// KubeAPIQPS is the QPS of the PodGroup client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the PodGroup client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NoisyNeighborArgs holds arguments used to configure the NoisyNeighbor plugin.
+type NoisyNeighborArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// KubeAPIQPS is the QPS of the InterferencePolicy client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the InterferencePolicy client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementLabelsArgs holds arguments used to configure the PlacementLabels plugin.
+type PlacementLabelsArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// KubeAPIQPS is the QPS of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ZoneLimitArgs holds arguments used to configure the ZoneLimit plugin.
+type ZoneLimitArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// KubeAPIQPS is the QPS of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIQPS *int32 `json:"kubeAPIQPS,omitempty"`
+	// KubeAPIBurst is the burst of the AppGroup client. The scheduler's one is used if not set.
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+}
@@ -27,6 +27,102 @@ import (
 	apisconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackfillArgs) DeepCopyInto(out *BackfillArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackfillArgs.
+func (in *BackfillArgs) DeepCopy() *BackfillArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(BackfillArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackfillArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELPolicyArgs) DeepCopyInto(out *CELPolicyArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CELPolicyRule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELPolicyArgs.
+func (in *CELPolicyArgs) DeepCopy() *CELPolicyArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CELPolicyArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CELPolicyArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELPolicyRule) DeepCopyInto(out *CELPolicyRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELPolicyRule.
+func (in *CELPolicyRule) DeepCopy() *CELPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CELPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacitySchedulingArgs) DeepCopyInto(out *CapacitySchedulingArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacitySchedulingArgs.
+func (in *CapacitySchedulingArgs) DeepCopy() *CapacitySchedulingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacitySchedulingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacitySchedulingArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoschedulingArgs) DeepCopyInto(out *CoschedulingArgs) {
 	*out = *in
@@ -52,11 +148,97 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossRegionRateLimitArgs) DeepCopyInto(out *CrossRegionRateLimitArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.IgnoredTaintKeys != nil {
+		in, out := &in.IgnoredTaintKeys, &out.IgnoredTaintKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossRegionRateLimitArgs.
+func (in *CrossRegionRateLimitArgs) DeepCopy() *CrossRegionRateLimitArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossRegionRateLimitArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CrossRegionRateLimitArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUResourceFraction) DeepCopyInto(out *GPUResourceFraction) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUResourceFraction.
+func (in *GPUResourceFraction) DeepCopy() *GPUResourceFraction {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUResourceFraction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkHeadroomArgs) DeepCopyInto(out *LinkHeadroomArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkHeadroomArgs.
+func (in *LinkHeadroomArgs) DeepCopy() *LinkHeadroomArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkHeadroomArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LinkHeadroomArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.MetricProvider = in.MetricProvider
+	if in.IncludedOperatingSystems != nil {
+		in, out := &in.IncludedOperatingSystems, &out.IncludedOperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedOperatingSystems != nil {
+		in, out := &in.ExcludedOperatingSystems, &out.ExcludedOperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoftEviction != nil {
+		in, out := &in.SoftEviction, &out.SoftEviction
+		*out = new(SoftEvictionArgs)
+		**out = **in
+	}
 	return
 }
 
@@ -129,6 +311,11 @@ func (in *NodeResourcesAllocatableArgs) DeepCopyInto(out *NodeResourcesAllocatab
 		*out = make([]apisconfig.ResourceSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.GPUResourceFractions != nil {
+		in, out := &in.GPUResourceFractions, &out.GPUResourceFractions
+		*out = make([]GPUResourceFraction, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -150,6 +337,56 @@ func (in *NodeResourcesAllocatableArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NoisyNeighborArgs) DeepCopyInto(out *NoisyNeighborArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NoisyNeighborArgs.
+func (in *NoisyNeighborArgs) DeepCopy() *NoisyNeighborArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NoisyNeighborArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NoisyNeighborArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementLabelsArgs) DeepCopyInto(out *PlacementLabelsArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementLabelsArgs.
+func (in *PlacementLabelsArgs) DeepCopy() *PlacementLabelsArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementLabelsArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementLabelsArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreemptionTolerationArgs) DeepCopyInto(out *PreemptionTolerationArgs) {
 	*out = *in
@@ -196,6 +433,75 @@ func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SoftEvictionArgs) DeepCopyInto(out *SoftEvictionArgs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoftEvictionArgs.
+func (in *SoftEvictionArgs) DeepCopy() *SoftEvictionArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(SoftEvictionArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotAwarenessArgs) DeepCopyInto(out *SpotAwarenessArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.SpotNodeLabels != nil {
+		in, out := &in.SpotNodeLabels, &out.SpotNodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PriorityClassPreferences != nil {
+		in, out := &in.PriorityClassPreferences, &out.PriorityClassPreferences
+		*out = make([]SpotPriorityClassPreference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotAwarenessArgs.
+func (in *SpotAwarenessArgs) DeepCopy() *SpotAwarenessArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotAwarenessArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpotAwarenessArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotPriorityClassPreference) DeepCopyInto(out *SpotPriorityClassPreference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotPriorityClassPreference.
+func (in *SpotPriorityClassPreference) DeepCopy() *SpotPriorityClassPreference {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotPriorityClassPreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 	*out = *in
@@ -208,6 +514,23 @@ func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 		}
 	}
 	out.MetricProvider = in.MetricProvider
+	if in.TargetUtilizationPerOperatingSystem != nil {
+		in, out := &in.TargetUtilizationPerOperatingSystem, &out.TargetUtilizationPerOperatingSystem
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IncludedOperatingSystems != nil {
+		in, out := &in.IncludedOperatingSystems, &out.IncludedOperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedOperatingSystems != nil {
+		in, out := &in.ExcludedOperatingSystems, &out.ExcludedOperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -228,3 +551,103 @@ func (in *TargetLoadPackingArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologicalImageLocalityArgs) DeepCopyInto(out *TopologicalImageLocalityArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologicalImageLocalityArgs.
+func (in *TopologicalImageLocalityArgs) DeepCopy() *TopologicalImageLocalityArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologicalImageLocalityArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TopologicalImageLocalityArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologicalSortArgs) DeepCopyInto(out *TopologicalSortArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologicalSortArgs.
+func (in *TopologicalSortArgs) DeepCopy() *TopologicalSortArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologicalSortArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TopologicalSortArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WarmPeersArgs) DeepCopyInto(out *WarmPeersArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmPeersArgs.
+func (in *WarmPeersArgs) DeepCopy() *WarmPeersArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmPeersArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WarmPeersArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneLimitArgs) DeepCopyInto(out *ZoneLimitArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneLimitArgs.
+func (in *ZoneLimitArgs) DeepCopy() *ZoneLimitArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneLimitArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ZoneLimitArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -57,6 +57,20 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AppGroupList{},
 		&NetworkTopology{},
 		&NetworkTopologyList{},
+		&InterferencePolicy{},
+		&InterferencePolicyList{},
+		&CoschedulingPolicy{},
+		&CoschedulingPolicyList{},
+		&RegistryMirror{},
+		&RegistryMirrorList{},
+		&NodeBandwidthProfile{},
+		&NodeBandwidthProfileList{},
+		&LinkHotspot{},
+		&LinkHotspotList{},
+		&NetworkLatency{},
+		&NetworkLatencyList{},
+		&VirtualNodeProfile{},
+		&VirtualNodeProfileList{},
 	)
 	// AddToGroupVersion allows the serialization of client types like ListOptions.
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -61,6 +61,27 @@ type ElasticQuotaStatus struct {
 	// Used is the current observed total usage of the resource in the namespace.
 	// +optional
 	Used v1.ResourceList `json:"used,omitempty" protobuf:"bytes,1,rep,name=used,casttype=ResourceList,castkey=ResourceName"`
+
+	// LastPreemptionDryRun is the latest preemption a pod of the namespace would have triggered,
+	// reported when the CapacityScheduling plugin runs preemption in dry-run mode.
+	// +optional
+	LastPreemptionDryRun *PreemptionDryRun `json:"lastPreemptionDryRun,omitempty" protobuf:"bytes,2,opt,name=lastPreemptionDryRun"`
+}
+
+// PreemptionDryRun is a preemption computed but not enforced.
+type PreemptionDryRun struct {
+	// Preemptor is the namespace/name of the pod that would have preempted the victims.
+	Preemptor string `json:"preemptor" protobuf:"bytes,1,opt,name=preemptor"`
+
+	// NodeName is the node the preemptor would have been nominated to.
+	NodeName string `json:"nodeName" protobuf:"bytes,2,opt,name=nodeName"`
+
+	// Victims are the namespace/name of the pods that would have been preempted.
+	// +optional
+	Victims []string `json:"victims,omitempty" protobuf:"bytes,3,rep,name=victims"`
+
+	// Time is when the preemption was computed.
+	Time metav1.Time `json:"time" protobuf:"bytes,4,opt,name=time"`
 }
 
 // +kubebuilder:object:root=true
@@ -78,6 +99,10 @@ type ElasticQuotaList struct {
 	Items []ElasticQuota `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// ElasticQuotaMachinePoolSelectorAnnotation may be set on an ElasticQuota to a label selector of Cluster API
+// MachineDeployments and MachinePools, whose capacity the controller then keeps as the max of the quota.
+const ElasticQuotaMachinePoolSelectorAnnotation = scheduling.GroupName + "/machine-pool-selector"
+
 // PodGroupPhase is the phase of a pod group at the current time.
 type PodGroupPhase string
 
@@ -115,6 +140,26 @@ const (
 	PodGroupLabel = "pod-group." + scheduling.GroupName
 )
 
+// These are the valid condition types of podGroups.
+const (
+	// PodGroupMinMemberSatisfiable means `spec.minMember` can be reached by the workload owning the
+	// pods of the PodGroup, e.g. it does not exceed the parallelism of a Job.
+	PodGroupMinMemberSatisfiable = "MinMemberSatisfiable"
+
+	// PodGroupFullyScheduled means `spec.minMember` pods of the PodGroup have been running at
+	// least once. Pods replacing lost members of such a group, e.g. recreated after a node
+	// failure, are not held at Permit waiting for `spec.minMember` siblings again.
+	PodGroupFullyScheduled = "FullyScheduled"
+
+	// PodGroupAntiAffinityRelaxed means the anti-affinity of the members of the PodGroup turned
+	// from required into preferred, a member being unschedulable because of it.
+	PodGroupAntiAffinityRelaxed = "AntiAffinityRelaxed"
+
+	// PodGroupSchedulingFailed means the gang was rejected more than `spec.failurePolicy.backoffLimit`
+	// times: its pods are not scheduled anymore until all of them are gone.
+	PodGroupSchedulingFailed = "SchedulingFailed"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName={pg,pgs}
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -150,8 +195,92 @@ type PodGroupSpec struct {
 
 	// ScheduleTimeoutSeconds defines the maximal time of members/tasks to wait before run the pod group;
 	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+
+	// NodeSelector must be matched by the nodes of all the members of the pod group, on top of the
+	// node selector of their own spec, so that gang constraints (e.g., a GPU pool) are declared once.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// NodeAffinity must be matched by the nodes of all the members of the pod group, on top of the
+	// required node affinity of their own spec (e.g., to keep the gang in a zone).
+	// +optional
+	NodeAffinity *v1.NodeSelector `json:"nodeAffinity,omitempty"`
+
+	// Tolerations are added to the members of the pod group not scheduled yet, on top of the
+	// tolerations of their own spec, so that the gang can use tainted nodes (e.g., a GPU pool).
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// MemberAntiAffinity keeps the members of the pod group apart, in place of a required
+	// anti-affinity of the pods among themselves, so that it can be relaxed for large gangs to
+	// assemble on constrained clusters.
+	// +optional
+	MemberAntiAffinity *PodGroupAntiAffinity `json:"memberAntiAffinity,omitempty"`
+
+	// FailurePolicy bounds the scheduling attempts of the gang, so that a gang which cannot be
+	// placed stops being retried. The gang is retried indefinitely if not specified.
+	// +optional
+	FailurePolicy *PodGroupFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// PodGroupFailurePolicy tells how many times a gang may be rejected before the pod group is marked
+// Failed, and what happens to its pods then.
+type PodGroupFailurePolicy struct {
+	// BackoffLimit is the number of rejections of the gang retried; the next rejection marks the
+	// pod group Failed.
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit int32 `json:"backoffLimit"`
+
+	// BackoffSeconds is the time the gang waits before being retried after its first rejection,
+	// doubled at every rejection up to 10 minutes. Defaults to the denied PodGroup expiration time
+	// of the scheduler.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BackoffSeconds *int32 `json:"backoffSeconds,omitempty"`
+
+	// Action is applied to the pods of the gang not scheduled once the pod group failed. Defaults to Gate.
+	// +optional
+	Action PodGroupFailureAction `json:"action,omitempty"`
+}
+
+// PodGroupFailureAction is the action applied to the pending pods of a failed pod group.
+// +kubebuilder:validation:Enum=Gate;Delete
+type PodGroupFailureAction string
+
+const (
+	// PodGroupFailureGate keeps the pods pending, rejected before being filtered.
+	PodGroupFailureGate PodGroupFailureAction = "Gate"
+
+	// PodGroupFailureDelete deletes the pods, e.g. for their owner to give up or recreate them.
+	PodGroupFailureDelete PodGroupFailureAction = "Delete"
+)
+
+// MaxPodGroupBackoffSeconds caps the time a gang with a failure policy waits between two attempts.
+const MaxPodGroupBackoffSeconds = 600
+
+// PodGroupAntiAffinity keeps the members of a pod group in distinct domains of a topology key.
+type PodGroupAntiAffinity struct {
+	// TopologyKey is the node label whose domains host at most one member (e.g., kubernetes.io/hostname).
+	TopologyKey string `json:"topologyKey"`
+
+	// Relaxation tells whether the anti-affinity turns from required into preferred once a member
+	// cannot be placed because of it. Defaults to Never.
+	// +optional
+	Relaxation AntiAffinityRelaxation `json:"relaxation,omitempty"`
 }
 
+// AntiAffinityRelaxation tells whether the anti-affinity of the members of a pod group may be relaxed.
+type AntiAffinityRelaxation string
+
+const (
+	// AntiAffinityRelaxationNever always requires the anti-affinity.
+	AntiAffinityRelaxationNever AntiAffinityRelaxation = "Never"
+
+	// AntiAffinityRelaxationIfUnschedulable only prefers the anti-affinity once a member is
+	// unschedulable because of it, for the rest of the life of the pod group.
+	AntiAffinityRelaxationIfUnschedulable AntiAffinityRelaxation = "IfUnschedulable"
+)
+
 // PodGroupStatus represents the current state of a pod group.
 type PodGroupStatus struct {
 	// Current phase of PodGroup.
@@ -179,6 +308,40 @@ type PodGroupStatus struct {
 
 	// ScheduleStartTime of the group
 	ScheduleStartTime metav1.Time `json:"scheduleStartTime,omitempty"`
+
+	// Conditions represent the latest available observations of the pod group's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Milestones record when the gang went through the stages of its assembly.
+	// +optional
+	Milestones *PodGroupMilestones `json:"milestones,omitempty"`
+
+	// SchedulingFailures is the number of times the gang was rejected by the scheduler, counted
+	// if the pod group has a failure policy. It is reset when all pods of the group are gone.
+	// +optional
+	SchedulingFailures int32 `json:"schedulingFailures,omitempty"`
+}
+
+// PodGroupMilestones are the times a gang reached the stages of its assembly, telling where
+// gang scheduling time is spent. They are recorded once, and reset when all pods of the group
+// are gone.
+type PodGroupMilestones struct {
+	// FirstPodEnqueued is the creation time of the first pod of the group, queued for scheduling.
+	// +optional
+	FirstPodEnqueued *metav1.Time `json:"firstPodEnqueued,omitempty"`
+
+	// MinMemberReached is the creation time of the pod bringing the group to `spec.minMember` pods.
+	// +optional
+	MinMemberReached *metav1.Time `json:"minMemberReached,omitempty"`
+
+	// PermitGranted is the time `spec.minMember` pods got a node and were permitted together.
+	// +optional
+	PermitGranted *metav1.Time `json:"permitGranted,omitempty"`
+
+	// AllBound is the time `spec.minMember` pods were bound.
+	// +optional
+	AllBound *metav1.Time `json:"allBound,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -194,6 +357,76 @@ type PodGroupList struct {
 	Items []PodGroup `json:"items"`
 }
 
+// GangTimeoutPolicy is the action taken when a pod of a PodGroup times out waiting at Permit.
+type GangTimeoutPolicy string
+
+// These are the valid gang timeout policies.
+const (
+	// GangTimeoutRejectGang rejects the other pods of the PodGroup waiting at Permit and denies the
+	// whole PodGroup for `deniedPGExpirationTimeSeconds`.
+	GangTimeoutRejectGang GangTimeoutPolicy = "RejectGang"
+
+	// GangTimeoutRejectPod only rejects the pod that timed out; the other pods of the PodGroup keep
+	// waiting at Permit and the PodGroup is not denied.
+	GangTimeoutRejectPod GangTimeoutPolicy = "RejectPod"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName={csp,csps}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CoschedulingPolicy sets the defaults the Coscheduling plugin applies to the pods of its namespace.
+// A namespace is expected to have at most one CoschedulingPolicy; if it has several, the first one
+// by name is used.
+type CoschedulingPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// CoschedulingPolicySpec defines the defaults of the namespace.
+	// +optional
+	Spec CoschedulingPolicySpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// CoschedulingPolicySpec represents the template of a CoschedulingPolicy.
+type CoschedulingPolicySpec struct {
+	// PermitWaitingTimeSeconds is the default time the pods of a PodGroup wait at Permit for the rest
+	// of the group. The scheduleTimeoutSeconds of a PodGroup takes precedence over it, and it takes
+	// precedence over the permitWaitingTimeSeconds of the plugin args.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PermitWaitingTimeSeconds *int32 `json:"permitWaitingTimeSeconds,omitempty" protobuf:"varint,1,opt,name=permitWaitingTimeSeconds"`
+
+	// GangTimeoutPolicy is the action taken when a pod times out waiting at Permit.
+	// Defaults to RejectGang if not specified.
+	// +optional
+	// +kubebuilder:validation:Enum=RejectGang;RejectPod
+	GangTimeoutPolicy GangTimeoutPolicy `json:"gangTimeoutPolicy,omitempty" protobuf:"bytes,2,opt,name=gangTimeoutPolicy"`
+
+	// AutoGroupByOwner groups the pods of the namespace not labeled with a PodGroup by their
+	// controller owner (e.g., a ReplicaSet or a Job): the pods of an owner are scheduled as a gang
+	// whose minMember is the number of pods the owner has.
+	// +optional
+	AutoGroupByOwner bool `json:"autoGroupByOwner,omitempty" protobuf:"varint,3,opt,name=autoGroupByOwner"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CoschedulingPolicyList is a collection of coscheduling policies.
+type CoschedulingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of CoschedulingPolicy
+	Items []CoschedulingPolicy `json:"items"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -229,6 +462,36 @@ const (
 	AppGroupReverseTarjan   = "ReverseTarjan"
 	AppGroupAlternateKahn   = "AlternateKahn"
 	AppGroupAlternateTarjan = "AlternateTarjan"
+
+	// AppGroupDefaultTopologySortingAlgorithm is the algorithm of the AppGroups not specifying any
+	AppGroupDefaultTopologySortingAlgorithm = AppGroupKahnSort
+)
+
+// Labels set on the bound pods by the PlacementLabels plugin, for the pods to be aggregated by placement.
+const (
+	// PlacementZoneLabel is the zone of the node a pod is bound to.
+	PlacementZoneLabel = "placement-zone." + scheduling.GroupName
+	// PlacementRegionLabel is the region of the node a pod is bound to.
+	PlacementRegionLabel = "placement-region." + scheduling.GroupName
+	// PlacementCostBucketLabel is the widest topology boundary between a pod of an AppGroup and the placed
+	// pods of the dependencies of its workload.
+	PlacementCostBucketLabel = "placement-cost-bucket." + scheduling.GroupName
+
+	// Cost buckets of the pods of AppGroups
+	PlacementCostBucketSameZone    = "SameZone"
+	PlacementCostBucketCrossZone   = "CrossZone"
+	PlacementCostBucketCrossRegion = "CrossRegion"
+)
+
+// These are the valid condition types of AppGroups.
+const (
+	// AppGroupDependenciesResolved means every dependency of the AppGroup references a workload
+	// declared in the AppGroup, with a non-negative bandwidth and network cost.
+	AppGroupDependenciesResolved = "DependenciesResolved"
+
+	// AppGroupMinBandwidthSufficient means the bandwidth observed from every workload of the AppGroup
+	// to its dependencies, averaged over time, stays within their declared minimum egress bandwidth.
+	AppGroupMinBandwidthSufficient = "MinBandwidthSufficient"
 )
 
 // AppGroupSpec represents the template of a app group.
@@ -236,11 +499,44 @@ type AppGroupSpec struct {
 	// NumMembers defines the number of Pods belonging to the App Group
 	NumMembers int32 `json:"numMembers,omitempty" protobuf:"bytes,1,opt,name=numMembers"`
 
-	// The preferred Topology Sorting Algorithm
+	// The preferred Topology Sorting Algorithm. Defaults to KahnSort.
+	// +kubebuilder:default=KahnSort
+	// +optional
 	TopologySortingAlgorithm string `json:"topologySortingAlgorithm,omitempty" protobuf:"bytes,2,opt,name=topologySortingAlgorithm"`
 
 	// Workloads defines the workloads belonging to the group
 	Workloads AppGroupWorkloadList `json:"workloads,omitempty" protobuf:"bytes,3,rep,name=workloads, casttype=AppGroupWorkloadList"`
+
+	// ZoneDistribution configures the per-zone replica recommendations published in the status.
+	// No recommendation is computed if not specified.
+	// +optional
+	ZoneDistribution *AppGroupZoneDistribution `json:"zoneDistribution,omitempty" protobuf:"bytes,4,opt,name=zoneDistribution"`
+
+	// MaxPodsPerZone is the maximum number of pods of the AppGroup placed in any zone at a time, e.g.
+	// to match the capacity of a zone or to bound the blast radius of its failure. Enforced by the
+	// ZoneLimit plugin. Unlimited if not specified.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPodsPerZone int32 `json:"maxPodsPerZone,omitempty" protobuf:"bytes,5,opt,name=maxPodsPerZone"`
+}
+
+// AppGroupZoneDistribution configures how the replicas of the workloads are recommended to be
+// distributed across zones.
+// +protobuf=true
+type AppGroupZoneDistribution struct {
+	// NetworkTopologyName is the name of the NetworkTopology, in the namespace of the AppGroup,
+	// providing the zones and the network costs between them.
+	NetworkTopologyName string `json:"networkTopologyName" protobuf:"bytes,1,opt,name=networkTopologyName"`
+
+	// WeightsName is the name of the weights of the NetworkTopology to use. Defaults to UserDefined.
+	// +optional
+	WeightsName string `json:"weightsName,omitempty" protobuf:"bytes,2,opt,name=weightsName"`
+
+	// MaxSkew is the maximum difference between the numbers of replicas of a workload in any
+	// two zones. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty" protobuf:"bytes,3,opt,name=maxSkew"`
 }
 
 // AppGroupWorkload represents the Workloads belonging to the App Group.
@@ -251,6 +547,36 @@ type AppGroupWorkload struct {
 
 	// Dependencies of the Workload.
 	Dependencies DependenciesList `json:"dependencies,omitempty" protobuf:"bytes,2,opt,name=dependencies, casttype=DependenciesList"`
+
+	// RequiredZones restricts the replicas of the Workload to these zones, e.g. to anchor a stateful
+	// component next to its volumes. No zone is excluded if not specified.
+	// +optional
+	RequiredZones []string `json:"requiredZones,omitempty" protobuf:"bytes,3,rep,name=requiredZones"`
+
+	// PreferredZones receive the replicas of the Workload as long as one of them is available, among the
+	// required zones if any. The other zones are only used when none of them is.
+	// +optional
+	PreferredZones []string `json:"preferredZones,omitempty" protobuf:"bytes,4,rep,name=preferredZones"`
+
+	// Canary marks the Workload as a canary, or as the new color of a blue-green deployment, of another
+	// workload of the AppGroup, whose dependencies it shares.
+	// +optional
+	Canary *AppGroupCanary `json:"canary,omitempty" protobuf:"bytes,5,opt,name=canary"`
+}
+
+// AppGroupCanary represents the primary workload a canary Workload shares the dependencies of.
+// +protobuf=true
+type AppGroupCanary struct {
+	// Primary is the name of the workload of the AppGroup the Workload is a canary of.
+	Primary string `json:"primary" protobuf:"bytes,1,opt,name=primary"`
+
+	// WeightPercent is the share of the weight and of the minimum bandwidths of the dependencies of the
+	// primary given to the canary, so that the canary does not reserve the bandwidth of the primary twice.
+	// Defaults to 10 if not specified.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	WeightPercent *int32 `json:"weightPercent,omitempty" protobuf:"varint,2,opt,name=weightPercent"`
 }
 
 // AppGroupWorkloadInfo contains information about one workload.
@@ -278,19 +604,71 @@ type AppGroupWorkloadInfo struct {
 // +protobuf=true
 type AppGroupWorkloadList []AppGroupWorkload
 
+// BandwidthClass is the reservation class of the bandwidth demanded by a dependency.
+type BandwidthClass string
+
+// These are the valid bandwidth classes.
+const (
+	// BandwidthGuaranteed reserves the bandwidth of the dependency: the capacity of the links is enforced.
+	BandwidthGuaranteed BandwidthClass = "Guaranteed"
+
+	// BandwidthBurstable reserves the bandwidth of the dependency when the links have headroom left.
+	BandwidthBurstable BandwidthClass = "Burstable"
+
+	// BandwidthBestEffort reserves no bandwidth: the capacity of the links is not enforced.
+	BandwidthBestEffort BandwidthClass = "BestEffort"
+)
+
 // DependenciesInfo contains information about one dependency.
 // +protobuf=true
 type DependenciesInfo struct {
 	// Workload reference Info.
 	Workload AppGroupWorkloadInfo `json:"workload,omitempty" protobuf:"bytes,1,opt,name=workload, casttype=AppGroupWorkloadInfo"`
 
-	// MinBandwidth between workloads
+	// MinBandwidth between workloads. Defaults to 0, no demand.
+	// +kubebuilder:default="0"
 	// +optional
 	MinBandwidth resource.Quantity `json:"minBandwidth,omitempty" protobuf:"bytes,2,opt,name=minBandwidth"`
 
-	// Max Network Cost between workloads
+	// Max Network Cost between workloads. Defaults to 0, unbounded.
+	// +kubebuilder:default=0
 	// +optional
 	MaxNetworkCost int64 `json:"maxNetworkCost,omitempty" protobuf:"bytes,3,opt,name=maxNetworkCost"`
+
+	// MinIngressBandwidth demanded from the dependency to the workload.
+	// Defaults to MinBandwidth if not specified.
+	// +optional
+	MinIngressBandwidth *resource.Quantity `json:"minIngressBandwidth,omitempty" protobuf:"bytes,4,opt,name=minIngressBandwidth"`
+
+	// MinEgressBandwidth demanded from the workload to the dependency.
+	// Defaults to MinBandwidth if not specified.
+	// +optional
+	MinEgressBandwidth *resource.Quantity `json:"minEgressBandwidth,omitempty" protobuf:"bytes,5,opt,name=minEgressBandwidth"`
+
+	// Weight of the dependency relative to the other ones, so that high-traffic dependencies
+	// are kept closer than light ones. A weight of 0 makes the dependency ignored for scoring.
+	// Defaults to 1 if not specified.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Weight *int32 `json:"weight,omitempty" protobuf:"varint,6,opt,name=weight"`
+
+	// BandwidthClass of the bandwidth demanded by the dependency. Only the Guaranteed class has the capacity
+	// of the links enforced. Defaults to Guaranteed if not specified.
+	// +optional
+	// +kubebuilder:validation:Enum=Guaranteed;Burstable;BestEffort
+	BandwidthClass BandwidthClass `json:"bandwidthClass,omitempty" protobuf:"bytes,7,opt,name=bandwidthClass"`
+
+	// ZoneLocal marks a dependency backed by a replica in every zone, e.g. a DaemonSet or a per-zone
+	// deployment, so that a peer in the zone of the workload is assumed to always exist: the network
+	// cost to the dependency is zero wherever the workload is placed.
+	// +optional
+	ZoneLocal bool `json:"zoneLocal,omitempty" protobuf:"varint,8,opt,name=zoneLocal"`
+
+	// InterfaceClass of the network interfaces carrying the traffic of the dependency (e.g., storage),
+	// selecting the network costs and links of that class. Costs missing from the class fall back to
+	// the ones of the default class. Defaults to the default class if not specified.
+	// +optional
+	InterfaceClass string `json:"interfaceClass,omitempty" protobuf:"bytes,9,opt,name=interfaceClass"`
 }
 
 // DependenciesList contains an array of ResourceInfo objects.
@@ -311,8 +689,82 @@ type AppGroupStatus struct {
 
 	// Topology order for TopSort plugin (QueueSort)
 	TopologyOrder AppGroupTopologyList `json:"topologyOrder,omitempty" protobuf:"bytes,4,rep,name=topologyOrder,casttype=TopologyList"`
+
+	// ZoneRecommendations holds the recommended number of replicas of every workload per zone,
+	// minimizing the network cost between dependent workloads within the configured skew.
+	// +optional
+	ZoneRecommendations AppGroupZoneRecommendationList `json:"zoneRecommendations,omitempty" protobuf:"bytes,5,rep,name=zoneRecommendations,casttype=AppGroupZoneRecommendationList"`
+
+	// Conditions represent the latest available observations of the AppGroup's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,6,rep,name=conditions"`
+
+	// DependencyTraffic holds the bandwidth observed from the workloads to their dependencies, when
+	// the controller ingests the traffic measured by an eBPF exporter or a service mesh.
+	// +optional
+	DependencyTraffic AppGroupDependencyTrafficList `json:"dependencyTraffic,omitempty" protobuf:"bytes,7,rep,name=dependencyTraffic,casttype=AppGroupDependencyTrafficList"`
+
+	// ObservedGeneration is the generation of the spec the topology order was computed for. The
+	// topology order of a previous generation, e.g. before a dependency was added, is ignored.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,8,opt,name=observedGeneration"`
+}
+
+// AppGroupDependencyTraffic represents the bandwidth observed from a Workload to one of its dependencies.
+// +protobuf=true
+type AppGroupDependencyTraffic struct {
+	// Workload sending the traffic.
+	Workload AppGroupWorkloadInfo `json:"workload,omitempty" protobuf:"bytes,1,opt,name=workload,casttype=AppGroupWorkloadInfo"`
+
+	// Dependency receiving the traffic.
+	Dependency AppGroupWorkloadInfo `json:"dependency,omitempty" protobuf:"bytes,2,opt,name=dependency,casttype=AppGroupWorkloadInfo"`
+
+	// ObservedBandwidth is the bandwidth last measured, per second.
+	ObservedBandwidth resource.Quantity `json:"observedBandwidth,omitempty" protobuf:"bytes,3,opt,name=observedBandwidth"`
+
+	// AverageBandwidth is the moving average of the measured bandwidths, per second.
+	AverageBandwidth resource.Quantity `json:"averageBandwidth,omitempty" protobuf:"bytes,4,opt,name=averageBandwidth"`
+
+	// RecommendedMinBandwidth is the minimum bandwidth the dependency should declare: the average
+	// bandwidth plus the configured headroom.
+	RecommendedMinBandwidth resource.Quantity `json:"recommendedMinBandwidth,omitempty" protobuf:"bytes,5,opt,name=recommendedMinBandwidth"`
+
+	// LastObservationTime is the time of the last measurement.
+	LastObservationTime metav1.Time `json:"lastObservationTime,omitempty" protobuf:"bytes,6,opt,name=lastObservationTime"`
+}
+
+// AppGroupDependencyTrafficList contains an array of AppGroupDependencyTraffic objects.
+// +protobuf=true
+type AppGroupDependencyTrafficList []AppGroupDependencyTraffic
+
+// AppGroupZoneRecommendation represents the recommended distribution of a Workload across zones.
+// +protobuf=true
+type AppGroupZoneRecommendation struct {
+	// Workload reference Info.
+	Workload AppGroupWorkloadInfo `json:"workload,omitempty" protobuf:"bytes,1,opt,name=workload,casttype=AppGroupWorkloadInfo"`
+
+	// Zones holds the recommended number of replicas per zone. Zones without replicas are omitted.
+	Zones ZoneReplicasList `json:"zones,omitempty" protobuf:"bytes,2,rep,name=zones,casttype=ZoneReplicasList"`
+}
+
+// AppGroupZoneRecommendationList contains an array of AppGroupZoneRecommendation objects.
+// +protobuf=true
+type AppGroupZoneRecommendationList []AppGroupZoneRecommendation
+
+// ZoneReplicas represents a number of replicas in a zone.
+// +protobuf=true
+type ZoneReplicas struct {
+	// Zone name (i.e., value of the "topology.kubernetes.io/zone" node label).
+	Zone string `json:"zone" protobuf:"bytes,1,opt,name=zone"`
+
+	// Replicas recommended in the zone.
+	Replicas int32 `json:"replicas" protobuf:"bytes,2,opt,name=replicas"`
 }
 
+// ZoneReplicasList contains an array of ZoneReplicas objects.
+// +protobuf=true
+type ZoneReplicasList []ZoneReplicas
+
 // AppGroupTopologyInfo represents the calculated order for a given Workload.
 // +protobuf=true
 type AppGroupTopologyInfo struct {
@@ -355,6 +807,16 @@ const (
 	NetworkTopologyNetperfCosts string = "NetperfCosts"
 )
 
+// NetworkTopologyShadowOfAnnotation marks a NetworkTopology as a shadow of the NetworkTopology
+// named by its value, in the same namespace. A shadow describes a planned topology change (a new
+// zone, a link upgrade): it is ignored by the plugins and only compared to the live one.
+const NetworkTopologyShadowOfAnnotation = "shadow-of.network-topology." + scheduling.GroupName
+
+// NetworkTopologyRebuildBandwidthAnnotation asks the controller to rebuild the bandwidth allocated on
+// the links of a NetworkTopology from the pods currently assigned at once, rather than at its next
+// run. Its value is ignored, and the controller removes it once the allocations are rebuilt.
+const NetworkTopologyRebuildBandwidthAnnotation = "rebuild-bandwidth.network-topology." + scheduling.GroupName
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -380,8 +842,234 @@ type NetworkTopologySpec struct {
 	// The manual defined weights of the cluster
 	Weights WeightList `json:"weights,omitempty" protobuf:"bytes,1,opt,name=weights,casttype=WeightList"`
 
-	// ConfigmapName to be used for cost calculation
+	// ConfigmapName to be used for cost calculation. Defaults to netperfMetrics.
+	// Deprecated: the flat node-pair keys of the ConfigMap are not read; measurement agents write
+	// NetworkLatencies instead, consumed through a NetworkLatency cost source.
+	// +kubebuilder:default=netperfMetrics
+	// +optional
 	ConfigmapName string `json:"configmapName,omitempty" protobuf:"bytes,2,opt,name=configmapName"`
+
+	// LinkPolicies caps the bandwidth allocated on the links of a topology key,
+	// e.g. never allocate more than 80% of the inter-region capacity.
+	// +optional
+	LinkPolicies []LinkUtilizationPolicy `json:"linkPolicies,omitempty" protobuf:"bytes,3,rep,name=linkPolicies"`
+
+	// DefaultCost is the network cost of the origin-destination pairs missing from the weights, so that a
+	// forgotten entry does not make a link look free or unknown. If not specified, the plugins treat missing
+	// pairs as unknown and the zone recommendations of the controller as the most expensive ones.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DefaultCost *int64 `json:"defaultCost,omitempty" protobuf:"varint,4,opt,name=defaultCost"`
+
+	// DrainingZones are the zones under maintenance. The plugins stop favoring them as placement targets,
+	// and reject them once past their deadline, and the controller stops allocating new bandwidth to their links.
+	// +optional
+	DrainingZones []DrainingZone `json:"drainingZones,omitempty" protobuf:"bytes,5,rep,name=drainingZones"`
+
+	// WeightCalculationPeriod is the period between two calculations of the weights of the NetworkTopology by the
+	// controller, out of the nodes of the cluster and the costs known, e.g. "30m". If not specified, the default
+	// period of the controller applies.
+	// +optional
+	WeightCalculationPeriod *metav1.Duration `json:"weightCalculationPeriod,omitempty" protobuf:"bytes,6,opt,name=weightCalculationPeriod"`
+
+	// CostSource is the source of the costs of the NetworkTopology. If not specified, the costs are maintained
+	// by the operator, in the weights or in the ConfigMap.
+	// +optional
+	CostSource *CostSource `json:"costSource,omitempty" protobuf:"bytes,7,opt,name=costSource"`
+
+	// Tiers are the levels of the topology, from the widest to the narrowest, e.g. datacenter, rack and node.
+	// The controller computes the costs of each tier between the domains given by its node label. If not specified,
+	// the tiers are the region then the zone.
+	// +optional
+	Tiers []TopologyTier `json:"tiers,omitempty" protobuf:"bytes,8,rep,name=tiers"`
+
+	// WeightAlgorithm is the algorithm computing the weights calculated by the controller if Algorithms is empty.
+	// Deprecated: use Algorithms, of which it is the only algorithm.
+	// +kubebuilder:validation:Enum=Manual;Dijkstra;FloydWarshall
+	// +optional
+	WeightAlgorithm WeightAlgorithm `json:"weightAlgorithm,omitempty" protobuf:"bytes,9,opt,name=weightAlgorithm"`
+
+	// Algorithms are the algorithms computing the weights calculated by the controller, each one writing its own
+	// weights entry: Dijkstra the calculated weights themselves, the others an entry named after them with the
+	// algorithm as suffix (e.g., UserDefinedManual, UserDefinedFloydWarshall). A NetworkTopology only using
+	// the costs set by hand lists Manual alone, so that no path is searched. Defaults to WeightAlgorithm, then
+	// to Dijkstra.
+	// +optional
+	Algorithms []WeightAlgorithm `json:"algorithms,omitempty" protobuf:"bytes,10,rep,name=algorithms"`
+
+	// NodeSelector selects the nodes counted by the controller in the node count, the zones and regions and the
+	// costs calculated of the NetworkTopology, e.g. to leave out the control-plane or virtual kubelet nodes. If not
+	// specified, all the nodes selected by the controller are counted.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty" protobuf:"bytes,11,opt,name=nodeSelector"`
+}
+
+// WeightAlgorithm is an algorithm computing the costs between the origins of the weights.
+// +kubebuilder:validation:Enum=Manual;Dijkstra;FloydWarshall
+type WeightAlgorithm string
+
+const (
+	// WeightAlgorithmManual keeps the costs known and gives the other pairs the default costs, searching no path.
+	WeightAlgorithmManual WeightAlgorithm = "Manual"
+	// WeightAlgorithmDijkstra runs Dijkstra's algorithm from every origin.
+	WeightAlgorithmDijkstra WeightAlgorithm = "Dijkstra"
+	// WeightAlgorithmFloydWarshall runs the Floyd–Warshall algorithm over all the pairs of origins.
+	WeightAlgorithmFloydWarshall WeightAlgorithm = "FloydWarshall"
+)
+
+// TopologyTier is a level of the topology hierarchy.
+type TopologyTier struct {
+	// TopologyKey is the node label naming the domain of a node in the tier (e.g., "example.com/rack").
+	TopologyKey TopologyKey `json:"topologyKey" protobuf:"bytes,1,opt,name=topologyKey"`
+
+	// CrossCost is the default network cost between two domains of the tier, and between the domains of the
+	// narrower tiers they hold. If not specified, the default cross-region cost of the controller applies to
+	// the widest tier and the cross-zone cost to the others.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	CrossCost *int64 `json:"crossCost,omitempty" protobuf:"varint,2,opt,name=crossCost"`
+}
+
+// CostSourceType is the kind of source of the costs of a NetworkTopology.
+type CostSourceType string
+
+const (
+	// CostSourceManual costs are maintained by the operator.
+	CostSourceManual CostSourceType = "Manual"
+	// CostSourceNetperfAgent costs are the round-trip times measured between the nodes by probe agents.
+	CostSourceNetperfAgent CostSourceType = "NetperfAgent"
+	// CostSourcePrometheus costs are the latencies between nodes, zones or regions queried from Prometheus.
+	CostSourcePrometheus CostSourceType = "Prometheus"
+	// CostSourceNetworkLatency costs are the round-trip times between the nodes written as NetworkLatencies by
+	// measurement agents.
+	CostSourceNetworkLatency CostSourceType = "NetworkLatency"
+)
+
+// CostSource selects the source of the costs of a NetworkTopology.
+type CostSource struct {
+	// Type of the source, the member of the same name holding its configuration.
+	// +kubebuilder:validation:Enum=Manual;NetperfAgent;Prometheus;NetworkLatency
+	Type CostSourceType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=CostSourceType"`
+
+	// NetperfAgent configures the probe agents of the NetperfAgent source.
+	// +optional
+	NetperfAgent *NetperfAgentSource `json:"netperfAgent,omitempty" protobuf:"bytes,2,opt,name=netperfAgent"`
+
+	// Prometheus configures the latency query of the Prometheus source.
+	// +optional
+	Prometheus *PrometheusSource `json:"prometheus,omitempty" protobuf:"bytes,3,opt,name=prometheus"`
+
+	// NetworkLatency selects the NetworkLatencies of the NetworkLatency source.
+	// +optional
+	NetworkLatency *NetworkLatencySource `json:"networkLatency,omitempty" protobuf:"bytes,4,opt,name=networkLatency"`
+}
+
+// NetperfAgentSource is a DaemonSet of agents probing the round-trip times from their node to the other ones,
+// and serving them over HTTP. The controller writes the average round-trip times between the zones and
+// regions of the nodes, in milliseconds, as the costs of the NetworkTopology.
+type NetperfAgentSource struct {
+	// Namespace of the agent pods.
+	Namespace string `json:"namespace" protobuf:"bytes,1,opt,name=namespace"`
+
+	// Selector of the agent pods.
+	Selector *metav1.LabelSelector `json:"selector" protobuf:"bytes,2,opt,name=selector"`
+
+	// Port of the endpoint of the agents.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port" protobuf:"varint,3,opt,name=port"`
+
+	// Path of the endpoint of the agents. Defaults to "/rtt".
+	// +optional
+	Path string `json:"path,omitempty" protobuf:"bytes,4,opt,name=path"`
+
+	// WeightsName is the name of the weights receiving the costs. Defaults to "NetperfAgent".
+	// +optional
+	WeightsName string `json:"weightsName,omitempty" protobuf:"bytes,5,opt,name=weightsName"`
+}
+
+// NetworkLatencySource is the NetworkLatencies written by measurement agents in the namespace of the
+// NetworkTopology. The controller writes the average round-trip times between the zones and regions of their
+// nodes, in milliseconds, weighted by their samples, as the costs of the NetworkTopology.
+type NetworkLatencySource struct {
+	// Selector of the NetworkLatencies. All the ones of the namespace if not specified.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,1,opt,name=selector"`
+
+	// StaleAfter is the age of a measurement past which its weight in the averages decays linearly, down to
+	// none at twice StaleAfter, so that the measurements of the agents gone stop driving the costs. The
+	// measurements never decay if not specified.
+	// +optional
+	StaleAfter *metav1.Duration `json:"staleAfter,omitempty" protobuf:"bytes,2,opt,name=staleAfter"`
+
+	// WeightsName is the name of the weights receiving the costs. Defaults to "NetworkLatency".
+	// +optional
+	WeightsName string `json:"weightsName,omitempty" protobuf:"bytes,3,opt,name=weightsName"`
+}
+
+// PrometheusLocality is the kind of the endpoints of the latencies returned by a Prometheus query.
+type PrometheusLocality string
+
+const (
+	// PrometheusLocalityNode latencies are between nodes, named by their labels.
+	PrometheusLocalityNode PrometheusLocality = "Node"
+	// PrometheusLocalityZone latencies are between zones.
+	PrometheusLocalityZone PrometheusLocality = "Zone"
+	// PrometheusLocalityRegion latencies are between regions.
+	PrometheusLocalityRegion PrometheusLocality = "Region"
+)
+
+// PrometheusSource is a query of the latencies between two localities, e.g. measured by the blackbox or
+// smokeping exporters. The controller writes the average latencies between the zones and regions of the
+// localities, in milliseconds, as the costs of the NetworkTopology.
+type PrometheusSource struct {
+	// Address of the Prometheus API, e.g. "http://prometheus.monitoring:9090".
+	Address string `json:"address" protobuf:"bytes,1,opt,name=address"`
+
+	// Query is the PromQL expression returning an instant vector of the latencies, in milliseconds,
+	// e.g. "1000 * avg by (source, destination) (probe_duration_seconds)".
+	Query string `json:"query" protobuf:"bytes,2,opt,name=query"`
+
+	// Locality of the origins and destinations of the latencies. The latencies between nodes are averaged
+	// by pair of zones and of regions of the nodes, the ones between zones by pair of regions of the
+	// zones. Defaults to Node.
+	// +kubebuilder:validation:Enum=Node;Zone;Region
+	// +optional
+	Locality PrometheusLocality `json:"locality,omitempty" protobuf:"bytes,3,opt,name=locality,casttype=PrometheusLocality"`
+
+	// OriginLabel is the label of the samples naming the origin. Defaults to "source".
+	// +optional
+	OriginLabel string `json:"originLabel,omitempty" protobuf:"bytes,4,opt,name=originLabel"`
+
+	// DestinationLabel is the label of the samples naming the destination. Defaults to "destination".
+	// +optional
+	DestinationLabel string `json:"destinationLabel,omitempty" protobuf:"bytes,5,opt,name=destinationLabel"`
+
+	// WeightsName is the name of the weights receiving the costs. Defaults to "Prometheus".
+	// +optional
+	WeightsName string `json:"weightsName,omitempty" protobuf:"bytes,6,opt,name=weightsName"`
+}
+
+// DrainingZone marks a zone as draining for maintenance.
+type DrainingZone struct {
+	// Zone is the name of the draining zone.
+	Zone string `json:"zone" protobuf:"bytes,1,opt,name=zone"`
+
+	// Deadline after which the plugins reject the zone instead of only scoring it the lowest.
+	// If not specified, the zone is only scored the lowest.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty" protobuf:"bytes,2,opt,name=deadline"`
+}
+
+// LinkUtilizationPolicy caps the bandwidth allocated on the links of a topology key.
+type LinkUtilizationPolicy struct {
+	// Topology key of the links the policy applies to (e.g., "topology.kubernetes.io/region" for inter-region links).
+	TopologyKey TopologyKey `json:"topologyKey" protobuf:"bytes,1,opt,name=topologyKey"`
+
+	// MaxUtilizationPercent is the highest percentage of the bandwidth capacity of a link that may be allocated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxUtilizationPercent int32 `json:"maxUtilizationPercent" protobuf:"varint,2,opt,name=maxUtilizationPercent"`
 }
 
 // NetworkTopologyStatus represents the current state of a Network Topology.
@@ -419,8 +1107,32 @@ type TopologyInfo struct {
 
 	// OriginList for a particular origin.
 	OriginList OriginList `json:"originList,omitempty" protobuf:"bytes,2,rep,name=originList,casttype=OriginList"`
+
+	// CostUnit is the unit of the network costs of the origin list. The topologies of a weight list
+	// must share their unit. Defaults to Abstract.
+	// +optional
+	// +kubebuilder:validation:Enum=Abstract;Milliseconds;MilliDollarsPerGB
+	CostUnit NetworkCostUnit `json:"costUnit,omitempty" protobuf:"bytes,3,opt,name=costUnit"`
+
+	// InterfaceClass of the network interfaces the costs are measured on (e.g., data, storage), for nodes
+	// with separate networks. A weight list may hold a topology per key and class. Defaults to the
+	// default class, used by the dependencies not selecting any class.
+	// +optional
+	InterfaceClass string `json:"interfaceClass,omitempty" protobuf:"bytes,4,opt,name=interfaceClass"`
 }
 
+// NetworkCostUnit is the unit of network costs.
+type NetworkCostUnit string
+
+const (
+	// NetworkCostUnitAbstract costs only rank the links, e.g. set by hand.
+	NetworkCostUnitAbstract NetworkCostUnit = "Abstract"
+	// NetworkCostUnitMilliseconds costs are latencies, e.g. measured by probes or a service mesh.
+	NetworkCostUnitMilliseconds NetworkCostUnit = "Milliseconds"
+	// NetworkCostUnitMilliDollarsPerGB costs are the prices of the traffic, in thousandths of a dollar per GB.
+	NetworkCostUnitMilliDollarsPerGB NetworkCostUnit = "MilliDollarsPerGB"
+)
+
 // OriginList contains an array of OriginInfo objects.
 // +protobuf=true
 type OriginList []OriginInfo
@@ -449,12 +1161,33 @@ type CostInfo struct {
 	// +optional
 	BandwidthCapacity resource.Quantity `json:"bandwidthCapacity,omitempty" protobuf:"bytes,2,opt,name=bandwidthCapacity"`
 
-	// Bandwidth allocated between origin and destination.
+	// Bandwidth allocated between origin and destination, written by the bandwidth controller out of
+	// the bandwidth demanded by the bound pods of the AppGroups.
 	// +optional
 	BandwidthAllocated resource.Quantity `json:"bandwidthAllocated,omitempty" protobuf:"bytes,3,opt,name=bandwidthAllocated"`
 
 	// Network Cost between origin and destination (e.g., Dijkstra shortest path, etc)
 	NetworkCost int64 `json:"networkCost,omitempty" protobuf:"bytes,4,opt,name=networkCost"`
+
+	// Parallel links between origin and destination (e.g., ECMP paths, distinct capacity pools).
+	// When set, the bandwidth between origin and destination is the one of the links, and
+	// BandwidthCapacity and BandwidthAllocated are ignored.
+	// +optional
+	Links []ParallelLink `json:"links,omitempty" protobuf:"bytes,5,rep,name=links"`
+}
+
+// ParallelLink is one of the parallel links between an origin and a destination.
+type ParallelLink struct {
+	// Name of the link (e.g., the name of the path or of the capacity pool).
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// Bandwidth capacity of the link.
+	// +optional
+	BandwidthCapacity resource.Quantity `json:"bandwidthCapacity,omitempty" protobuf:"bytes,2,opt,name=bandwidthCapacity"`
+
+	// Bandwidth allocated on the link, written by the bandwidth controller.
+	// +optional
+	BandwidthAllocated resource.Quantity `json:"bandwidthAllocated,omitempty" protobuf:"bytes,3,opt,name=bandwidthAllocated"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -469,3 +1202,369 @@ type NetworkTopologyList struct {
 	// Items is the list of AppGroup
 	Items []NetworkTopology `json:"items"`
 }
+
+// LinkHotspotNetworkTopologyLabel is set on a LinkHotspot to the name of the NetworkTopology it was raised for.
+const LinkHotspotNetworkTopologyLabel = "network-topology." + scheduling.GroupName
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName={lh,lhs}
+// +kubebuilder:printcolumn:name="Origin",type=string,JSONPath=`.spec.origin`
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destination`
+// +kubebuilder:printcolumn:name="Utilization",type=integer,JSONPath=`.spec.utilizationPercent`
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LinkHotspot signals that the bandwidth allocated on a link of a NetworkTopology crossed its utilization
+// threshold. It is created by the controller, deleted once the link cools down, and consumed by rebalancing
+// components choosing pods to move away from the link. The allocated bandwidth is the one written by the
+// bandwidth controller, so that no LinkHotspot is raised when it is disabled.
+type LinkHotspot struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// LinkHotspotSpec defines the link and its utilization.
+	// +optional
+	Spec LinkHotspotSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// LinkHotspotSpec represents the template of a LinkHotspot.
+type LinkHotspotSpec struct {
+	// NetworkTopology is the name of the NetworkTopology, in the same namespace, declaring the link.
+	NetworkTopology string `json:"networkTopology" protobuf:"bytes,1,opt,name=networkTopology"`
+
+	// Weights is the name of the weights of the NetworkTopology declaring the link (e.g., UserDefined).
+	Weights string `json:"weights" protobuf:"bytes,2,opt,name=weights"`
+
+	// Topology key of the link (e.g., "topology.kubernetes.io/zone").
+	TopologyKey TopologyKey `json:"topologyKey" protobuf:"bytes,3,opt,name=topologyKey"`
+
+	// Origin of the link (e.g., Region Name, Zone Name).
+	Origin string `json:"origin" protobuf:"bytes,4,opt,name=origin"`
+
+	// Destination of the link (e.g., Region Name, Zone Name).
+	Destination string `json:"destination" protobuf:"bytes,5,opt,name=destination"`
+
+	// Bandwidth capacity of the link.
+	BandwidthCapacity resource.Quantity `json:"bandwidthCapacity" protobuf:"bytes,6,opt,name=bandwidthCapacity"`
+
+	// Bandwidth allocated on the link.
+	BandwidthAllocated resource.Quantity `json:"bandwidthAllocated" protobuf:"bytes,7,opt,name=bandwidthAllocated"`
+
+	// UtilizationPercent is the percentage of the bandwidth capacity of the link that is allocated.
+	UtilizationPercent int32 `json:"utilizationPercent" protobuf:"varint,8,opt,name=utilizationPercent"`
+
+	// ThresholdPercent is the utilization above which the link is considered a hotspot.
+	ThresholdPercent int32 `json:"thresholdPercent" protobuf:"varint,9,opt,name=thresholdPercent"`
+
+	// InterfaceClass of the link, empty for the default class.
+	// +optional
+	InterfaceClass string `json:"interfaceClass,omitempty" protobuf:"bytes,10,opt,name=interfaceClass"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LinkHotspotList is a collection of link hotspots.
+type LinkHotspotList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of LinkHotspot
+	Items []LinkHotspot `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName={nl,nls}
+// +kubebuilder:printcolumn:name="Origin",type=string,JSONPath=`.spec.origin`
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destination`
+// +kubebuilder:printcolumn:name="RTT",type=string,JSONPath=`.spec.rtt`
+// +kubebuilder:printcolumn:name="Measured",type=date,JSONPath=`.spec.timestamp`
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkLatency is a round-trip time measured from a node to another one. It is written by measurement agents,
+// one per pair of nodes they probe, in the namespace of the NetworkTopologies whose cost source is NetworkLatency,
+// and consumed by the controller writing their costs.
+type NetworkLatency struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// NetworkLatencySpec defines the pair of nodes and the round-trip time measured between them.
+	// +optional
+	Spec NetworkLatencySpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// NetworkLatencySpec represents the template of a NetworkLatency.
+type NetworkLatencySpec struct {
+	// Origin is the name of the node the round-trip time is measured from.
+	Origin string `json:"origin" protobuf:"bytes,1,opt,name=origin"`
+
+	// Destination is the name of the node the round-trip time is measured to.
+	Destination string `json:"destination" protobuf:"bytes,2,opt,name=destination"`
+
+	// RTT is the round-trip time measured, averaged over the samples (e.g., "1.5ms").
+	RTT metav1.Duration `json:"rtt" protobuf:"bytes,3,opt,name=rtt"`
+
+	// Timestamp is the time of the last sample.
+	Timestamp metav1.Time `json:"timestamp" protobuf:"bytes,4,opt,name=timestamp"`
+
+	// Samples is the number of probes the round-trip time is averaged over, its weight in the averages of the
+	// controller. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Samples int32 `json:"samples,omitempty" protobuf:"varint,5,opt,name=samples"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkLatencyList is a collection of network latencies.
+type NetworkLatencyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of NetworkLatency
+	Items []NetworkLatency `json:"items"`
+}
+
+// Constants for InterferencePolicy
+const (
+	// InterferenceClassLabel is the label declaring the interference class of a pod (e.g., latency-critical)
+	InterferenceClassLabel = "interference-class." + scheduling.GroupName
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={ip,ips}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InterferencePolicy declares which interference classes of workloads disturb each other when colocated on a node.
+type InterferencePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// InterferencePolicySpec defines the conflicting interference classes.
+	// +optional
+	Spec InterferencePolicySpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// InterferencePolicySpec represents the template of an InterferencePolicy.
+type InterferencePolicySpec struct {
+	// Rules defines the interference classes conflicting with each other.
+	// +optional
+	Rules []InterferenceRule `json:"rules,omitempty" protobuf:"bytes,1,rep,name=rules"`
+}
+
+// InterferenceRule declares that pods of a given class conflict with pods of other classes.
+// Conflicts are symmetric: a node is penalized whether the incoming pod or the pod already
+// running on the node carries Class.
+type InterferenceRule struct {
+	// Class is the interference class the rule applies to (e.g., latency-critical).
+	Class string `json:"class" protobuf:"bytes,1,opt,name=class"`
+
+	// ConflictingClasses are the interference classes disturbing pods of Class (e.g., cache-thrashing-batch).
+	ConflictingClasses []string `json:"conflictingClasses" protobuf:"bytes,2,rep,name=conflictingClasses"`
+
+	// Penalty applied for every conflicting pod found on a node. Defaults to 1 if not specified.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Penalty int64 `json:"penalty,omitempty" protobuf:"bytes,3,opt,name=penalty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InterferencePolicyList is a collection of interference policies.
+type InterferencePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of InterferencePolicy
+	Items []InterferencePolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={rm,rms}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RegistryMirror declares the zones hosting a mirror or a pull-through cache of an image registry.
+type RegistryMirror struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// RegistryMirrorSpec defines the mirrored registry and the zones hosting the mirror.
+	// +optional
+	Spec RegistryMirrorSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// RegistryMirrorSpec represents the template of a RegistryMirror.
+type RegistryMirrorSpec struct {
+	// Registry is the host of the mirrored registry (e.g., docker.io). The mirror serves
+	// all registries if not specified.
+	// +optional
+	Registry string `json:"registry,omitempty" protobuf:"bytes,1,opt,name=registry"`
+
+	// Zones hosting the mirror (i.e., values of the "topology.kubernetes.io/zone" node label).
+	Zones []string `json:"zones" protobuf:"bytes,2,rep,name=zones"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RegistryMirrorList is a collection of registry mirrors.
+type RegistryMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of RegistryMirror
+	Items []RegistryMirror `json:"items"`
+}
+
+// NodeEgressBandwidthCapacityAnnotation is set by the controller on the nodes matching a
+// NodeBandwidthProfile to the egress bandwidth of their NIC, in bits per second.
+const NodeEgressBandwidthCapacityAnnotation = scheduling.GroupName + "/egress-bandwidth-capacity"
+
+// NodeInterruptionRiskAnnotation may be set on a node, e.g. by a feed of the spot prices and interruption
+// rates of the cloud provider, to the likelihood, in [0, 100], that the node gets interrupted.
+const NodeInterruptionRiskAnnotation = scheduling.GroupName + "/interruption-risk"
+
+// NodeImageFSUsageAnnotation may be set on a node, e.g. by an exporter of the kubelet stats, to the usage, in
+// [0, 100], of the image filesystem of the node in percent.
+const NodeImageFSUsageAnnotation = scheduling.GroupName + "/imagefs-usage"
+
+// NodeCPUPressureAnnotation, NodeMemoryPressureAnnotation and NodeIOPressureAnnotation may be set on a node, e.g.
+// by an exporter of /proc/pressure, to the share of time, in [0, 100] percent, some tasks of the node stalled on
+// the CPU, the memory or the IO over the last minute, i.e. the "some avg60" pressure stall information (PSI).
+const (
+	NodeCPUPressureAnnotation    = scheduling.GroupName + "/cpu-pressure"
+	NodeMemoryPressureAnnotation = scheduling.GroupName + "/memory-pressure"
+	NodeIOPressureAnnotation     = scheduling.GroupName + "/io-pressure"
+)
+
+// PodSoftEvictionAnnotation is set by the load-aware plugins configured to, on the pods most likely to
+// over-consume on a node they observe consistently overloaded, to the reason of this soft eviction hint.
+// Deschedulers or operators may act on it; it is removed once the node is no longer overloaded.
+const PodSoftEvictionAnnotation = scheduling.GroupName + "/soft-eviction"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={nbp,nbps}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeBandwidthProfile declares the NIC bandwidth of the nodes of an instance type.
+type NodeBandwidthProfile struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// NodeBandwidthProfileSpec defines the instance type and the bandwidth of its NIC.
+	// +optional
+	Spec NodeBandwidthProfileSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// NodeBandwidthProfileSpec represents the template of a NodeBandwidthProfile.
+type NodeBandwidthProfileSpec struct {
+	// InstanceType is the value of the "node.kubernetes.io/instance-type" label of the nodes
+	// the profile applies to.
+	InstanceType string `json:"instanceType" protobuf:"bytes,1,opt,name=instanceType"`
+
+	// EgressBandwidth is the egress bandwidth of the NIC of the nodes, in bits per second (e.g., 10G).
+	EgressBandwidth resource.Quantity `json:"egressBandwidth" protobuf:"bytes,2,opt,name=egressBandwidth"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeBandwidthProfileList is a collection of node bandwidth profiles.
+type NodeBandwidthProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of NodeBandwidthProfile
+	Items []NodeBandwidthProfile `json:"items"`
+}
+
+// VirtualNodeLabel set to "true" marks a virtual or edge node, e.g. of virtual-kubelet, standing for downstream
+// capacity, whose VirtualNodeProfile of the same name describes the downstream capacity and its network cost.
+const VirtualNodeLabel = scheduling.GroupName + "/virtual-node"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={vnp,vnps}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualNodeProfile is exported by a virtual or edge node, named after it, for the plugins to treat the node
+// as an aggregate of its downstream capacity in its own topology tier rather than as an ordinary node.
+type VirtualNodeProfile struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// VirtualNodeProfileSpec defines the downstream capacity of the node and its network cost.
+	// +optional
+	Spec VirtualNodeProfileSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// VirtualNodeProfileSpec represents the template of a VirtualNodeProfile.
+type VirtualNodeProfileSpec struct {
+	// Allocatable is the aggregate allocatable of the downstream capacity (e.g., of the edge site or the
+	// serverless provider behind the node), used in place of the allocatable the node reports, often
+	// arbitrarily large.
+	// +optional
+	Allocatable v1.ResourceList `json:"allocatable,omitempty" protobuf:"bytes,1,rep,name=allocatable,casttype=ResourceList,castkey=ResourceName"`
+
+	// TopologyTier is the topology domain of the downstream capacity, looked up as a zone in the network
+	// costs of the NetworkTopology in place of the zone of the node (e.g., "edge-paris").
+	// +optional
+	TopologyTier string `json:"topologyTier,omitempty" protobuf:"bytes,2,opt,name=topologyTier"`
+
+	// NetworkCost is the network cost of reaching the downstream capacity from any zone of the cluster,
+	// used when the NetworkTopology has no cost for the topology tier.
+	// +optional
+	NetworkCost int64 `json:"networkCost,omitempty" protobuf:"varint,3,opt,name=networkCost"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualNodeProfileList is a collection of virtual node profiles.
+type VirtualNodeProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of VirtualNodeProfile
+	Items []VirtualNodeProfile `json:"items"`
+}
@@ -24,6 +24,7 @@ package v1alpha1
 import (
 	"k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -55,6 +56,71 @@ func (in *AppGroup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppGroupCanary) DeepCopyInto(out *AppGroupCanary) {
+	*out = *in
+	if in.WeightPercent != nil {
+		in, out := &in.WeightPercent, &out.WeightPercent
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupCanary.
+func (in *AppGroupCanary) DeepCopy() *AppGroupCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppGroupDependencyTraffic) DeepCopyInto(out *AppGroupDependencyTraffic) {
+	*out = *in
+	out.Workload = in.Workload
+	out.Dependency = in.Dependency
+	out.ObservedBandwidth = in.ObservedBandwidth.DeepCopy()
+	out.AverageBandwidth = in.AverageBandwidth.DeepCopy()
+	out.RecommendedMinBandwidth = in.RecommendedMinBandwidth.DeepCopy()
+	in.LastObservationTime.DeepCopyInto(&out.LastObservationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupDependencyTraffic.
+func (in *AppGroupDependencyTraffic) DeepCopy() *AppGroupDependencyTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupDependencyTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in AppGroupDependencyTrafficList) DeepCopyInto(out *AppGroupDependencyTrafficList) {
+	{
+		in := &in
+		*out = make(AppGroupDependencyTrafficList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupDependencyTrafficList.
+func (in AppGroupDependencyTrafficList) DeepCopy() AppGroupDependencyTrafficList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupDependencyTrafficList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppGroupList) DeepCopyInto(out *AppGroupList) {
 	*out = *in
@@ -98,6 +164,11 @@ func (in *AppGroupSpec) DeepCopyInto(out *AppGroupSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ZoneDistribution != nil {
+		in, out := &in.ZoneDistribution, &out.ZoneDistribution
+		*out = new(AppGroupZoneDistribution)
+		**out = **in
+	}
 	return
 }
 
@@ -121,6 +192,27 @@ func (in *AppGroupStatus) DeepCopyInto(out *AppGroupStatus) {
 		*out = make(AppGroupTopologyList, len(*in))
 		copy(*out, *in)
 	}
+	if in.ZoneRecommendations != nil {
+		in, out := &in.ZoneRecommendations, &out.ZoneRecommendations
+		*out = make(AppGroupZoneRecommendationList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependencyTraffic != nil {
+		in, out := &in.DependencyTraffic, &out.DependencyTraffic
+		*out = make(AppGroupDependencyTrafficList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -182,6 +274,21 @@ func (in *AppGroupWorkload) DeepCopyInto(out *AppGroupWorkload) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RequiredZones != nil {
+		in, out := &in.RequiredZones, &out.RequiredZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredZones != nil {
+		in, out := &in.PreferredZones, &out.PreferredZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(AppGroupCanary)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -233,11 +340,159 @@ func (in AppGroupWorkloadList) DeepCopy() AppGroupWorkloadList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppGroupZoneDistribution) DeepCopyInto(out *AppGroupZoneDistribution) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupZoneDistribution.
+func (in *AppGroupZoneDistribution) DeepCopy() *AppGroupZoneDistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupZoneDistribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppGroupZoneRecommendation) DeepCopyInto(out *AppGroupZoneRecommendation) {
+	*out = *in
+	out.Workload = in.Workload
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make(ZoneReplicasList, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupZoneRecommendation.
+func (in *AppGroupZoneRecommendation) DeepCopy() *AppGroupZoneRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupZoneRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in AppGroupZoneRecommendationList) DeepCopyInto(out *AppGroupZoneRecommendationList) {
+	{
+		in := &in
+		*out = make(AppGroupZoneRecommendationList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppGroupZoneRecommendationList.
+func (in AppGroupZoneRecommendationList) DeepCopy() AppGroupZoneRecommendationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppGroupZoneRecommendationList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoschedulingPolicy) DeepCopyInto(out *CoschedulingPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoschedulingPolicy.
+func (in *CoschedulingPolicy) DeepCopy() *CoschedulingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CoschedulingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CoschedulingPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoschedulingPolicyList) DeepCopyInto(out *CoschedulingPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CoschedulingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoschedulingPolicyList.
+func (in *CoschedulingPolicyList) DeepCopy() *CoschedulingPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(CoschedulingPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CoschedulingPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoschedulingPolicySpec) DeepCopyInto(out *CoschedulingPolicySpec) {
+	*out = *in
+	if in.PermitWaitingTimeSeconds != nil {
+		in, out := &in.PermitWaitingTimeSeconds, &out.PermitWaitingTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoschedulingPolicySpec.
+func (in *CoschedulingPolicySpec) DeepCopy() *CoschedulingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CoschedulingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CostInfo) DeepCopyInto(out *CostInfo) {
 	*out = *in
 	out.BandwidthCapacity = in.BandwidthCapacity.DeepCopy()
 	out.BandwidthAllocated = in.BandwidthAllocated.DeepCopy()
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]ParallelLink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -251,6 +506,37 @@ func (in *CostInfo) DeepCopy() *CostInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostSource) DeepCopyInto(out *CostSource) {
+	*out = *in
+	if in.NetperfAgent != nil {
+		in, out := &in.NetperfAgent, &out.NetperfAgent
+		*out = new(NetperfAgentSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusSource)
+		**out = **in
+	}
+	if in.NetworkLatency != nil {
+		in, out := &in.NetworkLatency, &out.NetworkLatency
+		*out = new(NetworkLatencySource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostSource.
+func (in *CostSource) DeepCopy() *CostSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CostSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in CostList) DeepCopyInto(out *CostList) {
 	{
@@ -278,6 +564,21 @@ func (in *DependenciesInfo) DeepCopyInto(out *DependenciesInfo) {
 	*out = *in
 	out.Workload = in.Workload
 	out.MinBandwidth = in.MinBandwidth.DeepCopy()
+	if in.MinIngressBandwidth != nil {
+		in, out := &in.MinIngressBandwidth, &out.MinIngressBandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MinEgressBandwidth != nil {
+		in, out := &in.MinEgressBandwidth, &out.MinEgressBandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -313,6 +614,26 @@ func (in DependenciesList) DeepCopy() DependenciesList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainingZone) DeepCopyInto(out *DrainingZone) {
+	*out = *in
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainingZone.
+func (in *DrainingZone) DeepCopy() *DrainingZone {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainingZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticQuota) DeepCopyInto(out *ElasticQuota) {
 	*out = *in
@@ -411,6 +732,11 @@ func (in *ElasticQuotaStatus) DeepCopyInto(out *ElasticQuotaStatus) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.LastPreemptionDryRun != nil {
+		in, out := &in.LastPreemptionDryRun, &out.LastPreemptionDryRun
+		*out = new(PreemptionDryRun)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticQuotaStatus.
@@ -424,27 +750,26 @@ func (in *ElasticQuotaStatus) DeepCopy() *ElasticQuotaStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkTopology) DeepCopyInto(out *NetworkTopology) {
+func (in *InterferencePolicy) DeepCopyInto(out *InterferencePolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopology.
-func (in *NetworkTopology) DeepCopy() *NetworkTopology {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferencePolicy.
+func (in *InterferencePolicy) DeepCopy() *InterferencePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkTopology)
+	out := new(InterferencePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NetworkTopology) DeepCopyObject() runtime.Object {
+func (in *InterferencePolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -452,13 +777,13 @@ func (in *NetworkTopology) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkTopologyList) DeepCopyInto(out *NetworkTopologyList) {
+func (in *InterferencePolicyList) DeepCopyInto(out *InterferencePolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NetworkTopology, len(*in))
+		*out = make([]InterferencePolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -466,18 +791,18 @@ func (in *NetworkTopologyList) DeepCopyInto(out *NetworkTopologyList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyList.
-func (in *NetworkTopologyList) DeepCopy() *NetworkTopologyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferencePolicyList.
+func (in *InterferencePolicyList) DeepCopy() *InterferencePolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkTopologyList)
+	out := new(InterferencePolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NetworkTopologyList) DeepCopyObject() runtime.Object {
+func (in *InterferencePolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -485,11 +810,11 @@ func (in *NetworkTopologyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkTopologySpec) DeepCopyInto(out *NetworkTopologySpec) {
+func (in *InterferencePolicySpec) DeepCopyInto(out *InterferencePolicySpec) {
 	*out = *in
-	if in.Weights != nil {
-		in, out := &in.Weights, &out.Weights
-		*out = make(WeightList, len(*in))
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]InterferenceRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -497,61 +822,506 @@ func (in *NetworkTopologySpec) DeepCopyInto(out *NetworkTopologySpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologySpec.
-func (in *NetworkTopologySpec) DeepCopy() *NetworkTopologySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferencePolicySpec.
+func (in *InterferencePolicySpec) DeepCopy() *InterferencePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkTopologySpec)
+	out := new(InterferencePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkTopologyStatus) DeepCopyInto(out *NetworkTopologyStatus) {
+func (in *InterferenceRule) DeepCopyInto(out *InterferenceRule) {
 	*out = *in
-	in.WeightCalculationTime.DeepCopyInto(&out.WeightCalculationTime)
+	if in.ConflictingClasses != nil {
+		in, out := &in.ConflictingClasses, &out.ConflictingClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyStatus.
-func (in *NetworkTopologyStatus) DeepCopy() *NetworkTopologyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferenceRule.
+func (in *InterferenceRule) DeepCopy() *InterferenceRule {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkTopologyStatus)
+	out := new(InterferenceRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OriginInfo) DeepCopyInto(out *OriginInfo) {
+func (in *LinkHotspot) DeepCopyInto(out *LinkHotspot) {
 	*out = *in
-	if in.CostList != nil {
-		in, out := &in.CostList, &out.CostList
-		*out = make(CostList, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OriginInfo.
-func (in *OriginInfo) DeepCopy() *OriginInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkHotspot.
+func (in *LinkHotspot) DeepCopy() *LinkHotspot {
 	if in == nil {
 		return nil
 	}
-	out := new(OriginInfo)
+	out := new(LinkHotspot)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in OriginList) DeepCopyInto(out *OriginList) {
-	{
-		in := &in
-		*out = make(OriginList, len(*in))
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LinkHotspot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkHotspotList) DeepCopyInto(out *LinkHotspotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LinkHotspot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkHotspotList.
+func (in *LinkHotspotList) DeepCopy() *LinkHotspotList {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkHotspotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LinkHotspotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkHotspotSpec) DeepCopyInto(out *LinkHotspotSpec) {
+	*out = *in
+	out.BandwidthCapacity = in.BandwidthCapacity.DeepCopy()
+	out.BandwidthAllocated = in.BandwidthAllocated.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkHotspotSpec.
+func (in *LinkHotspotSpec) DeepCopy() *LinkHotspotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkHotspotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkUtilizationPolicy) DeepCopyInto(out *LinkUtilizationPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkUtilizationPolicy.
+func (in *LinkUtilizationPolicy) DeepCopy() *LinkUtilizationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkUtilizationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetperfAgentSource) DeepCopyInto(out *NetperfAgentSource) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetperfAgentSource.
+func (in *NetperfAgentSource) DeepCopy() *NetperfAgentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NetperfAgentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatency) DeepCopyInto(out *NetworkLatency) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatency.
+func (in *NetworkLatency) DeepCopy() *NetworkLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLatency) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencyList) DeepCopyInto(out *NetworkLatencyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkLatency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencyList.
+func (in *NetworkLatencyList) DeepCopy() *NetworkLatencyList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLatencyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencySource) DeepCopyInto(out *NetworkLatencySource) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StaleAfter != nil {
+		in, out := &in.StaleAfter, &out.StaleAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencySource.
+func (in *NetworkLatencySource) DeepCopy() *NetworkLatencySource {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencySpec) DeepCopyInto(out *NetworkLatencySpec) {
+	*out = *in
+	out.RTT = in.RTT
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencySpec.
+func (in *NetworkLatencySpec) DeepCopy() *NetworkLatencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTopology) DeepCopyInto(out *NetworkTopology) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopology.
+func (in *NetworkTopology) DeepCopy() *NetworkTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkTopology) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTopologyList) DeepCopyInto(out *NetworkTopologyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkTopology, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyList.
+func (in *NetworkTopologyList) DeepCopy() *NetworkTopologyList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTopologyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkTopologyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTopologySpec) DeepCopyInto(out *NetworkTopologySpec) {
+	*out = *in
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make(WeightList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LinkPolicies != nil {
+		in, out := &in.LinkPolicies, &out.LinkPolicies
+		*out = make([]LinkUtilizationPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultCost != nil {
+		in, out := &in.DefaultCost, &out.DefaultCost
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DrainingZones != nil {
+		in, out := &in.DrainingZones, &out.DrainingZones
+		*out = make([]DrainingZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WeightCalculationPeriod != nil {
+		in, out := &in.WeightCalculationPeriod, &out.WeightCalculationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CostSource != nil {
+		in, out := &in.CostSource, &out.CostSource
+		*out = new(CostSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]TopologyTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Algorithms != nil {
+		in, out := &in.Algorithms, &out.Algorithms
+		*out = make([]WeightAlgorithm, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologySpec.
+func (in *NetworkTopologySpec) DeepCopy() *NetworkTopologySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTopologySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTopologyStatus) DeepCopyInto(out *NetworkTopologyStatus) {
+	*out = *in
+	in.WeightCalculationTime.DeepCopyInto(&out.WeightCalculationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyStatus.
+func (in *NetworkTopologyStatus) DeepCopy() *NetworkTopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBandwidthProfile) DeepCopyInto(out *NodeBandwidthProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBandwidthProfile.
+func (in *NodeBandwidthProfile) DeepCopy() *NodeBandwidthProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBandwidthProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeBandwidthProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBandwidthProfileList) DeepCopyInto(out *NodeBandwidthProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeBandwidthProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBandwidthProfileList.
+func (in *NodeBandwidthProfileList) DeepCopy() *NodeBandwidthProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBandwidthProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeBandwidthProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBandwidthProfileSpec) DeepCopyInto(out *NodeBandwidthProfileSpec) {
+	*out = *in
+	out.EgressBandwidth = in.EgressBandwidth.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBandwidthProfileSpec.
+func (in *NodeBandwidthProfileSpec) DeepCopy() *NodeBandwidthProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBandwidthProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OriginInfo) DeepCopyInto(out *OriginInfo) {
+	*out = *in
+	if in.CostList != nil {
+		in, out := &in.CostList, &out.CostList
+		*out = make(CostList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OriginInfo.
+func (in *OriginInfo) DeepCopy() *OriginInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(OriginInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in OriginList) DeepCopyInto(out *OriginList) {
+	{
+		in := &in
+		*out = make(OriginList, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -564,9 +1334,27 @@ func (in OriginList) DeepCopy() OriginList {
 	if in == nil {
 		return nil
 	}
-	out := new(OriginList)
+	out := new(OriginList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelLink) DeepCopyInto(out *ParallelLink) {
+	*out = *in
+	out.BandwidthCapacity = in.BandwidthCapacity.DeepCopy()
+	out.BandwidthAllocated = in.BandwidthAllocated.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelLink.
+func (in *ParallelLink) DeepCopy() *ParallelLink {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelLink)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -596,6 +1384,42 @@ func (in *PodGroup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodGroupAntiAffinity) DeepCopyInto(out *PodGroupAntiAffinity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupAntiAffinity.
+func (in *PodGroupAntiAffinity) DeepCopy() *PodGroupAntiAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(PodGroupAntiAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodGroupFailurePolicy) DeepCopyInto(out *PodGroupFailurePolicy) {
+	*out = *in
+	if in.BackoffSeconds != nil {
+		in, out := &in.BackoffSeconds, &out.BackoffSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupFailurePolicy.
+func (in *PodGroupFailurePolicy) DeepCopy() *PodGroupFailurePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PodGroupFailurePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodGroupList) DeepCopyInto(out *PodGroupList) {
 	*out = *in
@@ -628,6 +1452,37 @@ func (in *PodGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodGroupMilestones) DeepCopyInto(out *PodGroupMilestones) {
+	*out = *in
+	if in.FirstPodEnqueued != nil {
+		in, out := &in.FirstPodEnqueued, &out.FirstPodEnqueued
+		*out = (*in).DeepCopy()
+	}
+	if in.MinMemberReached != nil {
+		in, out := &in.MinMemberReached, &out.MinMemberReached
+		*out = (*in).DeepCopy()
+	}
+	if in.PermitGranted != nil {
+		in, out := &in.PermitGranted, &out.PermitGranted
+		*out = (*in).DeepCopy()
+	}
+	if in.AllBound != nil {
+		in, out := &in.AllBound, &out.AllBound
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupMilestones.
+func (in *PodGroupMilestones) DeepCopy() *PodGroupMilestones {
+	if in == nil {
+		return nil
+	}
+	out := new(PodGroupMilestones)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodGroupSpec) DeepCopyInto(out *PodGroupSpec) {
 	*out = *in
@@ -647,6 +1502,35 @@ func (in *PodGroupSpec) DeepCopyInto(out *PodGroupSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(v1.NodeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemberAntiAffinity != nil {
+		in, out := &in.MemberAntiAffinity, &out.MemberAntiAffinity
+		*out = new(PodGroupAntiAffinity)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(PodGroupFailurePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupSpec.
@@ -663,6 +1547,18 @@ func (in *PodGroupSpec) DeepCopy() *PodGroupSpec {
 func (in *PodGroupStatus) DeepCopyInto(out *PodGroupStatus) {
 	*out = *in
 	in.ScheduleStartTime.DeepCopyInto(&out.ScheduleStartTime)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Milestones != nil {
+		in, out := &in.Milestones, &out.Milestones
+		*out = new(PodGroupMilestones)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupStatus.
@@ -675,6 +1571,124 @@ func (in *PodGroupStatus) DeepCopy() *PodGroupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreemptionDryRun) DeepCopyInto(out *PreemptionDryRun) {
+	*out = *in
+	if in.Victims != nil {
+		in, out := &in.Victims, &out.Victims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreemptionDryRun.
+func (in *PreemptionDryRun) DeepCopy() *PreemptionDryRun {
+	if in == nil {
+		return nil
+	}
+	out := new(PreemptionDryRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusSource) DeepCopyInto(out *PrometheusSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSource.
+func (in *PrometheusSource) DeepCopy() *PrometheusSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryMirror) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirrorList) DeepCopyInto(out *RegistryMirrorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirrorList.
+func (in *RegistryMirrorList) DeepCopy() *RegistryMirrorList {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirrorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryMirrorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirrorSpec) DeepCopyInto(out *RegistryMirrorSpec) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirrorSpec.
+func (in *RegistryMirrorSpec) DeepCopy() *RegistryMirrorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirrorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologyInfo) DeepCopyInto(out *TopologyInfo) {
 	*out = *in
@@ -720,6 +1734,110 @@ func (in TopologyList) DeepCopy() TopologyList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyTier) DeepCopyInto(out *TopologyTier) {
+	*out = *in
+	if in.CrossCost != nil {
+		in, out := &in.CrossCost, &out.CrossCost
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyTier.
+func (in *TopologyTier) DeepCopy() *TopologyTier {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNodeProfile) DeepCopyInto(out *VirtualNodeProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNodeProfile.
+func (in *VirtualNodeProfile) DeepCopy() *VirtualNodeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNodeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualNodeProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNodeProfileList) DeepCopyInto(out *VirtualNodeProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualNodeProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNodeProfileList.
+func (in *VirtualNodeProfileList) DeepCopy() *VirtualNodeProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNodeProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualNodeProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNodeProfileSpec) DeepCopyInto(out *VirtualNodeProfileSpec) {
+	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNodeProfileSpec.
+func (in *VirtualNodeProfileSpec) DeepCopy() *VirtualNodeProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNodeProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WeightInfo) DeepCopyInto(out *WeightInfo) {
 	*out = *in
@@ -764,3 +1882,39 @@ func (in WeightList) DeepCopy() WeightList {
 	in.DeepCopyInto(out)
 	return *out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneReplicas) DeepCopyInto(out *ZoneReplicas) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneReplicas.
+func (in *ZoneReplicas) DeepCopy() *ZoneReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ZoneReplicasList) DeepCopyInto(out *ZoneReplicasList) {
+	{
+		in := &in
+		*out = make(ZoneReplicasList, len(*in))
+		copy(*out, *in)
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneReplicasList.
+func (in ZoneReplicasList) DeepCopy() ZoneReplicasList {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneReplicasList)
+	in.DeepCopyInto(out)
+	return *out
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	goruntime "runtime"
+	"sort"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	pluginconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/scheme"
+	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta2"
+	"sigs.k8s.io/scheduler-plugins/pkg/backfill"
+	"sigs.k8s.io/scheduler-plugins/pkg/capacityscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/imagelocality"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology"
+	"sigs.k8s.io/scheduler-plugins/pkg/noisyneighbor"
+	"sigs.k8s.io/scheduler-plugins/pkg/registry"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// Extension points measured, per scheduling cycle of a pending pod. Filter and Score run over
+// all the nodes of the cluster, Score including NormalizeScore.
+const (
+	PreFilter = "PreFilter"
+	Filter    = "Filter"
+	PreScore  = "PreScore"
+	Score     = "Score"
+)
+
+// apiServerPlugins build clients of the scheduler-plugins API out of the kubeconfig of the
+// scheduler, so they cannot run against a synthetic cluster.
+var apiServerPlugins = sets.NewString(
+	backfill.Name,
+	capacityscheduling.Name,
+	coscheduling.Name,
+	imagelocality.Name,
+	noderesourcetopology.Name,
+	noisyneighbor.Name,
+)
+
+// Options are the plugins to measure and how many times.
+type Options struct {
+	// Plugins are the names of the plugins, all those not needing an API server if empty.
+	Plugins []string
+	// Iterations is the number of scheduling cycles measured per extension point.
+	Iterations int
+}
+
+// Result is the cost of an extension point of a plugin, per scheduling cycle.
+type Result struct {
+	Plugin         string `json:"plugin"`
+	ExtensionPoint string `json:"extensionPoint"`
+	Iterations     int    `json:"iterations"`
+	NsPerOp        int64  `json:"nsPerOp"`
+	P50Ns          int64  `json:"p50Ns"`
+	P99Ns          int64  `json:"p99Ns"`
+	AllocsPerOp    uint64 `json:"allocsPerOp"`
+	BytesPerOp     uint64 `json:"bytesPerOp"`
+}
+
+// Report holds the results of the plugins over a synthetic cluster.
+type Report struct {
+	Cluster ClusterSpec `json:"cluster"`
+	Results []Result    `json:"results"`
+}
+
+// DefaultPlugins returns the plugins of the in-tree registry not needing an API server.
+func DefaultPlugins() []string {
+	var names []string
+	for name := range registry.NewInTreeRegistry() {
+		if !apiServerPlugins.Has(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run measures the extension points of the plugins over the synthetic cluster of spec.
+func Run(ctx context.Context, spec ClusterSpec, opts Options) (*Report, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Iterations <= 0 {
+		return nil, fmt.Errorf("iterations should be positive, got %d", opts.Iterations)
+	}
+	names := opts.Plugins
+	if len(names) == 0 {
+		names = DefaultPlugins()
+	}
+	c := newCluster(spec)
+
+	loadWatcher := httptest.NewServer(watcherHandler(c.nodes))
+	defer loadWatcher.Close()
+
+	cs := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	snapshot := testutil.NewFakeSharedLister(c.runningPods, c.nodes)
+	fh, err := testutil.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		}, nil, "bench",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithPodNominator(testutil.NewPodNominator(nil)),
+		frameworkruntime.WithSnapshotSharedLister(snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("creating framework: %w", err)
+	}
+
+	r := registry.NewInTreeRegistry()
+	var plugins []framework.Plugin
+	for _, name := range names {
+		factory, ok := r[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown plugin %q", name)
+		}
+		if apiServerPlugins.Has(name) {
+			return nil, fmt.Errorf("plugin %q needs an API server", name)
+		}
+		args, err := pluginArgs(name, loadWatcher.URL)
+		if err != nil {
+			return nil, err
+		}
+		p, err := factory(args, fh)
+		if err != nil {
+			return nil, fmt.Errorf("creating plugin %q: %w", name, err)
+		}
+		plugins = append(plugins, p)
+	}
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	nodeInfos, err := snapshot.NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Cluster: spec}
+	for _, p := range plugins {
+		report.Results = append(report.Results, benchPlugin(ctx, p, c.pendingPods, nodeInfos, opts.Iterations)...)
+	}
+	return report, nil
+}
+
+// pluginArgs returns the default args of the plugin, nil if it has none, pointing the load aware
+// plugins to the load watcher at watcherAddress.
+func pluginArgs(name, watcherAddress string) (runtime.Object, error) {
+	kind := name + "Args"
+	versioned, err := scheme.Scheme.New(v1beta2.SchemeGroupVersion.WithKind(kind))
+	if runtime.IsNotRegisteredError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	scheme.Scheme.Default(versioned)
+	args, err := scheme.Scheme.New(pluginconfig.SchemeGroupVersion.WithKind(kind))
+	if err != nil {
+		return nil, err
+	}
+	if err := scheme.Scheme.Convert(versioned, args, nil); err != nil {
+		return nil, fmt.Errorf("converting the args of plugin %q: %w", name, err)
+	}
+
+	switch args := args.(type) {
+	case *pluginconfig.TargetLoadPackingArgs:
+		args.WatcherAddress = watcherAddress
+	case *pluginconfig.LoadVariationRiskBalancingArgs:
+		args.WatcherAddress = watcherAddress
+	case *pluginconfig.CELPolicyArgs:
+		args.Rules = []pluginconfig.CELPolicyRule{
+			{
+				Name:       "same-workload-zone",
+				Expression: fmt.Sprintf(`node.labels[%q] == "zone-0" && pod.labels[%q] == %q`, v1.LabelTopologyZone, workloadLabel, workloadName(0)),
+				Action:     pluginconfig.CELPolicyScore,
+				Score:      50,
+			},
+		}
+	}
+	return args, nil
+}
+
+// watcherHandler serves the load of the nodes as a load watcher would, the same at every call.
+func watcherHandler(nodes []*v1.Node) http.Handler {
+	metrics := watcher.WatcherMetrics{
+		Window: watcher.Window{Duration: "15m"},
+		Data:   watcher.Data{NodeMetricsMap: make(map[string]watcher.NodeMetrics, len(nodes))},
+	}
+	for i, node := range nodes {
+		metrics.Data.NodeMetricsMap[node.Name] = watcher.NodeMetrics{
+			Metrics: []watcher.Metric{
+				{Type: watcher.CPU, Operator: watcher.Average, Value: float64(i * 13 % 60)},
+				{Type: watcher.CPU, Operator: watcher.Std, Value: 5},
+				{Type: watcher.Memory, Operator: watcher.Average, Value: float64(i * 7 % 50)},
+				{Type: watcher.Memory, Operator: watcher.Std, Value: 5},
+			},
+		}
+	}
+	body, _ := json.Marshal(metrics)
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(body)
+	})
+}
+
+// benchPlugin measures the extension points p implements. The statuses are ignored: a cycle costs
+// the same whether the pod fits or not.
+func benchPlugin(ctx context.Context, p framework.Plugin, pods []*v1.Pod, nodeInfos []*framework.NodeInfo, iterations int) []Result {
+	nodes := make([]*v1.Node, 0, len(nodeInfos))
+	for _, n := range nodeInfos {
+		nodes = append(nodes, n.Node())
+	}
+	preFilter, _ := p.(framework.PreFilterPlugin)
+	filter, _ := p.(framework.FilterPlugin)
+	preScore, _ := p.(framework.PreScorePlugin)
+	score, _ := p.(framework.ScorePlugin)
+
+	// The cycle states Filter and Score read, as written by PreFilter and PreScore.
+	states := make([]*framework.CycleState, len(pods))
+	for i, pod := range pods {
+		states[i] = framework.NewCycleState()
+		if preFilter != nil {
+			preFilter.PreFilter(ctx, states[i], pod)
+		}
+		if preScore != nil {
+			preScore.PreScore(ctx, states[i], pod, nodes)
+		}
+	}
+	newStates := func() []*framework.CycleState {
+		s := make([]*framework.CycleState, iterations)
+		for i := range s {
+			s[i] = framework.NewCycleState()
+		}
+		return s
+	}
+
+	var results []Result
+	if preFilter != nil {
+		fresh := newStates()
+		results = append(results, measure(p.Name(), PreFilter, iterations, func(i int) {
+			preFilter.PreFilter(ctx, fresh[i], pods[i%len(pods)])
+		}))
+	}
+	if filter != nil {
+		results = append(results, measure(p.Name(), Filter, iterations, func(i int) {
+			for _, nodeInfo := range nodeInfos {
+				filter.Filter(ctx, states[i%len(pods)], pods[i%len(pods)], nodeInfo)
+			}
+		}))
+	}
+	if preScore != nil {
+		fresh := newStates()
+		results = append(results, measure(p.Name(), PreScore, iterations, func(i int) {
+			preScore.PreScore(ctx, fresh[i], pods[i%len(pods)], nodes)
+		}))
+	}
+	if score != nil {
+		scores := make(framework.NodeScoreList, len(nodes))
+		results = append(results, measure(p.Name(), Score, iterations, func(i int) {
+			state, pod := states[i%len(pods)], pods[i%len(pods)]
+			for j, node := range nodes {
+				s, _ := score.Score(ctx, state, pod, node.Name)
+				scores[j] = framework.NodeScore{Name: node.Name, Score: s}
+			}
+			if ext := score.ScoreExtensions(); ext != nil {
+				ext.NormalizeScore(ctx, state, pod, scores)
+			}
+		}))
+	}
+	return results
+}
+
+// measure runs op iterations times, after a warm up run, and returns its mean and percentile
+// latencies and its mean allocations.
+func measure(plugin, extensionPoint string, iterations int, op func(i int)) Result {
+	op(0)
+	durations := make([]time.Duration, iterations)
+	goruntime.GC()
+	var before, after goruntime.MemStats
+	goruntime.ReadMemStats(&before)
+	var total time.Duration
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		op(i)
+		durations[i] = time.Since(start)
+		total += durations[i]
+	}
+	goruntime.ReadMemStats(&after)
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	n := uint64(iterations)
+	return Result{
+		Plugin:         plugin,
+		ExtensionPoint: extensionPoint,
+		Iterations:     iterations,
+		NsPerOp:        total.Nanoseconds() / int64(iterations),
+		P50Ns:          durations[iterations/2].Nanoseconds(),
+		P99Ns:          durations[iterations*99/100].Nanoseconds(),
+		AllocsPerOp:    (after.Mallocs - before.Mallocs) / n,
+		BytesPerOp:     (after.TotalAlloc - before.TotalAlloc) / n,
+	}
+}
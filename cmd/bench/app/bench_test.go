@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/celpolicy"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/nodebandwidth"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/targetloadpacking"
+)
+
+var smallCluster = ClusterSpec{Nodes: 6, Zones: 3, Regions: 1, PodsPerNode: 2, AppGroupWorkloads: 2, GangSize: 3}
+
+func TestNewCluster(t *testing.T) {
+	c := newCluster(smallCluster)
+	if len(c.nodes) != 6 || len(c.runningPods) != 12 || len(c.pendingPods) != 6 {
+		t.Errorf("want 6 nodes, 12 running and 6 pending pods, got %d, %d and %d", len(c.nodes), len(c.runningPods), len(c.pendingPods))
+	}
+	zones := map[string]int{}
+	for _, n := range c.nodes {
+		zones[n.Labels["topology.kubernetes.io/zone"]]++
+	}
+	if len(zones) != 3 || zones["zone-0"] != 2 {
+		t.Errorf("want the nodes spread over 3 zones, got %v", zones)
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), smallCluster, Options{
+		Plugins:    []string{nodebandwidth.Name, celpolicy.Name, targetloadpacking.Name},
+		Iterations: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, r := range report.Results {
+		if r.Iterations != 3 {
+			t.Errorf("want 3 iterations of %s/%s, got %d", r.Plugin, r.ExtensionPoint, r.Iterations)
+		}
+		got[r.Plugin+"/"+r.ExtensionPoint] = true
+	}
+	for _, want := range []string{nodebandwidth.Name + "/" + Filter, celpolicy.Name + "/" + Filter,
+		celpolicy.Name + "/" + Score, targetloadpacking.Name + "/" + Score} {
+		if !got[want] {
+			t.Errorf("want a result for %s, got %v", want, report.Results)
+		}
+	}
+
+	if _, err := Run(context.Background(), smallCluster, Options{Plugins: []string{coscheduling.Name}, Iterations: 1}); err == nil {
+		t.Errorf("want an error for a plugin needing an API server")
+	}
+	if _, err := Run(context.Background(), ClusterSpec{Nodes: 1, Zones: 2, Regions: 1, AppGroupWorkloads: 1, GangSize: 1},
+		Options{Iterations: 1}); err == nil {
+		t.Errorf("want an error for more zones than nodes")
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/nodebandwidth"
+)
+
+const (
+	benchNamespace = "bench"
+	benchAppGroup  = "bench"
+	workloadLabel  = "app"
+)
+
+// ClusterSpec are the parameters of a synthetic cluster.
+type ClusterSpec struct {
+	// Nodes is the number of nodes, spread over the zones.
+	Nodes int `json:"nodes"`
+	// Zones is the number of zones, spread over the regions.
+	Zones int `json:"zones"`
+	// Regions is the number of regions.
+	Regions int `json:"regions"`
+	// PodsPerNode is the number of pods already running on every node.
+	PodsPerNode int `json:"podsPerNode"`
+	// AppGroupWorkloads is the number of workloads of the AppGroup the pods belong to.
+	AppGroupWorkloads int `json:"appGroupWorkloads"`
+	// GangSize is the number of pending pods of the gang of every workload.
+	GangSize int `json:"gangSize"`
+}
+
+func (s ClusterSpec) validate() error {
+	if s.Nodes <= 0 || s.Zones <= 0 || s.Regions <= 0 || s.AppGroupWorkloads <= 0 || s.GangSize <= 0 {
+		return fmt.Errorf("nodes, zones, regions, appGroupWorkloads and gangSize should be positive, got %+v", s)
+	}
+	if s.PodsPerNode < 0 {
+		return fmt.Errorf("podsPerNode should not be negative, got %d", s.PodsPerNode)
+	}
+	if s.Zones < s.Regions || s.Nodes < s.Zones {
+		return fmt.Errorf("want at least a zone per region and a node per zone, got %+v", s)
+	}
+	return nil
+}
+
+// cluster is a synthetic cluster: its nodes, the pods running on them and the pending pods.
+type cluster struct {
+	nodes       []*v1.Node
+	runningPods []*v1.Pod
+	pendingPods []*v1.Pod
+}
+
+// newCluster returns the cluster of spec. It is deterministic, so that the results of two runs
+// over the same spec compare.
+func newCluster(spec ClusterSpec) *cluster {
+	c := &cluster{}
+	for i := 0; i < spec.Nodes; i++ {
+		zone := i % spec.Zones
+		c.nodes = append(c.nodes, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("node-%d", i),
+				Labels: map[string]string{
+					v1.LabelHostname:       fmt.Sprintf("node-%d", i),
+					v1.LabelOSStable:       "linux",
+					v1.LabelTopologyZone:   fmt.Sprintf("zone-%d", zone),
+					v1.LabelTopologyRegion: fmt.Sprintf("region-%d", zone%spec.Regions),
+				},
+				Annotations: map[string]string{
+					v1alpha1.NodeEgressBandwidthCapacityAnnotation: "10G",
+				},
+			},
+			Status: v1.NodeStatus{
+				Capacity:    nodeResources(),
+				Allocatable: nodeResources(),
+			},
+		})
+		for j := 0; j < spec.PodsPerNode; j++ {
+			pod := newPod(fmt.Sprintf("running-%d-%d", i, j), workloadName((i+j)%spec.AppGroupWorkloads))
+			pod.Spec.NodeName = c.nodes[i].Name
+			c.runningPods = append(c.runningPods, pod)
+		}
+	}
+	for w := 0; w < spec.AppGroupWorkloads; w++ {
+		for j := 0; j < spec.GangSize; j++ {
+			c.pendingPods = append(c.pendingPods, newPod(fmt.Sprintf("pending-%d-%d", w, j), workloadName(w)))
+		}
+	}
+	return c
+}
+
+func nodeResources() v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("32"),
+		v1.ResourceMemory: resource.MustParse("128Gi"),
+		v1.ResourcePods:   resource.MustParse("110"),
+	}
+}
+
+func workloadName(i int) string {
+	return fmt.Sprintf("workload-%d", i)
+}
+
+// newPod returns a pod of the gang of workload, in the AppGroup.
+func newPod(name, workload string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: benchNamespace,
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				workloadLabel:          workload,
+				v1alpha1.PodGroupLabel: workload,
+				v1alpha1.AppGroupLabel: benchAppGroup,
+			},
+			Annotations: map[string]string{
+				nodebandwidth.EgressBandwidthAnnotation: "100M",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "app",
+				Image: "registry.k8s.io/pause:3.6",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("250m"),
+						v1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				},
+			}},
+		},
+	}
+}
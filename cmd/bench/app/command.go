@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the command measuring the plugins over synthetic clusters, writing a JSON
+// report of the results to out, or to the --output file.
+func NewCommand(out io.Writer) *cobra.Command {
+	spec := ClusterSpec{}
+	opts := Options{}
+	var clustersFile, outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure the latency and allocations of the extension points of the plugins over synthetic clusters",
+		Long: `Measure the latency and allocations of the extension points of the plugins over synthetic clusters.
+
+Every cluster is generated from its parameters, the same at every run, so that the reports of two
+revisions compare. The plugins needing an API server, e.g. for their custom resources, are not
+supported.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			specs := []ClusterSpec{spec}
+			if clustersFile != "" {
+				data, err := ioutil.ReadFile(clustersFile)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(data, &specs); err != nil {
+					return fmt.Errorf("parsing %s: %w", clustersFile, err)
+				}
+			}
+
+			var reports []*Report
+			for _, s := range specs {
+				report, err := Run(cmd.Context(), s, opts)
+				if err != nil {
+					return err
+				}
+				reports = append(reports, report)
+			}
+
+			w := out
+			if outputFile != "" {
+				f, err := os.Create(outputFile)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(reports)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&spec.Nodes, "nodes", 2000, "The number of nodes of the cluster.")
+	flags.IntVar(&spec.Zones, "zones", 10, "The number of zones the nodes are spread over.")
+	flags.IntVar(&spec.Regions, "regions", 2, "The number of regions the zones are spread over.")
+	flags.IntVar(&spec.PodsPerNode, "pods-per-node", 10, "The number of pods already running on every node.")
+	flags.IntVar(&spec.AppGroupWorkloads, "app-group-workloads", 3, "The number of workloads of the AppGroup of the pods.")
+	flags.IntVar(&spec.GangSize, "gang-size", 5, "The number of pending pods of the gang of every workload.")
+	flags.StringVar(&clustersFile, "clusters", "", "A JSON file holding a list of cluster parameters, e.g. "+
+		`[{"nodes": 500, "zones": 5, "regions": 1, "podsPerNode": 10, "appGroupWorkloads": 3, "gangSize": 5}], overriding the cluster flags.`)
+	flags.StringSliceVar(&opts.Plugins, "plugins", nil, "The plugins to measure, all those not needing an API server if empty.")
+	flags.IntVar(&opts.Iterations, "iterations", 100, "The number of scheduling cycles measured per extension point.")
+	flags.StringVarP(&outputFile, "output", "o", "", "The file the JSON report is written to, the standard output if empty.")
+	return cmd
+}
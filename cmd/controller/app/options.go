@@ -17,7 +17,11 @@ limitations under the License.
 package app
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/controller"
 )
 
 type ServerRunOptions struct {
@@ -28,6 +32,58 @@ type ServerRunOptions struct {
 	ApiServerBurst       int
 	Workers              int
 	EnableLeaderElection bool
+	DebugBindAddress     string
+	DebugAuthorization   bool
+	MetricsBindAddress   string
+	EnableProfiling      bool
+
+	NetworkCostSmoothingAlpha float64
+	NetworkCostMinChange      int64
+	LinkHotspotThreshold      int32
+	BandwidthInterval         time.Duration
+	BandwidthAllocationTTL    time.Duration
+
+	MeshPrometheusAddress string
+	MeshProvider          string
+	MeshLatencyQuantile   float64
+	MeshNetworkTopology   string
+	MeshWeightsName       string
+	MeshCostInterval      time.Duration
+
+	TrafficPrometheusAddress string
+	TrafficQuery             string
+	TrafficSmoothingAlpha    float64
+	TrafficHeadroomPercent   int32
+	TrafficAutoTune          bool
+	TrafficInterval          time.Duration
+
+	NetperfInterval time.Duration
+	NetperfTimeout  time.Duration
+	NetperfWorkers  int
+
+	PrometheusCostInterval time.Duration
+	PrometheusCostTimeout  time.Duration
+
+	NetworkLatencyInterval time.Duration
+
+	BootstrapNetworkTopology string
+	BootstrapWeightsName     string
+	SameZoneCost             int64
+	CrossZoneCost            int64
+	CrossRegionCost          int64
+	NetworkCostWorkers       int
+	WeightCalculationPeriod  time.Duration
+
+	TopologyIgnoreNotReadyNodes      bool
+	TopologyIgnoreUnschedulableNodes bool
+	TopologyIgnoredTaints            []string
+	TopologyNodeSelector             string
+
+	MachinePoolQuotas     bool
+	MachinePoolKubeConfig string
+	MachinePoolResources  []string
+
+	SparkApplications bool
 }
 
 func NewServerRunOptions() *ServerRunOptions {
@@ -44,4 +100,46 @@ func (s *ServerRunOptions) addAllFlags() {
 	pflag.IntVar(&s.ApiServerBurst, "burst", 10, "burst of query apiserver.")
 	pflag.IntVar(&s.Workers, "workers", 1, "workers of scheduler-plugin-controllers.")
 	pflag.BoolVar(&s.EnableLeaderElection, "enableLeaderElection", s.EnableLeaderElection, "If EnableLeaderElection for controller.")
+	pflag.StringVar(&s.DebugBindAddress, "debugBindAddress", s.DebugBindAddress, "Address serving the comparison of shadow NetworkTopologies at "+controller.ShadowComparePath+". Disabled if empty.")
+	pflag.BoolVar(&s.DebugAuthorization, "debugAuthorization", s.DebugAuthorization, "If the callers of the debug endpoints must present a bearer token allowed to get the AppGroups of the requested namespace.")
+	pflag.StringVar(&s.MetricsBindAddress, "metricsBindAddress", s.MetricsBindAddress, "Address serving the controller metrics at /metrics. Disabled if empty.")
+	pflag.BoolVar(&s.EnableProfiling, "enableProfiling", s.EnableProfiling, "If the pprof profiles of the controller are also served at /debug/pprof/ on --metricsBindAddress.")
+	pflag.Float64Var(&s.NetworkCostSmoothingAlpha, "networkCostSmoothingAlpha", 1, "Weight, in (0, 1], of the latest network cost of a NetworkTopology link in its moving average used for zone recommendations. 1 disables the averaging.")
+	pflag.Int64Var(&s.NetworkCostMinChange, "networkCostMinChange", 0, "Minimum change of the averaged network cost of a NetworkTopology link for the AppGroups distributed across it to be updated.")
+	pflag.Int32Var(&s.LinkHotspotThreshold, "linkHotspotThreshold", 90, "Percentage of the bandwidth capacity of a NetworkTopology link above which a LinkHotspot is raised, for the topology keys without a link policy. 0 disables these hotspots.")
+	pflag.DurationVar(&s.BandwidthInterval, "bandwidthInterval", 30*time.Second, "Period between two calculations of the bandwidth allocated on the NetworkTopology links by the bound pods of the AppGroups. 0 disables the calculation.")
+	pflag.DurationVar(&s.BandwidthAllocationTTL, "bandwidthAllocationTTL", 0, "Duration after which the bandwidth allocated by a pod terminating, or on a node not Ready, is released, so that the pods lost on crashed nodes do not leak it. 0 keeps it until the pod is deleted.")
+	pflag.StringVar(&s.MeshPrometheusAddress, "meshPrometheusAddress", s.MeshPrometheusAddress, "Address of the Prometheus scraping a service mesh, whose latencies between zones are written as the costs of --meshNetworkTopology. Disabled if empty.")
+	pflag.StringVar(&s.MeshProvider, "meshProvider", string(controller.MeshIstio), "Service mesh exporting the latencies, istio or linkerd.")
+	pflag.Float64Var(&s.MeshLatencyQuantile, "meshLatencyQuantile", 0.9, "Latency percentile, in (0, 1], used as network cost between zones.")
+	pflag.StringVar(&s.MeshNetworkTopology, "meshNetworkTopology", s.MeshNetworkTopology, "Namespace/name of the NetworkTopology receiving the service mesh costs.")
+	pflag.StringVar(&s.MeshWeightsName, "meshWeightsName", "Mesh", "Name of the NetworkTopology weights holding the service mesh costs.")
+	pflag.DurationVar(&s.MeshCostInterval, "meshCostInterval", time.Minute, "Period between two queries of the service mesh latencies.")
+	pflag.StringVar(&s.TrafficPrometheusAddress, "trafficPrometheusAddress", s.TrafficPrometheusAddress, "Address of the Prometheus scraping an eBPF exporter or a service mesh, whose bandwidth between workloads is written into the status of the AppGroups. Disabled if empty.")
+	pflag.StringVar(&s.TrafficQuery, "trafficQuery", controller.DefaultTrafficQuery, "PromQL query of the bandwidth between workloads, in bytes per second, labeled by source_workload, source_workload_namespace, destination_workload and destination_workload_namespace.")
+	pflag.Float64Var(&s.TrafficSmoothingAlpha, "trafficSmoothingAlpha", 0.3, "Weight, in (0, 1], of the latest bandwidth measured between two workloads in its moving average.")
+	pflag.Int32Var(&s.TrafficHeadroomPercent, "trafficHeadroomPercent", 20, "Percentage of the average bandwidth between two workloads added to it to recommend their minimum bandwidth.")
+	pflag.BoolVar(&s.TrafficAutoTune, "trafficAutoTune", s.TrafficAutoTune, "If the declared minimum bandwidths of the AppGroup dependencies are rewritten to the recommended ones when exceeded, or over twice the recommended ones.")
+	pflag.DurationVar(&s.TrafficInterval, "trafficInterval", time.Minute, "Period between two queries of the bandwidth between workloads.")
+	pflag.DurationVar(&s.NetperfInterval, "netperfInterval", time.Minute, "Period between two queries of the agents of the NetworkTopologies whose cost source is NetperfAgent. 0 disables these queries.")
+	pflag.DurationVar(&s.NetperfTimeout, "netperfTimeout", 5*time.Second, "Timeout of a query of a netperf agent.")
+	pflag.IntVar(&s.NetperfWorkers, "netperfWorkers", 8, "Number of the netperf agents queried in parallel.")
+	pflag.DurationVar(&s.PrometheusCostInterval, "prometheusCostInterval", time.Minute, "Period between two queries of the latencies of the NetworkTopologies whose cost source is Prometheus. 0 disables these queries.")
+	pflag.DurationVar(&s.PrometheusCostTimeout, "prometheusCostTimeout", 30*time.Second, "Timeout of a query of the latencies of a NetworkTopology.")
+	pflag.DurationVar(&s.NetworkLatencyInterval, "networkLatencyInterval", time.Minute, "Period between two averages of the NetworkLatencies of the NetworkTopologies whose cost source is NetworkLatency. 0 disables these averages.")
+	pflag.StringVar(&s.BootstrapNetworkTopology, "bootstrapNetworkTopology", s.BootstrapNetworkTopology, "Namespace/name of a NetworkTopology created at startup, if missing, with default costs between the zones and regions of the nodes. Disabled if empty.")
+	pflag.StringVar(&s.BootstrapWeightsName, "bootstrapWeightsName", "UserDefined", "Name of the weights of --bootstrapNetworkTopology holding the default costs, also the weights calculated again every weight calculation period.")
+	pflag.Int64Var(&s.SameZoneCost, "sameZoneCost", 1, "Default network cost within a zone.")
+	pflag.Int64Var(&s.CrossZoneCost, "crossZoneCost", 5, "Default network cost between two zones of a region.")
+	pflag.Int64Var(&s.CrossRegionCost, "crossRegionCost", 50, "Default network cost between two regions, also the default cost of the zones and regions added later.")
+	pflag.IntVar(&s.NetworkCostWorkers, "networkCostWorkers", 4, "Number of the origins of a NetworkTopology whose costs are computed in parallel.")
+	pflag.DurationVar(&s.WeightCalculationPeriod, "weightCalculationPeriod", 0, "Default period between two calculations of the weights of the NetworkTopologies not setting spec.weightCalculationPeriod. 0 disables their calculation.")
+	pflag.BoolVar(&s.TopologyIgnoreNotReadyNodes, "topologyIgnoreNotReadyNodes", s.TopologyIgnoreNotReadyNodes, "If the zones and regions of the NotReady nodes are left out of the default network costs.")
+	pflag.BoolVar(&s.TopologyIgnoreUnschedulableNodes, "topologyIgnoreUnschedulableNodes", s.TopologyIgnoreUnschedulableNodes, "If the zones and regions of the cordoned nodes are left out of the default network costs.")
+	pflag.StringSliceVar(&s.TopologyIgnoredTaints, "topologyIgnoredTaints", s.TopologyIgnoredTaints, "Taint keys of the nodes whose zones and regions are left out of the default network costs.")
+	pflag.StringVar(&s.TopologyNodeSelector, "topologyNodeSelector", s.TopologyNodeSelector, "Label selector of the nodes counted in the NetworkTopologies, e.g. '!node-role.kubernetes.io/control-plane' to leave out the control-plane nodes. All nodes are counted if empty.")
+	pflag.BoolVar(&s.MachinePoolQuotas, "machinePoolQuotas", s.MachinePoolQuotas, "If the max of the ElasticQuotas selecting Cluster API machine pools follows the capacity of the pools.")
+	pflag.StringVar(&s.MachinePoolKubeConfig, "machinePoolKubeConfig", s.MachinePoolKubeConfig, "Kube Config path of the Cluster API management cluster holding the machine pools. Defaults to the cluster of the controller.")
+	pflag.StringSliceVar(&s.MachinePoolResources, "machinePoolResources", []string{"machinedeployments.v1beta1.cluster.x-k8s.io", "machinepools.v1beta1.cluster.x-k8s.io"}, "Resources, as resource.version.group, of the machine pools of --machinePoolQuotas.")
+	pflag.BoolVar(&s.SparkApplications, "sparkApplications", s.SparkApplications, "If every SparkApplication of the spark-operator gets an AppGroup, whose executors depend on the driver, and a PodGroup gathering its executors.")
 }
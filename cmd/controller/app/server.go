@@ -18,10 +18,17 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
 	"os"
 
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -29,11 +36,16 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/component-base/metrics/legacyregistry"
+	// Registers the adds, depth, retries and latency metrics of the named controller workqueues.
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/scheduler-plugins/pkg/controller"
 	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
 	schedformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
 
 func newConfig(kubeconfig, master string, inCluster bool) (*restclient.Config, error) {
@@ -59,8 +71,7 @@ func Run(s *ServerRunOptions) error {
 		klog.ErrorS(err, "Failed to parse config")
 		os.Exit(1)
 	}
-	config.QPS = float32(s.ApiServerQPS)
-	config.Burst = s.ApiServerBurst
+	config = util.NewClientConfig(config, "controller", int32(s.ApiServerQPS), int32(s.ApiServerBurst))
 	stopCh := server.SetupSignalHandler()
 	schedClient := schedclientset.NewForConfigOrDie(config)
 	kubeClient := kubernetes.NewForConfigOrDie(config)
@@ -69,21 +80,191 @@ func Run(s *ServerRunOptions) error {
 	pgInformer := schedInformerFactory.Scheduling().V1alpha1().PodGroups()
 	eqInformer := schedInformerFactory.Scheduling().V1alpha1().ElasticQuotas()
 	agInformer := schedInformerFactory.Scheduling().V1alpha1().AppGroups()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	nbpInformer := schedInformerFactory.Scheduling().V1alpha1().NodeBandwidthProfiles()
+	lhInformer := schedInformerFactory.Scheduling().V1alpha1().LinkHotspots()
+	nlInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkLatencies()
 
 	coreInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
 	podInformer := coreInformerFactory.Core().V1().Pods()
-	pgCtrl := controller.NewPodGroupController(kubeClient, pgInformer, podInformer, schedClient)
+	jobInformer := coreInformerFactory.Batch().V1().Jobs()
+	nodeInformer := coreInformerFactory.Core().V1().Nodes()
+	rsInformer := coreInformerFactory.Apps().V1().ReplicaSets()
+	pgCtrl := controller.NewPodGroupController(kubeClient, pgInformer, podInformer, jobInformer, schedClient)
 	eqCtrl := controller.NewElasticQuotaController(kubeClient, eqInformer, podInformer, schedClient)
-	agCtrl := controller.NewAppGroupController(kubeClient, agInformer, podInformer, schedClient)
+	agCtrl := controller.NewAppGroupController(kubeClient, agInformer, podInformer, ntInformer, schedClient,
+		controller.CostSmoothing{Alpha: s.NetworkCostSmoothingAlpha, MinChange: s.NetworkCostMinChange})
+	nbCtrl := controller.NewNodeBandwidthController(kubeClient, nbpInformer, nodeInformer)
+	agmCtrl := controller.NewAppGroupMembershipController(kubeClient, agInformer, rsInformer, jobInformer, podInformer)
+	lhCtrl := controller.NewLinkHotspotController(schedClient, ntInformer, lhInformer, s.LinkHotspotThreshold)
+	weightsOptions := weights.Options{
+		WeightsName:     s.BootstrapWeightsName,
+		SameZoneCost:    s.SameZoneCost,
+		CrossZoneCost:   s.CrossZoneCost,
+		CrossRegionCost: s.CrossRegionCost,
+		Workers:         s.NetworkCostWorkers,
+	}
+	nodeSelector, err := labels.Parse(s.TopologyNodeSelector)
+	if err != nil {
+		return fmt.Errorf("invalid --topologyNodeSelector: %w", err)
+	}
+	nodeFilter := util.NodeFilter{
+		IgnoreNotReady:      s.TopologyIgnoreNotReadyNodes,
+		IgnoreUnschedulable: s.TopologyIgnoreUnschedulableNodes,
+		IgnoredTaints:       s.TopologyIgnoredTaints,
+		Selector:            nodeSelector,
+	}
+	ntwCtrl := controller.NewNetworkTopologyWeightsController(schedClient, ntInformer, nodeInformer, controller.NetworkTopologyWeightsOptions{
+		Options:       weightsOptions,
+		NodeFilter:    nodeFilter,
+		DefaultPeriod: s.WeightCalculationPeriod,
+	})
+	var ntbCtrl *controller.NetworkTopologyBandwidthController
+	if s.BandwidthInterval > 0 {
+		ntbCtrl = controller.NewNetworkTopologyBandwidthController(schedClient, ntInformer, agInformer, podInformer, nodeInformer, s.BandwidthInterval,
+			s.BandwidthAllocationTTL)
+	}
+	var mcCtrl *controller.MeshCostController
+	if len(s.MeshPrometheusAddress) != 0 {
+		mcCtrl, err = controller.NewMeshCostController(schedClient, ntInformer, controller.MeshCostOptions{
+			PrometheusAddress: s.MeshPrometheusAddress,
+			Provider:          controller.MeshProvider(s.MeshProvider),
+			Quantile:          s.MeshLatencyQuantile,
+			NetworkTopology:   s.MeshNetworkTopology,
+			WeightsName:       s.MeshWeightsName,
+			Interval:          s.MeshCostInterval,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var trCtrl *controller.TrafficController
+	if len(s.TrafficPrometheusAddress) != 0 {
+		trCtrl, err = controller.NewTrafficController(schedClient, agInformer, controller.TrafficOptions{
+			PrometheusAddress: s.TrafficPrometheusAddress,
+			Query:             s.TrafficQuery,
+			Alpha:             s.TrafficSmoothingAlpha,
+			HeadroomPercent:   s.TrafficHeadroomPercent,
+			AutoTune:          s.TrafficAutoTune,
+			Interval:          s.TrafficInterval,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var npCtrl *controller.NetperfCostController
+	if s.NetperfInterval > 0 {
+		npCtrl = controller.NewNetperfCostController(schedClient, ntInformer, podInformer, nodeInformer, controller.NetperfCostOptions{
+			Interval: s.NetperfInterval,
+			Timeout:  s.NetperfTimeout,
+			Workers:  s.NetperfWorkers,
+		})
+	}
+
+	var pcCtrl *controller.PrometheusCostController
+	if s.PrometheusCostInterval > 0 {
+		pcCtrl = controller.NewPrometheusCostController(schedClient, ntInformer, nodeInformer, controller.PrometheusCostOptions{
+			Interval: s.PrometheusCostInterval,
+			Timeout:  s.PrometheusCostTimeout,
+		})
+	}
+
+	var nlCtrl *controller.NetworkLatencyCostController
+	if s.NetworkLatencyInterval > 0 {
+		nlCtrl = controller.NewNetworkLatencyCostController(schedClient, ntInformer, nlInformer, nodeInformer, s.NetworkLatencyInterval)
+	}
+
+	var mpqCtrl *controller.MachinePoolQuotaController
+	var poolInformerFactory dynamicinformer.DynamicSharedInformerFactory
+	if s.MachinePoolQuotas {
+		poolConfig := config
+		if len(s.MachinePoolKubeConfig) != 0 {
+			if poolConfig, err = newConfig(s.MachinePoolKubeConfig, "", false); err != nil {
+				return err
+			}
+			poolConfig = util.NewClientConfig(poolConfig, "controller", int32(s.ApiServerQPS), int32(s.ApiServerBurst))
+		}
+		var resources []schema.GroupVersionResource
+		for _, arg := range s.MachinePoolResources {
+			gvr, _ := schema.ParseResourceArg(arg)
+			if gvr == nil {
+				return fmt.Errorf("invalid machine pool resource %q, want resource.version.group", arg)
+			}
+			resources = append(resources, *gvr)
+		}
+		poolInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(dynamic.NewForConfigOrDie(poolConfig), 0)
+		mpqCtrl = controller.NewMachinePoolQuotaController(schedClient, eqInformer, poolInformerFactory, resources)
+	}
+
+	var saCtrl *controller.SparkApplicationController
+	var sparkInformerFactory dynamicinformer.DynamicSharedInformerFactory
+	if s.SparkApplications {
+		dynamicClient := dynamic.NewForConfigOrDie(config)
+		sparkInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+		saCtrl = controller.NewSparkApplicationController(schedClient, dynamicClient, agInformer, pgInformer, sparkInformerFactory)
+	}
+
+	if len(s.DebugBindAddress) != 0 {
+		go serveDebug(s.DebugBindAddress, agCtrl, kubeClient, s.DebugAuthorization)
+	}
+	if len(s.MetricsBindAddress) != 0 {
+		legacyregistry.CustomMustRegister(controller.NewMetricsCollector(pgInformer, eqInformer, agInformer, ntInformer))
+		go serveMetrics(s.MetricsBindAddress, s.EnableProfiling)
+	}
 
 	run := func(ctx context.Context) {
+		if len(s.BootstrapNetworkTopology) != 0 {
+			if err := controller.BootstrapNetworkTopology(ctx, kubeClient, schedClient, controller.TopologyBootstrapOptions{
+				NetworkTopology: s.BootstrapNetworkTopology,
+				Options:         weightsOptions,
+				NodeFilter:      nodeFilter,
+			}); err != nil {
+				klog.ErrorS(err, "Failed to bootstrap NetworkTopology", "networkTopology", s.BootstrapNetworkTopology)
+			}
+		}
 		go pgCtrl.Run(s.Workers, ctx.Done())
 		go eqCtrl.Run(s.Workers, ctx.Done())
 		go agCtrl.Run(s.Workers, ctx.Done())
+		go nbCtrl.Run(s.Workers, ctx.Done())
+		go agmCtrl.Run(s.Workers, ctx.Done())
+		go lhCtrl.Run(s.Workers, ctx.Done())
+		go ntwCtrl.Run(ctx.Done())
+		if ntbCtrl != nil {
+			go ntbCtrl.Run(ctx.Done())
+		}
+		if mcCtrl != nil {
+			go mcCtrl.Run(ctx.Done())
+		}
+		if trCtrl != nil {
+			go trCtrl.Run(ctx.Done())
+		}
+		if npCtrl != nil {
+			go npCtrl.Run(ctx.Done())
+		}
+		if pcCtrl != nil {
+			go pcCtrl.Run(ctx.Done())
+		}
+		if nlCtrl != nil {
+			go nlCtrl.Run(ctx.Done())
+		}
+		if mpqCtrl != nil {
+			go mpqCtrl.Run(s.Workers, ctx.Done())
+		}
+		if saCtrl != nil {
+			go saCtrl.Run(s.Workers, ctx.Done())
+		}
 		select {}
 	}
 	schedInformerFactory.Start(stopCh)
 	coreInformerFactory.Start(stopCh)
+	if poolInformerFactory != nil {
+		poolInformerFactory.Start(stopCh)
+	}
+	if sparkInformerFactory != nil {
+		sparkInformerFactory.Start(stopCh)
+	}
 	if !s.EnableLeaderElection {
 		run(ctx)
 	} else {
@@ -123,3 +304,33 @@ func Run(s *ServerRunOptions) error {
 	<-stopCh
 	return nil
 }
+
+func serveDebug(address string, agCtrl *controller.AppGroupController, client kubernetes.Interface, authorization bool) {
+	handler := agCtrl.ShadowCompareHandler()
+	if authorization {
+		handler = controller.WithNamespaceAuthorization(client, handler)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(controller.ShadowComparePath, handler)
+	klog.InfoS("Serving shadow NetworkTopology comparison", "address", address, "path", controller.ShadowComparePath)
+	if err := http.ListenAndServe(address, mux); err != nil {
+		klog.ErrorS(err, "Failed to serve shadow NetworkTopology comparison", "address", address)
+	}
+}
+
+func serveMetrics(address string, profiling bool) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", legacyregistry.Handler())
+	if profiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		klog.InfoS("Serving controller profiles", "address", address, "path", "/debug/pprof/")
+	}
+	klog.InfoS("Serving controller metrics", "address", address, "path", "/metrics")
+	if err := http.ListenAndServe(address, mux); err != nil {
+		klog.ErrorS(err, "Failed to serve controller metrics", "address", address)
+	}
+}
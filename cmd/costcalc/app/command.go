@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// NewCommand returns the command computing the network costs of the placements of the workloads of an
+// AppGroup across the zones of a set of nodes, writing a text report to out.
+func NewCommand(out io.Writer) *cobra.Command {
+	opts := Options{}
+	var nodesFile, appGroupFile, networkTopologyFile, latencyFile string
+	var replicas map[string]int64
+
+	cmd := &cobra.Command{
+		Use:   "costcalc",
+		Short: "Compute the network costs of the placements of the workloads of an AppGroup across zones",
+		Long: `Compute the network costs of the placements of the workloads of an AppGroup across zones.
+
+The costs between the zones of the nodes are computed by the same cost model as the controller
+bootstrapping NetworkTopologies: the costs of the cost source are kept, the pairs they connect get
+the cost of their cheapest path, and the other pairs the default costs. The report lists the cost of
+one more replica of every workload in every zone, then the replicas per zone the AppGroup controller
+would recommend, placed greedily, and their costs. No API server is needed, e.g. to review an
+architecture before deploying the plugins.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if nodesFile == "" || appGroupFile == "" {
+				return fmt.Errorf("--nodes and --app-group are required")
+			}
+			if networkTopologyFile != "" && latencyFile != "" {
+				return fmt.Errorf("--network-topology and --latency-csv are exclusive")
+			}
+
+			var nodes []v1.Node
+			if err := readFile(nodesFile, func(r io.Reader) (err error) {
+				nodes, err = ReadNodes(r)
+				return err
+			}); err != nil {
+				return err
+			}
+			var ag *v1alpha1.AppGroup
+			if err := readFile(appGroupFile, func(r io.Reader) (err error) {
+				ag, err = ReadAppGroup(r)
+				return err
+			}); err != nil {
+				return err
+			}
+			var costs v1alpha1.TopologyList
+			if networkTopologyFile != "" {
+				if err := readFile(networkTopologyFile, func(r io.Reader) (err error) {
+					costs, err = ReadNetworkTopologyCosts(r, opts.Weights.WeightsName)
+					return err
+				}); err != nil {
+					return err
+				}
+			}
+			if latencyFile != "" {
+				if err := readFile(latencyFile, func(r io.Reader) (err error) {
+					costs, err = ReadLatencyCSV(r)
+					return err
+				}); err != nil {
+					return err
+				}
+			}
+
+			opts.Replicas = make(map[string]int32, len(replicas))
+			for selector, r := range replicas {
+				opts.Replicas[selector] = int32(r)
+			}
+			return WriteReport(out, ag, Plan(nodes, costs, ag, opts))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&nodesFile, "nodes", "", "A YAML or JSON file holding the nodes, e.g. the output of kubectl get nodes -o yaml.")
+	flags.StringVar(&appGroupFile, "app-group", "", "A YAML or JSON file holding the AppGroup.")
+	flags.StringVar(&networkTopologyFile, "network-topology", "", "A YAML or JSON file holding a NetworkTopology, "+
+		"whose weights named --weights-name are the cost source.")
+	flags.StringVar(&latencyFile, "latency-csv", "", "A CSV file of origin zone, destination zone and latency in milliseconds records, "+
+		"the cost source.")
+	flags.StringVar(&opts.Weights.WeightsName, "weights-name", "UserDefined", "The name of the weights of the NetworkTopology.")
+	flags.Int64Var(&opts.Weights.SameZoneCost, "same-zone-cost", 1, "Default network cost within a zone.")
+	flags.Int64Var(&opts.Weights.CrossZoneCost, "cross-zone-cost", 5, "Default network cost between two zones of a region.")
+	flags.Int64Var(&opts.Weights.CrossRegionCost, "cross-region-cost", 50, "Default network cost between two regions.")
+	flags.StringToInt64Var(&replicas, "replicas", nil, "The number of replicas of the workloads, by selector, e.g. frontend=3,backend=6.")
+	flags.Int32Var(&opts.DefaultReplicas, "default-replicas", 1, "The number of replicas of the workloads missing from --replicas.")
+	flags.Int32Var(&opts.MaxSkew, "max-skew", 0, "The maximum skew of the workloads across zones, the one of the zone distribution "+
+		"of the AppGroup if 0, else 1.")
+	return cmd
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/controller"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+)
+
+// Options configures the placement computed.
+type Options struct {
+	// Weights configures the costs of the pairs of zones and regions missing from the cost source.
+	Weights weights.Options
+	// Replicas are the replicas of the workloads, keyed by selector, DefaultReplicas if missing.
+	Replicas        map[string]int32
+	DefaultReplicas int32
+	// MaxSkew is the maximum skew of the workloads, the one of the zone distribution of the AppGroup if 0.
+	MaxSkew int32
+}
+
+// ReadNodes reads the nodes of a YAML or JSON file holding a NodeList, e.g. the output of
+// `kubectl get nodes -o yaml`, or Node documents.
+func ReadNodes(r io.Reader) ([]v1.Node, error) {
+	var nodes []v1.Node
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return nodes, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 || string(doc) == "null" {
+			continue
+		}
+		var list struct {
+			Kind  string    `json:"kind"`
+			Items []v1.Node `json:"items"`
+		}
+		if err := json.Unmarshal(doc, &list); err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(list.Kind, "List") {
+			nodes = append(nodes, list.Items...)
+			continue
+		}
+		var node v1.Node
+		if err := json.Unmarshal(doc, &node); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+// ReadAppGroup reads an AppGroup from a YAML or JSON file.
+func ReadAppGroup(r io.Reader) (*v1alpha1.AppGroup, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ag := &v1alpha1.AppGroup{}
+	if err := yaml.UnmarshalStrict(data, ag); err != nil {
+		return nil, err
+	}
+	if len(ag.Spec.Workloads) == 0 {
+		return nil, fmt.Errorf("AppGroup %s has no workload", ag.Name)
+	}
+	return ag, nil
+}
+
+// ReadNetworkTopologyCosts reads the costs of the weights weightsName of a NetworkTopology from a YAML or
+// JSON file, e.g. the output of `kubectl get networktopology -o yaml`.
+func ReadNetworkTopologyCosts(r io.Reader, weightsName string) (v1alpha1.TopologyList, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	nt := &v1alpha1.NetworkTopology{}
+	if err := yaml.Unmarshal(data, nt); err != nil {
+		return nil, err
+	}
+	for _, w := range nt.Spec.Weights {
+		if w.Name == weightsName {
+			return w.TopologyList, nil
+		}
+	}
+	return nil, fmt.Errorf("NetworkTopology %s has no weights %s", nt.Name, weightsName)
+}
+
+// ReadLatencyCSV reads the latencies between zones from CSV records of an origin zone, a destination zone and
+// a latency in milliseconds, rounded. A first record whose latency is not a number is taken for a header.
+func ReadLatencyCSV(r io.Reader) (v1alpha1.TopologyList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	destinations := make(map[string]v1alpha1.CostList)
+	for i, record := range records {
+		latency, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid latency %q", i+1, record[2])
+		}
+		if latency < 0 {
+			return nil, fmt.Errorf("line %d: negative latency %v", i+1, latency)
+		}
+		destinations[record[0]] = append(destinations[record[0]], v1alpha1.CostInfo{
+			Destination: record[1],
+			NetworkCost: int64(math.Round(latency)),
+		})
+	}
+	origins := make([]string, 0, len(destinations))
+	for origin := range destinations {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	topology := v1alpha1.TopologyInfo{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds}
+	for _, origin := range origins {
+		topology.OriginList = append(topology.OriginList, v1alpha1.OriginInfo{Origin: origin, CostList: destinations[origin]})
+	}
+	return v1alpha1.TopologyList{topology}, nil
+}
+
+// Plan returns the placement of the workloads of ag across the zones of nodes, the costs between them computed
+// from costs and the defaults of options by the weights library, like the controller bootstraps NetworkTopologies.
+// The topologies of costs for an interface class are kept as is.
+func Plan(nodes []v1.Node, costs v1alpha1.TopologyList, ag *v1alpha1.AppGroup, options Options) *controller.ZonePlacementPlan {
+	var defaultCosts, classCosts v1alpha1.TopologyList
+	for _, t := range costs {
+		if t.InterfaceClass == "" {
+			defaultCosts = append(defaultCosts, t)
+		} else {
+			classCosts = append(classCosts, t)
+		}
+	}
+	nt := &v1alpha1.NetworkTopology{Spec: v1alpha1.NetworkTopologySpec{
+		Weights: weights.ComputeWeights(nodes, defaultCosts, options.Weights),
+	}}
+	nt.Spec.Weights[0].TopologyList = append(nt.Spec.Weights[0].TopologyList, classCosts...)
+
+	replicas := make(map[string]int32, len(ag.Spec.Workloads))
+	for _, w := range ag.Spec.Workloads {
+		replicas[w.Workload.Selector] = options.DefaultReplicas
+		if r, ok := options.Replicas[w.Workload.Selector]; ok {
+			replicas[w.Workload.Selector] = r
+		}
+	}
+	maxSkew := options.MaxSkew
+	if maxSkew == 0 && ag.Spec.ZoneDistribution != nil {
+		maxSkew = ag.Spec.ZoneDistribution.MaxSkew
+	}
+	return controller.PlanZonePlacement(ag, nt, options.Weights.WeightsName, replicas, maxSkew)
+}
+
+// WriteReport writes plan as text: the cost of one more replica of every workload per candidate zone, then the
+// recommended replicas per zone and the resulting costs.
+func WriteReport(out io.Writer, ag *v1alpha1.AppGroup, plan *controller.ZonePlacementPlan) error {
+	if len(plan.Zones) == 0 {
+		_, err := fmt.Fprintln(out, "No zone: the nodes have no topology.kubernetes.io/zone label.")
+		return err
+	}
+	selectors := make([]string, 0, len(ag.Spec.Workloads))
+	for _, w := range ag.Spec.Workloads {
+		selectors = append(selectors, w.Workload.Selector)
+	}
+	sort.Strings(selectors)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "AppGroup:   %s\n", ag.Name)
+	fmt.Fprintf(&b, "Zones:      %s\n\n", strings.Join(plan.Zones, ", "))
+	fmt.Fprintf(&b, "Cost of one more replica per candidate zone:\n")
+	fmt.Fprintf(&b, "  %-20s", "WORKLOAD")
+	for _, zone := range plan.Zones {
+		fmt.Fprintf(&b, " %12s", zone)
+	}
+	fmt.Fprintln(&b)
+	for _, selector := range selectors {
+		fmt.Fprintf(&b, "  %-20s", selector)
+		for _, zone := range plan.Zones {
+			fmt.Fprintf(&b, " %12d", plan.CandidateCosts[selector][zone])
+		}
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintf(&b, "\nRecommended assignment:\n")
+	var total int64
+	for _, r := range plan.Recommendations {
+		var zones []string
+		for _, z := range r.Zones {
+			zones = append(zones, fmt.Sprintf("%s=%d", z.Zone, z.Replicas))
+		}
+		if len(zones) == 0 {
+			zones = []string{"-"}
+		}
+		cost := plan.Costs[r.Workload.Selector]
+		total += cost
+		fmt.Fprintf(&b, "  %-20s %-40s cost %d\n", r.Workload.Selector, strings.Join(zones, " "), cost)
+	}
+	// Every dependency is counted from both of its workloads.
+	fmt.Fprintf(&b, "\nTotal cost: %d\n", total/2)
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// readFile opens path and passes it to read.
+func readFile(path string, read func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := read(f); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+)
+
+func readTestdata(t *testing.T, name string, read func(io.Reader) error) {
+	t.Helper()
+	if err := readFile(filepath.Join("testdata", name), read); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadNodes(t *testing.T) {
+	var nodes []v1.Node
+	readTestdata(t, "nodes.yaml", func(r io.Reader) (err error) {
+		nodes, err = ReadNodes(r)
+		return err
+	})
+	var got []string
+	for _, n := range nodes {
+		got = append(got, n.Name+"/"+n.Labels[v1.LabelTopologyZone])
+	}
+	if want := []string{"node-1/z1", "node-2/z2", "node-3/z3"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want the nodes of the list and of the documents %v, got %v", want, got)
+	}
+}
+
+func TestReadLatencyCSV(t *testing.T) {
+	var costs v1alpha1.TopologyList
+	readTestdata(t, "latency.csv", func(r io.Reader) (err error) {
+		costs, err = ReadLatencyCSV(r)
+		return err
+	})
+	want := v1alpha1.TopologyList{{
+		TopologyKey: v1alpha1.NetworkTopologyZone,
+		CostUnit:    v1alpha1.NetworkCostUnitMilliseconds,
+		OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 2}, {Destination: "z3", NetworkCost: 20}}},
+			{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z1", NetworkCost: 2}, {Destination: "z3", NetworkCost: 15}}},
+			{Origin: "z3", CostList: v1alpha1.CostList{{Destination: "z1", NetworkCost: 20}, {Destination: "z2", NetworkCost: 15}}},
+		},
+	}}
+	if !reflect.DeepEqual(want, costs) {
+		t.Errorf("want %v, got %v", want, costs)
+	}
+
+	if _, err := ReadLatencyCSV(strings.NewReader("z1,z2,2\nz2,z1,fast\n")); err == nil {
+		t.Errorf("want an error for a latency not a number past the header")
+	}
+	if _, err := ReadLatencyCSV(strings.NewReader("z1,z2,-2\n")); err == nil {
+		t.Errorf("want an error for a negative latency")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	var nodes []v1.Node
+	readTestdata(t, "nodes.yaml", func(r io.Reader) (err error) {
+		nodes, err = ReadNodes(r)
+		return err
+	})
+	var ag *v1alpha1.AppGroup
+	readTestdata(t, "appgroup.yaml", func(r io.Reader) (err error) {
+		ag, err = ReadAppGroup(r)
+		return err
+	})
+	var costs v1alpha1.TopologyList
+	readTestdata(t, "networktopology.yaml", func(r io.Reader) (err error) {
+		costs, err = ReadNetworkTopologyCosts(r, "UserDefined")
+		return err
+	})
+	options := Options{
+		Weights:         weights.Options{WeightsName: "UserDefined", SameZoneCost: 1, CrossZoneCost: 5, CrossRegionCost: 50},
+		DefaultReplicas: 1,
+	}
+
+	plan := Plan(nodes, costs, ag, options)
+	// z3 is one away from z1 in the NetworkTopology, z2 gets the default cross zone cost.
+	want := map[string]int64{"z1": 0, "z2": 5, "z3": 1}
+	if got := plan.CandidateCosts["frontend"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("want the candidate costs %v of frontend, got %v", want, got)
+	}
+	for _, r := range plan.Recommendations {
+		if want := (v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}}); !reflect.DeepEqual(want, r.Zones) {
+			t.Errorf("want %s colocated in z1, got %v", r.Workload.Selector, r.Zones)
+		}
+	}
+
+	if _, err := ReadNetworkTopologyCosts(strings.NewReader("metadata:\n  name: nt\n"), "UserDefined"); err == nil {
+		t.Errorf("want an error for missing weights")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := NewCommand(out)
+	cmd.SetArgs([]string{
+		"--nodes", filepath.Join("testdata", "nodes.yaml"),
+		"--app-group", filepath.Join("testdata", "appgroup.yaml"),
+		"--latency-csv", filepath.Join("testdata", "latency.csv"),
+		"--replicas", "frontend=2,backend=2",
+		"--max-skew", "1",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spread over the two closest zones, each replica is 2ms away from the one of the other workload in the other zone.
+	rows := make(map[string][]string)
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) != 0 {
+			rows[fields[0]] = append(rows[fields[0]], strings.Join(fields[1:], " "))
+		}
+	}
+	want := map[string][]string{
+		"frontend": {"2 2 35", "z1=1 z2=1 cost 4"},
+		"backend":  {"2 2 35", "z1=1 z2=1 cost 4"},
+	}
+	for selector, lines := range want {
+		if !reflect.DeepEqual(lines, rows[selector]) {
+			t.Errorf("want the rows %q of %s, got %q", lines, selector, rows[selector])
+		}
+	}
+	if !strings.Contains(out.String(), "Total cost: 4\n") {
+		t.Errorf("want a total cost of 4, got\n%s", out.String())
+	}
+
+	cmd = NewCommand(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--nodes", filepath.Join("testdata", "nodes.yaml")})
+	cmd.SetErr(ioutil.Discard)
+	if err := cmd.Execute(); err == nil {
+		t.Errorf("want an error without AppGroup")
+	}
+}
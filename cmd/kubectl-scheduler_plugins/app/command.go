@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// NewCommand returns the root command of the kubectl plugin, run as `kubectl scheduler-plugins`.
+func NewCommand(out io.Writer) *cobra.Command {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	root := &cobra.Command{
+		Use:          "kubectl scheduler-plugins",
+		Short:        "Inspect the objects of the scheduler-plugins",
+		SilenceUsage: true,
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file.")
+	flags.StringVar(&overrides.CurrentContext, "context", "", "The name of the kubeconfig context to use.")
+	flags.StringVarP(&overrides.Context.Namespace, "namespace", "n", "", "The namespace of the object.")
+
+	gang := &cobra.Command{
+		Use:   "gang",
+		Short: "Inspect the gangs scheduled by Coscheduling",
+	}
+	gang.AddCommand(newGangStatusCommand(clientConfig, out))
+	root.AddCommand(gang)
+	return root
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// coschedulingName is the name of the Coscheduling plugin in the plugins snapshots.
+const coschedulingName = "Coscheduling"
+
+func newGangStatusCommand(clientConfig clientcmd.ClientConfig, out io.Writer) *cobra.Command {
+	var snapshotSource string
+	cmd := &cobra.Command{
+		Use:   "status <podgroup>",
+		Short: "Explain why the gang of a PodGroup is not scheduled",
+		Long: "Correlate the status of a PodGroup, the conditions of its pending pods, the ElasticQuota of its namespace\n" +
+			"and, given a plugins snapshot of the scheduler, the pods waiting at Permit, to print why the gang is stuck.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := clientConfig.ClientConfig()
+			if err != nil {
+				return err
+			}
+			namespace, _, err := clientConfig.Namespace()
+			if err != nil {
+				return err
+			}
+			var gangs *gangSnapshot
+			if snapshotSource != "" {
+				if gangs, err = loadGangSnapshot(snapshotSource); err != nil {
+					return err
+				}
+			}
+			status, err := diagnoseGang(cmd.Context(), kubernetes.NewForConfigOrDie(config),
+				schedclientset.NewForConfigOrDie(config), namespace, args[0], gangs)
+			if err != nil {
+				return err
+			}
+			status.Print(out)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&snapshotSource, "plugins-snapshot", "",
+		"URL or file of a plugins snapshot of the scheduler, served at "+debug.SnapshotPath+
+			" with --plugins-debug-bind-address, to account for the pods waiting at Permit.")
+	return cmd
+}
+
+// gangSnapshot is the state of the gangs reported by the Coscheduling plugin in a plugins snapshot.
+type gangSnapshot struct {
+	// WaitingPods maps a PodGroup full name to the pods waiting at Permit.
+	WaitingPods     map[string][]string `json:"waitingPods"`
+	DeniedPodGroups []string            `json:"deniedPodGroups"`
+}
+
+// loadGangSnapshot reads the Coscheduling state of a plugins snapshot, from a URL or a file, merging
+// the instances of all the profiles.
+func loadGangSnapshot(source string) (*gangSnapshot, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		var resp *http.Response
+		if resp, err = http.Get(source); err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching plugins snapshot: %s", resp.Status)
+		}
+		data, err = ioutil.ReadAll(resp.Body)
+	} else {
+		data, err = ioutil.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot struct {
+		Plugins map[string][]gangSnapshot `json:"plugins"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decoding plugins snapshot: %w", err)
+	}
+	instances, ok := snapshot.Plugins[coschedulingName]
+	if !ok {
+		return nil, fmt.Errorf("plugins snapshot holds no %s state", coschedulingName)
+	}
+	gangs := &gangSnapshot{WaitingPods: make(map[string][]string)}
+	for _, s := range instances {
+		for pg, pods := range s.WaitingPods {
+			gangs.WaitingPods[pg] = append(gangs.WaitingPods[pg], pods...)
+		}
+		gangs.DeniedPodGroups = append(gangs.DeniedPodGroups, s.DeniedPodGroups...)
+	}
+	return gangs, nil
+}
+
+// gangStatus is the diagnosis of a gang.
+type gangStatus struct {
+	PodGroup  string
+	Phase     v1alpha1.PodGroupPhase
+	MinMember int32
+	// Pods is the number of live pods of the gang, Bound of them having a node.
+	Pods  int
+	Bound int
+	// WaitingAtPermit is nil if no plugins snapshot was given.
+	WaitingAtPermit *int
+	// Quotas describes the usage of the ElasticQuotas of the namespace.
+	Quotas []string
+	// Reasons are why the gang is not scheduled, most actionable first.
+	Reasons []string
+}
+
+// diagnoseGang returns why the gang of the PodGroup namespace/name is not scheduled. gangs is the
+// Coscheduling state of a plugins snapshot, nil if unknown.
+func diagnoseGang(ctx context.Context, kubeClient kubernetes.Interface, schedClient schedclientset.Interface,
+	namespace, name string, gangs *gangSnapshot) (*gangStatus, error) {
+	pg, err := schedClient.SchedulingV1alpha1().PodGroups(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.Set{v1alpha1.PodGroupLabel: name}.AsSelector().String()
+	podList, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	eqList, err := schedClient.SchedulingV1alpha1().ElasticQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &gangStatus{
+		PodGroup:  namespace + "/" + name,
+		Phase:     pg.Status.Phase,
+		MinMember: pg.Spec.MinMember,
+	}
+	if status.Phase == "" {
+		status.Phase = v1alpha1.PodGroupPending
+	}
+
+	pendingRequest := v1.ResourceList{}
+	unschedulable := make(map[string]int)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		status.Pods++
+		if pod.Spec.NodeName != "" {
+			status.Bound++
+			continue
+		}
+		pendingRequest = quota.Add(pendingRequest, util.GetPodEffectiveRequest(pod))
+		for _, c := range pod.Status.Conditions {
+			if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse && c.Reason == v1.PodReasonUnschedulable {
+				unschedulable[c.Message]++
+			}
+		}
+	}
+
+	switch status.Phase {
+	case v1alpha1.PodGroupScheduled, v1alpha1.PodGroupRunning, v1alpha1.PodGroupFinished:
+		status.Reasons = append(status.Reasons, fmt.Sprintf("The gang is %s, it is not stuck.", status.Phase))
+		return status, nil
+	}
+
+	if missing := int(pg.Spec.MinMember) - status.Pods; missing > 0 {
+		reason := fmt.Sprintf("Waiting for %d more pod(s) to be created: %d of minMember %d exist.", missing, status.Pods, pg.Spec.MinMember)
+		cond := meta.FindStatusCondition(pg.Status.Conditions, v1alpha1.PodGroupMinMemberSatisfiable)
+		if cond != nil && cond.Status == metav1.ConditionFalse {
+			reason += fmt.Sprintf(" %s: %s", cond.Reason, cond.Message)
+		}
+		status.Reasons = append(status.Reasons, reason)
+	}
+
+	for i := range eqList.Items {
+		eq := &eqList.Items[i]
+		var exhausted []string
+		for _, res := range sortedResourceNames(eq.Spec.Max) {
+			max, used, pending := eq.Spec.Max[res], eq.Status.Used[res], pendingRequest[res]
+			status.Quotas = append(status.Quotas, fmt.Sprintf("ElasticQuota %s/%s: %s used %s, max %s",
+				eq.Namespace, eq.Name, res, used.String(), max.String()))
+			total := used.DeepCopy()
+			total.Add(pending)
+			if !pending.IsZero() && total.Cmp(max) > 0 {
+				exhausted = append(exhausted, fmt.Sprintf("%s used %s + pending %s exceeds max %s",
+					res, used.String(), pending.String(), max.String()))
+			}
+		}
+		if len(exhausted) != 0 {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("ElasticQuota %s/%s is exhausted: %s.",
+				eq.Namespace, eq.Name, strings.Join(exhausted, ", ")))
+		}
+	}
+
+	if gangs != nil {
+		for _, denied := range gangs.DeniedPodGroups {
+			if denied == status.PodGroup {
+				status.Reasons = append(status.Reasons, "The PodGroup was recently denied by Coscheduling, e.g. on Permit "+
+					"timeout; its pods are retried once the denial expires.")
+			}
+		}
+		waiting := len(gangs.WaitingPods[status.PodGroup])
+		status.WaitingAtPermit = &waiting
+		if more := int(pg.Spec.MinMember) - status.Bound - waiting; waiting > 0 && more > 0 {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("%d pod(s) waiting at Permit for %d more pod(s) of the gang to fit.",
+				waiting, more))
+		}
+	}
+
+	messages := make([]string, 0, len(unschedulable))
+	for m := range unschedulable {
+		messages = append(messages, m)
+	}
+	sort.Strings(messages)
+	for _, m := range messages {
+		status.Reasons = append(status.Reasons, fmt.Sprintf("%d pod(s) rejected by the scheduler: %s", unschedulable[m], m))
+	}
+
+	if len(status.Reasons) == 0 {
+		status.Reasons = append(status.Reasons, fmt.Sprintf("No blocking reason found, %d pod(s) are pending scheduling.",
+			status.Pods-status.Bound))
+	}
+	return status, nil
+}
+
+// Print writes the diagnosis in a human readable form.
+func (s *gangStatus) Print(out io.Writer) {
+	fmt.Fprintf(out, "PodGroup:   %s\n", s.PodGroup)
+	fmt.Fprintf(out, "Phase:      %s\n", s.Phase)
+	fmt.Fprintf(out, "MinMember:  %d\n", s.MinMember)
+	fmt.Fprintf(out, "Pods:       %d (%d bound, %d pending)\n", s.Pods, s.Bound, s.Pods-s.Bound)
+	if s.WaitingAtPermit != nil {
+		fmt.Fprintf(out, "Permit:     %d waiting\n", *s.WaitingAtPermit)
+	}
+	for _, q := range s.Quotas {
+		fmt.Fprintf(out, "Quota:      %s\n", q)
+	}
+	fmt.Fprintln(out, "Reasons:")
+	for _, r := range s.Reasons {
+		fmt.Fprintf(out, "  - %s\n", r)
+	}
+}
+
+func sortedResourceNames(list v1.ResourceList) []v1.ResourceName {
+	names := make([]v1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+)
+
+const snapshotJSON = `{
+  "timestamp": "2022-06-01T00:00:00Z",
+  "plugins": {
+    "Coscheduling": [
+      {"waitingPods": {"default/pg": ["default/p1"]}, "permittedPodGroups": [], "deniedPodGroups": []},
+      {"waitingPods": {}, "permittedPodGroups": [], "deniedPodGroups": ["default/other"]}
+    ]
+  }
+}`
+
+func TestDiagnoseGang(t *testing.T) {
+	makePod := func(name, nodeName, cpu, unschedulable string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{v1alpha1.PodGroupLabel: "pg"}},
+			Spec: v1.PodSpec{
+				NodeName: nodeName,
+				Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				}}},
+			},
+		}
+		if unschedulable != "" {
+			pod.Status.Conditions = []v1.PodCondition{{
+				Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable, Message: unschedulable,
+			}}
+		}
+		return pod
+	}
+	makePodGroup := func(minMember int32, phase v1alpha1.PodGroupPhase) *v1alpha1.PodGroup {
+		return &v1alpha1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"},
+			Spec:       v1alpha1.PodGroupSpec{MinMember: minMember},
+			Status:     v1alpha1.PodGroupStatus{Phase: phase},
+		}
+	}
+	eq := &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: "default"},
+		Spec:       v1alpha1.ElasticQuotaSpec{Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}},
+		Status:     v1alpha1.ElasticQuotaStatus{Used: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3")}},
+	}
+	gangs := &gangSnapshot{WaitingPods: map[string][]string{"default/pg": {"default/p1"}}}
+	insufficientCPU := "0/3 nodes are available: 3 Insufficient cpu."
+
+	tests := []struct {
+		name            string
+		pods            []runtime.Object
+		schedObjects    []runtime.Object
+		gangs           *gangSnapshot
+		expectedReasons []string
+	}{
+		{
+			name:            "gang running",
+			pods:            []runtime.Object{makePod("p1", "n1", "1", ""), makePod("p2", "n2", "1", "")},
+			schedObjects:    []runtime.Object{makePodGroup(2, v1alpha1.PodGroupRunning)},
+			expectedReasons: []string{"The gang is Running, it is not stuck."},
+		},
+		{
+			name:         "missing pods",
+			pods:         []runtime.Object{makePod("p1", "", "1", "")},
+			schedObjects: []runtime.Object{makePodGroup(3, v1alpha1.PodGroupPending)},
+			gangs:        gangs,
+			expectedReasons: []string{
+				"Waiting for 2 more pod(s) to be created: 1 of minMember 3 exist.",
+				"1 pod(s) waiting at Permit for 2 more pod(s) of the gang to fit.",
+			},
+		},
+		{
+			name: "quota exhausted and rejected by filters",
+			pods: []runtime.Object{
+				makePod("p1", "", "1", ""),
+				makePod("p2", "", "1", insufficientCPU),
+				makePod("p3", "", "1", insufficientCPU),
+			},
+			schedObjects: []runtime.Object{makePodGroup(3, v1alpha1.PodGroupPreScheduling), eq},
+			expectedReasons: []string{
+				"ElasticQuota default/eq is exhausted: cpu used 3 + pending 3 exceeds max 4.",
+				"2 pod(s) rejected by the scheduler: " + insufficientCPU,
+			},
+		},
+		{
+			name:            "nothing blocking",
+			pods:            []runtime.Object{makePod("p1", "", "1", "")},
+			schedObjects:    []runtime.Object{makePodGroup(1, "")},
+			expectedReasons: []string{"No blocking reason found, 1 pod(s) are pending scheduling."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := diagnoseGang(context.TODO(), fake.NewSimpleClientset(tt.pods...),
+				schedfake.NewSimpleClientset(tt.schedObjects...), "default", "pg", tt.gangs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expectedReasons, status.Reasons) {
+				t.Errorf("expected reasons %q, got %q", tt.expectedReasons, status.Reasons)
+			}
+			var out bytes.Buffer
+			status.Print(&out)
+			if !strings.Contains(out.String(), "PodGroup:   default/pg\n") {
+				t.Errorf("unexpected output %q", out.String())
+			}
+		})
+	}
+
+	if _, err := diagnoseGang(context.TODO(), fake.NewSimpleClientset(), schedfake.NewSimpleClientset(),
+		"default", "pg", nil); err == nil {
+		t.Errorf("expected an error for a missing PodGroup")
+	}
+}
+
+func TestLoadGangSnapshot(t *testing.T) {
+	expected := &gangSnapshot{
+		WaitingPods:     map[string][]string{"default/pg": {"default/p1"}},
+		DeniedPodGroups: []string{"default/other"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(snapshotJSON))
+	}))
+	defer server.Close()
+	got, err := loadGangSnapshot(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := ioutil.WriteFile(path, []byte(`{"plugins": {"CapacityScheduling": [{}]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadGangSnapshot(path); err == nil {
+		t.Errorf("expected an error for a snapshot without Coscheduling state")
+	}
+}
@@ -17,25 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
 	"time"
 
+	"github.com/spf13/cobra"
 	"k8s.io/component-base/logs"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 
-	"sigs.k8s.io/scheduler-plugins/pkg/capacityscheduling"
-	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
-	"sigs.k8s.io/scheduler-plugins/pkg/noderesources"
-	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology"
-	"sigs.k8s.io/scheduler-plugins/pkg/podstate"
-	"sigs.k8s.io/scheduler-plugins/pkg/preemptiontoleration"
-	"sigs.k8s.io/scheduler-plugins/pkg/qos"
-	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
-	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/targetloadpacking"
-
-	// Ensure scheme package is initialized.
-	_ "sigs.k8s.io/scheduler-plugins/apis/config/scheme"
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
+	"sigs.k8s.io/scheduler-plugins/pkg/registry"
 )
 
 func main() {
@@ -44,19 +38,28 @@ func main() {
 	// Register custom plugins to the scheduler framework.
 	// Later they can consist of scheduler profile(s) and hence
 	// used by various kinds of workloads.
-	command := app.NewSchedulerCommand(
-		app.WithPlugin(capacityscheduling.Name, capacityscheduling.New),
-		app.WithPlugin(coscheduling.Name, coscheduling.New),
-		app.WithPlugin(loadvariationriskbalancing.Name, loadvariationriskbalancing.New),
-		app.WithPlugin(noderesources.AllocatableName, noderesources.NewAllocatable),
-		app.WithPlugin(noderesourcetopology.Name, noderesourcetopology.New),
-		app.WithPlugin(preemptiontoleration.Name, preemptiontoleration.New),
-		app.WithPlugin(targetloadpacking.Name, targetloadpacking.New),
-		// Sample plugins below.
-		// app.WithPlugin(crossnodepreemption.Name, crossnodepreemption.New),
-		app.WithPlugin(podstate.Name, podstate.New),
-		app.WithPlugin(qos.Name, qos.New),
-	)
+	// The score plugins are wrapped to record their scores, which they only do if requested.
+	command := app.NewSchedulerCommand(registry.NewSchedulerOptions(debug.WrapScorePlugins(registry.NewInTreeRegistry()))...)
+
+	// Serve the in-memory state of the plugins for support bundles, if requested.
+	var debugBindAddress string
+	var scoredCycles int
+	command.Flags().StringVar(&debugBindAddress, "plugins-debug-bind-address", "",
+		"The address serving the in-memory state of the plugins as JSON at "+debug.SnapshotPath+", for support bundles. Disabled if empty.")
+	command.Flags().IntVar(&scoredCycles, "plugins-debug-scored-cycles", 0,
+		"The number of the last scheduling cycles whose normalized scores, per plugin of this repository and candidate node, "+
+			"are served at "+debug.ScoresPath+" of --plugins-debug-bind-address, to tune the plugin weights. Disabled if 0.")
+	runE := command.RunE
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		if scoredCycles < 0 {
+			return fmt.Errorf("--plugins-debug-scored-cycles should not be negative, got %d", scoredCycles)
+		}
+		if len(debugBindAddress) != 0 {
+			debug.SetScoredCycles(scoredCycles)
+			go serveDebug(debugBindAddress)
+		}
+		return runE(cmd, args)
+	}
 
 	// TODO: once we switch everything over to Cobra commands, we can go back to calling
 	// utilflag.InitFlags() (by removing its pflag.Parse() call). For now, we have to set the
@@ -69,3 +72,13 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func serveDebug(address string) {
+	mux := http.NewServeMux()
+	mux.Handle(debug.SnapshotPath, debug.Handler())
+	mux.Handle(debug.ScoresPath, debug.ScoresHandler())
+	klog.InfoS("Serving plugins snapshot", "address", address, "path", debug.SnapshotPath)
+	if err := http.ListenAndServe(address, mux); err != nil {
+		klog.ErrorS(err, "Failed to serve plugins snapshot", "address", address)
+	}
+}
@@ -436,7 +436,9 @@ profiles:
 				"default-scheduler": {
 					MultiPoint: defaults.ExpandedPluginsV1beta3.MultiPoint,
 					QueueSort:  config.PluginSet{Enabled: []config.Plugin{{Name: coscheduling.Name}}},
-					Bind:       defaults.ExpandedPluginsV1beta3.Bind,
+					Bind: config.PluginSet{
+						Enabled: append(defaults.ExpandedPluginsV1beta3.Bind.Enabled, config.Plugin{Name: coscheduling.Name}),
+					},
 					PreFilter: config.PluginSet{
 						Enabled: append(defaults.ExpandedPluginsV1beta3.PreFilter.Enabled, config.Plugin{Name: coscheduling.Name}),
 					},
@@ -0,0 +1,26 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifests embeds the CustomResourceDefinitions of the plugins, so that they can be
+// installed without vendoring the yaml files.
+package manifests
+
+import "embed"
+
+// CRDs holds the <plugin>/crd.yaml files of this directory.
+//
+//go:embed */crd.yaml
+var CRDs embed.FS
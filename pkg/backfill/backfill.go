@@ -0,0 +1,287 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// Backfill is a PreFilter plugin reserving the free capacity still missing to the gangs waiting
+// at the Coscheduling permit, and letting short lower-priority pods run in it as long as they are
+// predicted to finish before the gangs can be fully assembled.
+type Backfill struct {
+	handle   framework.Handle
+	pgLister listers.PodGroupLister
+}
+
+var _ framework.PreFilterPlugin = &Backfill{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "Backfill"
+)
+
+// gang is a PodGroup with members waiting at the Coscheduling permit.
+type gang struct {
+	name     string
+	priority int32
+	// missing is the capacity still needed by the members not assigned yet.
+	missing v1.ResourceList
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := &config.BackfillArgs{}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.BackfillArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type BackfillArgs, got %T", obj)
+		}
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	pgInformer := informerFactory.Scheduling().V1alpha1().PodGroups()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	return &Backfill{
+		handle:   handle,
+		pgLister: pgInformer.Lister(),
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (b *Backfill) Name() string {
+	return Name
+}
+
+func (b *Backfill) preFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	// Gang members are handled by Coscheduling.
+	if len(util.GetPodGroupLabel(pod)) != 0 {
+		return nil
+	}
+	var waiting []*v1.Pod
+	b.handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		for _, plugin := range waitingPod.GetPendingPlugins() {
+			if plugin == coscheduling.Name {
+				waiting = append(waiting, waitingPod.GetPod())
+				return
+			}
+		}
+	})
+	if len(waiting) == 0 {
+		return nil
+	}
+	nodeInfos, err := b.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("listing NodeInfos: %w", err))
+	}
+	return admit(pod, b.pendingGangs(waiting, nodeInfos), nodeInfos, time.Now())
+}
+
+// PreFilterExtensions returns nil as the reservation is checked once for the whole cluster.
+func (b *Backfill) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// pendingGangs returns the gangs of the given waiting pods still missing members.
+func (b *Backfill) pendingGangs(waiting []*v1.Pod, nodeInfos []*framework.NodeInfo) []gang {
+	seen := make(map[string]bool)
+	var gangs []gang
+	for _, pod := range waiting {
+		name := util.GetPodGroupFullName(pod)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		pg, err := b.pgLister.PodGroups(pod.Namespace).Get(util.GetPodGroupLabel(pod))
+		if err != nil {
+			klog.V(4).InfoS("Cannot get the PodGroup of a waiting pod", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		missing := pg.Spec.MinMember - assignedMembers(name, nodeInfos)
+		if missing <= 0 {
+			continue
+		}
+		// The members still to come are assumed to request as much as a waiting one.
+		request := util.GetPodEffectiveRequest(pod)
+		need := make(v1.ResourceList, len(request))
+		for rName, quantity := range request {
+			need[rName] = *resource.NewMilliQuantity(quantity.MilliValue()*int64(missing), quantity.Format)
+		}
+		gangs = append(gangs, gang{name: name, priority: corev1helpers.PodPriority(pod), missing: need})
+	}
+	return gangs
+}
+
+// admit lets the pod use the free capacity if it leaves enough of it to the pending gangs of higher
+// priority, or if it declares a maximum runtime ending before these gangs can be assembled.
+func admit(pod *v1.Pod, gangs []gang, nodeInfos []*framework.NodeInfo, now time.Time) *framework.Status {
+	priority := corev1helpers.PodPriority(pod)
+	free := freeCapacity(nodeInfos)
+	reserved := v1.ResourceList{}
+	var outranking []gang
+	for _, g := range gangs {
+		if g.priority <= priority {
+			continue
+		}
+		outranking = append(outranking, g)
+		add(reserved, g.missing)
+	}
+	if len(outranking) == 0 {
+		return nil
+	}
+	request := util.GetPodEffectiveRequest(pod)
+	needed := v1.ResourceList{}
+	add(needed, reserved)
+	add(needed, request)
+	if fits(needed, free) {
+		return nil
+	}
+
+	if pod.Spec.ActiveDeadlineSeconds == nil {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("capacity is reserved for PodGroup %v and the pod declares no activeDeadlineSeconds", outranking[0].name))
+	}
+	end := now.Add(time.Duration(*pod.Spec.ActiveDeadlineSeconds) * time.Second)
+	for _, g := range outranking {
+		assembly, ok := assemblyTime(g.missing, free, nodeInfos, now)
+		if !ok || end.After(assembly) {
+			return framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("capacity is reserved for PodGroup %v and the pod may not finish before it is assembled", g.name))
+		}
+	}
+	klog.V(4).InfoS("Backfilling pod in capacity reserved for pending PodGroups", "pod", klog.KObj(pod), "end", end)
+	return nil
+}
+
+// assemblyTime predicts when the given missing capacity of a gang is free, from the end of the running
+// pods declaring a maximum runtime. It returns false if the pods declaring one never free enough of it.
+func assemblyTime(missing, free v1.ResourceList, nodeInfos []*framework.NodeInfo, now time.Time) (time.Time, bool) {
+	if fits(missing, free) {
+		return now, true
+	}
+	type release struct {
+		end     time.Time
+		request v1.ResourceList
+	}
+	var releases []release
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			p := podInfo.Pod
+			if p.Spec.ActiveDeadlineSeconds == nil || p.Status.StartTime == nil {
+				continue
+			}
+			end := p.Status.StartTime.Add(time.Duration(*p.Spec.ActiveDeadlineSeconds) * time.Second)
+			releases = append(releases, release{end: end, request: util.GetPodEffectiveRequest(p)})
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].end.Before(releases[j].end)
+	})
+	available := v1.ResourceList{}
+	add(available, free)
+	for _, r := range releases {
+		add(available, r.request)
+		if fits(missing, available) {
+			return r.end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// assignedMembers returns the number of members of the given gang assigned a node: assumed or bound.
+func assignedMembers(pgFullName string, nodeInfos []*framework.NodeInfo) int32 {
+	var count int32
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			if util.GetPodGroupFullName(podInfo.Pod) == pgFullName && podInfo.Pod.Spec.NodeName != "" {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// freeCapacity returns the capacity left on all the nodes.
+func freeCapacity(nodeInfos []*framework.NodeInfo) v1.ResourceList {
+	free := v1.ResourceList{}
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Node() == nil {
+			continue
+		}
+		allocatable := util.ResourceList(nodeInfo.Allocatable)
+		requested := util.ResourceList(nodeInfo.Requested)
+		requested[v1.ResourcePods] = *resource.NewQuantity(int64(len(nodeInfo.Pods)), resource.DecimalSI)
+		for rName, quantity := range allocatable {
+			q := quantity.DeepCopy()
+			q.Sub(requested[rName])
+			if current, ok := free[rName]; ok {
+				q.Add(current)
+			}
+			free[rName] = q
+		}
+	}
+	return free
+}
+
+// add adds the quantities of b to a.
+func add(a, b v1.ResourceList) {
+	for rName, quantity := range b {
+		q := quantity.DeepCopy()
+		if current, ok := a[rName]; ok {
+			q.Add(current)
+		}
+		a[rName] = q
+	}
+}
+
+// fits returns whether every quantity of the request is covered by the available capacity.
+func fits(request, available v1.ResourceList) bool {
+	for rName, quantity := range request {
+		q := available[rName]
+		if quantity.Cmp(q) > 0 {
+			return false
+		}
+	}
+	return true
+}
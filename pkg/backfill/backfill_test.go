@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfill
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	pginformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestAdmit(t *testing.T) {
+	now := time.Now()
+	pgInformer := pginformers.NewSharedInformerFactory(fakeclientset.NewSimpleClientset(), 0).Scheduling().V1alpha1().PodGroups()
+	pgInformer.Informer().GetStore().Add(testutil.MakePG("train", "default", 3, nil, nil))
+	b := &Backfill{pgLister: pgInformer.Lister()}
+
+	// One member of the gang "train" waits at Permit on n1, two more cpus are needed to assemble it.
+	member := st.MakePod().Namespace("default").Name("train-1").Node("n1").Priority(100).
+		Label(v1alpha1.PodGroupLabel, "train").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+	// A batch pod releasing two cpus in 10 minutes.
+	batch := st.MakePod().Namespace("default").Name("batch").Node("n1").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	batch.Spec.ActiveDeadlineSeconds = int64Ptr(1200)
+	batch.Status.StartTime = &metav1.Time{Time: now.Add(-10 * time.Minute)}
+	n1 := framework.NewNodeInfo(member, batch)
+	n1.SetNode(st.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourcePods: "10"}).Obj())
+	n2 := framework.NewNodeInfo()
+	n2.SetNode(st.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourcePods: "10"}).Obj())
+
+	makePod := func(priority int32, activeDeadlineSeconds *int64) *v1.Pod {
+		pod := st.MakePod().Namespace("default").Name("pod").Priority(priority).
+			Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+		pod.Spec.ActiveDeadlineSeconds = activeDeadlineSeconds
+		return pod
+	}
+
+	tests := []struct {
+		name      string
+		pod       *v1.Pod
+		waiting   []*v1.Pod
+		nodeInfos []*framework.NodeInfo
+		expected  framework.Code
+	}{
+		{
+			name:      "no pending gang",
+			pod:       makePod(0, nil),
+			nodeInfos: []*framework.NodeInfo{n1},
+			expected:  framework.Success,
+		},
+		{
+			name:      "pod outranking the gang",
+			pod:       makePod(200, nil),
+			waiting:   []*v1.Pod{member},
+			nodeInfos: []*framework.NodeInfo{n1},
+			expected:  framework.Success,
+		},
+		{
+			name:      "pod leaving enough capacity to the gang",
+			pod:       makePod(0, nil),
+			waiting:   []*v1.Pod{member},
+			nodeInfos: []*framework.NodeInfo{n1, n2},
+			expected:  framework.Success,
+		},
+		{
+			name:      "pod without declared runtime in reserved capacity",
+			pod:       makePod(0, nil),
+			waiting:   []*v1.Pod{member},
+			nodeInfos: []*framework.NodeInfo{n1},
+			expected:  framework.Unschedulable,
+		},
+		{
+			name:      "pod finishing before the gang is assembled",
+			pod:       makePod(0, int64Ptr(300)),
+			waiting:   []*v1.Pod{member},
+			nodeInfos: []*framework.NodeInfo{n1},
+			expected:  framework.Success,
+		},
+		{
+			name:      "pod finishing after the gang is assembled",
+			pod:       makePod(0, int64Ptr(1800)),
+			waiting:   []*v1.Pod{member},
+			nodeInfos: []*framework.NodeInfo{n1},
+			expected:  framework.Unschedulable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := admit(tt.pod, b.pendingGangs(tt.waiting, tt.nodeInfos), tt.nodeInfos, now)
+			if status.Code() != tt.expected {
+				t.Errorf("expected %v, got %v: %v", tt.expected, status.Code(), status.Message())
+			}
+		})
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
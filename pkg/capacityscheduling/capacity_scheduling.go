@@ -42,8 +42,10 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
 
+	"sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
 	"sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
 	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
 	externalv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
@@ -58,6 +60,9 @@ type CapacityScheduling struct {
 	pdbLister          policylisters.PodDisruptionBudgetLister
 	elasticQuotaLister externalv1alpha1.ElasticQuotaLister
 	elasticQuotaInfos  ElasticQuotaInfos
+	client             versioned.Interface
+	// preemptionDryRun makes PostFilter report the victims instead of preempting them.
+	preemptionDryRun bool
 }
 
 // PreFilterState computed at PreFilter and used at PostFilter or Reserve.
@@ -97,6 +102,7 @@ var _ framework.PostFilterPlugin = &CapacityScheduling{}
 var _ framework.ReservePlugin = &CapacityScheduling{}
 var _ framework.EnqueueExtensions = &CapacityScheduling{}
 var _ preemption.Interface = &preemptor{}
+var _ debug.Dumper = &CapacityScheduling{}
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
@@ -114,17 +120,27 @@ func (c *CapacityScheduling) Name() string {
 
 // New initializes a new plugin and returns it.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := &config.CapacitySchedulingArgs{}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.CapacitySchedulingArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type CapacitySchedulingArgs, got %T", obj)
+		}
+	}
+
 	c := &CapacityScheduling{
 		fh:                handle,
 		elasticQuotaInfos: NewElasticQuotaInfos(),
 		podLister:         handle.SharedInformerFactory().Core().V1().Pods().Lister(),
 		pdbLister:         getPDBLister(handle.SharedInformerFactory()),
+		preemptionDryRun:  args.PreemptionDryRun,
 	}
 
-	client, err := versioned.NewForConfig(handle.KubeConfig())
+	client, err := versioned.NewForConfig(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
 	if err != nil {
 		return nil, err
 	}
+	c.client = client
 
 	schedSharedInformerFactory := schedinformer.NewSharedInformerFactory(client, 0)
 	c.elasticQuotaLister = schedSharedInformerFactory.Scheduling().V1alpha1().ElasticQuotas().Lister()
@@ -182,9 +198,35 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		},
 	)
 	klog.InfoS("CapacityScheduling start")
+	debug.Register(Name, c)
 	return c, nil
 }
 
+// quotaSnapshot is the state of an ElasticQuota tracked by the plugin.
+type quotaSnapshot struct {
+	Min  *framework.Resource `json:"min"`
+	Max  *framework.Resource `json:"max"`
+	Used *framework.Resource `json:"used"`
+	// Pods is the number of pods accounted in Used.
+	Pods int `json:"pods"`
+}
+
+// Dump returns the ElasticQuotas tracked by the plugin, keyed by namespace.
+func (c *CapacityScheduling) Dump() interface{} {
+	c.RLock()
+	defer c.RUnlock()
+	s := make(map[string]*quotaSnapshot, len(c.elasticQuotaInfos))
+	for namespace, info := range c.elasticQuotaInfos {
+		s[namespace] = &quotaSnapshot{
+			Min:  info.Min.Clone(),
+			Max:  info.Max.Clone(),
+			Used: info.Used.Clone(),
+			Pods: info.pods.Len(),
+		}
+	}
+	return s
+}
+
 func (c *CapacityScheduling) EventsToRegister() []framework.ClusterEvent {
 	// To register a custom event, follow the naming convention at:
 	// https://git.k8s.io/kubernetes/pkg/scheduler/eventhandlers.go#L403-L410
@@ -195,10 +237,10 @@ func (c *CapacityScheduling) EventsToRegister() []framework.ClusterEvent {
 	}
 }
 
-// PreFilter performs the following validations.
+// preFilter performs the following validations.
 // 1. Check if the (pod.request + eq.allocated) is less than eq.max.
 // 2. Check if the sum(eq's usage) > sum(eq's min).
-func (c *CapacityScheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+func (c *CapacityScheduling) preFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
 	// TODO improve the efficiency of taking snapshot
 	// e.g. use a two-pointer data structure to only copy the updated EQs when necessary.
 	snapshotElasticQuota := c.snapshotElasticQuota()
@@ -334,6 +376,9 @@ func (c *CapacityScheduling) PostFilter(ctx context.Context, state *framework.Cy
 		},
 	}
 
+	if c.preemptionDryRun {
+		return c.dryRunPreempt(ctx, &pe, pod, m)
+	}
 	return pe.Preempt(ctx, pod, m)
 }
 
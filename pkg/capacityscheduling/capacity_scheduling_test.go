@@ -18,6 +18,7 @@ package capacityscheduling
 
 import (
 	"context"
+	"reflect"
 	"sort"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
@@ -40,6 +42,9 @@ import (
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 	imageutils "k8s.io/kubernetes/test/utils/image"
 
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeschedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
 	testutil "sigs.k8s.io/scheduler-plugins/test/util"
 )
 
@@ -51,9 +56,11 @@ var (
 
 func TestPreFilter(t *testing.T) {
 	type podInfo struct {
-		podName      string
-		podNamespace string
-		memReq       int64
+		podName        string
+		podNamespace   string
+		memReq         int64
+		initMemReq     int64
+		overheadMemReq int64
 	}
 
 	tests := []struct {
@@ -122,6 +129,33 @@ func TestPreFilter(t *testing.T) {
 				framework.Unschedulable,
 			},
 		},
+		{
+			name: "init containers and pod overhead count against ElasticQuota",
+			podInfos: []podInfo{
+				{podName: "ns1-p1", podNamespace: "ns1", memReq: 500, initMemReq: 1800},
+				{podName: "ns1-p2", podNamespace: "ns1", memReq: 1500, overheadMemReq: 300},
+				{podName: "ns1-p3", podNamespace: "ns1", memReq: 400, initMemReq: 600, overheadMemReq: 100},
+			},
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": {
+					Namespace: "ns1",
+					Min: &framework.Resource{
+						Memory: 1000,
+					},
+					Max: &framework.Resource{
+						Memory: 2000,
+					},
+					Used: &framework.Resource{
+						Memory: 300,
+					},
+				},
+			},
+			expected: []framework.Code{
+				framework.Unschedulable,
+				framework.Unschedulable,
+				framework.Success,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -150,6 +184,13 @@ func TestPreFilter(t *testing.T) {
 			pods := make([]*v1.Pod, 0)
 			for _, podInfo := range tt.podInfos {
 				pod := makePod(podInfo.podName, podInfo.podNamespace, podInfo.memReq, 0, 0, 0, podInfo.podName, "")
+				if podInfo.initMemReq != 0 {
+					pod.Spec.InitContainers = []v1.Container{{Name: "init", Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceMemory: *resource.NewQuantity(podInfo.initMemReq, resource.DecimalSI)}}}}
+				}
+				if podInfo.overheadMemReq != 0 {
+					pod.Spec.Overhead = v1.ResourceList{v1.ResourceMemory: *resource.NewQuantity(podInfo.overheadMemReq, resource.DecimalSI)}
+				}
 				pods = append(pods, pod)
 			}
 
@@ -369,6 +410,96 @@ func TestDryRunPreemption(t *testing.T) {
 	}
 }
 
+func TestPostFilterPreemptionDryRun(t *testing.T) {
+	res := map[v1.ResourceName]string{v1.ResourceMemory: "150"}
+	pod := makePod("t1-p", "ns1", 50, 0, 0, highPriority, "", "t1-p")
+	pods := []*v1.Pod{
+		makePod("t1-p1", "ns1", 50, 0, 0, midPriority, "t1-p1", "node-a"),
+		makePod("t1-p2", "ns2", 50, 0, 0, midPriority, "t1-p2", "node-a"),
+		makePod("t1-p3", "ns2", 50, 0, 0, midPriority, "t1-p3", "node-a"),
+	}
+	nodes := []*v1.Node{st.MakeNode().Name("node-a").Capacity(res).Obj()}
+	elasticQuotas := map[string]*ElasticQuotaInfo{
+		"ns1": {Namespace: "ns1", Max: &framework.Resource{Memory: 200}, Min: &framework.Resource{Memory: 50}, Used: &framework.Resource{Memory: 50}},
+		"ns2": {Namespace: "ns2", Max: &framework.Resource{Memory: 200}, Min: &framework.Resource{Memory: 200}, Used: &framework.Resource{Memory: 100}},
+	}
+
+	cs := clientsetfake.NewSimpleClientset()
+	recorder := &events.FakeRecorder{Events: make(chan string, 1)}
+	fwk, err := st.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			st.RegisterPluginAsExtensions(noderesources.FitName, func(plArgs apiruntime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return noderesources.NewFit(plArgs, fh, plfeature.Features{})
+			}, "Filter", "PreFilter"),
+		},
+		"default-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithEventRecorder(recorder),
+		frameworkruntime.WithPodNominator(testutil.NewPodNominator(nil)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(pods, nodes)),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eq := &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: "ns1"}}
+	schedClient := fakeschedclientset.NewSimpleClientset(eq)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	eqLister := schedInformerFactory.Scheduling().V1alpha1().ElasticQuotas().Lister()
+	schedInformerFactory.Start(ctx.Done())
+	schedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	c := &CapacityScheduling{
+		fh:                 fwk,
+		podLister:          fwk.SharedInformerFactory().Core().V1().Pods().Lister(),
+		pdbLister:          getPDBLister(fwk.SharedInformerFactory()),
+		elasticQuotaLister: eqLister,
+		elasticQuotaInfos:  elasticQuotas,
+		client:             schedClient,
+		preemptionDryRun:   true,
+	}
+	state := framework.NewCycleState()
+	if status := fwk.RunPreFilterPlugins(ctx, state, pod); !status.IsSuccess() {
+		t.Fatalf("Unexpected preFilterStatus: %v", status)
+	}
+	podReq := computePodResourceRequest(pod)
+	state.Write(preFilterStateKey, &PreFilterState{
+		podReq:                         *podReq,
+		nominatedPodsReqWithPodReq:     *podReq,
+		nominatedPodsReqInEQWithPodReq: *podReq,
+	})
+	state.Write(ElasticQuotaSnapshotKey, &ElasticQuotaSnapshotState{elasticQuotaInfos: elasticQuotas})
+
+	result, status := c.PostFilter(ctx, state, pod, framework.NodeToStatusMap{"node-a": framework.NewStatus(framework.Unschedulable)})
+	if result != nil || status.Code() != framework.Unschedulable {
+		t.Errorf("expected the preemptor to stay unschedulable and not nominated, got %v, %v", result, status)
+	}
+	for _, action := range cs.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Errorf("unexpected eviction in dry-run mode: %v", action)
+		}
+	}
+	select {
+	case <-recorder.Events:
+	default:
+		t.Errorf("expected a PreemptionDryRun event")
+	}
+
+	got, err := schedClient.SchedulingV1alpha1().ElasticQuotas("ns1").Get(ctx, "eq", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dryRun := got.Status.LastPreemptionDryRun
+	if dryRun == nil || dryRun.Preemptor != "ns1/t1-p" || dryRun.NodeName != "node-a" || !reflect.DeepEqual(dryRun.Victims, []string{"ns1/t1-p1"}) {
+		t.Errorf("unexpected preemption dry-run in ElasticQuota status: %+v", dryRun)
+	}
+}
+
 func makePod(podName string, namespace string, memReq int64, cpuReq int64, gpuReq int64, priority int32, uid string, nodeName string) *v1.Pod {
 	pause := imageutils.GetPauseImageName()
 	pod := st.MakePod().Namespace(namespace).Name(podName).Container(pause).
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+var (
+	preemptionDryRunVictims = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "capacity_scheduling_dry_run_preemption_victims_total",
+			Help:           "Number of pods CapacityScheduling would have preempted in preemption dry-run mode, broken down by preemptor namespace.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"namespace"})
+
+	registerMetrics sync.Once
+)
+
+// dryRunPreempt selects the victims the preemptor would preempt the way pe.Preempt does, but
+// only reports them: the victims are not evicted and the preemptor is not nominated.
+func (c *CapacityScheduling) dryRunPreempt(ctx context.Context, pe *preemption.Evaluator, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(preemptionDryRunVictims)
+	})
+
+	if !pe.PodEligibleToPreemptOthers(pod, m[pod.Status.NominatedNodeName]) {
+		klog.V(5).InfoS("Pod is not eligible for more preemption", "pod", klog.KObj(pod))
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	allNodes, err := c.fh.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	var potentialNodes []*framework.NodeInfo
+	for _, nodeInfo := range allNodes {
+		if m[nodeInfo.Node().Name].Code() != framework.UnschedulableAndUnresolvable {
+			potentialNodes = append(potentialNodes, nodeInfo)
+		}
+	}
+	if len(potentialNodes) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable, "Preemption is not helpful for scheduling")
+	}
+	pdbs, err := getPodDisruptionBudgets(c.pdbLister)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+
+	offset, numCandidates := pe.GetOffsetAndNumCandidates(int32(len(potentialNodes)))
+	candidates, _, err := pe.DryRunPreemption(ctx, pod, potentialNodes, pdbs, offset, numCandidates)
+	if err != nil && len(candidates) == 0 {
+		return nil, framework.AsStatus(err)
+	}
+	best := pe.SelectCandidate(candidates)
+	if best == nil || len(best.Name()) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	dryRun := &v1alpha1.PreemptionDryRun{
+		Preemptor: pod.Namespace + "/" + pod.Name,
+		NodeName:  best.Name(),
+		Time:      metav1.Now(),
+	}
+	for _, victim := range best.Victims().Pods {
+		dryRun.Victims = append(dryRun.Victims, victim.Namespace+"/"+victim.Name)
+	}
+	c.reportPreemptionDryRun(ctx, pod, dryRun)
+	return nil, framework.NewStatus(framework.Unschedulable,
+		fmt.Sprintf("preemption dry-run: would preempt %d pods on node %s", len(dryRun.Victims), dryRun.NodeName))
+}
+
+// reportPreemptionDryRun records dryRun as an event on the preemptor, in the dry-run metric and in
+// the status of the ElasticQuota of the preemptor's namespace.
+func (c *CapacityScheduling) reportPreemptionDryRun(ctx context.Context, pod *v1.Pod, dryRun *v1alpha1.PreemptionDryRun) {
+	klog.V(3).InfoS("Preemption dry-run", "pod", klog.KObj(pod), "node", dryRun.NodeName, "victims", dryRun.Victims)
+	c.fh.EventRecorder().Eventf(pod, nil, v1.EventTypeNormal, "PreemptionDryRun", "Preempting",
+		"Would preempt %v on node %v", dryRun.Victims, dryRun.NodeName)
+	preemptionDryRunVictims.WithLabelValues(pod.Namespace).Add(float64(len(dryRun.Victims)))
+
+	eqs, err := c.elasticQuotaLister.ElasticQuotas(pod.Namespace).List(labels.Everything())
+	if err != nil || len(eqs) == 0 {
+		return
+	}
+	eq := eqs[0]
+	newEQ := eq.DeepCopy()
+	newEQ.Status.LastPreemptionDryRun = dryRun
+	patch, err := util.CreateMergePatch(eq, newEQ)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create ElasticQuota patch", "elasticQuota", klog.KObj(eq))
+		return
+	}
+	if _, err := c.client.SchedulingV1alpha1().ElasticQuotas(eq.Namespace).Patch(ctx, eq.Name, types.MergePatchType,
+		patch, metav1.PatchOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to record preemption dry-run in ElasticQuota", "elasticQuota", klog.KObj(eq))
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celpolicy
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// CELPolicy is a filter and score plugin evaluating operator-defined CEL rules over the pod,
+// the node and the network costs between topology domains.
+type CELPolicy struct {
+	handle        framework.Handle
+	defaultAction config.CELPolicyAction
+	// filterRules are the Allow and Deny rules, in order.
+	filterRules []rule
+	scoreRules  []rule
+	// costOracle is nil if no NetworkTopology is configured.
+	costOracle *costoracle.CostOracle
+}
+
+var _ framework.FilterPlugin = &CELPolicy{}
+var _ framework.ScorePlugin = &CELPolicy{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "CELPolicy"
+)
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.CELPolicyArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type CELPolicyArgs, got %T", obj)
+	}
+	if args.DefaultAction != config.CELPolicyAllow && args.DefaultAction != config.CELPolicyDeny {
+		return nil, fmt.Errorf("defaultAction must be %s or %s, got %q", config.CELPolicyAllow, config.CELPolicyDeny, args.DefaultAction)
+	}
+
+	var costOracle *costoracle.CostOracle
+	if args.NetworkTopologyName != "" {
+		client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+		informerFactory := informers.NewSharedInformerFactory(client, 0)
+		ntInformer := informerFactory.Scheduling().V1alpha1().NetworkTopologies()
+		var err error
+		if costOracle, err = costoracle.New(ntInformer, args.NetworkTopologyNamespace, args.NetworkTopologyName, args.WeightsName); err != nil {
+			return nil, fmt.Errorf("invalid weightsName: %w", err)
+		}
+
+		ctx := context.TODO()
+		informerFactory.Start(ctx.Done())
+		if !cache.WaitForCacheSync(ctx.Done(), ntInformer.Informer().HasSynced) {
+			err := fmt.Errorf("WaitForCacheSync failed")
+			klog.ErrorS(err, "Cannot sync caches")
+			return nil, err
+		}
+	}
+
+	return newCELPolicy(handle, args, costOracle)
+}
+
+func newCELPolicy(handle framework.Handle, args *config.CELPolicyArgs, costOracle *costoracle.CostOracle) (*CELPolicy, error) {
+	rules, err := compileRules(args.Rules, costOracle)
+	if err != nil {
+		return nil, err
+	}
+	pl := &CELPolicy{handle: handle, defaultAction: args.DefaultAction, costOracle: costOracle}
+	for _, r := range rules {
+		if r.action == config.CELPolicyScore {
+			pl.scoreRules = append(pl.scoreRules, r)
+		} else {
+			pl.filterRules = append(pl.filterRules, r)
+		}
+	}
+	return pl, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *CELPolicy) Name() string {
+	return Name
+}
+
+// Filter applies the action of the first Allow or Deny rule matching the node, the default
+// action if none does. Rules failing to evaluate, e.g. looking up a missing label, do not match.
+// The nodes of a zone past the deadline of its draining are rejected before any rule.
+func (pl *CELPolicy) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	if _, pastDeadline := pl.zoneDraining(node); pastDeadline {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("zone %q is draining", node.Labels[v1.LabelTopologyZone]))
+	}
+	vars := map[string]interface{}{podVar: podVars(pod), nodeVar: nodeVars(node)}
+	for i := range pl.filterRules {
+		r := &pl.filterRules[i]
+		matched, err := r.matches(vars)
+		if err != nil {
+			klog.V(5).InfoS("Rule evaluation failed", "rule", r.name, "pod", klog.KObj(pod), "node", klog.KObj(node), "err", err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		if r.action == config.CELPolicyDeny {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("node denied by rule %q", r.name))
+		}
+		return nil
+	}
+	if pl.defaultAction == config.CELPolicyDeny {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, "node allowed by no rule")
+	}
+	return nil
+}
+
+// Score sums the scores of the Score rules matching the node. The nodes of a draining zone
+// score 0.
+func (pl *CELPolicy) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	if len(pl.scoreRules) == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	node := nodeInfo.Node()
+	if draining, _ := pl.zoneDraining(node); draining {
+		return 0, nil
+	}
+	vars := map[string]interface{}{podVar: podVars(pod), nodeVar: nodeVars(node)}
+	var score int64
+	for i := range pl.scoreRules {
+		r := &pl.scoreRules[i]
+		matched, err := r.matches(vars)
+		if err != nil {
+			klog.V(5).InfoS("Rule evaluation failed", "rule", r.name, "pod", klog.KObj(pod), "node", klog.KObj(node), "err", err)
+			continue
+		}
+		if matched {
+			score += r.score
+		}
+	}
+	return score, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *CELPolicy) ScoreExtensions() framework.ScoreExtensions {
+	return pl
+}
+
+// NormalizeScore scales the summed scores so that the highest one is framework.MaxNodeScore.
+func (pl *CELPolicy) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	helper.DefaultNormalizeScore(framework.MaxNodeScore, false, scores)
+	return nil
+}
+
+// zoneDraining tells whether the zone of node is draining, and whether it is past its deadline.
+func (pl *CELPolicy) zoneDraining(node *v1.Node) (draining, pastDeadline bool) {
+	zone := node.Labels[v1.LabelTopologyZone]
+	if pl.costOracle == nil || zone == "" {
+		return false, false
+	}
+	return pl.costOracle.ZoneDraining(zone)
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celpolicy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestCompileRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []config.CELPolicyRule
+		wantErr bool
+	}{
+		{
+			name: "valid rules",
+			rules: []config.CELPolicyRule{
+				{Name: "gpu", Expression: `node.labels["gpu"] == "true"`, Action: config.CELPolicyDeny},
+				{Name: "zone", Expression: `networkCost("topology.kubernetes.io/zone", "z1", node.labels["zone"]) < 5`, Action: config.CELPolicyScore, Score: 10},
+			},
+		},
+		{
+			name:    "missing name",
+			rules:   []config.CELPolicyRule{{Expression: "true", Action: config.CELPolicyAllow}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			rules: []config.CELPolicyRule{
+				{Name: "r", Expression: "true", Action: config.CELPolicyAllow},
+				{Name: "r", Expression: "false", Action: config.CELPolicyDeny},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			rules:   []config.CELPolicyRule{{Name: "r", Expression: "true", Action: "Kill"}},
+			wantErr: true,
+		},
+		{
+			name:    "score out of range",
+			rules:   []config.CELPolicyRule{{Name: "r", Expression: "true", Action: config.CELPolicyScore, Score: 101}},
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			rules:   []config.CELPolicyRule{{Name: "r", Expression: "pod.labels[", Action: config.CELPolicyAllow}},
+			wantErr: true,
+		},
+		{
+			name:    "expression not evaluating to a bool",
+			rules:   []config.CELPolicyRule{{Name: "r", Expression: "pod.name", Action: config.CELPolicyAllow}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileRules(tt.rules, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCELPolicyFilter(t *testing.T) {
+	gpuNode := st.MakeNode().Name("gpu").Label("gpu", "true").Obj()
+	cpuNode := st.MakeNode().Name("cpu").Label("tier", "batch").Obj()
+	bareNode := st.MakeNode().Name("bare").Obj()
+
+	tests := []struct {
+		name          string
+		defaultAction config.CELPolicyAction
+		pod           *v1.Pod
+		node          *v1.Node
+		expected      framework.Code
+	}{
+		{
+			name:          "denied by the first matching rule",
+			defaultAction: config.CELPolicyAllow,
+			pod:           st.MakePod().Name("p").Label("tier", "batch").Obj(),
+			node:          gpuNode,
+			expected:      framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:          "allowed by the first matching rule",
+			defaultAction: config.CELPolicyDeny,
+			pod:           st.MakePod().Name("p").Label("tier", "batch").Obj(),
+			node:          cpuNode,
+			expected:      framework.Success,
+		},
+		{
+			name:          "rule failing to evaluate does not match",
+			defaultAction: config.CELPolicyDeny,
+			pod:           st.MakePod().Name("p").Obj(),
+			node:          cpuNode,
+			expected:      framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:          "default action allows",
+			defaultAction: config.CELPolicyAllow,
+			pod:           st.MakePod().Name("p").Label("tier", "web").Obj(),
+			node:          bareNode,
+			expected:      framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, err := newCELPolicy(nil, &config.CELPolicyArgs{
+				DefaultAction: tt.defaultAction,
+				Rules: []config.CELPolicyRule{
+					{Name: "no-gpu-for-batch", Expression: `pod.labels["tier"] == "batch" && "gpu" in node.labels`, Action: config.CELPolicyDeny},
+					{Name: "batch-tier", Expression: `pod.labels["tier"] == node.labels["tier"]`, Action: config.CELPolicyAllow},
+				},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.node)
+			if got := pl.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo); got.Code() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got.Code())
+			}
+		})
+	}
+}
+
+func TestCELPolicyScore(t *testing.T) {
+	topology := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 2}, {Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyZone, "z1").Label("ssd", "true").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyZone, "z2").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyZone, "z3").Label("ssd", "true").Obj(),
+		st.MakeNode().Name("n4").Label(v1.LabelTopologyZone, "z4").Obj(),
+	}
+	pod := st.MakePod().Name("p").Label("example.com/data-zone", "z1").Obj()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	costOracle := newTestCostOracle(ctx, t, topology)
+	fh := newTestFramework(t, nodes)
+
+	pl, err := newCELPolicy(fh, &config.CELPolicyArgs{
+		DefaultAction: config.CELPolicyAllow,
+		Rules: []config.CELPolicyRule{
+			{
+				Name:       "close-to-data",
+				Expression: `networkCost("topology.kubernetes.io/zone", pod.labels["example.com/data-zone"], node.labels["topology.kubernetes.io/zone"]) < 5`,
+				Action:     config.CELPolicyScore,
+				Score:      30,
+			},
+			{Name: "ssd", Expression: `node.labels["ssd"] == "true"`, Action: config.CELPolicyScore, Score: 10},
+		},
+	}, costOracle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := framework.NewCycleState()
+	var gotList framework.NodeScoreList
+	for _, n := range nodes {
+		score, status := pl.Score(ctx, state, pod, n.Name)
+		if !status.IsSuccess() {
+			t.Fatalf("unexpected Score status: %v", status)
+		}
+		gotList = append(gotList, framework.NodeScore{Name: n.Name, Score: score})
+	}
+	if status := pl.NormalizeScore(ctx, state, pod, gotList); !status.IsSuccess() {
+		t.Fatalf("unexpected NormalizeScore status: %v", status)
+	}
+	// n1 is in the data zone and has SSDs, n2 is close to it, n3 only has SSDs, and
+	// the cost to z4 is unknown, hence the highest.
+	expected := framework.NodeScoreList{{Name: "n1", Score: 100}, {Name: "n2", Score: 75}, {Name: "n3", Score: 25}, {Name: "n4", Score: 0}}
+	if !reflect.DeepEqual(expected, gotList) {
+		t.Errorf("expected %v, got %v", expected, gotList)
+	}
+}
+
+func TestCELPolicyDrainingZones(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	topology := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 2}, {Destination: "z3", NetworkCost: 2}}},
+					},
+				}},
+			}},
+			DrainingZones: []v1alpha1.DrainingZone{{Zone: "z2"}, {Zone: "z3", Deadline: &past}},
+		},
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyZone, "z1").Label("ssd", "true").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyZone, "z2").Label("ssd", "true").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyZone, "z3").Label("ssd", "true").Obj(),
+	}
+	pod := st.MakePod().Name("p").Obj()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	costOracle := newTestCostOracle(ctx, t, topology)
+	fh := newTestFramework(t, nodes)
+	pl, err := newCELPolicy(fh, &config.CELPolicyArgs{
+		DefaultAction: config.CELPolicyAllow,
+		Rules:         []config.CELPolicyRule{{Name: "ssd", Expression: `node.labels["ssd"] == "true"`, Action: config.CELPolicyScore, Score: 10}},
+	}, costOracle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The draining zone z2 is still feasible but scores the lowest, z3 is past its deadline.
+	expectedCodes := map[string]framework.Code{"n1": framework.Success, "n2": framework.Success, "n3": framework.UnschedulableAndUnresolvable}
+	expectedScores := map[string]int64{"n1": 10, "n2": 0, "n3": 0}
+	for _, n := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(n)
+		if got := pl.Filter(ctx, framework.NewCycleState(), pod, nodeInfo); got.Code() != expectedCodes[n.Name] {
+			t.Errorf("expected %v for %v, got %v", expectedCodes[n.Name], n.Name, got.Code())
+		}
+		score, status := pl.Score(ctx, framework.NewCycleState(), pod, n.Name)
+		if !status.IsSuccess() {
+			t.Fatalf("unexpected Score status: %v", status)
+		}
+		if score != expectedScores[n.Name] {
+			t.Errorf("expected score %v for %v, got %v", expectedScores[n.Name], n.Name, score)
+		}
+	}
+}
+
+// newTestCostOracle returns a CostOracle serving the UserDefined weights of topology, once observed.
+func newTestCostOracle(ctx context.Context, t *testing.T, topology *v1alpha1.NetworkTopology) *costoracle.CostOracle {
+	cs := fakeclientset.NewSimpleClientset(topology)
+	schedInformerFactory := schedinformers.NewSharedInformerFactory(cs, 0)
+	costOracle, err := costoracle.New(schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), topology.Namespace, topology.Name, "UserDefined")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedInformerFactory.Start(ctx.Done())
+	schedInformerFactory.WaitForCacheSync(ctx.Done())
+	if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		return costOracle.GetMaxCost(v1alpha1.NetworkTopologyZone) > 0, nil
+	}); err != nil {
+		t.Fatalf("NetworkTopology not observed: %v", err)
+	}
+	return costOracle
+}
+
+func newTestFramework(t *testing.T, nodes []*v1.Node) framework.Handle {
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fh
+}
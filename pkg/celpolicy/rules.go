@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celpolicy
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter/functions"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+)
+
+const (
+	// podVar and nodeVar are the variables the rules refer to the pod and the node with.
+	podVar  = "pod"
+	nodeVar = "node"
+
+	// networkCostFunction returns the network cost between an origin and a destination
+	// for a topology key.
+	networkCostFunction = "networkCost"
+	networkCostOverload = "networkCost_string_string_string"
+)
+
+// rule is a compiled CELPolicyRule.
+type rule struct {
+	name    string
+	action  config.CELPolicyAction
+	score   int64
+	program cel.Program
+}
+
+// compileRules compiles and validates rules. costOracle provides the network costs
+// to networkCost(), which fails if nil.
+func compileRules(rules []config.CELPolicyRule, costOracle *costoracle.CostOracle) ([]rule, error) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar(podVar, decls.NewMapType(decls.String, decls.Dyn)),
+		decls.NewVar(nodeVar, decls.NewMapType(decls.String, decls.Dyn)),
+		decls.NewFunction(networkCostFunction, decls.NewOverload(networkCostOverload,
+			[]*exprpb.Type{decls.String, decls.String, decls.String}, decls.Int)),
+	))
+	if err != nil {
+		return nil, err
+	}
+	networkCost := &functions.Overload{
+		Operator: networkCostOverload,
+		Function: func(args ...ref.Val) ref.Val {
+			if costOracle == nil {
+				return types.NewErr("%s: no NetworkTopology configured", networkCostFunction)
+			}
+			key, origin, destination := v1alpha1.TopologyKey(args[0].(types.String)), string(args[1].(types.String)), string(args[2].(types.String))
+			if cost, ok := costOracle.GetCost(key, origin, destination); ok {
+				return types.Int(cost)
+			}
+			// Unknown links are assumed to be the most expensive ones.
+			return types.Int(costOracle.GetMaxCost(key))
+		},
+	}
+
+	names := make(map[string]bool, len(rules))
+	compiled := make([]rule, 0, len(rules))
+	for i, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rules[%d]: name is required", i)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("rules[%d]: duplicate name %q", i, r.Name)
+		}
+		names[r.Name] = true
+		switch r.Action {
+		case config.CELPolicyAllow, config.CELPolicyDeny:
+		case config.CELPolicyScore:
+			if r.Score < 0 || r.Score > framework.MaxNodeScore {
+				return nil, fmt.Errorf("rule %q: score %d out of range [0, %d]", r.Name, r.Score, framework.MaxNodeScore)
+			}
+		default:
+			return nil, fmt.Errorf("rule %q: unknown action %q", r.Name, r.Action)
+		}
+		ast, iss := env.Compile(r.Expression)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("rule %q: %v", r.Name, iss.Err())
+		}
+		if !proto.Equal(ast.ResultType(), decls.Bool) {
+			return nil, fmt.Errorf("rule %q: expression must evaluate to a bool", r.Name)
+		}
+		program, err := env.Program(ast, cel.Functions(networkCost))
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %v", r.Name, err)
+		}
+		compiled = append(compiled, rule{name: r.Name, action: r.Action, score: r.Score, program: program})
+	}
+	return compiled, nil
+}
+
+// matches returns whether the expression of r evaluates to true for vars.
+func (r *rule) matches(vars map[string]interface{}) (bool, error) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %v, not a bool", out)
+	}
+	return bool(matched), nil
+}
+
+// podVars returns the attributes of pod exposed to the rules.
+func podVars(pod *v1.Pod) map[string]interface{} {
+	var priority int64
+	if pod.Spec.Priority != nil {
+		priority = int64(*pod.Spec.Priority)
+	}
+	return map[string]interface{}{
+		"name":               pod.Name,
+		"namespace":          pod.Namespace,
+		"labels":             nonNil(pod.Labels),
+		"annotations":        nonNil(pod.Annotations),
+		"priority":           priority,
+		"serviceAccountName": pod.Spec.ServiceAccountName,
+	}
+}
+
+// nodeVars returns the attributes of node exposed to the rules.
+func nodeVars(node *v1.Node) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        node.Name,
+		"labels":      nonNil(node.Labels),
+		"annotations": nonNil(node.Annotations),
+	}
+}
+
+// nonNil returns m, or an empty map if nil, so that the rules can look up keys in it.
+func nonNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
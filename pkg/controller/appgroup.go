@@ -23,10 +23,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -35,7 +37,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
-	corelister "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -45,6 +46,7 @@ import (
 	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
 	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
 	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
 
@@ -58,17 +60,22 @@ type AppGroupController struct {
 	eventRecorder   record.EventRecorder
 	agQueue         workqueue.RateLimitingInterface
 	agLister        schedlister.AppGroupLister
-	podLister       corelister.PodLister
+	podIndexer      cache.Indexer
+	ntLister        schedlister.NetworkTopologyLister
 	agListerSynced  cache.InformerSynced
 	podListerSynced cache.InformerSynced
+	ntListerSynced  cache.InformerSynced
 	agClient        schedclientset.Interface
+	costSmoother    *costSmoother
 }
 
 // NewAppGroupController : returns a new *AppGroupController
 func NewAppGroupController(client kubernetes.Interface,
 	agInformer schedinformer.AppGroupInformer,
 	podInformer coreinformer.PodInformer,
-	agClient schedclientset.Interface) *AppGroupController {
+	ntInformer schedinformer.NetworkTopologyInformer,
+	agClient schedclientset.Interface,
+	smoothing CostSmoothing) *AppGroupController {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
 
@@ -85,17 +92,30 @@ func NewAppGroupController(client kubernetes.Interface,
 	})
 
 	klog.V(5).InfoS("Setting up Pod event handlers")
+	if err := util.AddAppGroupPodIndex(podInformer.Informer()); err != nil {
+		klog.ErrorS(err, "Unable to index pods by AppGroup")
+	}
 	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    ctrl.podAdded,
 		UpdateFunc: ctrl.podUpdated,
 		DeleteFunc: ctrl.podDeleted,
 	})
 
+	klog.V(5).InfoS("Setting up NetworkTopology event handlers")
+	ntInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.ntAdded,
+		UpdateFunc: ctrl.ntUpdated,
+		DeleteFunc: ctrl.ntDeleted,
+	})
+
 	ctrl.agLister = agInformer.Lister()
-	ctrl.podLister = podInformer.Lister()
+	ctrl.podIndexer = podInformer.Informer().GetIndexer()
+	ctrl.ntLister = ntInformer.Lister()
 	ctrl.agListerSynced = agInformer.Informer().HasSynced
 	ctrl.podListerSynced = podInformer.Informer().HasSynced
+	ctrl.ntListerSynced = ntInformer.Informer().HasSynced
 	ctrl.agClient = agClient
+	ctrl.costSmoother = newCostSmoother(smoothing)
 	return ctrl
 }
 
@@ -106,7 +126,7 @@ func (ctrl *AppGroupController) Run(workers int, stopCh <-chan struct{}) {
 	klog.InfoS("Starting App Group controller")
 	defer klog.InfoS("Shutting App Group controller")
 
-	if !cache.WaitForCacheSync(stopCh, ctrl.agListerSynced, ctrl.podListerSynced) {
+	if !cache.WaitForCacheSync(stopCh, ctrl.agListerSynced, ctrl.podListerSynced, ctrl.ntListerSynced) {
 		klog.Error("Cannot sync caches")
 		return
 	}
@@ -138,9 +158,20 @@ func (ctrl *AppGroupController) agAdded(obj interface{}) {
 	ctrl.agQueue.Add(key)
 }
 
-// agUpdated : reacts to a AppGroup update
+// agUpdated : reacts to a AppGroup update, re-sorting its workloads right away if its spec changed
 func (ctrl *AppGroupController) agUpdated(old, new interface{}) {
-	ctrl.agAdded(new)
+	oldAG, newAG := old.(*v1alpha1.AppGroup), new.(*v1alpha1.AppGroup)
+	if oldAG.Generation == newAG.Generation {
+		ctrl.agAdded(new)
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(new)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	klog.V(5).InfoS("Enqueue AppGroup whose spec changed", "appGroup", key, "generation", newAG.Generation)
+	ctrl.agQueue.Add(key)
 }
 
 // agDeleted : reacts to a AppGroup deletion
@@ -185,6 +216,68 @@ func (ctrl *AppGroupController) podUpdated(old, new interface{}) {
 	ctrl.podAdded(new)
 }
 
+// ntAdded : reacts to a NetworkTopology creation
+func (ctrl *AppGroupController) ntAdded(obj interface{}) {
+	ctrl.validateCostUnits(obj.(*v1alpha1.NetworkTopology))
+	ctrl.costSmoother.observe(obj.(*v1alpha1.NetworkTopology))
+	ctrl.enqueueNetworkTopologyAppGroups(obj)
+}
+
+// enqueueNetworkTopologyAppGroups : enqueues the AppGroups distributed across the zones of a NetworkTopology
+func (ctrl *AppGroupController) enqueueNetworkTopologyAppGroups(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ags, err := ctrl.agLister.AppGroups(namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing AppGroups", "networkTopology", key)
+		return
+	}
+	for _, ag := range ags {
+		if ag.Spec.ZoneDistribution != nil && ag.Spec.ZoneDistribution.NetworkTopologyName == name {
+			ctrl.agAdded(ag)
+		}
+	}
+}
+
+// ntUpdated : reacts to a NetworkTopology update, unless the smoothed costs in use did not change
+func (ctrl *AppGroupController) ntUpdated(old, new interface{}) {
+	nt := new.(*v1alpha1.NetworkTopology)
+	if !reflect.DeepEqual(old.(*v1alpha1.NetworkTopology).Spec.Weights, nt.Spec.Weights) {
+		ctrl.validateCostUnits(nt)
+	}
+	if !ctrl.costSmoother.observe(nt) {
+		klog.V(5).InfoS("Smoothed network costs unchanged", "networkTopology", klog.KObj(nt))
+		return
+	}
+	ctrl.enqueueNetworkTopologyAppGroups(new)
+}
+
+// validateCostUnits : warns about the weights of a NetworkTopology whose topologies declare different cost units
+func (ctrl *AppGroupController) validateCostUnits(nt *v1alpha1.NetworkTopology) {
+	for _, w := range nt.Spec.Weights {
+		if _, err := costoracle.WeightsCostUnit(w); err != nil {
+			klog.InfoS("Heterogeneous network cost units", "networkTopology", klog.KObj(nt), "weights", w.Name, "err", err)
+			ctrl.eventRecorder.Event(nt, v1.EventTypeWarning, "HeterogeneousCostUnits", err.Error())
+		}
+	}
+}
+
+// ntDeleted : reacts to a NetworkTopology deletion
+func (ctrl *AppGroupController) ntDeleted(obj interface{}) {
+	if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+		ctrl.costSmoother.forget(key)
+	}
+	ctrl.enqueueNetworkTopologyAppGroups(obj)
+}
+
 func (ctrl *AppGroupController) worker() {
 	for ctrl.processNextWorkItem() {
 	}
@@ -238,9 +331,7 @@ func (ctrl *AppGroupController) syncHandler(key string) error {
 	}
 
 	agCopy := ag.DeepCopy()
-	selector := labels.Set(map[string]string{v1alpha1.AppGroupLabel: agCopy.Name}).AsSelector()
-
-	pods, err := ctrl.podLister.List(selector)
+	pods, err := util.GetAppGroupPods(ctrl.podIndexer, agCopy.Namespace, agCopy.Name)
 	if err != nil {
 		klog.ErrorS(err, "List pods for App group failed", "AppGroup", klog.KObj(agCopy))
 		return err
@@ -259,10 +350,11 @@ func (ctrl *AppGroupController) syncHandler(key string) error {
 
 	agCopy.Status.RunningWorkloads = numWorkloadsRunning
 	klog.V(5).Info("RunningWorkloads: ", numWorkloadsRunning)
+	ctrl.fillDependenciesResolved(agCopy)
 
-	if agCopy.Status.TopologyCalculationTime.IsZero() {
-		klog.V(5).InfoS("Initial Calculation of Topology order...")
-		agCopy.Status.TopologyOrder, err = calculateTopologyOrder(agCopy, agCopy.Spec.TopologySortingAlgorithm, agCopy.Spec.Workloads, err)
+	if agCopy.Status.TopologyCalculationTime.IsZero() || agCopy.Status.ObservedGeneration != agCopy.Generation {
+		klog.V(5).InfoS("Calculation of Topology order for the current spec...", "generation", agCopy.Generation)
+		agCopy.Status.TopologyOrder, err = calculateTopologyOrder(agCopy, agCopy.Spec.TopologySortingAlgorithm, util.WithCanaryDependencies(agCopy.Spec.Workloads), err)
 		if err != nil {
 			klog.InfoS("Error Calculating Topology order, application reflects a DAG...", "appGroup", key)
 			agCopy.Status.TopologyOrder = defaultTopologyOrder(agCopy.Spec.Workloads)
@@ -271,13 +363,18 @@ func (ctrl *AppGroupController) syncHandler(key string) error {
 
 	} else if time.Now().Sub(ag.Status.TopologyCalculationTime.Time) > 24*time.Hour {
 		klog.InfoS("Recalculation of Topology Order... Every 24 hours...")
-		agCopy.Status.TopologyOrder, err = calculateTopologyOrder(agCopy, agCopy.Spec.TopologySortingAlgorithm, agCopy.Spec.Workloads, err)
+		agCopy.Status.TopologyOrder, err = calculateTopologyOrder(agCopy, agCopy.Spec.TopologySortingAlgorithm, util.WithCanaryDependencies(agCopy.Spec.Workloads), err)
 		if err != nil {
 			klog.InfoS("Error Calculating Topology order, application reflects a DAG...", "appGroup", key)
 			agCopy.Status.TopologyOrder = defaultTopologyOrder(agCopy.Spec.Workloads)
 		}
 		agCopy.Status.TopologyCalculationTime = metav1.Time{Time: time.Now()}
 	}
+	agCopy.Status.ObservedGeneration = agCopy.Generation
+	agCopy.Status.ZoneRecommendations, err = ctrl.zoneRecommendations(agCopy, pods)
+	if err != nil {
+		return err
+	}
 	klog.V(5).Info("ag to patch: ", agCopy)
 
 	err = ctrl.patchAppGroup(ag, agCopy)
@@ -287,16 +384,103 @@ func (ctrl *AppGroupController) syncHandler(key string) error {
 	return err
 }
 
-// patchAppGroup : patches the new info to the AppGroup
+// fillDependenciesResolved : sets the DependenciesResolved condition of the AppGroup, emitting a warning event
+// when it turns false, so that a dependency misspelled or with a negative demand is not silently ignored
+func (ctrl *AppGroupController) fillDependenciesResolved(ag *v1alpha1.AppGroup) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.AppGroupDependenciesResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ag.Generation,
+		Reason:             "DependenciesDeclared",
+		Message:            "every dependency references a workload of the AppGroup",
+	}
+	if problems := validateDependencies(ag); len(problems) != 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidDependencies"
+		condition.Message = strings.Join(problems, "; ")
+		if !meta.IsStatusConditionFalse(ag.Status.Conditions, condition.Type) {
+			ctrl.eventRecorder.Event(ag, v1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&ag.Status.Conditions, condition)
+}
+
+// validateDependencies : returns the problems of the dependencies of the AppGroup: references to workloads not
+// declared in the AppGroup, negative bandwidths and negative network costs, canaries of unknown workloads or of
+// other canaries
+func validateDependencies(ag *v1alpha1.AppGroup) []string {
+	declared := make(map[string]*v1alpha1.AppGroupWorkload, len(ag.Spec.Workloads))
+	for i, w := range ag.Spec.Workloads {
+		declared[w.Workload.Name] = &ag.Spec.Workloads[i]
+	}
+
+	var problems []string
+	for _, w := range ag.Spec.Workloads {
+		if w.Canary != nil {
+			primary := declared[w.Canary.Primary]
+			switch {
+			case primary == nil:
+				problems = append(problems, fmt.Sprintf("primary %s of canary workload %s is not a workload of the AppGroup", w.Canary.Primary, w.Workload.Name))
+			case primary.Canary != nil:
+				problems = append(problems, fmt.Sprintf("primary %s of canary workload %s is a canary itself", w.Canary.Primary, w.Workload.Name))
+			}
+		}
+		for _, d := range w.Dependencies {
+			prefix := fmt.Sprintf("dependency %s of workload %s", d.Workload.Name, w.Workload.Name)
+			if declared[d.Workload.Name] == nil {
+				problems = append(problems, prefix+" is not a workload of the AppGroup")
+			}
+			if d.MinBandwidth.Sign() < 0 {
+				problems = append(problems, prefix+" has a negative minBandwidth")
+			}
+			if d.MinIngressBandwidth != nil && d.MinIngressBandwidth.Sign() < 0 {
+				problems = append(problems, prefix+" has a negative minIngressBandwidth")
+			}
+			if d.MinEgressBandwidth != nil && d.MinEgressBandwidth.Sign() < 0 {
+				problems = append(problems, prefix+" has a negative minEgressBandwidth")
+			}
+			if d.MaxNetworkCost < 0 {
+				problems = append(problems, prefix+" has a negative maxNetworkCost")
+			}
+		}
+	}
+	return problems
+}
+
+// zoneRecommendations : computes the recommended per-zone replicas of the workloads of the AppGroup, if configured
+func (ctrl *AppGroupController) zoneRecommendations(ag *v1alpha1.AppGroup, pods []*v1.Pod) (v1alpha1.AppGroupZoneRecommendationList, error) {
+	zd := ag.Spec.ZoneDistribution
+	if zd == nil {
+		return nil, nil
+	}
+	nt, err := ctrl.ntLister.NetworkTopologies(ag.Namespace).Get(zd.NetworkTopologyName)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("NetworkTopology not found, no zone recommendation", "AppGroup", klog.KObj(ag), "networkTopology", zd.NetworkTopologyName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isShadowNetworkTopology(nt) {
+		klog.V(5).InfoS("NetworkTopology is a shadow, no zone recommendation", "AppGroup", klog.KObj(ag), "networkTopology", zd.NetworkTopologyName)
+		return nil, nil
+	}
+	weightsName, maxSkew := zoneDistributionParams(zd)
+	nt = ctrl.costSmoother.smoothed(nt)
+	return recommendZoneReplicas(ag, countWorkloadReplicas(pods), newZoneCostTable(nt, weightsName), maxSkew), nil
+}
+
+// patchAppGroup : patches the new status to the AppGroup, through its status subresource so that the generation
+// only counts the changes of the spec
 func (ctrl *AppGroupController) patchAppGroup(old, new *v1alpha1.AppGroup) error {
-	if !reflect.DeepEqual(old, new) {
-		patch, err := util.CreateMergePatch(old, new)
+	if !reflect.DeepEqual(old.Status, new.Status) {
+		patch, err := util.CreateMergePatch(&v1alpha1.AppGroup{Status: old.Status}, &v1alpha1.AppGroup{Status: new.Status})
 		if err != nil {
 			return err
 		}
 
 		_, err = ctrl.agClient.SchedulingV1alpha1().AppGroups(old.Namespace).Patch(context.TODO(), old.Name, types.MergePatchType,
-			patch, metav1.PatchOptions{})
+			patch, metav1.PatchOptions{}, "status")
 		if err != nil {
 			return err
 		}
@@ -320,6 +504,9 @@ func calculateTopologyOrder(agCopy *v1alpha1.AppGroup, algorithm string, workloa
 	klog.V(5).Info("Service Dependency Tree: ", tree)
 
 	// Calculate order based on the specified algorithm
+	if algorithm == "" {
+		algorithm = v1alpha1.AppGroupDefaultTopologySortingAlgorithm
+	}
 	switch algorithm {
 	case v1alpha1.AppGroupKahnSort:
 		klog.V(5).InfoS("Sorting Algorithm identified as KahnSort")
@@ -364,8 +551,7 @@ func calculateTopologyOrder(agCopy *v1alpha1.AppGroup, algorithm string, workloa
 			return topologyList, err
 		}
 	default: // Default
-		klog.V(5).Info("Sorting Algorithm not identified: ", agCopy.Spec.TopologySortingAlgorithm)
-		klog.V(5).InfoS("Default: KahnSort Selected...")
+		klog.V(2).InfoS("Sorting Algorithm not identified, KahnSort selected", "AppGroup", klog.KObj(agCopy), "algorithm", algorithm)
 		order, err = util.KahnSort(tree)
 		if err != nil {
 			klog.ErrorS(err, "KahnSort failed", "AppGroup", klog.KObj(agCopy))
@@ -0,0 +1,422 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsinformer "k8s.io/client-go/informers/apps/v1"
+	batchinformer "k8s.io/client-go/informers/batch/v1"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	appslister "k8s.io/client-go/listers/apps/v1"
+	batchlister "k8s.io/client-go/listers/batch/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+// AppGroupMembershipController : a controller labeling the ReplicaSets of the Deployments, the Jobs of the
+// CronJobs and the pods of the Deployments, Jobs and CronJobs referenced by an AppGroup with the AppGroup and
+// the workload selector, so that they are recognized as members even if their pod template lacks these labels
+type AppGroupMembershipController struct {
+	agQueue         workqueue.RateLimitingInterface
+	agLister        schedlister.AppGroupLister
+	rsLister        appslister.ReplicaSetLister
+	jobLister       batchlister.JobLister
+	podLister       corelister.PodLister
+	agListerSynced  cache.InformerSynced
+	rsListerSynced  cache.InformerSynced
+	jobListerSynced cache.InformerSynced
+	podListerSynced cache.InformerSynced
+	client          kubernetes.Interface
+}
+
+// NewAppGroupMembershipController : returns a new *AppGroupMembershipController
+func NewAppGroupMembershipController(client kubernetes.Interface,
+	agInformer schedinformer.AppGroupInformer,
+	rsInformer appsinformer.ReplicaSetInformer,
+	jobInformer batchinformer.JobInformer,
+	podInformer coreinformer.PodInformer) *AppGroupMembershipController {
+	ctrl := &AppGroupMembershipController{
+		agQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AppGroupMembership"),
+	}
+
+	klog.V(5).InfoS("Setting up AppGroup event handlers")
+	agInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.agAdded,
+		UpdateFunc: ctrl.agUpdated,
+	})
+
+	klog.V(5).InfoS("Setting up ReplicaSet event handlers")
+	rsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.rsAdded,
+		UpdateFunc: ctrl.rsUpdated,
+	})
+
+	klog.V(5).InfoS("Setting up Job event handlers")
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.jobAdded,
+		UpdateFunc: ctrl.jobUpdated,
+	})
+
+	klog.V(5).InfoS("Setting up Pod event handlers")
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.podAdded,
+		UpdateFunc: ctrl.podUpdated,
+	})
+
+	ctrl.agLister = agInformer.Lister()
+	ctrl.rsLister = rsInformer.Lister()
+	ctrl.jobLister = jobInformer.Lister()
+	ctrl.podLister = podInformer.Lister()
+	ctrl.agListerSynced = agInformer.Informer().HasSynced
+	ctrl.rsListerSynced = rsInformer.Informer().HasSynced
+	ctrl.jobListerSynced = jobInformer.Informer().HasSynced
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+	ctrl.client = client
+	return ctrl
+}
+
+// Run : starts listening on channel events
+func (ctrl *AppGroupMembershipController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.agQueue.ShutDown()
+
+	klog.InfoS("Starting App Group Membership controller")
+	defer klog.InfoS("Shutting App Group Membership controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.agListerSynced, ctrl.rsListerSynced, ctrl.jobListerSynced, ctrl.podListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	klog.InfoS("App Group Membership sync finished")
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// agAdded : reacts to a AppGroup creation
+func (ctrl *AppGroupMembershipController) agAdded(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ctrl.agQueue.Add(key)
+}
+
+// agUpdated : reacts to a AppGroup update
+func (ctrl *AppGroupMembershipController) agUpdated(old, new interface{}) {
+	ctrl.agAdded(new)
+}
+
+// rsAdded : reacts to a ReplicaSet creation, enqueuing the AppGroups referencing its Deployment
+func (ctrl *AppGroupMembershipController) rsAdded(obj interface{}) {
+	rs := obj.(*appsv1.ReplicaSet)
+	if _, ok := rs.Labels[v1alpha1.AppGroupLabel]; ok {
+		return
+	}
+	if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+		ctrl.enqueueWorkloadAppGroups(rs.Namespace, "Deployment", owner.Name)
+	}
+}
+
+// rsUpdated : reacts to a ReplicaSet update
+func (ctrl *AppGroupMembershipController) rsUpdated(old, new interface{}) {
+	ctrl.rsAdded(new)
+}
+
+// jobAdded : reacts to a Job creation, enqueuing the AppGroups referencing it or its CronJob
+func (ctrl *AppGroupMembershipController) jobAdded(obj interface{}) {
+	job := obj.(*batchv1.Job)
+	if _, ok := job.Labels[v1alpha1.AppGroupLabel]; ok {
+		return
+	}
+	ctrl.enqueueJobAppGroups(job)
+}
+
+// jobUpdated : reacts to a Job update
+func (ctrl *AppGroupMembershipController) jobUpdated(old, new interface{}) {
+	ctrl.jobAdded(new)
+}
+
+// enqueueJobAppGroups : enqueues the AppGroups referencing a Job or its CronJob
+func (ctrl *AppGroupMembershipController) enqueueJobAppGroups(job *batchv1.Job) {
+	ctrl.enqueueWorkloadAppGroups(job.Namespace, "Job", job.Name)
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+		ctrl.enqueueWorkloadAppGroups(job.Namespace, "CronJob", owner.Name)
+	}
+}
+
+// podAdded : reacts to a pod creation, enqueuing the AppGroups referencing the Deployment of its ReplicaSet,
+// or its Job or the CronJob of its Job
+func (ctrl *AppGroupMembershipController) podAdded(obj interface{}) {
+	pod := obj.(*v1.Pod)
+	if _, ok := pod.Labels[v1alpha1.AppGroupLabel]; ok {
+		return
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return
+	}
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := ctrl.rsLister.ReplicaSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			klog.V(5).InfoS("ReplicaSet of pod not found", "pod", klog.KObj(pod), "replicaSet", owner.Name)
+			return
+		}
+		if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+			ctrl.enqueueWorkloadAppGroups(pod.Namespace, "Deployment", owner.Name)
+		}
+	case "Job":
+		job, err := ctrl.jobLister.Jobs(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			klog.V(5).InfoS("Job of pod not found", "pod", klog.KObj(pod), "job", owner.Name)
+			return
+		}
+		ctrl.enqueueJobAppGroups(job)
+	}
+}
+
+// podUpdated : reacts to a pod update
+func (ctrl *AppGroupMembershipController) podUpdated(old, new interface{}) {
+	ctrl.podAdded(new)
+}
+
+// enqueueWorkloadAppGroups : enqueues the AppGroups referencing a workload of the given kind as one of their workloads
+func (ctrl *AppGroupMembershipController) enqueueWorkloadAppGroups(namespace, kind, name string) {
+	ags, err := ctrl.agLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing AppGroups")
+		return
+	}
+	for _, ag := range ags {
+		for _, w := range ag.Spec.Workloads {
+			if isWorkload(ag, w.Workload, kind, namespace, name) {
+				ctrl.agAdded(ag)
+				break
+			}
+		}
+	}
+}
+
+// isWorkload : tells whether a workload of an AppGroup is the given workload
+func isWorkload(ag *v1alpha1.AppGroup, w v1alpha1.AppGroupWorkloadInfo, kind, namespace, name string) bool {
+	return w.Kind == kind && w.Name == name && workloadNamespace(ag, w) == namespace
+}
+
+// workloadNamespace : returns the namespace of a workload, the one of its AppGroup if not specified
+func workloadNamespace(ag *v1alpha1.AppGroup, w v1alpha1.AppGroupWorkloadInfo) string {
+	if w.Namespace != "" {
+		return w.Namespace
+	}
+	return ag.Namespace
+}
+
+func (ctrl *AppGroupMembershipController) worker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem : deals with one key off the queue.  It returns false when it's time to quit.
+func (ctrl *AppGroupMembershipController) processNextWorkItem() bool {
+	keyObj, quit := ctrl.agQueue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.agQueue.Done(keyObj)
+
+	key, ok := keyObj.(string)
+	if !ok {
+		ctrl.agQueue.Forget(keyObj)
+		runtime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", keyObj))
+		return true
+	}
+	if err := ctrl.syncHandler(key); err != nil {
+		runtime.HandleError(err)
+		klog.ErrorS(err, "Error syncing app group membership", "appGroup", key)
+		ctrl.agQueue.AddRateLimited(key)
+		return true
+	}
+	ctrl.agQueue.Forget(keyObj)
+	return true
+}
+
+// syncHandler : labels the members of the Deployments, Jobs and CronJobs of an AppGroup
+func (ctrl *AppGroupMembershipController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+	ag, err := ctrl.agLister.AppGroups(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("App group has been deleted", "appGroup", key)
+		return nil
+	}
+	if err != nil {
+		klog.V(3).ErrorS(err, "Unable to retrieve app group from store", "appGroup", key)
+		return err
+	}
+
+	for _, w := range ag.Spec.Workloads {
+		var err error
+		switch w.Workload.Kind {
+		case "Deployment":
+			err = ctrl.labelDeploymentMembers(ag, w.Workload)
+		case "Job", "CronJob":
+			err = ctrl.labelJobMembers(ag, w.Workload)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workloadMembership : returns the labels making an object a member of a workload of an AppGroup
+func workloadMembership(ag *v1alpha1.AppGroup, w v1alpha1.AppGroupWorkloadInfo) map[string]string {
+	membership := map[string]string{v1alpha1.AppGroupLabel: ag.Name}
+	if w.Selector != "" {
+		membership[v1alpha1.AppGroupSelectorLabel] = w.Selector
+	}
+	return membership
+}
+
+// labelDeploymentMembers : labels the ReplicaSets of a Deployment workload and their pods
+func (ctrl *AppGroupMembershipController) labelDeploymentMembers(ag *v1alpha1.AppGroup, w v1alpha1.AppGroupWorkloadInfo) error {
+	namespace := workloadNamespace(ag, w)
+	membership := workloadMembership(ag, w)
+
+	rss, err := ctrl.rsLister.ReplicaSets(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	replicaSets := make(map[types.UID]bool)
+	for _, rs := range rss {
+		if owner := metav1.GetControllerOf(rs); owner == nil || owner.Kind != "Deployment" || owner.Name != w.Name {
+			continue
+		}
+		replicaSets[rs.UID] = true
+		if patch := membershipPatch(rs.Labels, membership); patch != nil {
+			klog.V(4).InfoS("Labeling ReplicaSet with its AppGroup", "replicaSet", klog.KObj(rs), "AppGroup", klog.KObj(ag))
+			if _, err := ctrl.client.AppsV1().ReplicaSets(namespace).Patch(context.TODO(), rs.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return ctrl.labelPods(ag, namespace, replicaSets, membership)
+}
+
+// labelJobMembers : labels a Job workload and its pods, or the Jobs of a CronJob workload and their pods
+func (ctrl *AppGroupMembershipController) labelJobMembers(ag *v1alpha1.AppGroup, w v1alpha1.AppGroupWorkloadInfo) error {
+	namespace := workloadNamespace(ag, w)
+	membership := workloadMembership(ag, w)
+
+	allJobs, err := ctrl.jobLister.Jobs(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	jobs := make(map[types.UID]bool)
+	for _, job := range allJobs {
+		if !isWorkloadJob(job, w) {
+			continue
+		}
+		jobs[job.UID] = true
+		if patch := membershipPatch(job.Labels, membership); patch != nil {
+			klog.V(4).InfoS("Labeling Job with its AppGroup", "job", klog.KObj(job), "AppGroup", klog.KObj(ag))
+			if _, err := ctrl.client.BatchV1().Jobs(namespace).Patch(context.TODO(), job.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return ctrl.labelPods(ag, namespace, jobs, membership)
+}
+
+// isWorkloadJob : tells whether a Job is the given Job workload or was created by the given CronJob workload
+func isWorkloadJob(job *batchv1.Job, w v1alpha1.AppGroupWorkloadInfo) bool {
+	if w.Kind == "Job" {
+		return job.Name == w.Name
+	}
+	owner := metav1.GetControllerOf(job)
+	return owner != nil && owner.Kind == "CronJob" && owner.Name == w.Name
+}
+
+// labelPods : labels the pods of a namespace controlled by the given owners
+func (ctrl *AppGroupMembershipController) labelPods(ag *v1alpha1.AppGroup, namespace string, owners map[types.UID]bool, membership map[string]string) error {
+	if len(owners) == 0 {
+		return nil
+	}
+	pods, err := ctrl.podLister.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if owner := metav1.GetControllerOf(pod); owner == nil || !owners[owner.UID] {
+			continue
+		}
+		if patch := membershipPatch(pod.Labels, membership); patch != nil {
+			klog.V(4).InfoS("Labeling pod with its AppGroup", "pod", klog.KObj(pod), "AppGroup", klog.KObj(ag))
+			if _, err := ctrl.client.CoreV1().Pods(namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// membershipPatch : returns a merge patch adding the membership labels missing from current, nil if
+// none is. Labels already set are left untouched, and objects labeled with another AppGroup skipped:
+// an object never changes AppGroup.
+func membershipPatch(current, wanted map[string]string) []byte {
+	if ag, ok := current[v1alpha1.AppGroupLabel]; ok && ag != wanted[v1alpha1.AppGroupLabel] {
+		return nil
+	}
+	missing := make(map[string]string)
+	for k, v := range wanted {
+		if _, ok := current[k]; !ok {
+			missing[k] = v
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": missing},
+	})
+	return patch
+}
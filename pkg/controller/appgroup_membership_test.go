@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestAppGroupMembershipController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	controlledBy := func(kind, name string, uid types.UID) []metav1.OwnerReference {
+		apiVersion := "apps/v1"
+		if kind == "Job" || kind == "CronJob" {
+			apiVersion = "batch/v1"
+		}
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: uid, Controller: pointer.Bool(true)}}
+	}
+	frontendRS := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "frontend-1", Namespace: "default", UID: "rs-1", OwnerReferences: controlledBy("Deployment", "frontend", "d-1")}}
+	otherRS := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "other-1", Namespace: "default", UID: "rs-2", OwnerReferences: controlledBy("Deployment", "other", "d-2")}}
+	frontendPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "frontend-1-a", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "frontend-1", "rs-1")}}
+	// Already member of another AppGroup, left untouched.
+	labeledPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "frontend-1-b", Namespace: "default", Labels: map[string]string{v1alpha1.AppGroupLabel: "legacy"},
+		OwnerReferences: controlledBy("ReplicaSet", "frontend-1", "rs-1")}}
+	otherPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "other-1-a", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "other-1", "rs-2")}}
+	migrateJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default", UID: "job-1"}}
+	migratePod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "migrate-a", Namespace: "default", OwnerReferences: controlledBy("Job", "migrate", "job-1")}}
+	reportJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name: "report-1", Namespace: "default", UID: "job-2", OwnerReferences: controlledBy("CronJob", "report", "cj-1")}}
+	reportPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "report-1-a", Namespace: "default", OwnerReferences: controlledBy("Job", "report-1", "job-2")}}
+	kubeClient := fake.NewSimpleClientset(frontendRS, otherRS, frontendPod, labeledPod, otherPod,
+		migrateJob, migratePod, reportJob, reportPod)
+
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "frontend", Selector: "P1", APIVersion: "apps/v1"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Kind: "Job", Name: "migrate", Selector: "P2", APIVersion: "batch/v1"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Kind: "CronJob", Name: "report", Selector: "P3", APIVersion: "batch/v1"}},
+		}},
+	}
+	schedClient := schedfake.NewSimpleClientset(ag)
+
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	ctrl := NewAppGroupMembershipController(kubeClient, schedInformerFactory.Scheduling().V1alpha1().AppGroups(),
+		informerFactory.Apps().V1().ReplicaSets(), informerFactory.Batch().V1().Jobs(), informerFactory.Core().V1().Pods())
+	informerFactory.Start(ctx.Done())
+	schedInformerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	member := map[string]string{v1alpha1.AppGroupLabel: "shop", v1alpha1.AppGroupSelectorLabel: "P1"}
+	err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		rs, err := kubeClient.AppsV1().ReplicaSets("default").Get(ctx, frontendRS.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, frontendPod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return reflect.DeepEqual(rs.Labels, member) && reflect.DeepEqual(pod.Labels, member), nil
+	})
+	if err != nil {
+		t.Fatalf("ReplicaSet and pod of the Deployment not labeled with %v: %v", member, err)
+	}
+
+	// Jobs are labeled before their pods.
+	jobMembers := map[string]map[string]string{
+		migratePod.Name: {v1alpha1.AppGroupLabel: "shop", v1alpha1.AppGroupSelectorLabel: "P2"},
+		reportPod.Name:  {v1alpha1.AppGroupLabel: "shop", v1alpha1.AppGroupSelectorLabel: "P3"},
+	}
+	err = wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		for name, want := range jobMembers {
+			pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+			if err != nil || !reflect.DeepEqual(pod.Labels, want) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Pods of the Job and the CronJob not labeled with %v: %v", jobMembers, err)
+	}
+	for name, want := range map[string]map[string]string{migrateJob.Name: jobMembers[migratePod.Name], reportJob.Name: jobMembers[reportPod.Name]} {
+		job, err := kubeClient.BatchV1().Jobs("default").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(job.Labels, want) {
+			t.Errorf("Job %s: expected labels %v, got %v", name, want, job.Labels)
+		}
+	}
+
+	expected := map[string]map[string]string{
+		labeledPod.Name: {v1alpha1.AppGroupLabel: "legacy"},
+		otherPod.Name:   nil,
+	}
+	for name, want := range expected {
+		pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pod.Labels, want) {
+			t.Errorf("pod %s: expected labels %v, got %v", name, want, pod.Labels)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// ZonePlacementPlan is the placement of the workloads of an AppGroup across the zones of a
+// NetworkTopology, computed offline with the cost model of the controller.
+type ZonePlacementPlan struct {
+	// Zones are the zones replicas may be placed in, sorted.
+	Zones []string
+	// Recommendations are the replicas per zone the controller would recommend, by selector.
+	Recommendations v1alpha1.AppGroupZoneRecommendationList
+	// Costs are the network costs of every workload to its dependencies and dependents under
+	// Recommendations, keyed by selector.
+	Costs map[string]int64
+	// CandidateCosts are the network costs of one more replica of every workload in every zone
+	// of Zones, given Recommendations, keyed by selector then zone.
+	CandidateCosts map[string]map[string]int64
+}
+
+// PlanZonePlacement returns the placement of the replicas of the workloads of ag, keyed by selector,
+// across the zones of the weights weightsName of nt, keeping the skew of every workload within maxSkew,
+// the way the AppGroup controller recommends it. The topology order of ag is computed if its status has none.
+func PlanZonePlacement(ag *v1alpha1.AppGroup, nt *v1alpha1.NetworkTopology, weightsName string, replicas map[string]int32, maxSkew int32) *ZonePlacementPlan {
+	if maxSkew <= 0 {
+		maxSkew = defaultZoneMaxSkew
+	}
+	if len(ag.Status.TopologyOrder) == 0 {
+		ag = ag.DeepCopy()
+		order, err := calculateTopologyOrder(ag, ag.Spec.TopologySortingAlgorithm, util.WithCanaryDependencies(ag.Spec.Workloads), nil)
+		if err != nil {
+			order = defaultTopologyOrder(ag.Spec.Workloads)
+		}
+		ag.Status.TopologyOrder = order
+	}
+	table := newZoneCostTable(nt, weightsName)
+	recs := recommendZoneReplicas(ag, replicas, table, maxSkew)
+	plan := &ZonePlacementPlan{
+		Zones:           table.zones,
+		Recommendations: recs,
+		Costs:           placementCosts(ag, recs, table),
+		CandidateCosts:  make(map[string]map[string]int64, len(ag.Spec.Workloads)),
+	}
+
+	zones := zonesBySelector(recs)
+	neighbors := workloadNeighbors(ag)
+	for _, w := range ag.Spec.Workloads {
+		selector := w.Workload.Selector
+		costs := make(map[string]int64, len(table.zones))
+		for _, zone := range table.zones {
+			var cost int64
+			for n, weight := range neighbors[selector] {
+				for _, to := range zones[n.selector] {
+					cost += weight * int64(to.Replicas) * table.cost(n.interfaceClass, zone, to.Zone)
+				}
+			}
+			costs[zone] = cost
+		}
+		plan.CandidateCosts[selector] = costs
+	}
+	return plan
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestPlanZonePlacement(t *testing.T) {
+	// Zones z1 and z2 are close, z3 is far from both.
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+						{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	// The topology order is left to compute, as in an AppGroup spec not deployed yet.
+	ag := makeAG("ag", 4, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+		{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2}}},
+		{Workload: p2},
+	}, nil)
+
+	plan := PlanZonePlacement(ag, nt, "UserDefined", map[string]int32{"P1": 2, "P2": 2}, 2)
+
+	if expected := []string{"z1", "z2", "z3"}; !reflect.DeepEqual(expected, plan.Zones) {
+		t.Errorf("expected zones %v, got %v", expected, plan.Zones)
+	}
+	expectedRecs := v1alpha1.AppGroupZoneRecommendationList{
+		{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 2}}},
+		{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 2}}},
+	}
+	if !reflect.DeepEqual(expectedRecs, plan.Recommendations) {
+		t.Errorf("expected recommendations %v, got %v", expectedRecs, plan.Recommendations)
+	}
+	if expected := map[string]int64{"P1": 0, "P2": 0}; !reflect.DeepEqual(expected, plan.Costs) {
+		t.Errorf("expected costs %v, got %v", expected, plan.Costs)
+	}
+	// One more replica costs its distance to the two replicas of the other workload.
+	expectedCandidates := map[string]map[string]int64{
+		"P1": {"z1": 0, "z2": 2, "z3": 20},
+		"P2": {"z1": 0, "z2": 2, "z3": 20},
+	}
+	if !reflect.DeepEqual(expectedCandidates, plan.CandidateCosts) {
+		t.Errorf("expected candidate costs %v, got %v", expectedCandidates, plan.CandidateCosts)
+	}
+	if len(ag.Status.TopologyOrder) != 0 || ag.Spec.Workloads[0].Workload.Selector != "P1" {
+		t.Errorf("expected the AppGroup given to be left unmodified, got %v", ag)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// ShadowComparePath is the path the shadow NetworkTopology comparison is served at.
+const ShadowComparePath = "/debug/networktopologies/compare"
+
+// WorkloadPlacementDelta compares the expected placement of a workload under the live
+// NetworkTopology and under its shadow.
+type WorkloadPlacementDelta struct {
+	AppGroup string `json:"appGroup"`
+	Workload string `json:"workload"`
+	// LiveZones and ShadowZones are the recommended replicas per zone.
+	LiveZones   v1alpha1.ZoneReplicasList `json:"liveZones,omitempty"`
+	ShadowZones v1alpha1.ZoneReplicasList `json:"shadowZones,omitempty"`
+	// LiveCost and ShadowCost are the network costs to the dependencies of the workload,
+	// weighted by the dependency weights and the numbers of replicas.
+	LiveCost   int64 `json:"liveCost"`
+	ShadowCost int64 `json:"shadowCost"`
+	// Delta is ShadowCost minus LiveCost, negative if the shadow topology improves the placement.
+	Delta int64 `json:"delta"`
+}
+
+// isShadowNetworkTopology tells whether nt is a shadow NetworkTopology.
+func isShadowNetworkTopology(nt *v1alpha1.NetworkTopology) bool {
+	_, ok := nt.Annotations[v1alpha1.NetworkTopologyShadowOfAnnotation]
+	return ok
+}
+
+// CompareShadowNetworkTopology compares the zone recommendations of the AppGroups distributed
+// across the live NetworkTopology shadowed by namespace/name with the ones the shadow would give.
+func (ctrl *AppGroupController) CompareShadowNetworkTopology(namespace, name string) ([]WorkloadPlacementDelta, error) {
+	shadow, err := ctrl.ntLister.NetworkTopologies(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	liveName, ok := shadow.Annotations[v1alpha1.NetworkTopologyShadowOfAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("NetworkTopology %s/%s is not a shadow", namespace, name)
+	}
+	live, err := ctrl.ntLister.NetworkTopologies(namespace).Get(liveName)
+	if err != nil {
+		return nil, err
+	}
+	ags, err := ctrl.agLister.AppGroups(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ags, func(i, j int) bool { return ags[i].Name < ags[j].Name })
+
+	var deltas []WorkloadPlacementDelta
+	for _, ag := range ags {
+		zd := ag.Spec.ZoneDistribution
+		if zd == nil || zd.NetworkTopologyName != liveName {
+			continue
+		}
+		pods, err := util.GetAppGroupPods(ctrl.podIndexer, namespace, ag.Name)
+		if err != nil {
+			return nil, err
+		}
+		replicas := countWorkloadReplicas(pods)
+		weightsName, maxSkew := zoneDistributionParams(zd)
+		liveTable, shadowTable := newZoneCostTable(live, weightsName), newZoneCostTable(shadow, weightsName)
+		liveRecs := recommendZoneReplicas(ag, replicas, liveTable, maxSkew)
+		shadowRecs := recommendZoneReplicas(ag, replicas, shadowTable, maxSkew)
+		liveCosts, shadowCosts := placementCosts(ag, liveRecs, liveTable), placementCosts(ag, shadowRecs, shadowTable)
+
+		liveZones, shadowZones := zonesBySelector(liveRecs), zonesBySelector(shadowRecs)
+		for _, w := range ag.Spec.Workloads {
+			selector := w.Workload.Selector
+			deltas = append(deltas, WorkloadPlacementDelta{
+				AppGroup:    ag.Name,
+				Workload:    selector,
+				LiveZones:   liveZones[selector],
+				ShadowZones: shadowZones[selector],
+				LiveCost:    liveCosts[selector],
+				ShadowCost:  shadowCosts[selector],
+				Delta:       shadowCosts[selector] - liveCosts[selector],
+			})
+		}
+	}
+	return deltas, nil
+}
+
+// ShadowCompareHandler serves the comparison of the shadow NetworkTopology given by the namespace
+// and name query parameters as JSON.
+func (ctrl *AppGroupController) ShadowCompareHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace, name := r.URL.Query().Get("namespace"), r.URL.Query().Get("name")
+		if namespace == "" || name == "" {
+			http.Error(w, "namespace and name query parameters are required", http.StatusBadRequest)
+			return
+		}
+		deltas, err := ctrl.CompareShadowNetworkTopology(namespace, name)
+		if apierrs.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(deltas); err != nil {
+			klog.ErrorS(err, "Failed to encode shadow NetworkTopology comparison")
+		}
+	})
+}
+
+// zonesBySelector returns the recommended zones keyed by workload selector.
+func zonesBySelector(recs v1alpha1.AppGroupZoneRecommendationList) map[string]v1alpha1.ZoneReplicasList {
+	zones := make(map[string]v1alpha1.ZoneReplicasList, len(recs))
+	for _, r := range recs {
+		zones[r.Workload.Selector] = r.Zones
+	}
+	return zones
+}
+
+// placementCosts returns the network cost of every workload of ag to its dependencies and
+// dependents when placed after recs, keyed by selector.
+func placementCosts(ag *v1alpha1.AppGroup, recs v1alpha1.AppGroupZoneRecommendationList, table *zoneCostTable) map[string]int64 {
+	zones := zonesBySelector(recs)
+	costs := make(map[string]int64)
+	for selector, neighbors := range workloadNeighbors(ag) {
+		for n, weight := range neighbors {
+			for _, from := range zones[selector] {
+				for _, to := range zones[n.selector] {
+					costs[selector] += weight * int64(from.Replicas) * int64(to.Replicas) * table.cost(n.interfaceClass, from.Zone, to.Zone)
+				}
+			}
+		}
+	}
+	return costs
+}
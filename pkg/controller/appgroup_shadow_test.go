@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	agfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func makeZoneTopology(name string, z1z3Cost int64, annotations map[string]string) *v1alpha1.NetworkTopology {
+	return &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 10}, {Destination: "z3", NetworkCost: z1z3Cost}}},
+						{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func TestCompareShadowNetworkTopology(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	ag := makeAG("ag", 4, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+		{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2}}},
+		{Workload: p2},
+	}, nil)
+	ag.Spec.ZoneDistribution = &v1alpha1.AppGroupZoneDistribution{NetworkTopologyName: "live"}
+	// The shadow upgrades the link between z1 and z3.
+	live := makeZoneTopology("live", 5, nil)
+	shadow := makeZoneTopology("shadow", 1, map[string]string{v1alpha1.NetworkTopologyShadowOfAnnotation: "live"})
+
+	var kubeObjs []runtime.Object
+	for _, pod := range makePodsAppGroup([]string{"P1", "P1", "P2", "P2"}, []string{"a", "b", "c", "d"}, "ag", "Running") {
+		kubeObjs = append(kubeObjs, pod)
+	}
+	kubeClient := fake.NewSimpleClientset(kubeObjs...)
+	agClient := agfake.NewSimpleClientset(ag, live, shadow)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	agInformerFactory := schedinformer.NewSharedInformerFactory(agClient, controller.NoResyncPeriodFunc())
+	ctrl := NewAppGroupController(kubeClient, agInformerFactory.Scheduling().V1alpha1().AppGroups(),
+		informerFactory.Core().V1().Pods(), agInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), agClient, CostSmoothing{})
+	agInformerFactory.Start(ctx.Done())
+	informerFactory.Start(ctx.Done())
+	agInformerFactory.WaitForCacheSync(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	expected := []WorkloadPlacementDelta{
+		{
+			AppGroup:    "ag",
+			Workload:    "P1",
+			LiveZones:   v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}},
+			ShadowZones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}},
+			LiveCost:    10,
+			ShadowCost:  2,
+			Delta:       -8,
+		},
+		{
+			AppGroup:    "ag",
+			Workload:    "P2",
+			LiveZones:   v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}},
+			ShadowZones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}},
+			LiveCost:    10,
+			ShadowCost:  2,
+			Delta:       -8,
+		},
+	}
+	got, err := ctrl.CompareShadowNetworkTopology("default", "shadow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if _, err := ctrl.CompareShadowNetworkTopology("default", "live"); err == nil {
+		t.Errorf("expected an error comparing a NetworkTopology which is not a shadow")
+	}
+
+	// Shadows never drive the published recommendations.
+	agShadow := ag.DeepCopy()
+	agShadow.Spec.ZoneDistribution.NetworkTopologyName = "shadow"
+	if recs, err := ctrl.zoneRecommendations(agShadow, nil); err != nil || recs != nil {
+		t.Errorf("expected no recommendation from a shadow NetworkTopology, got %v (err: %v)", recs, err)
+	}
+
+	server := httptest.NewServer(ctrl.ShadowCompareHandler())
+	defer server.Close()
+	for _, tt := range []struct {
+		query  string
+		status int
+	}{
+		{query: "", status: http.StatusBadRequest},
+		{query: "?namespace=default&name=unknown", status: http.StatusNotFound},
+		{query: "?namespace=default&name=live", status: http.StatusBadRequest},
+		{query: "?namespace=default&name=shadow", status: http.StatusOK},
+	} {
+		resp, err := http.Get(server.URL + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%q: expected status %v, got %v", tt.query, tt.status, resp.StatusCode)
+		}
+		if resp.StatusCode == http.StatusOK {
+			var deltas []WorkloadPlacementDelta
+			if err := json.NewDecoder(resp.Body).Decode(&deltas); err != nil || !reflect.DeepEqual(expected, deltas) {
+				t.Errorf("expected %v, got %v (err: %v)", expected, deltas, err)
+			}
+		}
+		resp.Body.Close()
+	}
+}
@@ -19,14 +19,17 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/kubernetes/pkg/controller"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 
@@ -200,6 +203,18 @@ func TestAppGroupController_Run(t *testing.T) {
 			workloadList:             onlineBoutique,
 			desiredTopologyOrder:     onlineBoutiqueTopologyOrderAlternateKahn,
 		},
+		{
+			name:       "AppGroup Online Boutique - default algorithm",
+			agName:     "onlineBoutique",
+			numMembers: 11,
+			selectors:  []string{"P1", "P2", "P3", "P4", "P5", "P6", "P7", "P8", "P9", "P10", "P11"},
+			deploymentNames: []string{"P1-deployment", "P2-deployment", "P3-deployment", "P4-deployment", "P5-deployment",
+				"P6-deployment", "P7-deployment", "P8-deployment", "P9-deployment", "P10-deployment", "P11-deployment"},
+			desiredRunningWorkloads: 11,
+			podPhase:                v1.PodRunning,
+			workloadList:            onlineBoutique,
+			desiredTopologyOrder:    onlineBoutiqueTopologyOrderKahn,
+		},
 	}
 
 	for _, c := range cases {
@@ -221,8 +236,9 @@ func TestAppGroupController_Run(t *testing.T) {
 			agInformerFactory := schedinformer.NewSharedInformerFactory(agClient, controller.NoResyncPeriodFunc())
 			podInformer := informerFactory.Core().V1().Pods()
 			agInformer := agInformerFactory.Scheduling().V1alpha1().AppGroups()
+			ntInformer := agInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
 
-			ctrl := NewAppGroupController(kubeClient, agInformer, podInformer, agClient)
+			ctrl := NewAppGroupController(kubeClient, agInformer, podInformer, ntInformer, agClient, CostSmoothing{})
 
 			agInformerFactory.Start(ctx.Done())
 			informerFactory.Start(ctx.Done())
@@ -257,6 +273,132 @@ func TestAppGroupController_Run(t *testing.T) {
 	}
 }
 
+func TestAppGroupController_SpecChange(t *testing.T) {
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	// Created long ago, the AppGroup is not enqueued on updates of its status any more.
+	created := metav1.NewTime(time.Now().Add(-3 * timeLimitation))
+	old := makeAG("ag", 2, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+		{Workload: p1},
+		{Workload: p2, Dependencies: v1alpha1.DependenciesList{{Workload: p1}}},
+	}, &created)
+	old.Generation = 1
+	old.Status.ObservedGeneration = 1
+	old.Status.TopologyCalculationTime = metav1.Now()
+	old.Status.TopologyOrder = v1alpha1.AppGroupTopologyList{{Workload: p1, Index: 2}, {Workload: p2, Index: 1}}
+
+	// The dependency is reversed, the order computed an hour ago does not hold any more.
+	ag := old.DeepCopy()
+	ag.Generation = 2
+	ag.Spec.Workloads = v1alpha1.AppGroupWorkloadList{
+		{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2}}},
+		{Workload: p2},
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	agClient := agfake.NewSimpleClientset(ag)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	agInformerFactory := schedinformer.NewSharedInformerFactory(agClient, controller.NoResyncPeriodFunc())
+	agInformer := agInformerFactory.Scheduling().V1alpha1().AppGroups()
+	ctrl := NewAppGroupController(kubeClient, agInformer, informerFactory.Core().V1().Pods(),
+		agInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), agClient, CostSmoothing{})
+	agInformer.Informer().GetIndexer().Add(ag)
+
+	ctrl.agUpdated(old, old.DeepCopy())
+	if ctrl.agQueue.Len() != 0 {
+		t.Fatalf("want no sync of the old AppGroup without spec change, got %v queued", ctrl.agQueue.Len())
+	}
+	ctrl.agUpdated(old, ag)
+	if ctrl.agQueue.Len() != 1 {
+		t.Fatalf("want the AppGroup queued on spec change, got %v queued", ctrl.agQueue.Len())
+	}
+	if !ctrl.processNextWorkItem() {
+		t.Fatal("want the AppGroup synced")
+	}
+
+	got, err := agClient.SchedulingV1alpha1().AppGroups("default").Get(context.TODO(), "ag", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ObservedGeneration != 2 {
+		t.Errorf("want observedGeneration 2, got %v", got.Status.ObservedGeneration)
+	}
+	want := v1alpha1.AppGroupTopologyList{{Workload: p1, Index: 1}, {Workload: p2, Index: 2}}
+	if !reflect.DeepEqual(want, got.Status.TopologyOrder) {
+		t.Errorf("want the workloads sorted again %v, got %v", want, got.Status.TopologyOrder)
+	}
+	for _, action := range agClient.Actions() {
+		if action.GetVerb() == "patch" && action.GetSubresource() != "status" {
+			t.Errorf("want the status patched through the status subresource, got %v", action)
+		}
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	workload := func(name string) v1alpha1.AppGroupWorkloadInfo {
+		return v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: name, Selector: name, APIVersion: "apps/v1", Namespace: "default"}
+	}
+	negative := resource.MustParse("-10Mi")
+
+	cases := []struct {
+		name         string
+		workloads    v1alpha1.AppGroupWorkloadList
+		wantProblems []string
+	}{
+		{
+			name: "dependencies declared",
+			workloads: v1alpha1.AppGroupWorkloadList{
+				{Workload: workload("P1"), Dependencies: v1alpha1.DependenciesList{
+					{Workload: workload("P2"), MinBandwidth: resource.MustParse("100Mi"), MaxNetworkCost: 10}}},
+				{Workload: workload("P2")},
+			},
+		},
+		{
+			name: "dependency not declared",
+			workloads: v1alpha1.AppGroupWorkloadList{
+				{Workload: workload("P1"), Dependencies: v1alpha1.DependenciesList{{Workload: workload("P3")}}},
+				{Workload: workload("P2")},
+			},
+			wantProblems: []string{"dependency P3 of workload P1 is not a workload of the AppGroup"},
+		},
+		{
+			name: "negative demands",
+			workloads: v1alpha1.AppGroupWorkloadList{
+				{Workload: workload("P1"), Dependencies: v1alpha1.DependenciesList{
+					{Workload: workload("P2"), MinBandwidth: negative, MinEgressBandwidth: &negative, MaxNetworkCost: -1}}},
+				{Workload: workload("P2")},
+			},
+			wantProblems: []string{
+				"dependency P2 of workload P1 has a negative minBandwidth",
+				"dependency P2 of workload P1 has a negative minEgressBandwidth",
+				"dependency P2 of workload P1 has a negative maxNetworkCost",
+			},
+		},
+		{
+			name: "canaries",
+			workloads: v1alpha1.AppGroupWorkloadList{
+				{Workload: workload("P1")},
+				{Workload: workload("P1-canary"), Canary: &v1alpha1.AppGroupCanary{Primary: "P1"}},
+				{Workload: workload("P1-canary-canary"), Canary: &v1alpha1.AppGroupCanary{Primary: "P1-canary"}},
+				{Workload: workload("P2-canary"), Canary: &v1alpha1.AppGroupCanary{Primary: "P2"}},
+			},
+			wantProblems: []string{
+				"primary P1-canary of canary workload P1-canary-canary is a canary itself",
+				"primary P2 of canary workload P2-canary is not a workload of the AppGroup",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ag := makeAG("ag", int32(len(c.workloads)), v1alpha1.AppGroupKahnSort, c.workloads, nil)
+			if got := validateDependencies(ag); !reflect.DeepEqual(got, c.wantProblems) {
+				t.Errorf("want %v, got %v", c.wantProblems, got)
+			}
+		})
+	}
+}
+
 func makePodsAppGroup(selectors []string, podNames []string, agName string, phase v1.PodPhase) []*v1.Pod {
 	pds := make([]*v1.Pod, 0)
 	i := 0
@@ -293,3 +435,37 @@ func makeAG(agName string, numMembers int32, topologySortingAlgorithm string, ap
 	}
 	return ag
 }
+
+func TestAppGroupController_ValidateCostUnits(t *testing.T) {
+	topology := func(key v1alpha1.TopologyKey, unit v1alpha1.NetworkCostUnit) v1alpha1.TopologyInfo {
+		return v1alpha1.TopologyInfo{TopologyKey: key, CostUnit: unit}
+	}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{Weights: v1alpha1.WeightList{
+			{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				topology(v1alpha1.NetworkTopologyRegion, ""),
+				topology(v1alpha1.NetworkTopologyZone, v1alpha1.NetworkCostUnitAbstract),
+			}},
+			{Name: "Mixed", TopologyList: v1alpha1.TopologyList{
+				topology(v1alpha1.NetworkTopologyRegion, v1alpha1.NetworkCostUnitMilliDollarsPerGB),
+				topology(v1alpha1.NetworkTopologyZone, v1alpha1.NetworkCostUnitMilliseconds),
+			}},
+		}},
+	}
+	recorder := record.NewFakeRecorder(2)
+	ctrl := &AppGroupController{eventRecorder: recorder}
+	ctrl.validateCostUnits(nt)
+	close(recorder.Events)
+
+	var events []string
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected a single event for the heterogeneous weights, got %v", events)
+	}
+	if expected := "Warning HeterogeneousCostUnits"; len(events[0]) < len(expected) || events[0][:len(expected)] != expected {
+		t.Errorf("expected a %q event, got %q", expected, events[0])
+	}
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// DefaultTrafficQuery is the bandwidth between workloads reported by the Istio proxies, in bytes per second.
+const DefaultTrafficQuery = `sum by (source_workload, source_workload_namespace, destination_workload, destination_workload_namespace) ` +
+	`(rate(istio_tcp_sent_bytes_total{reporter="source"}[5m]))`
+
+// The labels of the samples of the traffic query identifying the source and destination workloads,
+// by the name and namespace of their controller, e.g. a Deployment.
+const (
+	trafficSourceLabel               = "source_workload"
+	trafficSourceNamespaceLabel      = "source_workload_namespace"
+	trafficDestinationLabel          = "destination_workload"
+	trafficDestinationNamespaceLabel = "destination_workload_namespace"
+)
+
+// TrafficOptions configures the ingestion of the bandwidth observed between workloads, e.g. by an eBPF
+// exporter or a service mesh, into the status of the AppGroups.
+type TrafficOptions struct {
+	// PrometheusAddress is the address of the Prometheus scraping the exporter. Ingestion is disabled if empty.
+	PrometheusAddress string
+	// Query is the PromQL query of the bandwidth between workloads, in bytes per second, labeled by
+	// source_workload, source_workload_namespace, destination_workload and destination_workload_namespace.
+	Query string
+	// Alpha is the weight of the latest measurement in the moving average of the bandwidth, in (0, 1].
+	Alpha float64
+	// HeadroomPercent is the share of the average bandwidth added to it to recommend a minimum bandwidth.
+	HeadroomPercent int32
+	// AutoTune makes the controller rewrite the declared minimum egress bandwidth of the dependencies
+	// to the recommended one when it is exceeded by the average bandwidth, or over twice the recommended one.
+	AutoTune bool
+	// Interval is the period between two queries.
+	Interval time.Duration
+}
+
+// trafficKey identifies the traffic from a workload to another one.
+type trafficKey struct {
+	sourceNamespace, source, destinationNamespace, destination string
+}
+
+// TrafficController : a controller writing the bandwidth observed from the workloads of the AppGroups to their
+// dependencies into the status of the AppGroups, so that the declared minimum bandwidths can be validated and tuned
+type TrafficController struct {
+	TrafficOptions
+
+	prometheus     promv1.API
+	agLister       schedlister.AppGroupLister
+	agListerSynced cache.InformerSynced
+	schedClient    schedclientset.Interface
+}
+
+// NewTrafficController : returns a new *TrafficController
+func NewTrafficController(schedClient schedclientset.Interface, agInformer schedinformer.AppGroupInformer,
+	options TrafficOptions) (*TrafficController, error) {
+	if options.Query == "" {
+		options.Query = DefaultTrafficQuery
+	}
+	if options.Alpha <= 0 || options.Alpha > 1 {
+		return nil, fmt.Errorf("traffic smoothing alpha %v out of (0, 1]", options.Alpha)
+	}
+	if options.HeadroomPercent < 0 {
+		return nil, fmt.Errorf("negative traffic headroom %v%%", options.HeadroomPercent)
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: options.PrometheusAddress})
+	if err != nil {
+		return nil, err
+	}
+	return &TrafficController{
+		TrafficOptions: options,
+		prometheus:     promv1.NewAPI(client),
+		agLister:       agInformer.Lister(),
+		agListerSynced: agInformer.Informer().HasSynced,
+		schedClient:    schedClient,
+	}, nil
+}
+
+// Run : ingests the traffic every Interval
+func (ctrl *TrafficController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting Traffic controller", "autoTune", ctrl.AutoTune)
+	defer klog.InfoS("Shutting Traffic controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.agListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error ingesting the traffic between workloads")
+		}
+	}, ctrl.Interval, stopCh)
+}
+
+// sync : queries the traffic and updates the AppGroups whose dependencies it covers
+func (ctrl *TrafficController) sync(ctx context.Context) error {
+	now := time.Now()
+	value, warnings, err := ctrl.prometheus.Query(ctx, ctrl.Query, now)
+	if err != nil {
+		return fmt.Errorf("querying the traffic: %w", err)
+	}
+	if len(warnings) != 0 {
+		klog.V(4).InfoS("Warnings querying the traffic between workloads", "warnings", warnings)
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return fmt.Errorf("unexpected %v result of the traffic query", value.Type())
+	}
+	traffic := workloadTraffic(vector)
+
+	ags, err := ctrl.agLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, ag := range ags {
+		agCopy := ag.DeepCopy()
+		if !ctrl.observe(agCopy, traffic, metav1.NewTime(now)) {
+			continue
+		}
+		if err := ctrl.patchAppGroup(ctx, ag, agCopy); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", ag.Namespace, ag.Name, err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("updating the traffic of the AppGroups: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// workloadTraffic : returns the bandwidths of the samples, in bytes per second
+func workloadTraffic(vector model.Vector) map[trafficKey]float64 {
+	traffic := make(map[trafficKey]float64, len(vector))
+	for _, sample := range vector {
+		key := trafficKey{
+			sourceNamespace:      string(sample.Metric[trafficSourceNamespaceLabel]),
+			source:               string(sample.Metric[trafficSourceLabel]),
+			destinationNamespace: string(sample.Metric[trafficDestinationNamespaceLabel]),
+			destination:          string(sample.Metric[trafficDestinationLabel]),
+		}
+		bandwidth := float64(sample.Value)
+		if key.source == "" || key.destination == "" || math.IsNaN(bandwidth) || math.IsInf(bandwidth, 0) || bandwidth < 0 {
+			continue
+		}
+		traffic[key] += bandwidth
+	}
+	return traffic
+}
+
+// observe : folds the traffic of the dependencies of ag into their moving averages, sets the MinBandwidthSufficient
+// condition and, if AutoTune is set, the declared minimum egress bandwidths. Returns whether ag was observed at all;
+// the dependencies without any traffic sample keep their last observation
+func (ctrl *TrafficController) observe(ag *v1alpha1.AppGroup, traffic map[trafficKey]float64, now metav1.Time) bool {
+	previous := make(map[trafficKey]v1alpha1.AppGroupDependencyTraffic, len(ag.Status.DependencyTraffic))
+	for _, t := range ag.Status.DependencyTraffic {
+		previous[dependencyTrafficKey(ag, t.Workload, t.Dependency)] = t
+	}
+
+	var list v1alpha1.AppGroupDependencyTrafficList
+	var exceeded []string
+	observed := false
+	for i := range ag.Spec.Workloads {
+		w := &ag.Spec.Workloads[i]
+		for j := range w.Dependencies {
+			d := &w.Dependencies[j]
+			key := dependencyTrafficKey(ag, w.Workload, d.Workload)
+			t, known := previous[key]
+			if bandwidth, ok := traffic[key]; ok {
+				observed = true
+				average := bandwidth
+				if known {
+					average = ctrl.Alpha*bandwidth + (1-ctrl.Alpha)*float64(t.AverageBandwidth.Value())
+				}
+				t = v1alpha1.AppGroupDependencyTraffic{
+					Workload:                w.Workload,
+					Dependency:              d.Workload,
+					ObservedBandwidth:       *resource.NewQuantity(int64(math.Round(bandwidth)), resource.BinarySI),
+					AverageBandwidth:        *resource.NewQuantity(int64(math.Round(average)), resource.BinarySI),
+					RecommendedMinBandwidth: *resource.NewQuantity(int64(math.Round(average*float64(100+ctrl.HeadroomPercent)/100)), resource.BinarySI),
+					LastObservationTime:     now,
+				}
+			} else if !known {
+				continue
+			}
+			list = append(list, t)
+
+			declared := d.MinBandwidth
+			if d.MinEgressBandwidth != nil {
+				declared = *d.MinEgressBandwidth
+			}
+			if declared.Cmp(t.AverageBandwidth) >= 0 {
+				if ctrl.AutoTune && declared.Value() > 2*t.RecommendedMinBandwidth.Value() {
+					setMinEgressBandwidth(d, t.RecommendedMinBandwidth)
+				}
+				continue
+			}
+			if ctrl.AutoTune {
+				setMinEgressBandwidth(d, t.RecommendedMinBandwidth)
+				continue
+			}
+			exceeded = append(exceeded, fmt.Sprintf("workload %s sends %s/s on average to dependency %s, over its minimum of %s",
+				w.Workload.Name, t.AverageBandwidth.String(), d.Workload.Name, declared.String()))
+		}
+	}
+	if !observed {
+		return false
+	}
+	ag.Status.DependencyTraffic = list
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.AppGroupMinBandwidthSufficient,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ag.Generation,
+		Reason:             "WithinMinBandwidth",
+		Message:            "the observed bandwidth of every dependency is within its declared minimum",
+	}
+	if len(exceeded) != 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MinBandwidthExceeded"
+		condition.Message = strings.Join(exceeded, "; ")
+	}
+	meta.SetStatusCondition(&ag.Status.Conditions, condition)
+	return true
+}
+
+// dependencyTrafficKey : returns the key of the traffic from workload to dependency, defaulting their namespace to the
+// one of the AppGroup
+func dependencyTrafficKey(ag *v1alpha1.AppGroup, workload, dependency v1alpha1.AppGroupWorkloadInfo) trafficKey {
+	key := trafficKey{
+		sourceNamespace:      workload.Namespace,
+		source:               workload.Name,
+		destinationNamespace: dependency.Namespace,
+		destination:          dependency.Name,
+	}
+	if key.sourceNamespace == "" {
+		key.sourceNamespace = ag.Namespace
+	}
+	if key.destinationNamespace == "" {
+		key.destinationNamespace = ag.Namespace
+	}
+	return key
+}
+
+// setMinEgressBandwidth : sets the minimum egress bandwidth of d, in the field it was declared in
+func setMinEgressBandwidth(d *v1alpha1.DependenciesInfo, bandwidth resource.Quantity) {
+	if d.MinEgressBandwidth != nil {
+		d.MinEgressBandwidth = &bandwidth
+		return
+	}
+	d.MinBandwidth = bandwidth
+}
+
+// patchAppGroup : patches the tuned bandwidths to the spec of the AppGroup, then the observed traffic to its status
+// subresource
+func (ctrl *TrafficController) patchAppGroup(ctx context.Context, old, new *v1alpha1.AppGroup) error {
+	if !reflect.DeepEqual(old.Spec, new.Spec) {
+		patch, err := util.CreateMergePatch(&v1alpha1.AppGroup{Spec: old.Spec}, &v1alpha1.AppGroup{Spec: new.Spec})
+		if err != nil {
+			return err
+		}
+		if _, err := ctrl.schedClient.SchedulingV1alpha1().AppGroups(old.Namespace).Patch(ctx, old.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+	}
+	if reflect.DeepEqual(old.Status, new.Status) {
+		return nil
+	}
+	patch, err := util.CreateMergePatch(&v1alpha1.AppGroup{Status: old.Status}, &v1alpha1.AppGroup{Status: new.Status})
+	if err != nil {
+		return err
+	}
+	_, err = ctrl.schedClient.SchedulingV1alpha1().AppGroups(old.Namespace).Patch(ctx, old.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func makeTrafficAppGroup() *v1alpha1.AppGroup {
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1"}
+	p3 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P3-deployment", Selector: "P3", APIVersion: "apps/v1"}
+	return &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "ag", Namespace: "default"},
+		Spec: v1alpha1.AppGroupSpec{
+			NumMembers: 3,
+			Workloads: v1alpha1.AppGroupWorkloadList{
+				{Workload: p1, Dependencies: v1alpha1.DependenciesList{
+					{Workload: p2, MinBandwidth: resource.MustParse("1Mi")},
+					{Workload: p3, MinBandwidth: resource.MustParse("10Mi")},
+				}},
+				{Workload: p2},
+				{Workload: p3},
+			},
+		},
+	}
+}
+
+func TestTrafficController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"source_workload":"P1-deployment","source_workload_namespace":"default","destination_workload":"P2-deployment","destination_workload_namespace":"default"},"value":[1650000000,"2097152"]},
+			{"metric":{"source_workload":"P1-deployment","source_workload_namespace":"other","destination_workload":"P3-deployment","destination_workload_namespace":"default"},"value":[1650000000,"1048576"]}
+		]}}`)
+	}))
+	defer prometheus.Close()
+
+	schedClient := schedfake.NewSimpleClientset(makeTrafficAppGroup())
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	ctrl, err := NewTrafficController(schedClient, schedInformerFactory.Scheduling().V1alpha1().AppGroups(), TrafficOptions{
+		PrometheusAddress: prometheus.URL,
+		Alpha:             1,
+		HeadroomPercent:   50,
+		Interval:          100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedInformerFactory.Start(ctx.Done())
+	go ctrl.Run(ctx.Done())
+
+	var got *v1alpha1.AppGroup
+	err = wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		got, err = schedClient.SchedulingV1alpha1().AppGroups("default").Get(ctx, "ag", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return len(got.Status.DependencyTraffic) != 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the observed traffic, got %v: %v", got.Status, err)
+	}
+	// The traffic of P1 to P3 comes from another namespace than the AppGroup's.
+	if len(got.Status.DependencyTraffic) != 1 {
+		t.Fatalf("Expected the traffic of a single dependency, got %v", got.Status.DependencyTraffic)
+	}
+	traffic := got.Status.DependencyTraffic[0]
+	if traffic.Dependency.Name != "P2-deployment" || traffic.ObservedBandwidth.Cmp(resource.MustParse("2Mi")) != 0 ||
+		traffic.RecommendedMinBandwidth.Cmp(resource.MustParse("3Mi")) != 0 {
+		t.Errorf("Expected 2Mi observed to P2-deployment and 3Mi recommended, got %v", traffic)
+	}
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.AppGroupMinBandwidthSufficient) {
+		t.Errorf("Expected the minimum bandwidth to be reported exceeded, got %v", got.Status.Conditions)
+	}
+}
+
+func TestTrafficControllerObserve(t *testing.T) {
+	now := metav1.Now()
+	traffic := map[trafficKey]float64{
+		{sourceNamespace: "default", source: "P1-deployment", destinationNamespace: "default", destination: "P2-deployment"}: 4 << 20,
+		{sourceNamespace: "default", source: "P1-deployment", destinationNamespace: "default", destination: "P3-deployment"}: 1 << 20,
+	}
+
+	t.Run("moving average", func(t *testing.T) {
+		ctrl := &TrafficController{TrafficOptions: TrafficOptions{Alpha: 0.5}}
+		ag := makeTrafficAppGroup()
+		ag.Status.DependencyTraffic = v1alpha1.AppGroupDependencyTrafficList{{
+			Workload:         ag.Spec.Workloads[0].Workload,
+			Dependency:       ag.Spec.Workloads[1].Workload,
+			AverageBandwidth: resource.MustParse("2Mi"),
+		}}
+		if !ctrl.observe(ag, traffic, now) {
+			t.Fatalf("Expected the AppGroup to be observed")
+		}
+		if got := ag.Status.DependencyTraffic[0].AverageBandwidth; got.Cmp(resource.MustParse("3Mi")) != 0 {
+			t.Errorf("Expected an average of 3Mi, got %v", got.String())
+		}
+		if !meta.IsStatusConditionFalse(ag.Status.Conditions, v1alpha1.AppGroupMinBandwidthSufficient) {
+			t.Errorf("Expected the minimum bandwidth of P2-deployment to be reported exceeded, got %v", ag.Status.Conditions)
+		}
+	})
+
+	t.Run("auto tune", func(t *testing.T) {
+		ctrl := &TrafficController{TrafficOptions: TrafficOptions{Alpha: 1, HeadroomPercent: 100, AutoTune: true}}
+		ag := makeTrafficAppGroup()
+		if !ctrl.observe(ag, traffic, now) {
+			t.Fatalf("Expected the AppGroup to be observed")
+		}
+		// The minimum to P2 is exceeded, the one to P3 over twice the recommended one.
+		for i, expected := range []string{"8Mi", "2Mi"} {
+			if got := ag.Spec.Workloads[0].Dependencies[i].MinBandwidth; got.Cmp(resource.MustParse(expected)) != 0 {
+				t.Errorf("Expected the minimum bandwidth of dependency %d tuned to %v, got %v", i, expected, got.String())
+			}
+		}
+		if !meta.IsStatusConditionTrue(ag.Status.Conditions, v1alpha1.AppGroupMinBandwidthSufficient) {
+			t.Errorf("Expected the tuned minimum bandwidths to be sufficient, got %v", ag.Status.Conditions)
+		}
+	})
+
+	t.Run("no traffic", func(t *testing.T) {
+		ctrl := &TrafficController{TrafficOptions: TrafficOptions{Alpha: 1}}
+		if ag := makeTrafficAppGroup(); ctrl.observe(ag, nil, now) || len(ag.Status.Conditions) != 0 {
+			t.Errorf("Expected an AppGroup without traffic to be left untouched, got %v", ag.Status)
+		}
+	})
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// defaultZoneWeightsName is the NetworkTopology weights used if the AppGroup does not specify any.
+	defaultZoneWeightsName = "UserDefined"
+	// defaultZoneMaxSkew is the maximum skew used if the AppGroup does not specify any.
+	defaultZoneMaxSkew int32 = 1
+	// defaultDependencyWeight is the weight of a dependency not specifying any.
+	defaultDependencyWeight int32 = 1
+)
+
+// zoneCostTable holds the network costs between the zones of a NetworkTopology.
+type zoneCostTable struct {
+	// zones is sorted, without the draining zones, which get no new replicas.
+	zones []string
+	// costs is keyed by interface class, empty for the default class, then origin then destination zone.
+	costs map[string]map[string]map[string]int64
+	// max is the highest cost, of any class.
+	max int64
+	// unknown is the cost of the pairs missing from the weights: the default cost of the
+	// NetworkTopology if set, the highest cost otherwise.
+	unknown int64
+}
+
+func newZoneCostTable(nt *v1alpha1.NetworkTopology, weightsName string) *zoneCostTable {
+	table := &zoneCostTable{costs: make(map[string]map[string]map[string]int64), max: 1}
+	zones := make(map[string]bool)
+	for _, w := range nt.Spec.Weights {
+		if w.Name != weightsName {
+			continue
+		}
+		for _, t := range w.TopologyList {
+			if t.TopologyKey != v1alpha1.NetworkTopologyZone {
+				continue
+			}
+			if table.costs[t.InterfaceClass] == nil {
+				table.costs[t.InterfaceClass] = make(map[string]map[string]int64)
+			}
+			for _, o := range t.OriginList {
+				zones[o.Origin] = true
+				destinations := make(map[string]int64, len(o.CostList))
+				for _, c := range o.CostList {
+					zones[c.Destination] = true
+					destinations[c.Destination] = c.NetworkCost
+					if c.NetworkCost > table.max {
+						table.max = c.NetworkCost
+					}
+				}
+				table.costs[t.InterfaceClass][o.Origin] = destinations
+			}
+		}
+	}
+	draining := costoracle.DrainingZones(nt)
+	for z := range zones {
+		if _, ok := draining[z]; ok {
+			continue
+		}
+		table.zones = append(table.zones, z)
+	}
+	sort.Strings(table.zones)
+	table.unknown = table.max
+	if nt.Spec.DefaultCost != nil {
+		table.unknown = *nt.Spec.DefaultCost
+	}
+	return table
+}
+
+// cost returns the network cost between two zones over the interfaces of class, falling back to the
+// default class, then to the cost of the unknown pairs if missing.
+func (t *zoneCostTable) cost(class, from, to string) int64 {
+	if from == to {
+		return 0
+	}
+	if c, ok := t.classCost(class, from, to); ok {
+		return c
+	}
+	if c, ok := t.classCost("", from, to); class != "" && ok {
+		return c
+	}
+	return t.unknown
+}
+
+// classCost returns the network cost between two zones over the interfaces of class, in either direction.
+func (t *zoneCostTable) classCost(class, from, to string) (int64, bool) {
+	if c, ok := t.costs[class][from][to]; ok {
+		return c, true
+	}
+	c, ok := t.costs[class][to][from]
+	return c, ok
+}
+
+// zoneDistributionParams returns the weights name and the maximum skew of zd, defaulted.
+func zoneDistributionParams(zd *v1alpha1.AppGroupZoneDistribution) (string, int32) {
+	weightsName := zd.WeightsName
+	if weightsName == "" {
+		weightsName = defaultZoneWeightsName
+	}
+	maxSkew := zd.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultZoneMaxSkew
+	}
+	return weightsName, maxSkew
+}
+
+// dependencyWeight returns the weight of d, defaultDependencyWeight if not specified.
+func dependencyWeight(d v1alpha1.DependenciesInfo) int64 {
+	if d.Weight == nil {
+		return int64(defaultDependencyWeight)
+	}
+	return int64(*d.Weight)
+}
+
+// workloadNeighbor is a dependency or dependent of a workload, reached over the interfaces of a class.
+type workloadNeighbor struct {
+	selector       string
+	interfaceClass string
+}
+
+// workloadNeighbors returns the dependencies of the workloads of ag in both directions, keyed
+// by selector then neighbor, with their summed weights. Canary workloads share the
+// dependencies of their primary, discounted. The zone-local dependencies, with a peer in every
+// zone at no network cost, are left out.
+func workloadNeighbors(ag *v1alpha1.AppGroup) map[string]map[workloadNeighbor]int64 {
+	neighbors := make(map[string]map[workloadNeighbor]int64)
+	addNeighbor := func(from, to, class string, weight int64) {
+		if neighbors[from] == nil {
+			neighbors[from] = make(map[workloadNeighbor]int64)
+		}
+		neighbors[from][workloadNeighbor{selector: to, interfaceClass: class}] += weight
+	}
+	for _, w := range util.WithCanaryDependencies(ag.Spec.Workloads) {
+		for _, d := range w.Dependencies {
+			if d.ZoneLocal {
+				continue
+			}
+			weight := dependencyWeight(d)
+			addNeighbor(w.Workload.Selector, d.Workload.Selector, d.InterfaceClass, weight)
+			addNeighbor(d.Workload.Selector, w.Workload.Selector, d.InterfaceClass, weight)
+		}
+	}
+	return neighbors
+}
+
+// workloadZones returns the indexes of the zones the replicas of w may be placed in: its preferred zones
+// among its required ones if any of them is in zones, else its required zones, else all the zones.
+func workloadZones(w v1alpha1.AppGroupWorkload, zones []string) []int {
+	required := sets.NewString(w.RequiredZones...)
+	preferred := sets.NewString(w.PreferredZones...)
+	var candidates, preferredCandidates []int
+	for i, zone := range zones {
+		if required.Len() != 0 && !required.Has(zone) {
+			continue
+		}
+		candidates = append(candidates, i)
+		if preferred.Has(zone) {
+			preferredCandidates = append(preferredCandidates, i)
+		}
+	}
+	if len(preferredCandidates) != 0 {
+		return preferredCandidates
+	}
+	return candidates
+}
+
+// countWorkloadReplicas returns the number of active pods of every workload, keyed by selector.
+func countWorkloadReplicas(pods []*v1.Pod) map[string]int32 {
+	replicas := make(map[string]int32)
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if selector := pod.Labels[v1alpha1.AppGroupSelectorLabel]; selector != "" {
+			replicas[selector]++
+		}
+	}
+	return replicas
+}
+
+// recommendZoneReplicas distributes the replicas of the workloads of ag across the zones of
+// table. Replicas are placed one at a time, workloads taking turns in topology order, in the
+// zone minimizing the network cost to the replicas of their dependencies and dependents
+// already placed, weighted by the dependency weights, among the zones keeping the skew of the workload within maxSkew. This
+// greedy heuristic favors colocating dependent workloads as much as the skew allows. Workloads pinned to zones are only
+// placed, and have their skew computed, in these zones; workloads whose required zones are all missing get no replicas. The
+// draining zones get no replicas.
+func recommendZoneReplicas(ag *v1alpha1.AppGroup, replicas map[string]int32, table *zoneCostTable, maxSkew int32) v1alpha1.AppGroupZoneRecommendationList {
+	if len(table.zones) == 0 {
+		return nil
+	}
+
+	neighbors := workloadNeighbors(ag)
+
+	workloads := make([]v1alpha1.AppGroupWorkloadInfo, 0, len(ag.Spec.Workloads))
+	// allowed is keyed by selector, holding the indexes of the zones the workload may be placed in.
+	allowed := make(map[string][]int, len(ag.Spec.Workloads))
+	for _, w := range ag.Spec.Workloads {
+		workloads = append(workloads, w.Workload)
+		allowed[w.Workload.Selector] = workloadZones(w, table.zones)
+	}
+	index := make(map[string]int32, len(ag.Status.TopologyOrder))
+	for _, t := range ag.Status.TopologyOrder {
+		index[t.Workload.Selector] = t.Index
+	}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		return index[workloads[i].Selector] < index[workloads[j].Selector]
+	})
+
+	// counts is keyed by selector then zone index.
+	counts := make(map[string][]int32, len(workloads))
+	for _, w := range workloads {
+		counts[w.Selector] = make([]int32, len(table.zones))
+	}
+	placed := make(map[string]int32, len(workloads))
+	for done := false; !done; {
+		done = true
+		for _, w := range workloads {
+			zones := allowed[w.Selector]
+			if placed[w.Selector] >= replicas[w.Selector] || len(zones) == 0 {
+				continue
+			}
+			done = false
+			zoneCounts := counts[w.Selector]
+			lowest := zoneCounts[zones[0]]
+			for _, i := range zones {
+				if zoneCounts[i] < lowest {
+					lowest = zoneCounts[i]
+				}
+			}
+			best, bestCost := -1, int64(math.MaxInt64)
+			for _, i := range zones {
+				zone := table.zones[i]
+				if zoneCounts[i]+1-lowest > maxSkew {
+					continue
+				}
+				var cost int64
+				for n, weight := range neighbors[w.Selector] {
+					for j, c := range counts[n.selector] {
+						cost += weight * int64(c) * table.cost(n.interfaceClass, zone, table.zones[j])
+					}
+				}
+				if cost < bestCost {
+					best, bestCost = i, cost
+				}
+			}
+			zoneCounts[best]++
+			placed[w.Selector]++
+		}
+	}
+
+	var recommendations v1alpha1.AppGroupZoneRecommendationList
+	for _, w := range workloads {
+		recommendation := v1alpha1.AppGroupZoneRecommendation{Workload: w}
+		for i, c := range counts[w.Selector] {
+			if c > 0 {
+				recommendation.Zones = append(recommendation.Zones, v1alpha1.ZoneReplicas{Zone: table.zones[i], Replicas: c})
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Workload.Selector < recommendations[j].Workload.Selector
+	})
+	return recommendations
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestRecommendZoneReplicas(t *testing.T) {
+	// Zones z1 and z2 are close, z3 is far from both.
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+						{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	ag := makeAG("ag", 4, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+		{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2}}},
+		{Workload: p2},
+	}, nil)
+	ag.Status.TopologyOrder = v1alpha1.AppGroupTopologyList{{Workload: p1, Index: 1}, {Workload: p2, Index: 2}}
+	drainingNT := nt.DeepCopy()
+	drainingNT.Spec.DrainingZones = []v1alpha1.DrainingZone{{Zone: "z1"}}
+
+	tests := []struct {
+		name     string
+		replicas map[string]int32
+		maxSkew  int32
+		table    *zoneCostTable
+		expected v1alpha1.AppGroupZoneRecommendationList
+	}{
+		{
+			name:     "dependent workloads are colocated when the skew allows it",
+			replicas: map[string]int32{"P1": 2, "P2": 2},
+			maxSkew:  2,
+			table:    newZoneCostTable(nt, "UserDefined"),
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 2}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 2}}},
+			},
+		},
+		{
+			name:     "replicas are spread to the closest zones within the skew",
+			replicas: map[string]int32{"P1": 2, "P2": 2},
+			maxSkew:  1,
+			table:    newZoneCostTable(nt, "UserDefined"),
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+			},
+		},
+		{
+			name:     "workloads without replicas get no zone",
+			replicas: map[string]int32{"P1": 1},
+			maxSkew:  1,
+			table:    newZoneCostTable(nt, "UserDefined"),
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}}},
+				{Workload: p2},
+			},
+		},
+		{
+			name:     "draining zones get no replicas",
+			replicas: map[string]int32{"P1": 2, "P2": 2},
+			maxSkew:  1,
+			table:    newZoneCostTable(drainingNT, "UserDefined"),
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z2", Replicas: 1}, {Zone: "z3", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z2", Replicas: 1}, {Zone: "z3", Replicas: 1}}},
+			},
+		},
+		{
+			name:     "no recommendation without zones",
+			replicas: map[string]int32{"P1": 2, "P2": 2},
+			maxSkew:  1,
+			table:    newZoneCostTable(nt, "NetperfCosts"),
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recommendZoneReplicas(ag, tt.replicas, tt.table, tt.maxSkew)
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRecommendZoneReplicasPinnedZones(t *testing.T) {
+	// Zones z1 and z2 are close, z3 is far from both.
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+						{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	db := v1alpha1.AppGroupWorkloadInfo{Kind: "StatefulSet", Name: "DB-statefulset", Selector: "DB", APIVersion: "apps/v1", Namespace: "default"}
+
+	tests := []struct {
+		name           string
+		requiredZones  []string
+		preferredZones []string
+		expected       v1alpha1.AppGroupZoneRecommendationList
+	}{
+		{
+			name:          "dependents follow a workload required in a zone",
+			requiredZones: []string{"z3"},
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: db, Zones: v1alpha1.ZoneReplicasList{{Zone: "z3", Replicas: 2}}},
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z3", Replicas: 2}}},
+			},
+		},
+		{
+			name:           "available preferred zones are used",
+			preferredZones: []string{"z2", "z4"},
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: db, Zones: v1alpha1.ZoneReplicasList{{Zone: "z2", Replicas: 2}}},
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z2", Replicas: 2}}},
+			},
+		},
+		{
+			name:           "preferred zones outside of the required ones are ignored",
+			requiredZones:  []string{"z3"},
+			preferredZones: []string{"z2"},
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: db, Zones: v1alpha1.ZoneReplicasList{{Zone: "z3", Replicas: 2}}},
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z3", Replicas: 2}}},
+			},
+		},
+		{
+			name:          "no replicas without any required zone available",
+			requiredZones: []string{"z4"},
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: db},
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 2}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ag := makeAG("ag", 4, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+				{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: db}}},
+				{Workload: db, RequiredZones: tt.requiredZones, PreferredZones: tt.preferredZones},
+			}, nil)
+			ag.Status.TopologyOrder = v1alpha1.AppGroupTopologyList{{Workload: db, Index: 1}, {Workload: p1, Index: 2}}
+			got := recommendZoneReplicas(ag, map[string]int32{"P1": 2, "DB": 2}, newZoneCostTable(nt, "UserDefined"), 2)
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRecommendZoneReplicasDependencyWeight(t *testing.T) {
+	// Zone z3 is closer to z1 than z2 is, except over the storage network.
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{
+					{
+						TopologyKey: v1alpha1.NetworkTopologyZone,
+						OriginList: v1alpha1.OriginList{
+							{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 10}, {Destination: "z3", NetworkCost: 1}}},
+							{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+						},
+					},
+					{
+						TopologyKey:    v1alpha1.NetworkTopologyZone,
+						InterfaceClass: "storage",
+						OriginList: v1alpha1.OriginList{
+							{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+						},
+					},
+				},
+			}},
+		},
+	}
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	zero := int32(0)
+
+	tests := []struct {
+		name           string
+		weight         *int32
+		zoneLocal      bool
+		interfaceClass string
+		expected       v1alpha1.AppGroupZoneRecommendationList
+	}{
+		{
+			name:   "dependency with the default weight pulls replicas to the closest zone",
+			weight: nil,
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z3", Replicas: 1}}},
+			},
+		},
+		{
+			name:   "dependency with a zero weight is ignored",
+			weight: &zero,
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+			},
+		},
+		{
+			name:           "dependency over the storage network uses its costs",
+			interfaceClass: "storage",
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+			},
+		},
+		{
+			name:      "zone-local dependency is ignored",
+			zoneLocal: true,
+			expected: v1alpha1.AppGroupZoneRecommendationList{
+				{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+				{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}, {Zone: "z2", Replicas: 1}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ag := makeAG("ag", 4, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+				{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2, Weight: tt.weight, ZoneLocal: tt.zoneLocal, InterfaceClass: tt.interfaceClass}}},
+				{Workload: p2},
+			}, nil)
+			ag.Status.TopologyOrder = v1alpha1.AppGroupTopologyList{{Workload: p1, Index: 1}, {Workload: p2, Index: 2}}
+			got := recommendZoneReplicas(ag, map[string]int32{"P1": 2, "P2": 2}, newZoneCostTable(nt, "UserDefined"), 1)
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestZoneCostTableDefaultCost(t *testing.T) {
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	if cost := newZoneCostTable(nt, "UserDefined").cost("", "z2", "z3"); cost != 10 {
+		t.Errorf("expected the highest cost for a missing pair, got %v", cost)
+	}
+	defaultCost := int64(3)
+	nt.Spec.DefaultCost = &defaultCost
+	table := newZoneCostTable(nt, "UserDefined")
+	if cost := table.cost("", "z2", "z3"); cost != defaultCost {
+		t.Errorf("expected the default cost for a missing pair, got %v", cost)
+	}
+	if cost := table.cost("", "z3", "z1"); cost != 10 {
+		t.Errorf("expected the cost of the reverse pair, got %v", cost)
+	}
+	if cost := table.cost("storage", "z1", "z2"); cost != 1 {
+		t.Errorf("expected the cost of the default class for a class without costs, got %v", cost)
+	}
+}
+
+func TestCountWorkloadReplicas(t *testing.T) {
+	pods := makePodsAppGroup([]string{"P1", "P1", "P2"}, []string{"a", "b", "c"}, "ag", v1.PodRunning)
+	pods[1].Status.Phase = v1.PodSucceeded
+	expected := map[string]int32{"P1": 1, "P2": 1}
+	if got := countWorkloadReplicas(pods); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"sync"
+
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// maxSmoothedTopologies bounds the number of NetworkTopologies whose costs are smoothed. Past it,
+// the averages of the least recently used one are dropped and start over from its current costs.
+const maxSmoothedTopologies = 256
+
+// CostSmoothing configures the smoothing of the network costs of the NetworkTopologies, which
+// fluctuate when measured by probes, before they are used for zone recommendations.
+type CostSmoothing struct {
+	// Alpha is the weight of the latest cost in the exponentially weighted moving average of
+	// the cost of every link, in (0, 1]. 1, or any value out of range, disables the averaging.
+	Alpha float64
+	// MinChange is the minimum change of the averaged cost of a link for it to be taken into
+	// account. 0 takes every change into account.
+	MinChange int64
+}
+
+// enabled returns whether s changes the costs at all.
+func (s CostSmoothing) enabled() bool {
+	return (s.Alpha > 0 && s.Alpha < 1) || s.MinChange > 0
+}
+
+// costLink identifies a link of a NetworkTopology.
+type costLink struct {
+	weightsName    string
+	topologyKey    v1alpha1.TopologyKey
+	interfaceClass string
+	origin         string
+	destination    string
+}
+
+// smoothedCost is the state of a link.
+type smoothedCost struct {
+	// average is the moving average of the observed costs.
+	average float64
+	// cost is the cost in use, only updated when average moved away from it by MinChange.
+	cost int64
+}
+
+// costSmoother holds the smoothed costs of the links of the NetworkTopologies, keyed by
+// NetworkTopology namespace/name.
+type costSmoother struct {
+	CostSmoothing
+
+	sync.Mutex
+	// topologies holds the map[costLink]*smoothedCost of every NetworkTopology.
+	topologies *util.BoundedCache
+}
+
+func newCostSmoother(s CostSmoothing) *costSmoother {
+	return &costSmoother{CostSmoothing: s, topologies: util.NewBoundedCache("controller_smoothed_topologies", maxSmoothedTopologies, 0)}
+}
+
+// linksLocked returns the smoothed costs of the NetworkTopology namespace/name, if known.
+func (cs *costSmoother) linksLocked(key string) (map[costLink]*smoothedCost, bool) {
+	links, ok := cs.topologies.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return links.(map[costLink]*smoothedCost), true
+}
+
+// observe folds the costs of nt into the moving averages, and returns whether the cost in use
+// of any link changed, links appearing or disappearing included.
+func (cs *costSmoother) observe(nt *v1alpha1.NetworkTopology) bool {
+	if !cs.enabled() {
+		return true
+	}
+	key, err := cache.MetaNamespaceKeyFunc(nt)
+	if err != nil {
+		return true
+	}
+	cs.Lock()
+	defer cs.Unlock()
+	return cs.observeLocked(key, nt)
+}
+
+func (cs *costSmoother) observeLocked(key string, nt *v1alpha1.NetworkTopology) bool {
+	previous, known := cs.linksLocked(key)
+	links := make(map[costLink]*smoothedCost, len(previous))
+	changed := !known
+	for _, w := range nt.Spec.Weights {
+		for _, t := range w.TopologyList {
+			for _, o := range t.OriginList {
+				for _, c := range o.CostList {
+					l := costLink{weightsName: w.Name, topologyKey: t.TopologyKey, interfaceClass: t.InterfaceClass, origin: o.Origin, destination: c.Destination}
+					s, ok := previous[l]
+					if !ok {
+						links[l] = &smoothedCost{average: float64(c.NetworkCost), cost: c.NetworkCost}
+						changed = true
+						continue
+					}
+					if cs.Alpha > 0 && cs.Alpha < 1 {
+						s.average = cs.Alpha*float64(c.NetworkCost) + (1-cs.Alpha)*s.average
+					} else {
+						s.average = float64(c.NetworkCost)
+					}
+					if cost := int64(math.Round(s.average)); cost != s.cost && abs(cost-s.cost) >= cs.MinChange {
+						s.cost = cost
+						changed = true
+					}
+					links[l] = s
+				}
+			}
+		}
+	}
+	if len(links) != len(previous) {
+		changed = true
+	}
+	cs.topologies.SetDefault(key, links)
+	return changed
+}
+
+// smoothed returns a copy of nt with the costs in use, nt itself if smoothing is disabled.
+func (cs *costSmoother) smoothed(nt *v1alpha1.NetworkTopology) *v1alpha1.NetworkTopology {
+	if !cs.enabled() {
+		return nt
+	}
+	key, err := cache.MetaNamespaceKeyFunc(nt)
+	if err != nil {
+		return nt
+	}
+	cs.Lock()
+	defer cs.Unlock()
+	links, ok := cs.linksLocked(key)
+	if !ok {
+		cs.observeLocked(key, nt)
+		links, _ = cs.linksLocked(key)
+	}
+	nt = nt.DeepCopy()
+	for _, w := range nt.Spec.Weights {
+		for _, t := range w.TopologyList {
+			for _, o := range t.OriginList {
+				for i, c := range o.CostList {
+					if s, ok := links[costLink{weightsName: w.Name, topologyKey: t.TopologyKey, interfaceClass: t.InterfaceClass, origin: o.Origin, destination: c.Destination}]; ok {
+						o.CostList[i].NetworkCost = s.cost
+					}
+				}
+			}
+		}
+	}
+	return nt
+}
+
+// forget drops the costs of the NetworkTopology namespace/name.
+func (cs *costSmoother) forget(key string) {
+	cs.Lock()
+	defer cs.Unlock()
+	cs.topologies.Delete(key)
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func makeZoneCostNT(costs ...int64) *v1alpha1.NetworkTopology {
+	var costList v1alpha1.CostList
+	for i, c := range costs {
+		costList = append(costList, v1alpha1.CostInfo{Destination: "z" + string(rune('2'+i)), NetworkCost: c})
+	}
+	return &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: costList}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestCostSmoother(t *testing.T) {
+	type observation struct {
+		costs           []int64
+		expectedChanged bool
+		expectedCosts   []int64
+	}
+	tests := []struct {
+		name         string
+		smoothing    CostSmoothing
+		observations []observation
+	}{
+		{
+			name:      "disabled smoothing passes costs through",
+			smoothing: CostSmoothing{Alpha: 1},
+			observations: []observation{
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+				{costs: []int64{20}, expectedChanged: true, expectedCosts: []int64{20}},
+				{costs: []int64{20}, expectedChanged: true, expectedCosts: []int64{20}},
+			},
+		},
+		{
+			name:      "spikes are averaged out",
+			smoothing: CostSmoothing{Alpha: 0.25},
+			observations: []observation{
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+				{costs: []int64{30}, expectedChanged: true, expectedCosts: []int64{15}},
+				{costs: []int64{15}, expectedChanged: false, expectedCosts: []int64{15}},
+			},
+		},
+		{
+			name:      "changes under the threshold are ignored",
+			smoothing: CostSmoothing{Alpha: 1, MinChange: 5},
+			observations: []observation{
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+				{costs: []int64{13}, expectedChanged: false, expectedCosts: []int64{10}},
+				{costs: []int64{16}, expectedChanged: true, expectedCosts: []int64{16}},
+			},
+		},
+		{
+			name:      "averaged changes accumulate up to the threshold",
+			smoothing: CostSmoothing{Alpha: 0.5, MinChange: 4},
+			observations: []observation{
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+				{costs: []int64{16}, expectedChanged: false, expectedCosts: []int64{10}},
+				{costs: []int64{16}, expectedChanged: true, expectedCosts: []int64{15}},
+			},
+		},
+		{
+			name:      "new links are taken into account immediately",
+			smoothing: CostSmoothing{Alpha: 0.5, MinChange: 4},
+			observations: []observation{
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+				{costs: []int64{10, 30}, expectedChanged: true, expectedCosts: []int64{10, 30}},
+				{costs: []int64{10}, expectedChanged: true, expectedCosts: []int64{10}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newCostSmoother(tt.smoothing)
+			for i, o := range tt.observations {
+				nt := makeZoneCostNT(o.costs...)
+				if changed := cs.observe(nt); changed != o.expectedChanged {
+					t.Errorf("observation %d: expected changed %v, got %v", i, o.expectedChanged, changed)
+				}
+				got := cs.smoothed(nt).Spec.Weights[0].TopologyList[0].OriginList[0].CostList
+				for j, c := range got {
+					if c.NetworkCost != o.expectedCosts[j] {
+						t.Errorf("observation %d: expected cost %v to %s, got %v", i, o.expectedCosts[j], c.Destination, c.NetworkCost)
+					}
+				}
+				if nt.Spec.Weights[0].TopologyList[0].OriginList[0].CostList[0].NetworkCost != o.costs[0] {
+					t.Errorf("observation %d: the observed NetworkTopology was modified", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+// WithNamespaceAuthorization : restricts a debug handler serving the data of the namespace given by the
+// "namespace" query parameter to the callers allowed to get the AppGroups of that namespace, so that
+// tenants only see the placements and costs of their own AppGroups. Callers authenticate with a bearer
+// token, checked by a TokenReview, and are authorized by a SubjectAccessReview, i.e. by the cluster RBAC.
+func WithNamespaceAuthorization(client kubernetes.Interface, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+			return
+		}
+		tr, err := client.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to review the token of a debug request")
+			http.Error(w, "unable to authenticate the request", http.StatusInternalServerError)
+			return
+		}
+		if !tr.Status.Authenticated {
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		namespace := r.URL.Query().Get("namespace")
+		if namespace == "" {
+			http.Error(w, "namespace query parameter is required", http.StatusBadRequest)
+			return
+		}
+		user := tr.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+		sar, err := client.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "get",
+					Group:     v1alpha1.SchemeGroupVersion.Group,
+					Resource:  "appgroups",
+				},
+				User:   user.Username,
+				Groups: user.Groups,
+				UID:    user.UID,
+				Extra:  extra,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to review the access of a debug request", "user", user.Username, "namespace", namespace)
+			http.Error(w, "unable to authorize the request", http.StatusInternalServerError)
+			return
+		}
+		if !sar.Status.Allowed {
+			http.Error(w, fmt.Sprintf("user %q cannot get appgroups in namespace %q", user.Username, namespace), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestWithNamespaceAuthorization(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	// Token "alice" authenticates tenant alice, who can only read the AppGroups of namespace "team-a".
+	client.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		tr := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if tr.Spec.Token == "alice" {
+			tr.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "alice"}}
+		}
+		return true, tr, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := sar.Spec.ResourceAttributes
+		sar.Status.Allowed = sar.Spec.User == "alice" && attrs.Namespace == "team-a" &&
+			attrs.Resource == "appgroups" && attrs.Verb == "get"
+		return true, sar, nil
+	})
+	handler := WithNamespaceAuthorization(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		namespace     string
+		authorization string
+		expected      int
+	}{
+		{name: "own namespace", namespace: "team-a", authorization: "Bearer alice", expected: http.StatusOK},
+		{name: "other tenant namespace", namespace: "team-b", authorization: "Bearer alice", expected: http.StatusForbidden},
+		{name: "no namespace", authorization: "Bearer alice", expected: http.StatusBadRequest},
+		{name: "invalid token", namespace: "team-a", authorization: "Bearer mallory", expected: http.StatusUnauthorized},
+		{name: "no token", namespace: "team-a", expected: http.StatusUnauthorized},
+		{name: "not a bearer token", namespace: "team-a", authorization: "Basic YWxpY2U6", expected: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, ShadowComparePath+"?name=nt&namespace="+tt.namespace, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %v, got %v: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
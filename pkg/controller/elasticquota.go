@@ -322,12 +322,13 @@ func computePodResourceRequest(pod *v1.Pod) v1.ResourceList {
 	for _, container := range pod.Spec.InitContainers {
 		initRes = quota.Max(initRes, container.Resources.Requests)
 	}
+	// take max_resource for init_containers and containers
+	result = quota.Max(result, initRes)
 	// If Overhead is being utilized, add to the total requests for the pod
 	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(kubefeatures.PodOverhead) {
-		quota.Add(result, pod.Spec.Overhead)
+		result = quota.Add(result, pod.Spec.Overhead)
 	}
-	// take max_resource for init_containers and containers
-	return quota.Max(result, initRes)
+	return result
 }
 
 // newZeroUsed will return the zero value of the union of min and max
@@ -95,6 +95,29 @@ func TestElasticQuotaController_Run(t *testing.T) {
 					Used(testutil.MakeResourceList().CPU(5).Mem(7).Obj()).Obj(),
 			},
 		},
+		{
+			name: "pod overhead",
+			elasticQuotas: []*v1alpha1.ElasticQuota{
+				testutil.MakeEQ("t7-ns1", "t7-eq1").
+					Min(testutil.MakeResourceList().CPU(3).Mem(5).Obj()).
+					Max(testutil.MakeResourceList().CPU(5).Mem(15).Obj()).Obj(),
+			},
+			pods: []*v1.Pod{
+				// CPU: 2, Mem: 3
+				testutil.MakePod("t7-ns1", "pod1").Phase(v1.PodRunning).
+					Container(testutil.MakeResourceList().CPU(1).Mem(2).Obj()).
+					Overhead(testutil.MakeResourceList().CPU(1).Mem(1).Obj()).Obj(),
+				// CPU: 3, Mem: 4, the overhead adds to the largest init container
+				testutil.MakePod("t7-ns1", "pod2").Phase(v1.PodRunning).
+					InitContainerRequest(testutil.MakeResourceList().CPU(2).Mem(3).Obj()).
+					Container(testutil.MakeResourceList().CPU(1).Mem(1).Obj()).
+					Overhead(testutil.MakeResourceList().CPU(1).Mem(1).Obj()).Obj(),
+			},
+			want: []*v1alpha1.ElasticQuota{
+				testutil.MakeEQ("t7-ns1", "t7-eq1").
+					Used(testutil.MakeResourceList().CPU(5).Mem(7).Obj()).Obj(),
+			},
+		},
 		{
 			name: "update pods",
 			elasticQuotas: []*v1alpha1.ElasticQuota{
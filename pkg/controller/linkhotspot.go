@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+)
+
+// LinkHotspotController : a controller raising a LinkHotspot for every link of a NetworkTopology whose
+// allocated bandwidth, written by the NetworkTopologyBandwidthController, crosses its utilization threshold, and
+// deleting it once the link cools down
+type LinkHotspotController struct {
+	ntQueue          workqueue.RateLimitingInterface
+	ntLister         schedlister.NetworkTopologyLister
+	lhLister         schedlister.LinkHotspotLister
+	ntListerSynced   cache.InformerSynced
+	lhListerSynced   cache.InformerSynced
+	schedClient      schedclientset.Interface
+	thresholdPercent int32
+}
+
+// NewLinkHotspotController : returns a new *LinkHotspotController. The threshold applies to the links of the
+// topology keys without a LinkUtilizationPolicy, for which the policy's MaxUtilizationPercent is used instead.
+// A threshold of 0 disables the hotspots of these links.
+func NewLinkHotspotController(schedClient schedclientset.Interface,
+	ntInformer schedinformer.NetworkTopologyInformer,
+	lhInformer schedinformer.LinkHotspotInformer,
+	thresholdPercent int32) *LinkHotspotController {
+	ctrl := &LinkHotspotController{
+		ntQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LinkHotspot"),
+		thresholdPercent: thresholdPercent,
+	}
+
+	klog.V(5).InfoS("Setting up NetworkTopology event handlers")
+	ntInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.ntAdded,
+		UpdateFunc: ctrl.ntUpdated,
+	})
+
+	klog.V(5).InfoS("Setting up LinkHotspot event handlers")
+	lhInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: ctrl.lhDeleted,
+	})
+
+	ctrl.ntLister = ntInformer.Lister()
+	ctrl.lhLister = lhInformer.Lister()
+	ctrl.ntListerSynced = ntInformer.Informer().HasSynced
+	ctrl.lhListerSynced = lhInformer.Informer().HasSynced
+	ctrl.schedClient = schedClient
+	return ctrl
+}
+
+// Run : starts listening on channel events
+func (ctrl *LinkHotspotController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.ntQueue.ShutDown()
+
+	klog.InfoS("Starting Link Hotspot controller")
+	defer klog.InfoS("Shutting Link Hotspot controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.lhListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	klog.InfoS("Link Hotspot sync finished")
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// ntAdded : reacts to a NetworkTopology creation
+func (ctrl *LinkHotspotController) ntAdded(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ctrl.ntQueue.Add(key)
+}
+
+// ntUpdated : reacts to a NetworkTopology update
+func (ctrl *LinkHotspotController) ntUpdated(old, new interface{}) {
+	ctrl.ntAdded(new)
+}
+
+// lhDeleted : reacts to a LinkHotspot deletion, restoring it if its link is still hot
+func (ctrl *LinkHotspotController) lhDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	lh, ok := obj.(*v1alpha1.LinkHotspot)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object %#v", obj))
+		return
+	}
+	if name := lh.Labels[v1alpha1.LinkHotspotNetworkTopologyLabel]; name != "" {
+		ctrl.ntQueue.Add(lh.Namespace + "/" + name)
+	}
+}
+
+func (ctrl *LinkHotspotController) worker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem : deals with one key off the queue.  It returns false when it's time to quit.
+func (ctrl *LinkHotspotController) processNextWorkItem() bool {
+	keyObj, quit := ctrl.ntQueue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.ntQueue.Done(keyObj)
+
+	key, ok := keyObj.(string)
+	if !ok {
+		ctrl.ntQueue.Forget(keyObj)
+		runtime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", keyObj))
+		return true
+	}
+	if err := ctrl.syncHandler(key); err != nil {
+		runtime.HandleError(err)
+		klog.ErrorS(err, "Error syncing link hotspots", "networkTopology", key)
+		ctrl.ntQueue.AddRateLimited(key)
+		return true
+	}
+	ctrl.ntQueue.Forget(keyObj)
+	return true
+}
+
+// syncHandler : creates, updates and deletes the LinkHotspots of a NetworkTopology so that they match its
+// hot links. The LinkHotspots of deleted NetworkTopologies are garbage collected through their owner reference.
+func (ctrl *LinkHotspotController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	nt, err := ctrl.ntLister.NetworkTopologies(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("NetworkTopology has been deleted", "networkTopology", key)
+		return nil
+	}
+	if err != nil {
+		klog.V(3).ErrorS(err, "Unable to retrieve NetworkTopology from store", "networkTopology", key)
+		return err
+	}
+
+	desired := map[string]*v1alpha1.LinkHotspot{}
+	// Shadow topologies describe planned changes, their links carry no traffic.
+	if !isShadowNetworkTopology(nt) {
+		desired = ctrl.hotspots(nt)
+	}
+	existing, err := ctrl.lhLister.LinkHotspots(namespace).List(
+		labels.SelectorFromSet(labels.Set{v1alpha1.LinkHotspotNetworkTopologyLabel: name}))
+	if err != nil {
+		return err
+	}
+
+	for _, lh := range existing {
+		want, ok := desired[lh.Name]
+		delete(desired, lh.Name)
+		if !ok {
+			klog.V(4).InfoS("Link cooled down", "linkHotspot", klog.KObj(lh))
+			err := ctrl.schedClient.SchedulingV1alpha1().LinkHotspots(namespace).Delete(context.TODO(), lh.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		if apiequality.Semantic.DeepEqual(lh.Spec, want.Spec) {
+			continue
+		}
+		lhCopy := lh.DeepCopy()
+		lhCopy.Spec = want.Spec
+		if _, err := ctrl.schedClient.SchedulingV1alpha1().LinkHotspots(namespace).Update(context.TODO(), lhCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	for _, lh := range desired {
+		klog.V(4).InfoS("Link crossed its utilization threshold", "linkHotspot", klog.KObj(lh),
+			"origin", lh.Spec.Origin, "destination", lh.Spec.Destination, "utilization", lh.Spec.UtilizationPercent)
+		_, err := ctrl.schedClient.SchedulingV1alpha1().LinkHotspots(namespace).Create(context.TODO(), lh, metav1.CreateOptions{})
+		if err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// hotspots returns the LinkHotspots of the links of a NetworkTopology whose utilization is at least their threshold,
+// by name. Links of unknown capacity are skipped.
+func (ctrl *LinkHotspotController) hotspots(nt *v1alpha1.NetworkTopology) map[string]*v1alpha1.LinkHotspot {
+	thresholds := map[v1alpha1.TopologyKey]int32{}
+	for _, policy := range nt.Spec.LinkPolicies {
+		thresholds[policy.TopologyKey] = policy.MaxUtilizationPercent
+	}
+	owner := metav1.NewControllerRef(nt, v1alpha1.SchemeGroupVersion.WithKind("NetworkTopology"))
+
+	hotspots := map[string]*v1alpha1.LinkHotspot{}
+	for _, w := range nt.Spec.Weights {
+		for _, topology := range w.TopologyList {
+			threshold, ok := thresholds[topology.TopologyKey]
+			if !ok {
+				threshold = ctrl.thresholdPercent
+			}
+			if threshold <= 0 {
+				continue
+			}
+			for _, origin := range topology.OriginList {
+				for _, cost := range origin.CostList {
+					capacity, allocated := costoracle.LinkBandwidth(cost)
+					if capacity.Value() <= 0 {
+						continue
+					}
+					utilization := int32(allocated.Value() * 100 / capacity.Value())
+					if utilization < threshold {
+						continue
+					}
+					name := linkHotspotName(nt.Name, w.Name, topology.TopologyKey, topology.InterfaceClass, origin.Origin, cost.Destination)
+					hotspots[name] = &v1alpha1.LinkHotspot{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            name,
+							Namespace:       nt.Namespace,
+							Labels:          map[string]string{v1alpha1.LinkHotspotNetworkTopologyLabel: nt.Name},
+							OwnerReferences: []metav1.OwnerReference{*owner},
+						},
+						Spec: v1alpha1.LinkHotspotSpec{
+							NetworkTopology:    nt.Name,
+							Weights:            w.Name,
+							TopologyKey:        topology.TopologyKey,
+							Origin:             origin.Origin,
+							Destination:        cost.Destination,
+							BandwidthCapacity:  capacity,
+							BandwidthAllocated: allocated,
+							UtilizationPercent: utilization,
+							ThresholdPercent:   threshold,
+							InterfaceClass:     topology.InterfaceClass,
+						},
+					}
+				}
+			}
+		}
+	}
+	return hotspots
+}
+
+// linkHotspotName returns a stable name for the LinkHotspot of a link. Origins and destinations are hashed
+// as they are not necessarily valid object names. The default interface class is left out of the hash, so
+// that the links of the default class keep their names.
+func linkHotspotName(ntName, weights string, key v1alpha1.TopologyKey, class, origin, destination string) string {
+	h := fnv.New32a()
+	for _, s := range []string{weights, string(key), origin, destination} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	if class != "" {
+		h.Write([]byte(class))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s-%08x", ntName, h.Sum32())
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestLinkHotspotController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	link := func(destination, capacity, allocated string) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: 1,
+			BandwidthCapacity: resource.MustParse(capacity), BandwidthAllocated: resource.MustParse(allocated)}
+	}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyRegion, OriginList: v1alpha1.OriginList{
+					// Hot under the 80% policy of the region links.
+					{Origin: "us-west-1", CostList: v1alpha1.CostList{link("us-east-1", "10G", "8G")}},
+					{Origin: "us-east-1", CostList: v1alpha1.CostList{link("us-west-1", "10G", "7G")}},
+				}},
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					// Hot under the 90% default threshold.
+					{Origin: "z1", CostList: v1alpha1.CostList{link("z2", "1G", "950M"), link("z3", "1G", "500M")}},
+					// Unknown capacity, and hot parallel links.
+					{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z1", NetworkCost: 1}, {Destination: "z3", NetworkCost: 1,
+						Links: []v1alpha1.ParallelLink{
+							{Name: "path-a", BandwidthCapacity: resource.MustParse("1G"), BandwidthAllocated: resource.MustParse("1G")},
+							{Name: "path-b", BandwidthCapacity: resource.MustParse("1G"), BandwidthAllocated: resource.MustParse("900M")},
+						}}}},
+				}},
+			}}},
+			LinkPolicies: []v1alpha1.LinkUtilizationPolicy{{TopologyKey: v1alpha1.NetworkTopologyRegion, MaxUtilizationPercent: 80}},
+		},
+	}
+	// Raised earlier for the z1-z3 link, which since cooled down.
+	stale := &v1alpha1.LinkHotspot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      linkHotspotName(nt.Name, "UserDefined", v1alpha1.NetworkTopologyZone, "", "z1", "z3"),
+			Namespace: "default",
+			Labels:    map[string]string{v1alpha1.LinkHotspotNetworkTopologyLabel: nt.Name},
+		},
+	}
+	shadow := nt.DeepCopy()
+	shadow.Name = "nt-shadow"
+	shadow.Annotations = map[string]string{v1alpha1.NetworkTopologyShadowOfAnnotation: nt.Name}
+	schedClient := schedfake.NewSimpleClientset(nt, shadow, stale)
+
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	ctrl := NewLinkHotspotController(schedClient, schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(),
+		schedInformerFactory.Scheduling().V1alpha1().LinkHotspots(), 90)
+	schedInformerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	expected := map[string]int32{
+		linkHotspotName(nt.Name, "UserDefined", v1alpha1.NetworkTopologyRegion, "", "us-west-1", "us-east-1"): 80,
+		linkHotspotName(nt.Name, "UserDefined", v1alpha1.NetworkTopologyZone, "", "z1", "z2"):                 95,
+		linkHotspotName(nt.Name, "UserDefined", v1alpha1.NetworkTopologyZone, "", "z2", "z3"):                 95,
+	}
+	var hotspots *v1alpha1.LinkHotspotList
+	err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		var err error
+		hotspots, err = schedClient.SchedulingV1alpha1().LinkHotspots("default").List(ctx, metav1.ListOptions{})
+		if err != nil || len(hotspots.Items) != len(expected) {
+			return false, err
+		}
+		for _, lh := range hotspots.Items {
+			if utilization, ok := expected[lh.Name]; !ok || lh.Spec.UtilizationPercent != utilization {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected link hotspots %v, got %v: %v", expected, hotspots, err)
+	}
+}
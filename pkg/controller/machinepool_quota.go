@@ -0,0 +1,344 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+var (
+	// MachineDeploymentsResource : the Cluster API MachineDeployments
+	MachineDeploymentsResource = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machinedeployments"}
+	// MachinePoolsResource : the Cluster API MachinePools
+	MachinePoolsResource = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machinepools"}
+)
+
+// Annotations declaring the capacity of a machine of a pool, as set for the cluster-autoscaler to scale the pool from zero
+const (
+	machineCPUCapacityAnnotation    = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+	machineMemoryCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+	machineDiskCapacityAnnotation   = "capacity.cluster-autoscaler.kubernetes.io/ephemeral-disk"
+	machineGPUTypeAnnotation        = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+	machineGPUCountAnnotation       = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+)
+
+// MachinePoolQuotaController : a controller keeping the max of the ElasticQuotas selecting Cluster API machine pools,
+// through the ElasticQuotaMachinePoolSelectorAnnotation, to the capacity of the pools, so that it follows their scaling
+type MachinePoolQuotaController struct {
+	schedClient   schedclientset.Interface
+	eqLister      schedlister.ElasticQuotaLister
+	poolListers   []cache.GenericLister
+	listersSynced []cache.InformerSynced
+	eqQueue       workqueue.RateLimitingInterface
+}
+
+// NewMachinePoolQuotaController : returns a new *MachinePoolQuotaController watching the pools of the given resources
+func NewMachinePoolQuotaController(schedClient schedclientset.Interface,
+	eqInformer schedinformer.ElasticQuotaInformer,
+	poolInformerFactory dynamicinformer.DynamicSharedInformerFactory,
+	resources []schema.GroupVersionResource) *MachinePoolQuotaController {
+	ctrl := &MachinePoolQuotaController{
+		schedClient:   schedClient,
+		eqLister:      eqInformer.Lister(),
+		listersSynced: []cache.InformerSynced{eqInformer.Informer().HasSynced},
+		eqQueue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "MachinePoolQuota"),
+	}
+
+	klog.V(5).InfoS("Setting up ElasticQuota event handlers")
+	eqInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ctrl.eqAdded,
+		UpdateFunc: func(_, new interface{}) {
+			ctrl.eqAdded(new)
+		},
+	})
+
+	for _, gvr := range resources {
+		klog.V(5).InfoS("Setting up machine pool event handlers", "resource", gvr.String())
+		informer := poolInformerFactory.ForResource(gvr)
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.poolAdded,
+			UpdateFunc: ctrl.poolUpdated,
+			DeleteFunc: ctrl.poolDeleted,
+		})
+		ctrl.poolListers = append(ctrl.poolListers, informer.Lister())
+		ctrl.listersSynced = append(ctrl.listersSynced, informer.Informer().HasSynced)
+	}
+	return ctrl
+}
+
+// Run : starts listening on channel events
+func (ctrl *MachinePoolQuotaController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.eqQueue.ShutDown()
+
+	klog.InfoS("Starting Machine Pool Quota controller")
+	defer klog.InfoS("Shutting Machine Pool Quota controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.listersSynced...) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	klog.InfoS("Machine Pool Quota sync finished")
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// eqAdded : reacts to an ElasticQuota creation or update, if it selects machine pools
+func (ctrl *MachinePoolQuotaController) eqAdded(obj interface{}) {
+	eq := obj.(*v1alpha1.ElasticQuota)
+	if _, ok := eq.Annotations[v1alpha1.ElasticQuotaMachinePoolSelectorAnnotation]; !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(eq)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ctrl.eqQueue.Add(key)
+}
+
+// poolAdded : reacts to a machine pool creation
+func (ctrl *MachinePoolQuotaController) poolAdded(obj interface{}) {
+	if pool, ok := obj.(*unstructured.Unstructured); ok {
+		ctrl.enqueuePoolQuotas(pool.GetLabels())
+	}
+}
+
+// poolUpdated : reacts to a machine pool update, e.g. a scaling, queuing the ElasticQuotas selecting it before and after
+func (ctrl *MachinePoolQuotaController) poolUpdated(old, new interface{}) {
+	oldPool, ok := old.(*unstructured.Unstructured)
+	if ok {
+		ctrl.enqueuePoolQuotas(oldPool.GetLabels())
+	}
+	ctrl.poolAdded(new)
+}
+
+// poolDeleted : reacts to a machine pool deletion
+func (ctrl *MachinePoolQuotaController) poolDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pool, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object %#v", obj))
+		return
+	}
+	ctrl.enqueuePoolQuotas(pool.GetLabels())
+}
+
+// enqueuePoolQuotas : enqueues the ElasticQuotas selecting the machine pools with the given labels
+func (ctrl *MachinePoolQuotaController) enqueuePoolQuotas(poolLabels map[string]string) {
+	eqs, err := ctrl.eqLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing ElasticQuotas")
+		return
+	}
+	for _, eq := range eqs {
+		selector, ok, err := machinePoolSelector(eq)
+		if err != nil || !ok || !selector.Matches(labels.Set(poolLabels)) {
+			continue
+		}
+		ctrl.eqAdded(eq)
+	}
+}
+
+// machinePoolSelector : returns the selector of the machine pools of an ElasticQuota, false if it has none
+func machinePoolSelector(eq *v1alpha1.ElasticQuota) (labels.Selector, bool, error) {
+	value, ok := eq.Annotations[v1alpha1.ElasticQuotaMachinePoolSelectorAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return selector, true, nil
+}
+
+func (ctrl *MachinePoolQuotaController) worker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem : deals with one key off the queue.  It returns false when it's time to quit.
+func (ctrl *MachinePoolQuotaController) processNextWorkItem() bool {
+	keyObj, quit := ctrl.eqQueue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.eqQueue.Done(keyObj)
+
+	key, ok := keyObj.(string)
+	if !ok {
+		ctrl.eqQueue.Forget(keyObj)
+		runtime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", keyObj))
+		return true
+	}
+	if err := ctrl.syncHandler(key); err != nil {
+		runtime.HandleError(err)
+		klog.ErrorS(err, "Error syncing machine pool quota", "elasticQuota", key)
+		ctrl.eqQueue.AddRateLimited(key)
+		return true
+	}
+	ctrl.eqQueue.Forget(keyObj)
+	return true
+}
+
+// syncHandler : sets the max of an ElasticQuota, for every resource the machines of its pools declare, to their
+// capacity summed over the machines. The other resources of the max are kept, as is the max of an ElasticQuota
+// selecting no pool
+func (ctrl *MachinePoolQuotaController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+	eq, err := ctrl.eqLister.ElasticQuotas(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("ElasticQuota has been deleted", "elasticQuota", key)
+		return nil
+	}
+	if err != nil {
+		klog.V(3).ErrorS(err, "Unable to retrieve elastic quota from store", "elasticQuota", key)
+		return err
+	}
+	selector, ok, err := machinePoolSelector(eq)
+	if err != nil {
+		klog.ErrorS(err, "Invalid machine pool selector", "elasticQuota", key)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	capacity, found, err := ctrl.poolsCapacity(selector)
+	if err != nil || !found {
+		return err
+	}
+	newEQ := eq.DeepCopy()
+	if newEQ.Spec.Max == nil {
+		newEQ.Spec.Max = make(v1.ResourceList, len(capacity))
+	}
+	for name, quantity := range capacity {
+		newEQ.Spec.Max[name] = quantity
+	}
+	if apiequality.Semantic.DeepEqual(newEQ.Spec, eq.Spec) {
+		return nil
+	}
+	patch, err := util.CreateMergePatch(eq, newEQ)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Updating the max of the ElasticQuota to the capacity of its machine pools", "elasticQuota", key, "max", newEQ.Spec.Max)
+	_, err = ctrl.schedClient.SchedulingV1alpha1().ElasticQuotas(namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// poolsCapacity : returns the capacity of the machine pools matching selector, false if none matches
+func (ctrl *MachinePoolQuotaController) poolsCapacity(selector labels.Selector) (v1.ResourceList, bool, error) {
+	capacity := make(v1.ResourceList)
+	found := false
+	for _, lister := range ctrl.poolListers {
+		pools, err := lister.List(selector)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, obj := range pools {
+			pool, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			poolCapacity, err := machinePoolCapacity(pool)
+			if err != nil {
+				klog.ErrorS(err, "Ignoring the machine pool with an invalid capacity", "pool", klog.KObj(pool), "kind", pool.GetKind())
+				continue
+			}
+			found = true
+			for name, quantity := range poolCapacity {
+				total := capacity[name]
+				total.Add(quantity)
+				capacity[name] = total
+			}
+		}
+	}
+	return capacity, found, nil
+}
+
+// machinePoolCapacity : returns the capacity of the machines of a pool declared by its annotations, multiplied by its
+// replicas, as reported by its status or, before it is reported, as desired by its spec
+func machinePoolCapacity(pool *unstructured.Unstructured) (v1.ResourceList, error) {
+	replicas, found, err := unstructured.NestedInt64(pool.Object, "status", "replicas")
+	if err == nil && !found {
+		replicas, _, err = unstructured.NestedInt64(pool.Object, "spec", "replicas")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := pool.GetAnnotations()
+	names := map[string]v1.ResourceName{
+		machineCPUCapacityAnnotation:    v1.ResourceCPU,
+		machineMemoryCapacityAnnotation: v1.ResourceMemory,
+		machineDiskCapacityAnnotation:   v1.ResourceEphemeralStorage,
+	}
+	if gpuType := annotations[machineGPUTypeAnnotation]; gpuType != "" {
+		names[machineGPUCountAnnotation] = v1.ResourceName(gpuType)
+	}
+	capacity := make(v1.ResourceList)
+	for annotation, name := range names {
+		value, ok := annotations[annotation]
+		if !ok {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", annotation, err)
+		}
+		if name == v1.ResourceCPU {
+			capacity[name] = *resource.NewMilliQuantity(quantity.MilliValue()*replicas, quantity.Format)
+		} else {
+			capacity[name] = *resource.NewQuantity(quantity.Value()*replicas, quantity.Format)
+		}
+	}
+	return capacity, nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestMachinePoolQuotaController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	makePool := func(kind, name, quota string, annotations map[string]string, field string, replicas int64) *unstructured.Unstructured {
+		pool := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		pool.SetAPIVersion("cluster.x-k8s.io/v1beta1")
+		pool.SetKind(kind)
+		pool.SetNamespace("capi")
+		pool.SetName(name)
+		pool.SetLabels(map[string]string{"quota": quota})
+		pool.SetAnnotations(annotations)
+		if err := unstructured.SetNestedField(pool.Object, replicas, field, "replicas"); err != nil {
+			t.Fatal(err)
+		}
+		return pool
+	}
+	makeEQ := func(name, selector string, max v1.ResourceList) *v1alpha1.ElasticQuota {
+		eq := &v1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.ElasticQuotaSpec{Max: max},
+		}
+		if selector != "" {
+			eq.Annotations = map[string]string{v1alpha1.ElasticQuotaMachinePoolSelectorAnnotation: selector}
+		}
+		return eq
+	}
+	deployment := makePool("MachineDeployment", "md-a", "team-a", map[string]string{
+		machineCPUCapacityAnnotation:    "4",
+		machineMemoryCapacityAnnotation: "16Gi",
+	}, "status", 3)
+	pools := []runtime.Object{
+		deployment,
+		// Not reported yet, its desired replicas count.
+		makePool("MachinePool", "mp-a", "team-a", map[string]string{
+			machineCPUCapacityAnnotation: "8",
+			machineGPUTypeAnnotation:     "nvidia.com/gpu",
+			machineGPUCountAnnotation:    "1",
+		}, "spec", 2),
+		makePool("MachineDeployment", "md-b", "team-b", map[string]string{machineCPUCapacityAnnotation: "64"}, "status", 10),
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		MachineDeploymentsResource: "MachineDeploymentList",
+		MachinePoolsResource:       "MachinePoolList",
+	}, pools...)
+	schedClient := schedfake.NewSimpleClientset(
+		makeEQ("team-a", "quota=team-a", v1.ResourceList{v1.ResourceCPU: resource.MustParse("10"), v1.ResourcePods: resource.MustParse("100")}),
+		makeEQ("manual", "", v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}),
+		makeEQ("no-pool", "quota=none", v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}),
+	)
+
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	poolInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, controller.NoResyncPeriodFunc())
+	ctrl := NewMachinePoolQuotaController(schedClient, schedInformerFactory.Scheduling().V1alpha1().ElasticQuotas(),
+		poolInformerFactory, []schema.GroupVersionResource{MachineDeploymentsResource, MachinePoolsResource})
+	schedInformerFactory.Start(ctx.Done())
+	poolInformerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	waitForMax := func(expected map[string]v1.ResourceList) {
+		t.Helper()
+		err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+			for name, max := range expected {
+				eq, err := schedClient.SchedulingV1alpha1().ElasticQuotas("default").Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if len(eq.Spec.Max) != len(max) {
+					return false, nil
+				}
+				for resourceName, quantity := range max {
+					if got, ok := eq.Spec.Max[resourceName]; !ok || got.Cmp(quantity) != 0 {
+						return false, nil
+					}
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			t.Fatalf("ElasticQuotas max not updated to %v: %v", expected, err)
+		}
+	}
+	expected := map[string]v1.ResourceList{
+		"team-a": {
+			v1.ResourceCPU:    resource.MustParse("28"),
+			v1.ResourceMemory: resource.MustParse("48Gi"),
+			"nvidia.com/gpu":  resource.MustParse("2"),
+			v1.ResourcePods:   resource.MustParse("100"),
+		},
+		"manual":  {v1.ResourceCPU: resource.MustParse("1")},
+		"no-pool": {v1.ResourceCPU: resource.MustParse("2")},
+	}
+	waitForMax(expected)
+
+	scaled := deployment.DeepCopy()
+	if err := unstructured.SetNestedField(scaled.Object, int64(5), "status", "replicas"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dynamicClient.Resource(MachineDeploymentsResource).Namespace("capi").Update(ctx, scaled, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expected["team-a"][v1.ResourceCPU] = resource.MustParse("36")
+	expected["team-a"][v1.ResourceMemory] = resource.MustParse("80Gi")
+	waitForMax(expected)
+}
+
+func TestMachinePoolCapacity(t *testing.T) {
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	pool.SetAnnotations(map[string]string{machineCPUCapacityAnnotation: "500m", machineMemoryCapacityAnnotation: "invalid"})
+	if _, err := machinePoolCapacity(pool); err == nil {
+		t.Errorf("expected an error for an invalid capacity")
+	}
+
+	pool.SetAnnotations(map[string]string{machineCPUCapacityAnnotation: "500m", machineDiskCapacityAnnotation: "100G"})
+	capacity, err := machinePoolCapacity(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[v1.ResourceName]string{v1.ResourceCPU: "0", v1.ResourceEphemeralStorage: "0"} {
+		if got := capacity[name]; got.Cmp(resource.MustParse(expected)) != 0 {
+			t.Errorf("expected no %v capacity without replicas, got %v", name, got.String())
+		}
+	}
+
+	if err := unstructured.SetNestedField(pool.Object, int64(3), "spec", "replicas"); err != nil {
+		t.Fatal(err)
+	}
+	capacity, err = machinePoolCapacity(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[v1.ResourceName]string{v1.ResourceCPU: "1500m", v1.ResourceEphemeralStorage: "300G"} {
+		if got := capacity[name]; got.Cmp(resource.MustParse(expected)) != 0 {
+			t.Errorf("expected %v %v, got %v", expected, name, got.String())
+		}
+	}
+}
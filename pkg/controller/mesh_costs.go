@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+// MeshProvider is a service mesh exporting the latencies of the requests between workloads to Prometheus.
+type MeshProvider string
+
+const (
+	// MeshIstio reads the istio_request_duration_milliseconds histogram reported by the source proxies.
+	MeshIstio MeshProvider = "istio"
+	// MeshLinkerd reads the response_latency_ms histogram of the outbound traffic of the proxies.
+	MeshLinkerd MeshProvider = "linkerd"
+)
+
+// meshQuery is the latency percentile query of a provider, by source and destination locality.
+type meshQuery struct {
+	// expression formats the quantile into a PromQL expression.
+	expression       string
+	originLabel      string
+	destinationLabel string
+}
+
+// meshQueries hold the queries of the providers. The localities of the source and destination workloads
+// are expected as labels of the metrics, e.g. added as tags of the Istio standard metrics.
+var meshQueries = map[MeshProvider]meshQuery{
+	MeshIstio: {
+		expression:       `histogram_quantile(%v, sum by (le, source_zone, destination_zone) (rate(istio_request_duration_milliseconds_bucket{reporter="source"}[5m])))`,
+		originLabel:      "source_zone",
+		destinationLabel: "destination_zone",
+	},
+	MeshLinkerd: {
+		expression:       `histogram_quantile(%v, sum by (le, src_zone, dst_zone) (rate(response_latency_ms_bucket{direction="outbound"}[5m])))`,
+		originLabel:      "src_zone",
+		destinationLabel: "dst_zone",
+	},
+}
+
+// MeshCostOptions configures the ingestion of the latencies measured by a service mesh as the costs of a
+// NetworkTopology, so that the zones the mesh sees traffic between get costs without probes.
+type MeshCostOptions struct {
+	// PrometheusAddress is the address of the Prometheus scraping the mesh. Ingestion is disabled if empty.
+	PrometheusAddress string
+	// Provider is the service mesh, selecting the metrics to query.
+	Provider MeshProvider
+	// Quantile is the latency percentile used as cost, in (0, 1].
+	Quantile float64
+	// NetworkTopology is the namespace/name of the NetworkTopology receiving the costs.
+	NetworkTopology string
+	// WeightsName is the name of the weights of the NetworkTopology holding the costs.
+	WeightsName string
+	// Interval is the period between two queries.
+	Interval time.Duration
+}
+
+// MeshCostController : a controller writing the latencies between zones measured by a service mesh,
+// in milliseconds, as the zone costs of a NetworkTopology
+type MeshCostController struct {
+	MeshCostOptions
+
+	query          meshQuery
+	prometheus     promv1.API
+	ntLister       schedlister.NetworkTopologyLister
+	ntListerSynced cache.InformerSynced
+	schedClient    schedclientset.Interface
+}
+
+// NewMeshCostController : returns a new *MeshCostController
+func NewMeshCostController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	options MeshCostOptions) (*MeshCostController, error) {
+	query, ok := meshQueries[options.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown service mesh %q", options.Provider)
+	}
+	if options.Quantile <= 0 || options.Quantile > 1 {
+		return nil, fmt.Errorf("latency quantile %v out of (0, 1]", options.Quantile)
+	}
+	if _, _, err := cache.SplitMetaNamespaceKey(options.NetworkTopology); err != nil || options.NetworkTopology == "" {
+		return nil, fmt.Errorf("invalid NetworkTopology %q, expected namespace/name", options.NetworkTopology)
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: options.PrometheusAddress})
+	if err != nil {
+		return nil, err
+	}
+	return &MeshCostController{
+		MeshCostOptions: options,
+		query:           query,
+		prometheus:      promv1.NewAPI(client),
+		ntLister:        ntInformer.Lister(),
+		ntListerSynced:  ntInformer.Informer().HasSynced,
+		schedClient:     schedClient,
+	}, nil
+}
+
+// Run : ingests the costs every Interval
+func (ctrl *MeshCostController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting Mesh Cost controller", "provider", ctrl.Provider, "networkTopology", ctrl.NetworkTopology)
+	defer klog.InfoS("Shutting Mesh Cost controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error ingesting service mesh costs", "networkTopology", ctrl.NetworkTopology)
+		}
+	}, ctrl.Interval, stopCh)
+}
+
+// sync : queries the latencies and updates the weights of the NetworkTopology if they changed
+func (ctrl *MeshCostController) sync(ctx context.Context) error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(ctrl.NetworkTopology)
+	nt, err := ctrl.ntLister.NetworkTopologies(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(4).InfoS("NetworkTopology receiving service mesh costs not found", "networkTopology", ctrl.NetworkTopology)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	value, warnings, err := ctrl.prometheus.Query(ctx, fmt.Sprintf(ctrl.query.expression, ctrl.Quantile), time.Now())
+	if err != nil {
+		return fmt.Errorf("querying the latencies: %w", err)
+	}
+	if len(warnings) != 0 {
+		klog.V(4).InfoS("Warnings querying service mesh latencies", "warnings", warnings)
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return fmt.Errorf("unexpected %v result of the latency query", value.Type())
+	}
+
+	topology := ctrl.zoneCosts(vector)
+	if len(topology.OriginList) == 0 {
+		// Keep the last costs while the mesh reports no traffic between zones.
+		klog.V(4).InfoS("No service mesh latency between zones", "networkTopology", klog.KObj(nt))
+		return nil
+	}
+	return updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+		if current, ok := weightsTopology(nt, ctrl.WeightsName, topology.TopologyKey); ok && sameCosts(current, topology) {
+			return false
+		}
+		setWeightsTopology(nt, ctrl.WeightsName, topology)
+		klog.V(4).InfoS("Updating service mesh costs", "networkTopology", klog.KObj(nt), "origins", len(topology.OriginList))
+		return true
+	})
+}
+
+// zoneCosts : returns the zone costs of the latencies, sorted by origin and destination
+func (ctrl *MeshCostController) zoneCosts(vector model.Vector) v1alpha1.TopologyInfo {
+	costs := make(map[string]map[string]int64)
+	for _, sample := range vector {
+		origin := string(sample.Metric[model.LabelName(ctrl.query.originLabel)])
+		destination := string(sample.Metric[model.LabelName(ctrl.query.destinationLabel)])
+		latency := float64(sample.Value)
+		// Traffic within a zone, or too scarce for a percentile, gives no cost.
+		if origin == "" || destination == "" || origin == destination || math.IsNaN(latency) || math.IsInf(latency, 0) {
+			continue
+		}
+		if costs[origin] == nil {
+			costs[origin] = make(map[string]int64)
+		}
+		costs[origin][destination] = int64(math.Round(latency))
+	}
+
+	topology := v1alpha1.TopologyInfo{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds}
+	for origin, destinations := range costs {
+		info := v1alpha1.OriginInfo{Origin: origin}
+		for destination, cost := range destinations {
+			info.CostList = append(info.CostList, v1alpha1.CostInfo{Destination: destination, NetworkCost: cost})
+		}
+		sort.Slice(info.CostList, func(i, j int) bool { return info.CostList[i].Destination < info.CostList[j].Destination })
+		topology.OriginList = append(topology.OriginList, info)
+	}
+	sort.Slice(topology.OriginList, func(i, j int) bool { return topology.OriginList[i].Origin < topology.OriginList[j].Origin })
+	return topology
+}
+
+// setWeightsTopology : replaces the topology of the same key and interface class in the weights of nt named
+// weightsName, adding the weights or the topology if missing. The bandwidth of the links replaced is kept, see
+// keepBandwidth
+func setWeightsTopology(nt *v1alpha1.NetworkTopology, weightsName string, topology v1alpha1.TopologyInfo) {
+	for i := range nt.Spec.Weights {
+		w := &nt.Spec.Weights[i]
+		if w.Name != weightsName {
+			continue
+		}
+		for j := range w.TopologyList {
+			if w.TopologyList[j].TopologyKey == topology.TopologyKey && w.TopologyList[j].InterfaceClass == topology.InterfaceClass {
+				w.TopologyList[j] = keepBandwidth(topology, w.TopologyList[j])
+				return
+			}
+		}
+		w.TopologyList = append(w.TopologyList, topology)
+		return
+	}
+	nt.Spec.Weights = append(nt.Spec.Weights, v1alpha1.WeightInfo{Name: weightsName, TopologyList: v1alpha1.TopologyList{topology}})
+}
+
+// keepBandwidth : returns a copy of topology with the bandwidth capacity, the allocated bandwidth and the parallel
+// links of the links of current, the controllers rewriting the costs of the links but neither the bandwidth declared
+// by the users nor the one allocated by the NetworkTopologyBandwidthController
+func keepBandwidth(topology, current v1alpha1.TopologyInfo) v1alpha1.TopologyInfo {
+	links := make(map[string]map[string]v1alpha1.CostInfo)
+	for _, o := range current.OriginList {
+		links[o.Origin] = make(map[string]v1alpha1.CostInfo, len(o.CostList))
+		for _, c := range o.CostList {
+			links[o.Origin][c.Destination] = c
+		}
+	}
+	kept := topology.DeepCopy()
+	for i := range kept.OriginList {
+		o := &kept.OriginList[i]
+		for j := range o.CostList {
+			c := &o.CostList[j]
+			if l, ok := links[o.Origin][c.Destination]; ok {
+				c.BandwidthCapacity = l.BandwidthCapacity.DeepCopy()
+				c.BandwidthAllocated = l.BandwidthAllocated.DeepCopy()
+				c.Links = append([]v1alpha1.ParallelLink(nil), l.Links...)
+			}
+		}
+	}
+	return *kept
+}
+
+// weightsTopology : returns the topology of the given key and of the default interface class in the weights
+// of nt named weightsName
+func weightsTopology(nt *v1alpha1.NetworkTopology, weightsName string, key v1alpha1.TopologyKey) (v1alpha1.TopologyInfo, bool) {
+	for _, w := range nt.Spec.Weights {
+		if w.Name != weightsName {
+			continue
+		}
+		for _, t := range w.TopologyList {
+			if t.TopologyKey == key && t.InterfaceClass == "" {
+				return t, true
+			}
+		}
+	}
+	return v1alpha1.TopologyInfo{}, false
+}
+
+// sameCosts : tells whether two topologies list the same links with the same costs, in the same order
+func sameCosts(a, b v1alpha1.TopologyInfo) bool {
+	if len(a.OriginList) != len(b.OriginList) {
+		return false
+	}
+	for i, o := range a.OriginList {
+		if o.Origin != b.OriginList[i].Origin || len(o.CostList) != len(b.OriginList[i].CostList) {
+			return false
+		}
+		for j, c := range o.CostList {
+			other := b.OriginList[i].CostList[j]
+			if c.Destination != other.Destination || c.NetworkCost != other.NetworkCost {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestMeshCostController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || !strings.HasPrefix(r.Form.Get("query"), "histogram_quantile(0.9,") ||
+			!strings.Contains(r.Form.Get("query"), "istio_request_duration_milliseconds_bucket") {
+			http.Error(w, fmt.Sprintf("unexpected query %q", r.Form.Get("query")), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"source_zone":"z1","destination_zone":"z2"},"value":[1650000000,"12.4"]},
+			{"metric":{"source_zone":"z1","destination_zone":"z1"},"value":[1650000000,"0.8"]},
+			{"metric":{"source_zone":"z2","destination_zone":"z1"},"value":[1650000000,"NaN"]},
+			{"metric":{"source_zone":"z2","destination_zone":"z3"},"value":[1650000000,"30.6"]},
+			{"metric":{"destination_zone":"z3"},"value":[1650000000,"5"]}
+		]}}`)
+	}))
+	defer prometheus.Close()
+
+	userDefined := v1alpha1.WeightInfo{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{{
+		TopologyKey: v1alpha1.NetworkTopologyZone,
+		OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 5}}}},
+	}}}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec:       v1alpha1.NetworkTopologySpec{Weights: v1alpha1.WeightList{userDefined}},
+	}
+	schedClient := schedfake.NewSimpleClientset(nt)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	ctrl, err := NewMeshCostController(schedClient, schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), MeshCostOptions{
+		PrometheusAddress: prometheus.URL,
+		Provider:          MeshIstio,
+		Quantile:          0.9,
+		NetworkTopology:   "default/nt-test",
+		WeightsName:       "Mesh",
+		Interval:          100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedInformerFactory.Start(ctx.Done())
+	go ctrl.Run(ctx.Done())
+
+	expected := v1alpha1.TopologyInfo{
+		TopologyKey: v1alpha1.NetworkTopologyZone,
+		CostUnit:    v1alpha1.NetworkCostUnitMilliseconds,
+		OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 12}}},
+			{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 31}}},
+		},
+	}
+	var got *v1alpha1.NetworkTopology
+	err = wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		got, err = schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt-test", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		mesh, ok := weightsTopology(got, "Mesh", v1alpha1.NetworkTopologyZone)
+		return ok && sameCosts(mesh, expected), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the mesh costs %v, got %v: %v", expected, got.Spec.Weights, err)
+	}
+	if manual, ok := weightsTopology(got, "UserDefined", v1alpha1.NetworkTopologyZone); !ok || !sameCosts(manual, userDefined.TopologyList[0]) {
+		t.Errorf("Expected the UserDefined costs to be kept, got %v", got.Spec.Weights)
+	}
+}
+
+func TestNewMeshCostController(t *testing.T) {
+	ntInformer := schedinformer.NewSharedInformerFactory(schedfake.NewSimpleClientset(), 0).Scheduling().V1alpha1().NetworkTopologies()
+	valid := MeshCostOptions{PrometheusAddress: "http://prometheus:9090", Provider: MeshLinkerd, Quantile: 0.5, NetworkTopology: "default/nt"}
+	tests := []struct {
+		name    string
+		mutate  func(o *MeshCostOptions)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(o *MeshCostOptions) {}},
+		{name: "unknown provider", mutate: func(o *MeshCostOptions) { o.Provider = "consul" }, wantErr: true},
+		{name: "quantile out of range", mutate: func(o *MeshCostOptions) { o.Quantile = 99 }, wantErr: true},
+		{name: "missing NetworkTopology", mutate: func(o *MeshCostOptions) { o.NetworkTopology = "" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := valid
+			tt.mutate(&options)
+			if _, err := NewMeshCostController(nil, ntInformer, options); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
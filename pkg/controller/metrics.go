@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+)
+
+const metricsSubsystem = "scheduler_plugins_controller"
+
+var (
+	podGroupsPendingDesc = metrics.NewDesc(
+		metrics.BuildFQName("", metricsSubsystem, "podgroups_pending"),
+		"Number of PodGroups not running yet, by namespace and reason.",
+		[]string{"namespace", "reason"}, nil, metrics.ALPHA, "")
+	appGroupNetworkCostDesc = metrics.NewDesc(
+		metrics.BuildFQName("", metricsSubsystem, "appgroup_network_cost"),
+		"Network cost between the dependent workloads of an AppGroup placed after its zone recommendations.",
+		[]string{"namespace", "appgroup"}, nil, metrics.ALPHA, "")
+	elasticQuotaUtilizationDesc = metrics.NewDesc(
+		metrics.BuildFQName("", metricsSubsystem, "elasticquota_utilization_ratio"),
+		"Ratio of the used resources of an ElasticQuota to its min or max bound.",
+		[]string{"namespace", "elasticquota", "resource", "bound"}, nil, metrics.ALPHA, "")
+	linkHeadroomDesc = metrics.NewDesc(
+		metrics.BuildFQName("", metricsSubsystem, "network_link_bandwidth_headroom"),
+		"Bandwidth still available on a link of a NetworkTopology, honoring its link utilization policies.",
+		[]string{"namespace", "networktopology", "weights", "topology_key", "interface_class", "origin", "destination"}, nil, metrics.ALPHA, "")
+)
+
+// MetricsCollector publishes the state of the PodGroups, ElasticQuotas, AppGroups and
+// NetworkTopologies known to the controller, computed from the informer caches at scrape time.
+type MetricsCollector struct {
+	metrics.BaseStableCollector
+
+	pgLister schedlister.PodGroupLister
+	eqLister schedlister.ElasticQuotaLister
+	agLister schedlister.AppGroupLister
+	ntLister schedlister.NetworkTopologyLister
+}
+
+// NewMetricsCollector returns a new *MetricsCollector
+func NewMetricsCollector(pgInformer schedinformer.PodGroupInformer,
+	eqInformer schedinformer.ElasticQuotaInformer,
+	agInformer schedinformer.AppGroupInformer,
+	ntInformer schedinformer.NetworkTopologyInformer) *MetricsCollector {
+	return &MetricsCollector{
+		pgLister: pgInformer.Lister(),
+		eqLister: eqInformer.Lister(),
+		agLister: agInformer.Lister(),
+		ntLister: ntInformer.Lister(),
+	}
+}
+
+// DescribeWithStability implements metrics.StableCollector.
+func (c *MetricsCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- podGroupsPendingDesc
+	ch <- appGroupNetworkCostDesc
+	ch <- elasticQuotaUtilizationDesc
+	ch <- linkHeadroomDesc
+}
+
+// CollectWithStability implements metrics.StableCollector.
+func (c *MetricsCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	c.collectPodGroups(ch)
+	c.collectAppGroups(ch)
+	c.collectElasticQuotas(ch)
+	c.collectNetworkTopologies(ch)
+}
+
+type pendingKey struct {
+	namespace string
+	reason    string
+}
+
+func (c *MetricsCollector) collectPodGroups(ch chan<- metrics.Metric) {
+	pgs, err := c.pgLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list PodGroups")
+		return
+	}
+	pending := make(map[pendingKey]int)
+	for _, pg := range pgs {
+		if reason, ok := pendingReason(pg); ok {
+			pending[pendingKey{namespace: pg.Namespace, reason: reason}]++
+		}
+	}
+	for k, n := range pending {
+		ch <- metrics.NewLazyConstMetric(podGroupsPendingDesc, metrics.GaugeValue, float64(n), k.namespace, k.reason)
+	}
+}
+
+// pendingReason returns why pg is not running yet: the reason of a MinMemberSatisfiable=False
+// condition if any, its phase otherwise.
+func pendingReason(pg *v1alpha1.PodGroup) (string, bool) {
+	switch pg.Status.Phase {
+	case v1alpha1.PodGroupRunning, v1alpha1.PodGroupScheduled, v1alpha1.PodGroupFinished, v1alpha1.PodGroupFailed:
+		return "", false
+	}
+	cond := meta.FindStatusCondition(pg.Status.Conditions, v1alpha1.PodGroupMinMemberSatisfiable)
+	if cond != nil && cond.Status == metav1.ConditionFalse {
+		return cond.Reason, true
+	}
+	if pg.Status.Phase == "" {
+		return string(v1alpha1.PodGroupPending), true
+	}
+	return string(pg.Status.Phase), true
+}
+
+func (c *MetricsCollector) collectAppGroups(ch chan<- metrics.Metric) {
+	ags, err := c.agLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list AppGroups")
+		return
+	}
+	for _, ag := range ags {
+		zd := ag.Spec.ZoneDistribution
+		if zd == nil {
+			continue
+		}
+		nt, err := c.ntLister.NetworkTopologies(ag.Namespace).Get(zd.NetworkTopologyName)
+		if err != nil {
+			klog.V(5).InfoS("NetworkTopology of AppGroup not found", "appGroup", klog.KObj(ag), "networkTopology", zd.NetworkTopologyName)
+			continue
+		}
+		weightsName, _ := zoneDistributionParams(zd)
+		var total int64
+		for _, cost := range placementCosts(ag, ag.Status.ZoneRecommendations, newZoneCostTable(nt, weightsName)) {
+			total += cost
+		}
+		// Every dependency is accounted by both of its workloads.
+		ch <- metrics.NewLazyConstMetric(appGroupNetworkCostDesc, metrics.GaugeValue, float64(total/2), ag.Namespace, ag.Name)
+	}
+}
+
+func (c *MetricsCollector) collectElasticQuotas(ch chan<- metrics.Metric) {
+	eqs, err := c.eqLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ElasticQuotas")
+		return
+	}
+	for _, eq := range eqs {
+		for name, used := range eq.Status.Used {
+			if min, ok := eq.Spec.Min[name]; ok && !min.IsZero() {
+				ch <- metrics.NewLazyConstMetric(elasticQuotaUtilizationDesc, metrics.GaugeValue,
+					float64(used.MilliValue())/float64(min.MilliValue()), eq.Namespace, eq.Name, string(name), "min")
+			}
+			if max, ok := eq.Spec.Max[name]; ok && !max.IsZero() {
+				ch <- metrics.NewLazyConstMetric(elasticQuotaUtilizationDesc, metrics.GaugeValue,
+					float64(used.MilliValue())/float64(max.MilliValue()), eq.Namespace, eq.Name, string(name), "max")
+			}
+		}
+	}
+}
+
+func (c *MetricsCollector) collectNetworkTopologies(ch chan<- metrics.Metric) {
+	nts, err := c.ntLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list NetworkTopologies")
+		return
+	}
+	for _, nt := range nts {
+		if isShadowNetworkTopology(nt) {
+			continue
+		}
+		maxUtilization := make(map[v1alpha1.TopologyKey]int32, len(nt.Spec.LinkPolicies))
+		for _, p := range nt.Spec.LinkPolicies {
+			maxUtilization[p.TopologyKey] = p.MaxUtilizationPercent
+		}
+		for _, w := range nt.Spec.Weights {
+			for _, t := range w.TopologyList {
+				for _, o := range t.OriginList {
+					for _, cost := range o.CostList {
+						// Links without a known capacity have no meaningful headroom.
+						if capacity, _ := costoracle.LinkBandwidth(cost); capacity.IsZero() {
+							continue
+						}
+						headroom := costoracle.LinkHeadroom(cost, maxUtilization[t.TopologyKey])
+						ch <- metrics.NewLazyConstMetric(linkHeadroomDesc, metrics.GaugeValue, float64(headroom.Value()),
+							nt.Namespace, nt.Name, w.Name, string(t.TopologyKey), t.InterfaceClass, o.Origin, cost.Destination)
+					}
+				}
+			}
+		}
+	}
+}
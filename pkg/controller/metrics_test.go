@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	pgs := []*v1alpha1.PodGroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "ns1"}, Status: v1alpha1.PodGroupStatus{Phase: v1alpha1.PodGroupPending}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pg2", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pg3", Namespace: "ns1"}, Status: v1alpha1.PodGroupStatus{Phase: v1alpha1.PodGroupRunning}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pg4", Namespace: "ns2"}, Status: v1alpha1.PodGroupStatus{
+			Phase: v1alpha1.PodGroupPending,
+			Conditions: []metav1.Condition{{
+				Type:   v1alpha1.PodGroupMinMemberSatisfiable,
+				Status: metav1.ConditionFalse,
+				Reason: "MinMemberExceedsParallelism",
+			}},
+		}},
+	}
+	eqs := []*v1alpha1.ElasticQuota{{
+		ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: "ns1"},
+		Spec: v1alpha1.ElasticQuotaSpec{
+			Min: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		},
+		Status: v1alpha1.ElasticQuotaStatus{Used: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+	}}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{{
+						Origin: "z1",
+						CostList: v1alpha1.CostList{
+							{Destination: "z2", NetworkCost: 10, BandwidthCapacity: resource.MustParse("1000"), BandwidthAllocated: resource.MustParse("300")},
+							{Destination: "z3", NetworkCost: 20},
+						},
+					}},
+				}},
+			}},
+			LinkPolicies: []v1alpha1.LinkUtilizationPolicy{{TopologyKey: v1alpha1.NetworkTopologyZone, MaxUtilizationPercent: 80}},
+		},
+	}
+	p1 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P1-deployment", Selector: "P1", APIVersion: "apps/v1", Namespace: "default"}
+	p2 := v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "P2-deployment", Selector: "P2", APIVersion: "apps/v1", Namespace: "default"}
+	ag := makeAG("ag", 2, v1alpha1.AppGroupKahnSort, v1alpha1.AppGroupWorkloadList{
+		{Workload: p1, Dependencies: v1alpha1.DependenciesList{{Workload: p2}}},
+		{Workload: p2},
+	}, nil)
+	ag.Spec.ZoneDistribution = &v1alpha1.AppGroupZoneDistribution{NetworkTopologyName: "nt"}
+	ag.Status.ZoneRecommendations = v1alpha1.AppGroupZoneRecommendationList{
+		{Workload: p1, Zones: v1alpha1.ZoneReplicasList{{Zone: "z1", Replicas: 1}}},
+		{Workload: p2, Zones: v1alpha1.ZoneReplicasList{{Zone: "z2", Replicas: 2}}},
+	}
+
+	informerFactory := schedinformer.NewSharedInformerFactory(schedfake.NewSimpleClientset(), 0)
+	pgInformer := informerFactory.Scheduling().V1alpha1().PodGroups()
+	eqInformer := informerFactory.Scheduling().V1alpha1().ElasticQuotas()
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	ntInformer := informerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	for _, pg := range pgs {
+		pgInformer.Informer().GetStore().Add(pg)
+	}
+	for _, eq := range eqs {
+		eqInformer.Informer().GetStore().Add(eq)
+	}
+	agInformer.Informer().GetStore().Add(ag)
+	ntInformer.Informer().GetStore().Add(nt)
+
+	expected := `
+# HELP scheduler_plugins_controller_appgroup_network_cost [ALPHA] Network cost between the dependent workloads of an AppGroup placed after its zone recommendations.
+# TYPE scheduler_plugins_controller_appgroup_network_cost gauge
+scheduler_plugins_controller_appgroup_network_cost{appgroup="ag",namespace="default"} 20
+# HELP scheduler_plugins_controller_elasticquota_utilization_ratio [ALPHA] Ratio of the used resources of an ElasticQuota to its min or max bound.
+# TYPE scheduler_plugins_controller_elasticquota_utilization_ratio gauge
+scheduler_plugins_controller_elasticquota_utilization_ratio{bound="max",elasticquota="eq",namespace="ns1",resource="cpu"} 0.25
+scheduler_plugins_controller_elasticquota_utilization_ratio{bound="min",elasticquota="eq",namespace="ns1",resource="cpu"} 0.5
+# HELP scheduler_plugins_controller_network_link_bandwidth_headroom [ALPHA] Bandwidth still available on a link of a NetworkTopology, honoring its link utilization policies.
+# TYPE scheduler_plugins_controller_network_link_bandwidth_headroom gauge
+scheduler_plugins_controller_network_link_bandwidth_headroom{destination="z2",interface_class="",namespace="default",networktopology="nt",origin="z1",topology_key="topology.kubernetes.io/zone",weights="UserDefined"} 500
+# HELP scheduler_plugins_controller_podgroups_pending [ALPHA] Number of PodGroups not running yet, by namespace and reason.
+# TYPE scheduler_plugins_controller_podgroups_pending gauge
+scheduler_plugins_controller_podgroups_pending{namespace="ns1",reason="Pending"} 2
+scheduler_plugins_controller_podgroups_pending{namespace="ns2",reason="MinMemberExceedsParallelism"} 1
+`
+	collector := NewMetricsCollector(pgInformer, eqInformer, agInformer, ntInformer)
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+const (
+	// DefaultNetperfAgentPath is the path of the endpoint of the agents if not specified.
+	DefaultNetperfAgentPath = "/rtt"
+	// DefaultNetperfAgentWeightsName is the name of the weights receiving the costs if not specified.
+	DefaultNetperfAgentWeightsName = "NetperfAgent"
+)
+
+// netperfAgentReport is the response of the endpoint of an agent: the round-trip times measured from the node
+// of the agent to the other nodes.
+type netperfAgentReport struct {
+	RTTs []netperfAgentRTT `json:"rtts"`
+}
+
+// netperfAgentRTT is the round-trip time to a node.
+type netperfAgentRTT struct {
+	Node         string  `json:"node"`
+	Milliseconds float64 `json:"milliseconds"`
+}
+
+// NetperfCostOptions configures the ingestion of the round-trip times measured by the agents of the
+// NetworkTopologies whose cost source is NetperfAgent.
+type NetperfCostOptions struct {
+	// Interval is the period between two queries of the agents.
+	Interval time.Duration
+	// Timeout is the timeout of a query of an agent.
+	Timeout time.Duration
+	// Workers is the number of agents queried in parallel, 1 if lower.
+	Workers int
+}
+
+// NetperfCostController : a controller writing the average round-trip times between the domains of the tiers of the
+// nodes, the zones and regions by default, measured by the agents of a NetworkTopology, in milliseconds, as its costs
+type NetperfCostController struct {
+	NetperfCostOptions
+
+	ntLister         schedlister.NetworkTopologyLister
+	podLister        corelister.PodLister
+	nodeLister       corelister.NodeLister
+	ntListerSynced   cache.InformerSynced
+	podListerSynced  cache.InformerSynced
+	nodeListerSynced cache.InformerSynced
+	schedClient      schedclientset.Interface
+	client           *http.Client
+}
+
+// NewNetperfCostController : returns a new *NetperfCostController
+func NewNetperfCostController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	podInformer coreinformer.PodInformer, nodeInformer coreinformer.NodeInformer, options NetperfCostOptions) *NetperfCostController {
+	return &NetperfCostController{
+		NetperfCostOptions: options,
+		ntLister:           ntInformer.Lister(),
+		podLister:          podInformer.Lister(),
+		nodeLister:         nodeInformer.Lister(),
+		ntListerSynced:     ntInformer.Informer().HasSynced,
+		podListerSynced:    podInformer.Informer().HasSynced,
+		nodeListerSynced:   nodeInformer.Informer().HasSynced,
+		schedClient:        schedClient,
+		client:             &http.Client{Timeout: options.Timeout},
+	}
+}
+
+// Run : ingests the costs every Interval
+func (ctrl *NetperfCostController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting Netperf Cost controller", "interval", ctrl.Interval)
+	defer klog.InfoS("Shutting Netperf Cost controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.podListerSynced, ctrl.nodeListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error ingesting netperf agent costs")
+		}
+	}, ctrl.Interval, stopCh)
+}
+
+// sync : ingests the costs of the NetworkTopologies whose cost source is NetperfAgent
+func (ctrl *NetperfCostController) sync(ctx context.Context) error {
+	nts, err := ctrl.ntLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, nt := range nts {
+		if source := nt.Spec.CostSource; source == nil || source.Type != v1alpha1.CostSourceNetperfAgent {
+			continue
+		}
+		if err := ctrl.syncNetworkTopology(ctx, nt); err != nil {
+			klog.ErrorS(err, "Error ingesting netperf agent costs", "networkTopology", klog.KObj(nt))
+		}
+	}
+	return nil
+}
+
+// syncNetworkTopology : queries the agents of nt and updates its weights if the costs changed
+func (ctrl *NetperfCostController) syncNetworkTopology(ctx context.Context, nt *v1alpha1.NetworkTopology) error {
+	source := nt.Spec.CostSource.NetperfAgent
+	if source == nil {
+		return fmt.Errorf("missing netperfAgent of the NetperfAgent cost source")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(source.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector of the agents: %w", err)
+	}
+	pods, err := ctrl.podLister.Pods(source.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+	path := source.Path
+	if path == "" {
+		path = DefaultNetperfAgentPath
+	}
+	weightsName := source.WeightsName
+	if weightsName == "" {
+		weightsName = DefaultNetperfAgentWeightsName
+	}
+
+	rtts := ctrl.queryAgents(ctx, pods, source.Port, path)
+	topologies, err := ctrl.netperfCosts(rtts, tierKeys(nt))
+	if err != nil {
+		return err
+	}
+	if len(topologies) == 0 {
+		// Keep the last costs while no agent reports round-trip times between domains.
+		klog.V(4).InfoS("No netperf agent round-trip time between domains", "networkTopology", klog.KObj(nt), "agents", len(pods))
+		return nil
+	}
+	return updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+		if !setWeightsTopologies(nt, weightsName, topologies) {
+			return false
+		}
+		klog.V(4).InfoS("Updating netperf agent costs", "networkTopology", klog.KObj(nt), "weights", weightsName, "agents", len(rtts))
+		return true
+	})
+}
+
+// setWeightsTopologies : sets the topologies in the weights of nt named weightsName, returning whether any
+// of their costs changed
+func setWeightsTopologies(nt *v1alpha1.NetworkTopology, weightsName string, topologies []v1alpha1.TopologyInfo) bool {
+	changed := false
+	for _, topology := range topologies {
+		if current, ok := weightsTopology(nt, weightsName, topology.TopologyKey); ok && current.CostUnit == topology.CostUnit &&
+			sameCosts(current, topology) {
+			continue
+		}
+		setWeightsTopology(nt, weightsName, topology)
+		changed = true
+	}
+	return changed
+}
+
+// queryAgents : returns the round-trip times reported by the running agents, by node of the agent. The agents
+// failing to answer are left out.
+func (ctrl *NetperfCostController) queryAgents(ctx context.Context, pods []*v1.Pod, port int32, path string) map[string][]netperfAgentRTT {
+	var agents []*v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning && pod.Status.PodIP != "" && pod.Spec.NodeName != "" {
+			agents = append(agents, pod)
+		}
+	}
+	workers := ctrl.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var lock sync.Mutex
+	rtts := make(map[string][]netperfAgentRTT, len(agents))
+	workqueue.ParallelizeUntil(ctx, workers, len(agents), func(i int) {
+		pod := agents[i]
+		url := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))) + path
+		report, err := ctrl.queryAgent(ctx, url)
+		if err != nil {
+			klog.V(4).InfoS("Failed to query netperf agent", "pod", klog.KObj(pod), "node", pod.Spec.NodeName, "err", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		rtts[pod.Spec.NodeName] = report.RTTs
+	})
+	return rtts
+}
+
+// queryAgent : returns the report of the agent at url
+func (ctrl *NetperfCostController) queryAgent(ctx context.Context, url string) (*netperfAgentReport, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ctrl.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	report := &netperfAgentReport{}
+	if err := json.NewDecoder(resp.Body).Decode(report); err != nil {
+		return nil, fmt.Errorf("decoding the report: %w", err)
+	}
+	return report, nil
+}
+
+// netperfCosts : returns the costs of the tiers of the given keys, sorted by origin and destination, of the
+// round-trip times between the nodes, averaged by pair of domains of every tier. Round-trip times within a
+// domain, or to unknown nodes, give no cost.
+func (ctrl *NetperfCostController) netperfCosts(rtts map[string][]netperfAgentRTT, keys []v1alpha1.TopologyKey) ([]v1alpha1.TopologyInfo, error) {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*v1.Node, len(nodes))
+	for _, node := range nodes {
+		byName[node.Name] = node
+	}
+
+	var latencies []localityLatency
+	for originNode, list := range rtts {
+		origin, ok := byName[originNode]
+		if !ok {
+			continue
+		}
+		for _, rtt := range list {
+			if destination, ok := byName[rtt.Node]; ok {
+				latencies = append(latencies, localityLatency{origin: origin.Labels, destination: destination.Labels, milliseconds: rtt.Milliseconds})
+			}
+		}
+	}
+	return averageCosts(latencies, keys), nil
+}
+
+// localityLatency is a latency between two localities, given by their tier labels, e.g. region and zone.
+type localityLatency struct {
+	origin       map[string]string
+	destination  map[string]string
+	milliseconds float64
+	// weight is the weight of the latency in the averages, 1 if not set.
+	weight float64
+}
+
+// averageCosts : returns the costs of the tiers of the given keys, sorted by origin and destination, of the
+// latencies averaged by pair of domains of every tier, by their weights. Latencies within a domain, of unknown
+// localities or invalid, give no cost.
+func averageCosts(latencies []localityLatency, keys []v1alpha1.TopologyKey) []v1alpha1.TopologyInfo {
+	type average struct {
+		sum    float64
+		weight float64
+	}
+	costs := map[v1alpha1.TopologyKey]map[string]map[string]*average{}
+	for _, l := range latencies {
+		if l.milliseconds < 0 || math.IsNaN(l.milliseconds) || math.IsInf(l.milliseconds, 0) || l.weight < 0 {
+			continue
+		}
+		weight := l.weight
+		if weight == 0 {
+			weight = 1
+		}
+		for _, key := range keys {
+			from, to := l.origin[string(key)], l.destination[string(key)]
+			if from == "" || to == "" || from == to {
+				continue
+			}
+			if costs[key] == nil {
+				costs[key] = make(map[string]map[string]*average)
+			}
+			if costs[key][from] == nil {
+				costs[key][from] = make(map[string]*average)
+			}
+			if costs[key][from][to] == nil {
+				costs[key][from][to] = &average{}
+			}
+			costs[key][from][to].sum += weight * l.milliseconds
+			costs[key][from][to].weight += weight
+		}
+	}
+
+	var topologies []v1alpha1.TopologyInfo
+	for _, key := range keys {
+		if len(costs[key]) == 0 {
+			continue
+		}
+		topology := v1alpha1.TopologyInfo{TopologyKey: key, CostUnit: v1alpha1.NetworkCostUnitMilliseconds}
+		for origin, destinations := range costs[key] {
+			info := v1alpha1.OriginInfo{Origin: origin}
+			for destination, a := range destinations {
+				info.CostList = append(info.CostList, v1alpha1.CostInfo{
+					Destination: destination,
+					NetworkCost: int64(math.Round(a.sum / a.weight)),
+				})
+			}
+			sort.Slice(info.CostList, func(i, j int) bool { return info.CostList[i].Destination < info.CostList[j].Destination })
+			topology.OriginList = append(topology.OriginList, info)
+		}
+		sort.Slice(topology.OriginList, func(i, j int) bool { return topology.OriginList[i].Origin < topology.OriginList[j].Origin })
+		topologies = append(topologies, topology)
+	}
+	return topologies
+}
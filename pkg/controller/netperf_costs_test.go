@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+// agentTransport answers the queries of the agents by their host.
+type agentTransport map[string]http.HandlerFunc
+
+func (t agentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	handler, ok := t[req.URL.Host]
+	if !ok || req.URL.Path != "/rtt" {
+		handler = func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }
+	}
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	return recorder.Result(), nil
+}
+
+func TestNetperfCostController(t *testing.T) {
+	ctx := context.TODO()
+	report := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		}
+	}
+	transport := agentTransport{
+		"10.0.0.1:8080": report(`{"rtts":[{"node":"n1","milliseconds":0.1},{"node":"n2","milliseconds":2.4},` +
+			`{"node":"n3","milliseconds":40},{"node":"gone","milliseconds":5}]}`),
+		"10.0.0.2:8080": report(`{"rtts":[{"node":"n1","milliseconds":1.6},{"node":"n3","milliseconds":30}]}`),
+		"10.0.0.3:8080": func(w http.ResponseWriter, r *http.Request) { http.Error(w, "probing", http.StatusServiceUnavailable) },
+		"10.0.0.4:8080": report(`{"rtts":[{"node":"n2","milliseconds":3.6}]}`),
+	}
+
+	node := func(name, region, zone string) *v1.Node {
+		return st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, region).Label(v1.LabelTopologyZone, zone).Obj()
+	}
+	agent := func(name, nodeName, ip string, phase v1.PodPhase) *v1.Pod {
+		pod := st.MakePod().Namespace("netperf").Name(name).Label("app", "netperf-agent").Node(nodeName).Obj()
+		pod.Status.Phase = phase
+		pod.Status.PodIP = ip
+		return pod
+	}
+	userDefined := v1alpha1.WeightInfo{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{{
+		TopologyKey: v1alpha1.NetworkTopologyZone,
+		OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 5}}}},
+	}}}
+	probed := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "probed", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{userDefined},
+			CostSource: &v1alpha1.CostSource{Type: v1alpha1.CostSourceNetperfAgent, NetperfAgent: &v1alpha1.NetperfAgentSource{
+				Namespace: "netperf",
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "netperf-agent"}},
+				Port:      8080,
+			}},
+		},
+	}
+	manual := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"},
+		Spec:       v1alpha1.NetworkTopologySpec{Weights: v1alpha1.WeightList{userDefined}},
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(probed, manual)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	podInformer := informerFactory.Core().V1().Pods()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	for _, n := range []*v1.Node{node("n1", "r1", "z1"), node("n2", "r1", "z2"), node("n3", "r2", "z3"), node("n4", "r1", "z1")} {
+		nodeInformer.Informer().GetIndexer().Add(n)
+	}
+	for _, p := range []*v1.Pod{
+		agent("agent-1", "n1", "10.0.0.1", v1.PodRunning),
+		agent("agent-2", "n2", "10.0.0.2", v1.PodRunning),
+		agent("agent-3", "n3", "10.0.0.3", v1.PodRunning),
+		agent("agent-4", "n4", "10.0.0.4", v1.PodRunning),
+		agent("agent-5", "n5", "10.0.0.5", v1.PodPending),
+	} {
+		podInformer.Informer().GetIndexer().Add(p)
+	}
+	for _, nt := range []*v1alpha1.NetworkTopology{probed, manual} {
+		ntInformer.Informer().GetIndexer().Add(nt)
+	}
+
+	ctrl := NewNetperfCostController(schedClient, ntInformer, podInformer, nodeInformer, NetperfCostOptions{Workers: 2})
+	ctrl.client = &http.Client{Transport: transport}
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "probed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// z1 to z2 averages the round-trip times of n1 and n4, the unreachable agent of n3 reports nothing.
+	expected := v1alpha1.WeightList{userDefined, {Name: DefaultNetperfAgentWeightsName, TopologyList: v1alpha1.TopologyList{
+		{TopologyKey: v1alpha1.NetworkTopologyRegion, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+			{Origin: "r1", CostList: v1alpha1.CostList{{Destination: "r2", NetworkCost: 35}}},
+		}},
+		{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 3}, {Destination: "z3", NetworkCost: 40}}},
+			{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z1", NetworkCost: 2}, {Destination: "z3", NetworkCost: 30}}},
+		}},
+	}}}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+	ntInformer.Informer().GetIndexer().Update(got)
+
+	got, err = schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "manual", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(manual.Spec.Weights, got.Spec.Weights) {
+		t.Errorf("expected the weights of the NetworkTopology without cost source untouched, got %v", got.Spec.Weights)
+	}
+
+	// The same costs do not update the NetworkTopology again.
+	schedClient.ClearActions()
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range schedClient.Actions() {
+		if action.GetVerb() == "update" {
+			t.Errorf("expected no update of unchanged costs, got %v", action)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+// DefaultNetworkLatencyWeightsName is the name of the weights receiving the costs if not specified.
+const DefaultNetworkLatencyWeightsName = "NetworkLatency"
+
+// NetworkLatencyCostController : a controller writing the average round-trip times between the domains of the tiers of
+// the nodes, the zones and regions by default, of the NetworkLatencies selected by a NetworkTopology, in milliseconds,
+// as its costs. Every measurement weighs its samples in the averages, less once stale
+type NetworkLatencyCostController struct {
+	interval time.Duration
+
+	ntLister         schedlister.NetworkTopologyLister
+	nlLister         schedlister.NetworkLatencyLister
+	nodeLister       corelister.NodeLister
+	ntListerSynced   cache.InformerSynced
+	nlListerSynced   cache.InformerSynced
+	nodeListerSynced cache.InformerSynced
+	schedClient      schedclientset.Interface
+	clock            clock.PassiveClock
+}
+
+// NewNetworkLatencyCostController : returns a new *NetworkLatencyCostController
+func NewNetworkLatencyCostController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	nlInformer schedinformer.NetworkLatencyInformer, nodeInformer coreinformer.NodeInformer, interval time.Duration) *NetworkLatencyCostController {
+	return &NetworkLatencyCostController{
+		interval:         interval,
+		ntLister:         ntInformer.Lister(),
+		nlLister:         nlInformer.Lister(),
+		nodeLister:       nodeInformer.Lister(),
+		ntListerSynced:   ntInformer.Informer().HasSynced,
+		nlListerSynced:   nlInformer.Informer().HasSynced,
+		nodeListerSynced: nodeInformer.Informer().HasSynced,
+		schedClient:      schedClient,
+		clock:            clock.RealClock{},
+	}
+}
+
+// Run : ingests the costs every interval
+func (ctrl *NetworkLatencyCostController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting NetworkLatency Cost controller", "interval", ctrl.interval)
+	defer klog.InfoS("Shutting NetworkLatency Cost controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.nlListerSynced, ctrl.nodeListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error ingesting NetworkLatency costs")
+		}
+	}, ctrl.interval, stopCh)
+}
+
+// sync : ingests the costs of the NetworkTopologies whose cost source is NetworkLatency
+func (ctrl *NetworkLatencyCostController) sync(ctx context.Context) error {
+	nts, err := ctrl.ntLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, nt := range nts {
+		if source := nt.Spec.CostSource; source == nil || source.Type != v1alpha1.CostSourceNetworkLatency {
+			continue
+		}
+		if err := ctrl.syncNetworkTopology(ctx, nt); err != nil {
+			klog.ErrorS(err, "Error ingesting NetworkLatency costs", "networkTopology", klog.KObj(nt))
+		}
+	}
+	return nil
+}
+
+// syncNetworkTopology : averages the NetworkLatencies selected by nt and updates its weights if the costs changed
+func (ctrl *NetworkLatencyCostController) syncNetworkTopology(ctx context.Context, nt *v1alpha1.NetworkTopology) error {
+	source := nt.Spec.CostSource.NetworkLatency
+	if source == nil {
+		source = &v1alpha1.NetworkLatencySource{}
+	}
+	selector := labels.Everything()
+	if source.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(source.Selector); err != nil {
+			return fmt.Errorf("invalid selector of the NetworkLatencies: %w", err)
+		}
+	}
+	weightsName := source.WeightsName
+	if weightsName == "" {
+		weightsName = DefaultNetworkLatencyWeightsName
+	}
+	var staleAfter time.Duration
+	if source.StaleAfter != nil {
+		staleAfter = source.StaleAfter.Duration
+	}
+
+	nls, err := ctrl.nlLister.NetworkLatencies(nt.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+	latencies, err := ctrl.localityLatencies(nls, staleAfter)
+	if err != nil {
+		return err
+	}
+	topologies := averageCosts(latencies, tierKeys(nt))
+	if len(topologies) == 0 {
+		// Keep the last costs while no fresh NetworkLatency is between domains.
+		klog.V(4).InfoS("No NetworkLatency between domains", "networkTopology", klog.KObj(nt), "networkLatencies", len(nls))
+		return nil
+	}
+	return updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+		if !setWeightsTopologies(nt, weightsName, topologies) {
+			return false
+		}
+		klog.V(4).InfoS("Updating NetworkLatency costs", "networkTopology", klog.KObj(nt), "weights", weightsName, "networkLatencies", len(latencies))
+		return true
+	})
+}
+
+// localityLatencies : returns the round-trip times of nls between the localities of their nodes, weighted by their
+// samples and their staleness, see stalenessWeight. The ones of unknown nodes, or fully decayed, are left out
+func (ctrl *NetworkLatencyCostController) localityLatencies(nls []*v1alpha1.NetworkLatency, staleAfter time.Duration) ([]localityLatency, error) {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*v1.Node, len(nodes))
+	for _, node := range nodes {
+		byName[node.Name] = node
+	}
+
+	now := ctrl.clock.Now()
+	var latencies []localityLatency
+	for _, nl := range nls {
+		origin, ok := byName[nl.Spec.Origin]
+		if !ok {
+			continue
+		}
+		destination, ok := byName[nl.Spec.Destination]
+		if !ok {
+			continue
+		}
+		weight := stalenessWeight(now.Sub(nl.Spec.Timestamp.Time), staleAfter)
+		if weight == 0 {
+			continue
+		}
+		if nl.Spec.Samples > 1 {
+			weight *= float64(nl.Spec.Samples)
+		}
+		latencies = append(latencies, localityLatency{origin: origin.Labels, destination: destination.Labels,
+			milliseconds: float64(nl.Spec.RTT.Duration) / float64(time.Millisecond), weight: weight})
+	}
+	return latencies, nil
+}
+
+// stalenessWeight : returns the weight, between 0 and 1, of a measurement of the given age: 1 up to staleAfter, then
+// decaying linearly down to 0 at twice staleAfter. Always 1 if staleAfter is not positive
+func stalenessWeight(age, staleAfter time.Duration) float64 {
+	if staleAfter <= 0 || age <= staleAfter {
+		return 1
+	}
+	if weight := 1 - float64(age-staleAfter)/float64(staleAfter); weight > 0 {
+		return weight
+	}
+	return 0
+}
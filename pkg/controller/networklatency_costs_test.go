@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestNetworkLatencyCostController(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	staleAfter := 10 * time.Minute
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{CostSource: &v1alpha1.CostSource{
+			Type: v1alpha1.CostSourceNetworkLatency,
+			NetworkLatency: &v1alpha1.NetworkLatencySource{
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"agent": "probe"}},
+				StaleAfter: &metav1.Duration{Duration: staleAfter},
+			},
+		}},
+	}
+	manual := &v1alpha1.NetworkTopology{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"}}
+	latency := func(name, namespace, origin, destination string, rtt time.Duration, samples int32, age time.Duration) *v1alpha1.NetworkLatency {
+		return &v1alpha1.NetworkLatency{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"agent": "probe"}},
+			Spec: v1alpha1.NetworkLatencySpec{Origin: origin, Destination: destination, RTT: metav1.Duration{Duration: rtt},
+				Timestamp: metav1.NewTime(now.Add(-age)), Samples: samples},
+		}
+	}
+	other := latency("other", "default", "n1", "n2", time.Second, 1, 0)
+	other.Labels = nil
+	nls := []*v1alpha1.NetworkLatency{
+		// Averaged by their samples: (2ms + 3 * 4ms) / 4.
+		latency("n1-n2-a", "default", "n1", "n2", 2*time.Millisecond, 0, 0),
+		latency("n1-n2-b", "default", "n1", "n2", 4*time.Millisecond, 3, time.Minute),
+		// The stale one weighs half: (40ms + 0.5 * 100ms) / 1.5.
+		latency("n1-n3-a", "default", "n1", "n3", 40*time.Millisecond, 1, 0),
+		latency("n1-n3-b", "default", "n1", "n3", 100*time.Millisecond, 1, staleAfter*3/2),
+		// Fully decayed, unknown node, other namespace or not selected: no cost.
+		latency("n2-n1", "default", "n2", "n1", 10*time.Millisecond, 1, 3*staleAfter),
+		latency("n1-gone", "default", "n1", "gone", 5*time.Millisecond, 1, 0),
+		latency("n3-n1", "other", "n3", "n1", 5*time.Millisecond, 1, 0),
+		other,
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(nt, manual)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	nlInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkLatencies()
+	for _, n := range []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, "z1").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, "z2").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyRegion, "r2").Label(v1.LabelTopologyZone, "z3").Obj(),
+	} {
+		nodeInformer.Informer().GetIndexer().Add(n)
+	}
+	ntInformer.Informer().GetIndexer().Add(nt)
+	ntInformer.Informer().GetIndexer().Add(manual)
+	for _, nl := range nls {
+		nlInformer.Informer().GetIndexer().Add(nl)
+	}
+
+	ctrl := NewNetworkLatencyCostController(schedClient, ntInformer, nlInformer, nodeInformer, time.Minute)
+	ctrl.clock = testingclock.NewFakeClock(now)
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost := func(destination string, cost int64) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: cost}
+	}
+	expected := v1alpha1.WeightList{{Name: DefaultNetworkLatencyWeightsName, TopologyList: v1alpha1.TopologyList{
+		{TopologyKey: v1alpha1.NetworkTopologyRegion, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+			{Origin: "r1", CostList: v1alpha1.CostList{cost("r2", 60)}},
+		}},
+		{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{cost("z2", 4), cost("z3", 60)}},
+		}},
+	}}}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+	if got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "manual", metav1.GetOptions{}); err != nil ||
+		len(got.Spec.Weights) != 0 {
+		t.Errorf("expected the weights of manual untouched, got %v, %v", got, err)
+	}
+}
+
+func TestStalenessWeight(t *testing.T) {
+	tests := []struct {
+		age, staleAfter time.Duration
+		expected        float64
+	}{
+		{age: time.Hour, staleAfter: 0, expected: 1},
+		{age: 5 * time.Minute, staleAfter: 10 * time.Minute, expected: 1},
+		{age: 15 * time.Minute, staleAfter: 10 * time.Minute, expected: 0.5},
+		{age: 20 * time.Minute, staleAfter: 10 * time.Minute, expected: 0},
+		{age: time.Hour, staleAfter: 10 * time.Minute, expected: 0},
+	}
+	for _, tt := range tests {
+		if got := stalenessWeight(tt.age, tt.staleAfter); got != tt.expected {
+			t.Errorf("expected the weight %v at %v for %v, got %v", tt.expected, tt.age, tt.staleAfter, got)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// linkDemand is the bandwidth demanded by a pod on one direction of a link.
+type linkDemand struct {
+	key         v1alpha1.TopologyKey
+	origin      string
+	destination string
+	bandwidth   resource.Quantity
+	class       v1alpha1.BandwidthClass
+}
+
+// NetworkTopologyBandwidthController : a controller writing, every interval, the bandwidth allocated on the links of the
+// NetworkTopologies out of the bound pods of the AppGroups. Every pod allocates the egress demanded by its workload to
+// each dependency on the link to the pods of the dependency, see costoracle.DependencyLink, and the ingress on the
+// reverse link. The allocations are calculated again from the pods at every sync, so that the bandwidth of the pods deleted or terminated is released.
+// The pods lost without being deleted, e.g. on a crashed node, release theirs once older than allocationTTL. A sync runs at once when a
+// NetworkTopology asks for it by the NetworkTopologyRebuildBandwidthAnnotation
+type NetworkTopologyBandwidthController struct {
+	interval      time.Duration
+	allocationTTL time.Duration
+	// rebuild is signaled when a NetworkTopology asks for its allocations to be rebuilt.
+	rebuild chan struct{}
+
+	ntLister         schedlister.NetworkTopologyLister
+	agLister         schedlister.AppGroupLister
+	podLister        corelister.PodLister
+	nodeLister       corelister.NodeLister
+	ntListerSynced   cache.InformerSynced
+	agListerSynced   cache.InformerSynced
+	podListerSynced  cache.InformerSynced
+	nodeListerSynced cache.InformerSynced
+	schedClient      schedclientset.Interface
+	clock            clock.Clock
+}
+
+// NewNetworkTopologyBandwidthController : returns a new *NetworkTopologyBandwidthController
+func NewNetworkTopologyBandwidthController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	agInformer schedinformer.AppGroupInformer, podInformer coreinformer.PodInformer, nodeInformer coreinformer.NodeInformer,
+	interval, allocationTTL time.Duration) *NetworkTopologyBandwidthController {
+	ctrl := &NetworkTopologyBandwidthController{
+		interval:         interval,
+		allocationTTL:    allocationTTL,
+		rebuild:          make(chan struct{}, 1),
+		ntLister:         ntInformer.Lister(),
+		agLister:         agInformer.Lister(),
+		podLister:        podInformer.Lister(),
+		nodeLister:       nodeInformer.Lister(),
+		ntListerSynced:   ntInformer.Informer().HasSynced,
+		agListerSynced:   agInformer.Informer().HasSynced,
+		podListerSynced:  podInformer.Informer().HasSynced,
+		nodeListerSynced: nodeInformer.Informer().HasSynced,
+		schedClient:      schedClient,
+		clock:            clock.RealClock{},
+	}
+
+	klog.V(5).InfoS("Setting up NetworkTopology event handlers")
+	ntInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ctrl.ntAdded,
+		UpdateFunc: func(old, new interface{}) {
+			ctrl.ntAdded(new)
+		},
+	})
+	return ctrl
+}
+
+// ntAdded : signals a rebuild if the NetworkTopology asks for it
+func (ctrl *NetworkTopologyBandwidthController) ntAdded(obj interface{}) {
+	nt, ok := obj.(*v1alpha1.NetworkTopology)
+	if !ok {
+		return
+	}
+	if _, ok := nt.Annotations[v1alpha1.NetworkTopologyRebuildBandwidthAnnotation]; !ok {
+		return
+	}
+	select {
+	case ctrl.rebuild <- struct{}{}:
+	default:
+		// A rebuild is already pending.
+	}
+}
+
+// Run : writes the allocated bandwidth of the NetworkTopologies every interval, and whenever a NetworkTopology asks
+// for a rebuild
+func (ctrl *NetworkTopologyBandwidthController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting NetworkTopology Bandwidth controller", "interval", ctrl.interval, "allocationTTL", ctrl.allocationTTL)
+	defer klog.InfoS("Shutting NetworkTopology Bandwidth controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.agListerSynced, ctrl.podListerSynced, ctrl.nodeListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	timer := ctrl.clock.NewTimer(ctrl.interval)
+	defer timer.Stop()
+	for {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error allocating the bandwidth of the NetworkTopologies")
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C():
+		case <-ctrl.rebuild:
+			if !timer.Stop() {
+				<-timer.C()
+			}
+		}
+		timer.Reset(ctrl.interval)
+	}
+}
+
+// sync : allocates the bandwidth demanded by the bound pods on the links of every NetworkTopology but the shadow ones,
+// and removes the NetworkTopologyRebuildBandwidthAnnotation of the ones asking for a rebuild
+func (ctrl *NetworkTopologyBandwidthController) sync(ctx context.Context) error {
+	nts, err := ctrl.ntLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	demands, err := ctrl.linkDemands()
+	if err != nil {
+		return err
+	}
+	for _, nt := range nts {
+		if _, ok := nt.Annotations[v1alpha1.NetworkTopologyShadowOfAnnotation]; ok {
+			continue
+		}
+		if err := updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+			weights := nt.Spec.Weights.DeepCopy()
+			allocateBandwidth(nt, demands)
+			_, rebuilt := nt.Annotations[v1alpha1.NetworkTopologyRebuildBandwidthAnnotation]
+			delete(nt.Annotations, v1alpha1.NetworkTopologyRebuildBandwidthAnnotation)
+			return rebuilt || !apiequality.Semantic.DeepEqual(weights, nt.Spec.Weights)
+		}); err != nil {
+			klog.ErrorS(err, "Error allocating the bandwidth of the NetworkTopology", "networkTopology", klog.KObj(nt))
+		}
+	}
+	return nil
+}
+
+// linkDemands : returns the bandwidth demanded on the links by the pods of the AppGroups bound and not terminated nor
+// lost, see lost, the Guaranteed demands before the Burstable ones and in the order of the namespace and name of their pods so that
+// the same links fill up first at every sync. The BestEffort demands are left out
+func (ctrl *NetworkTopologyBandwidthController) linkDemands() ([]linkDemand, error) {
+	requirement, err := labels.NewRequirement(v1alpha1.AppGroupLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := ctrl.podLister.List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	// placements holds the placements of the pods of every workload, keyed by AppGroup then workload selector.
+	placements := make(map[string]map[string][]costoracle.Placement)
+	podPlacements := make(map[*v1.Pod]costoracle.Placement, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		node, err := ctrl.nodeLister.Get(pod.Spec.NodeName)
+		if err != nil || ctrl.lost(pod, node) {
+			continue
+		}
+		agKey := pod.Namespace + "/" + util.GetPodAppGroupLabel(pod)
+		if placements[agKey] == nil {
+			placements[agKey] = make(map[string][]costoracle.Placement)
+		}
+		placement := costoracle.NodePlacement(node)
+		selector := util.GetPodAppGroupSelector(pod)
+		placements[agKey][selector] = append(placements[agKey][selector], placement)
+		podPlacements[pod] = placement
+	}
+
+	var demands []linkDemand
+	for _, pod := range pods {
+		from, ok := podPlacements[pod]
+		if !ok {
+			continue
+		}
+		agName := util.GetPodAppGroupLabel(pod)
+		ag, err := ctrl.agLister.AppGroups(pod.Namespace).Get(agName)
+		if err != nil {
+			continue
+		}
+		for _, d := range costoracle.BandwidthDemands(ag, util.GetPodAppGroupSelector(pod)) {
+			// The BestEffort bandwidth is not reserved.
+			if d.Class == v1alpha1.BandwidthBestEffort {
+				continue
+			}
+			key, destination, ok := costoracle.DependencyLink(from, placements[pod.Namespace+"/"+agName][d.Dependency])
+			if !ok {
+				continue
+			}
+			origin := from.Domain(key)
+			if d.Egress.Sign() > 0 {
+				demands = append(demands, linkDemand{key: key, origin: origin, destination: destination, bandwidth: d.Egress, class: d.Class})
+			}
+			if d.Ingress.Sign() > 0 {
+				demands = append(demands, linkDemand{key: key, origin: destination, destination: origin, bandwidth: d.Ingress, class: d.Class})
+			}
+		}
+	}
+	// The Guaranteed bandwidth is allocated first, the Burstable one on the headroom left.
+	sort.SliceStable(demands, func(i, j int) bool {
+		return demands[i].class == v1alpha1.BandwidthGuaranteed && demands[j].class != v1alpha1.BandwidthGuaranteed
+	})
+	return demands, nil
+}
+
+// lost : tells whether the allocations of pod aged out: whether it has been terminating, or its node not Ready, e.g.
+// crashed, for longer than allocationTTL. Never if allocationTTL is not positive
+func (ctrl *NetworkTopologyBandwidthController) lost(pod *v1.Pod, node *v1.Node) bool {
+	if ctrl.allocationTTL <= 0 {
+		return false
+	}
+	now := ctrl.clock.Now()
+	if pod.DeletionTimestamp != nil && now.Sub(pod.DeletionTimestamp.Time) > ctrl.allocationTTL {
+		return true
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			return c.Status != v1.ConditionTrue && now.Sub(c.LastTransitionTime.Time) > ctrl.allocationTTL
+		}
+	}
+	return false
+}
+
+// allocateBandwidth : replaces the bandwidth allocated on the links of the default interface class of every weights
+// of nt by demands, spread across the parallel links of a link by costoracle.AllocateBandwidth. The demands exceeding
+// the headroom of their link, capped by the link policy of its topology key, and the links without a capacity are
+// left out. No new bandwidth is allocated on the links of the draining zones: their allocations never grow above the
+// ones of the previous sync
+func allocateBandwidth(nt *v1alpha1.NetworkTopology, demands []linkDemand) {
+	maxUtilization := make(map[v1alpha1.TopologyKey]int32, len(nt.Spec.LinkPolicies))
+	for _, p := range nt.Spec.LinkPolicies {
+		maxUtilization[p.TopologyKey] = p.MaxUtilizationPercent
+	}
+	draining := costoracle.DrainingZones(nt)
+	// links holds the links of every weights, keyed by topology key, origin and destination.
+	links := make(map[v1alpha1.TopologyKey]map[string]map[string][]*v1alpha1.CostInfo)
+	// frozen holds the bandwidth allocated at the previous sync on the links of the draining zones.
+	frozen := make(map[*v1alpha1.CostInfo]resource.Quantity)
+	for wi := range nt.Spec.Weights {
+		for ti := range nt.Spec.Weights[wi].TopologyList {
+			t := &nt.Spec.Weights[wi].TopologyList[ti]
+			if t.InterfaceClass != "" {
+				continue
+			}
+			if links[t.TopologyKey] == nil {
+				links[t.TopologyKey] = make(map[string]map[string][]*v1alpha1.CostInfo)
+			}
+			for oi := range t.OriginList {
+				o := &t.OriginList[oi]
+				if links[t.TopologyKey][o.Origin] == nil {
+					links[t.TopologyKey][o.Origin] = make(map[string][]*v1alpha1.CostInfo)
+				}
+				for ci := range o.CostList {
+					c := &o.CostList[ci]
+					_, originDraining := draining[o.Origin]
+					_, destinationDraining := draining[c.Destination]
+					if t.TopologyKey == v1alpha1.NetworkTopologyZone && (originDraining || destinationDraining) {
+						_, frozen[c] = costoracle.LinkBandwidth(*c)
+					}
+					c.BandwidthAllocated = resource.Quantity{}
+					for li := range c.Links {
+						c.Links[li].BandwidthAllocated = resource.Quantity{}
+					}
+					links[t.TopologyKey][o.Origin][c.Destination] = append(links[t.TopologyKey][o.Origin][c.Destination], c)
+				}
+			}
+		}
+	}
+
+	left := 0
+	for _, d := range demands {
+		for _, c := range links[d.key][d.origin][d.destination] {
+			// The links without a known capacity are not tracked.
+			capacity, allocated := costoracle.LinkBandwidth(*c)
+			if capacity.IsZero() {
+				continue
+			}
+			if ceiling, ok := frozen[c]; ok {
+				if allocated.Add(d.bandwidth); allocated.Cmp(ceiling) > 0 {
+					left++
+					continue
+				}
+			}
+			if !costoracle.AllocateBandwidth(c, d.bandwidth, maxUtilization[d.key]) {
+				left++
+			}
+		}
+	}
+	if left > 0 {
+		klog.V(4).InfoS("Bandwidth demands exceeding the headroom of their link", "networkTopology", klog.KObj(nt), "demands", left)
+	}
+}
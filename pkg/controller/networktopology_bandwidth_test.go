@@ -0,0 +1,313 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestNetworkTopologyBandwidthController(t *testing.T) {
+	ctx := context.TODO()
+	link := func(destination, capacity, allocated string) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: 1,
+			BandwidthCapacity: resource.MustParse(capacity), BandwidthAllocated: resource.MustParse(allocated)}
+	}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					// The allocations of a previous sync are replaced.
+					{Origin: "z1", CostList: v1alpha1.CostList{link("z2", "1G", "700M"), {Destination: "z3", NetworkCost: 1,
+						Links: []v1alpha1.ParallelLink{
+							{Name: "path-a", BandwidthCapacity: resource.MustParse("1G")},
+							{Name: "path-b", BandwidthCapacity: resource.MustParse("3G")},
+						}}}},
+					{Origin: "z2", CostList: v1alpha1.CostList{link("z1", "1G", "0")}},
+					{Origin: "z3", CostList: v1alpha1.CostList{link("z1", "1G", "0")}},
+				}},
+			}}},
+		},
+	}
+	shadow := nt.DeepCopy()
+	shadow.Name = "shadow"
+	shadow.Annotations = map[string]string{v1alpha1.NetworkTopologyShadowOfAnnotation: "nt"}
+	ingress := resource.MustParse("200M")
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "frontend"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("100M")},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "storage"}, MinBandwidth: resource.MustParse("400M"), MinIngressBandwidth: &ingress},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "batch"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("950M"),
+					BandwidthClass: v1alpha1.BandwidthBurstable},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "storage"}, MinBandwidth: resource.MustParse("100M"),
+					BandwidthClass: v1alpha1.BandwidthBestEffort},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "storage"}},
+		}},
+	}
+	pod := func(name, selector, nodeName string, phase v1.PodPhase) *v1.Pod {
+		p := st.MakePod().Namespace("default").Name(name).Label(v1alpha1.AppGroupLabel, "ag").
+			Label(v1alpha1.AppGroupSelectorLabel, selector).Node(nodeName).Obj()
+		p.Status.Phase = phase
+		return p
+	}
+	frontend := pod("frontend", "frontend", "n1", v1.PodRunning)
+	pods := []*v1.Pod{
+		frontend,
+		// Sorted before the frontend, but Burstable.
+		pod("batch", "batch", "n1", v1.PodRunning),
+		// Terminated and pending pods demand nothing.
+		pod("frontend-done", "frontend", "n1", v1.PodSucceeded),
+		pod("frontend-pending", "frontend", "", v1.PodPending),
+		pod("backend", "backend", "n2", v1.PodRunning),
+		pod("storage", "storage", "n3", v1.PodRunning),
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(nt, shadow)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	agInformer := schedInformerFactory.Scheduling().V1alpha1().AppGroups()
+	for i, zone := range []string{"z1", "z2", "z3"} {
+		node := st.MakeNode().Name("n"+string(rune('1'+i))).Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, zone).Obj()
+		nodeInformer.Informer().GetIndexer().Add(node)
+	}
+	for _, p := range pods {
+		podInformer.Informer().GetIndexer().Add(p)
+	}
+	ntInformer.Informer().GetIndexer().Add(nt)
+	ntInformer.Informer().GetIndexer().Add(shadow)
+	agInformer.Informer().GetIndexer().Add(ag)
+
+	ctrl := NewNetworkTopologyBandwidthController(schedClient, ntInformer, agInformer, podInformer, nodeInformer, 0, 0)
+	get := func(name string) *v1alpha1.NetworkTopology {
+		got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	expectAllocated := func(nt *v1alpha1.NetworkTopology, origin, destination string, expected ...string) {
+		t.Helper()
+		for _, o := range nt.Spec.Weights[0].TopologyList[0].OriginList {
+			if o.Origin != origin {
+				continue
+			}
+			for _, c := range o.CostList {
+				if c.Destination != destination {
+					continue
+				}
+				got := []resource.Quantity{c.BandwidthAllocated}
+				if len(c.Links) != 0 {
+					got = nil
+					for _, l := range c.Links {
+						got = append(got, l.BandwidthAllocated)
+					}
+				}
+				for i, e := range expected {
+					if got[i].Cmp(resource.MustParse(e)) != 0 {
+						t.Errorf("expected %v allocated from %v to %v, got %v", expected, origin, destination, got)
+						return
+					}
+				}
+				return
+			}
+		}
+		t.Errorf("expected a link from %v to %v", origin, destination)
+	}
+
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := get("nt")
+	// The Burstable bandwidth of the batch does not fit once the Guaranteed one allocated.
+	expectAllocated(got, "z1", "z2", "100M")
+	expectAllocated(got, "z2", "z1", "100M")
+	// Spread across the parallel links in proportion to their headroom.
+	expectAllocated(got, "z1", "z3", "100M", "300M")
+	// The ingress of the frontend from the storage, the BestEffort bandwidth of the batch not being reserved.
+	expectAllocated(got, "z3", "z1", "200M")
+	expectAllocated(get("shadow"), "z1", "z2", "700M")
+
+	// The bandwidth of the deleted pods is released at the next sync.
+	podInformer.Informer().GetIndexer().Delete(frontend)
+	ntInformer.Informer().GetIndexer().Update(got)
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got = get("nt")
+	// The Burstable bandwidth of the batch fits in the headroom left by the frontend.
+	expectAllocated(got, "z1", "z2", "950M")
+	expectAllocated(got, "z2", "z1", "950M")
+	expectAllocated(got, "z1", "z3", "0", "0")
+}
+
+func TestNetworkTopologyBandwidthLostPods(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default",
+			Annotations: map[string]string{v1alpha1.NetworkTopologyRebuildBandwidthAnnotation: ""}},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					// 900M leaked by pods lost before the controller restarted.
+					{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1,
+						BandwidthCapacity: resource.MustParse("1G"), BandwidthAllocated: resource.MustParse("900M")}}},
+				}},
+			}}},
+		},
+	}
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "frontend"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("100M")},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+		}},
+	}
+	pod := func(name, selector, nodeName string) *v1.Pod {
+		p := st.MakePod().Namespace("default").Name(name).Label(v1alpha1.AppGroupLabel, "ag").
+			Label(v1alpha1.AppGroupSelectorLabel, selector).Node(nodeName).Obj()
+		p.Status.Phase = v1.PodRunning
+		return p
+	}
+	node := func(name, zone string, ready v1.ConditionStatus, since time.Duration) *v1.Node {
+		n := st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, zone).Obj()
+		n.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: ready, LastTransitionTime: metav1.NewTime(now.Add(-since))}}
+		return n
+	}
+	// frontend-terminating has been terminating for an hour, frontend-crashed on a node NotReady for an hour, and
+	// frontend-unready on a node NotReady for a minute only.
+	terminating := pod("frontend-terminating", "frontend", "n1")
+	terminating.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Hour)}
+	pods := []*v1.Pod{
+		pod("frontend", "frontend", "n1"),
+		terminating,
+		pod("frontend-crashed", "frontend", "n3"),
+		pod("frontend-unready", "frontend", "n4"),
+		pod("backend", "backend", "n2"),
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(nt)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	agInformer := schedInformerFactory.Scheduling().V1alpha1().AppGroups()
+	for _, n := range []*v1.Node{
+		node("n1", "z1", v1.ConditionTrue, time.Hour),
+		node("n2", "z2", v1.ConditionTrue, time.Hour),
+		node("n3", "z1", v1.ConditionUnknown, time.Hour),
+		node("n4", "z1", v1.ConditionFalse, time.Minute),
+	} {
+		nodeInformer.Informer().GetIndexer().Add(n)
+	}
+	for _, p := range pods {
+		podInformer.Informer().GetIndexer().Add(p)
+	}
+	ntInformer.Informer().GetIndexer().Add(nt)
+	agInformer.Informer().GetIndexer().Add(ag)
+
+	ctrl := NewNetworkTopologyBandwidthController(schedClient, ntInformer, agInformer, podInformer, nodeInformer, time.Minute, 10*time.Minute)
+	ctrl.clock = testingclock.NewFakeClock(now)
+
+	// The annotation asks for a rebuild at once.
+	ctrl.ntAdded(nt)
+	select {
+	case <-ctrl.rebuild:
+	default:
+		t.Fatal("expected a rebuild signaled by the annotation")
+	}
+	ctrl.ntAdded(&v1alpha1.NetworkTopology{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}})
+	select {
+	case <-ctrl.rebuild:
+		t.Fatal("expected no rebuild signaled without the annotation")
+	default:
+	}
+
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The leaked bandwidth is released, and the pods lost for longer than the TTL allocate nothing.
+	if allocated := got.Spec.Weights[0].TopologyList[0].OriginList[0].CostList[0].BandwidthAllocated; allocated.Cmp(resource.MustParse("200M")) != 0 {
+		t.Errorf("expected 200M allocated by frontend and frontend-unready, got %v", allocated.String())
+	}
+	if _, ok := got.Annotations[v1alpha1.NetworkTopologyRebuildBandwidthAnnotation]; ok {
+		t.Errorf("expected the rebuild annotation removed, got %v", got.Annotations)
+	}
+}
+
+func TestAllocateBandwidthDrainingZones(t *testing.T) {
+	link := func(destination, allocated string) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: 1,
+			BandwidthCapacity: resource.MustParse("1G"), BandwidthAllocated: resource.MustParse(allocated)}
+	}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					{Origin: "z1", CostList: v1alpha1.CostList{link("z2", "300M"), link("z3", "300M")}},
+				}},
+			}}},
+			DrainingZones: []v1alpha1.DrainingZone{{Zone: "z2"}},
+		},
+	}
+	demand := func(destination, bandwidth string) linkDemand {
+		return linkDemand{key: v1alpha1.NetworkTopologyZone, origin: "z1", destination: destination,
+			bandwidth: resource.MustParse(bandwidth), class: v1alpha1.BandwidthGuaranteed}
+	}
+	allocateBandwidth(nt, []linkDemand{demand("z2", "200M"), demand("z2", "200M"), demand("z3", "200M"), demand("z3", "200M")})
+
+	// The second demand to the draining zone would grow its allocation above the 300M of the previous sync.
+	expected := map[string]string{"z2": "200M", "z3": "400M"}
+	for _, c := range nt.Spec.Weights[0].TopologyList[0].OriginList[0].CostList {
+		if c.BandwidthAllocated.Cmp(resource.MustParse(expected[c.Destination])) != 0 {
+			t.Errorf("expected %v allocated from z1 to %v, got %v", expected[c.Destination], c.Destination, c.BandwidthAllocated.String())
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+)
+
+// defaultTierKeys are the tiers of the NetworkTopologies not declaring any, from the widest to the narrowest.
+var defaultTierKeys = []v1alpha1.TopologyKey{v1alpha1.NetworkTopologyRegion, v1alpha1.NetworkTopologyZone}
+
+// tierKeys : returns the topology keys of the tiers of nt, from the widest to the narrowest, the region then
+// the zone if it declares none
+func tierKeys(nt *v1alpha1.NetworkTopology) []v1alpha1.TopologyKey {
+	if len(nt.Spec.Tiers) == 0 {
+		return defaultTierKeys
+	}
+	keys := make([]v1alpha1.TopologyKey, 0, len(nt.Spec.Tiers))
+	for _, tier := range nt.Spec.Tiers {
+		keys = append(keys, tier.TopologyKey)
+	}
+	return keys
+}
+
+// weightsTiers : returns the weights tiers of nt, none if it declares none so that the region and zone costs
+// of options apply. The tiers not setting their cross cost get the cross-region cost of options if the widest,
+// the cross-zone cost otherwise.
+func weightsTiers(nt *v1alpha1.NetworkTopology, options weights.Options) []weights.Tier {
+	var tiers []weights.Tier
+	for i, tier := range nt.Spec.Tiers {
+		crossCost := options.CrossZoneCost
+		if i == 0 {
+			crossCost = options.CrossRegionCost
+		}
+		if tier.CrossCost != nil {
+			crossCost = *tier.CrossCost
+		}
+		tiers = append(tiers, weights.Tier{Key: tier.TopologyKey, CrossCost: crossCost})
+	}
+	return tiers
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+)
+
+// updateNetworkTopology : applies mutate to a copy of nt and updates the NetworkTopology with it, guarded by its
+// resourceVersion. On conflict, e.g. with a user editing manual weights, mutate is applied again to the latest
+// NetworkTopology read from the API server, so that neither write is lost. mutate returns false if no update is
+// needed. nt, usually read from a lister, is not modified.
+func updateNetworkTopology(ctx context.Context, client schedclientset.Interface, nt *v1alpha1.NetworkTopology,
+	mutate func(*v1alpha1.NetworkTopology) bool) error {
+	current := nt
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if current == nil {
+			latest, err := client.SchedulingV1alpha1().NetworkTopologies(nt.Namespace).Get(ctx, nt.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			current = latest
+		}
+		updated := current.DeepCopy()
+		// Read the NetworkTopology again if the update conflicts.
+		current = nil
+		if !mutate(updated) {
+			return nil
+		}
+		_, err := client.SchedulingV1alpha1().NetworkTopologies(nt.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+}
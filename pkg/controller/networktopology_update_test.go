@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+)
+
+// withOptimisticConcurrency makes the updates of the NetworkTopologies of client fail with a conflict unless they
+// carry the current resourceVersion, which every update bumps, like the API server does.
+func withOptimisticConcurrency(client *schedfake.Clientset) {
+	resource := v1alpha1.SchemeGroupVersion.WithResource("networktopologies")
+	client.PrependReactor("update", "networktopologies", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		nt := action.(clienttesting.UpdateAction).GetObject().(*v1alpha1.NetworkTopology).DeepCopy()
+		obj, err := client.Tracker().Get(resource, nt.Namespace, nt.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		current := obj.(*v1alpha1.NetworkTopology)
+		if nt.ResourceVersion != current.ResourceVersion {
+			return true, nil, apierrs.NewConflict(resource.GroupResource(), nt.Name, fmt.Errorf("resourceVersion %q is stale", nt.ResourceVersion))
+		}
+		version, _ := strconv.Atoi(current.ResourceVersion)
+		nt.ResourceVersion = strconv.Itoa(version + 1)
+		return true, nt, client.Tracker().Update(resource, nt, nt.Namespace)
+	})
+}
+
+func TestUpdateNetworkTopologyConflict(t *testing.T) {
+	ctx := context.Background()
+	stale := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default", ResourceVersion: "1"},
+		Spec: v1alpha1.NetworkTopologySpec{Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{{
+			TopologyKey: v1alpha1.NetworkTopologyZone,
+			OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 5}}}},
+		}}}}},
+	}
+	client := schedfake.NewSimpleClientset(stale)
+	withOptimisticConcurrency(client)
+
+	// A user edits the manual weights after the controller read the NetworkTopology.
+	edited := stale.DeepCopy()
+	edited.Spec.Weights[0].TopologyList[0].OriginList[0].CostList[0].NetworkCost = 7
+	if _, err := client.SchedulingV1alpha1().NetworkTopologies("default").Update(ctx, edited, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mesh := v1alpha1.TopologyInfo{
+		TopologyKey: v1alpha1.NetworkTopologyZone,
+		OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 12}}}},
+	}
+	attempts := 0
+	err := updateNetworkTopology(ctx, client, stale, func(nt *v1alpha1.NetworkTopology) bool {
+		attempts++
+		setWeightsTopology(nt, "Mesh", mesh)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected a retry after the conflict, got %v attempts", attempts)
+	}
+	if stale.ResourceVersion != "1" || len(stale.Spec.Weights) != 1 {
+		t.Errorf("Expected the NetworkTopology given to be left unmodified, got %v", stale)
+	}
+
+	got, err := client.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manual, ok := weightsTopology(got, "UserDefined", v1alpha1.NetworkTopologyZone); !ok || manual.OriginList[0].CostList[0].NetworkCost != 7 {
+		t.Errorf("Expected the edit of the UserDefined costs to be kept, got %v", got.Spec.Weights)
+	}
+	if current, ok := weightsTopology(got, "Mesh", v1alpha1.NetworkTopologyZone); !ok || !sameCosts(current, mesh) {
+		t.Errorf("Expected the Mesh costs to be written, got %v", got.Spec.Weights)
+	}
+}
+
+func TestUpdateNetworkTopologyConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	nt := &v1alpha1.NetworkTopology{ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default", ResourceVersion: "1"}}
+	client := schedfake.NewSimpleClientset(nt)
+	withOptimisticConcurrency(client)
+
+	// Every writer starts from the same, soon stale, NetworkTopology and adds its own weights.
+	const writers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- updateNetworkTopology(ctx, client, nt, func(nt *v1alpha1.NetworkTopology) bool {
+				setWeightsTopology(nt, fmt.Sprintf("writer-%d", i), v1alpha1.TopologyInfo{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList:  v1alpha1.OriginList{{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: int64(i)}}}},
+				})
+				return true
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error of a writer: %v", err)
+		}
+	}
+
+	got, err := client.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < writers; i++ {
+		if _, ok := weightsTopology(got, fmt.Sprintf("writer-%d", i), v1alpha1.NetworkTopologyZone); !ok {
+			t.Errorf("Expected the weights of writer %d to be kept, got %v", i, got.Spec.Weights)
+		}
+	}
+	if got.ResourceVersion != strconv.Itoa(1+writers) {
+		t.Errorf("Expected %d updates, got resourceVersion %v", writers, got.ResourceVersion)
+	}
+}
+
+func TestUpdateNetworkTopologyUnchanged(t *testing.T) {
+	nt := &v1alpha1.NetworkTopology{ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"}}
+	client := schedfake.NewSimpleClientset(nt)
+	if err := updateNetworkTopology(context.Background(), client, nt, func(*v1alpha1.NetworkTopology) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			t.Errorf("Expected no update, got %v", action)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// weightCalculationCheckInterval is the period between two checks of the NetworkTopologies whose weights are due.
+const weightCalculationCheckInterval = time.Minute
+
+// NetworkTopologyWeightsOptions configures the periodic calculation of the weights of the NetworkTopologies.
+type NetworkTopologyWeightsOptions struct {
+	// Options hold the name of the weights calculated and the default costs of the pairs without known cost.
+	weights.Options
+	// NodeFilter leaves the zones and regions of the NotReady, cordoned, tainted or unselected nodes out of the weights.
+	NodeFilter util.NodeFilter
+	// DefaultPeriod is the period of the NetworkTopologies not setting any. Their weights are not calculated if 0.
+	DefaultPeriod time.Duration
+}
+
+// NetworkTopologyWeightsController : a controller calculating again, every weightCalculationPeriod, the weights of
+// the NetworkTopologies out of the nodes and the costs they hold, so that the zones and regions added to the cluster
+// get default costs and the pairs connected by new links the cost of their cheapest path
+type NetworkTopologyWeightsController struct {
+	NetworkTopologyWeightsOptions
+
+	ntLister         schedlister.NetworkTopologyLister
+	nodeLister       corelister.NodeLister
+	ntListerSynced   cache.InformerSynced
+	nodeListerSynced cache.InformerSynced
+	schedClient      schedclientset.Interface
+	clock            clock.PassiveClock
+	// paths holds the cheapest paths computed for every NetworkTopology, keyed by namespace/name, so that a
+	// calculation only computes again the ones going through the links changed since the previous one
+	paths map[string]*weights.PathCache
+}
+
+// NewNetworkTopologyWeightsController : returns a new *NetworkTopologyWeightsController
+func NewNetworkTopologyWeightsController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	nodeInformer coreinformer.NodeInformer, options NetworkTopologyWeightsOptions) *NetworkTopologyWeightsController {
+	return &NetworkTopologyWeightsController{
+		NetworkTopologyWeightsOptions: options,
+		ntLister:                      ntInformer.Lister(),
+		nodeLister:                    nodeInformer.Lister(),
+		ntListerSynced:                ntInformer.Informer().HasSynced,
+		nodeListerSynced:              nodeInformer.Informer().HasSynced,
+		schedClient:                   schedClient,
+		clock:                         clock.RealClock{},
+		paths:                         make(map[string]*weights.PathCache),
+	}
+}
+
+// Run : calculates the weights of the NetworkTopologies as they fall due
+func (ctrl *NetworkTopologyWeightsController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting NetworkTopology Weights controller", "weights", ctrl.WeightsName, "defaultPeriod", ctrl.DefaultPeriod)
+	defer klog.InfoS("Shutting NetworkTopology Weights controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.nodeListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error calculating the weights of the NetworkTopologies")
+		}
+	}, weightCalculationCheckInterval, stopCh)
+}
+
+// sync : calculates the weights of the NetworkTopologies whose period elapsed since their last calculation, out of
+// the nodes selected by the node filter and their node selector
+func (ctrl *NetworkTopologyWeightsController) sync(ctx context.Context) error {
+	nts, err := ctrl.ntLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var nodes []v1.Node
+	listed := make(map[string]bool, len(nts))
+	for _, nt := range nts {
+		if key, err := cache.MetaNamespaceKeyFunc(nt); err == nil {
+			listed[key] = true
+		}
+	}
+	for key := range ctrl.paths {
+		if !listed[key] {
+			delete(ctrl.paths, key)
+		}
+	}
+	for _, nt := range nts {
+		period := ctrl.weightCalculationPeriod(nt)
+		if period <= 0 || ctrl.clock.Since(nt.Status.WeightCalculationTime.Time) < period {
+			continue
+		}
+		if nodes == nil {
+			if nodes, err = ctrl.listNodes(); err != nil {
+				return err
+			}
+		}
+		selected, err := selectNodes(nt, nodes)
+		if err != nil {
+			klog.ErrorS(err, "Invalid node selector of the NetworkTopology", "networkTopology", klog.KObj(nt))
+			continue
+		}
+		if err := ctrl.calculateWeights(ctx, nt, selected); err != nil {
+			klog.ErrorS(err, "Error calculating the weights of the NetworkTopology", "networkTopology", klog.KObj(nt))
+		}
+	}
+	return nil
+}
+
+// weightCalculationPeriod : returns the period of nt, the default one if not set
+func (ctrl *NetworkTopologyWeightsController) weightCalculationPeriod(nt *v1alpha1.NetworkTopology) time.Duration {
+	if nt.Spec.WeightCalculationPeriod != nil {
+		return nt.Spec.WeightCalculationPeriod.Duration
+	}
+	return ctrl.DefaultPeriod
+}
+
+// listNodes : returns the nodes selected by the node filter
+func (ctrl *NetworkTopologyWeightsController) listNodes() ([]v1.Node, error) {
+	list, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]v1.Node, 0, len(list))
+	for _, node := range list {
+		nodes = append(nodes, *node)
+	}
+	return ctrl.NodeFilter.Filter(nodes), nil
+}
+
+// selectNodes : returns the nodes matching the node selector of nt, nodes itself if nt sets none
+func selectNodes(nt *v1alpha1.NetworkTopology, nodes []v1.Node) ([]v1.Node, error) {
+	if nt.Spec.NodeSelector == nil {
+		return nodes, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(nt.Spec.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	return util.NodeFilter{Selector: selector}.Filter(nodes), nil
+}
+
+// calculateWeights : replaces the weights of every algorithm of nt, see weightsEntryName, by the ones calculated out
+// of nodes and the current costs of the weights named WeightsName, for every tier of nt, and records the calculation
+// in the status. The topologies of an interface class are kept as is.
+func (ctrl *NetworkTopologyWeightsController) calculateWeights(ctx context.Context, nt *v1alpha1.NetworkTopology, nodes []v1.Node) error {
+	now := metav1.NewTime(ctrl.clock.Now())
+	return updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+		var costs v1alpha1.TopologyList
+		for _, key := range tierKeys(nt) {
+			if t, ok := weightsTopology(nt, ctrl.WeightsName, key); ok {
+				costs = append(costs, t)
+			}
+		}
+		options := ctrl.Options
+		options.Tiers = weightsTiers(nt, ctrl.Options)
+		options.Paths = ctrl.pathCache(nt)
+		algorithms := weightAlgorithms(nt)
+		// The costs are read before any weights is replaced, so that every algorithm starts from the same ones.
+		var calculated v1alpha1.WeightList
+		for _, algorithm := range algorithms {
+			options.Algorithm = algorithm
+			options.WeightsName = weightsEntryName(ctrl.WeightsName, algorithm)
+			calculated = append(calculated, weights.ComputeWeights(nodes, costs, options)...)
+		}
+		for _, w := range calculated {
+			for _, t := range w.TopologyList {
+				if current, ok := weightsTopology(nt, ctrl.WeightsName, t.TopologyKey); ok {
+					t.CostUnit = current.CostUnit
+				}
+				setWeightsTopology(nt, w.Name, t)
+			}
+		}
+		nt.Status.NodeCount = int64(len(nodes))
+		nt.Status.WeightCalculationTime = now
+		klog.V(4).InfoS("Calculated the weights of the NetworkTopology", "networkTopology", klog.KObj(nt), "weights", ctrl.WeightsName,
+			"algorithms", algorithms, "nodes", len(nodes))
+		return true
+	})
+}
+
+// pathCache : returns the cheapest paths computed for nt, empty if none yet, nil if nt has no key
+func (ctrl *NetworkTopologyWeightsController) pathCache(nt *v1alpha1.NetworkTopology) *weights.PathCache {
+	key, err := cache.MetaNamespaceKeyFunc(nt)
+	if err != nil {
+		return nil
+	}
+	if ctrl.paths[key] == nil {
+		ctrl.paths[key] = weights.NewPathCache()
+	}
+	return ctrl.paths[key]
+}
+
+// weightAlgorithms : returns the algorithms of nt, its deprecated weightAlgorithm if none, Dijkstra if neither is set
+func weightAlgorithms(nt *v1alpha1.NetworkTopology) []v1alpha1.WeightAlgorithm {
+	if len(nt.Spec.Algorithms) != 0 {
+		return nt.Spec.Algorithms
+	}
+	if nt.Spec.WeightAlgorithm != "" {
+		return []v1alpha1.WeightAlgorithm{nt.Spec.WeightAlgorithm}
+	}
+	return []v1alpha1.WeightAlgorithm{v1alpha1.WeightAlgorithmDijkstra}
+}
+
+// weightsEntryName : returns the name of the weights receiving the costs calculated out of the weights named
+// weightsName by the given algorithm: weightsName itself for Dijkstra, weightsName with the algorithm as suffix
+// for the others so that the costs known are kept apart from the ones calculated
+func weightsEntryName(weightsName string, algorithm v1alpha1.WeightAlgorithm) string {
+	if algorithm == v1alpha1.WeightAlgorithmDijkstra {
+		return weightsName
+	}
+	return weightsName + string(algorithm)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+)
+
+func TestNetworkTopologyWeightsController(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	cost := func(destination string, cost int64) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: cost}
+	}
+	storage := v1alpha1.TopologyInfo{TopologyKey: v1alpha1.NetworkTopologyZone, InterfaceClass: "storage", OriginList: v1alpha1.OriginList{
+		{Origin: "z1", CostList: v1alpha1.CostList{cost("z2", 20)}},
+	}}
+	makeNT := func(name string, period *metav1.Duration, calculated time.Duration) *v1alpha1.NetworkTopology {
+		return &v1alpha1.NetworkTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.NetworkTopologySpec{
+				Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+					{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{cost("z2", 7)}},
+					}},
+					storage,
+				}}},
+				WeightCalculationPeriod: period,
+			},
+			Status: v1alpha1.NetworkTopologyStatus{WeightCalculationTime: metav1.NewTime(now.Add(-calculated))},
+		}
+	}
+	// due calculated its weights an hour ago, every 30 minutes; recent every 2 hours; unset follows the default period.
+	due := makeNT("due", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	recent := makeNT("recent", &metav1.Duration{Duration: 2 * time.Hour}, time.Hour)
+	unset := makeNT("unset", nil, 24*time.Hour)
+	// tiered declares the region and rack tiers, the region one crossed at the default cross-region cost.
+	tiered := makeNT("tiered", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	tiered.Spec.Tiers = []v1alpha1.TopologyTier{
+		{TopologyKey: v1alpha1.NetworkTopologyRegion},
+		{TopologyKey: "example.com/rack", CrossCost: pointer.Int64(2)},
+	}
+	// allPairs has its weights calculated by Floyd–Warshall.
+	allPairs := makeNT("all-pairs", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	allPairs.Spec.WeightAlgorithm = v1alpha1.WeightAlgorithmFloydWarshall
+	// manual has its weights calculated by Dijkstra, and by hand in their own weights.
+	manual := makeNT("manual", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	manual.Spec.Algorithms = []v1alpha1.WeightAlgorithm{v1alpha1.WeightAlgorithmDijkstra, v1alpha1.WeightAlgorithmManual}
+	// bandwidth declares the capacity of a link, written its allocations by the bandwidth controller.
+	bandwidth := makeNT("bandwidth", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	link := v1alpha1.CostInfo{Destination: "z2", NetworkCost: 7, BandwidthCapacity: resource.MustParse("10G"),
+		BandwidthAllocated: resource.MustParse("1G"), Links: []v1alpha1.ParallelLink{
+			{Name: "path-a", BandwidthCapacity: resource.MustParse("4G"), BandwidthAllocated: resource.MustParse("400M")},
+			{Name: "path-b", BandwidthCapacity: resource.MustParse("6G"), BandwidthAllocated: resource.MustParse("600M")},
+		}}
+	bandwidth.Spec.Weights[0].TopologyList[0].OriginList[0].CostList[0] = link
+	// selected leaves the nodes of z3 out.
+	selected := makeNT("selected", &metav1.Duration{Duration: 30 * time.Minute}, time.Hour)
+	selected.Spec.NodeSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: v1.LabelTopologyZone, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"z3"}},
+	}}
+
+	node := func(name, zone string) *v1.Node {
+		return st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, zone).
+			Label("example.com/rack", "rack-"+zone).Obj()
+	}
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(due, recent, unset, tiered, allPairs, manual, bandwidth, selected)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	for _, n := range []*v1.Node{node("n1", "z1"), node("n2", "z2"), node("n3", "z3")} {
+		nodeInformer.Informer().GetIndexer().Add(n)
+	}
+	for _, nt := range []*v1alpha1.NetworkTopology{due, recent, unset, tiered, allPairs, manual, bandwidth, selected} {
+		ntInformer.Informer().GetIndexer().Add(nt)
+	}
+
+	ctrl := NewNetworkTopologyWeightsController(schedClient, ntInformer, nodeInformer, NetworkTopologyWeightsOptions{
+		Options: weights.Options{WeightsName: "UserDefined", SameZoneCost: 1, CrossZoneCost: 5, CrossRegionCost: 50},
+	})
+	ctrl.clock = testingclock.NewFakeClock(now)
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(name string) *v1alpha1.NetworkTopology {
+		nt, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nt
+	}
+	got := get("due")
+	// The known cost and its unit are kept, z3 joins with the default costs.
+	expected := v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+		{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{cost("z1", 1), cost("z2", 7), cost("z3", 5)}},
+			{Origin: "z2", CostList: v1alpha1.CostList{cost("z1", 5), cost("z2", 1), cost("z3", 5)}},
+			{Origin: "z3", CostList: v1alpha1.CostList{cost("z1", 5), cost("z2", 5), cost("z3", 1)}},
+		}},
+		storage,
+	}}}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+	if got.Status.NodeCount != 3 || !got.Status.WeightCalculationTime.Time.Equal(metav1.NewTime(now).Time) {
+		t.Errorf("expected the calculation of 3 nodes recorded at %v, got %v", now, got.Status)
+	}
+	// The rack costs of tiered are calculated, its zone costs kept as is.
+	got = get("tiered")
+	expected = v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+		tiered.Spec.Weights[0].TopologyList[0],
+		storage,
+		{TopologyKey: "example.com/rack", OriginList: v1alpha1.OriginList{
+			{Origin: "rack-z1", CostList: v1alpha1.CostList{cost("rack-z1", 1), cost("rack-z2", 2), cost("rack-z3", 2)}},
+			{Origin: "rack-z2", CostList: v1alpha1.CostList{cost("rack-z1", 2), cost("rack-z2", 1), cost("rack-z3", 2)}},
+			{Origin: "rack-z3", CostList: v1alpha1.CostList{cost("rack-z1", 2), cost("rack-z2", 2), cost("rack-z3", 1)}},
+		}},
+	}}}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+
+	// The Floyd–Warshall costs of all-pairs are the same, in their own weights, the known costs being kept as is.
+	got = get("all-pairs")
+	expected = v1alpha1.WeightList{
+		allPairs.Spec.Weights[0],
+		{Name: "UserDefinedFloydWarshall", TopologyList: v1alpha1.TopologyList{get("due").Spec.Weights[0].TopologyList[0]}},
+	}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+
+	// Every algorithm of manual writes its weights, out of the same known costs.
+	got = get("manual")
+	expected = v1alpha1.WeightList{
+		get("due").Spec.Weights[0],
+		{Name: "UserDefinedManual", TopologyList: v1alpha1.TopologyList{get("due").Spec.Weights[0].TopologyList[0]}},
+	}
+	if !reflect.DeepEqual(expected, got.Spec.Weights) {
+		t.Errorf("expected the weights %v, got %v", expected, got.Spec.Weights)
+	}
+
+	// The bandwidth of the links is kept along their new costs.
+	got = get("bandwidth")
+	if z1 := got.Spec.Weights[0].TopologyList[0].OriginList[0]; !reflect.DeepEqual(v1alpha1.CostList{cost("z1", 1), link, cost("z3", 5)}, z1.CostList) {
+		t.Errorf("expected the bandwidth of the link from z1 to z2 kept, got %v", z1.CostList)
+	}
+
+	// The nodes of selected are the ones matching its node selector.
+	got = get("selected")
+	if z1 := got.Spec.Weights[0].TopologyList[0].OriginList[0]; len(got.Spec.Weights[0].TopologyList[0].OriginList) != 2 ||
+		!reflect.DeepEqual(v1alpha1.CostList{cost("z1", 1), cost("z2", 7)}, z1.CostList) {
+		t.Errorf("expected the weights of z1 and z2 only, got %v", got.Spec.Weights)
+	}
+	if got.Status.NodeCount != 2 {
+		t.Errorf("expected the calculation of 2 nodes, got %v", got.Status.NodeCount)
+	}
+
+	for _, name := range []string{"recent", "unset"} {
+		if got := get(name); len(got.Spec.Weights[0].TopologyList[0].OriginList) != 1 {
+			t.Errorf("expected the weights of %s not calculated, got %v", name, got.Spec.Weights)
+		}
+	}
+
+	// With a default period, the NetworkTopologies not setting any are calculated too.
+	ctrl.DefaultPeriod = 12 * time.Hour
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := get("unset"); len(got.Spec.Weights[0].TopologyList[0].OriginList) != 3 {
+		t.Errorf("expected the weights of unset calculated, got %v", got.Spec.Weights)
+	}
+
+	// The cheapest paths of the NetworkTopologies calculated are kept for their next calculation, until deleted.
+	if ctrl.paths["default/due"] == nil || ctrl.paths["default/recent"] != nil {
+		t.Errorf("expected the paths of due kept and none of recent, got %v", ctrl.paths)
+	}
+	ntInformer.Informer().GetIndexer().Delete(due)
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ctrl.paths["default/due"]; ok {
+		t.Errorf("expected the paths of due dropped once deleted")
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+// NodeBandwidthController : a controller annotating the nodes with the egress bandwidth capacity of
+// their NIC, as declared by the NodeBandwidthProfile of their instance type
+type NodeBandwidthController struct {
+	nodeQueue        workqueue.RateLimitingInterface
+	nodeLister       corelister.NodeLister
+	nbpLister        schedlister.NodeBandwidthProfileLister
+	nodeListerSynced cache.InformerSynced
+	nbpListerSynced  cache.InformerSynced
+	client           kubernetes.Interface
+}
+
+// NewNodeBandwidthController : returns a new *NodeBandwidthController
+func NewNodeBandwidthController(client kubernetes.Interface,
+	nbpInformer schedinformer.NodeBandwidthProfileInformer,
+	nodeInformer coreinformer.NodeInformer) *NodeBandwidthController {
+	ctrl := &NodeBandwidthController{
+		nodeQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NodeBandwidth"),
+	}
+
+	klog.V(5).InfoS("Setting up NodeBandwidthProfile event handlers")
+	nbpInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.nbpAdded,
+		UpdateFunc: ctrl.nbpUpdated,
+		DeleteFunc: ctrl.nbpDeleted,
+	})
+
+	klog.V(5).InfoS("Setting up Node event handlers")
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.nodeAdded,
+		UpdateFunc: ctrl.nodeUpdated,
+	})
+
+	ctrl.nodeLister = nodeInformer.Lister()
+	ctrl.nbpLister = nbpInformer.Lister()
+	ctrl.nodeListerSynced = nodeInformer.Informer().HasSynced
+	ctrl.nbpListerSynced = nbpInformer.Informer().HasSynced
+	ctrl.client = client
+	return ctrl
+}
+
+// Run : starts listening on channel events
+func (ctrl *NodeBandwidthController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.nodeQueue.ShutDown()
+
+	klog.InfoS("Starting Node Bandwidth controller")
+	defer klog.InfoS("Shutting Node Bandwidth controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.nodeListerSynced, ctrl.nbpListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	klog.InfoS("Node Bandwidth sync finished")
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// nodeAdded : reacts to a Node creation
+func (ctrl *NodeBandwidthController) nodeAdded(obj interface{}) {
+	node := obj.(*v1.Node)
+	ctrl.nodeQueue.Add(node.Name)
+}
+
+// nodeUpdated : reacts to a Node update, if its instance type or its capacity annotation changed.
+// Status updates, e.g. kubelet heartbeats, are dropped before reaching the queue, while resyncs,
+// delivering an unchanged node, are always queued so that they still repair drifted annotations
+func (ctrl *NodeBandwidthController) nodeUpdated(old, new interface{}) {
+	oldNode, newNode := old.(*v1.Node), new.(*v1.Node)
+	if oldNode.ResourceVersion != newNode.ResourceVersion && !nodeBandwidthChanged(oldNode, newNode) {
+		return
+	}
+	ctrl.nodeAdded(new)
+}
+
+// nodeBandwidthChanged : tells whether the instance type or the capacity annotation of a node changed
+func nodeBandwidthChanged(oldNode, newNode *v1.Node) bool {
+	return oldNode.Labels[v1.LabelInstanceTypeStable] != newNode.Labels[v1.LabelInstanceTypeStable] ||
+		oldNode.Annotations[v1alpha1.NodeEgressBandwidthCapacityAnnotation] != newNode.Annotations[v1alpha1.NodeEgressBandwidthCapacityAnnotation]
+}
+
+// nbpAdded : reacts to a NodeBandwidthProfile creation
+func (ctrl *NodeBandwidthController) nbpAdded(obj interface{}) {
+	ctrl.enqueueInstanceTypeNodes(obj.(*v1alpha1.NodeBandwidthProfile).Spec.InstanceType)
+}
+
+// nbpUpdated : reacts to a NodeBandwidthProfile update, if its instance type or its bandwidth changed,
+// as every node of the instance type is queued
+func (ctrl *NodeBandwidthController) nbpUpdated(old, new interface{}) {
+	oldProfile, newProfile := old.(*v1alpha1.NodeBandwidthProfile), new.(*v1alpha1.NodeBandwidthProfile)
+	if oldProfile.ResourceVersion != newProfile.ResourceVersion && oldProfile.Spec.InstanceType == newProfile.Spec.InstanceType &&
+		oldProfile.Spec.EgressBandwidth.Cmp(newProfile.Spec.EgressBandwidth) == 0 {
+		return
+	}
+	if oldProfile.Spec.InstanceType != newProfile.Spec.InstanceType {
+		ctrl.enqueueInstanceTypeNodes(oldProfile.Spec.InstanceType)
+	}
+	ctrl.enqueueInstanceTypeNodes(newProfile.Spec.InstanceType)
+}
+
+// nbpDeleted : reacts to a NodeBandwidthProfile deletion
+func (ctrl *NodeBandwidthController) nbpDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	profile, ok := obj.(*v1alpha1.NodeBandwidthProfile)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object %#v", obj))
+		return
+	}
+	ctrl.enqueueInstanceTypeNodes(profile.Spec.InstanceType)
+}
+
+// enqueueInstanceTypeNodes : enqueues the nodes of an instance type
+func (ctrl *NodeBandwidthController) enqueueInstanceTypeNodes(instanceType string) {
+	if instanceType == "" {
+		return
+	}
+	selector := labels.SelectorFromSet(labels.Set{v1.LabelInstanceTypeStable: instanceType})
+	nodes, err := ctrl.nodeLister.List(selector)
+	if err != nil {
+		klog.ErrorS(err, "Error while listing nodes", "instanceType", instanceType)
+		return
+	}
+	for _, node := range nodes {
+		ctrl.nodeQueue.Add(node.Name)
+	}
+}
+
+func (ctrl *NodeBandwidthController) worker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem : deals with one key off the queue.  It returns false when it's time to quit.
+func (ctrl *NodeBandwidthController) processNextWorkItem() bool {
+	keyObj, quit := ctrl.nodeQueue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.nodeQueue.Done(keyObj)
+
+	key, ok := keyObj.(string)
+	if !ok {
+		ctrl.nodeQueue.Forget(keyObj)
+		runtime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", keyObj))
+		return true
+	}
+	if err := ctrl.syncHandler(key); err != nil {
+		runtime.HandleError(err)
+		klog.ErrorS(err, "Error syncing node bandwidth", "node", key)
+		ctrl.nodeQueue.AddRateLimited(key)
+		return true
+	}
+	ctrl.nodeQueue.Forget(keyObj)
+	return true
+}
+
+// syncHandler : sets the capacity annotation of a node to the bandwidth of the profile of its
+// instance type, or removes it if there is no such profile
+func (ctrl *NodeBandwidthController) syncHandler(name string) error {
+	node, err := ctrl.nodeLister.Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("Node has been deleted", "node", name)
+		return nil
+	}
+	if err != nil {
+		klog.V(3).ErrorS(err, "Unable to retrieve node from store", "node", name)
+		return err
+	}
+
+	capacity, err := ctrl.egressCapacity(node)
+	if err != nil {
+		return err
+	}
+	current, annotated := node.Annotations[v1alpha1.NodeEgressBandwidthCapacityAnnotation]
+	if (capacity == nil && !annotated) || (capacity != nil && *capacity == current) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{v1alpha1.NodeEgressBandwidthCapacityAnnotation: capacity},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Updating node egress bandwidth capacity", "node", klog.KObj(node), "capacity", capacity)
+	_, err = ctrl.client.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// egressCapacity returns the egress bandwidth capacity of a node, nil if no profile matches its
+// instance type. Should several profiles match, the first one by name wins.
+func (ctrl *NodeBandwidthController) egressCapacity(node *v1.Node) (*string, error) {
+	instanceType := node.Labels[v1.LabelInstanceTypeStable]
+	if instanceType == "" {
+		return nil, nil
+	}
+	profiles, err := ctrl.nbpLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	for _, profile := range profiles {
+		if profile.Spec.InstanceType == instanceType {
+			capacity := profile.Spec.EgressBandwidth.String()
+			return &capacity, nil
+		}
+	}
+	return nil, nil
+}
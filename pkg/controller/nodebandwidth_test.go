@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/controller"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestNodeBandwidthController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	makeProfile := func(name, instanceType, bandwidth string) *v1alpha1.NodeBandwidthProfile {
+		return &v1alpha1.NodeBandwidthProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.NodeBandwidthProfileSpec{InstanceType: instanceType, EgressBandwidth: resource.MustParse(bandwidth)},
+		}
+	}
+	staleNode := st.MakeNode().Name("stale").Label(v1.LabelInstanceTypeStable, "unknown").Obj()
+	staleNode.Annotations = map[string]string{v1alpha1.NodeEgressBandwidthCapacityAnnotation: "1G"}
+	kubeClient := fake.NewSimpleClientset(
+		st.MakeNode().Name("small").Label(v1.LabelInstanceTypeStable, "m5.large").Obj(),
+		st.MakeNode().Name("large").Label(v1.LabelInstanceTypeStable, "m5.24xlarge").Obj(),
+		st.MakeNode().Name("bare").Obj(),
+		staleNode,
+	)
+	schedClient := schedfake.NewSimpleClientset(
+		makeProfile("m5-large", "m5.large", "10G"),
+		makeProfile("m5-24xlarge", "m5.24xlarge", "25G"),
+		// Ignored in favor of "m5-24xlarge", first by name.
+		makeProfile("m5-24xlarge-legacy", "m5.24xlarge", "20G"),
+	)
+
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	ctrl := NewNodeBandwidthController(kubeClient, schedInformerFactory.Scheduling().V1alpha1().NodeBandwidthProfiles(),
+		informerFactory.Core().V1().Nodes())
+	informerFactory.Start(ctx.Done())
+	schedInformerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	expected := map[string]string{"small": "10G", "large": "25G", "bare": "", "stale": ""}
+	err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		for name, capacity := range expected {
+			node, err := kubeClient.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if node.Annotations[v1alpha1.NodeEgressBandwidthCapacityAnnotation] != capacity {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("nodes not annotated with %v: %v", expected, err)
+	}
+}
+
+func TestNodeBandwidthController_nodeUpdated(t *testing.T) {
+	ctrl := NewNodeBandwidthController(fake.NewSimpleClientset(),
+		schedinformer.NewSharedInformerFactory(schedfake.NewSimpleClientset(), 0).Scheduling().V1alpha1().NodeBandwidthProfiles(),
+		informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Nodes())
+
+	node := st.MakeNode().Name("node").Label(v1.LabelInstanceTypeStable, "m5.large").Obj()
+	node.ResourceVersion = "1"
+	heartbeat := node.DeepCopy()
+	heartbeat.ResourceVersion = "2"
+	heartbeat.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue, LastHeartbeatTime: metav1.Now()}}
+	resized := heartbeat.DeepCopy()
+	resized.ResourceVersion = "3"
+	resized.Labels[v1.LabelInstanceTypeStable] = "m5.24xlarge"
+
+	tests := []struct {
+		name     string
+		old, new *v1.Node
+		queued   bool
+	}{
+		{name: "heartbeat", old: node, new: heartbeat},
+		{name: "instance type change", old: heartbeat, new: resized, queued: true},
+		{name: "resync", old: resized, new: resized, queued: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl.nodeUpdated(tt.old, tt.new)
+			if queued := ctrl.nodeQueue.Len() == 1; queued != tt.queued {
+				t.Errorf("expected queued %v, got %v", tt.queued, queued)
+			}
+			for ctrl.nodeQueue.Len() != 0 {
+				key, _ := ctrl.nodeQueue.Get()
+				ctrl.nodeQueue.Done(key)
+			}
+		})
+	}
+}
@@ -18,23 +18,28 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	batchinformer "k8s.io/client-go/informers/batch/v1"
 	coreinformer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchlister "k8s.io/client-go/listers/batch/v1"
 	corelister "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -54,15 +59,19 @@ type PodGroupController struct {
 	pgQueue         workqueue.RateLimitingInterface
 	pgLister        schedlister.PodGroupLister
 	podLister       corelister.PodLister
+	jobLister       batchlister.JobLister
 	pgListerSynced  cache.InformerSynced
 	podListerSynced cache.InformerSynced
+	jobListerSynced cache.InformerSynced
 	pgClient        schedclientset.Interface
+	kubeClient      kubernetes.Interface
 }
 
 // NewPodGroupController returns a new *PodGroupController
 func NewPodGroupController(client kubernetes.Interface,
 	pgInformer schedinformer.PodGroupInformer,
 	podInformer coreinformer.PodInformer,
+	jobInformer batchinformer.JobInformer,
 	pgClient schedclientset.Interface) *PodGroupController {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
@@ -79,13 +88,17 @@ func NewPodGroupController(client kubernetes.Interface,
 	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    ctrl.podAdded,
 		UpdateFunc: ctrl.podUpdated,
+		DeleteFunc: ctrl.podDeleted,
 	})
 
 	ctrl.pgLister = pgInformer.Lister()
 	ctrl.podLister = podInformer.Lister()
+	ctrl.jobLister = jobInformer.Lister()
 	ctrl.pgListerSynced = pgInformer.Informer().HasSynced
 	ctrl.podListerSynced = podInformer.Informer().HasSynced
+	ctrl.jobListerSynced = jobInformer.Informer().HasSynced
 	ctrl.pgClient = pgClient
+	ctrl.kubeClient = client
 	return ctrl
 }
 
@@ -96,7 +109,7 @@ func (ctrl *PodGroupController) Run(workers int, stopCh <-chan struct{}) {
 	klog.InfoS("Starting Pod Group controller")
 	defer klog.InfoS("Shutting Pod Group controller")
 
-	if !cache.WaitForCacheSync(stopCh, ctrl.pgListerSynced, ctrl.podListerSynced) {
+	if !cache.WaitForCacheSync(stopCh, ctrl.pgListerSynced, ctrl.podListerSynced, ctrl.jobListerSynced) {
 		klog.ErrorS(nil, "Cannot sync caches")
 		return
 	}
@@ -116,7 +129,9 @@ func (ctrl *PodGroupController) pgAdded(obj interface{}) {
 		return
 	}
 	pg := obj.(*schedv1alpha1.PodGroup)
-	if pg.Status.Phase == schedv1alpha1.PodGroupFinished || pg.Status.Phase == schedv1alpha1.PodGroupFailed {
+	// A PodGroup failing scheduling is still synced, to be reset once its pods are gone.
+	if pg.Status.Phase == schedv1alpha1.PodGroupFinished || (pg.Status.Phase == schedv1alpha1.PodGroupFailed &&
+		!meta.IsStatusConditionTrue(pg.Status.Conditions, schedv1alpha1.PodGroupSchedulingFailed)) {
 		return
 	}
 	// If startScheduleTime - createTime > 2days, do not enqueue again because pod may have been GCed
@@ -154,6 +169,16 @@ func (ctrl *PodGroupController) podUpdated(old, new interface{}) {
 	ctrl.podAdded(new)
 }
 
+// podDeleted reacts to a pod deletion, so that a PodGroup left without pods gets reset
+func (ctrl *PodGroupController) podDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if pod, ok := obj.(*v1.Pod); ok {
+		ctrl.podAdded(pod)
+	}
+}
+
 func (ctrl *PodGroupController) worker() {
 	for ctrl.processNextWorkItem() {
 	}
@@ -213,13 +238,19 @@ func (ctrl *PodGroupController) syncHandler(key string) error {
 		return err
 	}
 
+	if err = ctrl.tolerateMembers(pgCopy, pods); err != nil {
+		return err
+	}
+	ctrl.fillJobMinMember(pgCopy, pods)
+	fillMilestones(pgCopy, pods)
+
 	switch pgCopy.Status.Phase {
 	case "":
 		pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
 	case schedv1alpha1.PodGroupPending:
-		if len(pods) >= int(pg.Spec.MinMember) {
+		if len(pods) >= int(pgCopy.Spec.MinMember) {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupPreScheduling
-			fillOccupiedObj(pg, pods[0])
+			fillOccupiedObj(pgCopy, pods[0])
 		}
 	default:
 		var (
@@ -245,6 +276,11 @@ func (ctrl *PodGroupController) syncHandler(key string) error {
 
 		if len(pods) == 0 {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
+			// A new gang has to be scheduled in full again.
+			meta.RemoveStatusCondition(&pgCopy.Status.Conditions, schedv1alpha1.PodGroupFullyScheduled)
+			meta.RemoveStatusCondition(&pgCopy.Status.Conditions, schedv1alpha1.PodGroupSchedulingFailed)
+			pgCopy.Status.Milestones = nil
+			pgCopy.Status.SchedulingFailures = 0
 			break
 		}
 
@@ -252,42 +288,187 @@ func (ctrl *PodGroupController) syncHandler(key string) error {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupScheduled
 		}
 
-		if pgCopy.Status.Succeeded+pgCopy.Status.Running >= pg.Spec.MinMember && pgCopy.Status.Phase == schedv1alpha1.PodGroupScheduled {
+		if pgCopy.Status.Succeeded+pgCopy.Status.Running >= pgCopy.Spec.MinMember && pgCopy.Status.Phase == schedv1alpha1.PodGroupScheduled {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupRunning
 		}
+		if pgCopy.Status.Running >= pgCopy.Spec.MinMember && !meta.IsStatusConditionTrue(pgCopy.Status.Conditions, schedv1alpha1.PodGroupFullyScheduled) {
+			meta.SetStatusCondition(&pgCopy.Status.Conditions, metav1.Condition{
+				Type:               schedv1alpha1.PodGroupFullyScheduled,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: pgCopy.Generation,
+				Reason:             "MinMemberRunning",
+				Message:            fmt.Sprintf("%d pods were running, reaching minMember %d", pgCopy.Status.Running, pgCopy.Spec.MinMember),
+			})
+		}
 		// Final state of pod group
-		if pgCopy.Status.Failed != 0 && pgCopy.Status.Failed+pgCopy.Status.Running+pgCopy.Status.Succeeded >= pg.Spec.
+		if pgCopy.Status.Failed != 0 && pgCopy.Status.Failed+pgCopy.Status.Running+pgCopy.Status.Succeeded >= pgCopy.Spec.
 			MinMember {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupFailed
 		}
-		if pgCopy.Status.Succeeded >= pg.Spec.MinMember {
+		if pgCopy.Status.Succeeded >= pgCopy.Spec.MinMember {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupFinished
 		}
 	}
 
-	err = ctrl.patchPodGroup(pg, pgCopy)
+	err = ctrl.updatePodGroup(pg, pgCopy)
 	if err == nil {
 		ctrl.pgQueue.Forget(pg)
 	}
 	return err
 }
 
-func (ctrl *PodGroupController) patchPodGroup(old, new *schedv1alpha1.PodGroup) error {
-	if !reflect.DeepEqual(old, new) {
-		patch, err := util.CreateMergePatch(old, new)
-		if err != nil {
-			return err
+// updatePodGroup : updates the PodGroup with new if it differs from old, the lister's PodGroup it was copied from. The
+// update is guarded by the resourceVersion of old, so that the conditions written by the scheduler in between are not
+// overwritten: on conflict, the PodGroup is synced again once the lister holds the latest one
+func (ctrl *PodGroupController) updatePodGroup(old, new *schedv1alpha1.PodGroup) error {
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+	_, err := ctrl.pgClient.SchedulingV1alpha1().PodGroups(new.Namespace).Update(context.TODO(), new, metav1.UpdateOptions{})
+	return err
+}
+
+// tolerateMembers : adds the tolerations of a PodGroup missing from its members not scheduled yet, so that they get
+// scheduled on the nodes tainted for the gang. The scheduler checks the taints of a node against the tolerations of the
+// pod only; a pod may be added tolerations, never have them changed
+func (ctrl *PodGroupController) tolerateMembers(pg *schedv1alpha1.PodGroup, pods []*v1.Pod) error {
+	if len(pg.Spec.Tolerations) == 0 {
+		return nil
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if patch := tolerationsPatch(pod.Spec.Tolerations, pg.Spec.Tolerations); patch != nil {
+			klog.V(4).InfoS("Adding the tolerations of the PodGroup to its pod", "podGroup", klog.KObj(pg), "pod", klog.KObj(pod))
+			if _, err := ctrl.kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tolerationsPatch : returns a merge patch appending the wanted tolerations missing from current to them, nil if none
+// is. The patch replaces the whole list, keeping the current tolerations unchanged as the API server requires
+func tolerationsPatch(current, wanted []v1.Toleration) []byte {
+	tolerations := append([]v1.Toleration(nil), current...)
+	for i := range wanted {
+		found := false
+		for j := range current {
+			if current[j].MatchToleration(&wanted[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tolerations = append(tolerations, wanted[i])
+		}
+	}
+	if len(tolerations) == len(current) {
+		return nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"tolerations": tolerations},
+	})
+	return patch
+}
+
+// fillJobMinMember derives the effective minMember of a PodGroup from the Job owning its pods.
+// An Indexed Job whose PodGroup omits minMember gets it set to the number of pods the Job runs
+// at once, i.e. min(parallelism, completions). A minMember above the Job's parallelism can never
+// be reached, so it is surfaced as a MinMemberSatisfiable=False condition.
+func (ctrl *PodGroupController) fillJobMinMember(pg *schedv1alpha1.PodGroup, pods []*v1.Pod) {
+	job := ctrl.getOwnerJob(pods)
+	if job == nil {
+		return
+	}
+
+	parallelism := getJobParallelism(job)
+	if pg.Spec.MinMember == 0 && isIndexedJob(job) {
+		minMember := parallelism
+		if job.Spec.Completions != nil && *job.Spec.Completions < minMember {
+			minMember = *job.Spec.Completions
 		}
+		klog.V(4).InfoS("Set minMember from Indexed Job", "podGroup", klog.KObj(pg), "job", klog.KObj(job), "minMember", minMember)
+		pg.Spec.MinMember = minMember
+	}
 
-		_, err = ctrl.pgClient.SchedulingV1alpha1().PodGroups(old.Namespace).Patch(context.TODO(), old.Name, types.MergePatchType,
-			patch, metav1.PatchOptions{})
+	condition := metav1.Condition{
+		Type:               schedv1alpha1.PodGroupMinMemberSatisfiable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: pg.Generation,
+		Reason:             "MinMemberWithinParallelism",
+		Message:            fmt.Sprintf("minMember %d is within the parallelism %d of Job %s", pg.Spec.MinMember, parallelism, job.Name),
+	}
+	if pg.Spec.MinMember > parallelism {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MinMemberExceedsParallelism"
+		condition.Message = fmt.Sprintf("minMember %d exceeds the parallelism %d of Job %s, the group can never be scheduled", pg.Spec.MinMember, parallelism, job.Name)
+		if !meta.IsStatusConditionFalse(pg.Status.Conditions, condition.Type) {
+			ctrl.eventRecorder.Event(pg, v1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&pg.Status.Conditions, condition)
+}
+
+// fillMilestones records when the first pod of a PodGroup was created, and when the group reached
+// minMember pods, from the creation time of its pods. Milestones already recorded are kept.
+func fillMilestones(pg *schedv1alpha1.PodGroup, pods []*v1.Pod) {
+	created := make([]metav1.Time, 0, len(pods))
+	for _, pod := range pods {
+		if !pod.CreationTimestamp.IsZero() {
+			created = append(created, pod.CreationTimestamp)
+		}
+	}
+	if len(created) == 0 {
+		return
+	}
+	sort.Slice(created, func(i, j int) bool { return created[i].Before(&created[j]) })
+
+	if pg.Status.Milestones == nil {
+		pg.Status.Milestones = &schedv1alpha1.PodGroupMilestones{}
+	}
+	milestones := pg.Status.Milestones
+	if milestones.FirstPodEnqueued == nil {
+		milestones.FirstPodEnqueued = &created[0]
+	}
+	if milestones.MinMemberReached == nil && pg.Spec.MinMember > 0 && len(created) >= int(pg.Spec.MinMember) {
+		milestones.MinMemberReached = &created[pg.Spec.MinMember-1]
+	}
+}
+
+// getOwnerJob returns the Job controlling the given pods, or nil if they are not owned by a Job.
+func (ctrl *PodGroupController) getOwnerJob(pods []*v1.Pod) *batchv1.Job {
+	for _, pod := range pods {
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil || ref.Kind != "Job" || ref.APIVersion != batchv1.SchemeGroupVersion.String() {
+			continue
+		}
+		job, err := ctrl.jobLister.Jobs(pod.Namespace).Get(ref.Name)
 		if err != nil {
-			return err
+			klog.V(4).ErrorS(err, "Unable to retrieve owner Job of pod", "pod", klog.KObj(pod), "job", ref.Name)
+			return nil
+		}
+		if job.UID != ref.UID {
+			return nil
 		}
+		return job
 	}
 	return nil
 }
 
+func isIndexedJob(job *batchv1.Job) bool {
+	return job.Spec.CompletionMode != nil && *job.Spec.CompletionMode == batchv1.IndexedCompletion
+}
+
+func getJobParallelism(job *batchv1.Job) int32 {
+	if job.Spec.Parallelism == nil {
+		return 1
+	}
+	return *job.Spec.Parallelism
+}
+
 func fillOccupiedObj(pg *schedv1alpha1.PodGroup, pod *v1.Pod) {
 	var refs []string
 	for _, ownerRef := range pod.OwnerReferences {
@@ -6,8 +6,12 @@ import (
 	"testing"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
@@ -32,6 +36,7 @@ func Test_Run(t *testing.T) {
 		previousPhase      v1alpha1.PodGroupPhase
 		desiredGroupPhase  v1alpha1.PodGroupPhase
 		podGroupCreateTime *metav1.Time
+		fullyScheduled     bool
 	}{
 		{
 			name:              "Group running",
@@ -41,6 +46,7 @@ func Test_Run(t *testing.T) {
 			podPhase:          v1.PodRunning,
 			previousPhase:     v1alpha1.PodGroupScheduled,
 			desiredGroupPhase: v1alpha1.PodGroupRunning,
+			fullyScheduled:    true,
 		},
 		{
 			name:              "Group running, more than min member",
@@ -50,6 +56,7 @@ func Test_Run(t *testing.T) {
 			podPhase:          v1.PodRunning,
 			previousPhase:     v1alpha1.PodGroupScheduled,
 			desiredGroupPhase: v1alpha1.PodGroupRunning,
+			fullyScheduled:    true,
 		},
 		{
 			name:              "Group failed",
@@ -152,8 +159,9 @@ func Test_Run(t *testing.T) {
 			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
 			pgInformerFactory := schedinformer.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
 			podInformer := informerFactory.Core().V1().Pods()
+			jobInformer := informerFactory.Batch().V1().Jobs()
 			pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
-			ctrl := NewPodGroupController(kubeClient, pgInformer, podInformer, pgClient)
+			ctrl := NewPodGroupController(kubeClient, pgInformer, podInformer, jobInformer, pgClient)
 
 			pgInformerFactory.Start(ctx.Done())
 			informerFactory.Start(ctx.Done())
@@ -173,6 +181,9 @@ func Test_Run(t *testing.T) {
 				if pg.Status.Phase != c.desiredGroupPhase {
 					return false, fmt.Errorf("want %v, got %v", c.desiredGroupPhase, pg.Status.Phase)
 				}
+				if fullyScheduled := meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupFullyScheduled); fullyScheduled != c.fullyScheduled {
+					return false, fmt.Errorf("want fully scheduled %v, got %v", c.fullyScheduled, fullyScheduled)
+				}
 				return true, nil
 			})
 			if err != nil {
@@ -183,6 +194,256 @@ func Test_Run(t *testing.T) {
 
 }
 
+func TestResetSchedulingFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pg := makePG("pg", 2, v1alpha1.PodGroupFailed, nil)
+	pg.Status.SchedulingFailures = 3
+	pg.Status.Conditions = []metav1.Condition{{Type: v1alpha1.PodGroupSchedulingFailed, Status: metav1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+	pod := makePods([]string{"pod1"}, "pg", v1.PodPending)[0]
+	kubeClient := fake.NewSimpleClientset(pod)
+	pgClient := pgfake.NewSimpleClientset(pg)
+
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	pgInformerFactory := schedinformer.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
+	ctrl := NewPodGroupController(kubeClient, pgInformerFactory.Scheduling().V1alpha1().PodGroups(),
+		informerFactory.Core().V1().Pods(), informerFactory.Batch().V1().Jobs(), pgClient)
+	pgInformerFactory.Start(ctx.Done())
+	informerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	// The PodGroup stays failed as long as a pod is left.
+	time.Sleep(200 * time.Millisecond)
+	got, err := pgClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, "pg", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != v1alpha1.PodGroupFailed || got.Status.SchedulingFailures != 3 {
+		t.Fatalf("want the PodGroup failed after 3 failures, got %v after %v", got.Status.Phase, got.Status.SchedulingFailures)
+	}
+
+	if err := kubeClient.CoreV1().Pods("default").Delete(ctx, "pod1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	err = wait.Poll(200*time.Millisecond, 2*time.Second, func() (bool, error) {
+		got, err := pgClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, "pg", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return got.Status.Phase == v1alpha1.PodGroupPending && got.Status.SchedulingFailures == 0 &&
+			meta.FindStatusCondition(got.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) == nil, nil
+	})
+	if err != nil {
+		t.Fatal("the PodGroup was not reset once its pods were gone:", err)
+	}
+}
+
+func TestJobMinMember(t *testing.T) {
+	ctx := context.TODO()
+	cases := []struct {
+		name              string
+		pgName            string
+		minMember         int32
+		parallelism       int32
+		completions       int32
+		completionMode    batchv1.CompletionMode
+		desiredMinMember  int32
+		desiredCondStatus metav1.ConditionStatus
+	}{
+		{
+			name:              "minMember omitted, set from parallelism of Indexed Job",
+			pgName:            "pg1",
+			parallelism:       3,
+			completions:       5,
+			completionMode:    batchv1.IndexedCompletion,
+			desiredMinMember:  3,
+			desiredCondStatus: metav1.ConditionTrue,
+		},
+		{
+			name:              "minMember omitted, set from completions of Indexed Job",
+			pgName:            "pg2",
+			parallelism:       4,
+			completions:       2,
+			completionMode:    batchv1.IndexedCompletion,
+			desiredMinMember:  2,
+			desiredCondStatus: metav1.ConditionTrue,
+		},
+		{
+			name:              "minMember omitted, NonIndexed Job left untouched",
+			pgName:            "pg3",
+			parallelism:       3,
+			completions:       3,
+			completionMode:    batchv1.NonIndexedCompletion,
+			desiredMinMember:  0,
+			desiredCondStatus: metav1.ConditionTrue,
+		},
+		{
+			name:              "minMember exceeds parallelism",
+			pgName:            "pg4",
+			minMember:         5,
+			parallelism:       2,
+			completions:       5,
+			completionMode:    batchv1.IndexedCompletion,
+			desiredMinMember:  5,
+			desiredCondStatus: metav1.ConditionFalse,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			job := makeJob("job-"+c.pgName, c.parallelism, c.completions, c.completionMode)
+			ps := makePods([]string{"pod1", "pod2"}, c.pgName, v1.PodPending)
+			for _, p := range ps {
+				p.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))}
+			}
+			kubeClient := fake.NewSimpleClientset(job, ps[0], ps[1])
+			pg := makePG(c.pgName, c.minMember, v1alpha1.PodGroupPending, nil)
+			pgClient := pgfake.NewSimpleClientset(pg)
+
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+			pgInformerFactory := schedinformer.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
+			podInformer := informerFactory.Core().V1().Pods()
+			jobInformer := informerFactory.Batch().V1().Jobs()
+			pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+			ctrl := NewPodGroupController(kubeClient, pgInformer, podInformer, jobInformer, pgClient)
+
+			pgInformerFactory.Start(ctx.Done())
+			informerFactory.Start(ctx.Done())
+			go ctrl.Run(1, ctx.Done())
+			err := wait.Poll(200*time.Millisecond, 1*time.Second, func() (done bool, err error) {
+				pg, err := pgClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, c.pgName, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if pg.Spec.MinMember != c.desiredMinMember {
+					return false, fmt.Errorf("want minMember %v, got %v", c.desiredMinMember, pg.Spec.MinMember)
+				}
+				cond := meta.FindStatusCondition(pg.Status.Conditions, v1alpha1.PodGroupMinMemberSatisfiable)
+				if cond == nil || cond.Status != c.desiredCondStatus {
+					return false, fmt.Errorf("want condition status %v, got %v", c.desiredCondStatus, cond)
+				}
+				return true, nil
+			})
+			if err != nil {
+				t.Fatal("Unexpected error", err)
+			}
+		})
+	}
+}
+
+func TestTolerateMembers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gpu := v1.Toleration{Key: "pool", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}
+	spot := v1.Toleration{Key: "spot", Operator: v1.TolerationOpExists}
+	pg := makePG("pg", 3, v1alpha1.PodGroupScheduling, nil)
+	pg.Spec.Tolerations = []v1.Toleration{gpu, spot}
+	pods := makePods([]string{"pending", "tolerating", "scheduled"}, "pg", v1.PodPending)
+	pods[1].Spec.Tolerations = []v1.Toleration{gpu}
+	pods[2].Spec.NodeName = "node-a"
+	kubeClient := fake.NewSimpleClientset(pods[0], pods[1], pods[2])
+	pgClient := pgfake.NewSimpleClientset(pg)
+
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	pgInformerFactory := schedinformer.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
+	ctrl := NewPodGroupController(kubeClient, pgInformerFactory.Scheduling().V1alpha1().PodGroups(),
+		informerFactory.Core().V1().Pods(), informerFactory.Batch().V1().Jobs(), pgClient)
+	pgInformerFactory.Start(ctx.Done())
+	informerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	expected := map[string][]v1.Toleration{
+		"pending":    {gpu, spot},
+		"tolerating": {gpu, spot},
+		"scheduled":  nil,
+	}
+	var got map[string][]v1.Toleration
+	err := wait.Poll(200*time.Millisecond, 2*time.Second, func() (bool, error) {
+		got = make(map[string][]v1.Toleration)
+		for name := range expected {
+			pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			got[name] = pod.Spec.Tolerations
+		}
+		return equality.Semantic.DeepEqual(expected, got), nil
+	})
+	if err != nil {
+		t.Fatalf("want the tolerations %v, got %v: %v", expected, got, err)
+	}
+}
+
+func TestFillMilestones(t *testing.T) {
+	now := time.Now()
+	created := func(names ...string) []*v1.Pod {
+		pods := makePods(names, "pg", v1.PodPending)
+		for i, p := range pods {
+			p.CreationTimestamp = metav1.Time{Time: now.Add(time.Duration(len(pods)-i) * -time.Minute)}
+		}
+		return pods
+	}
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+	cases := []struct {
+		name       string
+		minMember  int32
+		milestones *v1alpha1.PodGroupMilestones
+		pods       []*v1.Pod
+		desired    *v1alpha1.PodGroupMilestones
+	}{
+		{
+			name:      "no pods",
+			minMember: 2,
+		},
+		{
+			name:      "first pod created",
+			minMember: 2,
+			pods:      created("pod1"),
+			desired:   &v1alpha1.PodGroupMilestones{FirstPodEnqueued: at(-time.Minute)},
+		},
+		{
+			name:      "minMember pods created",
+			minMember: 2,
+			pods:      created("pod1", "pod2", "pod3"),
+			desired:   &v1alpha1.PodGroupMilestones{FirstPodEnqueued: at(-3 * time.Minute), MinMemberReached: at(-2 * time.Minute)},
+		},
+		{
+			name:       "recorded milestones kept",
+			minMember:  2,
+			milestones: &v1alpha1.PodGroupMilestones{FirstPodEnqueued: at(-time.Hour), PermitGranted: at(-time.Second)},
+			pods:       created("pod1", "pod2"),
+			desired: &v1alpha1.PodGroupMilestones{FirstPodEnqueued: at(-time.Hour), MinMemberReached: at(-time.Minute),
+				PermitGranted: at(-time.Second)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pg := makePG("pg", c.minMember, v1alpha1.PodGroupPending, nil)
+			pg.Status.Milestones = c.milestones
+			fillMilestones(pg, c.pods)
+			if !equality.Semantic.DeepEqual(pg.Status.Milestones, c.desired) {
+				t.Errorf("want milestones %v, got %v", c.desired, pg.Status.Milestones)
+			}
+		})
+	}
+}
+
+func makeJob(name string, parallelism, completions int32, mode batchv1.CompletionMode) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+		},
+		Spec: batchv1.JobSpec{
+			Parallelism:    &parallelism,
+			Completions:    &completions,
+			CompletionMode: &mode,
+		},
+	}
+}
+
 func makePods(podNames []string, pgName string, phase v1.PodPhase) []*v1.Pod {
 	pds := make([]*v1.Pod, 0)
 	for _, name := range podNames {
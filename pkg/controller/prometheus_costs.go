@@ -0,0 +1,242 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+const (
+	// DefaultPrometheusOriginLabel is the label of the samples naming the origin if not specified.
+	DefaultPrometheusOriginLabel = "source"
+	// DefaultPrometheusDestinationLabel is the label of the samples naming the destination if not specified.
+	DefaultPrometheusDestinationLabel = "destination"
+	// DefaultPrometheusWeightsName is the name of the weights receiving the costs if not specified.
+	DefaultPrometheusWeightsName = "Prometheus"
+)
+
+// PrometheusCostOptions configures the ingestion of the latencies queried from Prometheus for the
+// NetworkTopologies whose cost source is Prometheus.
+type PrometheusCostOptions struct {
+	// Interval is the period between two queries.
+	Interval time.Duration
+	// Timeout is the timeout of a query, none if 0.
+	Timeout time.Duration
+}
+
+// PrometheusCostController : a controller writing the average latencies between the domains of the tiers, the zones
+// and regions by default, of the localities returned by the Prometheus query of a NetworkTopology, in milliseconds,
+// as its costs
+type PrometheusCostController struct {
+	PrometheusCostOptions
+
+	ntLister         schedlister.NetworkTopologyLister
+	nodeLister       corelister.NodeLister
+	ntListerSynced   cache.InformerSynced
+	nodeListerSynced cache.InformerSynced
+	schedClient      schedclientset.Interface
+}
+
+// NewPrometheusCostController : returns a new *PrometheusCostController
+func NewPrometheusCostController(schedClient schedclientset.Interface, ntInformer schedinformer.NetworkTopologyInformer,
+	nodeInformer coreinformer.NodeInformer, options PrometheusCostOptions) *PrometheusCostController {
+	return &PrometheusCostController{
+		PrometheusCostOptions: options,
+		ntLister:              ntInformer.Lister(),
+		nodeLister:            nodeInformer.Lister(),
+		ntListerSynced:        ntInformer.Informer().HasSynced,
+		nodeListerSynced:      nodeInformer.Informer().HasSynced,
+		schedClient:           schedClient,
+	}
+}
+
+// Run : ingests the costs every Interval
+func (ctrl *PrometheusCostController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting Prometheus Cost controller", "interval", ctrl.Interval)
+	defer klog.InfoS("Shutting Prometheus Cost controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.ntListerSynced, ctrl.nodeListerSynced) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	wait.Until(func() {
+		if err := ctrl.sync(context.TODO()); err != nil {
+			klog.ErrorS(err, "Error ingesting Prometheus costs")
+		}
+	}, ctrl.Interval, stopCh)
+}
+
+// sync : ingests the costs of the NetworkTopologies whose cost source is Prometheus
+func (ctrl *PrometheusCostController) sync(ctx context.Context) error {
+	nts, err := ctrl.ntLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, nt := range nts {
+		if source := nt.Spec.CostSource; source == nil || source.Type != v1alpha1.CostSourcePrometheus {
+			continue
+		}
+		if err := ctrl.syncNetworkTopology(ctx, nt); err != nil {
+			klog.ErrorS(err, "Error ingesting Prometheus costs", "networkTopology", klog.KObj(nt))
+		}
+	}
+	return nil
+}
+
+// syncNetworkTopology : runs the query of nt and updates its weights if the costs changed
+func (ctrl *PrometheusCostController) syncNetworkTopology(ctx context.Context, nt *v1alpha1.NetworkTopology) error {
+	source := nt.Spec.CostSource.Prometheus
+	if source == nil {
+		return fmt.Errorf("missing prometheus of the Prometheus cost source")
+	}
+	weightsName := source.WeightsName
+	if weightsName == "" {
+		weightsName = DefaultPrometheusWeightsName
+	}
+
+	vector, err := ctrl.query(ctx, source)
+	if err != nil {
+		return err
+	}
+	latencies, err := ctrl.localityLatencies(source, vector)
+	if err != nil {
+		return err
+	}
+	topologies := averageCosts(latencies, tierKeys(nt))
+	if len(topologies) == 0 {
+		// Keep the last costs while the query returns no latency between domains.
+		klog.V(4).InfoS("No Prometheus latency between domains", "networkTopology", klog.KObj(nt), "samples", len(vector))
+		return nil
+	}
+	return updateNetworkTopology(ctx, ctrl.schedClient, nt, func(nt *v1alpha1.NetworkTopology) bool {
+		if !setWeightsTopologies(nt, weightsName, topologies) {
+			return false
+		}
+		klog.V(4).InfoS("Updating Prometheus costs", "networkTopology", klog.KObj(nt), "weights", weightsName, "samples", len(vector))
+		return true
+	})
+}
+
+// query : returns the latencies of the query of source
+func (ctrl *PrometheusCostController) query(ctx context.Context, source *v1alpha1.PrometheusSource) (model.Vector, error) {
+	client, err := promapi.NewClient(promapi.Config{Address: source.Address})
+	if err != nil {
+		return nil, err
+	}
+	if ctrl.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ctrl.Timeout)
+		defer cancel()
+	}
+	value, warnings, err := promv1.NewAPI(client).Query(ctx, source.Query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("querying the latencies: %w", err)
+	}
+	if len(warnings) != 0 {
+		klog.V(4).InfoS("Warnings querying Prometheus latencies", "warnings", warnings)
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %v result of the latency query", value.Type())
+	}
+	return vector, nil
+}
+
+// localityLatencies : returns the latencies of the samples between the localities of source, given by the
+// labels of the nodes. The samples of unknown nodes, or missing an origin or a destination, are left out.
+func (ctrl *PrometheusCostController) localityLatencies(source *v1alpha1.PrometheusSource, vector model.Vector) ([]localityLatency, error) {
+	originLabel, destinationLabel := source.OriginLabel, source.DestinationLabel
+	if originLabel == "" {
+		originLabel = DefaultPrometheusOriginLabel
+	}
+	if destinationLabel == "" {
+		destinationLabel = DefaultPrometheusDestinationLabel
+	}
+	localities, err := ctrl.localities(source.Locality)
+	if err != nil {
+		return nil, err
+	}
+
+	var latencies []localityLatency
+	for _, sample := range vector {
+		origin := string(sample.Metric[model.LabelName(originLabel)])
+		destination := string(sample.Metric[model.LabelName(destinationLabel)])
+		from, to := localities(origin), localities(destination)
+		if from == nil || to == nil {
+			continue
+		}
+		latencies = append(latencies, localityLatency{origin: from, destination: to, milliseconds: float64(sample.Value)})
+	}
+	return latencies, nil
+}
+
+// localities : returns the function giving the tier labels of a locality of the given kind, nil if unknown. Nodes
+// give all their labels; zones and regions are known by name and only give the region and zone labels, the
+// region of a zone being the one of its nodes.
+func (ctrl *PrometheusCostController) localities(locality v1alpha1.PrometheusLocality) (func(string) map[string]string, error) {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	switch locality {
+	case "", v1alpha1.PrometheusLocalityNode:
+		byName := make(map[string]map[string]string, len(nodes))
+		for _, node := range nodes {
+			byName[node.Name] = node.Labels
+		}
+		return func(name string) map[string]string { return byName[name] }, nil
+	case v1alpha1.PrometheusLocalityZone:
+		regions := make(map[string]string)
+		for _, node := range nodes {
+			if zone := node.Labels[v1.LabelTopologyZone]; zone != "" && regions[zone] == "" {
+				regions[zone] = node.Labels[v1.LabelTopologyRegion]
+			}
+		}
+		return func(zone string) map[string]string {
+			if zone == "" {
+				return nil
+			}
+			return map[string]string{v1.LabelTopologyZone: zone, v1.LabelTopologyRegion: regions[zone]}
+		}, nil
+	case v1alpha1.PrometheusLocalityRegion:
+		return func(region string) map[string]string {
+			if region == "" {
+				return nil
+			}
+			return map[string]string{v1.LabelTopologyRegion: region}
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown locality %q", locality)
+}
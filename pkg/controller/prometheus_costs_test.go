@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestPrometheusCostController(t *testing.T) {
+	ctx := context.TODO()
+	results := map[string]string{
+		"node_rtt": `[
+			{"metric":{"source":"n1","destination":"n2"},"value":[1650000000,"2.4"]},
+			{"metric":{"source":"n4","destination":"n2"},"value":[1650000000,"3.6"]},
+			{"metric":{"source":"n1","destination":"n3"},"value":[1650000000,"40"]},
+			{"metric":{"source":"n1","destination":"n4"},"value":[1650000000,"0.1"]},
+			{"metric":{"source":"n1","destination":"gone"},"value":[1650000000,"5"]},
+			{"metric":{"source":"n2","destination":"n3"},"value":[1650000000,"NaN"]}
+		]`,
+		"zone_rtt": `[
+			{"metric":{"src_zone":"z1","dst_zone":"z3"},"value":[1650000000,"41.2"]},
+			{"metric":{"src_zone":"z2","dst_zone":"z3"},"value":[1650000000,"28.8"]},
+			{"metric":{"src_zone":"z1","dst_zone":"z2"},"value":[1650000000,"3"]},
+			{"metric":{"dst_zone":"z2"},"value":[1650000000,"1"]}
+		]`,
+	}
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := "", false
+		if err := r.ParseForm(); err == nil {
+			result, ok = results[r.Form.Get("query")]
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("unexpected query %q", r.Form.Get("query")), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+	}))
+	defer prometheus.Close()
+
+	node := func(name, region, zone string) *v1.Node {
+		return st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, region).Label(v1.LabelTopologyZone, zone).Obj()
+	}
+	networkTopology := func(name string, source *v1alpha1.PrometheusSource) *v1alpha1.NetworkTopology {
+		return &v1alpha1.NetworkTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.NetworkTopologySpec{
+				CostSource: &v1alpha1.CostSource{Type: v1alpha1.CostSourcePrometheus, Prometheus: source},
+			},
+		}
+	}
+	byNode := networkTopology("by-node", &v1alpha1.PrometheusSource{Address: prometheus.URL, Query: "node_rtt"})
+	byZone := networkTopology("by-zone", &v1alpha1.PrometheusSource{
+		Address:          prometheus.URL,
+		Query:            "zone_rtt",
+		Locality:         v1alpha1.PrometheusLocalityZone,
+		OriginLabel:      "src_zone",
+		DestinationLabel: "dst_zone",
+		WeightsName:      "Smokeping",
+	})
+	failing := networkTopology("failing", &v1alpha1.PrometheusSource{Address: prometheus.URL, Query: "unknown"})
+
+	kubeClient := fake.NewSimpleClientset()
+	schedClient := schedfake.NewSimpleClientset(byNode, byZone, failing)
+	nodeInformer := informers.NewSharedInformerFactory(kubeClient, 0).Core().V1().Nodes()
+	ntInformer := schedinformer.NewSharedInformerFactory(schedClient, 0).Scheduling().V1alpha1().NetworkTopologies()
+	for _, n := range []*v1.Node{node("n1", "r1", "z1"), node("n2", "r1", "z2"), node("n3", "r2", "z3"), node("n4", "r1", "z1")} {
+		nodeInformer.Informer().GetIndexer().Add(n)
+	}
+	for _, nt := range []*v1alpha1.NetworkTopology{byNode, byZone, failing} {
+		ntInformer.Informer().GetIndexer().Add(nt)
+	}
+
+	ctrl := NewPrometheusCostController(schedClient, ntInformer, nodeInformer, PrometheusCostOptions{})
+	if err := ctrl.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		expected v1alpha1.WeightList
+	}{
+		{
+			// z1 to z2 averages the latencies from n1 and n4, the latencies to unknown nodes or NaN give no cost.
+			name: "by-node",
+			expected: v1alpha1.WeightList{{Name: DefaultPrometheusWeightsName, TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyRegion, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+					{Origin: "r1", CostList: v1alpha1.CostList{{Destination: "r2", NetworkCost: 40}}},
+				}},
+				{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+					{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 3}, {Destination: "z3", NetworkCost: 40}}},
+				}},
+			}}},
+		},
+		{
+			// r1 to r2 averages the latencies from z1 and z2 to z3.
+			name: "by-zone",
+			expected: v1alpha1.WeightList{{Name: "Smokeping", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyRegion, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+					{Origin: "r1", CostList: v1alpha1.CostList{{Destination: "r2", NetworkCost: 35}}},
+				}},
+				{TopologyKey: v1alpha1.NetworkTopologyZone, CostUnit: v1alpha1.NetworkCostUnitMilliseconds, OriginList: v1alpha1.OriginList{
+					{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 3}, {Destination: "z3", NetworkCost: 41}}},
+					{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 29}}},
+				}},
+			}}},
+		},
+		{
+			name: "failing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, tt.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expected, got.Spec.Weights) {
+				t.Errorf("expected the weights %v, got %v", tt.expected, got.Spec.Weights)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// SparkApplicationsResource : the SparkApplications of the spark-operator
+var SparkApplicationsResource = schema.GroupVersionResource{Group: "sparkoperator.k8s.io", Version: "v1beta2", Resource: "sparkapplications"}
+
+const sparkApplicationKind = "SparkApplication"
+
+// SparkApplicationController : a controller mapping every SparkApplication to an AppGroup, whose executor
+// workload depends on the driver workload, and to a PodGroup gathering the executors as a gang, and labeling
+// the driver and executor pod templates of the SparkApplication accordingly, so that Spark jobs get the
+// network-aware placement and the gang semantics without their users authoring these CRs
+type SparkApplicationController struct {
+	schedClient   schedclientset.Interface
+	dynamicClient dynamic.Interface
+	agLister      schedlister.AppGroupLister
+	pgLister      schedlister.PodGroupLister
+	appLister     cache.GenericLister
+	listersSynced []cache.InformerSynced
+	appQueue      workqueue.RateLimitingInterface
+}
+
+// NewSparkApplicationController : returns a new *SparkApplicationController
+func NewSparkApplicationController(schedClient schedclientset.Interface,
+	dynamicClient dynamic.Interface,
+	agInformer schedinformer.AppGroupInformer,
+	pgInformer schedinformer.PodGroupInformer,
+	appInformerFactory dynamicinformer.DynamicSharedInformerFactory) *SparkApplicationController {
+	appInformer := appInformerFactory.ForResource(SparkApplicationsResource)
+	ctrl := &SparkApplicationController{
+		schedClient:   schedClient,
+		dynamicClient: dynamicClient,
+		agLister:      agInformer.Lister(),
+		pgLister:      pgInformer.Lister(),
+		appLister:     appInformer.Lister(),
+		listersSynced: []cache.InformerSynced{
+			agInformer.Informer().HasSynced,
+			pgInformer.Informer().HasSynced,
+			appInformer.Informer().HasSynced,
+		},
+		appQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SparkApplication"),
+	}
+
+	klog.V(5).InfoS("Setting up SparkApplication event handlers")
+	appInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ctrl.appAdded,
+		UpdateFunc: func(_, new interface{}) {
+			ctrl.appAdded(new)
+		},
+	})
+	return ctrl
+}
+
+// Run : starts listening on channel events
+func (ctrl *SparkApplicationController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.appQueue.ShutDown()
+
+	klog.InfoS("Starting Spark Application controller")
+	defer klog.InfoS("Shutting Spark Application controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.listersSynced...) {
+		klog.Error("Cannot sync caches")
+		return
+	}
+	klog.InfoS("Spark Application sync finished")
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// appAdded : reacts to a SparkApplication creation or update
+func (ctrl *SparkApplicationController) appAdded(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ctrl.appQueue.Add(key)
+}
+
+func (ctrl *SparkApplicationController) worker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem : deals with one key off the queue.  It returns false when it's time to quit.
+func (ctrl *SparkApplicationController) processNextWorkItem() bool {
+	keyObj, quit := ctrl.appQueue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.appQueue.Done(keyObj)
+
+	key, ok := keyObj.(string)
+	if !ok {
+		ctrl.appQueue.Forget(keyObj)
+		runtime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", keyObj))
+		return true
+	}
+	if err := ctrl.syncHandler(key); err != nil {
+		runtime.HandleError(err)
+		klog.ErrorS(err, "Error syncing spark application", "sparkApplication", key)
+		ctrl.appQueue.AddRateLimited(key)
+		return true
+	}
+	ctrl.appQueue.Forget(keyObj)
+	return true
+}
+
+// syncHandler : creates or updates the AppGroup and the PodGroup of a SparkApplication, then labels its driver
+// and executor pod templates with them. SparkApplications whose driver already belongs to another AppGroup are
+// left to their users, as are the AppGroups and PodGroups of the same name not controlled by the SparkApplication.
+// Both CRs are owned by the SparkApplication, hence garbage collected with it
+func (ctrl *SparkApplicationController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+	obj, err := ctrl.appLister.ByNamespace(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		klog.V(5).InfoS("SparkApplication has been deleted", "sparkApplication", key)
+		return nil
+	}
+	if err != nil {
+		klog.V(3).ErrorS(err, "Unable to retrieve spark application from store", "sparkApplication", key)
+		return err
+	}
+	app, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object %#v", obj))
+		return nil
+	}
+	driverLabels, _, _ := unstructured.NestedStringMap(app.Object, "spec", "driver", "labels")
+	if ag, ok := driverLabels[v1alpha1.AppGroupLabel]; ok && ag != app.GetName() {
+		klog.V(5).InfoS("Skipping SparkApplication already member of an AppGroup", "sparkApplication", key, "appGroup", ag)
+		return nil
+	}
+
+	owner := *metav1.NewControllerRef(app, SparkApplicationsResource.GroupVersion().WithKind(sparkApplicationKind))
+	if err := ctrl.syncAppGroup(app, owner); err != nil {
+		return err
+	}
+	if err := ctrl.syncPodGroup(app, owner); err != nil {
+		return err
+	}
+	return ctrl.labelPodTemplates(app)
+}
+
+// syncAppGroup : creates or updates the AppGroup of a SparkApplication
+func (ctrl *SparkApplicationController) syncAppGroup(app *unstructured.Unstructured, owner metav1.OwnerReference) error {
+	spec := sparkAppGroupSpec(app)
+	ag, err := ctrl.agLister.AppGroups(app.GetNamespace()).Get(app.GetName())
+	if apierrs.IsNotFound(err) {
+		ag = &v1alpha1.AppGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: app.GetName(), Namespace: app.GetNamespace(), OwnerReferences: []metav1.OwnerReference{owner}},
+			Spec:       spec,
+		}
+		klog.V(4).InfoS("Creating the AppGroup of the SparkApplication", "sparkApplication", klog.KObj(app))
+		_, err = ctrl.schedClient.SchedulingV1alpha1().AppGroups(ag.Namespace).Create(context.TODO(), ag, metav1.CreateOptions{})
+		if apierrs.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(ag, app) {
+		klog.V(5).InfoS("Skipping AppGroup not controlled by the SparkApplication", "sparkApplication", klog.KObj(app), "appGroup", klog.KObj(ag))
+		return nil
+	}
+	if apiequality.Semantic.DeepEqual(ag.Spec, spec) {
+		return nil
+	}
+	newAG := ag.DeepCopy()
+	newAG.Spec = spec
+	patch, err := util.CreateMergePatch(ag, newAG)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Updating the AppGroup of the SparkApplication", "sparkApplication", klog.KObj(app))
+	_, err = ctrl.schedClient.SchedulingV1alpha1().AppGroups(ag.Namespace).Patch(context.TODO(), ag.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// syncPodGroup : creates or updates the PodGroup of the executors of a SparkApplication
+func (ctrl *SparkApplicationController) syncPodGroup(app *unstructured.Unstructured, owner metav1.OwnerReference) error {
+	spec := v1alpha1.PodGroupSpec{MinMember: sparkExecutorInstances(app)}
+	pg, err := ctrl.pgLister.PodGroups(app.GetNamespace()).Get(sparkExecutorsName(app))
+	if apierrs.IsNotFound(err) {
+		pg = &v1alpha1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: sparkExecutorsName(app), Namespace: app.GetNamespace(), OwnerReferences: []metav1.OwnerReference{owner}},
+			Spec:       spec,
+		}
+		klog.V(4).InfoS("Creating the PodGroup of the executors of the SparkApplication", "sparkApplication", klog.KObj(app), "minMember", spec.MinMember)
+		_, err = ctrl.schedClient.SchedulingV1alpha1().PodGroups(pg.Namespace).Create(context.TODO(), pg, metav1.CreateOptions{})
+		if apierrs.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(pg, app) || pg.Spec.MinMember == spec.MinMember {
+		return nil
+	}
+	newPG := pg.DeepCopy()
+	newPG.Spec.MinMember = spec.MinMember
+	patch, err := util.CreateMergePatch(pg, newPG)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Updating the PodGroup of the executors of the SparkApplication", "sparkApplication", klog.KObj(app), "minMember", spec.MinMember)
+	_, err = ctrl.schedClient.SchedulingV1alpha1().PodGroups(pg.Namespace).Patch(context.TODO(), pg.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// labelPodTemplates : adds the AppGroup membership labels to the driver and executor pod templates of a
+// SparkApplication, and the PodGroup label to its executor one, the spark-operator copying them to the pods
+func (ctrl *SparkApplicationController) labelPodTemplates(app *unstructured.Unstructured) error {
+	wanted := map[string]map[string]string{
+		"driver": {
+			v1alpha1.AppGroupLabel:         app.GetName(),
+			v1alpha1.AppGroupSelectorLabel: sparkDriverName(app),
+		},
+		"executor": {
+			v1alpha1.AppGroupLabel:         app.GetName(),
+			v1alpha1.AppGroupSelectorLabel: sparkExecutorsName(app),
+			v1alpha1.PodGroupLabel:         sparkExecutorsName(app),
+		},
+	}
+	missing := make(map[string]interface{})
+	for role, labels := range wanted {
+		current, _, _ := unstructured.NestedStringMap(app.Object, "spec", role, "labels")
+		added := make(map[string]string)
+		for k, v := range labels {
+			if _, ok := current[k]; !ok {
+				added[k] = v
+			}
+		}
+		if len(added) != 0 {
+			missing[role] = map[string]interface{}{"labels": added}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": missing})
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Labeling the pod templates of the SparkApplication", "sparkApplication", klog.KObj(app))
+	_, err = ctrl.dynamicClient.Resource(SparkApplicationsResource).Namespace(app.GetNamespace()).Patch(context.TODO(), app.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// sparkAppGroupSpec : returns the AppGroup of a SparkApplication, made of its driver and of its executors
+// depending on the driver
+func sparkAppGroupSpec(app *unstructured.Unstructured) v1alpha1.AppGroupSpec {
+	driver := v1alpha1.AppGroupWorkloadInfo{
+		Kind:       sparkApplicationKind,
+		APIVersion: SparkApplicationsResource.GroupVersion().String(),
+		Name:       sparkDriverName(app),
+		Selector:   sparkDriverName(app),
+	}
+	executors := v1alpha1.AppGroupWorkloadInfo{
+		Kind:       sparkApplicationKind,
+		APIVersion: SparkApplicationsResource.GroupVersion().String(),
+		Name:       sparkExecutorsName(app),
+		Selector:   sparkExecutorsName(app),
+	}
+	return v1alpha1.AppGroupSpec{
+		NumMembers: 1 + sparkExecutorInstances(app),
+		Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: driver},
+			{Workload: executors, Dependencies: v1alpha1.DependenciesList{{Workload: driver}}},
+		},
+	}
+}
+
+// sparkExecutorInstances : returns the number of executors of a SparkApplication, 1 if not specified as
+// by the spark-operator
+func sparkExecutorInstances(app *unstructured.Unstructured) int32 {
+	instances, found, err := unstructured.NestedInt64(app.Object, "spec", "executor", "instances")
+	if err != nil || !found || instances < 1 {
+		return 1
+	}
+	return int32(instances)
+}
+
+// sparkDriverName : returns the name of the driver workload of a SparkApplication
+func sparkDriverName(app *unstructured.Unstructured) string {
+	return app.GetName() + "-driver"
+}
+
+// sparkExecutorsName : returns the name of the executors workload, and of their PodGroup, of a SparkApplication
+func sparkExecutorsName(app *unstructured.Unstructured) string {
+	return app.GetName() + "-executors"
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func TestSparkApplicationController_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	makeApp := func(name string, executors int64, driverLabels map[string]interface{}) *unstructured.Unstructured {
+		app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		app.SetAPIVersion("sparkoperator.k8s.io/v1beta2")
+		app.SetKind("SparkApplication")
+		app.SetNamespace("default")
+		app.SetName(name)
+		app.SetUID(types.UID("uid-" + name))
+		if err := unstructured.SetNestedField(app.Object, executors, "spec", "executor", "instances"); err != nil {
+			t.Fatal(err)
+		}
+		if driverLabels != nil {
+			if err := unstructured.SetNestedMap(app.Object, driverLabels, "spec", "driver", "labels"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return app
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		SparkApplicationsResource: "SparkApplicationList",
+	},
+		makeApp("pi", 3, map[string]interface{}{"version": "3.1.1"}),
+		makeApp("manual", 2, map[string]interface{}{v1alpha1.AppGroupLabel: "user-defined"}),
+	)
+	schedClient := schedfake.NewSimpleClientset()
+
+	schedInformerFactory := schedinformer.NewSharedInformerFactory(schedClient, controller.NoResyncPeriodFunc())
+	appInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, controller.NoResyncPeriodFunc())
+	ctrl := NewSparkApplicationController(schedClient, dynamicClient, schedInformerFactory.Scheduling().V1alpha1().AppGroups(),
+		schedInformerFactory.Scheduling().V1alpha1().PodGroups(), appInformerFactory)
+	schedInformerFactory.Start(ctx.Done())
+	appInformerFactory.Start(ctx.Done())
+	go ctrl.Run(1, ctx.Done())
+
+	err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
+		app, err := dynamicClient.Resource(SparkApplicationsResource).Namespace("default").Get(ctx, "pi", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		executorLabels, _, _ := unstructured.NestedStringMap(app.Object, "spec", "executor", "labels")
+		return executorLabels[v1alpha1.PodGroupLabel] == "pi-executors", nil
+	})
+	if err != nil {
+		t.Fatalf("SparkApplication pod templates not labeled: %v", err)
+	}
+
+	app, err := dynamicClient.Resource(SparkApplicationsResource).Namespace("default").Get(ctx, "pi", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	driverLabels, _, _ := unstructured.NestedStringMap(app.Object, "spec", "driver", "labels")
+	for k, v := range map[string]string{"version": "3.1.1", v1alpha1.AppGroupLabel: "pi", v1alpha1.AppGroupSelectorLabel: "pi-driver"} {
+		if driverLabels[k] != v {
+			t.Errorf("expected driver label %v=%v, got %v", k, v, driverLabels)
+		}
+	}
+	executorLabels, _, _ := unstructured.NestedStringMap(app.Object, "spec", "executor", "labels")
+	if executorLabels[v1alpha1.AppGroupLabel] != "pi" || executorLabels[v1alpha1.AppGroupSelectorLabel] != "pi-executors" {
+		t.Errorf("expected executors labeled with their AppGroup workload, got %v", executorLabels)
+	}
+
+	ag, err := schedClient.SchedulingV1alpha1().AppGroups("default").Get(ctx, "pi", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(ag, app) {
+		t.Errorf("expected AppGroup controlled by the SparkApplication, got owners %v", ag.OwnerReferences)
+	}
+	if ag.Spec.NumMembers != 4 || len(ag.Spec.Workloads) != 2 {
+		t.Fatalf("expected 4 members in 2 workloads, got %+v", ag.Spec)
+	}
+	executors := ag.Spec.Workloads[1]
+	if executors.Workload.Selector != "pi-executors" || len(executors.Dependencies) != 1 || executors.Dependencies[0].Workload.Selector != "pi-driver" {
+		t.Errorf("expected executors depending on the driver, got %+v", executors)
+	}
+	pg, err := schedClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, "pi-executors", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pg.Spec.MinMember != 3 {
+		t.Errorf("expected a PodGroup of 3 executors, got %v", pg.Spec.MinMember)
+	}
+
+	if _, err := schedClient.SchedulingV1alpha1().AppGroups("default").Get(ctx, "manual", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no AppGroup for a SparkApplication already member of another one")
+	}
+}
+
+func TestSparkExecutorInstances(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if got := sparkExecutorInstances(app); got != 1 {
+		t.Errorf("expected 1 executor when not specified, got %v", got)
+	}
+	if err := unstructured.SetNestedField(app.Object, int64(8), "spec", "executor", "instances"); err != nil {
+		t.Fatal(err)
+	}
+	if got := sparkExecutorInstances(app); got != 8 {
+		t.Errorf("expected 8 executors, got %v", got)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// TopologyBootstrapOptions configures the creation of an initial NetworkTopology with default costs between
+// the zones and regions of the nodes, for the plugins to behave sensibly before any cost is measured.
+type TopologyBootstrapOptions struct {
+	// NetworkTopology is the namespace/name of the NetworkTopology to create. Bootstrap is disabled if empty.
+	NetworkTopology string
+	// Options hold the name of the weights and the default costs. The CrossRegionCost is also the default
+	// cost of the zones and regions added to the cluster later on.
+	weights.Options
+	// NodeFilter leaves the zones and regions of the NotReady, cordoned, tainted or unselected nodes out of the costs.
+	NodeFilter util.NodeFilter
+}
+
+// BootstrapNetworkTopology : creates the NetworkTopology of options with default costs between the zones and
+// regions of the nodes, unless it exists already, so that measured or user defined costs are never overwritten
+func BootstrapNetworkTopology(ctx context.Context, kubeClient kubernetes.Interface, schedClient schedclientset.Interface,
+	options TopologyBootstrapOptions) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(options.NetworkTopology)
+	if err != nil || namespace == "" {
+		return fmt.Errorf("invalid NetworkTopology %q, expected namespace/name", options.NetworkTopology)
+	}
+	if options.SameZoneCost < 0 || options.CrossZoneCost < 0 || options.CrossRegionCost < 0 {
+		return fmt.Errorf("negative default network cost")
+	}
+
+	_, err = schedClient.SchedulingV1alpha1().NetworkTopologies(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		klog.V(4).InfoS("NetworkTopology exists, skipping bootstrap", "networkTopology", options.NetworkTopology)
+		return nil
+	}
+	if !apierrs.IsNotFound(err) {
+		return err
+	}
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	nt := defaultNetworkTopology(namespace, name, nodes.Items, options)
+	klog.InfoS("Bootstrapping NetworkTopology with default costs", "networkTopology", options.NetworkTopology,
+		"weights", options.WeightsName)
+	_, err = schedClient.SchedulingV1alpha1().NetworkTopologies(namespace).Create(ctx, nt, metav1.CreateOptions{})
+	if apierrs.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// defaultNetworkTopology : returns a NetworkTopology with the default costs between the zones and regions of the
+// nodes selected by the node filter of options
+func defaultNetworkTopology(namespace, name string, nodes []v1.Node, options TopologyBootstrapOptions) *v1alpha1.NetworkTopology {
+	defaultCost := options.CrossRegionCost
+	return &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights:     weights.ComputeWeights(options.NodeFilter.Filter(nodes), nil, options.Options),
+			DefaultCost: &defaultCost,
+		},
+	}
+}
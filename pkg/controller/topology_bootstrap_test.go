@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/weights"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+func TestBootstrapNetworkTopology(t *testing.T) {
+	ctx := context.TODO()
+	options := TopologyBootstrapOptions{
+		NetworkTopology: "default/nt-test",
+		Options: weights.Options{
+			WeightsName:     "UserDefined",
+			SameZoneCost:    1,
+			CrossZoneCost:   5,
+			CrossRegionCost: 50,
+		},
+	}
+	node := func(name, region, zone string) *v1.Node {
+		return st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, region).Label(v1.LabelTopologyZone, zone).Obj()
+	}
+	kubeClient := fake.NewSimpleClientset(
+		node("n1", "us-east", "us-east-1a"),
+		node("n2", "us-east", "us-east-1b"),
+		node("n3", "us-east", "us-east-1b"),
+		node("n4", "eu-west", "eu-west-1a"),
+		st.MakeNode().Name("n5").Obj(),
+	)
+
+	cost := func(destination string, cost int64) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: cost}
+	}
+	defaultCost := int64(50)
+	expected := v1alpha1.NetworkTopologySpec{
+		Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+			{TopologyKey: v1alpha1.NetworkTopologyRegion, OriginList: v1alpha1.OriginList{
+				{Origin: "eu-west", CostList: v1alpha1.CostList{cost("us-east", 50)}},
+				{Origin: "us-east", CostList: v1alpha1.CostList{cost("eu-west", 50)}},
+			}},
+			{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+				{Origin: "eu-west-1a", CostList: v1alpha1.CostList{cost("eu-west-1a", 1), cost("us-east-1a", 50), cost("us-east-1b", 50)}},
+				{Origin: "us-east-1a", CostList: v1alpha1.CostList{cost("eu-west-1a", 50), cost("us-east-1a", 1), cost("us-east-1b", 5)}},
+				{Origin: "us-east-1b", CostList: v1alpha1.CostList{cost("eu-west-1a", 50), cost("us-east-1a", 5), cost("us-east-1b", 1)}},
+			}},
+		}}},
+		DefaultCost: &defaultCost,
+	}
+
+	schedClient := schedfake.NewSimpleClientset()
+	if err := BootstrapNetworkTopology(ctx, kubeClient, schedClient, options); err != nil {
+		t.Fatal(err)
+	}
+	got, err := schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt-test", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Spec, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got.Spec)
+	}
+
+	existing := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec:       v1alpha1.NetworkTopologySpec{Weights: v1alpha1.WeightList{{Name: "Measured"}}},
+	}
+	schedClient = schedfake.NewSimpleClientset(existing)
+	if err := BootstrapNetworkTopology(ctx, kubeClient, schedClient, options); err != nil {
+		t.Fatal(err)
+	}
+	got, err = schedClient.SchedulingV1alpha1().NetworkTopologies("default").Get(ctx, "nt-test", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Spec, existing.Spec) {
+		t.Errorf("Expected the existing NetworkTopology to be kept, got %+v", got.Spec)
+	}
+
+	// The zones and regions of the ignored or unselected nodes only are left out.
+	notReady := node("n6", "ap-south", "ap-south-1a")
+	notReady.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}
+	cordoned := node("n7", "us-east", "us-east-1c")
+	cordoned.Spec.Unschedulable = true
+	controlPlane := node("n8", "us-west", "us-west-1a")
+	controlPlane.Labels["node-role.kubernetes.io/control-plane"] = ""
+	workers, err := labels.Parse("!node-role.kubernetes.io/control-plane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := options
+	filtered.NodeFilter = util.NodeFilter{IgnoreNotReady: true, IgnoreUnschedulable: true, Selector: workers}
+	nodes := []v1.Node{*node("n1", "us-east", "us-east-1a"), *notReady, *cordoned, *controlPlane}
+	for i := range nodes {
+		if nodes[i].Status.Conditions == nil {
+			nodes[i].Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+		}
+	}
+	nt := defaultNetworkTopology("default", "nt-test", nodes, filtered)
+	expectedZones := v1alpha1.TopologyList{{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+		{Origin: "us-east-1a", CostList: v1alpha1.CostList{cost("us-east-1a", 1)}},
+	}}}
+	if !reflect.DeepEqual(nt.Spec.Weights[0].TopologyList, expectedZones) {
+		t.Errorf("Expected %+v, got %+v", expectedZones, nt.Spec.Weights[0].TopologyList)
+	}
+
+	options.NetworkTopology = "nt-test"
+	if err := BootstrapNetworkTopology(ctx, kubeClient, schedClient, options); err == nil {
+		t.Errorf("Expected an error for a NetworkTopology without namespace")
+	}
+}
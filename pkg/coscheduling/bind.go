@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// defaultBindParallelism is the parallelism of the gang binder given a non-positive one, the
+// default of the versioned args.
+const defaultBindParallelism = 16
+
+// bindFunc binds a pod to a node.
+type bindFunc func(ctx context.Context, pod *v1.Pod, nodeName string) error
+
+// bindRequest is a gang member waiting to be bound.
+type bindRequest struct {
+	pod      *v1.Pod
+	nodeName string
+}
+
+// bindBatch gathers the members of a permitted PodGroup so that they get bound together.
+type bindBatch struct {
+	// expected holds the UIDs of the members allowed by Permit.
+	expected sets.String
+	// requests holds the members which have reached the Bind extension point.
+	requests map[types.UID]*bindRequest
+	// errs holds the binding result of the members, filled in before done is closed, by the
+	// completing member once completing is set.
+	errs map[types.UID]error
+	// aborted is set once the batch has failed, so that late members are rejected too.
+	aborted bool
+	// completing is set once the last member reached Bind: the batch is being bound by it and
+	// can't be aborted anymore.
+	completing bool
+	// unreserved holds the UIDs of the expected members unreserved, the aborted batch being
+	// dropped once all of them are.
+	unreserved sets.String
+	done       chan struct{}
+}
+
+// gangBinder binds the members of a permitted PodGroup concurrently, once all of them
+// have reached the Bind extension point.
+type gangBinder struct {
+	sync.Mutex
+	parallelism int
+	bind        bindFunc
+	// batches is keyed by the PodGroup full name.
+	batches map[string]*bindBatch
+}
+
+func newGangBinder(parallelism int, bind bindFunc) *gangBinder {
+	if parallelism <= 0 {
+		parallelism = defaultBindParallelism
+	}
+	return &gangBinder{
+		parallelism: parallelism,
+		bind:        bind,
+		batches:     make(map[string]*bindBatch),
+	}
+}
+
+// expect registers the members of a PodGroup allowed by Permit, replacing any former batch.
+func (gb *gangBinder) expect(pgFullName string, uids sets.String) {
+	gb.Lock()
+	defer gb.Unlock()
+	gb.batches[pgFullName] = &bindBatch{
+		expected:   uids,
+		requests:   make(map[types.UID]*bindRequest),
+		errs:       make(map[types.UID]error),
+		unreserved: sets.NewString(),
+		done:       make(chan struct{}),
+	}
+}
+
+// abort fails the pending batch of a PodGroup, if any, as pod gets unreserved. The aborted batch
+// is kept so that the members reaching Bind later fail as well, until all of them are unreserved.
+func (gb *gangBinder) abort(pgFullName string, pod *v1.Pod, reason error) {
+	gb.Lock()
+	defer gb.Unlock()
+	batch, ok := gb.batches[pgFullName]
+	if !ok {
+		return
+	}
+	gb.abortLocked(batch, reason)
+	if batch.expected.Has(string(pod.UID)) {
+		batch.unreserved.Insert(string(pod.UID))
+	}
+	if batch.unreserved.Len() == batch.expected.Len() {
+		delete(gb.batches, pgFullName)
+	}
+}
+
+// drop fails and forgets the pending batch of a PodGroup, if any, e.g. once the PodGroup is deleted.
+func (gb *gangBinder) drop(pgFullName string, reason error) {
+	gb.Lock()
+	defer gb.Unlock()
+	if batch, ok := gb.batches[pgFullName]; ok {
+		gb.abortLocked(batch, reason)
+		delete(gb.batches, pgFullName)
+	}
+}
+
+func (gb *gangBinder) abortLocked(batch *bindBatch, reason error) {
+	if batch.aborted || batch.completing {
+		return
+	}
+	batch.aborted = true
+	for uid := range batch.requests {
+		batch.errs[uid] = reason
+	}
+	close(batch.done)
+}
+
+// bindPod adds pod to the batch of its PodGroup and returns its binding result. The last
+// member reaching this point binds the whole batch, the other ones wait for it at most
+// timeout. handled is false when pod is not part of any batch, leaving the binding to
+// other Bind plugins.
+func (gb *gangBinder) bindPod(ctx context.Context, pgFullName string, pod *v1.Pod, nodeName string, timeout time.Duration) (handled bool, err error) {
+	gb.Lock()
+	batch, ok := gb.batches[pgFullName]
+	if !ok || !batch.expected.Has(string(pod.UID)) {
+		gb.Unlock()
+		return false, nil
+	}
+	if batch.aborted {
+		gb.Unlock()
+		return true, fmt.Errorf("binding of PodGroup %v has been aborted", pgFullName)
+	}
+	batch.requests[pod.UID] = &bindRequest{pod: pod, nodeName: nodeName}
+	if len(batch.requests) == batch.expected.Len() {
+		// The batch is complete: it can't be aborted anymore.
+		batch.completing = true
+		delete(gb.batches, pgFullName)
+		gb.Unlock()
+		gb.bindAll(ctx, pgFullName, batch)
+		close(batch.done)
+		return true, batch.errs[pod.UID]
+	}
+	gb.Unlock()
+
+	var reason error
+	select {
+	case <-batch.done:
+		return true, batch.errs[pod.UID]
+	case <-ctx.Done():
+		reason = ctx.Err()
+	case <-time.After(timeout):
+		reason = fmt.Errorf("timed out waiting for the members of PodGroup %v to be bound", pgFullName)
+	}
+	gb.Lock()
+	if batch.completing {
+		// The last member is binding the batch: its result is awaited, whatever the timeout.
+		gb.Unlock()
+		<-batch.done
+		return true, batch.errs[pod.UID]
+	}
+	defer gb.Unlock()
+	select {
+	case <-batch.done:
+		// The batch got aborted in the meantime.
+		return true, batch.errs[pod.UID]
+	default:
+	}
+	// The aborted batch is kept so that the members reaching Bind later fail as well.
+	gb.abortLocked(batch, reason)
+	return true, reason
+}
+
+// bindAll binds the members of batch with bounded parallelism. On the first failure,
+// the bindings not issued yet are given up so that the whole PodGroup gets unreserved.
+func (gb *gangBinder) bindAll(ctx context.Context, pgFullName string, batch *bindBatch) {
+	requests := make([]*bindRequest, 0, len(batch.requests))
+	for _, r := range batch.requests {
+		requests = append(requests, r)
+	}
+	errs := make([]error, len(requests))
+	bound := make([]bool, len(requests))
+
+	bindCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workqueue.ParallelizeUntil(bindCtx, gb.parallelism, len(requests), func(i int) {
+		r := requests[i]
+		if err := gb.bind(bindCtx, r.pod, r.nodeName); err != nil {
+			klog.ErrorS(err, "Failed to bind gang member", "pod", klog.KObj(r.pod), "node", r.nodeName)
+			errs[i] = err
+			cancel()
+			return
+		}
+		bound[i] = true
+	})
+
+	for i, r := range requests {
+		switch {
+		case errs[i] != nil:
+			batch.errs[r.pod.UID] = errs[i]
+		case !bound[i]:
+			batch.errs[r.pod.UID] = fmt.Errorf("binding of PodGroup %v has been aborted", pgFullName)
+		}
+	}
+}
+
+// bindPodToNode binds pod to nodeName the same way as the DefaultBinder.
+func (cs *Coscheduling) bindPodToNode(ctx context.Context, pod *v1.Pod, nodeName string) error {
+	klog.V(3).InfoS("Attempting to bind pod to node", "pod", klog.KObj(pod), "node", nodeName)
+	binding := &v1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID},
+		Target:     v1.ObjectReference{Kind: "Node", Name: nodeName},
+	}
+	return cs.frameworkHandler.ClientSet().CoreV1().Pods(binding.Namespace).Bind(ctx, binding, metav1.CreateOptions{})
+}
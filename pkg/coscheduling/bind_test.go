@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestGangBinder(t *testing.T) {
+	const pgFullName = "ns1/pg1"
+	makePods := func(n int) []*v1.Pod {
+		var pods []*v1.Pod
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("pod%d", i)
+			pods = append(pods, st.MakePod().Namespace("ns1").Name(name).UID(name).Label(v1alpha1.PodGroupLabel, "pg1").Obj())
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name string
+		// failFirst fails the first binding issued.
+		failFirst bool
+		// abort aborts the batch before the last member reaches Bind.
+		abort        bool
+		expectBound  int
+		expectFailed int
+	}{
+		{
+			name:        "all members get bound",
+			expectBound: 4,
+		},
+		{
+			name:         "a failed binding fails the members not bound",
+			failFirst:    true,
+			expectFailed: 4,
+		},
+		{
+			name:         "aborted batch fails all members",
+			abort:        true,
+			expectFailed: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods := makePods(4)
+			var lock sync.Mutex
+			var boundPods []string
+			bind := func(ctx context.Context, pod *v1.Pod, nodeName string) error {
+				lock.Lock()
+				defer lock.Unlock()
+				if tt.failFirst && len(boundPods) == 0 {
+					tt.failFirst = false
+					return fmt.Errorf("failed to bind %v", pod.Name)
+				}
+				boundPods = append(boundPods, pod.Name)
+				return nil
+			}
+			// A parallelism of 1 makes the bindings sequential, so that no binding is
+			// issued after a failure.
+			gb := newGangBinder(1, bind)
+			uids := sets.NewString()
+			for _, pod := range pods {
+				uids.Insert(string(pod.UID))
+			}
+			gb.expect(pgFullName, uids)
+
+			handled, _ := gb.bindPod(context.Background(), pgFullName, st.MakePod().Namespace("ns1").Name("other").UID("other").Obj(), "node", time.Second)
+			if handled {
+				t.Errorf("expected a pod not permitted with the gang to be skipped")
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(pods))
+			for i := range pods[:len(pods)-1] {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = gb.bindPod(context.Background(), pgFullName, pods[i], "node", time.Minute)
+				}(i)
+			}
+			// Wait for the first members to reach Bind.
+			if err := wait(func() bool {
+				gb.Lock()
+				defer gb.Unlock()
+				return len(gb.batches[pgFullName].requests) == len(pods)-1
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.abort {
+				gb.abort(pgFullName, pods[0], fmt.Errorf("unreserved"))
+			}
+			last := len(pods) - 1
+			_, errs[last] = gb.bindPod(context.Background(), pgFullName, pods[last], "node", time.Minute)
+			wg.Wait()
+
+			var failed int
+			for _, err := range errs {
+				if err != nil {
+					failed++
+				}
+			}
+			if len(boundPods) != tt.expectBound {
+				t.Errorf("expected %v pods bound, got %v", tt.expectBound, boundPods)
+			}
+			if failed != tt.expectFailed {
+				t.Errorf("expected %v pods failed, got %v: %v", tt.expectFailed, failed, errs)
+			}
+		})
+	}
+}
+
+func TestGangBinderTimeout(t *testing.T) {
+	const pgFullName = "ns1/pg1"
+	pod1 := st.MakePod().Namespace("ns1").Name("pod1").UID("pod1").Obj()
+	pod2 := st.MakePod().Namespace("ns1").Name("pod2").UID("pod2").Obj()
+	gb := newGangBinder(16, func(ctx context.Context, pod *v1.Pod, nodeName string) error {
+		t.Errorf("unexpected binding of %v", pod.Name)
+		return nil
+	})
+	gb.expect(pgFullName, sets.NewString("pod1", "pod2"))
+
+	if _, err := gb.bindPod(context.Background(), pgFullName, pod1, "node", 10*time.Millisecond); err == nil {
+		t.Errorf("expected pod1 to time out waiting for pod2")
+	}
+	// pod2 reaching Bind late must not get bound on its own.
+	if handled, err := gb.bindPod(context.Background(), pgFullName, pod2, "node", time.Second); !handled || err == nil {
+		t.Errorf("expected pod2 to fail, got handled %v, err %v", handled, err)
+	}
+}
+
+func TestGangBinderTimeoutWhileBinding(t *testing.T) {
+	const pgFullName = "ns1/pg1"
+	pod1 := st.MakePod().Namespace("ns1").Name("pod1").UID("pod1").Obj()
+	pod2 := st.MakePod().Namespace("ns1").Name("pod2").UID("pod2").Obj()
+	gb := newGangBinder(16, func(ctx context.Context, pod *v1.Pod, nodeName string) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	gb.expect(pgFullName, sets.NewString("pod1", "pod2"))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := gb.bindPod(context.Background(), pgFullName, pod1, "node", 100*time.Millisecond)
+		errCh <- err
+	}()
+	if err := wait(func() bool {
+		gb.Lock()
+		defer gb.Unlock()
+		return len(gb.batches[pgFullName].requests) == 1
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// pod1 times out while pod2 binds the batch: it gets the result of the binding instead of aborting it.
+	if _, err := gb.bindPod(context.Background(), pgFullName, pod2, "node", time.Second); err != nil {
+		t.Errorf("expected pod2 to be bound, got %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Errorf("expected pod1 to be bound, got %v", err)
+	}
+}
+
+func TestGangBinderRelease(t *testing.T) {
+	const pgFullName = "ns1/pg1"
+	pod1 := st.MakePod().Namespace("ns1").Name("pod1").UID("pod1").Obj()
+	pod2 := st.MakePod().Namespace("ns1").Name("pod2").UID("pod2").Obj()
+	other := st.MakePod().Namespace("ns1").Name("other").UID("other").Obj()
+	gb := newGangBinder(0, func(ctx context.Context, pod *v1.Pod, nodeName string) error {
+		t.Errorf("unexpected binding of %v", pod.Name)
+		return nil
+	})
+	if gb.parallelism != defaultBindParallelism {
+		t.Errorf("expected a non-positive parallelism to default to %v, got %v", defaultBindParallelism, gb.parallelism)
+	}
+
+	// The aborted batch is dropped once all its members are unreserved.
+	gb.expect(pgFullName, sets.NewString("pod1", "pod2"))
+	gb.abort(pgFullName, pod1, fmt.Errorf("unreserved"))
+	gb.abort(pgFullName, other, fmt.Errorf("unreserved"))
+	if _, ok := gb.batches[pgFullName]; !ok {
+		t.Fatalf("expected the batch to be kept while pod2 is not unreserved")
+	}
+	if handled, err := gb.bindPod(context.Background(), pgFullName, pod2, "node", time.Second); !handled || err == nil {
+		t.Errorf("expected pod2 to fail, got handled %v, err %v", handled, err)
+	}
+	gb.abort(pgFullName, pod2, fmt.Errorf("unreserved"))
+	if _, ok := gb.batches[pgFullName]; ok {
+		t.Errorf("expected the batch to be dropped once all its members are unreserved")
+	}
+
+	// The batch of a deleted PodGroup is dropped.
+	gb.expect(pgFullName, sets.NewString("pod1", "pod2"))
+	gb.drop(pgFullName, fmt.Errorf("deleted"))
+	if _, ok := gb.batches[pgFullName]; ok {
+		t.Errorf("expected the batch of the deleted PodGroup to be dropped")
+	}
+}
+
+func wait(cond func() bool) error {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("condition not met in time")
+}
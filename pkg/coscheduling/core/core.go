@@ -19,17 +19,20 @@ package core
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
-	gochache "github.com/patrickmn/go-cache"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	informerv1 "k8s.io/client-go/informers/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -40,6 +43,10 @@ import (
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
 
+// maxPodGroupCacheSize bounds the number of PodGroups remembered as denied or permitted. Past it,
+// the least recently used ones are forgotten, i.e. they are checked again at PreFilter.
+const maxPodGroupCacheSize = 10000
+
 type Status string
 
 const (
@@ -58,10 +65,19 @@ type Manager interface {
 	Permit(context.Context, *corev1.Pod) Status
 	PostBind(context.Context, *corev1.Pod, string)
 	GetPodGroup(*corev1.Pod) (string, *v1alpha1.PodGroup)
+	GetPodGroupFullName(*corev1.Pod) string
+	GetWaitTimeDuration(*v1alpha1.PodGroup) time.Duration
+	GetGangTimeoutPolicy(string) v1alpha1.GangTimeoutPolicy
 	GetCreationTimestamp(*corev1.Pod, time.Time) time.Time
 	AddDeniedPodGroup(string)
 	DeletePermittedPodGroup(string)
+	RejectPodGroup(string, *v1alpha1.PodGroup) bool
+	GetPermittedPodGroups() []string
+	GetDeniedPodGroups() []string
 	CalculateAssignedPods(string, string) int
+	GetMemberDomains(*corev1.Pod, string) sets.String
+	GetPendingMembers(*corev1.Pod) ([]*corev1.Pod, error)
+	RelaxAntiAffinity(*v1alpha1.PodGroup, string)
 	ActivateSiblings(pod *corev1.Pod, state *framework.CycleState)
 }
 
@@ -76,15 +92,17 @@ type PodGroupManager struct {
 	scheduleTimeout *time.Duration
 	// lastDeniedPG stores the pg name if a pod can not pass pre-filer,
 	// or anyone of the pod timeout
-	lastDeniedPG *gochache.Cache
+	lastDeniedPG *util.BoundedCache
 	// permittedPG stores the pg name which has passed the pre resource check.
-	permittedPG *gochache.Cache
+	permittedPG *util.BoundedCache
 	// deniedCacheExpirationTime is the expiration time that a podGroup remains in lastDeniedPG store.
 	lastDeniedPGExpirationTime *time.Duration
 	// pgLister is podgroup lister
 	pgLister pglister.PodGroupLister
 	// podLister is pod lister
 	podLister listerv1.PodLister
+	// policyLister is the CoschedulingPolicy lister
+	policyLister pglister.CoschedulingPolicyLister
 	// reserveResourcePercentage is the reserved resource for the max finished group, range (0,100]
 	reserveResourcePercentage int32
 	sync.RWMutex
@@ -92,7 +110,7 @@ type PodGroupManager struct {
 
 // NewPodGroupManager create a new operation object
 func NewPodGroupManager(pgClient pgclientset.Interface, snapshotSharedLister framework.SharedLister, scheduleTimeout, deniedPGExpirationTime *time.Duration,
-	pgInformer pginformer.PodGroupInformer, podInformer informerv1.PodInformer) *PodGroupManager {
+	pgInformer pginformer.PodGroupInformer, podInformer informerv1.PodInformer, policyInformer pginformer.CoschedulingPolicyInformer) *PodGroupManager {
 	pgMgr := &PodGroupManager{
 		pgClient:                   pgClient,
 		snapshotSharedLister:       snapshotSharedLister,
@@ -100,8 +118,9 @@ func NewPodGroupManager(pgClient pgclientset.Interface, snapshotSharedLister fra
 		lastDeniedPGExpirationTime: deniedPGExpirationTime,
 		pgLister:                   pgInformer.Lister(),
 		podLister:                  podInformer.Lister(),
-		lastDeniedPG:               gochache.New(3*time.Second, 3*time.Second),
-		permittedPG:                gochache.New(3*time.Second, 3*time.Second),
+		policyLister:               policyInformer.Lister(),
+		lastDeniedPG:               util.NewBoundedCache("coscheduling_denied_podgroups", maxPodGroupCacheSize, 3*time.Second),
+		permittedPG:                util.NewBoundedCache("coscheduling_permitted_podgroups", maxPodGroupCacheSize, 3*time.Second),
 	}
 	return pgMgr
 }
@@ -109,14 +128,12 @@ func NewPodGroupManager(pgClient pgclientset.Interface, snapshotSharedLister fra
 // ActivateSiblings stashes the pods belonging to the same PodGroup of the given pod
 // in the given state, with a reserved key "kubernetes.io/pods-to-activate".
 func (pgMgr *PodGroupManager) ActivateSiblings(pod *corev1.Pod, state *framework.CycleState) {
-	pgName := util.GetPodGroupLabel(pod)
+	pgName := pgMgr.podGroupName(pod)
 	if pgName == "" {
 		return
 	}
 
-	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
-		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
-	)
+	pods, err := pgMgr.listGroupPods(pod)
 	if err != nil {
 		klog.ErrorS(err, "Failed to obtain pods belong to a PodGroup", "podGroup", pgName)
 		return
@@ -143,7 +160,7 @@ func (pgMgr *PodGroupManager) ActivateSiblings(pod *corev1.Pod, state *framework
 }
 
 // PreFilter filters out a pod if it
-// 1. belongs to a podgroup that was recently denied or
+// 1. belongs to a podgroup that failed scheduling or was recently denied or
 // 2. the total number of pods in the podgroup is less than the minimum number of pods
 // that is required to be scheduled.
 func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) error {
@@ -152,12 +169,13 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 	if pg == nil {
 		return nil
 	}
+	if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) {
+		return fmt.Errorf("pod with pgName: %v failed scheduling %v times, deny", pgFullName, pg.Status.SchedulingFailures)
+	}
 	if _, ok := pgMgr.lastDeniedPG.Get(pgFullName); ok {
 		return fmt.Errorf("pod with pgName: %v last failed in 3s, deny", pgFullName)
 	}
-	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
-		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: util.GetPodGroupLabel(pod)}),
-	)
+	pods, err := pgMgr.listGroupPods(pod)
 	if err != nil {
 		return fmt.Errorf("podLister list pods failed: %v", err)
 	}
@@ -188,7 +206,7 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 	err = CheckClusterResource(nodes, minResources, pgFullName)
 	if err != nil {
 		klog.ErrorS(err, "Failed to PreFilter", "podGroup", klog.KObj(pg))
-		pgMgr.AddDeniedPodGroup(pgFullName)
+		pgMgr.RejectPodGroup(pgFullName, pg)
 		return err
 	}
 	pgMgr.permittedPG.Add(pgFullName, pgFullName, *pgMgr.scheduleTimeout)
@@ -205,18 +223,57 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, pod *corev1.Pod) Statu
 		// A Pod with a podGroup name but without a PodGroup found is denied.
 		return PodGroupNotFound
 	}
+	if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupFullyScheduled) {
+		// The gang already ran in full, a pod replacing a lost member does not wait for minMember again.
+		klog.V(4).InfoS("PodGroup was fully scheduled, permit replacement pod", "pod", klog.KObj(pod), "podGroup", klog.KObj(pg))
+		return Success
+	}
 
 	assigned := pgMgr.CalculateAssignedPods(pg.Name, pg.Namespace)
 	// The number of pods that have been assigned nodes is calculated from the snapshot.
 	// The current pod in not included in the snapshot during the current scheduling cycle.
 	if int32(assigned)+1 >= pg.Spec.MinMember {
+		pgMgr.recordPermitGranted(pg)
 		return Success
 	}
 	return Wait
 }
 
+// recordPermitGranted records the time the minMember pods of a PodGroup were permitted together,
+// unless recorded already.
+func (pgMgr *PodGroupManager) recordPermitGranted(pg *v1alpha1.PodGroup) {
+	pgMgr.Lock()
+	defer pgMgr.Unlock()
+	if pg.Status.Milestones != nil && pg.Status.Milestones.PermitGranted != nil {
+		return
+	}
+	err := pgMgr.updatePodGroup(pg.Namespace, pg.Name, func(pg *v1alpha1.PodGroup) bool {
+		if pg.Status.Milestones != nil && pg.Status.Milestones.PermitGranted != nil {
+			return false
+		}
+		now := metav1.Now()
+		milestones(pg).PermitGranted = &now
+		return true
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to update", "podGroup", klog.KObj(pg))
+	}
+}
+
+// milestones returns the milestones of a PodGroup, initializing them if needed.
+func milestones(pg *v1alpha1.PodGroup) *v1alpha1.PodGroupMilestones {
+	if pg.Status.Milestones == nil {
+		pg.Status.Milestones = &v1alpha1.PodGroupMilestones{}
+	}
+	return pg.Status.Milestones
+}
+
 // PostBind updates a PodGroup's status.
 func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nodeName string) {
+	// Pods auto-grouped by owner have no PodGroup to update.
+	if len(util.GetPodGroupLabel(pod)) == 0 {
+		return
+	}
 	pgMgr.Lock()
 	defer pgMgr.Unlock()
 	pgFullName, pg := pgMgr.GetPodGroup(pod)
@@ -228,13 +285,17 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 
 	if pgCopy.Status.Scheduled >= pgCopy.Spec.MinMember {
 		pgCopy.Status.Phase = v1alpha1.PodGroupScheduled
+		if milestones(pgCopy).AllBound == nil {
+			now := metav1.Now()
+			pgCopy.Status.Milestones.AllBound = &now
+		}
 	} else {
 		pgCopy.Status.Phase = v1alpha1.PodGroupScheduling
 		if pgCopy.Status.ScheduleStartTime.IsZero() {
 			pgCopy.Status.ScheduleStartTime = metav1.Time{Time: time.Now()}
 		}
 	}
-	if pgCopy.Status.Phase != pg.Status.Phase {
+	if pgCopy.Status.Phase != pg.Status.Phase || !reflect.DeepEqual(pgCopy.Status.Milestones, pg.Status.Milestones) {
 		pg, err := pgMgr.pgLister.PodGroups(pgCopy.Namespace).Get(pgCopy.Name)
 		if err != nil {
 			klog.ErrorS(err, "Failed to get PodGroup", "podGroup", klog.KObj(pgCopy))
@@ -250,6 +311,7 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 			return
 		}
 		pg.Status.Phase = pgCopy.Status.Phase
+		pg.Status.Milestones = pgCopy.Status.Milestones
 	}
 	pg.Status.Scheduled = pgCopy.Status.Scheduled
 	return
@@ -278,6 +340,110 @@ func (pgMgr *PodGroupManager) DeletePermittedPodGroup(pgFullName string) {
 	pgMgr.permittedPG.Delete(pgFullName)
 }
 
+// RejectPodGroup denies a podGroup that fails to be scheduled and deletes it from the permitted ones.
+// If the podGroup has a failure policy, the rejection is counted in its status, once per denial, and
+// the podGroup is denied for its backoff. It returns whether the rejection exceeded the backoff limit,
+// marking the podGroup Failed.
+func (pgMgr *PodGroupManager) RejectPodGroup(pgFullName string, pg *v1alpha1.PodGroup) bool {
+	pgMgr.DeletePermittedPodGroup(pgFullName)
+	if pg == nil || pg.Spec.FailurePolicy == nil {
+		pgMgr.AddDeniedPodGroup(pgFullName)
+		return false
+	}
+	pgMgr.Lock()
+	defer pgMgr.Unlock()
+	if _, ok := pgMgr.lastDeniedPG.Get(pgFullName); ok {
+		// The other members of the gang are rejected along with the first one.
+		return false
+	}
+	if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) {
+		pgMgr.AddDeniedPodGroup(pgFullName)
+		return false
+	}
+	policy := pg.Spec.FailurePolicy
+	// The failures of the lister's PodGroup, in case the update fails.
+	failures, failed := pg.Status.SchedulingFailures+1, false
+	err := pgMgr.updatePodGroup(pg.Namespace, pg.Name, func(pg *v1alpha1.PodGroup) bool {
+		if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) {
+			failures, failed = pg.Status.SchedulingFailures, false
+			return false
+		}
+		pg.Status.SchedulingFailures++
+		failures = pg.Status.SchedulingFailures
+		failed = failures > policy.BackoffLimit
+		if failed {
+			pg.Status.Phase = v1alpha1.PodGroupFailed
+			meta.SetStatusCondition(&pg.Status.Conditions, metav1.Condition{
+				Type:    v1alpha1.PodGroupSchedulingFailed,
+				Status:  metav1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: fmt.Sprintf("The gang was rejected %d times, exceeding the backoff limit %d", failures, policy.BackoffLimit),
+			})
+		}
+		return true
+	})
+	pgMgr.lastDeniedPG.Add(pgFullName, "", pgMgr.backoff(policy, failures))
+	if err != nil {
+		klog.ErrorS(err, "Failed to update", "podGroup", klog.KObj(pg))
+		return failures > policy.BackoffLimit
+	}
+	if failed {
+		klog.InfoS("PodGroup failed scheduling", "podGroup", klog.KObj(pg), "failures", failures)
+	}
+	return failed
+}
+
+// backoff returns the time a podGroup is denied after its nth rejection: the backoffSeconds of its
+// failure policy, or the denied PodGroup expiration time, doubled at every rejection up to a cap.
+func (pgMgr *PodGroupManager) backoff(policy *v1alpha1.PodGroupFailurePolicy, failures int32) time.Duration {
+	d := *pgMgr.lastDeniedPGExpirationTime
+	if policy.BackoffSeconds != nil {
+		d = time.Duration(*policy.BackoffSeconds) * time.Second
+	}
+	maxBackoff := v1alpha1.MaxPodGroupBackoffSeconds * time.Second
+	for i := int32(1); i < failures && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// GetPermittedPodGroups returns the sorted full names of the podGroups which passed Pre-Filter.
+func (pgMgr *PodGroupManager) GetPermittedPodGroups() []string {
+	return sortedKeys(pgMgr.permittedPG)
+}
+
+// GetDeniedPodGroups returns the sorted full names of the podGroups in the denied PodGroup cache.
+func (pgMgr *PodGroupManager) GetDeniedPodGroups() []string {
+	return sortedKeys(pgMgr.lastDeniedPG)
+}
+
+func sortedKeys(c *util.BoundedCache) []string {
+	keys := c.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// updatePodGroup applies mutate to the latest PodGroup namespace/name read from the API server and
+// updates it, guarded by its resourceVersion. On conflict, e.g. with the controller updating the
+// status, the PodGroup is read and mutated again, so that neither write is lost. mutate returns
+// false if no update is needed. The PodGroups of the lister are never modified.
+func (pgMgr *PodGroupManager) updatePodGroup(namespace, name string, mutate func(*v1alpha1.PodGroup) bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pg, err := pgMgr.pgClient.SchedulingV1alpha1().PodGroups(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !mutate(pg) {
+			return nil
+		}
+		_, err = pgMgr.pgClient.SchedulingV1alpha1().PodGroups(namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
+		return err
+	})
+}
+
 // PatchPodGroup patches a podGroup.
 func (pgMgr *PodGroupManager) PatchPodGroup(pgName string, namespace string, patch []byte) error {
 	if len(patch) == 0 {
@@ -288,11 +454,12 @@ func (pgMgr *PodGroupManager) PatchPodGroup(pgName string, namespace string, pat
 	return err
 }
 
-// GetPodGroup returns the PodGroup that a Pod belongs to from cache.
+// GetPodGroup returns the PodGroup that a Pod belongs to from cache. For a pod auto-grouped
+// by owner, it returns a PodGroup built from the pods of the owner.
 func (pgMgr *PodGroupManager) GetPodGroup(pod *corev1.Pod) (string, *v1alpha1.PodGroup) {
 	pgName := util.GetPodGroupLabel(pod)
 	if len(pgName) == 0 {
-		return "", nil
+		return pgMgr.getAutoPodGroup(pod)
 	}
 	pg, err := pgMgr.pgLister.PodGroups(pod.Namespace).Get(pgName)
 	if err != nil {
@@ -308,11 +475,12 @@ func (pgMgr *PodGroupManager) CalculateAssignedPods(podGroupName, namespace stri
 		klog.ErrorS(err, "Cannot get nodeInfos from frameworkHandle")
 		return 0
 	}
+	autoGroup := pgMgr.autoGroupByOwner(namespace)
 	var count int
 	for _, nodeInfo := range nodeInfos {
 		for _, podInfo := range nodeInfo.Pods {
 			pod := podInfo.Pod
-			if pod.Labels[v1alpha1.PodGroupLabel] == podGroupName && pod.Namespace == namespace && pod.Spec.NodeName != "" {
+			if pod.Namespace == namespace && groupName(pod, autoGroup) == podGroupName && pod.Spec.NodeName != "" {
 				count++
 			}
 		}
@@ -321,6 +489,101 @@ func (pgMgr *PodGroupManager) CalculateAssignedPods(podGroupName, namespace stri
 	return count
 }
 
+// GetMemberDomains returns the values of the topologyKey label of the nodes the other members of
+// the group of pod have been assigned to, assumed or bound.
+func (pgMgr *PodGroupManager) GetMemberDomains(pod *corev1.Pod, topologyKey string) sets.String {
+	domains := sets.NewString()
+	pgName := pgMgr.podGroupName(pod)
+	if len(pgName) == 0 {
+		return domains
+	}
+	nodeInfos, err := pgMgr.snapshotSharedLister.NodeInfos().List()
+	if err != nil {
+		klog.ErrorS(err, "Cannot get nodeInfos from frameworkHandle")
+		return domains
+	}
+	autoGroup := pgMgr.autoGroupByOwner(pod.Namespace)
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		domain, ok := node.Labels[topologyKey]
+		if !ok {
+			continue
+		}
+		for _, podInfo := range nodeInfo.Pods {
+			p := podInfo.Pod
+			if p.UID != pod.UID && p.Namespace == pod.Namespace && groupName(p, autoGroup) == pgName {
+				domains.Insert(domain)
+				break
+			}
+		}
+	}
+	return domains
+}
+
+// GetPendingMembers returns the other members of the group of pod not assigned a node yet, i.e.
+// neither bound nor assumed, nor being deleted, in order of name.
+func (pgMgr *PodGroupManager) GetPendingMembers(pod *corev1.Pod) ([]*corev1.Pod, error) {
+	pgName := pgMgr.podGroupName(pod)
+	if len(pgName) == 0 {
+		return nil, nil
+	}
+	pods, err := pgMgr.listGroupPods(pod)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfos, err := pgMgr.snapshotSharedLister.NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+	autoGroup := pgMgr.autoGroupByOwner(pod.Namespace)
+	assigned := sets.NewString()
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			p := podInfo.Pod
+			if p.Namespace == pod.Namespace && groupName(p, autoGroup) == pgName {
+				assigned.Insert(string(p.UID))
+			}
+		}
+	}
+	var pending []*corev1.Pod
+	for _, p := range pods {
+		if p.UID == pod.UID || len(p.Spec.NodeName) != 0 || p.DeletionTimestamp != nil || assigned.Has(string(p.UID)) {
+			continue
+		}
+		pending = append(pending, p)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	return pending, nil
+}
+
+// RelaxAntiAffinity records on a PodGroup that the anti-affinity of its members turned from
+// required into preferred, unless recorded already.
+func (pgMgr *PodGroupManager) RelaxAntiAffinity(pg *v1alpha1.PodGroup, message string) {
+	pgMgr.Lock()
+	defer pgMgr.Unlock()
+	if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) {
+		return
+	}
+	err := pgMgr.updatePodGroup(pg.Namespace, pg.Name, func(pg *v1alpha1.PodGroup) bool {
+		if meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) {
+			return false
+		}
+		meta.SetStatusCondition(&pg.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.PodGroupAntiAffinityRelaxed,
+			Status:  metav1.ConditionTrue,
+			Reason:  "MemberUnschedulable",
+			Message: message,
+		})
+		return true
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to update", "podGroup", klog.KObj(pg))
+	}
+}
+
 // CheckClusterResource checks if resource capacity of the cluster can satisfy <resourceRequest>.
 // It returns an error detailing the resource gap if not satisfied; otherwise returns nil.
 func CheckClusterResource(nodeList []*framework.NodeInfo, resourceRequest corev1.ResourceList, desiredPodGroupName string) error {
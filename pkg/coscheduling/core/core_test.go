@@ -21,8 +21,8 @@ import (
 	"testing"
 	"time"
 
-	gochache "github.com/patrickmn/go-cache"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -54,7 +54,10 @@ func TestPreFilter(t *testing.T) {
 	pgInformer.Informer().GetStore().Add(pg)
 	pgInformer.Informer().GetStore().Add(pg1)
 	pgInformer.Informer().GetStore().Add(pg2)
+	pg4 := testutil.MakePG("pg4", "ns1", 1, nil, nil)
+	pg4.Status.Conditions = []v1.Condition{{Type: v1alpha1.PodGroupSchedulingFailed, Status: v1.ConditionTrue}}
 	pgInformer.Informer().GetStore().Add(pg3)
+	pgInformer.Informer().GetStore().Add(pg4)
 	pgLister := pgInformer.Lister()
 	denyCache := newCache()
 	denyCache.SetDefault("ns1/pg1", "ns1/pg1")
@@ -63,7 +66,7 @@ func TestPreFilter(t *testing.T) {
 		name            string
 		pod             *corev1.Pod
 		pods            []*corev1.Pod
-		lastDeniedPG    *gochache.Cache
+		lastDeniedPG    *util.BoundedCache
 		expectedSuccess bool
 	}{
 		{
@@ -150,6 +153,15 @@ func TestPreFilter(t *testing.T) {
 			lastDeniedPG:    newCache(),
 			expectedSuccess: true,
 		},
+		{
+			name: "pg failed scheduling",
+			pod:  st.MakePod().Name("p4").UID("p4").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg4").Obj(),
+			pods: []*corev1.Pod{
+				st.MakePod().Name("p4").UID("p4").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg4").Obj(),
+			},
+			lastDeniedPG:    newCache(),
+			expectedSuccess: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -179,7 +191,9 @@ func TestPermit(t *testing.T) {
 	ctx := context.Background()
 	pg := testutil.MakePG("pg", "ns1", 2, nil, nil)
 	pg1 := testutil.MakePG("pg1", "ns1", 2, nil, nil)
-	fakeClient := fakepgclientset.NewSimpleClientset(pg, pg1)
+	pg2 := testutil.MakePG("pg2", "ns1", 2, nil, nil)
+	pg2.Status.Conditions = []v1.Condition{{Type: v1alpha1.PodGroupFullyScheduled, Status: v1.ConditionTrue}}
+	fakeClient := fakepgclientset.NewSimpleClientset(pg, pg1, pg2)
 
 	pgInformerFactory := pgformers.NewSharedInformerFactory(fakeClient, 0)
 	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
@@ -187,6 +201,7 @@ func TestPermit(t *testing.T) {
 
 	pgInformer.Informer().GetStore().Add(pg)
 	pgInformer.Informer().GetStore().Add(pg1)
+	pgInformer.Informer().GetStore().Add(pg2)
 	pgLister := pgInformer.Lister()
 
 	existingPods, allNodes := testutil.MakeNodesAndPods(map[string]string{v1alpha1.PodGroupLabel: "pg1"}, 1, 1)
@@ -222,6 +237,12 @@ func TestPermit(t *testing.T) {
 			snapshot: snapshot,
 			want:     Success,
 		},
+		{
+			name:     "pod replaces a member of a pg that was fully scheduled",
+			pod:      st.MakePod().Name("p").UID("p").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg2").Obj(),
+			snapshot: testutil.NewFakeSharedLister([]*corev1.Pod{}, []*corev1.Node{}),
+			want:     Success,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -229,6 +250,15 @@ func TestPermit(t *testing.T) {
 			if got := pgMgr.Permit(ctx, tt.pod); got != tt.want {
 				t.Errorf("Expect %v, but got %v", tt.want, got)
 			}
+			if tt.name == "pod belongs to a pg that has enough pods" {
+				pg, err := fakeClient.SchedulingV1alpha1().PodGroups("ns1").Get(ctx, "pg1", v1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if pg.Status.Milestones == nil || pg.Status.Milestones.PermitGranted == nil {
+					t.Errorf("Expect permitGranted to be recorded, but got %v", pg.Status.Milestones)
+				}
+			}
 		})
 	}
 }
@@ -256,12 +286,14 @@ func TestPostBind(t *testing.T) {
 		pod               *corev1.Pod
 		desiredGroupPhase v1alpha1.PodGroupPhase
 		desiredScheduled  int32
+		desiredAllBound   bool
 	}{
 		{
 			name:              "pg status convert to scheduled",
 			pod:               st.MakePod().Name("p").UID("p").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
 			desiredGroupPhase: v1alpha1.PodGroupScheduled,
 			desiredScheduled:  1,
+			desiredAllBound:   true,
 		},
 		{
 			name:              "pg status convert to scheduling",
@@ -291,6 +323,9 @@ func TestPostBind(t *testing.T) {
 				if pg.Status.Scheduled != tt.desiredScheduled {
 					return false, nil
 				}
+				if allBound := pg.Status.Milestones != nil && pg.Status.Milestones.AllBound != nil; allBound != tt.desiredAllBound {
+					return false, nil
+				}
 				return true, nil
 			})
 			if err != nil {
@@ -354,6 +389,128 @@ func TestCheckClusterResource(t *testing.T) {
 
 }
 
-func newCache() *gochache.Cache {
-	return gochache.New(10*time.Second, 10*time.Second)
+func TestRejectPodGroup(t *testing.T) {
+	ctx := context.Background()
+	backoffSeconds := int32(10)
+	pg := testutil.MakePG("pg", "ns1", 2, nil, nil)
+	pg.Spec.FailurePolicy = &v1alpha1.PodGroupFailurePolicy{BackoffLimit: 1, BackoffSeconds: &backoffSeconds}
+	noPolicy := testutil.MakePG("no-policy", "ns1", 2, nil, nil)
+	fakeClient := fakepgclientset.NewSimpleClientset(pg, noPolicy)
+	expiration := 3 * time.Second
+	pgMgr := &PodGroupManager{pgClient: fakeClient, lastDeniedPG: newCache(), permittedPG: newCache(), lastDeniedPGExpirationTime: &expiration}
+
+	getPG := func(name string) *v1alpha1.PodGroup {
+		t.Helper()
+		got, err := fakeClient.SchedulingV1alpha1().PodGroups("ns1").Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// The rejections of a PodGroup without failure policy are not counted.
+	pgMgr.permittedPG.SetDefault("ns1/no-policy", "ns1/no-policy")
+	if pgMgr.RejectPodGroup("ns1/no-policy", noPolicy) {
+		t.Error("expected the PodGroup without failure policy not to fail")
+	}
+	if _, ok := pgMgr.lastDeniedPG.Get("ns1/no-policy"); !ok {
+		t.Error("expected the PodGroup without failure policy denied")
+	}
+	if _, ok := pgMgr.permittedPG.Get("ns1/no-policy"); ok {
+		t.Error("expected the PodGroup without failure policy not permitted anymore")
+	}
+	if got := getPG("no-policy"); got.Status.SchedulingFailures != 0 {
+		t.Errorf("expected no scheduling failure counted, got %v", got.Status.SchedulingFailures)
+	}
+
+	// The controller set a condition since the PodGroup was listed.
+	latest := getPG("pg")
+	meta.SetStatusCondition(&latest.Status.Conditions, v1.Condition{Type: v1alpha1.PodGroupMinMemberSatisfiable, Status: v1.ConditionTrue, Reason: "MinMemberWithinParallelism"})
+	if _, err := fakeClient.SchedulingV1alpha1().PodGroups("ns1").Update(ctx, latest, v1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if pgMgr.RejectPodGroup("ns1/pg", pg) {
+		t.Error("expected the PodGroup not to fail within its backoff limit")
+	}
+	if pg.Status.SchedulingFailures != 0 {
+		t.Errorf("expected the listed PodGroup untouched, got %v scheduling failures", pg.Status.SchedulingFailures)
+	}
+	// The members rejected along with the first one are not counted.
+	pgMgr.RejectPodGroup("ns1/pg", pg)
+	if got := getPG("pg"); got.Status.SchedulingFailures != 1 || got.Status.Phase == v1alpha1.PodGroupFailed {
+		t.Errorf("expected 1 scheduling failure, got %v in phase %v", got.Status.SchedulingFailures, got.Status.Phase)
+	}
+
+	// The backoff elapsed, the gang is rejected again.
+	pgMgr.lastDeniedPG.Delete("ns1/pg")
+	if !pgMgr.RejectPodGroup("ns1/pg", pg) {
+		t.Error("expected the PodGroup to fail past its backoff limit")
+	}
+	got := getPG("pg")
+	if got.Status.SchedulingFailures != 2 || got.Status.Phase != v1alpha1.PodGroupFailed {
+		t.Errorf("expected the PodGroup failed after 2 scheduling failures, got %v in phase %v", got.Status.SchedulingFailures, got.Status.Phase)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) {
+		t.Errorf("expected the %v condition, got %v", v1alpha1.PodGroupSchedulingFailed, got.Status.Conditions)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.PodGroupMinMemberSatisfiable) {
+		t.Errorf("expected the condition of the controller kept, got %v", got.Status.Conditions)
+	}
+
+	// A failed PodGroup is not counted again.
+	pgMgr.lastDeniedPG.Delete("ns1/pg")
+	if pgMgr.RejectPodGroup("ns1/pg", pg) {
+		t.Error("expected the failed PodGroup not to fail again")
+	}
+	if got := getPG("pg"); got.Status.SchedulingFailures != 2 {
+		t.Errorf("expected 2 scheduling failures, got %v", got.Status.SchedulingFailures)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	expiration := 3 * time.Second
+	pgMgr := &PodGroupManager{lastDeniedPGExpirationTime: &expiration}
+	backoffSeconds := int32(10)
+	tests := []struct {
+		name     string
+		policy   *v1alpha1.PodGroupFailurePolicy
+		failures int32
+		expected time.Duration
+	}{
+		{
+			name:     "first failure, default backoff",
+			policy:   &v1alpha1.PodGroupFailurePolicy{},
+			failures: 1,
+			expected: 3 * time.Second,
+		},
+		{
+			name:     "first failure",
+			policy:   &v1alpha1.PodGroupFailurePolicy{BackoffSeconds: &backoffSeconds},
+			failures: 1,
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "third failure",
+			policy:   &v1alpha1.PodGroupFailurePolicy{BackoffSeconds: &backoffSeconds},
+			failures: 3,
+			expected: 40 * time.Second,
+		},
+		{
+			name:     "backoff capped",
+			policy:   &v1alpha1.PodGroupFailurePolicy{BackoffSeconds: &backoffSeconds},
+			failures: 100,
+			expected: 10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pgMgr.backoff(tt.policy, tt.failures); got != tt.expected {
+				t.Errorf("expected a backoff of %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func newCache() *util.BoundedCache {
+	return util.NewBoundedCache("test", 0, 10*time.Second)
 }
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// GetPolicy returns the CoschedulingPolicy of a namespace, or nil if the namespace has none.
+// If the namespace has several policies, the first one by name is returned.
+func (pgMgr *PodGroupManager) GetPolicy(namespace string) *v1alpha1.CoschedulingPolicy {
+	if pgMgr.policyLister == nil {
+		return nil
+	}
+	policies, err := pgMgr.policyLister.CoschedulingPolicies(namespace).List(labels.Everything())
+	if err != nil || len(policies) == 0 {
+		return nil
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies[0]
+}
+
+// GetWaitTimeDuration returns the time the pods of pg wait at Permit, based on the following precedences:
+// 1. spec.scheduleTimeoutSeconds of pg, if specified
+// 2. spec.permitWaitingTimeSeconds of the CoschedulingPolicy of the namespace of pg, if specified
+// 3. the scheduleTimeout of the manager, see util.GetWaitTimeDuration
+func (pgMgr *PodGroupManager) GetWaitTimeDuration(pg *v1alpha1.PodGroup) time.Duration {
+	scheduleTimeout := pgMgr.scheduleTimeout
+	if policy := pgMgr.GetPolicy(pg.Namespace); policy != nil && policy.Spec.PermitWaitingTimeSeconds != nil {
+		policyTimeout := time.Duration(*policy.Spec.PermitWaitingTimeSeconds) * time.Second
+		scheduleTimeout = &policyTimeout
+	}
+	return util.GetWaitTimeDuration(pg, scheduleTimeout)
+}
+
+// GetGangTimeoutPolicy returns the action taken when a pod of the namespace times out at Permit.
+func (pgMgr *PodGroupManager) GetGangTimeoutPolicy(namespace string) v1alpha1.GangTimeoutPolicy {
+	if policy := pgMgr.GetPolicy(namespace); policy != nil && len(policy.Spec.GangTimeoutPolicy) != 0 {
+		return policy.Spec.GangTimeoutPolicy
+	}
+	return v1alpha1.GangTimeoutRejectGang
+}
+
+// GetPodGroupFullName returns the namespaced name of the group of a pod: the PodGroup of its
+// label or, if the pod is auto-grouped, the group of its owner. It returns "" if the pod does not
+// belong to any group.
+func (pgMgr *PodGroupManager) GetPodGroupFullName(pod *corev1.Pod) string {
+	pgName := pgMgr.podGroupName(pod)
+	if len(pgName) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v/%v", pod.Namespace, pgName)
+}
+
+// autoGroupByOwner returns whether the pods of a namespace not labeled with a PodGroup are
+// grouped by their controller owner.
+func (pgMgr *PodGroupManager) autoGroupByOwner(namespace string) bool {
+	policy := pgMgr.GetPolicy(namespace)
+	return policy != nil && policy.Spec.AutoGroupByOwner
+}
+
+// podGroupName returns the name of the group of a pod, or "" if it does not belong to any group.
+func (pgMgr *PodGroupManager) podGroupName(pod *corev1.Pod) string {
+	if pgName := util.GetPodGroupLabel(pod); len(pgName) != 0 {
+		return pgName
+	}
+	return groupName(pod, pgMgr.autoGroupByOwner(pod.Namespace))
+}
+
+// groupName returns the name of the group of a pod, given whether the pods of its namespace
+// are auto-grouped by owner.
+func groupName(pod *corev1.Pod, autoGroup bool) string {
+	if pgName := util.GetPodGroupLabel(pod); len(pgName) != 0 {
+		return pgName
+	}
+	if !autoGroup {
+		return ""
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return autoPodGroupName(owner)
+	}
+	return ""
+}
+
+// autoPodGroupName returns the name of the group of the pods controlled by owner, e.g. replicaset-foo.
+func autoPodGroupName(owner *metav1.OwnerReference) string {
+	return strings.ToLower(owner.Kind) + "-" + owner.Name
+}
+
+// listGroupPods returns the pods of the group of a pod, including the pod itself: the pods
+// labeled with its PodGroup or, for a pod auto-grouped by owner, the unlabeled pods of its owner.
+func (pgMgr *PodGroupManager) listGroupPods(pod *corev1.Pod) ([]*corev1.Pod, error) {
+	if pgName := util.GetPodGroupLabel(pod); len(pgName) != 0 {
+		return pgMgr.podLister.Pods(pod.Namespace).List(
+			labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
+		)
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var owned []*corev1.Pod
+	for _, p := range pods {
+		if len(util.GetPodGroupLabel(p)) != 0 {
+			continue
+		}
+		if ref := metav1.GetControllerOf(p); ref != nil && ref.UID == owner.UID {
+			owned = append(owned, p)
+		}
+	}
+	return owned, nil
+}
+
+// getAutoPodGroup returns the PodGroup of a pod auto-grouped by owner. It is not backed by any
+// object: its minMember is the number of pods of the owner, and its creation timestamp that of
+// the oldest of them.
+func (pgMgr *PodGroupManager) getAutoPodGroup(pod *corev1.Pod) (string, *v1alpha1.PodGroup) {
+	pgName := pgMgr.podGroupName(pod)
+	if len(pgName) == 0 {
+		return "", nil
+	}
+	pgFullName := fmt.Sprintf("%v/%v", pod.Namespace, pgName)
+	pods, err := pgMgr.listGroupPods(pod)
+	if err != nil {
+		return pgFullName, nil
+	}
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: pgName, Namespace: pod.Namespace, CreationTimestamp: pod.CreationTimestamp},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: int32(len(pods))},
+	}
+	for _, p := range pods {
+		if p.CreationTimestamp.Before(&pg.CreationTimestamp) {
+			pg.CreationTimestamp = p.CreationTimestamp
+		}
+	}
+	// The pod may not have reached the pod informer yet.
+	if pg.Spec.MinMember == 0 {
+		pg.Spec.MinMember = 1
+	}
+	return pgFullName, pg
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakepgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makePolicy(name, namespace string, waitingTime *int32, timeoutPolicy v1alpha1.GangTimeoutPolicy, autoGroup bool) *v1alpha1.CoschedulingPolicy {
+	return &v1alpha1.CoschedulingPolicy{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.CoschedulingPolicySpec{
+			PermitWaitingTimeSeconds: waitingTime,
+			GangTimeoutPolicy:        timeoutPolicy,
+			AutoGroupByOwner:         autoGroup,
+		},
+	}
+}
+
+func makeOwnedPod(name, namespace, owner string) *corev1.Pod {
+	pod := st.MakePod().Name(name).UID(name).Namespace(namespace).
+		OwnerReference(owner, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}).Obj()
+	pod.OwnerReferences[0].UID = types.UID(owner)
+	return pod
+}
+
+func newPolicyTestManager(policies []*v1alpha1.CoschedulingPolicy, pods []*corev1.Pod) *PodGroupManager {
+	pgInformerFactory := pgformers.NewSharedInformerFactory(fakepgclientset.NewSimpleClientset(), 0)
+	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
+	for _, policy := range policies {
+		policyInformer.Informer().GetStore().Add(policy)
+	}
+	podInformer := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0).Core().V1().Pods()
+	for _, pod := range pods {
+		podInformer.Informer().GetStore().Add(pod)
+	}
+	scheduleTimeout := 20 * time.Second
+	return NewPodGroupManager(nil, nil, &scheduleTimeout, &scheduleTimeout, pgInformer, podInformer, policyInformer)
+}
+
+func TestGetPodGroupAutoGroupByOwner(t *testing.T) {
+	pods := []*corev1.Pod{
+		makeOwnedPod("rs1-a", "ns1", "rs1"),
+		makeOwnedPod("rs1-b", "ns1", "rs1"),
+		makeOwnedPod("rs1-c", "ns1", "rs1"),
+		makeOwnedPod("rs2-a", "ns1", "rs2"),
+		makeOwnedPod("rs3-a", "ns2", "rs3"),
+	}
+	pgMgr := newPolicyTestManager([]*v1alpha1.CoschedulingPolicy{makePolicy("policy", "ns1", nil, "", true)}, pods)
+
+	tests := []struct {
+		name              string
+		pod               *corev1.Pod
+		expectedFullName  string
+		expectedMinMember int32
+	}{
+		{
+			name:              "pod auto-grouped by owner",
+			pod:               pods[0],
+			expectedFullName:  "ns1/replicaset-rs1",
+			expectedMinMember: 3,
+		},
+		{
+			name:              "pod labeled with a PodGroup is not auto-grouped",
+			pod:               st.MakePod().Name("p").UID("p").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
+			expectedFullName:  "ns1/pg",
+			expectedMinMember: 0,
+		},
+		{
+			name:             "pod without owner",
+			pod:              st.MakePod().Name("p").UID("p").Namespace("ns1").Obj(),
+			expectedFullName: "",
+		},
+		{
+			name:             "namespace without auto-grouping",
+			pod:              pods[4],
+			expectedFullName: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pgMgr.GetPodGroupFullName(tt.pod); got != tt.expectedFullName {
+				t.Errorf("expected group %q, got %q", tt.expectedFullName, got)
+			}
+			pgFullName, pg := pgMgr.GetPodGroup(tt.pod)
+			if pgFullName != tt.expectedFullName {
+				t.Errorf("expected PodGroup %q, got %q", tt.expectedFullName, pgFullName)
+			}
+			var minMember int32
+			if pg != nil {
+				minMember = pg.Spec.MinMember
+			}
+			if minMember != tt.expectedMinMember {
+				t.Errorf("expected minMember %v, got %v", tt.expectedMinMember, minMember)
+			}
+		})
+	}
+}
+
+func TestGetWaitTimeDuration(t *testing.T) {
+	waitingTime := int32(30)
+	pgMgr := newPolicyTestManager([]*v1alpha1.CoschedulingPolicy{
+		makePolicy("b", "ns1", nil, "", false),
+		makePolicy("a", "ns1", &waitingTime, v1alpha1.GangTimeoutRejectPod, false),
+	}, nil)
+
+	tests := []struct {
+		name     string
+		pg       *v1alpha1.PodGroup
+		expected time.Duration
+	}{
+		{
+			name:     "scheduleTimeoutSeconds of the PodGroup",
+			pg:       testutil.MakePG("pg", "ns1", 2, nil, nil),
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "permitWaitingTimeSeconds of the policy",
+			pg:       &v1alpha1.PodGroup{ObjectMeta: v1.ObjectMeta{Name: "pg", Namespace: "ns1"}},
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "namespace without policy",
+			pg:       &v1alpha1.PodGroup{ObjectMeta: v1.ObjectMeta{Name: "pg", Namespace: "ns2"}},
+			expected: 20 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pgMgr.GetWaitTimeDuration(tt.pg); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := pgMgr.GetGangTimeoutPolicy("ns1"); got != v1alpha1.GangTimeoutRejectPod {
+		t.Errorf("expected the gang timeout policy of the first policy by name, got %v", got)
+	}
+	if got := pgMgr.GetGangTimeoutPolicy("ns2"); got != v1alpha1.GangTimeoutRejectGang {
+		t.Errorf("expected the default gang timeout policy, got %v", got)
+	}
+}
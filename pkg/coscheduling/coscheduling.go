@@ -19,20 +19,28 @@ package coscheduling
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
 	"k8s.io/klog/v2"
+	schedulerapisconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling/core"
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
 	pgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
 	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
@@ -43,21 +51,67 @@ type Coscheduling struct {
 	frameworkHandler framework.Handle
 	pgMgr            core.Manager
 	scheduleTimeout  *time.Duration
+	binder           *gangBinder
+	// bindEnabled tells whether Coscheduling is enabled at the Bind extension point, only known
+	// once the framework is built.
+	bindEnabled     bool
+	bindEnabledOnce sync.Once
+	// maxWaitingPods caps the pods waiting at Permit, unbounded if not positive.
+	maxWaitingPods int64
+	// ruledOutNodes holds the nodes on which the first member of a PodGroup would leave the rest
+	// of the gang unschedulable, by PodGroup full name.
+	ruledOutNodes *util.BoundedCache
 }
 
 var _ framework.QueueSortPlugin = &Coscheduling{}
 var _ framework.PreFilterPlugin = &Coscheduling{}
+var _ framework.FilterPlugin = &Coscheduling{}
 var _ framework.PostFilterPlugin = &Coscheduling{}
+var _ framework.ScorePlugin = &Coscheduling{}
 var _ framework.PermitPlugin = &Coscheduling{}
 var _ framework.ReservePlugin = &Coscheduling{}
+var _ framework.BindPlugin = &Coscheduling{}
 var _ framework.PostBindPlugin = &Coscheduling{}
 var _ framework.EnqueueExtensions = &Coscheduling{}
+var _ debug.Dumper = &Coscheduling{}
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
 	Name = "Coscheduling"
+
+	// preFilterStateKey is the key in CycleState to the node constraints of the PodGroup of the pod.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonPodGroupNodeAffinity is the status message of the nodes not matching the node
+	// constraints of the PodGroup.
+	ErrReasonPodGroupNodeAffinity = "node(s) didn't match PodGroup node selector or affinity"
+
+	// ErrReasonPodGroupAntiAffinity is the status message of the nodes in the domain of another
+	// member of the PodGroup, whose member anti-affinity is required.
+	ErrReasonPodGroupAntiAffinity = "node(s) didn't match PodGroup member anti-affinity"
+
+	// maxRuledOutPodGroups bounds the number of PodGroups whose ruled out nodes are remembered.
+	maxRuledOutPodGroups = 10000
 )
 
+// preFilterState holds the node constraints of the PodGroup of the pod being scheduled.
+type preFilterState struct {
+	requiredNodeAffinity nodeaffinity.RequiredNodeAffinity
+	// antiAffinityKey is the topology key of the member anti-affinity of the PodGroup, if any,
+	// and memberDomains the domains of the members placed already.
+	antiAffinityKey     string
+	memberDomains       sets.String
+	antiAffinityRelaxed bool
+	// ruledOutNodes are the nodes on which the pod, first member of the gang, would leave the
+	// rest of the gang unschedulable.
+	ruledOutNodes sets.String
+}
+
+// Clone the prefilter state. The state is never modified once written.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
 // New initializes and returns a new Coscheduling plugin.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	args, ok := obj.(*config.CoschedulingArgs)
@@ -65,9 +119,10 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		return nil, fmt.Errorf("want args to be of type CoschedulingArgs, got %T", obj)
 	}
 
-	pgClient := pgclientset.NewForConfigOrDie(handle.KubeConfig())
+	pgClient := pgclientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
 	pgInformerFactory := pgformers.NewSharedInformerFactory(pgClient, 0)
 	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
 	podInformer := handle.SharedInformerFactory().Core().V1().Pods()
 
 	scheduleTimeDuration := time.Duration(args.PermitWaitingTimeSeconds) * time.Second
@@ -75,14 +130,28 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 
 	ctx := context.TODO()
 
-	pgMgr := core.NewPodGroupManager(pgClient, handle.SnapshotSharedLister(), &scheduleTimeDuration, &deniedPGExpirationTime, pgInformer, podInformer)
+	pgMgr := core.NewPodGroupManager(pgClient, handle.SnapshotSharedLister(), &scheduleTimeDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
 	plugin := &Coscheduling{
 		frameworkHandler: handle,
 		pgMgr:            pgMgr,
 		scheduleTimeout:  &scheduleTimeDuration,
+		ruledOutNodes:    newRuledOutNodes(),
+		maxWaitingPods:   args.MaxWaitingPods,
+	}
+	if plugin.maxWaitingPods > 0 {
+		registerWaitingPodsMetrics()
 	}
+	plugin.binder = newGangBinder(int(args.BindParallelism), plugin.bindPodToNode)
+	pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				plugin.binder.drop(key, fmt.Errorf("PodGroup %v has been deleted", key))
+			}
+		},
+	})
+	debug.Register(Name, plugin)
 	pgInformerFactory.Start(ctx.Done())
-	if !cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced) {
+	if !cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced, policyInformer.Informer().HasSynced) {
 		err := fmt.Errorf("WaitForCacheSync failed")
 		klog.ErrorS(err, "Cannot sync caches")
 		return nil, err
@@ -94,9 +163,12 @@ func (cs *Coscheduling) EventsToRegister() []framework.ClusterEvent {
 	// To register a custom event, follow the naming convention at:
 	// https://git.k8s.io/kubernetes/pkg/scheduler/eventhandlers.go#L403-L410
 	pgGVK := fmt.Sprintf("podgroups.v1alpha1.%v", scheduling.GroupName)
+	policyGVK := fmt.Sprintf("coschedulingpolicies.v1alpha1.%v", scheduling.GroupName)
 	return []framework.ClusterEvent{
 		{Resource: framework.Pod, ActionType: framework.Add},
 		{Resource: framework.GVK(pgGVK), ActionType: framework.Add | framework.Update},
+		{Resource: framework.GVK(policyGVK), ActionType: framework.Add | framework.Update},
+		{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeLabel},
 	}
 }
 
@@ -123,19 +195,131 @@ func (cs *Coscheduling) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
 	return creationTime1.Before(creationTime2)
 }
 
-// PreFilter performs the following validations.
+// preFilter performs the following validations.
 // 1. Whether the PodGroup that the Pod belongs to is on the deny list.
 // 2. Whether the total number of pods in a PodGroup is less than its `minMember`.
-func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+// It then records the node constraints of the PodGroup, if any, for Filter.
+func (cs *Coscheduling) preFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
 	// If any validation failed, a no-op state data is injected to "state" so that in later
 	// phases we can tell whether the failure comes from PreFilter or not.
 	if err := cs.pgMgr.PreFilter(ctx, pod); err != nil {
 		klog.ErrorS(err, "PreFilter failed", "pod", klog.KObj(pod))
+		if _, pg := cs.pgMgr.GetPodGroup(pod); pg != nil && meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupSchedulingFailed) {
+			cs.deleteFailedMembers(ctx, pod, pg)
+		}
 		return framework.NewStatus(framework.Unschedulable, err.Error())
 	}
+	pgFullName, pg := cs.pgMgr.GetPodGroup(pod)
+	if pg == nil {
+		return framework.NewStatus(framework.Success, "")
+	}
+	var ruledOutNodes sets.String
+	if nodes := cs.getRuledOutNodes(pgFullName); nodes.Len() != 0 && cs.pgMgr.CalculateAssignedPods(pg.Name, pod.Namespace) == 0 {
+		ruledOutNodes = nodes
+	}
+	if len(pg.Spec.NodeSelector) == 0 && pg.Spec.NodeAffinity == nil && pg.Spec.MemberAntiAffinity == nil && ruledOutNodes == nil {
+		return framework.NewStatus(framework.Success, "")
+	}
+	s := &preFilterState{requiredNodeAffinity: podGroupNodeAffinity(pg), ruledOutNodes: ruledOutNodes}
+	if aa := pg.Spec.MemberAntiAffinity; aa != nil && len(aa.TopologyKey) != 0 {
+		s.antiAffinityKey = aa.TopologyKey
+		s.memberDomains = cs.pgMgr.GetMemberDomains(pod, aa.TopologyKey)
+		s.antiAffinityRelaxed = meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed)
+	}
+	state.Write(preFilterStateKey, s)
 	return framework.NewStatus(framework.Success, "")
 }
 
+// podGroupNodeAffinity returns the node constraints of the PodGroup, shared by all its members.
+func podGroupNodeAffinity(pg *v1alpha1.PodGroup) nodeaffinity.RequiredNodeAffinity {
+	pod := &v1.Pod{Spec: v1.PodSpec{NodeSelector: pg.Spec.NodeSelector}}
+	if pg.Spec.NodeAffinity != nil {
+		pod.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: pg.Spec.NodeAffinity,
+		}}
+	}
+	return nodeaffinity.GetRequiredNodeAffinity(pod)
+}
+
+// getPreFilterState returns the node constraints of the PodGroup of the pod, nil if it has none.
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		// The PodGroup of the pod, if any, has no node constraints.
+		return nil, nil
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to coscheduling.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// inMemberDomain tells whether node is in the domain of another member of the PodGroup.
+func (s *preFilterState) inMemberDomain(node *v1.Node) bool {
+	if len(s.antiAffinityKey) == 0 {
+		return false
+	}
+	domain, ok := node.Labels[s.antiAffinityKey]
+	return ok && s.memberDomains.Has(domain)
+}
+
+// Filter rejects the nodes not matching the node selector or node affinity of the PodGroup of the
+// pod, and, unless relaxed, the nodes in the domain of another member of the PodGroup. The nodes
+// ruled out at Permit for the first member of the gang are rejected as well.
+func (cs *Coscheduling) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if s == nil {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	match, err := s.requiredNodeAffinity.Match(node)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if !match {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPodGroupNodeAffinity)
+	}
+	if !s.antiAffinityRelaxed && s.inMemberDomain(node) {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonPodGroupAntiAffinity)
+	}
+	if s.ruledOutNodes.Has(node.Name) {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonGangPlacement)
+	}
+	return nil
+}
+
+// Score prefers the nodes out of the domains of the other members of the PodGroup of the pod,
+// which only matters once its member anti-affinity is relaxed.
+func (cs *Coscheduling) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if s == nil || len(s.antiAffinityKey) == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := cs.frameworkHandler.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	if nodeInfo.Node() == nil || s.inMemberDomain(nodeInfo.Node()) {
+		return 0, nil
+	}
+	return framework.MaxNodeScore, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (cs *Coscheduling) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
 // PostFilter is used to rejecting a group of pods if a pod does not pass PreFilter or Filter.
 func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod,
 	filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
@@ -145,6 +329,12 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleSt
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable, "can not find pod group")
 	}
 
+	// The pod is retried with the member anti-affinity relaxed rather than rejecting the gang.
+	if cs.relaxAntiAffinity(pod, pg, filteredNodeStatusMap) {
+		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("PodGroup %v relaxed its member anti-affinity", pgName))
+	}
+
 	// This indicates there are already enough Pods satisfying the PodGroup,
 	// so don't bother to reject the whole PodGroup.
 	assigned := cs.pgMgr.CalculateAssignedPods(pg.Name, pod.Namespace)
@@ -164,23 +354,51 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleSt
 	// It's based on an implicit assumption: if the nth Pod failed,
 	// it's inferrable other Pods belonging to the same PodGroup would be very likely to fail.
 	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
-		if waitingPod.GetPod().Namespace == pod.Namespace && waitingPod.GetPod().Labels[v1alpha1.PodGroupLabel] == pg.Name {
+		if cs.pgMgr.GetPodGroupFullName(waitingPod.GetPod()) == pgName {
 			klog.V(3).InfoS("PostFilter rejects the pod", "podGroup", klog.KObj(pg), "pod", klog.KObj(waitingPod.GetPod()))
 			waitingPod.Reject(cs.Name(), "optimistic rejection in PostFilter")
 		}
 	})
-	cs.pgMgr.AddDeniedPodGroup(pgName)
-	cs.pgMgr.DeletePermittedPodGroup(pgName)
+	if cs.pgMgr.RejectPodGroup(pgName, pg) {
+		cs.deleteFailedMembers(ctx, pod, pg)
+	}
 	return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
 		fmt.Sprintf("PodGroup %v gets rejected due to Pod %v is unschedulable even after PostFilter", pgName, pod.Name))
 }
 
+// relaxAntiAffinity relaxes the member anti-affinity of the PodGroup, if allowed and not relaxed
+// already, when nodes rejected the pod because of it. It returns whether it relaxed it.
+func (cs *Coscheduling) relaxAntiAffinity(pod *v1.Pod, pg *v1alpha1.PodGroup, filteredNodeStatusMap framework.NodeToStatusMap) bool {
+	aa := pg.Spec.MemberAntiAffinity
+	if aa == nil || aa.Relaxation != v1alpha1.AntiAffinityRelaxationIfUnschedulable ||
+		meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) {
+		return false
+	}
+	var rejected int
+	for _, status := range filteredNodeStatusMap {
+		for _, reason := range status.Reasons() {
+			if reason == ErrReasonPodGroupAntiAffinity {
+				rejected++
+				break
+			}
+		}
+	}
+	if rejected == 0 {
+		return false
+	}
+	message := fmt.Sprintf("Pod %v was rejected by %d node(s) because of the member anti-affinity on %v", pod.Name, rejected, aa.TopologyKey)
+	klog.InfoS("Relaxing the member anti-affinity of the PodGroup", "podGroup", klog.KObj(pg), "pod", klog.KObj(pod), "rejectedNodes", rejected)
+	cs.pgMgr.RelaxAntiAffinity(pg, message)
+	return true
+}
+
 // PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
 func (cs *Coscheduling) PreFilterExtensions() framework.PreFilterExtensions {
 	return nil
 }
 
-// Permit is the functions invoked by the framework at "Permit" extension point.
+// Permit is the functions invoked by the framework at "Permit" extension point. Before making the
+// first member of a gang wait, it checks that the rest of the gang would fit along with it.
 func (cs *Coscheduling) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
 	waitTime := *cs.scheduleTimeout
 	s := cs.pgMgr.Permit(ctx, pod)
@@ -191,22 +409,42 @@ func (cs *Coscheduling) Permit(ctx context.Context, state *framework.CycleState,
 	case core.PodGroupNotFound:
 		return framework.NewStatus(framework.Unschedulable, "PodGroup not found"), 0
 	case core.Wait:
+		pgFullName, pg := cs.pgMgr.GetPodGroup(pod)
+		if cs.pgMgr.CalculateAssignedPods(pg.Name, pod.Namespace) == 0 {
+			if err := cs.checkGangPlacement(ctx, pod, nodeName, pg); err != nil {
+				return cs.rejectGangPlacement(state, pod, nodeName, pgFullName, err), 0
+			}
+		}
+		if status := cs.admitWaitingPod(pod, pgFullName); status != nil {
+			return status, 0
+		}
 		klog.InfoS("Pod is waiting to be scheduled to node", "pod", klog.KObj(pod), "nodeName", nodeName)
-		_, pg := cs.pgMgr.GetPodGroup(pod)
-		if wait := util.GetWaitTimeDuration(pg, cs.scheduleTimeout); wait != 0 {
+		if wait := cs.pgMgr.GetWaitTimeDuration(pg); wait != 0 {
 			waitTime = wait
 		}
 		retStatus = framework.NewStatus(framework.Wait)
 		// We will also request to move the sibling pods back to activeQ.
 		cs.pgMgr.ActivateSiblings(pod, state)
 	case core.Success:
-		pgFullName := util.GetPodGroupFullName(pod)
+		pgFullName := cs.pgMgr.GetPodGroupFullName(pod)
+		cs.ruledOutNodes.Delete(pgFullName)
+		var waitingPods []framework.WaitingPod
+		members := sets.NewString(string(pod.UID))
 		cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
-			if util.GetPodGroupFullName(waitingPod.GetPod()) == pgFullName {
-				klog.V(3).InfoS("Permit allows", "pod", klog.KObj(waitingPod.GetPod()))
-				waitingPod.Allow(cs.Name())
+			if cs.pgMgr.GetPodGroupFullName(waitingPod.GetPod()) == pgFullName {
+				waitingPods = append(waitingPods, waitingPod)
+				members.Insert(string(waitingPod.GetPod().UID))
 			}
 		})
+		// Register the members before allowing them, so that they get bound together
+		// if Coscheduling is enabled at the Bind extension point.
+		if cs.isBindEnabled() {
+			cs.binder.expect(pgFullName, members)
+		}
+		for _, waitingPod := range waitingPods {
+			klog.V(3).InfoS("Permit allows", "pod", klog.KObj(waitingPod.GetPod()))
+			waitingPod.Allow(cs.Name())
+		}
 		klog.V(3).InfoS("Permit allows", "pod", klog.KObj(pod))
 		retStatus = framework.NewStatus(framework.Success)
 		waitTime = 0
@@ -220,20 +458,95 @@ func (cs *Coscheduling) Reserve(ctx context.Context, state *framework.CycleState
 	return nil
 }
 
-// Unreserve rejects all other Pods in the PodGroup when one of the pods in the group times out.
+// Unreserve rejects all other Pods in the PodGroup when one of the pods in the group times out,
+// unless the CoschedulingPolicy of the namespace only rejects the pod timing out.
 func (cs *Coscheduling) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
 	pgName, pg := cs.pgMgr.GetPodGroup(pod)
 	if pg == nil {
 		return
 	}
+	if _, err := state.Read(gangPlacementRejectedKey); err == nil {
+		// The first member was rejected at Permit to be retried on another node, with no other
+		// member waiting: the gang is not denied.
+		return
+	}
+	cs.binder.abort(pgName, pod, fmt.Errorf("PodGroup %v gets unreserved due to Pod %v", pgName, pod.Name))
+	if cs.pgMgr.GetGangTimeoutPolicy(pod.Namespace) == v1alpha1.GangTimeoutRejectPod {
+		klog.V(3).InfoS("Unreserve only rejects the pod", "pod", klog.KObj(pod), "podGroup", klog.KObj(pg))
+		return
+	}
 	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
-		if waitingPod.GetPod().Namespace == pod.Namespace && waitingPod.GetPod().Labels[v1alpha1.PodGroupLabel] == pg.Name {
+		if cs.pgMgr.GetPodGroupFullName(waitingPod.GetPod()) == pgName {
 			klog.V(3).InfoS("Unreserve rejects", "pod", klog.KObj(waitingPod.GetPod()), "podGroup", klog.KObj(pg))
 			waitingPod.Reject(cs.Name(), "rejection in Unreserve")
 		}
 	})
-	cs.pgMgr.AddDeniedPodGroup(pgName)
-	cs.pgMgr.DeletePermittedPodGroup(pgName)
+	if cs.pgMgr.RejectPodGroup(pgName, pg) {
+		cs.deleteFailedMembers(ctx, pod, pg)
+	}
+}
+
+// deleteFailedMembers deletes the pod and the other pending members of a PodGroup that failed
+// scheduling, if its failure policy says so.
+func (cs *Coscheduling) deleteFailedMembers(ctx context.Context, pod *v1.Pod, pg *v1alpha1.PodGroup) {
+	if pg.Spec.FailurePolicy == nil || pg.Spec.FailurePolicy.Action != v1alpha1.PodGroupFailureDelete {
+		return
+	}
+	pods, err := cs.pgMgr.GetPendingMembers(pod)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list the pending members of the failed PodGroup", "podGroup", klog.KObj(pg))
+	}
+	for _, p := range append(pods, pod) {
+		klog.V(3).InfoS("Deleting the pod of the failed PodGroup", "pod", klog.KObj(p), "podGroup", klog.KObj(pg))
+		err := cs.frameworkHandler.ClientSet().CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the pod of the failed PodGroup", "pod", klog.KObj(p), "podGroup", klog.KObj(pg))
+		}
+	}
+}
+
+// isBindEnabled tells whether Coscheduling is enabled at the Bind extension point of its profile.
+// The framework lists its plugins once built, after New.
+func (cs *Coscheduling) isBindEnabled() bool {
+	cs.bindEnabledOnce.Do(func() {
+		lister, ok := cs.frameworkHandler.(interface {
+			ListPlugins() *schedulerapisconfig.Plugins
+		})
+		if !ok {
+			return
+		}
+		for _, p := range lister.ListPlugins().Bind.Enabled {
+			if p.Name == Name {
+				cs.bindEnabled = true
+			}
+		}
+	})
+	return cs.bindEnabled
+}
+
+// Bind binds the members of a permitted PodGroup concurrently, once all of them have reached
+// the Bind extension point. If any binding fails, the bindings not issued yet are given up and
+// all remaining members fail, so that they get unreserved. Pods not belonging to a PodGroup are
+// skipped and left to the other Bind plugins (e.g., DefaultBinder).
+func (cs *Coscheduling) Bind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	pgFullName := cs.pgMgr.GetPodGroupFullName(pod)
+	if len(pgFullName) == 0 {
+		return framework.NewStatus(framework.Skip)
+	}
+	waitTime := *cs.scheduleTimeout
+	if _, pg := cs.pgMgr.GetPodGroup(pod); pg != nil {
+		if wait := cs.pgMgr.GetWaitTimeDuration(pg); wait != 0 {
+			waitTime = wait
+		}
+	}
+	handled, err := cs.binder.bindPod(ctx, pgFullName, pod, nodeName, waitTime)
+	if !handled {
+		return framework.NewStatus(framework.Skip)
+	}
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
 }
 
 // PostBind is called after a pod is successfully bound. These plugins are used update PodGroup when pod is bound.
@@ -250,3 +563,27 @@ func (cs *Coscheduling) rejectPod(uid types.UID) {
 	}
 	waitingPod.Reject(Name, "")
 }
+
+// gangSnapshot is the state of the gangs being scheduled.
+type gangSnapshot struct {
+	// WaitingPods maps a PodGroup full name to the pods waiting at Permit.
+	WaitingPods        map[string][]string `json:"waitingPods"`
+	PermittedPodGroups []string            `json:"permittedPodGroups"`
+	DeniedPodGroups    []string            `json:"deniedPodGroups"`
+}
+
+// Dump returns the pods waiting at Permit and the permitted and denied PodGroups.
+func (cs *Coscheduling) Dump() interface{} {
+	s := &gangSnapshot{
+		WaitingPods:        make(map[string][]string),
+		PermittedPodGroups: cs.pgMgr.GetPermittedPodGroups(),
+		DeniedPodGroups:    cs.pgMgr.GetDeniedPodGroups(),
+	}
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		pod := waitingPod.GetPod()
+		if pgFullName := cs.pgMgr.GetPodGroupFullName(pod); len(pgFullName) != 0 {
+			s.WaitingPods[pgFullName] = append(s.WaitingPods[pgFullName], core.GetNamespacedName(pod))
+		}
+	})
+	return s
+}
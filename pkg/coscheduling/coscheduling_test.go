@@ -22,6 +22,8 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/events"
@@ -49,6 +51,7 @@ func TestLess(t *testing.T) {
 	cs := fakepgclientset.NewSimpleClientset()
 	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
 	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
 	pgInformerFactory.Start(ctx.Done())
 	for _, pgInfo := range []struct {
 		createTime time.Time
@@ -257,8 +260,8 @@ func TestLess(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pgMgr := core.NewPodGroupManager(cs, snapshot, &scheudleDuration, &deniedPGExpirationTime, pgInformer, podInformer)
-			coscheduling := &Coscheduling{pgMgr: pgMgr}
+			pgMgr := core.NewPodGroupManager(cs, snapshot, &scheudleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+			coscheduling := &Coscheduling{pgMgr: pgMgr, ruledOutNodes: newRuledOutNodes()}
 			if got := coscheduling.Less(tt.p1, tt.p2); got != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, got)
 			}
@@ -292,6 +295,7 @@ func TestPermit(t *testing.T) {
 	cs := fakepgclientset.NewSimpleClientset()
 	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
 	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
 	pgInformerFactory.Start(ctx.Done())
 	pg1 := testutil.MakePG("pg1", "ns1", 2, nil, nil)
 	pg2 := testutil.MakePG("pg2", "ns1", 1, nil, nil)
@@ -322,8 +326,9 @@ func TestPermit(t *testing.T) {
 	deniedPGExpirationTime := 3 * time.Second
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pgMgr := core.NewPodGroupManager(cs, snapshot, &scheudleDuration, &deniedPGExpirationTime, pgInformer, podInformer)
-			coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheudleDuration}
+			pgMgr := core.NewPodGroupManager(cs, snapshot, &scheudleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+			coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheudleDuration, ruledOutNodes: newRuledOutNodes()}
+			coscheduling.binder = newGangBinder(16, coscheduling.bindPodToNode)
 			code, _ := coscheduling.Permit(context.Background(), framework.NewCycleState(), tt.pod, "test")
 			if code.Code() != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, code.Code())
@@ -338,6 +343,7 @@ func TestPostFilter(t *testing.T) {
 	cs := fakepgclientset.NewSimpleClientset()
 	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
 	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
 	pgInformerFactory.Start(ctx.Done())
 	pg := testutil.MakePG("pg", "ns1", 2, nil, nil)
 	pgInformer.Informer().GetStore().Add(pg)
@@ -402,8 +408,8 @@ func TestPostFilter(t *testing.T) {
 				mgrSnapShot = tt.snapshotSharedLister
 			}
 
-			pgMgr := core.NewPodGroupManager(cs, mgrSnapShot, &scheduleDuration, &deniedPGExpirationTime, pgInformer, podInformer)
-			coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheduleDuration}
+			pgMgr := core.NewPodGroupManager(cs, mgrSnapShot, &scheduleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+			coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheduleDuration, ruledOutNodes: newRuledOutNodes()}
 			_, code := coscheduling.PostFilter(context.Background(), cycleState, tt.pod, nodeStatusMap)
 			if code.Message() == "" != tt.expectedEmptyMsg {
 				t.Errorf("expectedEmptyMsg %v, got %v", tt.expectedEmptyMsg, code.Message() == "")
@@ -411,3 +417,211 @@ func TestPostFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	cs := fakepgclientset.NewSimpleClientset()
+	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
+	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
+	pgInformerFactory.Start(ctx.Done())
+
+	pgSelector := testutil.MakePG("pg-selector", "ns1", 1, nil, nil)
+	pgSelector.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+	pgAffinity := testutil.MakePG("pg-affinity", "ns1", 1, nil, nil)
+	pgAffinity.Spec.NodeAffinity = &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+		MatchExpressions: []v1.NodeSelectorRequirement{{Key: v1.LabelTopologyZone, Operator: v1.NodeSelectorOpIn, Values: []string{"z1"}}},
+	}}}
+	pgFree := testutil.MakePG("pg-free", "ns1", 1, nil, nil)
+	for _, pg := range []*v1alpha1.PodGroup{pgSelector, pgAffinity, pgFree} {
+		pgInformer.Informer().GetStore().Add(pg)
+	}
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	informerFactory.Start(ctx.Done())
+
+	scheduleDuration := 10 * time.Second
+	deniedPGExpirationTime := 3 * time.Second
+	existingPods, allNodes := testutil.MakeNodesAndPods(map[string]string{"test": "a"}, 1, 1)
+	snapshot := testutil.NewFakeSharedLister(existingPods, allNodes)
+	pgMgr := core.NewPodGroupManager(cs, snapshot, &scheduleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+	coscheduling := &Coscheduling{pgMgr: pgMgr, scheduleTimeout: &scheduleDuration, ruledOutNodes: newRuledOutNodes()}
+
+	gpuNode := st.MakeNode().Name("gpu").Label("pool", "gpu").Label(v1.LabelTopologyZone, "z2").Obj()
+	zoneNode := st.MakeNode().Name("zone").Label(v1.LabelTopologyZone, "z1").Obj()
+
+	tests := []struct {
+		name   string
+		pod    *v1.Pod
+		node   *v1.Node
+		wanted framework.Code
+	}{
+		{
+			name:   "pod does not belong to any pod group",
+			pod:    st.MakePod().Name("p1").Namespace("ns1").UID("p1").Obj(),
+			node:   zoneNode,
+			wanted: framework.Success,
+		},
+		{
+			name:   "pod group without node constraints",
+			pod:    st.MakePod().Name("p2").Namespace("ns1").UID("p2").Label(v1alpha1.PodGroupLabel, "pg-free").Obj(),
+			node:   zoneNode,
+			wanted: framework.Success,
+		},
+		{
+			name:   "node matches the node selector of the pod group",
+			pod:    st.MakePod().Name("p3").Namespace("ns1").UID("p3").Label(v1alpha1.PodGroupLabel, "pg-selector").Obj(),
+			node:   gpuNode,
+			wanted: framework.Success,
+		},
+		{
+			name:   "node does not match the node selector of the pod group",
+			pod:    st.MakePod().Name("p4").Namespace("ns1").UID("p4").Label(v1alpha1.PodGroupLabel, "pg-selector").Obj(),
+			node:   zoneNode,
+			wanted: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:   "node matches the node affinity of the pod group",
+			pod:    st.MakePod().Name("p5").Namespace("ns1").UID("p5").Label(v1alpha1.PodGroupLabel, "pg-affinity").Obj(),
+			node:   zoneNode,
+			wanted: framework.Success,
+		},
+		{
+			name:   "node does not match the node affinity of the pod group",
+			pod:    st.MakePod().Name("p6").Namespace("ns1").UID("p6").Label(v1alpha1.PodGroupLabel, "pg-affinity").Obj(),
+			node:   gpuNode,
+			wanted: framework.UnschedulableAndUnresolvable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podInformer.Informer().GetStore().Add(tt.pod)
+			defer podInformer.Informer().GetStore().Delete(tt.pod)
+			state := framework.NewCycleState()
+			if status := coscheduling.preFilter(ctx, state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.node)
+			if code := coscheduling.Filter(ctx, state, tt.pod, nodeInfo).Code(); code != tt.wanted {
+				t.Errorf("Want %v, got %v", tt.wanted, code)
+			}
+		})
+	}
+}
+
+func TestMemberAntiAffinity(t *testing.T) {
+	ctx := context.Background()
+	pgSpread := testutil.MakePG("pg-spread", "ns1", 2, nil, nil)
+	pgSpread.Spec.MemberAntiAffinity = &v1alpha1.PodGroupAntiAffinity{TopologyKey: "rack", Relaxation: v1alpha1.AntiAffinityRelaxationIfUnschedulable}
+	pgStrict := testutil.MakePG("pg-strict", "ns1", 2, nil, nil)
+	pgStrict.Spec.MemberAntiAffinity = &v1alpha1.PodGroupAntiAffinity{TopologyKey: "rack"}
+	cs := fakepgclientset.NewSimpleClientset(pgSpread, pgStrict)
+	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
+	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
+	for _, pg := range []*v1alpha1.PodGroup{pgSpread, pgStrict} {
+		pgInformer.Informer().GetStore().Add(pg)
+	}
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+
+	nodeA := st.MakeNode().Name("node-a").Label("rack", "a").Obj()
+	nodeB := st.MakeNode().Name("node-b").Label("rack", "b").Obj()
+	members := []*v1.Pod{
+		st.MakePod().Name("spread-0").Namespace("ns1").UID("spread-0").Label(v1alpha1.PodGroupLabel, "pg-spread").Node("node-a").Obj(),
+		st.MakePod().Name("strict-0").Namespace("ns1").UID("strict-0").Label(v1alpha1.PodGroupLabel, "pg-strict").Node("node-a").Obj(),
+	}
+	for _, pod := range members {
+		podInformer.Informer().GetStore().Add(pod)
+	}
+	snapshot := testutil.NewFakeSharedLister(members, []*v1.Node{nodeA, nodeB})
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	f, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduleDuration := 10 * time.Second
+	deniedPGExpirationTime := 3 * time.Second
+	pgMgr := core.NewPodGroupManager(cs, snapshot, &scheduleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+	coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheduleDuration, ruledOutNodes: newRuledOutNodes()}
+
+	// filter runs PreFilter then Filter and Score on both nodes, returning the codes and scores.
+	filter := func(pod *v1.Pod) ([]framework.Code, []int64) {
+		state := framework.NewCycleState()
+		if status := coscheduling.preFilter(ctx, state, pod); !status.IsSuccess() {
+			t.Fatalf("Unexpected PreFilter status: %v", status)
+		}
+		var codes []framework.Code
+		var scores []int64
+		for _, node := range []*v1.Node{nodeA, nodeB} {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			codes = append(codes, coscheduling.Filter(ctx, state, pod, nodeInfo).Code())
+			score, status := coscheduling.Score(ctx, state, pod, node.Name)
+			if !status.IsSuccess() {
+				t.Fatalf("Unexpected Score status: %v", status)
+			}
+			scores = append(scores, score)
+		}
+		return codes, scores
+	}
+	rejected := framework.NodeToStatusMap{"node-a": framework.NewStatus(framework.Unschedulable, ErrReasonPodGroupAntiAffinity)}
+
+	spread := st.MakePod().Name("spread-1").Namespace("ns1").UID("spread-1").Label(v1alpha1.PodGroupLabel, "pg-spread").Obj()
+	podInformer.Informer().GetStore().Add(spread)
+	codes, scores := filter(spread)
+	if codes[0] != framework.Unschedulable || codes[1] != framework.Success {
+		t.Errorf("expected the rack of a member to be rejected, got %v", codes)
+	}
+	if scores[0] != 0 || scores[1] != framework.MaxNodeScore {
+		t.Errorf("expected the rack of a member to be scored the lowest, got %v", scores)
+	}
+	if _, status := coscheduling.PostFilter(ctx, framework.NewCycleState(), spread, rejected); status.Message() != "PodGroup ns1/pg-spread relaxed its member anti-affinity" {
+		t.Errorf("expected the member anti-affinity to be relaxed, got %q", status.Message())
+	}
+	pg, err := cs.SchedulingV1alpha1().PodGroups("ns1").Get(ctx, "pg-spread", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) {
+		t.Errorf("expected the relaxation to be surfaced in the status, got %v", pg.Status.Conditions)
+	}
+	if meta.FindStatusCondition(pgSpread.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) != nil {
+		t.Errorf("expected the PodGroup of the lister untouched, got %v", pgSpread.Status.Conditions)
+	}
+	// The informer delivers the updated PodGroup.
+	pgInformer.Informer().GetStore().Update(pg)
+	codes, scores = filter(spread)
+	if codes[0] != framework.Success || codes[1] != framework.Success {
+		t.Errorf("expected the relaxed anti-affinity to only be preferred, got %v", codes)
+	}
+	if scores[0] != 0 || scores[1] != framework.MaxNodeScore {
+		t.Errorf("expected the rack of a member to still be scored the lowest, got %v", scores)
+	}
+
+	strict := st.MakePod().Name("strict-1").Namespace("ns1").UID("strict-1").Label(v1alpha1.PodGroupLabel, "pg-strict").Obj()
+	podInformer.Informer().GetStore().Add(strict)
+	if codes, _ := filter(strict); codes[0] != framework.Unschedulable {
+		t.Errorf("expected the rack of a member to be rejected, got %v", codes)
+	}
+	coscheduling.PostFilter(ctx, framework.NewCycleState(), strict, rejected)
+	if pg, err = cs.SchedulingV1alpha1().PodGroups("ns1").Get(ctx, "pg-strict", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(pg.Status.Conditions, v1alpha1.PodGroupAntiAffinityRelaxed) != nil {
+		t.Errorf("expected the member anti-affinity never relaxed to stay required, got %v", pg.Status.Conditions)
+	}
+}
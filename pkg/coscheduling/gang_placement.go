@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// gangPlacementRejectedKey is the key in CycleState marking a first member rejected at Permit
+	// because the rest of its gang would not fit, so that Unreserve does not deny the gang.
+	gangPlacementRejectedKey = Name + "GangPlacementRejected"
+
+	// ErrReasonGangPlacement is the status message of the nodes on which the first member of a
+	// PodGroup would leave the rest of the gang unschedulable.
+	ErrReasonGangPlacement = "node(s) would leave the rest of the PodGroup unschedulable"
+)
+
+// gangPlacementRejected marks the cycle of a pod rejected by the gang placement check.
+type gangPlacementRejected struct{}
+
+// Clone the marker, which holds nothing.
+func (g *gangPlacementRejected) Clone() framework.StateData {
+	return g
+}
+
+// checkGangPlacement evaluates the placement of the whole gang before permitting its first member
+// on nodeName: the pending members needed to reach minMember are placed greedily, each on the first
+// node passing the Filter plugins, over the snapshot along with the members placed before them.
+// It returns an error if not enough of them fit, so that the first member does not settle on a node
+// making the rest of the gang unschedulable.
+func (cs *Coscheduling) checkGangPlacement(ctx context.Context, pod *v1.Pod, nodeName string, pg *v1alpha1.PodGroup) error {
+	if !canRunPreFilterPlugins(cs.frameworkHandler) {
+		return nil
+	}
+	needed := int(pg.Spec.MinMember) - 1
+	pending, err := cs.pgMgr.GetPendingMembers(pod)
+	if err != nil {
+		return err
+	}
+	// The members not created yet tell nothing about the placement.
+	if len(pending) < needed {
+		needed = len(pending)
+	}
+	if needed <= 0 {
+		return nil
+	}
+	nodeInfos, err := cs.frameworkHandler.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return err
+	}
+
+	// The nodes the members are placed on are cloned, so that the snapshot is left untouched.
+	simulated := make(map[string]*framework.NodeInfo)
+	var placed []*v1.Pod
+	place := func(p *v1.Pod, nodeInfo *framework.NodeInfo) {
+		name := nodeInfo.Node().Name
+		if _, ok := simulated[name]; !ok {
+			simulated[name] = nodeInfo.Clone()
+		}
+		p = p.DeepCopy()
+		p.Spec.NodeName = name
+		simulated[name].AddPod(p)
+		placed = append(placed, p)
+	}
+	nodeInfo, err := cs.frameworkHandler.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return err
+	}
+	place(pod, nodeInfo)
+
+	for _, member := range pending {
+		if len(placed)-1 >= needed {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		state := framework.NewCycleState()
+		if s := runPreFilterPlugins(ctx, cs.frameworkHandler, state, member); !s.IsSuccess() {
+			continue
+		}
+		for _, p := range placed {
+			if s := cs.frameworkHandler.RunPreFilterExtensionAddPod(ctx, state, member, framework.NewPodInfo(p), simulated[p.Spec.NodeName]); !s.IsSuccess() {
+				return s.AsError()
+			}
+		}
+		for _, nodeInfo := range nodeInfos {
+			if nodeInfo.Node() == nil {
+				continue
+			}
+			if sim, ok := simulated[nodeInfo.Node().Name]; ok {
+				nodeInfo = sim
+			}
+			if cs.frameworkHandler.RunFilterPlugins(ctx, state, member, nodeInfo).Merge().IsSuccess() {
+				place(member, nodeInfo)
+				break
+			}
+		}
+	}
+	if fit := len(placed) - 1; fit < needed {
+		return fmt.Errorf("only %d of the %d other members of PodGroup %v needed would fit with pod %v on node %v",
+			fit, needed, pg.Name, pod.Name, nodeName)
+	}
+	return nil
+}
+
+// newRuledOutNodes returns the cache of the nodes ruled out for the first member of the gangs.
+func newRuledOutNodes() *util.BoundedCache {
+	return util.NewBoundedCache("coscheduling_ruled_out_nodes", maxRuledOutPodGroups, time.Minute)
+}
+
+// ruleOutNode rules out nodeName for the first member of the PodGroup pgFullName, until the gang
+// gets permitted or the schedule timeout elapses.
+func (cs *Coscheduling) ruleOutNode(pgFullName, nodeName string) {
+	nodes := sets.NewString(nodeName)
+	if v, ok := cs.ruledOutNodes.Get(pgFullName); ok {
+		nodes = nodes.Union(v.(sets.String))
+	}
+	cs.ruledOutNodes.Add(pgFullName, nodes, *cs.scheduleTimeout)
+}
+
+// getRuledOutNodes returns the nodes ruled out for the first member of the PodGroup pgFullName.
+func (cs *Coscheduling) getRuledOutNodes(pgFullName string) sets.String {
+	if v, ok := cs.ruledOutNodes.Get(pgFullName); ok {
+		return v.(sets.String)
+	}
+	return nil
+}
+
+// rejectGangPlacement rejects the first member of a gang at Permit when the rest of it would not fit.
+func (cs *Coscheduling) rejectGangPlacement(state *framework.CycleState, pod *v1.Pod, nodeName, pgFullName string, err error) *framework.Status {
+	klog.V(3).InfoS("Permit rejects the first member of the gang", "pod", klog.KObj(pod), "nodeName", nodeName, "podGroup", pgFullName, "reason", err)
+	cs.ruleOutNode(pgFullName, nodeName)
+	state.Write(gangPlacementRejectedKey, &gangPlacementRejected{})
+	return framework.NewStatus(framework.Unschedulable, err.Error())
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling/core"
+	fakepgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// onePodPerNode is a filter plugin rejecting the nodes running a pod already.
+type onePodPerNode struct{}
+
+func (p *onePodPerNode) Name() string {
+	return "OnePodPerNode"
+}
+
+func (p *onePodPerNode) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if len(nodeInfo.Pods) != 0 {
+		return framework.NewStatus(framework.Unschedulable, "node running a pod")
+	}
+	return nil
+}
+
+func TestGangPlacement(t *testing.T) {
+	ctx := context.Background()
+	pg := testutil.MakePG("pg", "ns1", 2, nil, nil)
+	cs := fakepgclientset.NewSimpleClientset(pg)
+	pgInformerFactory := pgformers.NewSharedInformerFactory(cs, 0)
+	pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+	policyInformer := pgInformerFactory.Scheduling().V1alpha1().CoschedulingPolicies()
+	pgInformer.Informer().GetStore().Add(pg)
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	first := st.MakePod().Name("p1").Namespace("ns1").UID("p1").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	second := st.MakePod().Name("p2").Namespace("ns1").UID("p2").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	for _, pod := range []*v1.Pod{first, second} {
+		podInformer.Informer().GetStore().Add(pod)
+	}
+
+	nodeA := st.MakeNode().Name("node-a").Obj()
+	nodeB := st.MakeNode().Name("node-b").Obj()
+	nodeC := st.MakeNode().Name("node-c").Obj()
+	busy := st.MakePod().Name("busy").Namespace("ns2").UID("busy").Node("node-b").Obj()
+
+	newCoscheduling := func(nodes ...*v1.Node) *Coscheduling {
+		snapshot := testutil.NewFakeSharedLister([]*v1.Pod{busy}, nodes)
+		registeredPlugins := []st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterFilterPlugin("OnePodPerNode", func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+				return &onePodPerNode{}, nil
+			}),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		}
+		f, err := st.NewFramework(registeredPlugins, "",
+			frameworkruntime.WithClientSet(fakeClient),
+			frameworkruntime.WithInformerFactory(informerFactory),
+			frameworkruntime.WithSnapshotSharedLister(snapshot),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scheduleDuration := 10 * time.Second
+		deniedPGExpirationTime := 3 * time.Second
+		pgMgr := core.NewPodGroupManager(cs, snapshot, &scheduleDuration, &deniedPGExpirationTime, pgInformer, podInformer, policyInformer)
+		coscheduling := &Coscheduling{pgMgr: pgMgr, frameworkHandler: f, scheduleTimeout: &scheduleDuration, ruledOutNodes: newRuledOutNodes()}
+		coscheduling.binder = newGangBinder(16, coscheduling.bindPodToNode)
+		return coscheduling
+	}
+
+	t.Run("rest of the gang fits", func(t *testing.T) {
+		coscheduling := newCoscheduling(nodeA, nodeB, nodeC)
+		if status, _ := coscheduling.Permit(ctx, framework.NewCycleState(), first, "node-a"); status.Code() != framework.Wait {
+			t.Errorf("expected the first member to wait, got %v", status)
+		}
+	})
+
+	t.Run("rest of the gang does not fit", func(t *testing.T) {
+		coscheduling := newCoscheduling(nodeA, nodeB)
+		state := framework.NewCycleState()
+		status, _ := coscheduling.Permit(ctx, state, first, "node-a")
+		if status.Code() != framework.Unschedulable {
+			t.Fatalf("expected the first member to be rejected, got %v", status)
+		}
+		coscheduling.Unreserve(ctx, state, first, "node-a")
+		if denied := coscheduling.pgMgr.GetDeniedPodGroups(); len(denied) != 0 {
+			t.Errorf("expected the gang not to be denied, got %v", denied)
+		}
+
+		state = framework.NewCycleState()
+		if s := coscheduling.preFilter(ctx, state, first); !s.IsSuccess() {
+			t.Fatalf("Unexpected PreFilter status: %v", s)
+		}
+		for node, want := range map[*v1.Node]framework.Code{nodeA: framework.Unschedulable, nodeB: framework.Success} {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			if s := coscheduling.Filter(ctx, state, first, nodeInfo); s.Code() != want {
+				t.Errorf("expected %v on %s, got %v", want, node.Name, s)
+			}
+		}
+	})
+}
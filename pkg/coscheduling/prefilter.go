@@ -0,0 +1,50 @@
+//go:build !k8s_1_24
+// +build !k8s_1_24
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// PreFilter invoked at the prefilter extension point, with the signature of the scheduler
+// framework up to v1.23.
+func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	return cs.preFilter(ctx, state, pod)
+}
+
+// preFilterRunner runs the PreFilter plugins of a profile. It is implemented by the framework of
+// the scheduler, though not part of framework.Handle.
+type preFilterRunner interface {
+	RunPreFilterPlugins(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status
+}
+
+// canRunPreFilterPlugins tells whether the PreFilter plugins of the profile can be run for another pod.
+func canRunPreFilterPlugins(handle framework.Handle) bool {
+	_, ok := handle.(preFilterRunner)
+	return ok
+}
+
+// runPreFilterPlugins runs the PreFilter plugins of the profile for pod.
+func runPreFilterPlugins(ctx context.Context, handle framework.Handle, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	return handle.(preFilterRunner).RunPreFilterPlugins(ctx, state, pod)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	waitingPodsGauge = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "coscheduling_waiting_pods",
+			Help:           "Number of pods waiting at Permit for the rest of their gang, as of the last pod made to wait.",
+			StabilityLevel: metrics.ALPHA,
+		})
+	waitingGangRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "coscheduling_waiting_gang_rejections_total",
+			Help:           "Number of waiting gangs rejected at Permit to keep the waiting pods within maxWaitingPods, by namespace.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"namespace"})
+
+	registerMetrics sync.Once
+)
+
+// ReasonWaitingGangRejected is the reason of the events of the pods of the gangs rejected to keep the
+// waiting pods within maxWaitingPods.
+const ReasonWaitingGangRejected = "WaitingGangRejected"
+
+// registerWaitingPodsMetrics registers the metrics of the pods waiting at Permit, once.
+func registerWaitingPodsMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(waitingPodsGauge, waitingGangRejections)
+	})
+}
+
+// waitingGang is a gang with pods waiting at Permit.
+type waitingGang struct {
+	fullName string
+	priority int32
+	// creation is the creation time of the PodGroup.
+	creation time.Time
+	pods     []*v1.Pod
+}
+
+// lessValuable tells whether gang a is sorted after gang b by the queue: of lower priority, else
+// created later, else of greater name.
+func (a *waitingGang) lessValuable(b *waitingGang) bool {
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if !a.creation.Equal(b.creation) {
+		return a.creation.After(b.creation)
+	}
+	return a.fullName > b.fullName
+}
+
+// gangsToReject returns the gangs to reject, least valuable first, so that one more pod of the gang
+// own can wait along with the waiting pods of gangs without exceeding maxWaitingPods. own is the last
+// one if the gang of the pod has to be rejected itself.
+func gangsToReject(gangs []*waitingGang, own *waitingGang, maxWaitingPods int64) []*waitingGang {
+	candidates := append([]*waitingGang{}, gangs...)
+	waiting := 0
+	found := false
+	for _, g := range gangs {
+		waiting += len(g.pods)
+		found = found || g == own
+	}
+	if !found {
+		candidates = append(candidates, own)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lessValuable(candidates[j])
+	})
+	var victims []*waitingGang
+	for _, g := range candidates {
+		if int64(waiting+1) <= maxWaitingPods {
+			break
+		}
+		victims = append(victims, g)
+		if g == own {
+			break
+		}
+		waiting -= len(g.pods)
+	}
+	return victims
+}
+
+// admitWaitingPod makes room for pod to wait at Permit when the waiting pods are capped at maxWaitingPods,
+// by rejecting the least valuable waiting gangs, the way the queue sorts them. It returns an Unschedulable
+// status if the gang of pod is the least valuable one, rejected itself, nil otherwise.
+func (cs *Coscheduling) admitWaitingPod(pod *v1.Pod, pgFullName string) *framework.Status {
+	if cs.maxWaitingPods <= 0 {
+		return nil
+	}
+	byName := make(map[string]*waitingGang)
+	var gangs []*waitingGang
+	waiting := 0
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		waiting++
+		p := waitingPod.GetPod()
+		name := cs.pgMgr.GetPodGroupFullName(p)
+		if len(name) == 0 {
+			// Waiting for another plugin, the pod cannot be rejected in favor of a gang.
+			return
+		}
+		g, ok := byName[name]
+		if !ok {
+			g = cs.newWaitingGang(p, name)
+			byName[name] = g
+			gangs = append(gangs, g)
+		}
+		g.pods = append(g.pods, p)
+	})
+	waitingPodsGauge.Set(float64(waiting))
+	if int64(waiting) < cs.maxWaitingPods {
+		return nil
+	}
+
+	own, ok := byName[pgFullName]
+	if !ok {
+		own = cs.newWaitingGang(pod, pgFullName)
+	}
+	// The pods of other plugins count against the cap but cannot be rejected.
+	others := int64(waiting)
+	for _, g := range gangs {
+		others -= int64(len(g.pods))
+	}
+	for _, g := range gangsToReject(gangs, own, cs.maxWaitingPods-others) {
+		message := fmt.Sprintf("PodGroup %v rejected at Permit to keep the waiting pods within %d, in favor of PodGroup %v",
+			g.fullName, cs.maxWaitingPods, pgFullName)
+		if g == own {
+			message = fmt.Sprintf("PodGroup %v rejected at Permit to keep the waiting pods within %d, the other waiting gangs being more valuable",
+				pgFullName, cs.maxWaitingPods)
+		}
+		cs.rejectWaitingGang(g, message)
+		if g == own {
+			cs.recordWaitingGangRejected(pod, message)
+			return framework.NewStatus(framework.Unschedulable, message)
+		}
+	}
+	return nil
+}
+
+// newWaitingGang returns the waiting gang pgFullName of pod, without any waiting pod.
+func (cs *Coscheduling) newWaitingGang(pod *v1.Pod, pgFullName string) *waitingGang {
+	return &waitingGang{
+		fullName: pgFullName,
+		priority: corev1helpers.PodPriority(pod),
+		creation: cs.pgMgr.GetCreationTimestamp(pod, pod.CreationTimestamp.Time),
+	}
+}
+
+// rejectWaitingGang rejects the waiting pods of a gang and denies it, recording an event on every pod.
+func (cs *Coscheduling) rejectWaitingGang(g *waitingGang, message string) {
+	klog.InfoS("Rejecting a waiting gang to keep the pods waiting at Permit within the limit", "podGroup", g.fullName,
+		"waitingPods", len(g.pods), "maxWaitingPods", cs.maxWaitingPods)
+	for _, p := range g.pods {
+		if waitingPod := cs.frameworkHandler.GetWaitingPod(p.UID); waitingPod != nil {
+			waitingPod.Reject(cs.Name(), message)
+		}
+		cs.recordWaitingGangRejected(p, message)
+	}
+	cs.pgMgr.AddDeniedPodGroup(g.fullName)
+	cs.pgMgr.DeletePermittedPodGroup(g.fullName)
+	waitingGangRejections.WithLabelValues(strings.SplitN(g.fullName, "/", 2)[0]).Inc()
+}
+
+// recordWaitingGangRejected records an event on a pod of a gang rejected to keep the waiting pods within the limit.
+func (cs *Coscheduling) recordWaitingGangRejected(pod *v1.Pod, message string) {
+	if recorder := cs.frameworkHandler.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeWarning, ReasonWaitingGangRejected, "Permit", message)
+	}
+}
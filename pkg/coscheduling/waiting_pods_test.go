@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGangsToReject(t *testing.T) {
+	now := time.Now()
+	makeGang := func(name string, priority int32, age time.Duration, waiting int) *waitingGang {
+		return &waitingGang{fullName: "ns1/" + name, priority: priority, creation: now.Add(-age), pods: make([]*v1.Pod, waiting)}
+	}
+	tests := []struct {
+		name           string
+		gangs          []*waitingGang
+		own            *waitingGang
+		maxWaitingPods int64
+		expected       []string
+	}{
+		{
+			name:           "room left",
+			gangs:          []*waitingGang{makeGang("high", 10, time.Hour, 2)},
+			own:            makeGang("new", 0, 0, 0),
+			maxWaitingPods: 3,
+		},
+		{
+			name:           "lowest priority gang rejected",
+			gangs:          []*waitingGang{makeGang("high", 10, time.Hour, 2), makeGang("low", 0, time.Hour, 2)},
+			own:            makeGang("new", 5, 0, 0),
+			maxWaitingPods: 4,
+			expected:       []string{"ns1/low"},
+		},
+		{
+			name:           "youngest gang rejected among equal priorities",
+			gangs:          []*waitingGang{makeGang("young", 0, time.Minute, 2), makeGang("old", 0, time.Hour, 2)},
+			own:            makeGang("new", 0, 2*time.Hour, 0),
+			maxWaitingPods: 4,
+			expected:       []string{"ns1/young"},
+		},
+		{
+			name:           "several gangs rejected",
+			gangs:          []*waitingGang{makeGang("a", 1, time.Hour, 1), makeGang("b", 2, time.Hour, 1), makeGang("c", 3, time.Hour, 2)},
+			own:            makeGang("new", 5, 0, 0),
+			maxWaitingPods: 3,
+			expected:       []string{"ns1/a", "ns1/b"},
+		},
+		{
+			name:           "own gang rejected when the least valuable",
+			gangs:          []*waitingGang{makeGang("high", 10, time.Hour, 2)},
+			own:            makeGang("new", 0, 0, 0),
+			maxWaitingPods: 2,
+			expected:       []string{"ns1/new"},
+		},
+		{
+			// The own gang, the last one, has a pod waiting already.
+			name:           "own gang with waiting pods rejected",
+			gangs:          []*waitingGang{makeGang("high", 10, time.Hour, 1), makeGang("own", 0, time.Hour, 1)},
+			maxWaitingPods: 2,
+			expected:       []string{"ns1/own"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			own := tt.own
+			if own == nil {
+				own = tt.gangs[len(tt.gangs)-1]
+			}
+			var got []string
+			for _, g := range gangsToReject(tt.gangs, own, tt.maxWaitingPods) {
+				got = append(got, g.fullName)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
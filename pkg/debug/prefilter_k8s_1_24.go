@@ -0,0 +1,36 @@
+//go:build k8s_1_24
+// +build k8s_1_24
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// PreFilter delegates to the wrapped plugin, with the signature of the scheduler framework since
+// v1.24.
+func (s *scoreRecorder) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	if pl, ok := s.ScorePlugin.(framework.PreFilterPlugin); ok {
+		return pl.PreFilter(ctx, state, pod)
+	}
+	return nil, s.notImplemented("PreFilter")
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+// ScoresPath is the path the scores handler is served at.
+const ScoresPath = "/debug/plugins/scores"
+
+// ScoredCycle holds the scores given by the plugins to the candidate nodes of a scheduling cycle.
+type ScoredCycle struct {
+	Timestamp time.Time `json:"timestamp"`
+	Profile   string    `json:"profile,omitempty"`
+	// Pod is the namespace/name of the pod scheduled.
+	Pod string `json:"pod"`
+	// Scores maps a plugin name to the normalized score of every candidate node, before the
+	// weight of the plugin in the profile is applied.
+	Scores map[string]map[string]int64 `json:"scores"`
+}
+
+// scoredCycle is a ScoredCycle along with the state of its scheduling cycle.
+type scoredCycle struct {
+	ScoredCycle
+	state *framework.CycleState
+}
+
+var scores = struct {
+	sync.Mutex
+	maxCycles int
+	// cycles holds the last maxCycles scored cycles, oldest first.
+	cycles  []*scoredCycle
+	byState map[*framework.CycleState]*scoredCycle
+}{byState: make(map[*framework.CycleState]*scoredCycle)}
+
+// SetScoredCycles sets the number of the last scheduling cycles whose scores are kept, none if 0.
+// It must be called before the plugins wrapped by WrapScorePlugins are instantiated to record any.
+func SetScoredCycles(n int) {
+	scores.Lock()
+	defer scores.Unlock()
+	scores.maxCycles = n
+	for len(scores.cycles) > n {
+		evictOldestCycle()
+	}
+}
+
+// evictOldestCycle drops the oldest scored cycle. Must be called with scores locked.
+func evictOldestCycle() {
+	delete(scores.byState, scores.cycles[0].state)
+	scores.cycles = scores.cycles[1:]
+}
+
+// ScoredCycles returns the last scored cycles, oldest first.
+func ScoredCycles() []ScoredCycle {
+	scores.Lock()
+	defer scores.Unlock()
+	cycles := make([]ScoredCycle, 0, len(scores.cycles))
+	for _, c := range scores.cycles {
+		cp := c.ScoredCycle
+		cp.Scores = make(map[string]map[string]int64, len(c.Scores))
+		for plugin, nodeScores := range c.Scores {
+			cp.Scores[plugin] = make(map[string]int64, len(nodeScores))
+			for node, score := range nodeScores {
+				cp.Scores[plugin][node] = score
+			}
+		}
+		cycles = append(cycles, cp)
+	}
+	return cycles
+}
+
+// recordScores adds the normalized scores of a plugin to the scored cycle of state.
+func recordScores(state *framework.CycleState, profile string, pod *v1.Pod, plugin string, nodeScores framework.NodeScoreList) {
+	scores.Lock()
+	defer scores.Unlock()
+	if scores.maxCycles == 0 {
+		return
+	}
+	c, ok := scores.byState[state]
+	if !ok {
+		c = &scoredCycle{
+			ScoredCycle: ScoredCycle{
+				Timestamp: time.Now(),
+				Profile:   profile,
+				Pod:       pod.Namespace + "/" + pod.Name,
+				Scores:    make(map[string]map[string]int64),
+			},
+			state: state,
+		}
+		if len(scores.cycles) == scores.maxCycles {
+			evictOldestCycle()
+		}
+		scores.cycles = append(scores.cycles, c)
+		scores.byState[state] = c
+	}
+	m := make(map[string]int64, len(nodeScores))
+	for _, s := range nodeScores {
+		m[s.Name] = s.Score
+	}
+	c.Scores[plugin] = m
+}
+
+// ScoresHandler serves the last scored cycles as JSON.
+func ScoresHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(ScoredCycles()); err != nil {
+			klog.ErrorS(err, "Failed to encode plugins scores")
+		}
+	})
+}
+
+// WrapScorePlugins returns a copy of r whose score plugins record their normalized scores, as long
+// as SetScoredCycles was given a positive number of cycles when they are instantiated. Only the
+// plugins of r are recorded, not the in-tree ones of kube-scheduler.
+func WrapScorePlugins(r frameworkruntime.Registry) frameworkruntime.Registry {
+	wrapped := make(frameworkruntime.Registry, len(r))
+	for name, factory := range r {
+		factory := factory
+		wrapped[name] = func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+			p, err := factory(obj, handle)
+			if err != nil {
+				return nil, err
+			}
+			scores.Lock()
+			enabled := scores.maxCycles > 0
+			scores.Unlock()
+			sp, ok := p.(framework.ScorePlugin)
+			if !enabled || !ok {
+				return p, nil
+			}
+			var profile string
+			if f, ok := handle.(interface{ ProfileName() string }); ok {
+				profile = f.ProfileName()
+			}
+			return &scoreRecorder{ScorePlugin: sp, profile: profile}, nil
+		}
+	}
+	return wrapped
+}
+
+// allClusterEvents are the events the framework registers the plugins not implementing
+// EnqueueExtensions to.
+var allClusterEvents = []framework.ClusterEvent{
+	{Resource: framework.Pod, ActionType: framework.All},
+	{Resource: framework.Node, ActionType: framework.All},
+	{Resource: framework.CSINode, ActionType: framework.All},
+	{Resource: framework.PersistentVolume, ActionType: framework.All},
+	{Resource: framework.PersistentVolumeClaim, ActionType: framework.All},
+	{Resource: framework.StorageClass, ActionType: framework.All},
+}
+
+// scoreRecorder wraps a score plugin to record its normalized scores. As the framework finds the
+// extension points of a plugin by type assertion, it implements all of them, delegating to the
+// wrapped plugin. Only the ones the plugin is enabled at in the profile are called.
+type scoreRecorder struct {
+	framework.ScorePlugin
+	profile string
+}
+
+var _ framework.QueueSortPlugin = &scoreRecorder{}
+var _ framework.EnqueueExtensions = &scoreRecorder{}
+var _ framework.PreFilterPlugin = &scoreRecorder{}
+var _ framework.FilterPlugin = &scoreRecorder{}
+var _ framework.PostFilterPlugin = &scoreRecorder{}
+var _ framework.PreScorePlugin = &scoreRecorder{}
+var _ framework.ScorePlugin = &scoreRecorder{}
+var _ framework.ReservePlugin = &scoreRecorder{}
+var _ framework.PermitPlugin = &scoreRecorder{}
+var _ framework.PreBindPlugin = &scoreRecorder{}
+var _ framework.BindPlugin = &scoreRecorder{}
+var _ framework.PostBindPlugin = &scoreRecorder{}
+
+func (s *scoreRecorder) notImplemented(extensionPoint string) *framework.Status {
+	return framework.AsStatus(fmt.Errorf("plugin %q does not implement %s", s.Name(), extensionPoint))
+}
+
+// ScoreExtensions returns the recorder, so that the framework runs NormalizeScore for all the plugins.
+func (s *scoreRecorder) ScoreExtensions() framework.ScoreExtensions {
+	return s
+}
+
+// NormalizeScore normalizes the scores with the wrapped plugin, if it does, and records them.
+func (s *scoreRecorder) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeScores framework.NodeScoreList) *framework.Status {
+	if ext := s.ScorePlugin.ScoreExtensions(); ext != nil {
+		if status := ext.NormalizeScore(ctx, state, pod, nodeScores); !status.IsSuccess() {
+			return status
+		}
+	}
+	recordScores(state, s.profile, pod, s.Name(), nodeScores)
+	return nil
+}
+
+func (s *scoreRecorder) Less(p1, p2 *framework.QueuedPodInfo) bool {
+	if pl, ok := s.ScorePlugin.(framework.QueueSortPlugin); ok {
+		return pl.Less(p1, p2)
+	}
+	return false
+}
+
+func (s *scoreRecorder) EventsToRegister() []framework.ClusterEvent {
+	if pl, ok := s.ScorePlugin.(framework.EnqueueExtensions); ok {
+		return pl.EventsToRegister()
+	}
+	return allClusterEvents
+}
+
+func (s *scoreRecorder) PreFilterExtensions() framework.PreFilterExtensions {
+	if pl, ok := s.ScorePlugin.(framework.PreFilterPlugin); ok {
+		return pl.PreFilterExtensions()
+	}
+	return nil
+}
+
+func (s *scoreRecorder) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if pl, ok := s.ScorePlugin.(framework.FilterPlugin); ok {
+		return pl.Filter(ctx, state, pod, nodeInfo)
+	}
+	return s.notImplemented("Filter")
+}
+
+func (s *scoreRecorder) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if pl, ok := s.ScorePlugin.(framework.PostFilterPlugin); ok {
+		return pl.PostFilter(ctx, state, pod, m)
+	}
+	return nil, s.notImplemented("PostFilter")
+}
+
+func (s *scoreRecorder) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	if pl, ok := s.ScorePlugin.(framework.PreScorePlugin); ok {
+		return pl.PreScore(ctx, state, pod, nodes)
+	}
+	return s.notImplemented("PreScore")
+}
+
+func (s *scoreRecorder) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if pl, ok := s.ScorePlugin.(framework.ReservePlugin); ok {
+		return pl.Reserve(ctx, state, pod, nodeName)
+	}
+	return s.notImplemented("Reserve")
+}
+
+func (s *scoreRecorder) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if pl, ok := s.ScorePlugin.(framework.ReservePlugin); ok {
+		pl.Unreserve(ctx, state, pod, nodeName)
+	}
+}
+
+func (s *scoreRecorder) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	if pl, ok := s.ScorePlugin.(framework.PermitPlugin); ok {
+		return pl.Permit(ctx, state, pod, nodeName)
+	}
+	return s.notImplemented("Permit"), 0
+}
+
+func (s *scoreRecorder) PreBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if pl, ok := s.ScorePlugin.(framework.PreBindPlugin); ok {
+		return pl.PreBind(ctx, state, pod, nodeName)
+	}
+	return s.notImplemented("PreBind")
+}
+
+func (s *scoreRecorder) Bind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if pl, ok := s.ScorePlugin.(framework.BindPlugin); ok {
+		return pl.Bind(ctx, state, pod, nodeName)
+	}
+	return s.notImplemented("Bind")
+}
+
+func (s *scoreRecorder) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if pl, ok := s.ScorePlugin.(framework.PostBindPlugin); ok {
+		pl.PostBind(ctx, state, pod, nodeName)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+// halvingScorer scores nodes 100 and halves the scores at NormalizeScore.
+type halvingScorer struct{}
+
+func (halvingScorer) Name() string { return "Halving" }
+
+func (halvingScorer) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	return 100, nil
+}
+
+func (h halvingScorer) ScoreExtensions() framework.ScoreExtensions { return h }
+
+func (halvingScorer) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	for i := range scores {
+		scores[i].Score /= 2
+	}
+	return nil
+}
+
+func TestWrapScorePlugins(t *testing.T) {
+	r := WrapScorePlugins(frameworkruntime.Registry{
+		"Halving": func(runtime.Object, framework.Handle) (framework.Plugin, error) { return halvingScorer{}, nil },
+	})
+	defer SetScoredCycles(0)
+
+	if p, _ := r["Halving"](nil, nil); !reflect.DeepEqual(p, halvingScorer{}) {
+		t.Fatalf("expected the plugin to be left as is while no cycle is recorded, got %T", p)
+	}
+
+	SetScoredCycles(2)
+	p, err := r["Halving"](nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := p.(framework.ScorePlugin)
+	ctx := context.Background()
+	for _, name := range []string{"p1", "p2", "p3"} {
+		scores := framework.NodeScoreList{{Name: "n1", Score: 100}, {Name: "n2", Score: 40}}
+		if status := pl.ScoreExtensions().NormalizeScore(ctx, framework.NewCycleState(), st.MakePod().Namespace("default").Name(name).Obj(), scores); !status.IsSuccess() {
+			t.Fatal(status.AsError())
+		}
+	}
+	if status := pl.(framework.FilterPlugin).Filter(ctx, framework.NewCycleState(), &v1.Pod{}, nil); status.IsSuccess() {
+		t.Errorf("expected an extension point the plugin does not implement to fail")
+	}
+
+	rec := httptest.NewRecorder()
+	ScoresHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ScoresPath, nil))
+	var got []ScoredCycle
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	// Only the last 2 cycles are kept.
+	if len(got) != 2 || got[0].Pod != "default/p2" || got[1].Pod != "default/p3" {
+		t.Fatalf("expected the cycles of default/p2 and default/p3, got %v", got)
+	}
+	expected := map[string]map[string]int64{"Halving": {"n1": 50, "n2": 20}}
+	if !reflect.DeepEqual(expected, got[1].Scores) {
+		t.Errorf("expected the normalized scores %v, got %v", expected, got[1].Scores)
+	}
+}
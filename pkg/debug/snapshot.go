@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// SnapshotPath is the path the snapshot handler is served at.
+const SnapshotPath = "/debug/plugins/snapshot"
+
+// Dumper is implemented by the plugins exposing their in-memory state in snapshots.
+// Dump must only return sanitized data (names, counters, quantities), never pod specs
+// or any user payload, as snapshots are meant to be attached to bug reports.
+type Dumper interface {
+	Dump() interface{}
+}
+
+// Snapshot is the in-memory state of all the registered plugins.
+type Snapshot struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Plugins maps a plugin name to the state of its instances, one per profile.
+	Plugins map[string][]interface{} `json:"plugins"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	dumpers map[string][]Dumper
+}{dumpers: make(map[string][]Dumper)}
+
+// Register adds the given plugin instance to the snapshots.
+func Register(name string, d Dumper) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.dumpers[name] = append(registry.dumpers[name], d)
+}
+
+// TakeSnapshot returns the current state of all the registered plugins.
+func TakeSnapshot() *Snapshot {
+	registry.RLock()
+	defer registry.RUnlock()
+	s := &Snapshot{
+		Timestamp: time.Now(),
+		Plugins:   make(map[string][]interface{}, len(registry.dumpers)),
+	}
+	for name, dumpers := range registry.dumpers {
+		for _, d := range dumpers {
+			s.Plugins[name] = append(s.Plugins[name], d.Dump())
+		}
+	}
+	return s
+}
+
+// Handler serves the snapshot of all the registered plugins as JSON.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(TakeSnapshot()); err != nil {
+			klog.ErrorS(err, "Failed to encode plugins snapshot")
+		}
+	})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type fakeDumper map[string]int
+
+func (d fakeDumper) Dump() interface{} {
+	return d
+}
+
+func TestHandler(t *testing.T) {
+	Register("Foo", fakeDumper{"a": 1})
+	Register("Foo", fakeDumper{"b": 2})
+	Register("Bar", fakeDumper{})
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SnapshotPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %v, got %v", http.StatusOK, rec.Code)
+	}
+
+	var got struct {
+		Plugins map[string][]map[string]int `json:"plugins"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]map[string]int{
+		"Foo": {{"a": 1}, {"b": 2}},
+		"Bar": {{}},
+	}
+	if !reflect.DeepEqual(expected, got.Plugins) {
+		t.Errorf("expected %v, got %v", expected, got.Plugins)
+	}
+}
@@ -32,18 +32,46 @@ func (c *FakeSchedulingV1alpha1) AppGroups(namespace string) v1alpha1.AppGroupIn
 	return &FakeAppGroups{c, namespace}
 }
 
+func (c *FakeSchedulingV1alpha1) CoschedulingPolicies(namespace string) v1alpha1.CoschedulingPolicyInterface {
+	return &FakeCoschedulingPolicies{c, namespace}
+}
+
 func (c *FakeSchedulingV1alpha1) ElasticQuotas(namespace string) v1alpha1.ElasticQuotaInterface {
 	return &FakeElasticQuotas{c, namespace}
 }
 
+func (c *FakeSchedulingV1alpha1) InterferencePolicies() v1alpha1.InterferencePolicyInterface {
+	return &FakeInterferencePolicies{c}
+}
+
+func (c *FakeSchedulingV1alpha1) LinkHotspots(namespace string) v1alpha1.LinkHotspotInterface {
+	return &FakeLinkHotspots{c, namespace}
+}
+
+func (c *FakeSchedulingV1alpha1) NetworkLatencies(namespace string) v1alpha1.NetworkLatencyInterface {
+	return &FakeNetworkLatencies{c, namespace}
+}
+
 func (c *FakeSchedulingV1alpha1) NetworkTopologies(namespace string) v1alpha1.NetworkTopologyInterface {
 	return &FakeNetworkTopologies{c, namespace}
 }
 
+func (c *FakeSchedulingV1alpha1) NodeBandwidthProfiles() v1alpha1.NodeBandwidthProfileInterface {
+	return &FakeNodeBandwidthProfiles{c}
+}
+
 func (c *FakeSchedulingV1alpha1) PodGroups(namespace string) v1alpha1.PodGroupInterface {
 	return &FakePodGroups{c, namespace}
 }
 
+func (c *FakeSchedulingV1alpha1) RegistryMirrors() v1alpha1.RegistryMirrorInterface {
+	return &FakeRegistryMirrors{c}
+}
+
+func (c *FakeSchedulingV1alpha1) VirtualNodeProfiles() v1alpha1.VirtualNodeProfileInterface {
+	return &FakeVirtualNodeProfiles{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSchedulingV1alpha1) RESTClient() rest.Interface {
@@ -20,8 +20,22 @@ package v1alpha1
 
 type AppGroupExpansion interface{}
 
+type CoschedulingPolicyExpansion interface{}
+
 type ElasticQuotaExpansion interface{}
 
+type InterferencePolicyExpansion interface{}
+
+type LinkHotspotExpansion interface{}
+
+type NetworkLatencyExpansion interface{}
+
 type NetworkTopologyExpansion interface{}
 
+type NodeBandwidthProfileExpansion interface{}
+
 type PodGroupExpansion interface{}
+
+type RegistryMirrorExpansion interface{}
+
+type VirtualNodeProfileExpansion interface{}
@@ -29,9 +29,16 @@ import (
 type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AppGroupsGetter
+	CoschedulingPoliciesGetter
 	ElasticQuotasGetter
+	InterferencePoliciesGetter
+	LinkHotspotsGetter
+	NetworkLatenciesGetter
 	NetworkTopologiesGetter
+	NodeBandwidthProfilesGetter
 	PodGroupsGetter
+	RegistryMirrorsGetter
+	VirtualNodeProfilesGetter
 }
 
 // SchedulingV1alpha1Client is used to interact with features provided by the scheduling.sigs.k8s.io group.
@@ -43,18 +50,46 @@ func (c *SchedulingV1alpha1Client) AppGroups(namespace string) AppGroupInterface
 	return newAppGroups(c, namespace)
 }
 
+func (c *SchedulingV1alpha1Client) CoschedulingPolicies(namespace string) CoschedulingPolicyInterface {
+	return newCoschedulingPolicies(c, namespace)
+}
+
 func (c *SchedulingV1alpha1Client) ElasticQuotas(namespace string) ElasticQuotaInterface {
 	return newElasticQuotas(c, namespace)
 }
 
+func (c *SchedulingV1alpha1Client) InterferencePolicies() InterferencePolicyInterface {
+	return newInterferencePolicies(c)
+}
+
+func (c *SchedulingV1alpha1Client) LinkHotspots(namespace string) LinkHotspotInterface {
+	return newLinkHotspots(c, namespace)
+}
+
+func (c *SchedulingV1alpha1Client) NetworkLatencies(namespace string) NetworkLatencyInterface {
+	return newNetworkLatencies(c, namespace)
+}
+
 func (c *SchedulingV1alpha1Client) NetworkTopologies(namespace string) NetworkTopologyInterface {
 	return newNetworkTopologies(c, namespace)
 }
 
+func (c *SchedulingV1alpha1Client) NodeBandwidthProfiles() NodeBandwidthProfileInterface {
+	return newNodeBandwidthProfiles(c)
+}
+
 func (c *SchedulingV1alpha1Client) PodGroups(namespace string) PodGroupInterface {
 	return newPodGroups(c, namespace)
 }
 
+func (c *SchedulingV1alpha1Client) RegistryMirrors() RegistryMirrorInterface {
+	return newRegistryMirrors(c)
+}
+
+func (c *SchedulingV1alpha1Client) VirtualNodeProfiles() VirtualNodeProfileInterface {
+	return newVirtualNodeProfiles(c)
+}
+
 // NewForConfig creates a new SchedulingV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
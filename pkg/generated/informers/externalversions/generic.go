@@ -55,12 +55,26 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=scheduling.sigs.k8s.io, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("appgroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().AppGroups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("coschedulingpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().CoschedulingPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("elasticquotas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().ElasticQuotas().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("interferencepolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().InterferencePolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("linkhotspots"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().LinkHotspots().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("networklatencies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().NetworkLatencies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("networktopologies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().NetworkTopologies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("nodebandwidthprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().NodeBandwidthProfiles().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("podgroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().PodGroups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("registrymirrors"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().RegistryMirrors().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualnodeprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().VirtualNodeProfiles().Informer()}, nil
 
 	}
 
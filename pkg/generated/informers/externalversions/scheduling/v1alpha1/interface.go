@@ -26,12 +26,26 @@ import (
 type Interface interface {
 	// AppGroups returns a AppGroupInformer.
 	AppGroups() AppGroupInformer
+	// CoschedulingPolicies returns a CoschedulingPolicyInformer.
+	CoschedulingPolicies() CoschedulingPolicyInformer
 	// ElasticQuotas returns a ElasticQuotaInformer.
 	ElasticQuotas() ElasticQuotaInformer
+	// InterferencePolicies returns a InterferencePolicyInformer.
+	InterferencePolicies() InterferencePolicyInformer
+	// LinkHotspots returns a LinkHotspotInformer.
+	LinkHotspots() LinkHotspotInformer
+	// NetworkLatencies returns a NetworkLatencyInformer.
+	NetworkLatencies() NetworkLatencyInformer
 	// NetworkTopologies returns a NetworkTopologyInformer.
 	NetworkTopologies() NetworkTopologyInformer
+	// NodeBandwidthProfiles returns a NodeBandwidthProfileInformer.
+	NodeBandwidthProfiles() NodeBandwidthProfileInformer
 	// PodGroups returns a PodGroupInformer.
 	PodGroups() PodGroupInformer
+	// RegistryMirrors returns a RegistryMirrorInformer.
+	RegistryMirrors() RegistryMirrorInformer
+	// VirtualNodeProfiles returns a VirtualNodeProfileInformer.
+	VirtualNodeProfiles() VirtualNodeProfileInformer
 }
 
 type version struct {
@@ -50,17 +64,52 @@ func (v *version) AppGroups() AppGroupInformer {
 	return &appGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CoschedulingPolicies returns a CoschedulingPolicyInformer.
+func (v *version) CoschedulingPolicies() CoschedulingPolicyInformer {
+	return &coschedulingPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ElasticQuotas returns a ElasticQuotaInformer.
 func (v *version) ElasticQuotas() ElasticQuotaInformer {
 	return &elasticQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// InterferencePolicies returns a InterferencePolicyInformer.
+func (v *version) InterferencePolicies() InterferencePolicyInformer {
+	return &interferencePolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// LinkHotspots returns a LinkHotspotInformer.
+func (v *version) LinkHotspots() LinkHotspotInformer {
+	return &linkHotspotInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NetworkLatencies returns a NetworkLatencyInformer.
+func (v *version) NetworkLatencies() NetworkLatencyInformer {
+	return &networkLatencyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // NetworkTopologies returns a NetworkTopologyInformer.
 func (v *version) NetworkTopologies() NetworkTopologyInformer {
 	return &networkTopologyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NodeBandwidthProfiles returns a NodeBandwidthProfileInformer.
+func (v *version) NodeBandwidthProfiles() NodeBandwidthProfileInformer {
+	return &nodeBandwidthProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // PodGroups returns a PodGroupInformer.
 func (v *version) PodGroups() PodGroupInformer {
 	return &podGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RegistryMirrors returns a RegistryMirrorInformer.
+func (v *version) RegistryMirrors() RegistryMirrorInformer {
+	return &registryMirrorInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// VirtualNodeProfiles returns a VirtualNodeProfileInformer.
+func (v *version) VirtualNodeProfiles() VirtualNodeProfileInformer {
+	return &virtualNodeProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -26,6 +26,14 @@ type AppGroupListerExpansion interface{}
 // AppGroupNamespaceLister.
 type AppGroupNamespaceListerExpansion interface{}
 
+// CoschedulingPolicyListerExpansion allows custom methods to be added to
+// CoschedulingPolicyLister.
+type CoschedulingPolicyListerExpansion interface{}
+
+// CoschedulingPolicyNamespaceListerExpansion allows custom methods to be added to
+// CoschedulingPolicyNamespaceLister.
+type CoschedulingPolicyNamespaceListerExpansion interface{}
+
 // ElasticQuotaListerExpansion allows custom methods to be added to
 // ElasticQuotaLister.
 type ElasticQuotaListerExpansion interface{}
@@ -34,6 +42,26 @@ type ElasticQuotaListerExpansion interface{}
 // ElasticQuotaNamespaceLister.
 type ElasticQuotaNamespaceListerExpansion interface{}
 
+// InterferencePolicyListerExpansion allows custom methods to be added to
+// InterferencePolicyLister.
+type InterferencePolicyListerExpansion interface{}
+
+// LinkHotspotListerExpansion allows custom methods to be added to
+// LinkHotspotLister.
+type LinkHotspotListerExpansion interface{}
+
+// LinkHotspotNamespaceListerExpansion allows custom methods to be added to
+// LinkHotspotNamespaceLister.
+type LinkHotspotNamespaceListerExpansion interface{}
+
+// NetworkLatencyListerExpansion allows custom methods to be added to
+// NetworkLatencyLister.
+type NetworkLatencyListerExpansion interface{}
+
+// NetworkLatencyNamespaceListerExpansion allows custom methods to be added to
+// NetworkLatencyNamespaceLister.
+type NetworkLatencyNamespaceListerExpansion interface{}
+
 // NetworkTopologyListerExpansion allows custom methods to be added to
 // NetworkTopologyLister.
 type NetworkTopologyListerExpansion interface{}
@@ -42,6 +70,10 @@ type NetworkTopologyListerExpansion interface{}
 // NetworkTopologyNamespaceLister.
 type NetworkTopologyNamespaceListerExpansion interface{}
 
+// NodeBandwidthProfileListerExpansion allows custom methods to be added to
+// NodeBandwidthProfileLister.
+type NodeBandwidthProfileListerExpansion interface{}
+
 // PodGroupListerExpansion allows custom methods to be added to
 // PodGroupLister.
 type PodGroupListerExpansion interface{}
@@ -49,3 +81,11 @@ type PodGroupListerExpansion interface{}
 // PodGroupNamespaceListerExpansion allows custom methods to be added to
 // PodGroupNamespaceLister.
 type PodGroupNamespaceListerExpansion interface{}
+
+// RegistryMirrorListerExpansion allows custom methods to be added to
+// RegistryMirrorLister.
+type RegistryMirrorListerExpansion interface{}
+
+// VirtualNodeProfileListerExpansion allows custom methods to be added to
+// VirtualNodeProfileLister.
+type VirtualNodeProfileListerExpansion interface{}
@@ -0,0 +1,454 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagelocality
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// TopologicalImageLocality is a score plugin that favors nodes from which the images of the
+// incoming pod are cheap to pull: nodes already holding them, then nodes close, according
+// to a NetworkTopology, to a zone where the images are cached by a peer node or a registry mirror.
+// The nodes of the draining zones of the NetworkTopology score the lowest.
+type TopologicalImageLocality struct {
+	handle   framework.Handle
+	rmLister listers.RegistryMirrorLister
+	// costOracle is nil if no NetworkTopology is configured.
+	costOracle  *costoracle.CostOracle
+	scoreBudget *util.LatencyBudget
+	// vnpLister is nil unless the virtual nodes are placed by their VirtualNodeProfile.
+	vnpLister listers.VirtualNodeProfileLister
+	staleness *costoracle.StalenessDecay
+}
+
+var _ framework.PreScorePlugin = &TopologicalImageLocality{}
+var _ framework.ScorePlugin = &TopologicalImageLocality{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "TopologicalImageLocality"
+
+	// preScoreStateKey is the key in CycleState to TopologicalImageLocality pre-computed data for Scoring.
+	preScoreStateKey = "PreScore" + Name
+
+	mb int64 = 1024 * 1024
+
+	// defaultImageSizeMB is assumed for the images not reported by any node, so that
+	// registry mirrors are taken into account for them too.
+	defaultImageSizeMB int64 = 1
+
+	// defaultRegistry is the registry of the images not specifying any.
+	defaultRegistry = "docker.io"
+)
+
+// imageSource records where an image can be pulled from within the cluster.
+type imageSource struct {
+	sizeMB int64
+	// zones holding the image, either on a node or on a registry mirror.
+	zones map[string]bool
+}
+
+// preScoreState computed at PreScore and used at Score.
+type preScoreState struct {
+	// images maps the normalized names of the pod's images to their sources.
+	images map[string]*imageSource
+	costs  *zoneCosts
+	// draining holds the names of the nodes in a draining zone.
+	draining map[string]bool
+}
+
+// Clone the preScore state.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.TopologicalImageLocalityArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type TopologicalImageLocalityArgs, got %T", obj)
+	}
+	if args.ScoreBudgetMilliseconds < 0 {
+		return nil, fmt.Errorf("ScoreBudgetMilliseconds should not be negative, got %d", args.ScoreBudgetMilliseconds)
+	}
+	if args.StaleCostThresholdSeconds < 0 {
+		return nil, fmt.Errorf("StaleCostThresholdSeconds should not be negative, got %d", args.StaleCostThresholdSeconds)
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	rmInformer := informerFactory.Scheduling().V1alpha1().RegistryMirrors()
+	synced := []cache.InformerSynced{rmInformer.Informer().HasSynced}
+	var costOracle *costoracle.CostOracle
+	if args.NetworkTopologyName != "" {
+		ntInformer := informerFactory.Scheduling().V1alpha1().NetworkTopologies()
+		var err error
+		if costOracle, err = costoracle.New(ntInformer, args.NetworkTopologyNamespace, args.NetworkTopologyName, args.WeightsName); err != nil {
+			return nil, fmt.Errorf("invalid weightsName: %w", err)
+		}
+		synced = append(synced, ntInformer.Informer().HasSynced)
+	}
+	var vnpLister listers.VirtualNodeProfileLister
+	if args.VirtualNodeProfiles {
+		vnpInformer := informerFactory.Scheduling().V1alpha1().VirtualNodeProfiles()
+		vnpLister = vnpInformer.Lister()
+		synced = append(synced, vnpInformer.Informer().HasSynced)
+	}
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	return &TopologicalImageLocality{
+		handle:      handle,
+		rmLister:    rmInformer.Lister(),
+		costOracle:  costOracle,
+		scoreBudget: util.NewLatencyBudget(Name, "Score", time.Duration(args.ScoreBudgetMilliseconds)*time.Millisecond),
+		vnpLister:   vnpLister,
+		staleness:   costoracle.NewStalenessDecay(Name, time.Duration(args.StaleCostThresholdSeconds)*time.Second),
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (til *TopologicalImageLocality) Name() string {
+	return Name
+}
+
+// PreScore gathers the zones from which every image of the pod can be pulled, the costs between zones,
+// discounted if stale, and the nodes in a draining zone.
+func (til *TopologicalImageLocality) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	nodeInfos, err := til.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("listing NodeInfos: %w", err))
+	}
+	mirrors, err := til.rmLister.List(labels.Everything())
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("listing RegistryMirrors: %w", err))
+	}
+	draining := make(map[string]bool)
+	if til.costOracle != nil {
+		for _, n := range nodes {
+			if zone := n.Labels[v1.LabelTopologyZone]; zone != "" {
+				draining[n.Name], _ = til.costOracle.ZoneDraining(zone)
+			}
+		}
+	}
+	state.Write(preScoreStateKey, &preScoreState{
+		images:   imageSources(pod, nodeInfos, mirrors),
+		costs:    newZoneCosts(til.costOracle, til.staleness.Weight(til.costOracle, time.Now())),
+		draining: draining,
+	})
+	return nil
+}
+
+// Score invoked at the score extension point.
+// It returns the estimated cost of pulling the pod's images missing on the node.
+func (til *TopologicalImageLocality) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	// The draining nodes get the lowest score in NormalizeScore whatever their cost.
+	if len(s.images) == 0 || s.draining[nodeName] {
+		return 0, nil
+	}
+	// Past the budget, NormalizeScore gives every node the same score anyway.
+	if til.scoreBudget.Exhausted(state) {
+		return 0, nil
+	}
+	defer til.scoreBudget.Observe(state, time.Now())
+
+	nodeInfo, err := til.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+
+	present := nodeImages(nodeInfo)
+	zone := node.Labels[v1.LabelTopologyZone]
+	unknownCost := s.costs.max
+	// The downstream capacity of a virtual node pulls the images from its own topology tier.
+	if profile, ok := util.GetVirtualNodeProfile(til.vnpLister, node); ok {
+		zone, unknownCost = profile.Spec.TopologyTier, profile.Spec.NetworkCost
+	}
+	var cost int64
+	for name, src := range s.images {
+		if present[name] {
+			continue
+		}
+		cost += src.sizeMB * (1 + s.costs.weighted(s.costs.pullCost(zone, src.zones, unknownCost)))
+	}
+	return cost, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (til *TopologicalImageLocality) ScoreExtensions() framework.ScoreExtensions {
+	return til
+}
+
+// NormalizeScore maps the pull costs onto the framework's score range, the highest cost
+// getting the lowest score, and the nodes in a draining zone the lowest score. Every node
+// gets the same score if the score budget of the cycle was exceeded.
+func (til *TopologicalImageLocality) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	if til.scoreBudget.Exhausted(state) {
+		for i := range scores {
+			scores[i].Score = framework.MinNodeScore
+		}
+		return nil
+	}
+	s, err := getPreScoreState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	// Find highest and lowest costs, out of the draining nodes.
+	var highest int64 = -math.MaxInt64
+	var lowest int64 = math.MaxInt64
+	for _, nodeScore := range scores {
+		if s.draining[nodeScore.Name] {
+			continue
+		}
+		if nodeScore.Score > highest {
+			highest = nodeScore.Score
+		}
+		if nodeScore.Score < lowest {
+			lowest = nodeScore.Score
+		}
+	}
+
+	// Transform the highest to lowest cost range to fit the framework's max to min node score range.
+	oldRange := highest - lowest
+	newRange := framework.MaxNodeScore - framework.MinNodeScore
+	for i, nodeScore := range scores {
+		if s.draining[nodeScore.Name] {
+			scores[i].Score = framework.MinNodeScore
+			continue
+		}
+		if oldRange == 0 {
+			scores[i].Score = framework.MaxNodeScore
+		} else {
+			scores[i].Score = framework.MaxNodeScore - ((nodeScore.Score - lowest) * newRange / oldRange)
+		}
+	}
+	return nil
+}
+
+// zoneCosts answers the network costs between zones during a scheduling cycle.
+type zoneCosts struct {
+	oracle *costoracle.CostOracle
+	// max is the highest cost, used for the unknown costs.
+	max int64
+	// weight of the costs in the score, below 1 once they are stale.
+	weight float64
+}
+
+// newZoneCosts returns the zone costs served by oracle, weighing weight in the score. All costs
+// are 1 if oracle is nil or does not know any cost, i.e. zones are equidistant.
+func newZoneCosts(oracle *costoracle.CostOracle, weight float64) *zoneCosts {
+	zc := &zoneCosts{oracle: oracle, max: 1, weight: weight}
+	if oracle != nil {
+		if max := oracle.GetMaxCost(v1alpha1.NetworkTopologyZone); max > zc.max {
+			zc.max = max
+		}
+	}
+	return zc
+}
+
+// cost returns the network cost between two zones, unknown if not known.
+func (zc *zoneCosts) cost(from, to string, unknown int64) int64 {
+	if from == "" || to == "" {
+		return unknown
+	}
+	if from == to {
+		return 0
+	}
+	if zc.oracle == nil {
+		return unknown
+	}
+	if c, ok := zc.oracle.GetZoneCost(from, to); ok {
+		return c
+	}
+	if c, ok := zc.oracle.GetZoneCost(to, from); ok {
+		return c
+	}
+	return unknown
+}
+
+// weighted returns cost weighted by the weight of the costs, 0 once they have no weight left, so that
+// the pull costs only tell the nodes holding the images from the others.
+func (zc *zoneCosts) weighted(cost int64) int64 {
+	return int64(math.Round(zc.weight * float64(cost)))
+}
+
+// pullCost returns the cost of pulling an image into zone from the closest of the source
+// zones, the unknown costs being unknown. Pulling from outside of the cluster is more
+// expensive than from any zone.
+func (zc *zoneCosts) pullCost(zone string, sources map[string]bool, unknown int64) int64 {
+	best := zc.max + 1
+	if unknown >= best {
+		best = unknown + 1
+	}
+	for src := range sources {
+		if c := zc.cost(zone, src, unknown); c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+// imageSources returns the sources of the images of pod: the zones of the nodes holding
+// them, either reported in the node status or used by a running pod, and the zones of the
+// mirrors of their registries.
+func imageSources(pod *v1.Pod, nodeInfos []*framework.NodeInfo, mirrors []*v1alpha1.RegistryMirror) map[string]*imageSource {
+	images := make(map[string]*imageSource)
+	for _, c := range pod.Spec.InitContainers {
+		images[normalizedImageName(c.Image)] = &imageSource{sizeMB: defaultImageSizeMB, zones: make(map[string]bool)}
+	}
+	for _, c := range pod.Spec.Containers {
+		images[normalizedImageName(c.Image)] = &imageSource{sizeMB: defaultImageSizeMB, zones: make(map[string]bool)}
+	}
+	if len(images) == 0 {
+		return images
+	}
+
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		for _, image := range node.Status.Images {
+			for _, name := range image.Names {
+				src, ok := images[normalizedImageName(name)]
+				if !ok {
+					continue
+				}
+				if size := image.SizeBytes / mb; size > src.sizeMB {
+					src.sizeMB = size
+				}
+			}
+		}
+		zone, ok := node.Labels[v1.LabelTopologyZone]
+		if !ok {
+			continue
+		}
+		for name := range nodeImages(nodeInfo) {
+			if src, ok := images[name]; ok {
+				src.zones[zone] = true
+			}
+		}
+	}
+
+	for name, src := range images {
+		registry := imageRegistry(name)
+		for _, m := range mirrors {
+			if m.Spec.Registry != "" && m.Spec.Registry != registry {
+				continue
+			}
+			for _, zone := range m.Spec.Zones {
+				src.zones[zone] = true
+			}
+		}
+	}
+	return images
+}
+
+// nodeImages returns the normalized names of the images held by the node of nodeInfo,
+// either reported in its status or used by the pods running on it.
+func nodeImages(nodeInfo *framework.NodeInfo) map[string]bool {
+	images := make(map[string]bool)
+	if node := nodeInfo.Node(); node != nil {
+		for _, image := range node.Status.Images {
+			for _, name := range image.Names {
+				images[normalizedImageName(name)] = true
+			}
+		}
+	}
+	for _, p := range nodeInfo.Pods {
+		for _, c := range p.Pod.Spec.InitContainers {
+			images[normalizedImageName(c.Image)] = true
+		}
+		for _, c := range p.Pod.Spec.Containers {
+			images[normalizedImageName(c.Image)] = true
+		}
+	}
+	return images
+}
+
+// normalizedImageName returns the CRI compliant name for a given image, the same way as
+// the in-tree ImageLocality plugin.
+// TODO: cover the corner cases of missed matches, e.g,
+// 1. Using Docker as runtime and docker.io/library/test:tag in pod spec, but only test:tag will present in node status
+// 2. Using the implicit registry, i.e., test:tag or library/test:tag in pod spec but only docker.io/library/test:tag
+// in node status; note that if users consistently use one registry format, this should not happen.
+func normalizedImageName(name string) string {
+	if strings.LastIndex(name, ":") <= strings.LastIndex(name, "/") {
+		name = name + ":latest"
+	}
+	return name
+}
+
+// imageRegistry returns the registry host of the image name, following the Docker conventions.
+func imageRegistry(name string) string {
+	i := strings.Index(name, "/")
+	if i < 0 {
+		return defaultRegistry
+	}
+	host := name[:i]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return defaultRegistry
+	}
+	return host
+}
+
+func getPreScoreState(cycleState *framework.CycleState) (*preScoreState, error) {
+	c, err := cycleState.Read(preScoreStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preScoreStateKey, err)
+	}
+
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("invalid PreScore state, got type %T", c)
+	}
+	return s, nil
+}
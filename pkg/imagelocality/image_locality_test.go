@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagelocality
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestTopologicalImageLocalityScore(t *testing.T) {
+	// Zones z1 and z2 are close, z3 is far from both.
+	topology := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: v1alpha1.TopologyList{{
+					TopologyKey: v1alpha1.NetworkTopologyZone,
+					OriginList: v1alpha1.OriginList{
+						{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 10}}},
+						{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 10}}},
+					},
+				}},
+			}},
+		},
+	}
+	nodes := []*v1.Node{
+		makeNode("node1", "z1", v1.ContainerImage{Names: []string{"registry.example.com/app:v1"}, SizeBytes: 500 * mb}),
+		makeNode("node2", "z2"),
+		makeNode("node3", "z3"),
+	}
+	virtualNode := makeNode("vk", "z3")
+	virtualNode.Labels[v1alpha1.VirtualNodeLabel] = "true"
+	withVirtualNode := append(append([]*v1.Node{}, nodes...), virtualNode)
+	drainingTopology := topology.DeepCopy()
+	drainingTopology.Spec.DrainingZones = []v1alpha1.DrainingZone{{Zone: "z1"}}
+	staleTopology := topology.DeepCopy()
+	staleTopology.Status.WeightCalculationTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		mirrors      []*v1alpha1.RegistryMirror
+		topology     *v1alpha1.NetworkTopology
+		nodes        []*v1.Node
+		profiles     []*v1alpha1.VirtualNodeProfile
+		scoreBudget  time.Duration
+		staleCost    time.Duration
+		expected     framework.NodeScoreList
+	}{
+		{
+			name:     "node holding the image is preferred, then the nodes close to it",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			topology: topology,
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 82}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:     "nodes of a draining zone get the lowest score",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			topology: drainingTopology,
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: framework.MaxNodeScore}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:     "zones are equidistant without NetworkTopology",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:      "zones are equidistant once the costs are stale",
+			pod:       makePod("pod", "", "registry.example.com/app:v1"),
+			topology:  staleTopology,
+			staleCost: time.Hour,
+			expected:  []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:      "costs are kept until stale",
+			pod:       makePod("pod", "", "registry.example.com/app:v1"),
+			topology:  staleTopology,
+			staleCost: 3 * time.Hour,
+			expected:  []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 82}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:     "registry mirror zone is a source",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			mirrors:  []*v1alpha1.RegistryMirror{makeMirror("mirror", "registry.example.com", "z3")},
+			topology: topology,
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: 50}},
+		},
+		{
+			name:     "mirror of another registry is ignored",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			mirrors:  []*v1alpha1.RegistryMirror{makeMirror("mirror", "docker.io", "z3")},
+			topology: topology,
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 82}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+		{
+			name:         "peer pods running the image make their zone a source",
+			pod:          makePod("pod", "", "nginx"),
+			existingPods: []*v1.Pod{makePod("peer", "node3", "nginx:latest")},
+			topology:     topology,
+			expected:     []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: framework.MaxNodeScore}},
+		},
+		{
+			name:     "virtual node placed in its topology tier",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			topology: topology,
+			nodes:    withVirtualNode,
+			profiles: []*v1alpha1.VirtualNodeProfile{makeProfile("vk", "z2", 40)},
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 82}, {Name: "node3", Score: framework.MinNodeScore}, {Name: "vk", Score: 82}},
+		},
+		{
+			name:     "virtual node at its network cost when the topology has none for its tier",
+			pod:      makePod("pod", "", "registry.example.com/app:v1"),
+			topology: topology,
+			nodes:    withVirtualNode,
+			profiles: []*v1alpha1.VirtualNodeProfile{makeProfile("vk", "edge", 40)},
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 96}, {Name: "node3", Score: 74}, {Name: "vk", Score: framework.MinNodeScore}},
+		},
+		{
+			name:        "nodes get the same score past the score budget",
+			pod:         makePod("pod", "", "registry.example.com/app:v1"),
+			topology:    topology,
+			scoreBudget: time.Nanosecond,
+			expected:    []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: framework.MinNodeScore}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cs := fakeclientset.NewSimpleClientset()
+			schedInformerFactory := schedinformers.NewSharedInformerFactory(cs, 0)
+			rmInformer := schedInformerFactory.Scheduling().V1alpha1().RegistryMirrors()
+			rmInformer.Informer()
+			for _, m := range tt.mirrors {
+				if _, err := cs.SchedulingV1alpha1().RegistryMirrors().Create(ctx, m, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			vnpInformer := schedInformerFactory.Scheduling().V1alpha1().VirtualNodeProfiles()
+			vnpInformer.Informer()
+			for _, p := range tt.profiles {
+				if _, err := cs.SchedulingV1alpha1().VirtualNodeProfiles().Create(ctx, p, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			nodes := nodes
+			if tt.nodes != nil {
+				nodes = tt.nodes
+			}
+			var costOracle *costoracle.CostOracle
+			if tt.topology != nil {
+				if _, err := cs.SchedulingV1alpha1().NetworkTopologies(tt.topology.Namespace).Create(ctx, tt.topology, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+				var err error
+				if costOracle, err = costoracle.New(schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), tt.topology.Namespace, tt.topology.Name, "UserDefined"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			schedInformerFactory.Start(ctx.Done())
+			schedInformerFactory.WaitForCacheSync(ctx.Done())
+			if costOracle != nil {
+				if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+					return costOracle.GetMaxCost(v1alpha1.NetworkTopologyZone) > 0, nil
+				}); err != nil {
+					t.Fatalf("NetworkTopology not observed: %v", err)
+				}
+			}
+
+			fakeClient := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fh, err := st.NewFramework(registeredPlugins, "",
+				frameworkruntime.WithClientSet(fakeClient),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(tt.existingPods, nodes)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			til := &TopologicalImageLocality{
+				handle:      fh,
+				rmLister:    rmInformer.Lister(),
+				costOracle:  costOracle,
+				scoreBudget: util.NewLatencyBudget(Name, "Score", tt.scoreBudget),
+				vnpLister:   vnpInformer.Lister(),
+				staleness:   costoracle.NewStalenessDecay(Name, tt.staleCost),
+			}
+			state := framework.NewCycleState()
+			if status := til.PreScore(ctx, state, tt.pod, nodes); !status.IsSuccess() {
+				t.Fatalf("unexpected PreScore status: %v", status)
+			}
+			var gotList framework.NodeScoreList
+			for _, n := range nodes {
+				score, status := til.Score(ctx, state, tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status: %v", status)
+				}
+				gotList = append(gotList, framework.NodeScore{Name: n.Name, Score: score})
+			}
+			if status := til.NormalizeScore(ctx, state, tt.pod, gotList); !status.IsSuccess() {
+				t.Fatalf("unexpected NormalizeScore status: %v", status)
+			}
+			if !reflect.DeepEqual(tt.expected, gotList) {
+				t.Errorf("expected %v, got %v", tt.expected, gotList)
+			}
+		})
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"nginx:latest":                     "docker.io",
+		"library/nginx:latest":             "docker.io",
+		"docker.io/library/nginx:latest":   "docker.io",
+		"registry.example.com/app:v1":      "registry.example.com",
+		"localhost/app:v1":                 "localhost",
+		"registry.example.com:5000/app:v1": "registry.example.com:5000",
+	}
+	for image, expected := range tests {
+		if got := imageRegistry(image); got != expected {
+			t.Errorf("image %q: expected registry %q, got %q", image, expected, got)
+		}
+	}
+}
+
+func makeNode(name, zone string, images ...v1.ContainerImage) *v1.Node {
+	node := st.MakeNode().Name(name).Label(v1.LabelTopologyZone, zone).Obj()
+	node.Status.Images = images
+	return node
+}
+
+func makeMirror(name, registry string, zones ...string) *v1alpha1.RegistryMirror {
+	return &v1alpha1.RegistryMirror{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.RegistryMirrorSpec{Registry: registry, Zones: zones},
+	}
+}
+
+func makeProfile(name, tier string, networkCost int64) *v1alpha1.VirtualNodeProfile {
+	return &v1alpha1.VirtualNodeProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.VirtualNodeProfileSpec{TopologyTier: tier, NetworkCost: networkCost},
+	}
+}
+
+func makePod(name, nodeName, image string) *v1.Pod {
+	pod := st.MakePod().Name(name).Namespace("default").UID(name).Node(nodeName).Obj()
+	pod.Spec.Containers = []v1.Container{{Name: "c", Image: image}}
+	return pod
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package install installs the cluster resources needed by a set of plugins: their
+// CustomResourceDefinitions, the RBAC granted to the scheduler and, if required, the
+// scheduler-plugins controller. It is meant for operators embedding the plugins, which
+// would otherwise have to vendor the manifests of this repository.
+package install
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/scheduler-plugins/manifests"
+	"sigs.k8s.io/scheduler-plugins/pkg/backfill"
+	"sigs.k8s.io/scheduler-plugins/pkg/capacityscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/celpolicy"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/imagelocality"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/crossregionlimit"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/linkheadroom"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/nodebandwidth"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/placementlabels"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/topologicalsort"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/warmpeers"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/zonelimit"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesources"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology"
+	"sigs.k8s.io/scheduler-plugins/pkg/noisyneighbor"
+	"sigs.k8s.io/scheduler-plugins/pkg/podstate"
+	"sigs.k8s.io/scheduler-plugins/pkg/preemptiontoleration"
+	"sigs.k8s.io/scheduler-plugins/pkg/qos"
+	"sigs.k8s.io/scheduler-plugins/pkg/spotawareness"
+	"sigs.k8s.io/scheduler-plugins/pkg/statefulsetzone"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/targetloadpacking"
+)
+
+const (
+	// SchedulerRoleName is the name of the ClusterRole granting the plugins' permissions to the scheduler.
+	SchedulerRoleName = "system:kube-scheduler:plugins"
+	// SchedulerUser is the user the scheduler authenticates as.
+	SchedulerUser = "system:kube-scheduler"
+	// ControllerName is the name of the controller Deployment, ServiceAccount and ClusterRole.
+	ControllerName = "scheduler-plugins-controller"
+	// DefaultControllerNamespace is the namespace of the controller if none is given.
+	DefaultControllerNamespace = "scheduler-plugins"
+	// DefaultControllerImage is the controller image used if none is given.
+	DefaultControllerImage = "k8s.gcr.io/scheduler-plugins/controller:v0.22.6"
+)
+
+// Options selects what to install.
+type Options struct {
+	// Plugins are the names of the plugins to install the resources of.
+	Plugins []string
+	// ControllerNamespace is the namespace of the controller, DefaultControllerNamespace if empty.
+	ControllerNamespace string
+	// ControllerImage is the image of the controller, DefaultControllerImage if empty.
+	ControllerImage string
+}
+
+// pluginResources are the cluster resources needed by a plugin.
+type pluginResources struct {
+	// crds are the paths of the embedded CustomResourceDefinition manifests.
+	crds []string
+	// rules are granted to the scheduler.
+	rules []rbacv1.PolicyRule
+	// controller tells whether the plugin relies on the scheduler-plugins controller.
+	controller bool
+}
+
+var readWriteVerbs = []string{"get", "list", "watch", "create", "delete", "update", "patch"}
+var readVerbs = []string{"get", "list", "watch"}
+
+// plugins holds the resources of every plugin of this repository, keyed by plugin name.
+var plugins = map[string]pluginResources{
+	backfill.Name: {
+		crds:  []string{"coscheduling/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"podgroups"}, Verbs: readVerbs}},
+	},
+	capacityscheduling.Name: {
+		crds:       []string{"capacityscheduling/crd.yaml"},
+		rules:      []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"elasticquotas"}, Verbs: readWriteVerbs}},
+		controller: true,
+	},
+	celpolicy.Name: {
+		crds:  []string{"networktopology/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"networktopologies"}, Verbs: readVerbs}},
+	},
+	coscheduling.Name: {
+		crds: []string{"coscheduling/crd.yaml"},
+		rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"podgroups"}, Verbs: readWriteVerbs},
+			{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"coschedulingpolicies"}, Verbs: readVerbs},
+		},
+		controller: true,
+	},
+	// CrossRegionRateLimit only reads the pods and nodes of the scheduler, the pods of an AppGroup
+	// being labeled by the controller.
+	crossregionlimit.Name: {
+		crds:       []string{"appgroup/crd.yaml"},
+		controller: true,
+	},
+	imagelocality.Name: {
+		crds:  []string{"imagelocality/crd.yaml", "networktopology/crd.yaml", "virtualnode/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"registrymirrors", "networktopologies", "virtualnodeprofiles"}, Verbs: readVerbs}},
+	},
+	// LinkHeadroom reads the bandwidth the controller allocates on the links of the NetworkTopology.
+	linkheadroom.Name: {
+		crds:       []string{"appgroup/crd.yaml", "networktopology/crd.yaml"},
+		rules:      []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"appgroups", "networktopologies"}, Verbs: readVerbs}},
+		controller: true,
+	},
+	// LoadVariationRiskBalancing annotates the pods it marks for soft eviction.
+	loadvariationriskbalancing.Name: {
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"patch"}}},
+	},
+	nodebandwidth.Name: {
+		crds:       []string{"nodebandwidth/crd.yaml"},
+		controller: true,
+	},
+	noderesources.AllocatableName: {
+		crds:  []string{"virtualnode/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"virtualnodeprofiles"}, Verbs: readVerbs}},
+	},
+	noderesourcetopology.Name: {
+		crds:  []string{"noderesourcetopology/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"topology.node.k8s.io"}, Resources: []string{"noderesourcetopologies"}, Verbs: readVerbs}},
+	},
+	noisyneighbor.Name: {
+		crds:  []string{"noisyneighbor/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"interferencepolicies"}, Verbs: readVerbs}},
+	},
+	placementlabels.Name: {
+		crds: []string{"appgroup/crd.yaml"},
+		rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"appgroups"}, Verbs: readVerbs},
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"patch"}},
+		},
+	},
+	podstate.Name:             {},
+	preemptiontoleration.Name: {},
+	qos.Name:                  {},
+	spotawareness.Name:        {},
+	statefulsetzone.Name: {
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}, Verbs: []string{"patch"}}},
+	},
+	targetloadpacking.Name: {},
+	// TopologicalSort reads the topology order the controller writes in the AppGroup status.
+	topologicalsort.Name: {
+		crds:       []string{"appgroup/crd.yaml"},
+		rules:      []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"appgroups"}, Verbs: readVerbs}},
+		controller: true,
+	},
+	warmpeers.Name: {
+		crds:  []string{"appgroup/crd.yaml", "networktopology/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"appgroups", "networktopologies"}, Verbs: readVerbs}},
+	},
+	zonelimit.Name: {
+		crds:  []string{"appgroup/crd.yaml"},
+		rules: []rbacv1.PolicyRule{{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"appgroups"}, Verbs: readVerbs}},
+	},
+}
+
+// controllerCRDs are the CustomResourceDefinitions watched by the controller, which does not
+// start without them.
+var controllerCRDs = []string{
+	"appgroup/crd.yaml",
+	"capacityscheduling/crd.yaml",
+	"coscheduling/crd.yaml",
+	"linkhotspot/crd.yaml",
+	"networklatency/crd.yaml",
+	"networktopology/crd.yaml",
+	"nodebandwidth/crd.yaml",
+}
+
+// Install creates, or updates if they already exist, the resources needed by the plugins of opts.
+// CustomResourceDefinitions are applied first so that the controller does not start before them.
+func Install(ctx context.Context, client dynamic.Interface, opts Options) error {
+	objs, err := Objects(opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := apply(ctx, client, obj); err != nil {
+			return fmt.Errorf("applying %s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Objects returns the resources needed by the plugins of opts, in the order they must be applied.
+func Objects(opts Options) ([]*unstructured.Unstructured, error) {
+	if opts.ControllerNamespace == "" {
+		opts.ControllerNamespace = DefaultControllerNamespace
+	}
+	if opts.ControllerImage == "" {
+		opts.ControllerImage = DefaultControllerImage
+	}
+
+	crds := make(map[string]bool)
+	var rules []rbacv1.PolicyRule
+	controller := false
+	for _, name := range opts.Plugins {
+		r, ok := plugins[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown plugin %q", name)
+		}
+		for _, crd := range r.crds {
+			crds[crd] = true
+		}
+		rules = append(rules, r.rules...)
+		controller = controller || r.controller
+	}
+	if controller {
+		for _, crd := range controllerCRDs {
+			crds[crd] = true
+		}
+	}
+
+	paths := make([]string, 0, len(crds))
+	for crd := range crds {
+		paths = append(paths, crd)
+	}
+	sort.Strings(paths)
+	var objs []*unstructured.Unstructured
+	for _, path := range paths {
+		crdObjs, err := decodeManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, crdObjs...)
+	}
+
+	var typed []runtime.Object
+	if len(rules) > 0 {
+		typed = append(typed, schedulerRBAC(rules)...)
+	}
+	if controller {
+		typed = append(typed, controllerObjects(opts.ControllerNamespace, opts.ControllerImage)...)
+	}
+	for _, obj := range typed {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: u})
+	}
+	return objs, nil
+}
+
+// decodeManifest returns the objects of an embedded multi-document manifest.
+func decodeManifest(path string) ([]*unstructured.Unstructured, error) {
+	data, err := manifests.CRDs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("decoding %s: %w", path, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
+
+func schedulerRBAC(rules []rbacv1.PolicyRule) []runtime.Object {
+	return []runtime.Object{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: SchedulerRoleName},
+			Rules:      rules,
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: SchedulerRoleName},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: SchedulerRoleName},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: SchedulerUser}},
+		},
+	}
+}
+
+func controllerObjects(namespace, image string) []runtime.Object {
+	labels := map[string]string{"app": ControllerName}
+	return []runtime.Object{
+		&v1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		},
+		&v1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: ControllerName, Namespace: namespace},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: ControllerName},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods", "nodes"}, Verbs: []string{"get", "list", "watch", "patch"}},
+				{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"get", "list", "watch", "patch"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"replicasets"}, Verbs: []string{"get", "list", "watch", "patch"}},
+				{APIGroups: []string{"authentication.k8s.io"}, Resources: []string{"tokenreviews"}, Verbs: []string{"create"}},
+				{APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"subjectaccessreviews"}, Verbs: []string{"create"}},
+				{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"podgroups", "elasticquotas", "appgroups", "appgroups/status", "linkhotspots"}, Verbs: readWriteVerbs},
+				{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"networktopologies"}, Verbs: []string{"get", "list", "watch", "create", "update"}},
+				{APIGroups: []string{"scheduling.sigs.k8s.io"}, Resources: []string{"nodebandwidthprofiles", "networklatencies"}, Verbs: readVerbs},
+				{APIGroups: []string{"cluster.x-k8s.io"}, Resources: []string{"machinedeployments", "machinepools"}, Verbs: readVerbs},
+				{APIGroups: []string{"sparkoperator.k8s.io"}, Resources: []string{"sparkapplications"}, Verbs: []string{"get", "list", "watch", "patch"}},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: ControllerName},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: ControllerName},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: ControllerName, Namespace: namespace}},
+		},
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: ControllerName, Namespace: namespace, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32(1),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{
+						ServiceAccountName: ControllerName,
+						Containers: []v1.Container{{
+							Name:            ControllerName,
+							Image:           image,
+							ImagePullPolicy: v1.PullIfNotPresent,
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// apply creates obj, or replaces the existing object with it.
+func apply(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured) error {
+	gvr, _ := meta.UnsafeGuessKindToResource(obj.GroupVersionKind())
+	var ri dynamic.ResourceInterface = client.Resource(gvr)
+	if ns := obj.GetNamespace(); ns != "" {
+		ri = client.Resource(gvr).Namespace(ns)
+	}
+	existing, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.V(3).InfoS("Creating object", "kind", obj.GetKind(), "name", obj.GetName())
+		_, err = ri.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	klog.V(3).InfoS("Updating object", "kind", obj.GetKind(), "name", obj.GetName())
+	_, err = ri.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/noisyneighbor"
+	"sigs.k8s.io/scheduler-plugins/pkg/qos"
+	"sigs.k8s.io/scheduler-plugins/pkg/registry"
+	"sigs.k8s.io/scheduler-plugins/pkg/statefulsetzone"
+)
+
+func objectNames(opts Options) ([]string, error) {
+	objs, err := Objects(opts)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	return names, nil
+}
+
+func TestPluginsInstallable(t *testing.T) {
+	for name := range registry.NewInTreeRegistry() {
+		if _, ok := plugins[name]; !ok {
+			t.Errorf("plugin %q has no installed resources", name)
+		}
+	}
+}
+
+func TestObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugins  []string
+		expected []string
+	}{
+		{
+			name:     "plugin without resources",
+			plugins:  []string{qos.Name},
+			expected: nil,
+		},
+		{
+			name:    "plugin with a CRD",
+			plugins: []string{noisyneighbor.Name},
+			expected: []string{
+				"CustomResourceDefinition/interferencepolicies.scheduling.sigs.k8s.io",
+				"ClusterRole/" + SchedulerRoleName,
+				"ClusterRoleBinding/" + SchedulerRoleName,
+			},
+		},
+		{
+			name:    "plugin relying on the controller",
+			plugins: []string{coscheduling.Name, statefulsetzone.Name},
+			expected: []string{
+				"CustomResourceDefinition/appgroups.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/elasticquotas.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/podgroups.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/coschedulingpolicies.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/linkhotspots.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/networklatencies.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/networktopologies.scheduling.sigs.k8s.io",
+				"CustomResourceDefinition/nodebandwidthprofiles.scheduling.sigs.k8s.io",
+				"ClusterRole/" + SchedulerRoleName,
+				"ClusterRoleBinding/" + SchedulerRoleName,
+				"Namespace/" + DefaultControllerNamespace,
+				"ServiceAccount/" + ControllerName,
+				"ClusterRole/" + ControllerName,
+				"ClusterRoleBinding/" + ControllerName,
+				"Deployment/" + ControllerName,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := objectNames(Options{Plugins: tt.plugins})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := Objects(Options{Plugins: []string{"Unknown"}}); err == nil {
+		t.Errorf("expected an error for an unknown plugin")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: "CustomResourceDefinitionList",
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}:         "ClusterRoleList",
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}:  "ClusterRoleBindingList",
+		{Version: "v1", Resource: "namespaces"}:                                               "NamespaceList",
+		{Version: "v1", Resource: "serviceaccounts"}:                                          "ServiceAccountList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:                               "DeploymentList",
+	})
+	ctx := context.Background()
+	opts := Options{Plugins: []string{coscheduling.Name}, ControllerNamespace: "plugins", ControllerImage: "controller:v1"}
+	if err := Install(ctx, client, opts); err != nil {
+		t.Fatal(err)
+	}
+	// Installing again updates the existing objects.
+	opts.ControllerImage = "controller:v2"
+	if err := Install(ctx, client, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	d, err := client.Resource(deployments).Namespace("plugins").Get(ctx, ControllerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers, _, _ := unstructured.NestedSlice(d.Object, "spec", "template", "spec", "containers")
+	if len(containers) != 1 || containers[0].(map[string]interface{})["image"] != "controller:v2" {
+		t.Errorf("expected the controller image to be updated, got %v", containers)
+	}
+
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	if _, err := client.Resource(crds).Get(ctx, "podgroups.scheduling.sigs.k8s.io", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the PodGroup CRD to be installed: %v", err)
+	}
+}
@@ -0,0 +1,510 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
+)
+
+// CostOracle answers network cost queries from the weights of a NetworkTopology. It is kept
+// up to date by the NetworkTopology informer and safe for concurrent use, e.g. by Filter and
+// Score running in parallel goroutines.
+type CostOracle struct {
+	namespace    string
+	name         string
+	weightsNames []string
+	// factors holds the blend factors of the weights, 1 for the ones missing.
+	factors map[string]float64
+
+	sync.RWMutex
+	// table is replaced, never mutated, on NetworkTopology updates.
+	table *costTable
+}
+
+// link holds the network information from an origin to a destination.
+type link struct {
+	cost     int64
+	headroom resource.Quantity
+	// bounded tells whether the link has a known capacity, its headroom being meaningless otherwise.
+	bounded bool
+}
+
+// costTable holds the links of a NetworkTopology, keyed by topology key, origin and destination.
+type costTable struct {
+	links map[v1alpha1.TopologyKey]map[string]map[string]link
+	// max holds the highest cost per topology key.
+	max map[v1alpha1.TopologyKey]int64
+	// defaultCost is the cost of the links missing from the weights, unknown if nil.
+	defaultCost *int64
+	// draining holds the deadlines of the draining zones, nil for the ones without any.
+	draining map[string]*metav1.Time
+	// calculatedAt is the weightCalculationTime of the NetworkTopology, zero if unknown.
+	calculatedAt time.Time
+}
+
+// NormalizedMaxCost is the highest cost of the weights blended across cost units, which are
+// scaled to it.
+const NormalizedMaxCost int64 = 1000
+
+// New returns a CostOracle serving the weights named weightsName of the NetworkTopology
+// namespace/name, and registers it to informer. The informer must be started afterwards.
+// weightsName may list several comma-separated weights, whose costs are then averaged, after
+// being normalized to NormalizedMaxCost if their cost units differ. A weights may be given a
+// blend factor, e.g. UserDefined:0.7,Dijkstra:0.3, its costs then weighing factor times as much
+// as the ones of a weights without any in the average.
+func New(informer informers.NetworkTopologyInformer, namespace, name, weightsName string) (*CostOracle, error) {
+	weightsNames, factors, err := parseWeightsNames(weightsName)
+	if err != nil {
+		return nil, err
+	}
+	co := &CostOracle{
+		namespace:    namespace,
+		name:         name,
+		weightsNames: weightsNames,
+		factors:      factors,
+	}
+	informer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: co.filter,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				co.update(obj.(*v1alpha1.NetworkTopology))
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				co.update(newObj.(*v1alpha1.NetworkTopology))
+			},
+			DeleteFunc: func(_ interface{}) {
+				co.update(nil)
+			},
+		},
+	})
+	return co, nil
+}
+
+func (co *CostOracle) filter(obj interface{}) bool {
+	switch t := obj.(type) {
+	case *v1alpha1.NetworkTopology:
+		return t.Namespace == co.namespace && t.Name == co.name
+	case cache.DeletedFinalStateUnknown:
+		if nt, ok := t.Obj.(*v1alpha1.NetworkTopology); ok {
+			return nt.Namespace == co.namespace && nt.Name == co.name
+		}
+	}
+	return false
+}
+
+// isShadow tells whether nt is a shadow NetworkTopology, which plugins ignore.
+func isShadow(nt *v1alpha1.NetworkTopology) bool {
+	_, ok := nt.Annotations[v1alpha1.NetworkTopologyShadowOfAnnotation]
+	return ok
+}
+
+// update rebuilds the cost table from nt, clearing it if nt is nil or a shadow NetworkTopology.
+func (co *CostOracle) update(nt *v1alpha1.NetworkTopology) {
+	var table *costTable
+	if nt != nil && !isShadow(nt) {
+		table = newCostTable(nt, co.weightsNames, co.factors)
+	}
+	co.Lock()
+	co.table = table
+	co.Unlock()
+	klog.V(5).InfoS("Updated network costs", "networkTopology", klog.KRef(co.namespace, co.name), "found", nt != nil)
+}
+
+// parseWeightsNames returns the comma-separated weights names of weightsName, and the blend factors
+// following the names of some of them after a colon, e.g. Dijkstra:0.3. A factor must be positive.
+func parseWeightsNames(weightsName string) ([]string, map[string]float64, error) {
+	var names []string
+	var factors map[string]float64
+	for _, n := range strings.Split(weightsName, ",") {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+		if i := strings.LastIndex(n, ":"); i >= 0 {
+			factor, err := strconv.ParseFloat(strings.TrimSpace(n[i+1:]), 64)
+			if err != nil || factor <= 0 || math.IsInf(factor, 0) {
+				return nil, nil, fmt.Errorf("invalid blend factor of the weights %q, expecting a positive number", n)
+			}
+			if n = strings.TrimSpace(n[:i]); n == "" {
+				return nil, nil, fmt.Errorf("missing weights name before the blend factor %v", factor)
+			}
+			if factors == nil {
+				factors = make(map[string]float64)
+			}
+			factors[n] = factor
+		}
+		names = append(names, n)
+	}
+	return names, factors, nil
+}
+
+// WeightsCostUnit returns the cost unit of the weights w, Abstract if unset, and an error if its
+// topologies declare different units.
+func WeightsCostUnit(w v1alpha1.WeightInfo) (v1alpha1.NetworkCostUnit, error) {
+	var unit v1alpha1.NetworkCostUnit
+	for i, t := range w.TopologyList {
+		u := t.CostUnit
+		if u == "" {
+			u = v1alpha1.NetworkCostUnitAbstract
+		}
+		if i > 0 && u != unit {
+			return "", fmt.Errorf("weights %q mix the cost units %s and %s", w.Name, unit, u)
+		}
+		unit = u
+	}
+	if unit == "" {
+		unit = v1alpha1.NetworkCostUnitAbstract
+	}
+	return unit, nil
+}
+
+// costSum accumulates the costs of a link known to several weights, multiplied by their factor.
+type costSum struct {
+	sum     float64
+	factors float64
+}
+
+// newCostTable returns the cost table of the weights of nt named in weightsNames, blended by their
+// factors, 1 for the ones missing from factors.
+func newCostTable(nt *v1alpha1.NetworkTopology, weightsNames []string, factors map[string]float64) *costTable {
+	table := &costTable{
+		links:        make(map[v1alpha1.TopologyKey]map[string]map[string]link),
+		max:          make(map[v1alpha1.TopologyKey]int64),
+		defaultCost:  nt.Spec.DefaultCost,
+		draining:     DrainingZones(nt),
+		calculatedAt: nt.Status.WeightCalculationTime.Time,
+	}
+	maxUtilization := make(map[v1alpha1.TopologyKey]int32, len(nt.Spec.LinkPolicies))
+	for _, p := range nt.Spec.LinkPolicies {
+		maxUtilization[p.TopologyKey] = p.MaxUtilizationPercent
+	}
+	weights := selectWeights(nt, weightsNames)
+	normalize := !sameCostUnit(weights)
+	if normalize && table.defaultCost != nil {
+		defaultCost := NormalizedMaxCost
+		table.defaultCost = &defaultCost
+	}
+
+	sums := make(map[v1alpha1.TopologyKey]map[string]map[string]*costSum)
+	for _, w := range weights {
+		var maxCosts map[v1alpha1.TopologyKey]int64
+		if normalize {
+			maxCosts = weightsMaxCosts(w)
+		}
+		factor, ok := factors[w.Name]
+		if !ok {
+			factor = 1
+		}
+		for _, t := range w.TopologyList {
+			if t.InterfaceClass != "" {
+				// Only the default class is known to the oracle, its users having no dependency selecting a class.
+				continue
+			}
+			origins, ok := table.links[t.TopologyKey]
+			if !ok {
+				origins = make(map[string]map[string]link)
+				table.links[t.TopologyKey] = origins
+				sums[t.TopologyKey] = make(map[string]map[string]*costSum)
+			}
+			for _, o := range t.OriginList {
+				destinations, ok := origins[o.Origin]
+				if !ok {
+					destinations = make(map[string]link, len(o.CostList))
+					origins[o.Origin] = destinations
+					sums[t.TopologyKey][o.Origin] = make(map[string]*costSum, len(o.CostList))
+				}
+				for _, c := range o.CostList {
+					cost := c.NetworkCost
+					if normalize && maxCosts[t.TopologyKey] > 0 {
+						cost = cost * NormalizedMaxCost / maxCosts[t.TopologyKey]
+					}
+					sum, ok := sums[t.TopologyKey][o.Origin][c.Destination]
+					if !ok {
+						// The bandwidth of a link is the one of the first weights knowing it.
+						capacity, _ := LinkBandwidth(c)
+						l := link{headroom: LinkHeadroom(c, maxUtilization[t.TopologyKey]), bounded: !capacity.IsZero()}
+						if t.TopologyKey == v1alpha1.NetworkTopologyZone && (table.isDraining(o.Origin) || table.isDraining(c.Destination)) {
+							// No new bandwidth is allocated to the links of a draining zone.
+							l.headroom = resource.Quantity{}
+						}
+						destinations[c.Destination] = l
+						sum = &costSum{}
+						sums[t.TopologyKey][o.Origin][c.Destination] = sum
+					}
+					sum.sum += float64(cost) * factor
+					sum.factors += factor
+				}
+			}
+		}
+	}
+
+	for key, origins := range table.links {
+		for origin, destinations := range origins {
+			for destination, l := range destinations {
+				sum := sums[key][origin][destination]
+				l.cost = int64(sum.sum / sum.factors)
+				destinations[destination] = l
+				if l.cost > table.max[key] {
+					table.max[key] = l.cost
+				}
+			}
+		}
+		if table.defaultCost != nil && *table.defaultCost > table.max[key] {
+			table.max[key] = *table.defaultCost
+		}
+	}
+	return table
+}
+
+// selectWeights returns the weights of nt named in weightsNames, in the order of nt.
+func selectWeights(nt *v1alpha1.NetworkTopology, weightsNames []string) []v1alpha1.WeightInfo {
+	var weights []v1alpha1.WeightInfo
+	for _, w := range nt.Spec.Weights {
+		for _, n := range weightsNames {
+			if w.Name == n {
+				weights = append(weights, w)
+				break
+			}
+		}
+	}
+	return weights
+}
+
+// sameCostUnit tells whether the weights share their cost unit. Weights mixing units are assumed
+// to differ from the others.
+func sameCostUnit(weights []v1alpha1.WeightInfo) bool {
+	if len(weights) < 2 {
+		return true
+	}
+	first, err := WeightsCostUnit(weights[0])
+	if err != nil {
+		return false
+	}
+	for _, w := range weights[1:] {
+		if unit, err := WeightsCostUnit(w); err != nil || unit != first {
+			return false
+		}
+	}
+	return true
+}
+
+// weightsMaxCosts returns the highest cost of the default class of the weights w per topology key.
+func weightsMaxCosts(w v1alpha1.WeightInfo) map[v1alpha1.TopologyKey]int64 {
+	maxCosts := make(map[v1alpha1.TopologyKey]int64)
+	for _, t := range w.TopologyList {
+		if t.InterfaceClass != "" {
+			continue
+		}
+		for _, o := range t.OriginList {
+			for _, c := range o.CostList {
+				if c.NetworkCost > maxCosts[t.TopologyKey] {
+					maxCosts[t.TopologyKey] = c.NetworkCost
+				}
+			}
+		}
+	}
+	return maxCosts
+}
+
+// DrainingZones returns the deadlines of the draining zones of nt, keyed by zone, nil for the
+// zones without any.
+func DrainingZones(nt *v1alpha1.NetworkTopology) map[string]*metav1.Time {
+	draining := make(map[string]*metav1.Time, len(nt.Spec.DrainingZones))
+	for _, z := range nt.Spec.DrainingZones {
+		draining[z.Zone] = z.Deadline
+	}
+	return draining
+}
+
+func (t *costTable) isDraining(zone string) bool {
+	_, ok := t.draining[zone]
+	return ok
+}
+
+// LinkHeadroom returns the bandwidth still available on a link: its capacity, capped at
+// maxUtilizationPercent of it if set, minus the allocated bandwidth. The headroom of a link
+// made of parallel links is the sum of theirs.
+func LinkHeadroom(c v1alpha1.CostInfo, maxUtilizationPercent int32) resource.Quantity {
+	if len(c.Links) == 0 {
+		return headroom(c.BandwidthCapacity, c.BandwidthAllocated, maxUtilizationPercent)
+	}
+	var total resource.Quantity
+	for _, l := range c.Links {
+		total.Add(headroom(l.BandwidthCapacity, l.BandwidthAllocated, maxUtilizationPercent))
+	}
+	return total
+}
+
+func headroom(capacity, allocated resource.Quantity, maxUtilizationPercent int32) resource.Quantity {
+	headroom := capacity.DeepCopy()
+	if maxUtilizationPercent > 0 && maxUtilizationPercent < 100 {
+		headroom = *resource.NewQuantity(capacity.Value()*int64(maxUtilizationPercent)/100, capacity.Format)
+	}
+	headroom.Sub(allocated)
+	if headroom.Sign() < 0 {
+		headroom = resource.Quantity{}
+	}
+	return headroom
+}
+
+// LinkBandwidth returns the capacity and the allocated bandwidth of a link, summed over its
+// parallel links if any.
+func LinkBandwidth(c v1alpha1.CostInfo) (capacity, allocated resource.Quantity) {
+	if len(c.Links) == 0 {
+		return c.BandwidthCapacity.DeepCopy(), c.BandwidthAllocated.DeepCopy()
+	}
+	for _, l := range c.Links {
+		capacity.Add(l.BandwidthCapacity)
+		allocated.Add(l.BandwidthAllocated)
+	}
+	return capacity, allocated
+}
+
+// AllocateBandwidth allocates bandwidth on a link, spread across its parallel links in proportion
+// to their headroom, so that they fill up evenly. Nothing is allocated, and false returned, if the
+// headroom of the link is not enough.
+func AllocateBandwidth(c *v1alpha1.CostInfo, bandwidth resource.Quantity, maxUtilizationPercent int32) bool {
+	total := LinkHeadroom(*c, maxUtilizationPercent)
+	if total.Cmp(bandwidth) < 0 {
+		return false
+	}
+	if len(c.Links) == 0 {
+		c.BandwidthAllocated.Add(bandwidth)
+		return true
+	}
+
+	remaining, largest := bandwidth.Value(), 0
+	shares := make([]int64, len(c.Links))
+	headrooms := make([]int64, len(c.Links))
+	for i, l := range c.Links {
+		h := headroom(l.BandwidthCapacity, l.BandwidthAllocated, maxUtilizationPercent)
+		headrooms[i] = h.Value()
+		if headrooms[i] > headrooms[largest] {
+			largest = i
+		}
+	}
+	for i := range c.Links {
+		if total.Value() > 0 {
+			shares[i] = int64(float64(bandwidth.Value()) * float64(headrooms[i]) / float64(total.Value()))
+		}
+		remaining -= shares[i]
+	}
+	// The rounding remainder goes to the link with the most headroom.
+	shares[largest] += remaining
+	for i := range c.Links {
+		c.Links[i].BandwidthAllocated.Add(*resource.NewQuantity(shares[i], bandwidth.Format))
+	}
+	return true
+}
+
+func (co *CostOracle) getLink(key v1alpha1.TopologyKey, origin, destination string) (link, bool) {
+	co.RLock()
+	defer co.RUnlock()
+	if co.table == nil {
+		return link{}, false
+	}
+	l, ok := co.table.links[key][origin][destination]
+	return l, ok
+}
+
+// GetCost returns the network cost from origin to destination for the given topology key.
+// The cost within an origin is 0, the one of a pair missing from the weights the default
+// cost of the NetworkTopology. ok is false if the cost is unknown.
+func (co *CostOracle) GetCost(key v1alpha1.TopologyKey, origin, destination string) (cost int64, ok bool) {
+	if origin == destination {
+		return 0, true
+	}
+	if l, ok := co.getLink(key, origin, destination); ok {
+		return l.cost, true
+	}
+	co.RLock()
+	defer co.RUnlock()
+	if co.table == nil || co.table.defaultCost == nil {
+		return 0, false
+	}
+	return *co.table.defaultCost, true
+}
+
+// GetZoneCost returns the network cost from the origin zone to the destination zone.
+func (co *CostOracle) GetZoneCost(origin, destination string) (int64, bool) {
+	return co.GetCost(v1alpha1.NetworkTopologyZone, origin, destination)
+}
+
+// GetRegionCost returns the network cost from the origin region to the destination region.
+func (co *CostOracle) GetRegionCost(origin, destination string) (int64, bool) {
+	return co.GetCost(v1alpha1.NetworkTopologyRegion, origin, destination)
+}
+
+// GetMaxCost returns the highest network cost for the given topology key, 0 if none is known.
+func (co *CostOracle) GetMaxCost(key v1alpha1.TopologyKey) int64 {
+	co.RLock()
+	defer co.RUnlock()
+	if co.table == nil {
+		return 0
+	}
+	return co.table.max[key]
+}
+
+// GetLinkHeadroom returns the bandwidth still available from origin to destination for the
+// given topology key, i.e. the capacity minus the allocated bandwidth, if the link and its
+// capacity are known. The capacity is capped by the LinkUtilizationPolicy of the topology key,
+// if any. The links from or to a draining zone have no headroom.
+func (co *CostOracle) GetLinkHeadroom(key v1alpha1.TopologyKey, origin, destination string) (resource.Quantity, bool) {
+	l, ok := co.getLink(key, origin, destination)
+	if !ok || !l.bounded {
+		return resource.Quantity{}, false
+	}
+	return l.headroom.DeepCopy(), true
+}
+
+// ZoneDraining tells whether zone is draining, and whether it is past the deadline of its
+// draining, after which the plugins reject it instead of only scoring it the lowest.
+func (co *CostOracle) ZoneDraining(zone string) (draining, pastDeadline bool) {
+	co.RLock()
+	defer co.RUnlock()
+	if co.table == nil {
+		return false, false
+	}
+	deadline, ok := co.table.draining[zone]
+	if !ok {
+		return false, false
+	}
+	return true, deadline != nil && !time.Now().Before(deadline.Time)
+}
+
+// GetCostAge returns the age of the network costs at now, since the weightCalculationTime of the
+// NetworkTopology, if known.
+func (co *CostOracle) GetCostAge(now time.Time) (time.Duration, bool) {
+	co.RLock()
+	defer co.RUnlock()
+	if co.table == nil || co.table.calculatedAt.IsZero() {
+		return 0, false
+	}
+	return now.Sub(co.table.calculatedAt), true
+}
@@ -0,0 +1,407 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+)
+
+func makeTopology(name string) *v1alpha1.NetworkTopology {
+	return &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{
+				{
+					Name: "UserDefined",
+					TopologyList: v1alpha1.TopologyList{
+						{
+							TopologyKey: v1alpha1.NetworkTopologyRegion,
+							OriginList: v1alpha1.OriginList{
+								{Origin: "us-east", CostList: v1alpha1.CostList{{Destination: "us-west", NetworkCost: 20}}},
+							},
+						},
+						{
+							TopologyKey: v1alpha1.NetworkTopologyZone,
+							OriginList: v1alpha1.OriginList{
+								{Origin: "z1", CostList: v1alpha1.CostList{{
+									Destination:        "z2",
+									NetworkCost:        5,
+									BandwidthCapacity:  resource.MustParse("10Gi"),
+									BandwidthAllocated: resource.MustParse("4Gi"),
+								}}},
+								{Origin: "z2", CostList: v1alpha1.CostList{{
+									Destination:        "z1",
+									NetworkCost:        3,
+									BandwidthCapacity:  resource.MustParse("1Gi"),
+									BandwidthAllocated: resource.MustParse("2Gi"),
+								}}},
+							},
+						},
+					},
+				},
+				{
+					Name: "NetperfCosts",
+					TopologyList: v1alpha1.TopologyList{{
+						TopologyKey: v1alpha1.NetworkTopologyZone,
+						OriginList: v1alpha1.OriginList{
+							{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 100}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestCostOracle(t *testing.T) {
+	cs := fakeclientset.NewSimpleClientset(makeTopology("nt"), makeTopology("other"))
+	informerFactory := schedinformers.NewSharedInformerFactory(cs, 0)
+	co, err := New(informerFactory.Scheduling().V1alpha1().NetworkTopologies(), "default", "nt", "UserDefined")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := co.GetZoneCost("z1", "z2"); ok {
+		t.Errorf("expected no cost before the NetworkTopology is synced")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		_, ok := co.GetZoneCost("z1", "z2")
+		return ok, nil
+	}); err != nil {
+		t.Fatalf("NetworkTopology not observed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      func() (int64, bool)
+		expected int64
+		found    bool
+	}{
+		{name: "zone cost", got: func() (int64, bool) { return co.GetZoneCost("z1", "z2") }, expected: 5, found: true},
+		{name: "zone costs are directed", got: func() (int64, bool) { return co.GetZoneCost("z2", "z1") }, expected: 3, found: true},
+		{name: "same zone", got: func() (int64, bool) { return co.GetZoneCost("z1", "z1") }, expected: 0, found: true},
+		{name: "unknown zone", got: func() (int64, bool) { return co.GetZoneCost("z1", "z3") }, expected: 0, found: false},
+		{name: "region cost", got: func() (int64, bool) { return co.GetRegionCost("us-east", "us-west") }, expected: 20, found: true},
+		{name: "max zone cost", got: func() (int64, bool) { return co.GetMaxCost(v1alpha1.NetworkTopologyZone), true }, expected: 5, found: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tt.got()
+			if got != tt.expected || found != tt.found {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.found, got, found)
+			}
+		})
+	}
+
+	headroom, ok := co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z1", "z2")
+	if expected := resource.MustParse("6Gi"); !ok || headroom.Cmp(expected) != 0 {
+		t.Errorf("expected headroom %v, got %v (found: %v)", expected.String(), headroom.String(), ok)
+	}
+	headroom, ok = co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z2", "z1")
+	if !ok || !headroom.IsZero() {
+		t.Errorf("expected an overcommitted link to have no headroom, got %v (found: %v)", headroom.String(), ok)
+	}
+	if _, ok := co.GetLinkHeadroom(v1alpha1.NetworkTopologyRegion, "us-east", "us-west"); ok {
+		t.Errorf("expected no headroom for a link without capacity")
+	}
+
+	if err := cs.SchedulingV1alpha1().NetworkTopologies("default").Delete(ctx, "nt", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		_, ok := co.GetZoneCost("z1", "z2")
+		return !ok, nil
+	}); err != nil {
+		t.Errorf("NetworkTopology deletion not observed: %v", err)
+	}
+}
+
+func TestLinkHeadroom(t *testing.T) {
+	tests := []struct {
+		name                  string
+		capacity, allocated   string
+		maxUtilizationPercent int32
+		expected              string
+	}{
+		{name: "no policy", capacity: "10Gi", allocated: "4Gi", expected: "6Gi"},
+		{name: "below the watermark", capacity: "10G", allocated: "4G", maxUtilizationPercent: 80, expected: "4G"},
+		{name: "above the watermark", capacity: "10G", allocated: "9G", maxUtilizationPercent: 80, expected: "0"},
+		{name: "full capacity allowed", capacity: "10G", allocated: "9G", maxUtilizationPercent: 100, expected: "1G"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := v1alpha1.CostInfo{
+				BandwidthCapacity:  resource.MustParse(tt.capacity),
+				BandwidthAllocated: resource.MustParse(tt.allocated),
+			}
+			got := LinkHeadroom(c, tt.maxUtilizationPercent)
+			if expected := resource.MustParse(tt.expected); got.Cmp(expected) != 0 {
+				t.Errorf("expected headroom %v, got %v", expected.String(), got.String())
+			}
+		})
+	}
+}
+
+func TestParallelLinks(t *testing.T) {
+	link := func(name, capacity, allocated string) v1alpha1.ParallelLink {
+		return v1alpha1.ParallelLink{Name: name, BandwidthCapacity: resource.MustParse(capacity), BandwidthAllocated: resource.MustParse(allocated)}
+	}
+	c := v1alpha1.CostInfo{
+		Destination: "z2",
+		// Ignored in favor of the parallel links.
+		BandwidthCapacity: resource.MustParse("1G"),
+		Links:             []v1alpha1.ParallelLink{link("path-a", "10G", "2G"), link("path-b", "10G", "6G"), link("path-c", "5G", "5G")},
+	}
+
+	capacity, allocated := LinkBandwidth(c)
+	if capacity.Cmp(resource.MustParse("25G")) != 0 || allocated.Cmp(resource.MustParse("13G")) != 0 {
+		t.Errorf("expected 13G allocated of 25G, got %v of %v", allocated.String(), capacity.String())
+	}
+	if got := LinkHeadroom(c, 0); got.Cmp(resource.MustParse("12G")) != 0 {
+		t.Errorf("expected aggregate headroom 12G, got %v", got.String())
+	}
+	// path-a has 6G left below the watermark, path-b 2G, path-c none.
+	if got := LinkHeadroom(c, 80); got.Cmp(resource.MustParse("8G")) != 0 {
+		t.Errorf("expected aggregate headroom 8G, got %v", got.String())
+	}
+
+	if AllocateBandwidth(&c, resource.MustParse("9G"), 80) {
+		t.Errorf("expected no allocation beyond the aggregate headroom")
+	}
+	if !AllocateBandwidth(&c, resource.MustParse("4G"), 80) {
+		t.Fatalf("expected the allocation to fit in the aggregate headroom")
+	}
+	for i, expected := range []string{"5G", "7G", "5G"} {
+		if got := c.Links[i].BandwidthAllocated; got.Cmp(resource.MustParse(expected)) != 0 {
+			t.Errorf("expected %v allocated on %v, got %v", expected, c.Links[i].Name, got.String())
+		}
+	}
+}
+
+func TestCostOracleDefaultCost(t *testing.T) {
+	nt := makeTopology("nt")
+	defaultCost := int64(50)
+	nt.Spec.DefaultCost = &defaultCost
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}, table: newCostTable(nt, []string{"UserDefined"}, nil)}
+
+	if cost, ok := co.GetZoneCost("z1", "z2"); !ok || cost != 5 {
+		t.Errorf("expected the cost of a known link, got (%v, %v)", cost, ok)
+	}
+	if cost, ok := co.GetZoneCost("z1", "z3"); !ok || cost != defaultCost {
+		t.Errorf("expected the default cost for a missing link, got (%v, %v)", cost, ok)
+	}
+	if max := co.GetMaxCost(v1alpha1.NetworkTopologyZone); max != defaultCost {
+		t.Errorf("expected the default cost to be the max cost, got %v", max)
+	}
+	if _, ok := co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z1", "z3"); ok {
+		t.Errorf("expected no headroom for a missing link")
+	}
+}
+
+func TestCostOracleConcurrentUse(t *testing.T) {
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}}
+	nt := makeTopology("nt")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				co.GetZoneCost("z1", "z2")
+				co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z1", "z2")
+				co.GetMaxCost(v1alpha1.NetworkTopologyZone)
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		co.update(nt)
+		co.update(nil)
+	}
+	wg.Wait()
+}
+
+func TestCostOracleIgnoresShadow(t *testing.T) {
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}}
+	nt := makeTopology("nt")
+	co.update(nt)
+	if _, ok := co.GetZoneCost("z1", "z2"); !ok {
+		t.Fatalf("expected the NetworkTopology to be served")
+	}
+	nt.Annotations = map[string]string{v1alpha1.NetworkTopologyShadowOfAnnotation: "live"}
+	co.update(nt)
+	if _, ok := co.GetZoneCost("z1", "z2"); ok {
+		t.Errorf("expected a shadow NetworkTopology to be ignored")
+	}
+}
+
+func TestCostOracleIgnoresInterfaceClasses(t *testing.T) {
+	nt := makeTopology("nt")
+	nt.Spec.Weights[0].TopologyList = append(nt.Spec.Weights[0].TopologyList, v1alpha1.TopologyInfo{
+		TopologyKey:    v1alpha1.NetworkTopologyZone,
+		InterfaceClass: "storage",
+		OriginList: v1alpha1.OriginList{
+			{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}, {Destination: "z3", NetworkCost: 500}}},
+		},
+	})
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}, table: newCostTable(nt, []string{"UserDefined"}, nil)}
+
+	if cost, ok := co.GetZoneCost("z1", "z2"); !ok || cost != 5 {
+		t.Errorf("expected the cost of the default class, got (%v, %v)", cost, ok)
+	}
+	if _, ok := co.GetZoneCost("z1", "z3"); ok {
+		t.Errorf("expected no cost for a link only known to another class")
+	}
+	if max := co.GetMaxCost(v1alpha1.NetworkTopologyZone); max != 5 {
+		t.Errorf("expected the max cost of the default class, got %v", max)
+	}
+}
+
+func TestCostOracleDrainingZones(t *testing.T) {
+	nt := makeTopology("nt")
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	nt.Spec.DrainingZones = []v1alpha1.DrainingZone{{Zone: "z2"}, {Zone: "z3", Deadline: &past}}
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}, table: newCostTable(nt, []string{"UserDefined"}, nil)}
+
+	for zone, expected := range map[string][2]bool{"z1": {false, false}, "z2": {true, false}, "z3": {true, true}} {
+		if draining, pastDeadline := co.ZoneDraining(zone); draining != expected[0] || pastDeadline != expected[1] {
+			t.Errorf("expected zone %v draining %v past deadline %v, got %v, %v", zone, expected[0], expected[1], draining, pastDeadline)
+		}
+	}
+	if headroom, ok := co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z1", "z2"); !ok || !headroom.IsZero() {
+		t.Errorf("expected no headroom on the links of a draining zone, got (%v, %v)", headroom.String(), ok)
+	}
+	if cost, ok := co.GetZoneCost("z1", "z2"); !ok || cost != 5 {
+		t.Errorf("expected the cost of the links of a draining zone to be kept, got (%v, %v)", cost, ok)
+	}
+}
+
+func TestCostOracleBlendedWeights(t *testing.T) {
+	weightsNames := []string{"UserDefined", "NetperfCosts"}
+	nt := makeTopology("nt")
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: weightsNames, table: newCostTable(nt, weightsNames, nil)}
+	if cost, _ := co.GetZoneCost("z1", "z2"); cost != 52 {
+		t.Errorf("expected the costs of the same unit to be averaged, got %v", cost)
+	}
+	if cost, _ := co.GetZoneCost("z2", "z1"); cost != 3 {
+		t.Errorf("expected the cost known to a single weights to be kept, got %v", cost)
+	}
+
+	nt.Spec.Weights[1].TopologyList[0].CostUnit = v1alpha1.NetworkCostUnitMilliseconds
+	defaultCost := int64(50)
+	nt.Spec.DefaultCost = &defaultCost
+	co.table = newCostTable(nt, weightsNames, nil)
+	tests := []struct {
+		name     string
+		key      v1alpha1.TopologyKey
+		origin   string
+		dest     string
+		expected int64
+	}{
+		{name: "highest costs of both weights", key: v1alpha1.NetworkTopologyZone, origin: "z1", dest: "z2", expected: 1000},
+		{name: "cost of a single weights", key: v1alpha1.NetworkTopologyZone, origin: "z2", dest: "z1", expected: 600},
+		{name: "region cost", key: v1alpha1.NetworkTopologyRegion, origin: "us-east", dest: "us-west", expected: 1000},
+		{name: "default cost", key: v1alpha1.NetworkTopologyZone, origin: "z1", dest: "z3", expected: NormalizedMaxCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cost, ok := co.GetCost(tt.key, tt.origin, tt.dest); !ok || cost != tt.expected {
+				t.Errorf("expected the normalized cost %v, got (%v, %v)", tt.expected, cost, ok)
+			}
+		})
+	}
+	if headroom, ok := co.GetLinkHeadroom(v1alpha1.NetworkTopologyZone, "z1", "z2"); !ok || headroom.Cmp(resource.MustParse("6Gi")) != 0 {
+		t.Errorf("expected the headroom of the first weights, got (%v, %v)", headroom.String(), ok)
+	}
+}
+
+func TestCostOracleBlendFactors(t *testing.T) {
+	weightsNames, factors, err := parseWeightsNames("UserDefined:3, NetperfCosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nt := makeTopology("nt")
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: weightsNames, factors: factors, table: newCostTable(nt, weightsNames, factors)}
+	if cost, _ := co.GetZoneCost("z1", "z2"); cost != 28 {
+		t.Errorf("expected the costs to be averaged by their factors, got %v", cost)
+	}
+	if cost, _ := co.GetZoneCost("z2", "z1"); cost != 3 {
+		t.Errorf("expected the cost known to a single weights to be kept, got %v", cost)
+	}
+}
+
+func TestParseWeightsNames(t *testing.T) {
+	tests := []struct {
+		weightsName     string
+		expectedNames   []string
+		expectedFactors map[string]float64
+		expectedErr     bool
+	}{
+		{weightsName: "UserDefined", expectedNames: []string{"UserDefined"}},
+		{weightsName: "UserDefined, Mesh,", expectedNames: []string{"UserDefined", "Mesh"}},
+		{weightsName: "Manual:0.7,Dijkstra: 0.3", expectedNames: []string{"Manual", "Dijkstra"},
+			expectedFactors: map[string]float64{"Manual": 0.7, "Dijkstra": 0.3}},
+		{weightsName: "Manual:2,Mesh", expectedNames: []string{"Manual", "Mesh"}, expectedFactors: map[string]float64{"Manual": 2}},
+		{weightsName: "Manual:0", expectedErr: true},
+		{weightsName: "Manual:-1", expectedErr: true},
+		{weightsName: "Manual:half", expectedErr: true},
+		{weightsName: ":0.5", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.weightsName, func(t *testing.T) {
+			names, factors, err := parseWeightsNames(tt.weightsName)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(names, tt.expectedNames) || !reflect.DeepEqual(factors, tt.expectedFactors) {
+				t.Errorf("expected %v %v, got %v %v", tt.expectedNames, tt.expectedFactors, names, factors)
+			}
+		})
+	}
+}
+
+func TestWeightsCostUnit(t *testing.T) {
+	nt := makeTopology("nt")
+	if unit, err := WeightsCostUnit(nt.Spec.Weights[0]); err != nil || unit != v1alpha1.NetworkCostUnitAbstract {
+		t.Errorf("expected the Abstract unit by default, got (%v, %v)", unit, err)
+	}
+	nt.Spec.Weights[0].TopologyList[1].CostUnit = v1alpha1.NetworkCostUnitMilliDollarsPerGB
+	if _, err := WeightsCostUnit(nt.Spec.Weights[0]); err == nil {
+		t.Errorf("expected an error for weights mixing cost units")
+	}
+	nt.Spec.Weights[0].TopologyList[0].CostUnit = v1alpha1.NetworkCostUnitMilliDollarsPerGB
+	if unit, err := WeightsCostUnit(nt.Spec.Weights[0]); err != nil || unit != v1alpha1.NetworkCostUnitMilliDollarsPerGB {
+		t.Errorf("expected the declared unit, got (%v, %v)", unit, err)
+	}
+}
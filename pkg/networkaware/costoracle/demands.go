@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// Placement is the zone and region of a node.
+type Placement struct {
+	Zone   string
+	Region string
+}
+
+// NodePlacement returns the placement of node, from its topology labels.
+func NodePlacement(node *v1.Node) Placement {
+	return Placement{Zone: node.Labels[v1.LabelTopologyZone], Region: node.Labels[v1.LabelTopologyRegion]}
+}
+
+// Domain returns the zone or the region of the placement, depending on key.
+func (p Placement) Domain(key v1alpha1.TopologyKey) string {
+	if key == v1alpha1.NetworkTopologyRegion {
+		return p.Region
+	}
+	return p.Zone
+}
+
+// BandwidthDemand is the bandwidth a pod demands on the link to the pods of one of its dependencies.
+type BandwidthDemand struct {
+	// Dependency is the selector of the workload depended on.
+	Dependency string
+	// Egress is demanded from the pod to the dependency.
+	Egress resource.Quantity
+	// Ingress is demanded from the dependency to the pod.
+	Ingress resource.Quantity
+	// Class of the bandwidth demanded, Guaranteed if not set on the dependency.
+	Class v1alpha1.BandwidthClass
+}
+
+// BandwidthDemands returns the bandwidth demands of the pods of the workload selector of ag, the
+// canaries demanding their share of the dependencies of their primary. The egress and ingress of a
+// dependency are its minEgressBandwidth and minIngressBandwidth, its minBandwidth if not set. The
+// zone-local dependencies, always having a pod in the zone of the workload, cross no link and
+// demand nothing.
+func BandwidthDemands(ag *v1alpha1.AppGroup, selector string) []BandwidthDemand {
+	var demands []BandwidthDemand
+	for _, w := range util.WithCanaryDependencies(ag.Spec.Workloads) {
+		if w.Workload.Selector != selector {
+			continue
+		}
+		for _, d := range w.Dependencies {
+			if d.ZoneLocal {
+				continue
+			}
+			demand := BandwidthDemand{Dependency: d.Workload.Selector, Egress: d.MinBandwidth.DeepCopy(), Ingress: d.MinBandwidth.DeepCopy(),
+				Class: d.BandwidthClass}
+			if demand.Class == "" {
+				demand.Class = v1alpha1.BandwidthGuaranteed
+			}
+			if d.MinEgressBandwidth != nil {
+				demand.Egress = d.MinEgressBandwidth.DeepCopy()
+			}
+			if d.MinIngressBandwidth != nil {
+				demand.Ingress = d.MinIngressBandwidth.DeepCopy()
+			}
+			if demand.Egress.Sign() <= 0 && demand.Ingress.Sign() <= 0 {
+				continue
+			}
+			demands = append(demands, demand)
+		}
+	}
+	return demands
+}
+
+// DependencyLink returns the link carrying the traffic between a pod placed in from and the pods of
+// one of its dependencies placed in peers, as the topology key and the domain of the peers at the
+// other end: the zone of the region of the pod hosting the most peers, or the region hosting the
+// most peers if none shares the region of the pod, ties going to the first name. ok is false if a
+// peer shares the zone of the pod, so that no link is crossed, or if the domains are unknown.
+func DependencyLink(from Placement, peers []Placement) (key v1alpha1.TopologyKey, destination string, ok bool) {
+	zones := make(map[string]int)
+	regions := make(map[string]int)
+	sameRegion := false
+	for _, p := range peers {
+		switch {
+		case from.Zone != "" && p.Zone == from.Zone:
+			return "", "", false
+		case p.Region == from.Region:
+			sameRegion = true
+			if p.Zone != "" {
+				zones[p.Zone]++
+			}
+		case from.Region != "" && p.Region != "":
+			regions[p.Region]++
+		}
+	}
+	if sameRegion {
+		if zone := mostCommon(zones); zone != "" && from.Zone != "" {
+			return v1alpha1.NetworkTopologyZone, zone, true
+		}
+		return "", "", false
+	}
+	if region := mostCommon(regions); region != "" {
+		return v1alpha1.NetworkTopologyRegion, region, true
+	}
+	return "", "", false
+}
+
+// mostCommon returns the domain counted the most, the first name among equals, empty if none.
+func mostCommon(counts map[string]int) string {
+	var domain string
+	for d, n := range counts {
+		if domain == "" || n > counts[domain] || (n == counts[domain] && d < domain) {
+			domain = d
+		}
+	}
+	return domain
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestBandwidthDemands(t *testing.T) {
+	egress, ingress := resource.MustParse("300M"), resource.MustParse("0")
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "frontend"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("100M")},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "cache"}, MinBandwidth: resource.MustParse("50M"), ZoneLocal: true},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "metrics"}},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "storage"}, MinBandwidth: resource.MustParse("100M"),
+					MinEgressBandwidth: &egress, MinIngressBandwidth: &ingress},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "logs"}, MinEgressBandwidth: &egress, BandwidthClass: v1alpha1.BandwidthBestEffort},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+		}},
+	}
+	expected := []BandwidthDemand{
+		{Dependency: "backend", Egress: resource.MustParse("100M"), Ingress: resource.MustParse("100M"), Class: v1alpha1.BandwidthGuaranteed},
+		{Dependency: "storage", Egress: egress, Ingress: ingress, Class: v1alpha1.BandwidthGuaranteed},
+		{Dependency: "logs", Egress: egress, Class: v1alpha1.BandwidthBestEffort},
+	}
+	if got := BandwidthDemands(ag, "frontend"); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected the demands %v, got %v", expected, got)
+	}
+	if got := BandwidthDemands(ag, "backend"); len(got) != 0 {
+		t.Errorf("expected no demand of a workload without dependencies, got %v", got)
+	}
+}
+
+func TestDependencyLink(t *testing.T) {
+	at := func(region, zone string) Placement {
+		return Placement{Region: region, Zone: zone}
+	}
+	tests := []struct {
+		name        string
+		from        Placement
+		peers       []Placement
+		key         v1alpha1.TopologyKey
+		destination string
+		ok          bool
+	}{
+		{
+			name:  "peer in the zone",
+			from:  at("r1", "z1"),
+			peers: []Placement{at("r2", "z3"), at("r1", "z1")},
+		},
+		{
+			name:        "zone of the region hosting the most peers",
+			from:        at("r1", "z1"),
+			peers:       []Placement{at("r2", "z4"), at("r1", "z3"), at("r1", "z2"), at("r1", "z3")},
+			key:         v1alpha1.NetworkTopologyZone,
+			destination: "z3",
+			ok:          true,
+		},
+		{
+			name:        "ties going to the first zone",
+			from:        at("r1", "z1"),
+			peers:       []Placement{at("r1", "z3"), at("r1", "z2")},
+			key:         v1alpha1.NetworkTopologyZone,
+			destination: "z2",
+			ok:          true,
+		},
+		{
+			name:        "region hosting the most peers",
+			from:        at("r1", "z1"),
+			peers:       []Placement{at("r2", "z2"), at("r3", "z3"), at("r3", "z4")},
+			key:         v1alpha1.NetworkTopologyRegion,
+			destination: "r3",
+			ok:          true,
+		},
+		{
+			name:  "no peer",
+			from:  at("r1", "z1"),
+			peers: nil,
+		},
+		{
+			name:  "unknown zone of the pod",
+			from:  at("r1", ""),
+			peers: []Placement{at("r1", "z2")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, destination, ok := DependencyLink(tt.from, tt.peers)
+			if key != tt.key || destination != tt.destination || ok != tt.ok {
+				t.Errorf("expected (%q, %q, %v), got (%q, %q, %v)", tt.key, tt.destination, tt.ok, key, destination, ok)
+			}
+		})
+	}
+}
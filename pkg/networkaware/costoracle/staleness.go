@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	networkCostWeight = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "network_cost_weight",
+			Help:           "Weight of the network costs in the score of a plugin, below 1 once they are stale.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin"})
+
+	registerStalenessMetrics sync.Once
+)
+
+// StalenessDecay discounts the network costs in the score of a plugin as they age: past threshold
+// since their calculation, their weight decays linearly, down to none at twice threshold, so that
+// stale costs do not dominate the placement after the controller computing them has been down.
+type StalenessDecay struct {
+	plugin    string
+	threshold time.Duration
+}
+
+// NewStalenessDecay returns the staleness decay of the network costs of plugin. The costs never
+// decay if threshold is not positive.
+func NewStalenessDecay(plugin string, threshold time.Duration) *StalenessDecay {
+	registerStalenessMetrics.Do(func() {
+		legacyregistry.MustRegister(networkCostWeight)
+	})
+	return &StalenessDecay{plugin: plugin, threshold: threshold}
+}
+
+// Weight returns the weight, between 0 and 1, of the network costs of co at now. The costs whose
+// age is unknown keep their full weight.
+func (d *StalenessDecay) Weight(co *CostOracle, now time.Time) float64 {
+	if d == nil || d.threshold <= 0 || co == nil {
+		return 1
+	}
+	weight := 1.0
+	if age, ok := co.GetCostAge(now); ok && age > d.threshold {
+		weight = 1 - float64(age-d.threshold)/float64(d.threshold)
+		if weight < 0 {
+			weight = 0
+		}
+	}
+	networkCostWeight.WithLabelValues(d.plugin).Set(weight)
+	return weight
+}
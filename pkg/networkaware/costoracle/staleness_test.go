@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costoracle
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStalenessDecay(t *testing.T) {
+	now := time.Now()
+	nt := makeTopology("nt")
+	nt.Status.WeightCalculationTime = metav1.NewTime(now.Add(-90 * time.Minute))
+	co := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}, table: newCostTable(nt, []string{"UserDefined"}, nil)}
+	unknownAge := &CostOracle{namespace: "default", name: "nt", weightsNames: []string{"UserDefined"}, table: newCostTable(makeTopology("nt"), []string{"UserDefined"}, nil)}
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		oracle    *CostOracle
+		expected  float64
+	}{
+		{name: "decay disabled", threshold: 0, oracle: co, expected: 1},
+		{name: "fresh costs", threshold: 2 * time.Hour, oracle: co, expected: 1},
+		{name: "stale costs", threshold: time.Hour, oracle: co, expected: 0.5},
+		{name: "costs twice as old as the threshold", threshold: 45 * time.Minute, oracle: co, expected: 0},
+		{name: "costs older than twice the threshold", threshold: 30 * time.Minute, oracle: co, expected: 0},
+		{name: "unknown calculation time", threshold: time.Minute, oracle: unknownAge, expected: 1},
+		{name: "no NetworkTopology", threshold: time.Minute, oracle: nil, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewStalenessDecay("test", tt.threshold).Weight(tt.oracle, now); got != tt.expected {
+				t.Errorf("expected the weight %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossregionlimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// CrossRegionRateLimit is a permit plugin bounding the rate of the pods placed in another region
+// than the pods of their AppGroup, so that bursts of bandwidth reservations on the expensive WAN
+// links are smoothed while the controller's accounting catches up.
+type CrossRegionRateLimit struct {
+	handle     framework.Handle
+	podIndexer cache.Indexer
+	limiter    *rate.Limiter
+	maxWait    time.Duration
+	nodeFilter util.NodeFilter
+}
+
+var _ framework.PermitPlugin = &CrossRegionRateLimit{}
+var _ framework.ReservePlugin = &CrossRegionRateLimit{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "CrossRegionRateLimit"
+
+	// reservationStateKey is the key in CycleState to the rate limiter reservation of the pod.
+	reservationStateKey = "Reservation" + Name
+
+	// allowGracePeriod is added to the wait of a pod in Permit, so that it is allowed before timing out.
+	allowGracePeriod = 5 * time.Second
+)
+
+// reservationState holds the rate limiter reservation of a cross-region placement.
+type reservationState struct {
+	reservation *rate.Reservation
+}
+
+// Clone the reservation state.
+func (s *reservationState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.CrossRegionRateLimitArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type CrossRegionRateLimitArgs, got %T", obj)
+	}
+	if args.MaxPlacementsPerMinute <= 0 || args.Burst <= 0 {
+		return nil, fmt.Errorf("maxPlacementsPerMinute and burst should be positive, got %d and %d", args.MaxPlacementsPerMinute, args.Burst)
+	}
+	if args.MaxWaitSeconds < 0 {
+		return nil, fmt.Errorf("maxWaitSeconds should not be negative, got %d", args.MaxWaitSeconds)
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	if err := util.AddAppGroupPodIndex(podInformer); err != nil {
+		return nil, err
+	}
+	return newCrossRegionRateLimit(handle, podInformer.GetIndexer(), args), nil
+}
+
+func newCrossRegionRateLimit(handle framework.Handle, podIndexer cache.Indexer, args *config.CrossRegionRateLimitArgs) *CrossRegionRateLimit {
+	return &CrossRegionRateLimit{
+		handle:     handle,
+		podIndexer: podIndexer,
+		limiter:    rate.NewLimiter(rate.Limit(float64(args.MaxPlacementsPerMinute)/60), int(args.Burst)),
+		maxWait:    time.Duration(args.MaxWaitSeconds) * time.Second,
+		nodeFilter: util.NodeFilter{
+			IgnoreNotReady:      args.IgnoreNotReadyNodes,
+			IgnoreUnschedulable: args.IgnoreUnschedulableNodes,
+			IgnoredTaints:       args.IgnoredTaintKeys,
+		},
+	}
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (cr *CrossRegionRateLimit) Name() string {
+	return Name
+}
+
+// Permit lets the pods placed in the region of the pods of their AppGroup through. The other ones
+// take a slot of the rate limit: they wait for it to free up, or are rejected if it would take
+// longer than the maximum wait.
+func (cr *CrossRegionRateLimit) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	crossRegion, err := cr.isCrossRegion(pod, nodeName)
+	if err != nil {
+		return framework.AsStatus(err), 0
+	}
+	if !crossRegion {
+		return nil, 0
+	}
+
+	r := cr.limiter.Reserve()
+	delay := r.Delay()
+	if !r.OK() || delay > cr.maxWait {
+		r.Cancel()
+		return framework.NewStatus(framework.Unschedulable, "too many pods placed across regions"), 0
+	}
+	state.Write(reservationStateKey, &reservationState{reservation: r})
+	if delay == 0 {
+		return nil, 0
+	}
+
+	klog.V(4).InfoS("Cross-region placement delayed", "pod", klog.KObj(pod), "node", nodeName, "delay", delay)
+	uid := pod.UID
+	time.AfterFunc(delay, func() {
+		if wp := cr.handle.GetWaitingPod(uid); wp != nil {
+			wp.Allow(cr.Name())
+		}
+	})
+	return framework.NewStatus(framework.Wait), delay + allowGracePeriod
+}
+
+// Reserve is a no-op, the rate limit being taken in Permit.
+func (cr *CrossRegionRateLimit) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	return nil
+}
+
+// Unreserve gives back the slot of the rate limit a pod was waiting for, e.g. when rejected by
+// another Permit plugin.
+func (cr *CrossRegionRateLimit) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	c, err := state.Read(reservationStateKey)
+	if err != nil {
+		return
+	}
+	if s, ok := c.(*reservationState); ok {
+		s.reservation.Cancel()
+	}
+}
+
+// isCrossRegion tells whether placing pod on nodeName puts it in another region than the pods
+// of its AppGroup already placed. Pods out of any AppGroup and nodes without region never are. The
+// pods placed on the nodes left out by the node filter of the plugin are not counted.
+func (cr *CrossRegionRateLimit) isCrossRegion(pod *v1.Pod, nodeName string) (bool, error) {
+	agName := util.GetPodAppGroupLabel(pod)
+	if agName == "" {
+		return false, nil
+	}
+	node, err := cr.node(nodeName)
+	if err != nil {
+		return false, err
+	}
+	region := node.Labels[v1.LabelTopologyRegion]
+	if region == "" {
+		return false, nil
+	}
+	members, err := util.GetAppGroupPods(cr.podIndexer, pod.Namespace, agName)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range members {
+		if p.UID == pod.UID || p.Spec.NodeName == "" {
+			continue
+		}
+		// The nodes missing from the snapshot, e.g. deleted, tell nothing.
+		n, err := cr.node(p.Spec.NodeName)
+		if err != nil || !cr.nodeFilter.Selects(n) {
+			continue
+		}
+		if r := n.Labels[v1.LabelTopologyRegion]; r != "" && r != region {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (cr *CrossRegionRateLimit) node(nodeName string) (*v1.Node, error) {
+	nodeInfo, err := cr.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err)
+	}
+	if nodeInfo.Node() == nil {
+		return nil, fmt.Errorf("node %q not found", nodeName)
+	}
+	return nodeInfo.Node(), nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossregionlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestCrossRegionRateLimitPermit(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyRegion, "r1").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyRegion, "r2").Obj(),
+		st.MakeNode().Name("n3").Obj(),
+	}
+	makePod := func(name, appGroup string) *v1.Pod {
+		pod := st.MakePod().Namespace("default").Name(name).UID(name).Obj()
+		if appGroup != "" {
+			pod.Labels = map[string]string{v1alpha1.AppGroupLabel: appGroup}
+		}
+		return pod
+	}
+	running := makePod("running", "ag")
+	running.Spec.NodeName = "n1"
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister([]*v1.Pod{running}, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPlugin := func(maxWaitSeconds int64) *CrossRegionRateLimit {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+		if err := indexer.Add(running); err != nil {
+			t.Fatal(err)
+		}
+		return newCrossRegionRateLimit(fh, indexer, &config.CrossRegionRateLimitArgs{MaxPlacementsPerMinute: 1, Burst: 1, MaxWaitSeconds: maxWaitSeconds})
+	}
+	ctx := context.Background()
+
+	t.Run("rejected past the rate", func(t *testing.T) {
+		pl := newPlugin(0)
+		steps := []struct {
+			name     string
+			pod      *v1.Pod
+			node     string
+			expected framework.Code
+		}{
+			{name: "first cross-region placement", pod: makePod("p1", "ag"), node: "n2", expected: framework.Success},
+			{name: "same region", pod: makePod("p2", "ag"), node: "n1", expected: framework.Success},
+			{name: "node without region", pod: makePod("p3", "ag"), node: "n3", expected: framework.Success},
+			{name: "pod out of any AppGroup", pod: makePod("p4", ""), node: "n2", expected: framework.Success},
+			{name: "second cross-region placement", pod: makePod("p5", "ag"), node: "n2", expected: framework.Unschedulable},
+		}
+		for _, step := range steps {
+			if got, _ := pl.Permit(ctx, framework.NewCycleState(), step.pod, step.node); got.Code() != step.expected {
+				t.Errorf("%s: expected %v, got %v", step.name, step.expected, got.Code())
+			}
+		}
+	})
+
+	t.Run("waits for the rate", func(t *testing.T) {
+		pl := newPlugin(120)
+		if got, _ := pl.Permit(ctx, framework.NewCycleState(), makePod("p1", "ag"), "n2"); !got.IsSuccess() {
+			t.Fatalf("expected the first cross-region placement to be allowed, got %v", got.Code())
+		}
+		state, pod := framework.NewCycleState(), makePod("p2", "ag")
+		got, timeout := pl.Permit(ctx, state, pod, "n2")
+		if got.Code() != framework.Wait {
+			t.Fatalf("expected the second cross-region placement to wait, got %v", got.Code())
+		}
+		if timeout <= 55*time.Second || timeout > time.Minute+allowGracePeriod {
+			t.Errorf("expected to wait about a minute, got %v", timeout)
+		}
+
+		// The slot an unreserved pod was waiting for is given back.
+		pl.Unreserve(ctx, state, pod, "n2")
+		if _, timeout := pl.Permit(ctx, framework.NewCycleState(), makePod("p3", "ag"), "n2"); timeout > time.Minute+allowGracePeriod {
+			t.Errorf("expected the slot of the unreserved pod to be reused, got a wait of %v", timeout)
+		}
+	})
+}
+
+func TestCrossRegionRateLimitNodeFilter(t *testing.T) {
+	cordoned := st.MakeNode().Name("n1").Label(v1.LabelTopologyRegion, "r1").Obj()
+	cordoned.Spec.Unschedulable = true
+	nodes := []*v1.Node{cordoned, st.MakeNode().Name("n2").Label(v1.LabelTopologyRegion, "r2").Obj()}
+	running := st.MakePod().Namespace("default").Name("running").UID("running").Label(v1alpha1.AppGroupLabel, "ag").Node("n1").Obj()
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister([]*v1.Pod{running}, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     config.CrossRegionRateLimitArgs
+		expected framework.Code
+	}{
+		{
+			name:     "pods on cordoned nodes counted",
+			args:     config.CrossRegionRateLimitArgs{MaxPlacementsPerMinute: 1, Burst: 1},
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "pods on cordoned nodes ignored",
+			args:     config.CrossRegionRateLimitArgs{MaxPlacementsPerMinute: 1, Burst: 1, IgnoreUnschedulableNodes: true},
+			expected: framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+			if err := indexer.Add(running); err != nil {
+				t.Fatal(err)
+			}
+			pl := newCrossRegionRateLimit(fh, indexer, &tt.args)
+			// The first placement takes the only slot, the second one is allowed only if it is not cross-region.
+			for _, name := range []string{"p1", "p2"} {
+				pod := st.MakePod().Namespace("default").Name(name).UID(name).Label(v1alpha1.AppGroupLabel, "ag").Obj()
+				got, _ := pl.Permit(context.Background(), framework.NewCycleState(), pod, "n2")
+				if name == "p2" && got.Code() != tt.expected {
+					t.Errorf("expected %v, got %v", tt.expected, got.Code())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linkheadroom
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// LinkHeadroom is a filter plugin rejecting the nodes whose links to the pods of the dependencies
+// of a pod lack the bandwidth the pod demands. The headroom of a link is its capacity, capped by
+// the link policy of its topology key, minus the bandwidth allocated by the controller.
+type LinkHeadroom struct {
+	handle     framework.Handle
+	agLister   listers.AppGroupLister
+	podIndexer cache.Indexer
+	costOracle *costoracle.CostOracle
+}
+
+var _ framework.PreFilterPlugin = &LinkHeadroom{}
+var _ framework.FilterPlugin = &LinkHeadroom{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "LinkHeadroom"
+
+	// preFilterStateKey is the key in CycleState to the bandwidth demands of the pod.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonLinkHeadroom is returned when a link from or to the node lacks the bandwidth demanded by the pod.
+	ErrReasonLinkHeadroom = "node(s) without enough bandwidth on the links to the dependencies of the pod"
+)
+
+// dependencyDemand is the bandwidth demanded by the pod to and from a dependency, and the
+// placements of the pods of the dependency.
+type dependencyDemand struct {
+	egress  resource.Quantity
+	ingress resource.Quantity
+	peers   []costoracle.Placement
+}
+
+// preFilterState holds the bandwidth demands of the pod to its dependencies.
+type preFilterState struct {
+	demands []dependencyDemand
+}
+
+// Clone the state, which is never updated once written.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+// linkKey is one direction of a link.
+type linkKey struct {
+	key         v1alpha1.TopologyKey
+	origin      string
+	destination string
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.LinkHeadroomArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type LinkHeadroomArgs, got %T", obj)
+	}
+	if args.NetworkTopologyName == "" {
+		return nil, fmt.Errorf("networkTopologyName should be set")
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	ntInformer := informerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	agLister := agInformer.Lister()
+	costOracle, err := costoracle.New(ntInformer, args.NetworkTopologyNamespace, args.NetworkTopologyName, args.WeightsName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid weightsName: %w", err)
+	}
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), agInformer.Informer().HasSynced, ntInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	if err := util.AddAppGroupPodIndex(podInformer); err != nil {
+		return nil, err
+	}
+	return newLinkHeadroom(handle, agLister, podInformer.GetIndexer(), costOracle), nil
+}
+
+func newLinkHeadroom(handle framework.Handle, agLister listers.AppGroupLister, podIndexer cache.Indexer, costOracle *costoracle.CostOracle) *LinkHeadroom {
+	return &LinkHeadroom{
+		handle:     handle,
+		agLister:   agLister,
+		podIndexer: podIndexer,
+		costOracle: costOracle,
+	}
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (lh *LinkHeadroom) Name() string {
+	return Name
+}
+
+// preFilter collects the Guaranteed bandwidth demanded by the pod to its dependencies, and where
+// the pods of these are placed, if the workload of the pod demands any.
+func (lh *LinkHeadroom) preFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	agName := util.GetPodAppGroupLabel(pod)
+	if agName == "" {
+		return nil
+	}
+	ag, err := lh.agLister.AppGroups(pod.Namespace).Get(agName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return framework.AsStatus(err)
+	}
+	var demands []costoracle.BandwidthDemand
+	for _, d := range costoracle.BandwidthDemands(ag, util.GetPodAppGroupSelector(pod)) {
+		// Only the Guaranteed bandwidth has the capacity of the links enforced.
+		if d.Class == v1alpha1.BandwidthGuaranteed {
+			demands = append(demands, d)
+		}
+	}
+	if len(demands) == 0 {
+		return nil
+	}
+	placements, err := lh.placements(pod, agName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	s := &preFilterState{}
+	for _, d := range demands {
+		s.demands = append(s.demands, dependencyDemand{egress: d.Egress, ingress: d.Ingress, peers: placements[d.Dependency]})
+	}
+	state.Write(preFilterStateKey, s)
+	return nil
+}
+
+// PreFilterExtensions returns nil, the demands of the pod not depending on the pods preempted.
+func (lh *LinkHeadroom) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter rejects the node if the headroom of a link crossed by the traffic between the node and
+// the pods of the dependencies of the pod is below the bandwidth the pod demands on it: its egress
+// from the node, its ingress to the node. The links without a known capacity, and the pods without
+// dependency placed yet, are never rejected.
+func (lh *LinkHeadroom) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if s == nil {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+
+	from := costoracle.NodePlacement(node)
+	demanded := make(map[linkKey]resource.Quantity)
+	for _, d := range s.demands {
+		key, destination, ok := costoracle.DependencyLink(from, d.peers)
+		if !ok {
+			continue
+		}
+		out, in := linkKey{key, from.Domain(key), destination}, linkKey{key, destination, from.Domain(key)}
+		egress, ingress := demanded[out], demanded[in]
+		egress.Add(d.egress)
+		ingress.Add(d.ingress)
+		demanded[out], demanded[in] = egress, ingress
+	}
+	for l, bandwidth := range demanded {
+		headroom, ok := lh.costOracle.GetLinkHeadroom(l.key, l.origin, l.destination)
+		if ok && headroom.Cmp(bandwidth) < 0 {
+			klog.V(5).InfoS("Link headroom below the demanded bandwidth", "pod", klog.KObj(pod), "node", klog.KObj(node),
+				"origin", l.origin, "destination", l.destination, "headroom", headroom.String(), "demanded", bandwidth.String())
+			return framework.NewStatus(framework.Unschedulable, ErrReasonLinkHeadroom)
+		}
+	}
+	return nil
+}
+
+// placements returns the placements of the pods of AppGroup agName, but pod, bound and not
+// terminated, keyed by workload selector.
+func (lh *LinkHeadroom) placements(pod *v1.Pod, agName string) (map[string][]costoracle.Placement, error) {
+	members, err := util.GetAppGroupPods(lh.podIndexer, pod.Namespace, agName)
+	if err != nil {
+		return nil, err
+	}
+	placements := make(map[string][]costoracle.Placement)
+	for _, p := range members {
+		if p.UID == pod.UID || p.Spec.NodeName == "" || p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		// The nodes missing from the snapshot, e.g. deleted, tell nothing.
+		nodeInfo, err := lh.handle.SnapshotSharedLister().NodeInfos().Get(p.Spec.NodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+		selector := util.GetPodAppGroupSelector(p)
+		placements[selector] = append(placements[selector], costoracle.NodePlacement(nodeInfo.Node()))
+	}
+	return placements, nil
+}
+
+// getPreFilterState returns the state written in PreFilter, nil if the pod demands no bandwidth.
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		// The pod demands no bandwidth.
+		return nil, nil
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to linkheadroom.preFilterState error", c)
+	}
+	return s, nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linkheadroom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestLinkHeadroom(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	link := func(destination, capacity, allocated string) v1alpha1.CostInfo {
+		return v1alpha1.CostInfo{Destination: destination, NetworkCost: 1,
+			BandwidthCapacity: resource.MustParse(capacity), BandwidthAllocated: resource.MustParse(allocated)}
+	}
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			// Without the policy, the headroom from z2 to z1 would be 400M.
+			LinkPolicies: []v1alpha1.LinkUtilizationPolicy{{TopologyKey: v1alpha1.NetworkTopologyZone, MaxUtilizationPercent: 80}},
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					{Origin: "z1", CostList: v1alpha1.CostList{link("z2", "1G", "0"), link("z3", "1G", "0"), {Destination: "z4", NetworkCost: 1}}},
+					{Origin: "z2", CostList: v1alpha1.CostList{link("z1", "1G", "600M")}},
+					{Origin: "z3", CostList: v1alpha1.CostList{link("z1", "1G", "400M")}},
+					{Origin: "z4", CostList: v1alpha1.CostList{{Destination: "z1", NetworkCost: 1}}},
+				}},
+			}}},
+		},
+	}
+	cs := fakeclientset.NewSimpleClientset(nt)
+	schedInformerFactory := schedinformers.NewSharedInformerFactory(cs, 0)
+	costOracle, err := costoracle.New(schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), "default", "nt", "UserDefined")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedInformerFactory.Start(ctx.Done())
+	schedInformerFactory.WaitForCacheSync(ctx.Done())
+	if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		return costOracle.GetMaxCost(v1alpha1.NetworkTopologyZone) > 0, nil
+	}); err != nil {
+		t.Fatalf("NetworkTopology not observed: %v", err)
+	}
+
+	var nodes []*v1.Node
+	for _, zone := range []string{"z1", "z2", "z3", "z4"} {
+		nodes = append(nodes, st.MakeNode().Name("n-"+zone).Label(v1.LabelTopologyRegion, "r1").Label(v1.LabelTopologyZone, zone).Obj())
+	}
+	makePod := func(name, selector, nodeName string) *v1.Pod {
+		return st.MakePod().Namespace("default").Name(name).UID(name).Label(v1alpha1.AppGroupLabel, "ag").
+			Label(v1alpha1.AppGroupSelectorLabel, selector).Node(nodeName).Obj()
+	}
+	backend := makePod("backend", "backend", "n-z1")
+	done := makePod("backend-done", "backend", "n-z2")
+	done.Status.Phase = v1.PodSucceeded
+	placed := []*v1.Pod{backend, done}
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(placed, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingress := resource.MustParse("300M")
+	agIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := agIndexer.Add(&v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "frontend"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("300M")},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "downloader"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinIngressBandwidth: &ingress},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "batch"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("300M"),
+					BandwidthClass: v1alpha1.BandwidthBurstable},
+			}},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+	for _, p := range placed {
+		if err := podIndexer.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lh := newLinkHeadroom(fh, listers.NewAppGroupLister(agIndexer), podIndexer, costOracle)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected map[string]framework.Code
+	}{
+		{
+			name: "links to the dependency",
+			pod:  makePod("frontend", "frontend", ""),
+			expected: map[string]framework.Code{
+				// The zone of the backend crosses no link.
+				"n-z1": framework.Success,
+				// 200M left from z2 to z1 under the link policy, the terminated backend of z2 demanding nothing.
+				"n-z2": framework.Unschedulable,
+				"n-z3": framework.Success,
+				// The links without a capacity are not enforced.
+				"n-z4": framework.Success,
+			},
+		},
+		{
+			name: "ingress only",
+			pod:  makePod("downloader", "downloader", ""),
+			expected: map[string]framework.Code{
+				// 800M left from z1 to z2, nothing demanded from z2 to z1.
+				"n-z1": framework.Success, "n-z2": framework.Success, "n-z3": framework.Success, "n-z4": framework.Success,
+			},
+		},
+		{
+			name: "Burstable bandwidth not enforced",
+			pod:  makePod("batch", "batch", ""),
+			expected: map[string]framework.Code{
+				"n-z1": framework.Success, "n-z2": framework.Success, "n-z3": framework.Success, "n-z4": framework.Success,
+			},
+		},
+		{
+			name: "workload without demand",
+			pod:  makePod("backend-2", "backend", ""),
+			expected: map[string]framework.Code{
+				"n-z1": framework.Success, "n-z2": framework.Success, "n-z3": framework.Success, "n-z4": framework.Success,
+			},
+		},
+		{
+			name: "pod out of any AppGroup",
+			pod:  st.MakePod().Namespace("default").Name("single").UID("single").Obj(),
+			expected: map[string]framework.Code{
+				"n-z1": framework.Success, "n-z2": framework.Success, "n-z3": framework.Success, "n-z4": framework.Success,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := framework.NewCycleState()
+			if s := lh.preFilter(ctx, state, tt.pod); !s.IsSuccess() {
+				t.Fatalf("PreFilter failed: %v", s.AsError())
+			}
+			for _, n := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(n)
+				if got := lh.Filter(ctx, state, tt.pod, nodeInfo).Code(); got != tt.expected[n.Name] {
+					t.Errorf("expected %v on %s, got %v", tt.expected[n.Name], n.Name, got)
+				}
+			}
+		})
+	}
+}
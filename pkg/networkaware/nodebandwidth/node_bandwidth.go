@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodebandwidth
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// NodeBandwidth is a filter plugin keeping pods from being placed on nodes whose NIC lacks the
+// egress bandwidth they request.
+type NodeBandwidth struct{}
+
+var _ framework.FilterPlugin = &NodeBandwidth{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "NodeBandwidth"
+
+	// EgressBandwidthAnnotation is the pod annotation the bandwidth CNI plugin shapes the egress
+	// traffic of a pod with, in bits per second.
+	EgressBandwidthAnnotation = "kubernetes.io/egress-bandwidth"
+
+	// ErrReasonInsufficientBandwidth is the reason for a node lacking egress bandwidth.
+	ErrReasonInsufficientBandwidth = "node(s) didn't have enough egress bandwidth"
+)
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+	return &NodeBandwidth{}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (nb *NodeBandwidth) Name() string {
+	return Name
+}
+
+// Filter invoked at the filter extension point.
+// It rejects the nodes whose egress bandwidth capacity, as set by the controller from the
+// NodeBandwidthProfile of their instance type, cannot accommodate the egress bandwidth of the
+// pod on top of the one of the pods already placed there. Pods requesting no bandwidth and nodes
+// of unknown capacity pass.
+func (nb *NodeBandwidth) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	requested, ok, err := parseBandwidth(pod.Annotations, EgressBandwidthAnnotation)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("invalid %s annotation: %v", EgressBandwidthAnnotation, err))
+	}
+	if !ok {
+		return nil
+	}
+	capacity, ok, err := parseBandwidth(node.Annotations, v1alpha1.NodeEgressBandwidthCapacityAnnotation)
+	if err != nil {
+		klog.V(5).InfoS("Invalid egress bandwidth capacity", "node", klog.KObj(node), "err", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	for _, p := range nodeInfo.Pods {
+		if allocated, ok, _ := parseBandwidth(p.Pod.Annotations, EgressBandwidthAnnotation); ok {
+			requested.Add(allocated)
+		}
+	}
+	if requested.Cmp(capacity) > 0 {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonInsufficientBandwidth)
+	}
+	return nil
+}
+
+// parseBandwidth returns the bandwidth held by the key annotation, if set.
+func parseBandwidth(annotations map[string]string, key string) (resource.Quantity, bool, error) {
+	value, ok := annotations[key]
+	if !ok {
+		return resource.Quantity{}, false, nil
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, false, err
+	}
+	return q, true, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodebandwidth
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestNodeBandwidthFilter(t *testing.T) {
+	makePod := func(name, egress string) *v1.Pod {
+		pod := st.MakePod().Name(name).Obj()
+		if egress != "" {
+			pod.Annotations = map[string]string{EgressBandwidthAnnotation: egress}
+		}
+		return pod
+	}
+	makeNode := func(capacity string) *v1.Node {
+		node := st.MakeNode().Name("n").Obj()
+		if capacity != "" {
+			node.Annotations = map[string]string{v1alpha1.NodeEgressBandwidthCapacityAnnotation: capacity}
+		}
+		return node
+	}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		node     *v1.Node
+		existing []*v1.Pod
+		expected framework.Code
+	}{
+		{
+			name:     "pod requesting no bandwidth",
+			pod:      makePod("p", ""),
+			node:     makeNode("1G"),
+			expected: framework.Success,
+		},
+		{
+			name:     "node of unknown capacity",
+			pod:      makePod("p", "10G"),
+			node:     makeNode(""),
+			expected: framework.Success,
+		},
+		{
+			name:     "enough bandwidth left",
+			pod:      makePod("p", "4G"),
+			node:     makeNode("10G"),
+			existing: []*v1.Pod{makePod("e1", "5G"), makePod("e2", "")},
+			expected: framework.Success,
+		},
+		{
+			name:     "not enough bandwidth left",
+			pod:      makePod("p", "4G"),
+			node:     makeNode("10G"),
+			existing: []*v1.Pod{makePod("e1", "5G"), makePod("e2", "2G")},
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "small NIC",
+			pod:      makePod("p", "25G"),
+			node:     makeNode("10G"),
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "invalid pod annotation",
+			pod:      makePod("p", "fast"),
+			node:     makeNode("10G"),
+			expected: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(tt.existing...)
+			nodeInfo.SetNode(tt.node)
+			pl := &NodeBandwidth{}
+			if got := pl.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo); got.Code() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got.Code())
+			}
+		})
+	}
+}
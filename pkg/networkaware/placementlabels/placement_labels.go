@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementlabels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// PlacementLabels is a post bind plugin labeling the bound pods with the zone and region of their
+// node and, for the pods of AppGroups, the cost bucket of their placement, so that cost allocation
+// and observability tools aggregate the pods by placement without joining them to the nodes.
+type PlacementLabels struct {
+	handle     framework.Handle
+	nodeLister corelisters.NodeLister
+	agLister   listers.AppGroupLister
+	podIndexer cache.Indexer
+}
+
+var _ framework.PostBindPlugin = &PlacementLabels{}
+
+// Name is the name of the plugin used in the Registry and configurations.
+const Name = "PlacementLabels"
+
+// costBucketRanks ranks the cost buckets from the cheapest to the most expensive.
+var costBucketRanks = map[string]int{
+	v1alpha1.PlacementCostBucketSameZone:    1,
+	v1alpha1.PlacementCostBucketCrossZone:   2,
+	v1alpha1.PlacementCostBucketCrossRegion: 3,
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := &config.PlacementLabelsArgs{}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.PlacementLabelsArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type PlacementLabelsArgs, got %T", obj)
+		}
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	agLister := agInformer.Lister()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), agInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	if err := util.AddAppGroupPodIndex(podInformer); err != nil {
+		return nil, err
+	}
+	return &PlacementLabels{
+		handle:     handle,
+		nodeLister: handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
+		agLister:   agLister,
+		podIndexer: podInformer.GetIndexer(),
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *PlacementLabels) Name() string {
+	return Name
+}
+
+// PostBind labels the pod with its placement. Failures are only logged, the pod being bound.
+func (pl *PlacementLabels) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	labels, err := pl.placementLabels(pod, nodeName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the placement of the pod", "pod", klog.KObj(pod), "node", nodeName)
+		return
+	}
+	if len(labels) == 0 {
+		return
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+	if err != nil {
+		klog.ErrorS(err, "Failed to create merge patch", "pod", klog.KObj(pod))
+		return
+	}
+	if _, err := pl.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to label the pod with its placement", "pod", klog.KObj(pod))
+		return
+	}
+	klog.V(4).InfoS("Labeled the pod with its placement", "pod", klog.KObj(pod), "labels", labels)
+}
+
+// placementLabels returns the zone, region and cost bucket labels of pod bound to nodeName, the
+// ones unknown being left out.
+func (pl *PlacementLabels) placementLabels(pod *v1.Pod, nodeName string) (map[string]string, error) {
+	node, err := pl.nodeLister.Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	zone, region := node.Labels[v1.LabelTopologyZone], node.Labels[v1.LabelTopologyRegion]
+	labels := make(map[string]string)
+	if zone != "" {
+		labels[v1alpha1.PlacementZoneLabel] = zone
+	}
+	if region != "" {
+		labels[v1alpha1.PlacementRegionLabel] = region
+	}
+	if bucket := pl.costBucket(pod, zone, region); bucket != "" {
+		labels[v1alpha1.PlacementCostBucketLabel] = bucket
+	}
+	return labels, nil
+}
+
+// costBucket returns the widest topology boundary between pod, placed in zone and region, and
+// the placed pods of the dependencies of its workload, empty if none is placed or the AppGroup
+// of the pod is unknown. The zone-local dependencies are assumed to have a pod in zone.
+func (pl *PlacementLabels) costBucket(pod *v1.Pod, zone, region string) string {
+	agName := util.GetPodAppGroupLabel(pod)
+	if agName == "" {
+		return ""
+	}
+	ag, err := pl.agLister.AppGroups(pod.Namespace).Get(agName)
+	if err != nil {
+		return ""
+	}
+	selector := util.GetPodAppGroupSelector(pod)
+	var bucket string
+	dependencies := make(map[string]bool)
+	for _, w := range util.WithCanaryDependencies(ag.Spec.Workloads) {
+		if w.Workload.Selector != selector {
+			continue
+		}
+		for _, d := range w.Dependencies {
+			if d.ZoneLocal {
+				if zone != "" {
+					bucket = v1alpha1.PlacementCostBucketSameZone
+				}
+				continue
+			}
+			dependencies[d.Workload.Selector] = true
+		}
+	}
+	if len(dependencies) == 0 {
+		return bucket
+	}
+	members, err := util.GetAppGroupPods(pl.podIndexer, pod.Namespace, agName)
+	if err != nil {
+		return bucket
+	}
+
+	for _, p := range members {
+		if p.UID == pod.UID || p.Spec.NodeName == "" || !dependencies[util.GetPodAppGroupSelector(p)] {
+			continue
+		}
+		node, err := pl.nodeLister.Get(p.Spec.NodeName)
+		if err != nil {
+			continue
+		}
+		b := costBucketBetween(zone, region, node.Labels[v1.LabelTopologyZone], node.Labels[v1.LabelTopologyRegion])
+		if costBucketRanks[b] > costBucketRanks[bucket] {
+			bucket = b
+		}
+	}
+	return bucket
+}
+
+// costBucketBetween returns the topology boundary between two placements, empty if unknown. Zones
+// without region are assumed to share a region, as the default network costs do.
+func costBucketBetween(zone, region, otherZone, otherRegion string) string {
+	switch {
+	case zone != "" && zone == otherZone:
+		return v1alpha1.PlacementCostBucketSameZone
+	case region != otherRegion:
+		if region != "" && otherRegion != "" {
+			return v1alpha1.PlacementCostBucketCrossRegion
+		}
+		return ""
+	case region != "" || (zone != "" && otherZone != ""):
+		return v1alpha1.PlacementCostBucketCrossZone
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementlabels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+func TestPlacementLabelsPostBind(t *testing.T) {
+	node := func(name, region, zone string) *v1.Node {
+		n := st.MakeNode().Name(name).Obj()
+		n.Labels = make(map[string]string)
+		if region != "" {
+			n.Labels[v1.LabelTopologyRegion] = region
+		}
+		if zone != "" {
+			n.Labels[v1.LabelTopologyZone] = zone
+		}
+		return n
+	}
+	nodes := []*v1.Node{
+		node("n1", "r1", "z1"),
+		node("n2", "r1", "z2"),
+		node("n3", "r2", "z3"),
+		node("n4", "", ""),
+	}
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P1"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P2"}},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P3"}},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P2"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P3"}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P4"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P2"}},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P5"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P3"}, ZoneLocal: true},
+			}},
+		}},
+	}
+	makePod := func(name, workload, nodeName string) *v1.Pod {
+		pod := st.MakePod().Namespace("default").Name(name).UID(name).Node(nodeName).Obj()
+		if workload != "" {
+			pod.Labels = map[string]string{v1alpha1.AppGroupLabel: "ag", v1alpha1.AppGroupSelectorLabel: workload}
+		}
+		return pod
+	}
+	placed := []*v1.Pod{makePod("p2", "P2", "n1"), makePod("p3", "P3", "n2")}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		node     string
+		expected map[string]string
+	}{
+		{
+			name: "dependencies across zones",
+			pod:  makePod("p1", "P1", "n1"),
+			node: "n1",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:       "z1",
+				v1alpha1.PlacementRegionLabel:     "r1",
+				v1alpha1.PlacementCostBucketLabel: v1alpha1.PlacementCostBucketCrossZone,
+			},
+		},
+		{
+			name: "dependencies across regions",
+			pod:  makePod("p1", "P1", "n3"),
+			node: "n3",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:       "z3",
+				v1alpha1.PlacementRegionLabel:     "r2",
+				v1alpha1.PlacementCostBucketLabel: v1alpha1.PlacementCostBucketCrossRegion,
+			},
+		},
+		{
+			name: "dependency in the same zone",
+			pod:  makePod("p4", "P4", "n1"),
+			node: "n1",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:       "z1",
+				v1alpha1.PlacementRegionLabel:     "r1",
+				v1alpha1.PlacementCostBucketLabel: v1alpha1.PlacementCostBucketSameZone,
+			},
+		},
+		{
+			name: "zone-local dependency placed in another zone",
+			pod:  makePod("p8", "P5", "n3"),
+			node: "n3",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:       "z3",
+				v1alpha1.PlacementRegionLabel:     "r2",
+				v1alpha1.PlacementCostBucketLabel: v1alpha1.PlacementCostBucketSameZone,
+			},
+		},
+		{
+			name: "workload without dependencies",
+			pod:  makePod("p5", "P2", "n2"),
+			node: "n2",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:   "z2",
+				v1alpha1.PlacementRegionLabel: "r1",
+			},
+		},
+		{
+			name: "pod out of any AppGroup",
+			pod:  makePod("p6", "", "n3"),
+			node: "n3",
+			expected: map[string]string{
+				v1alpha1.PlacementZoneLabel:   "z3",
+				v1alpha1.PlacementRegionLabel: "r2",
+			},
+		},
+		{
+			name: "node without topology",
+			pod:  makePod("p7", "P2", "n4"),
+			node: "n4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := clientsetfake.NewSimpleClientset(tt.pod)
+			informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+			for _, n := range nodes {
+				if err := informerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fh, err := st.NewFramework(registeredPlugins, "",
+				frameworkruntime.WithClientSet(fakeClient),
+				frameworkruntime.WithInformerFactory(informerFactory),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := agIndexer.Add(ag); err != nil {
+				t.Fatal(err)
+			}
+			podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+			for _, p := range append(placed, tt.pod) {
+				if err := podIndexer.Add(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pl := &PlacementLabels{
+				handle:     fh,
+				nodeLister: informerFactory.Core().V1().Nodes().Lister(),
+				agLister:   listers.NewAppGroupLister(agIndexer),
+				podIndexer: podIndexer,
+			}
+
+			ctx := context.Background()
+			pl.PostBind(ctx, framework.NewCycleState(), tt.pod, tt.node)
+			got, err := fakeClient.CoreV1().Pods("default").Get(ctx, tt.pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := make(map[string]string)
+			for k, v := range tt.pod.Labels {
+				expected[k] = v
+			}
+			for k, v := range tt.expected {
+				expected[k] = v
+			}
+			if len(expected) == 0 {
+				expected = nil
+			}
+			if !reflect.DeepEqual(expected, got.Labels) {
+				t.Errorf("expected the labels %v, got %v", expected, got.Labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologicalsort
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// gaugeInterval is the period of the updates of the queue age gauge.
+const gaugeInterval = 10 * time.Second
+
+var (
+	maxQueueAge = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "topological_sort_max_queue_age_seconds",
+			Help:           "Time the oldest pending pod of an AppGroup has been waiting to be scheduled since its creation.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"namespace", "app_group"})
+
+	registerMetrics sync.Once
+)
+
+// queueAges tracks the creation of the pending pods of every AppGroup, for the gauge of the oldest
+// one. The pods are tracked from the pod informer rather than from the sort of the queue, which must
+// stay cheap, and forgotten once bound or deleted.
+type queueAges struct {
+	sync.Mutex
+	clock clock.PassiveClock
+	// pods is keyed by AppGroup then pod UID.
+	pods map[types.NamespacedName]map[types.UID]time.Time
+}
+
+func newQueueAges(clock clock.PassiveClock) *queueAges {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(maxQueueAge)
+	})
+	return &queueAges{clock: clock, pods: make(map[types.NamespacedName]map[types.UID]time.Time)}
+}
+
+// observe records the creation of a pending pod of an AppGroup, or forgets it once bound.
+func (a *queueAges) observe(pod *v1.Pod) {
+	if pod.Spec.NodeName != "" {
+		a.forget(pod)
+		return
+	}
+	key, ok := appGroupKey(pod)
+	if !ok {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	pods, ok := a.pods[key]
+	if !ok {
+		pods = make(map[types.UID]time.Time)
+		a.pods[key] = pods
+	}
+	pods[pod.UID] = pod.CreationTimestamp.Time
+}
+
+// forget drops a pod leaving the queue.
+func (a *queueAges) forget(pod *v1.Pod) {
+	key, ok := appGroupKey(pod)
+	if !ok {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	pods, ok := a.pods[key]
+	if !ok {
+		return
+	}
+	if _, ok := pods[pod.UID]; !ok {
+		return
+	}
+	delete(pods, pod.UID)
+	if len(pods) == 0 {
+		delete(a.pods, key)
+		maxQueueAge.DeleteLabelValues(key.Namespace, key.Name)
+	}
+}
+
+// updateGauges sets the gauge of every AppGroup with pending pods to the age of the oldest one.
+func (a *queueAges) updateGauges() {
+	a.Lock()
+	defer a.Unlock()
+	for key, pods := range a.pods {
+		var oldest time.Time
+		for _, t := range pods {
+			if oldest.IsZero() || t.Before(oldest) {
+				oldest = t
+			}
+		}
+		maxQueueAge.WithLabelValues(key.Namespace, key.Name).Set(a.clock.Since(oldest).Seconds())
+	}
+}
+
+func appGroupKey(pod *v1.Pod) (types.NamespacedName, bool) {
+	name := util.GetPodAppGroupLabel(pod)
+	return types.NamespacedName{Namespace: pod.Namespace, Name: name}, name != ""
+}
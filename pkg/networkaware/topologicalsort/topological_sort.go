@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologicalsort
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// TopologicalSort is a QueueSort plugin ordering the pods of an AppGroup by the topology index of
+// their workload, so that the dependencies of a workload are scheduled before it. The index of a
+// pod waiting for long improves over time, so that the pods of a workload whose dependencies stay
+// unschedulable are not starved.
+type TopologicalSort struct {
+	agLister  listers.AppGroupLister
+	threshold time.Duration
+	boost     int32
+	clock     clock.PassiveClock
+	ages      *queueAges
+}
+
+var _ framework.QueueSortPlugin = &TopologicalSort{}
+
+// Name is the name of the plugin used in the Registry and configurations.
+const Name = "TopologicalSort"
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.TopologicalSortArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type TopologicalSortArgs, got %T", obj)
+	}
+	if args.StarvationThresholdSeconds < 0 || args.StarvationIndexBoost < 0 {
+		return nil, fmt.Errorf("starvationThresholdSeconds and starvationIndexBoost should not be negative, got %d and %d",
+			args.StarvationThresholdSeconds, args.StarvationIndexBoost)
+	}
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	agLister := agInformer.Lister()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), agInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	ts := newTopologicalSort(agLister, args, clock.RealClock{})
+	// The pods leave the queue once bound or deleted.
+	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*v1.Pod); ok {
+				ts.ages.observe(pod)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if pod, ok := newObj.(*v1.Pod); ok {
+				ts.ages.observe(pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			switch t := obj.(type) {
+			case *v1.Pod:
+				ts.ages.forget(t)
+			case cache.DeletedFinalStateUnknown:
+				if pod, ok := t.Obj.(*v1.Pod); ok {
+					ts.ages.forget(pod)
+				}
+			}
+		},
+	})
+	go wait.Until(ts.ages.updateGauges, gaugeInterval, ctx.Done())
+	return ts, nil
+}
+
+func newTopologicalSort(agLister listers.AppGroupLister, args *config.TopologicalSortArgs, clock clock.PassiveClock) *TopologicalSort {
+	return &TopologicalSort{
+		agLister:  agLister,
+		threshold: time.Duration(args.StarvationThresholdSeconds) * time.Second,
+		boost:     args.StarvationIndexBoost,
+		clock:     clock,
+		ages:      newQueueAges(clock),
+	}
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (ts *TopologicalSort) Name() string {
+	return Name
+}
+
+// Less sorts the pods of the same AppGroup by the effective topology index of their workload, and
+// the other pods, the ones of equal index or of an AppGroup whose topology order is of a previous
+// generation, by priority then by time in the queue, as the PrioritySort plugin does.
+func (ts *TopologicalSort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	agName := util.GetPodAppGroupLabel(pInfo1.Pod)
+	if agName == "" || agName != util.GetPodAppGroupLabel(pInfo2.Pod) || pInfo1.Pod.Namespace != pInfo2.Pod.Namespace {
+		return prioritySortLess(pInfo1, pInfo2)
+	}
+	ag, err := ts.agLister.AppGroups(pInfo1.Pod.Namespace).Get(agName)
+	// The topology order of a previous spec may not hold any more, e.g. for a dependency added, until
+	// the controller sorts the workloads again.
+	if err != nil || ag.Status.ObservedGeneration != ag.Generation {
+		return prioritySortLess(pInfo1, pInfo2)
+	}
+	index1, ok1 := ts.effectiveIndex(ag, pInfo1)
+	index2, ok2 := ts.effectiveIndex(ag, pInfo2)
+	if !ok1 || !ok2 || index1 == index2 {
+		return prioritySortLess(pInfo1, pInfo2)
+	}
+	return index1 < index2
+}
+
+// effectiveIndex returns the topology index of the workload of the pod, improved by the boost
+// for every threshold period the pod has been waiting, and false if the workload has no index.
+func (ts *TopologicalSort) effectiveIndex(ag *v1alpha1.AppGroup, pInfo *framework.QueuedPodInfo) (int32, bool) {
+	selector := util.GetPodAppGroupSelector(pInfo.Pod)
+	for _, t := range ag.Status.TopologyOrder {
+		if t.Workload.Selector != selector {
+			continue
+		}
+		index := t.Index
+		if ts.threshold > 0 && !pInfo.InitialAttemptTimestamp.IsZero() {
+			periods := int32(ts.clock.Since(pInfo.InitialAttemptTimestamp) / ts.threshold)
+			index -= periods * ts.boost
+		}
+		return index, true
+	}
+	return 0, false
+}
+
+func prioritySortLess(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	p1 := corev1helpers.PodPriority(pInfo1.Pod)
+	p2 := corev1helpers.PodPriority(pInfo2.Pod)
+	return (p1 > p2) || (p1 == p2 && pInfo1.Timestamp.Before(pInfo2.Timestamp))
+}
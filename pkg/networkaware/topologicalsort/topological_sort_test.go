@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologicalsort
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+func TestTopologicalSortLess(t *testing.T) {
+	now := time.Now()
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ag"},
+		Status: v1alpha1.AppGroupStatus{TopologyOrder: v1alpha1.AppGroupTopologyList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P1"}, Index: 1},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P2"}, Index: 2},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "P3"}, Index: 3},
+		}},
+	}
+	// The spec of the stale AppGroup changed since its workloads were sorted.
+	stale := ag.DeepCopy()
+	stale.Name = "stale"
+	stale.Generation = 2
+	stale.Status.ObservedGeneration = 1
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range []*v1alpha1.AppGroup{ag, stale} {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	podInfo := func(name, appGroup, workload string, priority int32, waited time.Duration) *framework.QueuedPodInfo {
+		pod := st.MakePod().Namespace("default").Name(name).UID(name).Priority(priority).Obj()
+		if appGroup != "" {
+			pod.Labels = map[string]string{v1alpha1.AppGroupLabel: appGroup, v1alpha1.AppGroupSelectorLabel: workload}
+		}
+		return &framework.QueuedPodInfo{
+			PodInfo:                 framework.NewPodInfo(pod),
+			Timestamp:               now.Add(-waited),
+			InitialAttemptTimestamp: now.Add(-waited),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		args     config.TopologicalSortArgs
+		p1       *framework.QueuedPodInfo
+		p2       *framework.QueuedPodInfo
+		expected bool
+	}{
+		{
+			name:     "lower index first",
+			p1:       podInfo("p1", "ag", "P3", 10, 0),
+			p2:       podInfo("p2", "ag", "P1", 0, 0),
+			expected: false,
+		},
+		{
+			name:     "different AppGroups sorted by priority",
+			p1:       podInfo("p1", "ag", "P3", 10, 0),
+			p2:       podInfo("p2", "other", "P1", 0, 0),
+			expected: true,
+		},
+		{
+			name:     "same index sorted by time in the queue",
+			p1:       podInfo("p1", "ag", "P2", 0, time.Minute),
+			p2:       podInfo("p2", "ag", "P2", 0, 0),
+			expected: true,
+		},
+		{
+			name:     "workload without index sorted by priority",
+			p1:       podInfo("p1", "ag", "P9", 10, 0),
+			p2:       podInfo("p2", "ag", "P1", 0, 0),
+			expected: true,
+		},
+		{
+			name:     "topology order of a previous generation ignored",
+			p1:       podInfo("p1", "stale", "P3", 10, 0),
+			p2:       podInfo("p2", "stale", "P1", 0, 0),
+			expected: true,
+		},
+		{
+			name:     "starving pod boosted",
+			args:     config.TopologicalSortArgs{StarvationThresholdSeconds: 300, StarvationIndexBoost: 1},
+			p1:       podInfo("p1", "ag", "P3", 0, 10*time.Minute),
+			p2:       podInfo("p2", "ag", "P2", 0, 0),
+			expected: true,
+		},
+		{
+			name:     "pod not waiting long enough",
+			args:     config.TopologicalSortArgs{StarvationThresholdSeconds: 300, StarvationIndexBoost: 1},
+			p1:       podInfo("p1", "ag", "P3", 0, 4*time.Minute),
+			p2:       podInfo("p2", "ag", "P2", 0, 0),
+			expected: false,
+		},
+		{
+			name:     "boost disabled",
+			p1:       podInfo("p1", "ag", "P3", 0, time.Hour),
+			p2:       podInfo("p2", "ag", "P2", 0, 0),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTopologicalSort(listers.NewAppGroupLister(indexer), &tt.args, testingclock.NewFakeClock(now))
+			if got := ts.Less(tt.p1, tt.p2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQueueAges(t *testing.T) {
+	now := time.Now()
+	clock := testingclock.NewFakeClock(now)
+	ages := newQueueAges(clock)
+	key := types.NamespacedName{Namespace: "default", Name: "ag"}
+	pod := func(name string, age time.Duration) *v1.Pod {
+		p := st.MakePod().Namespace("default").Name(name).UID(name).Label(v1alpha1.AppGroupLabel, "ag").Obj()
+		p.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		return p
+	}
+	maxAge := func() float64 {
+		ages.updateGauges()
+		age, err := testutil.GetGaugeMetricValue(maxQueueAge.WithLabelValues(key.Namespace, key.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return age
+	}
+	old, recent := pod("old", 5*time.Minute), pod("recent", time.Minute)
+
+	ages.observe(recent)
+	ages.observe(old)
+	outOfAppGroup := pod("out-of-appgroup", time.Hour)
+	outOfAppGroup.Labels = nil
+	ages.observe(outOfAppGroup)
+	if got := maxAge(); got != (5 * time.Minute).Seconds() {
+		t.Errorf("expected the age of the oldest pod, got %v", got)
+	}
+	clock.Step(time.Minute)
+	bound := old.DeepCopy()
+	bound.Spec.NodeName = "node"
+	ages.observe(bound)
+	if got := maxAge(); got != (2 * time.Minute).Seconds() {
+		t.Errorf("expected the age of the remaining pod, got %v", got)
+	}
+	ages.forget(recent)
+	if _, ok := ages.pods[key]; ok {
+		t.Errorf("expected the AppGroup without pending pods to be forgotten")
+	}
+}
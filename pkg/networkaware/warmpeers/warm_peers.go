@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warmpeers
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// WarmPeers is a score plugin favoring the nodes, then the zones, already hosting running and Ready
+// pods of the workloads the pod depends on in its AppGroup. Pods assigned but still starting, e.g.
+// pulling their image, are not serving yet and are not counted, so that the replicas of a workload
+// follow the dependencies actually serving instead of the last pods placed. The nodes of the
+// draining zones of the NetworkTopology, if configured, score the lowest.
+type WarmPeers struct {
+	handle     framework.Handle
+	agLister   listers.AppGroupLister
+	podIndexer cache.Indexer
+	// costOracle is nil if no NetworkTopology is configured.
+	costOracle *costoracle.CostOracle
+}
+
+var _ framework.PreScorePlugin = &WarmPeers{}
+var _ framework.ScorePlugin = &WarmPeers{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "WarmPeers"
+
+	// preScoreStateKey is the key in CycleState to the Ready peers of the pod per node and zone.
+	preScoreStateKey = "PreScore" + Name
+
+	// nodePeerScore is the score of a Ready peer on the node, of unit weight.
+	nodePeerScore = 2
+	// zonePeerScore is the score of a Ready peer on another node of the zone of the node, of unit weight.
+	zonePeerScore = 1
+)
+
+// preScoreState holds the weights of the Ready peers of the pod per node and zone.
+type preScoreState struct {
+	peersPerNode map[string]int64
+	peersPerZone map[string]int64
+}
+
+// Clone the state, read only.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.WarmPeersArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type WarmPeersArgs, got %T", obj)
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	agLister := agInformer.Lister()
+	synced := []cache.InformerSynced{agInformer.Informer().HasSynced}
+	var costOracle *costoracle.CostOracle
+	if args.NetworkTopologyName != "" {
+		ntInformer := informerFactory.Scheduling().V1alpha1().NetworkTopologies()
+		var err error
+		if costOracle, err = costoracle.New(ntInformer, args.NetworkTopologyNamespace, args.NetworkTopologyName, args.WeightsName); err != nil {
+			return nil, fmt.Errorf("invalid weightsName: %w", err)
+		}
+		synced = append(synced, ntInformer.Informer().HasSynced)
+	}
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	if err := util.AddAppGroupPodIndex(podInformer); err != nil {
+		return nil, err
+	}
+	return &WarmPeers{
+		handle:     handle,
+		agLister:   agLister,
+		podIndexer: podInformer.GetIndexer(),
+		costOracle: costOracle,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (wp *WarmPeers) Name() string {
+	return Name
+}
+
+// PreScore counts the Ready peers of the pod per node and zone, weighted by the weight of their dependency.
+func (wp *WarmPeers) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	agName := util.GetPodAppGroupLabel(pod)
+	selector := util.GetPodAppGroupSelector(pod)
+	if agName == "" || selector == "" {
+		return nil
+	}
+	ag, err := wp.agLister.AppGroups(pod.Namespace).Get(agName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return framework.AsStatus(err)
+	}
+	dependencies := dependencyWeights(ag, selector)
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	members, err := util.GetAppGroupPods(wp.podIndexer, pod.Namespace, agName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	s := &preScoreState{peersPerNode: make(map[string]int64), peersPerZone: make(map[string]int64)}
+	for _, p := range members {
+		weight, ok := dependencies[util.GetPodAppGroupSelector(p)]
+		if !ok || p.UID == pod.UID || !isWarm(p) {
+			continue
+		}
+		s.peersPerNode[p.Spec.NodeName] += weight
+		// The nodes missing from the snapshot, e.g. deleted, give no zone.
+		nodeInfo, err := wp.handle.SnapshotSharedLister().NodeInfos().Get(p.Spec.NodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+		if zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]; zone != "" {
+			s.peersPerZone[zone] += weight
+		}
+	}
+	state.Write(preScoreStateKey, s)
+	return nil
+}
+
+// Score scores the node by the Ready peers of the pod it hosts, and, to a lesser extent, by the ones
+// on the other nodes of its zone. The nodes of a draining zone score 0.
+func (wp *WarmPeers) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if s == nil {
+		return 0, nil
+	}
+	nodeInfo, err := wp.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	onNode := s.peersPerNode[nodeName]
+	score := nodePeerScore * onNode
+	// The node may have been deleted since the snapshot, its zone telling nothing then.
+	if nodeInfo.Node() == nil {
+		return score, nil
+	}
+	zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]
+	if zone == "" {
+		return score, nil
+	}
+	if wp.costOracle != nil {
+		if draining, _ := wp.costOracle.ZoneDraining(zone); draining {
+			return 0, nil
+		}
+	}
+	return score + zonePeerScore*(s.peersPerZone[zone]-onNode), nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (wp *WarmPeers) ScoreExtensions() framework.ScoreExtensions {
+	return wp
+}
+
+// NormalizeScore scales the scores to the framework range, the node with the most Ready peers nearby
+// getting the highest score. The nodes are scored the lowest alike if no peer is Ready.
+func (wp *WarmPeers) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	var highest int64
+	for _, nodeScore := range scores {
+		if nodeScore.Score > highest {
+			highest = nodeScore.Score
+		}
+	}
+	for i := range scores {
+		if highest == 0 {
+			scores[i].Score = framework.MinNodeScore
+			continue
+		}
+		scores[i].Score = scores[i].Score * framework.MaxNodeScore / highest
+	}
+	return nil
+}
+
+// dependencyWeights returns the weights of the dependencies of the workload of the AppGroup with the
+// given selector, by selector of the dependency. The dependencies of weight 0 are left out.
+func dependencyWeights(ag *v1alpha1.AppGroup, selector string) map[string]int64 {
+	weights := make(map[string]int64)
+	for _, w := range util.WithCanaryDependencies(ag.Spec.Workloads) {
+		if w.Workload.Selector != selector {
+			continue
+		}
+		for _, d := range w.Dependencies {
+			weight := int64(1)
+			if d.Weight != nil {
+				weight = int64(*d.Weight)
+			}
+			if weight > 0 && d.Workload.Selector != "" {
+				weights[d.Workload.Selector] += weight
+			}
+		}
+	}
+	return weights
+}
+
+// isWarm tells whether the pod is bound, running and Ready, thus serving.
+func isWarm(pod *v1.Pod) bool {
+	return pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil && pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod)
+}
+
+// getPreScoreState returns the state written in PreScore, nil if the pod has no dependency.
+func getPreScoreState(state *framework.CycleState) (*preScoreState, error) {
+	c, err := state.Read(preScoreStateKey)
+	if err != nil {
+		// The pod is out of any AppGroup or depends on no workload.
+		return nil, nil
+	}
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to warmpeers.preScoreState error", c)
+	}
+	return s, nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warmpeers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/costoracle"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestWarmPeers(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyZone, "z1").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyZone, "z1").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyZone, "z2").Obj(),
+		st.MakeNode().Name("n4").Label(v1.LabelTopologyZone, "z3").Obj(),
+	}
+	makePod := func(name, selector, nodeName string, ready bool) *v1.Pod {
+		pod := st.MakePod().Namespace("default").Name(name).UID(name).Label(v1alpha1.AppGroupLabel, "a1").
+			Label(v1alpha1.AppGroupSelectorLabel, selector).Node(nodeName).Obj()
+		if ready {
+			pod.Status.Phase = v1.PodRunning
+			pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+		} else {
+			// Assigned, still creating its containers.
+			pod.Status.Phase = v1.PodPending
+		}
+		return pod
+	}
+	terminating := makePod("backend-3", "backend", "n2", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	placed := []*v1.Pod{
+		makePod("backend-1", "backend", "n1", true),
+		makePod("backend-2", "backend", "n3", false),
+		terminating,
+		makePod("cache-1", "cache", "n3", true),
+		makePod("metrics-1", "metrics", "n4", true),
+		makePod("frontend-1", "frontend", "n4", true),
+	}
+	weight := func(w int32) *int32 { return &w }
+	ag := &v1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a1"},
+		Spec: v1alpha1.AppGroupSpec{Workloads: v1alpha1.AppGroupWorkloadList{
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "frontend"}, Dependencies: v1alpha1.DependenciesList{
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "cache"}, Weight: weight(2)},
+				{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "metrics"}, Weight: weight(0)},
+			}},
+			{Workload: v1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+		}},
+	}
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(placed, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := agIndexer.Add(ag); err != nil {
+		t.Fatal(err)
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+	for _, p := range placed {
+		if err := podIndexer.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wp := &WarmPeers{handle: fh, agLister: listers.NewAppGroupLister(agIndexer), podIndexer: podIndexer}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	nt := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec: v1alpha1.NetworkTopologySpec{
+			Weights: v1alpha1.WeightList{{Name: "UserDefined", TopologyList: v1alpha1.TopologyList{
+				{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+					{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 1}}},
+				}},
+			}}},
+			DrainingZones: []v1alpha1.DrainingZone{{Zone: "z2"}},
+		},
+	}
+	schedInformerFactory := schedinformers.NewSharedInformerFactory(fakeclientset.NewSimpleClientset(nt), 0)
+	costOracle, err := costoracle.New(schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies(), "default", "nt", "UserDefined")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedInformerFactory.Start(ctx.Done())
+	schedInformerFactory.WaitForCacheSync(ctx.Done())
+	if err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		draining, _ := costOracle.ZoneDraining("z2")
+		return draining, nil
+	}); err != nil {
+		t.Fatalf("NetworkTopology not observed: %v", err)
+	}
+
+	score := func(wp *WarmPeers, pod *v1.Pod) map[string]int64 {
+		t.Helper()
+		state := framework.NewCycleState()
+		if s := wp.PreScore(ctx, state, pod, nodes); !s.IsSuccess() {
+			t.Fatalf("PreScore failed: %v", s.AsError())
+		}
+		var scores framework.NodeScoreList
+		for _, n := range nodes {
+			s, status := wp.Score(ctx, state, pod, n.Name)
+			if !status.IsSuccess() {
+				t.Fatalf("Score failed: %v", status.AsError())
+			}
+			scores = append(scores, framework.NodeScore{Name: n.Name, Score: s})
+		}
+		if s := wp.NormalizeScore(ctx, state, pod, scores); !s.IsSuccess() {
+			t.Fatalf("NormalizeScore failed: %v", s.AsError())
+		}
+		got := make(map[string]int64)
+		for _, s := range scores {
+			got[s.Name] = s.Score
+		}
+		return got
+	}
+
+	frontend := st.MakePod().Namespace("default").Name("frontend-2").UID("frontend-2").Label(v1alpha1.AppGroupLabel, "a1").Label(v1alpha1.AppGroupSelectorLabel, "frontend").Obj()
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		costOracle *costoracle.CostOracle
+		expected   map[string]int64
+	}{
+		{
+			// n3 hosts the Ready cache of weight 2, n1 the Ready backend, n2 shares its zone. The backend
+			// still starting on n3, the terminating one on n2 and the metrics of weight 0 count for nothing.
+			name:     "Ready peers",
+			pod:      frontend,
+			expected: map[string]int64{"n1": 50, "n2": 25, "n3": 100, "n4": 0},
+		},
+		{
+			// The zone z2 of n3 is draining, the backend of n1 is the best peer left.
+			name:       "draining zone",
+			pod:        frontend,
+			costOracle: costOracle,
+			expected:   map[string]int64{"n1": 100, "n2": 50, "n3": 0, "n4": 0},
+		},
+		{
+			name:     "no dependency",
+			pod:      st.MakePod().Namespace("default").Name("backend-4").UID("backend-4").Label(v1alpha1.AppGroupLabel, "a1").Label(v1alpha1.AppGroupSelectorLabel, "backend").Obj(),
+			expected: map[string]int64{"n1": 0, "n2": 0, "n3": 0, "n4": 0},
+		},
+		{
+			name:     "out of any AppGroup",
+			pod:      st.MakePod().Namespace("default").Name("p").UID("p").Obj(),
+			expected: map[string]int64{"n1": 0, "n2": 0, "n3": 0, "n4": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := *wp
+			wp.costOracle = tt.costOracle
+			if got := score(&wp, tt.pod); !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected the scores %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
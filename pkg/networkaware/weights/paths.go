@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weights
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// PathCache keeps the costs of the cheapest paths computed by Dijkstra's algorithm between two
+// computations of the same weights, so that only the origins whose paths may go through a changed
+// link are computed again. The paths of a topology key are reused at once while the revision of its
+// links, a hash of them, is unchanged. A PathCache is safe for concurrent use, but is meant to be
+// given the successive links of a single topology, e.g. of one NetworkTopology.
+type PathCache struct {
+	mu      sync.Mutex
+	entries map[v1alpha1.TopologyKey]*pathEntry
+}
+
+// pathEntry holds the paths computed from the links of a revision.
+type pathEntry struct {
+	revision uint64
+	links    map[string]map[string]int64
+	paths    map[string]map[string]int64
+	// computed is the number of origins whose paths were computed by the last refresh, for tests.
+	computed int
+}
+
+// NewPathCache returns an empty PathCache.
+func NewPathCache() *PathCache {
+	return &PathCache{entries: make(map[v1alpha1.TopologyKey]*pathEntry)}
+}
+
+// paths sets the paths of g from every origin of origins, keyed by key: the cached ones if the
+// revision of the links of g is the cached one, or if no changed link may be on their cheapest
+// paths; the ones computed again by Dijkstra's algorithm otherwise, spread across workers.
+func (c *PathCache) paths(g *graph, key v1alpha1.TopologyKey, origins []string, workers int) {
+	revision := linksRevision(g.links)
+	c.mu.Lock()
+	cached := c.entries[key]
+	c.mu.Unlock()
+
+	g.paths = make(map[string]map[string]int64, len(origins))
+	var stale []string
+	if cached != nil && cached.revision == revision {
+		for _, origin := range origins {
+			if paths, ok := cached.paths[origin]; ok {
+				g.paths[origin] = paths
+			} else {
+				stale = append(stale, origin)
+			}
+		}
+	} else {
+		var changes []linkChange
+		if cached != nil {
+			changes = changedLinks(cached.links, g.links)
+		}
+		for _, origin := range origins {
+			paths, ok := map[string]int64(nil), false
+			if cached != nil {
+				paths, ok = cached.paths[origin]
+			}
+			if ok && !affected(origin, paths, changes) {
+				g.paths[origin] = paths
+			} else {
+				stale = append(stale, origin)
+			}
+		}
+	}
+
+	computed := make([]map[string]int64, len(stale))
+	if workers < 1 {
+		workers = 1
+	}
+	workqueue.ParallelizeUntil(context.TODO(), workers, len(stale), func(i int) {
+		computed[i] = g.shortestPaths(stale[i])
+	})
+	for i, origin := range stale {
+		g.paths[origin] = computed[i]
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &pathEntry{revision: revision, links: g.links, paths: g.paths, computed: len(stale)}
+	c.mu.Unlock()
+}
+
+// linkChange is a link whose cost changed between two revisions, absent if not linked.
+type linkChange struct {
+	origin, destination string
+	before, after       int64
+	removed, added      bool
+}
+
+// changedLinks returns the links added, removed or whose cost changed from before to after.
+func changedLinks(before, after map[string]map[string]int64) []linkChange {
+	var changes []linkChange
+	for origin, destinations := range before {
+		for destination, c := range destinations {
+			if n, ok := after[origin][destination]; !ok {
+				changes = append(changes, linkChange{origin: origin, destination: destination, before: c, removed: true})
+			} else if n != c {
+				changes = append(changes, linkChange{origin: origin, destination: destination, before: c, after: n})
+			}
+		}
+	}
+	for origin, destinations := range after {
+		for destination, c := range destinations {
+			if _, ok := before[origin][destination]; !ok {
+				changes = append(changes, linkChange{origin: origin, destination: destination, after: c, added: true})
+			}
+		}
+	}
+	return changes
+}
+
+// affected returns whether the cheapest paths from origin, given by paths, may differ once changes
+// are applied: whether a link removed or made more expensive is on one of them, or whether a link
+// added or made cheaper shortens one of them. The other paths keep their cost.
+func affected(origin string, paths map[string]int64, changes []linkChange) bool {
+	dist := func(to string) (int64, bool) {
+		if to == origin {
+			return 0, true
+		}
+		d, ok := paths[to]
+		return d, ok
+	}
+	for _, change := range changes {
+		from, reached := dist(change.origin)
+		if !reached {
+			continue
+		}
+		to, ok := dist(change.destination)
+		if change.removed || !change.added && change.after > change.before {
+			if ok && from+change.before == to {
+				return true
+			}
+		} else if !ok || from+change.after < to {
+			return true
+		}
+	}
+	return false
+}
+
+// linksRevision returns a hash of links, the same for the same links whatever their order.
+func linksRevision(links map[string]map[string]int64) uint64 {
+	origins := make([]string, 0, len(links))
+	for origin := range links {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	h := fnv.New64a()
+	for _, origin := range origins {
+		destinations := make([]string, 0, len(links[origin]))
+		for destination := range links[origin] {
+			destinations = append(destinations, destination)
+		}
+		sort.Strings(destinations)
+		for _, destination := range destinations {
+			h.Write([]byte(origin))
+			h.Write([]byte{0})
+			h.Write([]byte(destination))
+			h.Write([]byte{0})
+			h.Write([]byte(strconv.FormatInt(links[origin][destination], 10)))
+			h.Write([]byte{'\n'})
+		}
+	}
+	return h.Sum64()
+}
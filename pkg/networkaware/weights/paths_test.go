@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weights
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestPathCache(t *testing.T) {
+	// A chain of links from z0 to z9, one way, so that the origins whose paths change are easy to tell.
+	var nodes []v1.Node
+	links := make(map[string]map[string]int64)
+	for i := 0; i < 10; i++ {
+		nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("node-%d", i),
+			Labels: map[string]string{v1.LabelTopologyRegion: "r1", v1.LabelTopologyZone: fmt.Sprintf("z%d", i)},
+		}})
+		if i != 9 {
+			links[fmt.Sprintf("z%d", i)] = map[string]int64{fmt.Sprintf("z%d", i+1): 1}
+		}
+	}
+	costs := func() v1alpha1.TopologyList {
+		t := v1alpha1.TopologyInfo{TopologyKey: v1alpha1.NetworkTopologyZone}
+		for _, origin := range sortedNames(map[string]bool{"z0": true, "z1": true, "z2": true, "z3": true, "z4": true,
+			"z5": true, "z6": true, "z7": true, "z8": true}) {
+			info := v1alpha1.OriginInfo{Origin: origin}
+			for destination, c := range links[origin] {
+				info.CostList = append(info.CostList, v1alpha1.CostInfo{Destination: destination, NetworkCost: c})
+			}
+			t.OriginList = append(t.OriginList, info)
+		}
+		return v1alpha1.TopologyList{t}
+	}
+	options := Options{WeightsName: "UserDefined", SameZoneCost: 1, CrossZoneCost: 50, CrossRegionCost: 100, Workers: 4}
+	cache := NewPathCache()
+
+	tests := []struct {
+		name   string
+		change func()
+		// computed is the number of origins whose paths are expected to be computed again.
+		computed int
+	}{
+		{name: "first computation", change: func() {}, computed: 10},
+		{name: "same links", change: func() {}, computed: 0},
+		{name: "link shortening no path", change: func() { links["z0"]["z9"] = 100 }, computed: 0},
+		{name: "link on the paths of z0 only made more expensive", change: func() { links["z0"]["z1"] = 2 }, computed: 1},
+		{name: "shortcut from z2 to z5", change: func() { links["z2"]["z5"] = 1 }, computed: 3},
+		{name: "link bypassed by the shortcut removed", change: func() { delete(links["z3"], "z4") }, computed: 4},
+		{name: "link made cheaper", change: func() { links["z0"]["z9"] = 1 }, computed: 1},
+		{name: "only link to z3 removed", change: func() { delete(links["z2"], "z3") }, computed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			options.Paths = nil
+			expected := ComputeWeights(nodes, costs(), options)
+			options.Paths = cache
+			if got := ComputeWeights(nodes, costs(), options); !reflect.DeepEqual(expected, got) {
+				t.Errorf("Expected the weights %v computed with the cache to be the same as without, got %v", expected, got)
+			}
+			if computed := cache.entries[v1alpha1.NetworkTopologyZone].computed; computed != tt.computed {
+				t.Errorf("Expected the paths of %d origins computed, got %d", tt.computed, computed)
+			}
+		})
+	}
+}
+
+func TestLinksRevision(t *testing.T) {
+	links := map[string]map[string]int64{"z1": {"z2": 1, "z3": 2}, "z2": {"z3": 1}}
+	same := map[string]map[string]int64{"z2": {"z3": 1}, "z1": {"z3": 2, "z2": 1}}
+	if linksRevision(links) != linksRevision(same) {
+		t.Errorf("Expected the same revision for the same links")
+	}
+	same["z2"]["z3"] = 2
+	if linksRevision(links) == linksRevision(same) {
+		t.Errorf("Expected another revision once a cost changed")
+	}
+}
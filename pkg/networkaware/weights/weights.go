@@ -0,0 +1,352 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package weights computes the weights of NetworkTopologies out of the nodes of a cluster and the
+// link costs known, without any client or informer, so that the controller, the scheduler
+// simulator, the CLI and tests share the same cost model.
+package weights
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// Options configures the default costs between the domains of the tiers, the zones and regions by default.
+type Options struct {
+	// WeightsName is the name of the weights computed.
+	WeightsName string
+	// SameZoneCost is the cost within a domain of the narrowest tier, a zone by default.
+	SameZoneCost int64
+	// CrossZoneCost is the cost between two zones of a region, if no tier is given.
+	CrossZoneCost int64
+	// CrossRegionCost is the cost between two regions, and between two zones of different regions, if no tier
+	// is given.
+	CrossRegionCost int64
+	// Tiers are the levels of the topology, from the widest to the narrowest. If empty, the tiers are the
+	// region, crossed at CrossRegionCost, then the zone, crossed at CrossZoneCost.
+	Tiers []Tier
+	// Algorithm computes the cost of the cheapest paths: the Floyd–Warshall algorithm over all the pairs if
+	// FloydWarshall, none if Manual, the pairs without a link getting the default costs, Dijkstra's algorithm
+	// from every origin otherwise. The result does not depend on it but for Manual.
+	Algorithm v1alpha1.WeightAlgorithm
+	// Workers is the number of origins whose costs are computed in parallel, 1 if lower. The result
+	// does not depend on it.
+	Workers int
+	// Paths, if set, keeps the cheapest paths computed by Dijkstra's algorithm for the next computation of
+	// the same weights, which only computes again the ones the changed links may be on. The result does not
+	// depend on it.
+	Paths *PathCache
+}
+
+// Tier is a level of the topology, the domain of a node in it being given by a node label.
+type Tier struct {
+	// Key is the node label naming the domain of a node in the tier.
+	Key v1alpha1.TopologyKey
+	// CrossCost is the default cost between two domains of the tier, and between the domains of the
+	// narrower tiers they hold.
+	CrossCost int64
+}
+
+// tiers returns the tiers of options, the region and zone ones if none.
+func (options Options) tiers() []Tier {
+	if len(options.Tiers) != 0 {
+		return options.Tiers
+	}
+	return []Tier{
+		{Key: v1alpha1.NetworkTopologyRegion, CrossCost: options.CrossRegionCost},
+		{Key: v1alpha1.NetworkTopologyZone, CrossCost: options.CrossZoneCost},
+	}
+}
+
+// ComputeWeights returns the weights named options.WeightsName with the costs between the domains of every tier
+// of options, sorted by origin and destination: the zones, and the regions if more than one, by default. The
+// domains of nodes and of costs are given a topology; the ones of the tiers wider than the narrowest only if
+// more than one. The links of costs, e.g. measured or set by hand, keep their cost; the pairs they connect
+// through other origins get the cost of the cheapest path, unless options.Algorithm is Manual; the other pairs
+// get the cross cost of the widest tier their nodes differ at. Negative costs are ignored. The domains of nodes without a wider domain are
+// assumed to share it.
+func ComputeWeights(nodes []v1.Node, costs v1alpha1.TopologyList, options Options) v1alpha1.WeightList {
+	tiers := options.tiers()
+	domains := make([]map[string]bool, len(tiers))
+	// parents holds, for every tier, the domains of the wider tiers holding each of its domains.
+	parents := make([]map[string][]string, len(tiers))
+	for i := range tiers {
+		domains[i] = make(map[string]bool)
+		parents[i] = make(map[string][]string)
+	}
+	for _, node := range nodes {
+		path := make([]string, len(tiers))
+		for i, tier := range tiers {
+			path[i] = node.Labels[string(tier.Key)]
+			if path[i] != "" {
+				domains[i][path[i]] = true
+				parents[i][path[i]] = path[:i]
+			}
+		}
+	}
+
+	var topologies v1alpha1.TopologyList
+	for i, tier := range tiers {
+		i, tier := i, tier
+		narrowest := i == len(tiers)-1
+		g := newGraph(costs, tier.Key, domains[i])
+		switch options.Algorithm {
+		case v1alpha1.WeightAlgorithmFloydWarshall:
+			g.allShortestPaths(options.Workers)
+		case v1alpha1.WeightAlgorithmManual:
+			// No path is searched, the pairs without a link get the default costs.
+			g.paths = make(map[string]map[string]int64)
+		default:
+			if options.Paths != nil {
+				options.Paths.paths(g, tier.Key, sortedNames(domains[i]), options.Workers)
+			}
+		}
+		tierCosts := func(origin string) costFunc {
+			known := g.costs(origin)
+			return func(destination string) (int64, bool) {
+				if c, ok := known(destination); ok {
+					return c, true
+				}
+				if origin == destination {
+					return options.SameZoneCost, narrowest
+				}
+				return crossCost(tiers[:i+1], parents[i][origin], parents[i][destination]), true
+			}
+		}
+		if len(domains[i]) > 1 || narrowest && len(domains[i]) != 0 {
+			topologies = append(topologies, costTopology(tier.Key, sortedNames(domains[i]), tierCosts, options.Workers))
+		}
+	}
+	return v1alpha1.WeightList{{Name: options.WeightsName, TopologyList: topologies}}
+}
+
+// crossCost returns the cross cost of the widest of tiers two different domains of the last one differ at,
+// given the domains of the wider tiers holding them. Unknown domains are assumed to be shared.
+func crossCost(tiers []Tier, origin, destination []string) int64 {
+	for i := range tiers[:len(tiers)-1] {
+		if domainAt(origin, i) != domainAt(destination, i) {
+			return tiers[i].CrossCost
+		}
+	}
+	return tiers[len(tiers)-1].CrossCost
+}
+
+// domainAt returns the domain of the tier i in path, empty if unknown.
+func domainAt(path []string, i int) string {
+	if i < len(path) {
+		return path[i]
+	}
+	return ""
+}
+
+// costFunc returns the cost from an origin to destination, false if the pair is left out.
+type costFunc func(destination string) (int64, bool)
+
+// graph holds the links of a topology key, keyed by origin then destination. It is only read once
+// built, so that the origins are computed concurrently.
+type graph struct {
+	links map[string]map[string]int64
+	// paths holds the costs of the cheapest paths between all the origins connected, keyed by origin then
+	// destination, if computed at once by allShortestPaths, empty if no path is searched.
+	paths map[string]map[string]int64
+}
+
+// newGraph returns the graph of the links of costs for key. Their origins and destinations are
+// added to origins.
+func newGraph(costs v1alpha1.TopologyList, key v1alpha1.TopologyKey, origins map[string]bool) *graph {
+	g := &graph{links: make(map[string]map[string]int64)}
+	for _, t := range costs {
+		if t.TopologyKey != key {
+			continue
+		}
+		for _, o := range t.OriginList {
+			for _, c := range o.CostList {
+				if c.NetworkCost < 0 {
+					continue
+				}
+				if g.links[o.Origin] == nil {
+					g.links[o.Origin] = make(map[string]int64)
+				}
+				g.links[o.Origin][c.Destination] = c.NetworkCost
+				origins[o.Origin] = true
+				origins[c.Destination] = true
+			}
+		}
+	}
+	return g
+}
+
+// costs returns the costs from origin: the cost of the link to a destination if any, else the cost
+// of the cheapest path to it, if they are connected.
+func (g *graph) costs(origin string) costFunc {
+	paths := g.paths[origin]
+	if g.paths == nil && len(g.links) != 0 {
+		paths = g.shortestPaths(origin)
+	}
+	return func(destination string) (int64, bool) {
+		if c, ok := g.links[origin][destination]; ok {
+			return c, true
+		}
+		c, ok := paths[destination]
+		return c, ok
+	}
+}
+
+// shortestPaths returns the costs of the cheapest paths from origin to the origins it is
+// connected to, by Dijkstra's algorithm.
+func (g *graph) shortestPaths(origin string) map[string]int64 {
+	dist := map[string]int64{origin: 0}
+	done := make(map[string]bool)
+	queue := &pathQueue{{origin: origin}}
+	for queue.Len() != 0 {
+		closest := heap.Pop(queue).(path)
+		if done[closest.origin] {
+			continue
+		}
+		done[closest.origin] = true
+		for next, c := range g.links[closest.origin] {
+			if d, ok := dist[next]; !ok || closest.cost+c < d {
+				dist[next] = closest.cost + c
+				heap.Push(queue, path{origin: next, cost: closest.cost + c})
+			}
+		}
+	}
+	delete(dist, origin)
+	return dist
+}
+
+// allShortestPaths computes the costs of the cheapest paths between all the origins connected by the
+// Floyd–Warshall algorithm, the rows of every step being spread across workers.
+func (g *graph) allShortestPaths(workers int) {
+	names := make(map[string]bool)
+	for origin, destinations := range g.links {
+		names[origin] = true
+		for destination := range destinations {
+			names[destination] = true
+		}
+	}
+	origins := sortedNames(names)
+	index := make(map[string]int, len(origins))
+	for i, origin := range origins {
+		index[origin] = i
+	}
+	// dist[i][j] is the cost of the cheapest path from i to j found so far, unreachable if negative.
+	dist := make([][]int64, len(origins))
+	for i := range dist {
+		dist[i] = make([]int64, len(origins))
+		for j := range dist[i] {
+			dist[i][j] = unreachable
+		}
+		dist[i][i] = 0
+	}
+	for origin, destinations := range g.links {
+		for destination, c := range destinations {
+			if i, j := index[origin], index[destination]; i != j {
+				dist[i][j] = c
+			}
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	// The row k is left unchanged by the step k, so that the other rows are updated concurrently.
+	for k := range origins {
+		through := dist[k]
+		workqueue.ParallelizeUntil(context.TODO(), workers, len(origins), func(i int) {
+			row := dist[i]
+			if i == k || row[k] == unreachable {
+				return
+			}
+			for j, c := range through {
+				if c != unreachable && (row[j] == unreachable || row[k]+c < row[j]) {
+					row[j] = row[k] + c
+				}
+			}
+		})
+	}
+
+	g.paths = make(map[string]map[string]int64, len(origins))
+	for i, origin := range origins {
+		paths := make(map[string]int64)
+		for j, c := range dist[i] {
+			if i != j && c != unreachable {
+				paths[origins[j]] = c
+			}
+		}
+		g.paths[origin] = paths
+	}
+}
+
+// unreachable marks the pairs without any path in the costs of allShortestPaths.
+const unreachable int64 = -1
+
+// path is the cost of a path to an origin.
+type path struct {
+	origin string
+	cost   int64
+}
+
+// pathQueue is a min-heap of paths by cost.
+type pathQueue []path
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(path)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	p := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return p
+}
+
+// costTopology returns the topology of the given key with the costs between all the origins, sorted.
+// The pairs the costs of their origin return false for are left out. The origins are spread across
+// workers, each one writing its own entry of the origin list.
+func costTopology(key v1alpha1.TopologyKey, origins []string, costs func(origin string) costFunc, workers int) v1alpha1.TopologyInfo {
+	topology := v1alpha1.TopologyInfo{TopologyKey: key, OriginList: make(v1alpha1.OriginList, len(origins))}
+	if workers < 1 {
+		workers = 1
+	}
+	workqueue.ParallelizeUntil(context.TODO(), workers, len(origins), func(i int) {
+		origin := origins[i]
+		cost := costs(origin)
+		info := v1alpha1.OriginInfo{Origin: origin}
+		for _, destination := range origins {
+			if c, ok := cost(destination); ok {
+				info.CostList = append(info.CostList, v1alpha1.CostInfo{Destination: destination, NetworkCost: c})
+			}
+		}
+		topology.OriginList[i] = info
+	})
+	return topology
+}
+
+// sortedNames returns the names of the set, sorted.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
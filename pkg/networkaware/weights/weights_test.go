@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weights
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "Rewrite the golden files of testdata with the current outputs.")
+
+// weightsFixture holds the inputs of ComputeWeights, read from a testdata/*.yaml fixture whose expected
+// weights are in the .golden file of the same name.
+type weightsFixture struct {
+	Options struct {
+		WeightsName     string `json:"weightsName"`
+		SameZoneCost    int64  `json:"sameZoneCost"`
+		CrossZoneCost   int64  `json:"crossZoneCost"`
+		CrossRegionCost int64  `json:"crossRegionCost"`
+		Tiers           []struct {
+			Key       v1alpha1.TopologyKey `json:"key"`
+			CrossCost int64                `json:"crossCost"`
+		} `json:"tiers"`
+	} `json:"options"`
+	Nodes []struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"nodes"`
+	// Costs are the link costs known, e.g. measured or set by hand.
+	Costs v1alpha1.TopologyList `json:"costs"`
+}
+
+// TestComputeWeightsGolden checks the costs computed from the fixtures of testdata against their golden
+// files, so that changes of the cost model are reviewed as diffs of the golden files, and that both
+// algorithms compute the same costs. Run with -update-golden to rewrite them.
+func TestComputeWeightsGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("Expected fixtures in testdata")
+	}
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var in weightsFixture
+			if err := yaml.UnmarshalStrict(data, &in); err != nil {
+				t.Fatal(err)
+			}
+			nodes := make([]v1.Node, 0, len(in.Nodes))
+			for _, n := range in.Nodes {
+				nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{Name: n.Name, Labels: n.Labels}})
+			}
+			var tiers []Tier
+			for _, tier := range in.Options.Tiers {
+				tiers = append(tiers, Tier{Key: tier.Key, CrossCost: tier.CrossCost})
+			}
+			options := Options{
+				WeightsName:     in.Options.WeightsName,
+				SameZoneCost:    in.Options.SameZoneCost,
+				CrossZoneCost:   in.Options.CrossZoneCost,
+				CrossRegionCost: in.Options.CrossRegionCost,
+				Tiers:           tiers,
+			}
+			weights := ComputeWeights(nodes, in.Costs, options)
+			got, err := yaml.Marshal(weights)
+			if err != nil {
+				t.Fatal(err)
+			}
+			options.Algorithm = v1alpha1.WeightAlgorithmFloydWarshall
+			if allPairs := ComputeWeights(nodes, in.Costs, options); !reflect.DeepEqual(weights, allPairs) {
+				t.Errorf("Expected the weights computed by Floyd–Warshall to be the same as by Dijkstra, got %v", allPairs)
+			}
+
+			golden := strings.TrimSuffix(fixture, ".yaml") + ".golden"
+			if *updateGolden {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Reading the golden file, run with -update-golden to create it: %v", err)
+			}
+			if !bytes.Equal(expected, got) {
+				t.Errorf("Weights differ from %s, run with -update-golden to accept them:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestComputeWeightsWorkers(t *testing.T) {
+	// A chain of measured links between the zones of two regions, so that every pair goes through the path algorithms.
+	var nodes []v1.Node
+	chain := v1alpha1.TopologyInfo{TopologyKey: v1alpha1.NetworkTopologyZone}
+	for i := 0; i < 200; i++ {
+		zone := fmt.Sprintf("zone-%03d", i)
+		nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("node-%03d", i),
+			Labels: map[string]string{v1.LabelTopologyRegion: fmt.Sprintf("region-%d", i%2), v1.LabelTopologyZone: zone},
+		}})
+		if i != 0 {
+			chain.OriginList = append(chain.OriginList, v1alpha1.OriginInfo{
+				Origin:   fmt.Sprintf("zone-%03d", i-1),
+				CostList: v1alpha1.CostList{{Destination: zone, NetworkCost: int64(i % 7)}},
+			})
+		}
+	}
+	options := Options{WeightsName: "UserDefined", SameZoneCost: 1, CrossZoneCost: 5, CrossRegionCost: 50, Workers: 1}
+
+	expected := ComputeWeights(nodes, v1alpha1.TopologyList{chain}, options)
+	options.Workers = 8
+	if got := ComputeWeights(nodes, v1alpha1.TopologyList{chain}, options); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected the weights computed by 8 workers to be the same as by 1")
+	}
+	options.Algorithm = v1alpha1.WeightAlgorithmFloydWarshall
+	for _, workers := range []int{1, 8} {
+		options.Workers = workers
+		if got := ComputeWeights(nodes, v1alpha1.TopologyList{chain}, options); !reflect.DeepEqual(expected, got) {
+			t.Errorf("Expected the weights computed by Floyd–Warshall and %d workers to be the same as by Dijkstra", workers)
+		}
+	}
+}
+
+func TestComputeWeightsManual(t *testing.T) {
+	var nodes []v1.Node
+	for _, zone := range []string{"z1", "z2", "z3"} {
+		nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-" + zone,
+			Labels: map[string]string{v1.LabelTopologyRegion: "r1", v1.LabelTopologyZone: zone},
+		}})
+	}
+	costs := v1alpha1.TopologyList{{TopologyKey: v1alpha1.NetworkTopologyZone, OriginList: v1alpha1.OriginList{
+		{Origin: "z1", CostList: v1alpha1.CostList{{Destination: "z2", NetworkCost: 2}}},
+		{Origin: "z2", CostList: v1alpha1.CostList{{Destination: "z3", NetworkCost: 2}}},
+	}}}
+	options := Options{WeightsName: "UserDefined", SameZoneCost: 1, CrossZoneCost: 5, CrossRegionCost: 50}
+
+	z1Costs := func(options Options) v1alpha1.CostList {
+		return ComputeWeights(nodes, costs, options)[0].TopologyList[0].OriginList[0].CostList
+	}
+	// z1 reaches z3 through z2, unless no path is searched.
+	expected := v1alpha1.CostList{{Destination: "z1", NetworkCost: 1}, {Destination: "z2", NetworkCost: 2}, {Destination: "z3", NetworkCost: 4}}
+	if got := z1Costs(options); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected the costs %v from z1 by Dijkstra, got %v", expected, got)
+	}
+	options.Algorithm = v1alpha1.WeightAlgorithmManual
+	expected[2].NetworkCost = 5
+	if got := z1Costs(options); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected the costs %v from z1 by hand, got %v", expected, got)
+	}
+}
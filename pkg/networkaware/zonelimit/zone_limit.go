@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zonelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// ZoneLimit is a filter plugin capping the number of pods of an AppGroup placed in any zone, as
+// set by the maxPodsPerZone of the AppGroup. Unlike the maxSkew of its zone distribution, the cap is
+// absolute, e.g. to match the capacity of a zone or to bound the blast radius of its failure.
+type ZoneLimit struct {
+	handle     framework.Handle
+	agLister   listers.AppGroupLister
+	podIndexer cache.Indexer
+
+	sync.Mutex
+	// reserved holds the zones of the pods reserved by the plugin whose binding is not observed
+	// yet by the pod informer, so that the pods of a burst are counted while being bound.
+	reserved map[types.UID]reservation
+}
+
+var _ framework.PreFilterPlugin = &ZoneLimit{}
+var _ framework.FilterPlugin = &ZoneLimit{}
+var _ framework.ReservePlugin = &ZoneLimit{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "ZoneLimit"
+
+	// preFilterStateKey is the key in CycleState to the pods per zone of the AppGroup of the pod.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonZoneLimit is returned when the zone of the node holds the maximum number of pods of the AppGroup.
+	ErrReasonZoneLimit = "node(s) in a zone with the maximum number of pods of the AppGroup"
+)
+
+// reservation is a pod reserved in a zone, not yet observed bound.
+type reservation struct {
+	appGroup string
+	zone     string
+}
+
+// preFilterState holds the number of pods of the AppGroup of the pod per zone.
+type preFilterState struct {
+	appGroup       string
+	maxPodsPerZone int
+	podsPerZone    map[string]int
+}
+
+// Clone the pods per zone, updated by the pods added or removed during preemption.
+func (s *preFilterState) Clone() framework.StateData {
+	podsPerZone := make(map[string]int, len(s.podsPerZone))
+	for zone, n := range s.podsPerZone {
+		podsPerZone[zone] = n
+	}
+	return &preFilterState{appGroup: s.appGroup, maxPodsPerZone: s.maxPodsPerZone, podsPerZone: podsPerZone}
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := &config.ZoneLimitArgs{}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.ZoneLimitArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type ZoneLimitArgs, got %T", obj)
+		}
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	agInformer := informerFactory.Scheduling().V1alpha1().AppGroups()
+	agLister := agInformer.Lister()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), agInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	if err := util.AddAppGroupPodIndex(podInformer); err != nil {
+		return nil, err
+	}
+	return newZoneLimit(handle, agLister, podInformer.GetIndexer()), nil
+}
+
+func newZoneLimit(handle framework.Handle, agLister listers.AppGroupLister, podIndexer cache.Indexer) *ZoneLimit {
+	return &ZoneLimit{
+		handle:     handle,
+		agLister:   agLister,
+		podIndexer: podIndexer,
+		reserved:   make(map[types.UID]reservation),
+	}
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (zl *ZoneLimit) Name() string {
+	return Name
+}
+
+// preFilter counts the pods of the AppGroup of the pod per zone, if the AppGroup caps them.
+func (zl *ZoneLimit) preFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	agName := util.GetPodAppGroupLabel(pod)
+	if agName == "" {
+		return nil
+	}
+	ag, err := zl.agLister.AppGroups(pod.Namespace).Get(agName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return framework.AsStatus(err)
+	}
+	if ag.Spec.MaxPodsPerZone <= 0 {
+		return nil
+	}
+	podsPerZone, err := zl.podsPerZone(pod, agName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	state.Write(preFilterStateKey, &preFilterState{
+		appGroup:       agName,
+		maxPodsPerZone: int(ag.Spec.MaxPodsPerZone),
+		podsPerZone:    podsPerZone,
+	})
+	return nil
+}
+
+// PreFilterExtensions returns the extensions updating the pods per zone during preemption.
+func (zl *ZoneLimit) PreFilterExtensions() framework.PreFilterExtensions {
+	return zl
+}
+
+// AddPod counts podToAdd in the zone of its node if it belongs to the AppGroup of the pod.
+func (zl *ZoneLimit) AddPod(ctx context.Context, state *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	zl.updatePodsPerZone(state, podToSchedule, podInfoToAdd.Pod, nodeInfo, 1)
+	return nil
+}
+
+// RemovePod uncounts podToRemove from the zone of its node if it belongs to the AppGroup of the pod.
+func (zl *ZoneLimit) RemovePod(ctx context.Context, state *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	zl.updatePodsPerZone(state, podToSchedule, podInfoToRemove.Pod, nodeInfo, -1)
+	return nil
+}
+
+// Filter rejects the nodes of the zones holding the maximum number of pods of the AppGroup of the
+// pod. The nodes without zone are never rejected.
+func (zl *ZoneLimit) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if s == nil || nodeInfo.Node() == nil {
+		return nil
+	}
+	zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]
+	if zone == "" {
+		return nil
+	}
+	if s.podsPerZone[zone] >= s.maxPodsPerZone {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonZoneLimit)
+	}
+	return nil
+}
+
+// Reserve counts the pod in the zone of its node until its binding is observed.
+func (zl *ZoneLimit) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil || s == nil {
+		return nil
+	}
+	nodeInfo, err := zl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil || nodeInfo.Node() == nil {
+		return nil
+	}
+	if zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]; zone != "" {
+		zl.Lock()
+		zl.reserved[pod.UID] = reservation{appGroup: pod.Namespace + "/" + s.appGroup, zone: zone}
+		zl.Unlock()
+	}
+	return nil
+}
+
+// Unreserve uncounts the pod, e.g. when rejected by a Permit plugin or failing to bind.
+func (zl *ZoneLimit) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	zl.Lock()
+	delete(zl.reserved, pod.UID)
+	zl.Unlock()
+}
+
+// podsPerZone counts the pods of AppGroup agName, but pod, placed per zone: the ones bound and not
+// terminated, from the pod index, and the ones reserved by the plugin but not bound yet. The
+// reservations of the pods observed bound or deleted are forgotten along the way.
+func (zl *ZoneLimit) podsPerZone(pod *v1.Pod, agName string) (map[string]int, error) {
+	members, err := util.GetAppGroupPods(zl.podIndexer, pod.Namespace, agName)
+	if err != nil {
+		return nil, err
+	}
+	agKey := pod.Namespace + "/" + agName
+	podsPerZone := make(map[string]int)
+	pending := make(map[types.UID]bool)
+	for _, p := range members {
+		// The pods succeeded or failed no longer take their place in the zone.
+		if p.UID == pod.UID || p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if p.Spec.NodeName == "" {
+			pending[p.UID] = true
+			continue
+		}
+		// The nodes missing from the snapshot, e.g. deleted, tell nothing.
+		nodeInfo, err := zl.handle.SnapshotSharedLister().NodeInfos().Get(p.Spec.NodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+		if zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]; zone != "" {
+			podsPerZone[zone]++
+		}
+	}
+
+	zl.Lock()
+	defer zl.Unlock()
+	for uid, r := range zl.reserved {
+		if r.appGroup != agKey || uid == pod.UID {
+			continue
+		}
+		if !pending[uid] {
+			delete(zl.reserved, uid)
+			continue
+		}
+		podsPerZone[r.zone]++
+	}
+	return podsPerZone, nil
+}
+
+func (zl *ZoneLimit) updatePodsPerZone(state *framework.CycleState, podToSchedule, pod *v1.Pod, nodeInfo *framework.NodeInfo, delta int) {
+	s, err := getPreFilterState(state)
+	if err != nil || s == nil || nodeInfo.Node() == nil {
+		return
+	}
+	if pod.Namespace != podToSchedule.Namespace || util.GetPodAppGroupLabel(pod) != s.appGroup {
+		return
+	}
+	if zone := nodeInfo.Node().Labels[v1.LabelTopologyZone]; zone != "" {
+		s.podsPerZone[zone] += delta
+	}
+}
+
+// getPreFilterState returns the state written in PreFilter, nil if the pod is not capped.
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		// The pod is out of any capped AppGroup.
+		return nil, nil
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to zonelimit.preFilterState error", c)
+	}
+	return s, nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zonelimit
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestZoneLimit(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyZone, "z1").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyZone, "z1").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyZone, "z2").Obj(),
+		st.MakeNode().Name("n4").Obj(),
+	}
+	makePod := func(name, appGroup, nodeName string) *v1.Pod {
+		return st.MakePod().Namespace("default").Name(name).UID(name).Label(v1alpha1.AppGroupLabel, appGroup).Node(nodeName).Obj()
+	}
+	placed := []*v1.Pod{
+		makePod("p1", "capped", "n1"),
+		makePod("p2", "capped", "n2"),
+		makePod("p3", "capped", "n3"),
+		makePod("p4", "uncapped", "n3"),
+		makePod("p5", "uncapped", "n3"),
+	}
+
+	fakeClient := clientsetfake.NewSimpleClientset()
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(fakeClient),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(placed, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, ag := range []*v1alpha1.AppGroup{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "capped"}, Spec: v1alpha1.AppGroupSpec{MaxPodsPerZone: 2}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "uncapped"}},
+	} {
+		if err := agIndexer.Add(ag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newPlugin := func(pods ...*v1.Pod) (*ZoneLimit, cache.Indexer) {
+		podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{util.AppGroupPodIndex: util.AppGroupPodIndexFunc})
+		for _, p := range append(pods, placed...) {
+			if err := podIndexer.Add(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return newZoneLimit(fh, listers.NewAppGroupLister(agIndexer), podIndexer), podIndexer
+	}
+	ctx := context.Background()
+	filter := func(zl *ZoneLimit, pod *v1.Pod) (*framework.CycleState, map[string]framework.Code) {
+		t.Helper()
+		state := framework.NewCycleState()
+		if s := zl.preFilter(ctx, state, pod); !s.IsSuccess() {
+			t.Fatalf("PreFilter failed: %v", s.AsError())
+		}
+		codes := make(map[string]framework.Code)
+		for _, n := range nodes {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(n)
+			codes[n.Name] = zl.Filter(ctx, state, pod, nodeInfo).Code()
+		}
+		return state, codes
+	}
+	expectCodes := func(name string, got map[string]framework.Code, expected map[string]framework.Code) {
+		t.Helper()
+		for node, code := range expected {
+			if got[node] != code {
+				t.Errorf("%s: expected %v on %s, got %v", name, code, node, got[node])
+			}
+		}
+	}
+
+	t.Run("capped zones", func(t *testing.T) {
+		pod := makePod("new", "capped", "")
+		zl, _ := newPlugin(pod)
+		_, codes := filter(zl, pod)
+		expectCodes("capped AppGroup", codes, map[string]framework.Code{
+			"n1": framework.Unschedulable, "n2": framework.Unschedulable, "n3": framework.Success, "n4": framework.Success,
+		})
+
+		uncapped := makePod("other", "uncapped", "")
+		_, codes = filter(zl, uncapped)
+		expectCodes("uncapped AppGroup", codes, map[string]framework.Code{"n1": framework.Success, "n3": framework.Success})
+
+		_, codes = filter(zl, st.MakePod().Namespace("default").Name("single").UID("single").Obj())
+		expectCodes("pod out of any AppGroup", codes, map[string]framework.Code{"n1": framework.Success, "n3": framework.Success})
+	})
+
+	t.Run("terminated pods not counted", func(t *testing.T) {
+		pod := makePod("new", "capped", "")
+		succeeded, failed := makePod("succeeded", "capped", "n3"), makePod("failed", "capped", "n3")
+		succeeded.Status.Phase = v1.PodSucceeded
+		failed.Status.Phase = v1.PodFailed
+		zl, _ := newPlugin(pod, succeeded, failed)
+		_, codes := filter(zl, pod)
+		expectCodes("terminated pods", codes, map[string]framework.Code{"n1": framework.Unschedulable, "n3": framework.Success})
+	})
+
+	t.Run("reserved pods counted until bound", func(t *testing.T) {
+		pod, next := makePod("new", "capped", ""), makePod("next", "capped", "")
+		zl, podIndexer := newPlugin(pod, next)
+		state, _ := filter(zl, pod)
+		if s := zl.Reserve(ctx, state, pod, "n3"); !s.IsSuccess() {
+			t.Fatalf("Reserve failed: %v", s.AsError())
+		}
+		_, codes := filter(zl, next)
+		expectCodes("pod reserved", codes, map[string]framework.Code{"n3": framework.Unschedulable})
+
+		zl.Unreserve(ctx, state, pod, "n3")
+		_, codes = filter(zl, next)
+		expectCodes("pod unreserved", codes, map[string]framework.Code{"n3": framework.Success})
+
+		if s := zl.Reserve(ctx, state, pod, "n3"); !s.IsSuccess() {
+			t.Fatalf("Reserve failed: %v", s.AsError())
+		}
+		bound := pod.DeepCopy()
+		bound.Spec.NodeName = "n3"
+		if err := podIndexer.Update(bound); err != nil {
+			t.Fatal(err)
+		}
+		_, codes = filter(zl, next)
+		expectCodes("pod bound", codes, map[string]framework.Code{"n3": framework.Unschedulable})
+		if len(zl.reserved) != 0 {
+			t.Errorf("expected the reservation of the bound pod to be forgotten, got %v", zl.reserved)
+		}
+	})
+
+	t.Run("preemption", func(t *testing.T) {
+		pod := makePod("new", "capped", "")
+		zl, _ := newPlugin(pod)
+		state, _ := filter(zl, pod)
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(nodes[0])
+		for _, p := range []*v1.Pod{placed[0], placed[3]} {
+			if s := zl.PreFilterExtensions().RemovePod(ctx, state, pod, framework.NewPodInfo(p), nodeInfo); !s.IsSuccess() {
+				t.Fatalf("RemovePod failed: %v", s.AsError())
+			}
+		}
+		if got := zl.Filter(ctx, state, pod, nodeInfo).Code(); got != framework.Success {
+			t.Errorf("expected the zone to be allowed once a pod of the AppGroup removed, got %v", got)
+		}
+		if s := zl.PreFilterExtensions().AddPod(ctx, state, pod, framework.NewPodInfo(placed[0]), nodeInfo); !s.IsSuccess() {
+			t.Fatalf("AddPod failed: %v", s.AsError())
+		}
+		if got := zl.Filter(ctx, state, pod, nodeInfo).Code(); got != framework.Unschedulable {
+			t.Errorf("expected the zone to be rejected once the pod added back, got %v", got)
+		}
+	})
+}
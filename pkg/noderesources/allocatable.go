@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +29,8 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
 )
 
 // Allocatable is a score plugin that favors nodes based on their allocatable
@@ -35,8 +38,15 @@ import (
 type Allocatable struct {
 	handle framework.Handle
 	resourceAllocationScorer
+	// diskPressureThreshold is the disk usage percentage above which nodes are penalized, 0 if disabled.
+	diskPressureThreshold int64
+	// vnpLister is nil unless the virtual nodes are scored by their VirtualNodeProfile.
+	vnpLister listers.VirtualNodeProfileLister
+	// nodeGroupLabel groups the nodes scored by their aggregate headroom, empty if disabled.
+	nodeGroupLabel string
 }
 
+var _ = framework.PreScorePlugin(&Allocatable{})
 var _ = framework.ScorePlugin(&Allocatable{})
 
 // AllocatableName is the name of the plugin used in the Registry and configurations.
@@ -64,6 +74,12 @@ func (alloc *Allocatable) Score(ctx context.Context, state *framework.CycleState
 		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
+	nodeInfo = alloc.virtualNodeInfo(nodeInfo)
+
+	if alloc.nodeGroupLabel != "" {
+		return alloc.scoreNodeGroup(state, pod, nodeInfo)
+	}
+
 	// alloc.score favors nodes with least allocatable or most allocatable resources.
 	// It calculates the sum of the node's weighted allocatable resources.
 	//
@@ -81,6 +97,10 @@ func NewAllocatable(allocArgs runtime.Object, h framework.Handle) (framework.Plu
 	// Start with default values.
 	mode := config.Least
 	resToWeightMap := defaultResourcesToWeightMap
+	var sharing *gpuSharing
+	var diskPressureThreshold int64
+	var vnpLister listers.VirtualNodeProfileLister
+	var nodeGroupLabel string
 
 	// Update values from args, if specified.
 	if allocArgs != nil {
@@ -105,6 +125,24 @@ func NewAllocatable(allocArgs runtime.Object, h framework.Handle) (framework.Plu
 				resToWeightMap[v1.ResourceName(resource.Name)] = resource.Weight
 			}
 		}
+
+		var err error
+		if sharing, err = newGPUSharing(args.GPUResourceName, args.GPUResourceFractions); err != nil {
+			return nil, err
+		}
+
+		if args.DiskPressureThresholdPercent < 0 || args.DiskPressureThresholdPercent >= 100 {
+			return nil, fmt.Errorf("disk pressure threshold should be in [0, 100), got %v", args.DiskPressureThresholdPercent)
+		}
+		diskPressureThreshold = int64(args.DiskPressureThresholdPercent)
+
+		if args.VirtualNodeProfiles {
+			var err error
+			if vnpLister, err = newVirtualNodeProfileLister(h, args); err != nil {
+				return nil, err
+			}
+		}
+		nodeGroupLabel = args.NodeGroupLabel
 	}
 
 	return &Allocatable{
@@ -113,7 +151,11 @@ func NewAllocatable(allocArgs runtime.Object, h framework.Handle) (framework.Plu
 			Name:                AllocatableName,
 			scorer:              resourceScorer(resToWeightMap, mode),
 			resourceToWeightMap: resToWeightMap,
+			gpuSharing:          sharing,
 		},
+		diskPressureThreshold: diskPressureThreshold,
+		vnpLister:             vnpLister,
+		nodeGroupLabel:        nodeGroupLabel,
 	}, nil
 }
 
@@ -162,10 +204,60 @@ func (alloc *Allocatable) NormalizeScore(ctx context.Context, state *framework.C
 	for i, nodeScore := range scores {
 		if oldRange == 0 {
 			scores[i].Score = framework.MinNodeScore
+			if alloc.diskPressureThreshold > 0 {
+				// Leave room for the disk pressure penalty to tell the nodes apart.
+				scores[i].Score = framework.MaxNodeScore
+			}
 		} else {
 			scores[i].Score = ((nodeScore.Score - lowest) * newRange / oldRange) + framework.MinNodeScore
 		}
 	}
 
+	if alloc.diskPressureThreshold > 0 {
+		for i := range scores {
+			nodeInfo, err := alloc.handle.SnapshotSharedLister().NodeInfos().Get(scores[i].Name)
+			if err != nil {
+				return framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", scores[i].Name, err))
+			}
+			scores[i].Score = alloc.diskPressurePenalty(scores[i].Score, diskUsage(pod, nodeInfo))
+		}
+	}
+
 	return nil
 }
+
+// diskPressurePenalty scales down the score of nodes whose disk usage is above the threshold, down to the
+// minimum score for full disks.
+func (alloc *Allocatable) diskPressurePenalty(score, usage int64) int64 {
+	if usage <= alloc.diskPressureThreshold {
+		return score
+	}
+	return score * (100 - usage) / (100 - alloc.diskPressureThreshold)
+}
+
+// diskUsage returns the highest of the usage of the node ephemeral-storage once the pod is bound, and of
+// the usage of the image filesystem annotated on the node, in percent.
+func diskUsage(pod *v1.Pod, nodeInfo *framework.NodeInfo) int64 {
+	var usage int64
+	allocatable, requested := calculateResourceAllocatableRequest(nodeInfo, pod, v1.ResourceEphemeralStorage)
+	if allocatable > 0 {
+		usage = requested * 100 / allocatable
+	}
+
+	node := nodeInfo.Node()
+	if value, ok := node.Annotations[v1alpha1.NodeImageFSUsageAnnotation]; ok {
+		imageFSUsage, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && imageFSUsage >= 0 && imageFSUsage <= 100 {
+			if imageFSUsage > usage {
+				usage = imageFSUsage
+			}
+		} else {
+			klog.V(4).InfoS("Ignoring invalid image filesystem usage", "node", klog.KObj(node), "usage", value)
+		}
+	}
+
+	if usage > 100 {
+		usage = 100
+	}
+	return usage
+}
@@ -35,6 +35,9 @@ import (
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	schedfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
 )
 
 func TestNodeResourcesAllocatable(t *testing.T) {
@@ -98,6 +101,12 @@ func TestNodeResourcesAllocatable(t *testing.T) {
 		{Name: string(v1.ResourceMemory), Weight: 1},
 	}
 
+	ephemeralStorage := makePod("ephemeralStorage", v1.ResourceList{
+		v1.ResourceCPU:              resource.MustParse("1000m"),
+		v1.ResourceMemory:           resource.MustParse("1Gi"),
+		v1.ResourceEphemeralStorage: resource.MustParse("9Gi")},
+	)
+
 	modeLeast := config.Least
 	modeMost := config.Most
 	tests := []struct {
@@ -217,6 +226,37 @@ func TestNodeResourcesAllocatable(t *testing.T) {
 				{Name: "machine3", Score: framework.MaxNodeScore}},
 			name: "nothing scheduled, resources requested, 3 differently sized machines, most mode",
 		},
+		{
+			pod: ephemeralStorage,
+			nodeInfos: []*framework.NodeInfo{
+				makeDiskNodeInfo("machine1", 4000, 10000, "10Gi", ""),
+				makeDiskNodeInfo("machine2", 4000, 10000, "100Gi", ""),
+				makeDiskNodeInfo("machine3", 4000, 10000, "100Gi", "95")},
+			args: config.NodeResourcesAllocatableArgs{Resources: defaultResourceAllocatableSet, Mode: modeLeast, DiskPressureThresholdPercent: 80},
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MaxNodeScore / 2},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: framework.MaxNodeScore / 4}},
+			name: "same sized machines, ephemeral-storage and image filesystem pressure",
+		},
+		{
+			pod: cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{
+				makeDiskNodeInfo("machine1", 4000, 10000, "", "90"),
+				makeDiskNodeInfo("machine2", 6000, 10000, "", "invalid")},
+			args: config.NodeResourcesAllocatableArgs{Resources: defaultResourceAllocatableSet, Mode: modeMost, DiskPressureThresholdPercent: 80},
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MinNodeScore},
+				{Name: "machine2", Score: framework.MaxNodeScore}},
+			name: "differently sized machines, invalid image filesystem usage ignored, most mode",
+		},
+		{
+			pod:       cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{makeNodeInfo("machine", 4000, 10000)},
+			args:      config.NodeResourcesAllocatableArgs{Resources: defaultResourceAllocatableSet, DiskPressureThresholdPercent: 100},
+			wantErr:   "disk pressure threshold should be in [0, 100), got 100",
+			name:      "disk pressure threshold out of range",
+		},
 		{
 			// resource with negative weight is not allowed
 			pod:       cpuAndMemory,
@@ -300,6 +340,245 @@ func TestNodeResourcesAllocatable(t *testing.T) {
 	}
 }
 
+func TestNodeResourcesAllocatableGPUSharing(t *testing.T) {
+	mig := v1.ResourceName("nvidia.com/mig-1g.5gb")
+	shared := v1.ResourceName("nvidia.com/gpu.shared")
+	fractions := []config.GPUResourceFraction{
+		{Name: string(mig), Fraction: 1.0 / 7},
+		{Name: string(shared), Fraction: 0.25},
+	}
+	gpuResourceAllocatableSet := []schedulerconfig.ResourceSpec{{Name: string(defaultGPUResourceName), Weight: 1}}
+
+	tests := []struct {
+		name      string
+		args      config.NodeResourcesAllocatableArgs
+		nodeInfo  *framework.NodeInfo
+		pod       *v1.Pod
+		wantScore int64
+		wantErr   string
+	}{
+		{
+			name:      "whole GPUs",
+			args:      config.NodeResourcesAllocatableArgs{Resources: gpuResourceAllocatableSet, Mode: config.Most, GPUResourceFractions: fractions},
+			nodeInfo:  makeResourceNodeInfo("machine1", v1.ResourceList{defaultGPUResourceName: resource.MustParse("2")}),
+			pod:       makePod("gpu", v1.ResourceList{defaultGPUResourceName: resource.MustParse("1")}),
+			wantScore: 2000,
+		},
+		{
+			name:      "MIG profiles",
+			args:      config.NodeResourcesAllocatableArgs{Resources: gpuResourceAllocatableSet, Mode: config.Most, GPUResourceFractions: fractions},
+			nodeInfo:  makeResourceNodeInfo("machine2", v1.ResourceList{mig: resource.MustParse("7")}),
+			pod:       makePod("mig", v1.ResourceList{mig: resource.MustParse("1")}),
+			wantScore: 1000,
+		},
+		{
+			name: "whole and time-sliced GPUs",
+			args: config.NodeResourcesAllocatableArgs{Resources: gpuResourceAllocatableSet, Mode: config.Most, GPUResourceFractions: fractions},
+			nodeInfo: makeResourceNodeInfo("machine3", v1.ResourceList{
+				defaultGPUResourceName: resource.MustParse("1"),
+				shared:                 resource.MustParse("4"),
+			}),
+			pod:       makePod("shared", v1.ResourceList{shared: resource.MustParse("1")}),
+			wantScore: 2000,
+		},
+		{
+			name:      "sharing not configured",
+			args:      config.NodeResourcesAllocatableArgs{Resources: gpuResourceAllocatableSet, Mode: config.Most},
+			nodeInfo:  makeResourceNodeInfo("machine2", v1.ResourceList{mig: resource.MustParse("7")}),
+			pod:       makePod("mig", v1.ResourceList{mig: resource.MustParse("1")}),
+			wantScore: 0,
+		},
+		{
+			name: "fraction out of range",
+			args: config.NodeResourcesAllocatableArgs{
+				Resources:            gpuResourceAllocatableSet,
+				GPUResourceFractions: []config.GPUResourceFraction{{Name: string(mig), Fraction: 2}},
+			},
+			wantErr: "GPU resource fraction of nvidia.com/mig-1g.5gb should be in (0, 1], got 2",
+		},
+		{
+			name: "fraction of the physical GPU resource",
+			args: config.NodeResourcesAllocatableArgs{
+				Resources:            gpuResourceAllocatableSet,
+				GPUResourceFractions: []config.GPUResourceFraction{{Name: string(defaultGPUResourceName), Fraction: 1}},
+			},
+			wantErr: `invalid GPU resource fraction name "nvidia.com/gpu"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc, err := NewAllocatable(&tt.args, nil)
+			if len(tt.wantErr) != 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got err %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to initialize plugin NodeResourcesAllocatable, got error: %v", err)
+			}
+			score, status := alloc.(*Allocatable).score(tt.pod, tt.nodeInfo)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected error: %v", status)
+			}
+			if score != tt.wantScore {
+				t.Errorf("expected score %v, got %v", tt.wantScore, score)
+			}
+		})
+	}
+}
+
+func TestNodeResourcesAllocatableVirtualNode(t *testing.T) {
+	informerFactory := schedinformers.NewSharedInformerFactory(schedfake.NewSimpleClientset(), 0)
+	vnpInformer := informerFactory.Scheduling().V1alpha1().VirtualNodeProfiles()
+	vnpInformer.Informer().GetStore().Add(&v1alpha1.VirtualNodeProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "virtual"},
+		Spec: v1alpha1.VirtualNodeProfileSpec{
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8"), v1.ResourceMemory: resource.MustParse("32Gi")},
+		},
+	})
+
+	labeled := func(ni *framework.NodeInfo) *framework.NodeInfo {
+		n := ni.Node().DeepCopy()
+		n.Labels = map[string]string{v1alpha1.VirtualNodeLabel: "true"}
+		ni.SetNode(n)
+		return ni
+	}
+	// The virtual kubelets report arbitrarily large allocatable.
+	huge := int64(1000000)
+	tests := []struct {
+		name      string
+		nodeInfo  *framework.NodeInfo
+		wantScore int64
+	}{
+		{
+			name:      "virtual node scored by its profile",
+			nodeInfo:  labeled(makeNodeInfo("virtual", huge*1000, huge<<30)),
+			wantScore: -(8000*(1<<20) + 32<<30) / (1<<20 + 1),
+		},
+		{
+			name:      "virtual node without profile",
+			nodeInfo:  labeled(makeNodeInfo("other", 4000, 16<<30)),
+			wantScore: -(4000*(1<<20) + 16<<30) / (1<<20 + 1),
+		},
+		{
+			name:      "profile of an ordinary node ignored",
+			nodeInfo:  makeNodeInfo("virtual", 4000, 16<<30),
+			wantScore: -(4000*(1<<20) + 16<<30) / (1<<20 + 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewAllocatable(&config.NodeResourcesAllocatableArgs{Mode: config.Least}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			alloc := p.(*Allocatable)
+			alloc.vnpLister = vnpInformer.Lister()
+			score, status := alloc.score(makePod("p", nil), alloc.virtualNodeInfo(tt.nodeInfo))
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected error: %v", status)
+			}
+			if score != tt.wantScore {
+				t.Errorf("expected score %v, got %v", tt.wantScore, score)
+			}
+		})
+	}
+}
+
+func TestNodeResourcesAllocatableNodeGroup(t *testing.T) {
+	rackLabel := "topology.example.com/rack"
+	inRack := func(ni *framework.NodeInfo, rack string) *framework.NodeInfo {
+		n := ni.Node().DeepCopy()
+		n.Labels = map[string]string{rackLabel: rack}
+		ni.SetNode(n)
+		return ni
+	}
+	withPod := func(ni *framework.NodeInfo, milliCPU int64) *framework.NodeInfo {
+		ni.AddPod(makePod("running", v1.ResourceList{v1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)}))
+		return ni
+	}
+	nodeInfos := []*framework.NodeInfo{
+		inRack(makeNodeInfo("r1-a", 4000, 0), "r1"),
+		inRack(withPod(makeNodeInfo("r1-b", 4000, 0), 3000), "r1"),
+		inRack(withPod(makeNodeInfo("r2-a", 8000, 0), 1000), "r2"),
+		makeNodeInfo("loner", 6000, 0),
+	}
+	cpuOnly := []schedulerconfig.ResourceSpec{{Name: string(v1.ResourceCPU), Weight: 1}}
+
+	tests := []struct {
+		name      string
+		mode      config.ModeType
+		preScore  bool
+		wantScore map[string]int64
+	}{
+		{
+			name:      "most aggregate headroom",
+			mode:      config.Most,
+			preScore:  true,
+			wantScore: map[string]int64{"r1-a": 5000, "r1-b": 5000, "r2-a": 7000, "loner": 6000},
+		},
+		{
+			name:      "least aggregate headroom",
+			mode:      config.Least,
+			preScore:  true,
+			wantScore: map[string]int64{"r1-a": -5000, "r1-b": -5000, "r2-a": -7000, "loner": -6000},
+		},
+		{
+			name:      "aggregated without PreScore",
+			mode:      config.Most,
+			wantScore: map[string]int64{"r1-a": 5000, "r1-b": 5000, "r2-a": 7000, "loner": 6000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := clientsetfake.NewSimpleClientset()
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := st.NewFramework(registeredPlugins, "default-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+				frameworkruntime.WithSnapshotSharedLister(&fakeSharedLister{nodes: nodeInfos}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := NewAllocatable(&config.NodeResourcesAllocatableArgs{Resources: cpuOnly, Mode: tt.mode, NodeGroupLabel: rackLabel}, fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			alloc := p.(*Allocatable)
+			pod := makePod("p", v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")})
+			state := framework.NewCycleState()
+			if tt.preScore {
+				if status := alloc.PreScore(context.Background(), state, pod, nil); !status.IsSuccess() {
+					t.Fatalf("unexpected error: %v", status)
+				}
+			}
+			for name, want := range tt.wantScore {
+				score, status := alloc.Score(context.Background(), state, pod, name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected error: %v", status)
+				}
+				if score != want {
+					t.Errorf("expected score %v for %v, got %v", want, name, score)
+				}
+			}
+		})
+	}
+}
+
+func makeResourceNodeInfo(node string, allocatable v1.ResourceList) *framework.NodeInfo {
+	ni := framework.NewNodeInfo()
+	ni.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: node},
+		Status:     v1.NodeStatus{Capacity: allocatable, Allocatable: allocatable},
+	})
+	return ni
+}
+
 func makeNodeInfo(node string, milliCPU, memory int64) *framework.NodeInfo {
 	ni := framework.NewNodeInfo()
 	ni.SetNode(&v1.Node{
@@ -318,6 +597,20 @@ func makeNodeInfo(node string, milliCPU, memory int64) *framework.NodeInfo {
 	return ni
 }
 
+func makeDiskNodeInfo(node string, milliCPU, memory int64, ephemeralStorage, imageFSUsage string) *framework.NodeInfo {
+	ni := makeNodeInfo(node, milliCPU, memory)
+	n := ni.Node().DeepCopy()
+	if ephemeralStorage != "" {
+		n.Status.Capacity[v1.ResourceEphemeralStorage] = resource.MustParse(ephemeralStorage)
+		n.Status.Allocatable[v1.ResourceEphemeralStorage] = resource.MustParse(ephemeralStorage)
+	}
+	if imageFSUsage != "" {
+		n.Annotations = map[string]string{v1alpha1.NodeImageFSUsageAnnotation: imageFSUsage}
+	}
+	ni.SetNode(n)
+	return ni
+}
+
 func makePod(name string, requests v1.ResourceList) *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// defaultGPUResourceName is the resource physical GPUs are exposed as by the NVIDIA device plugin.
+const defaultGPUResourceName v1.ResourceName = "nvidia.com/gpu"
+
+// milliGPUsPerGPU is the number of units a physical GPU is accounted as when GPU sharing is configured.
+const milliGPUsPerGPU = 1000
+
+// gpuSharing accounts the extended resources sharing physical GPUs as fractions of the physical GPU resource.
+type gpuSharing struct {
+	// gpuResourceName is the resource physical GPUs are exposed as.
+	gpuResourceName v1.ResourceName
+	// fractions maps the extended resources sharing physical GPUs to the fraction of a GPU a unit stands for.
+	fractions map[v1.ResourceName]float64
+}
+
+func newGPUSharing(gpuResourceName string, fractions []config.GPUResourceFraction) (*gpuSharing, error) {
+	if len(fractions) == 0 {
+		return nil, nil
+	}
+	gs := &gpuSharing{
+		gpuResourceName: defaultGPUResourceName,
+		fractions:       make(map[v1.ResourceName]float64, len(fractions)),
+	}
+	if gpuResourceName != "" {
+		gs.gpuResourceName = v1.ResourceName(gpuResourceName)
+	}
+	for _, f := range fractions {
+		name := v1.ResourceName(f.Name)
+		if name == "" || name == gs.gpuResourceName {
+			return nil, fmt.Errorf("invalid GPU resource fraction name %q", f.Name)
+		}
+		if _, ok := gs.fractions[name]; ok {
+			return nil, fmt.Errorf("duplicate GPU resource fraction %v", name)
+		}
+		if f.Fraction <= 0 || f.Fraction > 1 {
+			return nil, fmt.Errorf("GPU resource fraction of %v should be in (0, 1], got %v", name, f.Fraction)
+		}
+		gs.fractions[name] = f.Fraction
+	}
+	return gs, nil
+}
+
+// calculateGPUAllocatableRequest returns the allocatable and requested physical GPUs of a node,
+// in thousandths of a GPU, including the fractions of GPU shared as extended resources.
+func (gs *gpuSharing) calculateGPUAllocatableRequest(nodeInfo *framework.NodeInfo, pod *v1.Pod) (int64, int64) {
+	allocatable, requested := calculateResourceAllocatableRequest(nodeInfo, pod, gs.gpuResourceName)
+	allocatable *= milliGPUsPerGPU
+	requested *= milliGPUsPerGPU
+	for name, fraction := range gs.fractions {
+		a, r := calculateResourceAllocatableRequest(nodeInfo, pod, name)
+		allocatable += milliGPUs(a, fraction)
+		requested += milliGPUs(r, fraction)
+	}
+	return allocatable, requested
+}
+
+// milliGPUs returns the thousandths of a physical GPU that units of a fraction stand for.
+func milliGPUs(units int64, fraction float64) int64 {
+	return int64(math.Round(float64(units) * fraction * milliGPUsPerGPU))
+}
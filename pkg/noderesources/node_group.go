@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// nodeGroupStateKey is the key in CycleState to the aggregate headroom of the node groups.
+const nodeGroupStateKey = "PreScore" + AllocatableName + "NodeGroups"
+
+// nodeGroupState holds the headroom of the node groups, keyed by the value of the node group label.
+type nodeGroupState struct {
+	headroom map[string]resourceToValueMap
+}
+
+// Clone the node group state, read-only once written.
+func (s *nodeGroupState) Clone() framework.StateData {
+	return s
+}
+
+// PreScore aggregates the headroom of the nodes per group, if the nodes are scored by group.
+func (alloc *Allocatable) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	if alloc.nodeGroupLabel == "" {
+		return nil
+	}
+	headroom, err := alloc.nodeGroupHeadroom()
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	state.Write(nodeGroupStateKey, &nodeGroupState{headroom: headroom})
+	return nil
+}
+
+// scoreNodeGroup scores a node by the headroom of its group, or by its own headroom if it is in none.
+func (alloc *Allocatable) scoreNodeGroup(state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+	group, ok := node.Labels[alloc.nodeGroupLabel]
+	if !ok {
+		return alloc.scorer(nil, alloc.headroom(nodeInfo)), nil
+	}
+
+	headroom, err := nodeGroupHeadroomFromState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if headroom == nil {
+		// PreScore is not enabled, the groups are aggregated for every node.
+		if headroom, err = alloc.nodeGroupHeadroom(); err != nil {
+			return 0, framework.AsStatus(err)
+		}
+	}
+
+	score := alloc.scorer(nil, headroom[group])
+	klog.V(10).InfoS("Node group headroom and score", "podName", pod.Name, "nodeName", node.Name,
+		"nodeGroup", group, "headroom", headroom[group], "score", score)
+	return score, nil
+}
+
+// nodeGroupHeadroomFromState returns the headroom of the node groups written in PreScore, nil if none.
+func nodeGroupHeadroomFromState(state *framework.CycleState) (map[string]resourceToValueMap, error) {
+	if state == nil {
+		return nil, nil
+	}
+	c, err := state.Read(nodeGroupStateKey)
+	if err != nil {
+		return nil, nil
+	}
+	s, ok := c.(*nodeGroupState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to noderesources.nodeGroupState error", c)
+	}
+	return s.headroom, nil
+}
+
+// nodeGroupHeadroom returns the headroom of the nodes of the snapshot aggregated per group.
+func (alloc *Allocatable) nodeGroupHeadroom() (map[string]resourceToValueMap, error) {
+	nodeInfos, err := alloc.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes from Snapshot: %w", err)
+	}
+	headroom := make(map[string]resourceToValueMap)
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Node() == nil {
+			continue
+		}
+		group, ok := nodeInfo.Node().Labels[alloc.nodeGroupLabel]
+		if !ok {
+			continue
+		}
+		if headroom[group] == nil {
+			headroom[group] = make(resourceToValueMap, len(alloc.resourceToWeightMap))
+		}
+		for resource, value := range alloc.headroom(alloc.virtualNodeInfo(nodeInfo)) {
+			headroom[group][resource] += value
+		}
+	}
+	return headroom, nil
+}
+
+// headroom returns the allocatable minus the requested resources of a node, ignoring the pod being scheduled.
+func (alloc *Allocatable) headroom(nodeInfo *framework.NodeInfo) resourceToValueMap {
+	headroom := make(resourceToValueMap, len(alloc.resourceToWeightMap))
+	for resource := range alloc.resourceToWeightMap {
+		allocatable, requested := alloc.calculateAllocatableRequest(nodeInfo, &v1.Pod{}, resource)
+		if allocatable > requested {
+			headroom[resource] = allocatable - requested
+		}
+	}
+	return headroom
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// This file was copied from the main k/k repo and defaultResourcesToWeightMap was added.
+// This file was copied from the main k/k repo, defaultResourcesToWeightMap and GPU sharing were added.
 // See: https://github.com/kubernetes/kubernetes/blob/release-1.19/pkg/scheduler/framework/plugins/noderesources/resource_allocation.go
 
 package noderesources
@@ -42,6 +42,8 @@ type resourceAllocationScorer struct {
 	Name                string
 	scorer              func(requested, allocatable resourceToValueMap) int64
 	resourceToWeightMap resourceToWeightMap
+	// gpuSharing, if set, accounts the extended resources sharing physical GPUs as GPUs.
+	gpuSharing *gpuSharing
 }
 
 // resourceToValueMap contains resource name and score.
@@ -61,7 +63,7 @@ func (r *resourceAllocationScorer) score(
 	requested := make(resourceToValueMap, len(r.resourceToWeightMap))
 	allocatable := make(resourceToValueMap, len(r.resourceToWeightMap))
 	for resource := range r.resourceToWeightMap {
-		allocatable[resource], requested[resource] = calculateResourceAllocatableRequest(nodeInfo, pod, resource)
+		allocatable[resource], requested[resource] = r.calculateAllocatableRequest(nodeInfo, pod, resource)
 	}
 
 	score := r.scorer(requested, allocatable)
@@ -76,6 +78,15 @@ func (r *resourceAllocationScorer) score(
 	return score, nil
 }
 
+// calculateAllocatableRequest returns resources Allocatable and Requested values, accounting the
+// resources sharing physical GPUs as GPUs.
+func (r *resourceAllocationScorer) calculateAllocatableRequest(nodeInfo *framework.NodeInfo, pod *v1.Pod, resource v1.ResourceName) (int64, int64) {
+	if r.gpuSharing != nil && resource == r.gpuSharing.gpuResourceName {
+		return r.gpuSharing.calculateGPUAllocatableRequest(nodeInfo, pod)
+	}
+	return calculateResourceAllocatableRequest(nodeInfo, pod, resource)
+}
+
 // calculateResourceAllocatableRequest returns resources Allocatable and Requested values
 func calculateResourceAllocatableRequest(nodeInfo *framework.NodeInfo, pod *v1.Pod, resource v1.ResourceName) (int64, int64) {
 	podRequest := calculatePodResourceRequest(pod, resource)
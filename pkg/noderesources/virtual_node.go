@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// newVirtualNodeProfileLister returns the lister of the VirtualNodeProfiles, once synced, through a client
+// of the QPS and burst of args.
+func newVirtualNodeProfileLister(h framework.Handle, args *config.NodeResourcesAllocatableArgs) (listers.VirtualNodeProfileLister, error) {
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(h.KubeConfig(), AllocatableName, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	vnpInformer := informerFactory.Scheduling().V1alpha1().VirtualNodeProfiles()
+	lister := vnpInformer.Lister()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), vnpInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+	return lister, nil
+}
+
+// virtualNodeInfo returns a copy of nodeInfo with the allocatable of the VirtualNodeProfile of its node,
+// if the node is a virtual node whose profile declares one, and nodeInfo otherwise.
+func (alloc *Allocatable) virtualNodeInfo(nodeInfo *framework.NodeInfo) *framework.NodeInfo {
+	profile, ok := util.GetVirtualNodeProfile(alloc.vnpLister, nodeInfo.Node())
+	if !ok || len(profile.Spec.Allocatable) == 0 {
+		return nodeInfo
+	}
+	virtual := nodeInfo.Clone()
+	virtual.Allocatable = framework.NewResource(profile.Spec.Allocatable)
+	return virtual
+}
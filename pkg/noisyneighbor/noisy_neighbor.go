@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noisyneighbor
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	clientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	informers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// NoisyNeighbor is a score plugin that favors nodes not hosting pods whose interference
+// classes conflict with the incoming pod's one, as declared by InterferencePolicy objects.
+type NoisyNeighbor struct {
+	handle   framework.Handle
+	ipLister listers.InterferencePolicyLister
+}
+
+var _ framework.PreScorePlugin = &NoisyNeighbor{}
+var _ framework.ScorePlugin = &NoisyNeighbor{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "NoisyNeighbor"
+
+	// preScoreStateKey is the key in CycleState to NoisyNeighbor pre-computed data for Scoring.
+	preScoreStateKey = "PreScore" + Name
+
+	// defaultPenalty is applied for every conflicting pod when a rule does not set one.
+	defaultPenalty int64 = 1
+)
+
+// preScoreState computed at PreScore and used at Score.
+type preScoreState struct {
+	// penalties maps a conflicting interference class to the penalty of each pod carrying it.
+	penalties map[string]int64
+}
+
+// Clone the preScore state.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := &config.NoisyNeighborArgs{}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.NoisyNeighborArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type NoisyNeighborArgs, got %T", obj)
+		}
+	}
+
+	client := clientset.NewForConfigOrDie(util.NewClientConfig(handle.KubeConfig(), Name, args.KubeAPIQPS, args.KubeAPIBurst))
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	ipInformer := informerFactory.Scheduling().V1alpha1().InterferencePolicies()
+
+	ctx := context.TODO()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), ipInformer.Informer().HasSynced) {
+		err := fmt.Errorf("WaitForCacheSync failed")
+		klog.ErrorS(err, "Cannot sync caches")
+		return nil, err
+	}
+
+	return &NoisyNeighbor{
+		handle:   handle,
+		ipLister: ipInformer.Lister(),
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (nn *NoisyNeighbor) Name() string {
+	return Name
+}
+
+// PreScore computes the penalty of every interference class conflicting with the pod's one.
+func (nn *NoisyNeighbor) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	policies, err := nn.ipLister.List(labels.Everything())
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("listing InterferencePolicies: %w", err))
+	}
+	state.Write(preScoreStateKey, &preScoreState{
+		penalties: conflictPenalties(pod.Labels[v1alpha1.InterferenceClassLabel], policies),
+	})
+	return nil
+}
+
+// Score invoked at the score extension point.
+// It returns the sum of the penalties of the pods on the node conflicting with the incoming pod.
+func (nn *NoisyNeighbor) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if len(s.penalties) == 0 {
+		return 0, nil
+	}
+
+	nodeInfo, err := nn.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+
+	var penalty int64
+	for _, p := range nodeInfo.Pods {
+		class, ok := p.Pod.Labels[v1alpha1.InterferenceClassLabel]
+		if !ok {
+			continue
+		}
+		penalty += s.penalties[class]
+	}
+	return penalty, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (nn *NoisyNeighbor) ScoreExtensions() framework.ScoreExtensions {
+	return nn
+}
+
+// NormalizeScore maps the penalties onto the framework's score range, the highest penalty
+// getting the lowest score.
+func (nn *NoisyNeighbor) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	// Find highest and lowest penalties.
+	var highest int64 = -math.MaxInt64
+	var lowest int64 = math.MaxInt64
+	for _, nodeScore := range scores {
+		if nodeScore.Score > highest {
+			highest = nodeScore.Score
+		}
+		if nodeScore.Score < lowest {
+			lowest = nodeScore.Score
+		}
+	}
+
+	// Transform the highest to lowest penalty range to fit the framework's max to min node score range.
+	oldRange := highest - lowest
+	newRange := framework.MaxNodeScore - framework.MinNodeScore
+	for i, nodeScore := range scores {
+		if oldRange == 0 {
+			scores[i].Score = framework.MaxNodeScore
+		} else {
+			scores[i].Score = framework.MaxNodeScore - ((nodeScore.Score - lowest) * newRange / oldRange)
+		}
+	}
+	return nil
+}
+
+// conflictPenalties returns the penalty applied per pod of every interference class conflicting
+// with class. Rules are symmetric: a rule declaring A conflicting with B penalizes B pods when
+// placing an A pod and A pods when placing a B pod. The highest penalty wins on duplicates.
+func conflictPenalties(class string, policies []*v1alpha1.InterferencePolicy) map[string]int64 {
+	penalties := make(map[string]int64)
+	if class == "" {
+		return penalties
+	}
+	add := func(c string, penalty int64) {
+		if penalty > penalties[c] {
+			penalties[c] = penalty
+		}
+	}
+	for _, policy := range policies {
+		for _, rule := range policy.Spec.Rules {
+			penalty := rule.Penalty
+			if penalty <= 0 {
+				penalty = defaultPenalty
+			}
+			for _, conflicting := range rule.ConflictingClasses {
+				if rule.Class == class {
+					add(conflicting, penalty)
+				}
+				if conflicting == class {
+					add(rule.Class, penalty)
+				}
+			}
+		}
+	}
+	return penalties
+}
+
+func getPreScoreState(cycleState *framework.CycleState) (*preScoreState, error) {
+	c, err := cycleState.Read(preScoreStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preScoreStateKey, err)
+	}
+
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("invalid PreScore state, got type %T", c)
+	}
+	return s, nil
+}
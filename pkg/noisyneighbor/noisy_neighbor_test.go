@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noisyneighbor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	fakeclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	ipinformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestNoisyNeighborScore(t *testing.T) {
+	policy := makePolicy("policy",
+		v1alpha1.InterferenceRule{Class: "latency-critical", ConflictingClasses: []string{"cache-thrashing"}, Penalty: 3},
+		v1alpha1.InterferenceRule{Class: "latency-critical", ConflictingClasses: []string{"network-heavy"}},
+	)
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node1").Obj(),
+		st.MakeNode().Name("node2").Obj(),
+		st.MakeNode().Name("node3").Obj(),
+	}
+	existingPods := []*v1.Pod{
+		makePod("p1", "node1", "cache-thrashing"),
+		makePod("p2", "node1", "cache-thrashing"),
+		makePod("p3", "node2", "network-heavy"),
+		makePod("p4", "node2", "latency-critical"),
+		makePod("p5", "node3", ""),
+	}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		policies []*v1alpha1.InterferencePolicy
+		expected framework.NodeScoreList
+	}{
+		{
+			name:     "pod without interference class scores all nodes evenly",
+			pod:      makePod("pod", "", ""),
+			policies: []*v1alpha1.InterferencePolicy{policy},
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MaxNodeScore}, {Name: "node3", Score: framework.MaxNodeScore}},
+		},
+		{
+			name:     "no policy scores all nodes evenly",
+			pod:      makePod("pod", "", "latency-critical"),
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MaxNodeScore}, {Name: "node3", Score: framework.MaxNodeScore}},
+		},
+		{
+			name:     "latency-critical pod avoids nodes hosting conflicting pods",
+			pod:      makePod("pod", "", "latency-critical"),
+			policies: []*v1alpha1.InterferencePolicy{policy},
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: 84}, {Name: "node3", Score: framework.MaxNodeScore}},
+		},
+		{
+			name:     "conflicts are symmetric",
+			pod:      makePod("pod", "", "cache-thrashing"),
+			policies: []*v1alpha1.InterferencePolicy{policy},
+			expected: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MinNodeScore}, {Name: "node3", Score: framework.MaxNodeScore}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cs := fakeclientset.NewSimpleClientset()
+			ipInformerFactory := ipinformers.NewSharedInformerFactory(cs, 0)
+			ipInformer := ipInformerFactory.Scheduling().V1alpha1().InterferencePolicies()
+			for _, p := range tt.policies {
+				ipInformer.Informer().GetStore().Add(p)
+			}
+
+			fakeClient := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fh, err := st.NewFramework(registeredPlugins, "",
+				frameworkruntime.WithClientSet(fakeClient),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(existingPods, nodes)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nn := &NoisyNeighbor{handle: fh, ipLister: ipInformer.Lister()}
+			state := framework.NewCycleState()
+			if status := nn.PreScore(ctx, state, tt.pod, nodes); !status.IsSuccess() {
+				t.Fatalf("unexpected PreScore status: %v", status)
+			}
+			var gotList framework.NodeScoreList
+			for _, n := range nodes {
+				score, status := nn.Score(ctx, state, tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status: %v", status)
+				}
+				gotList = append(gotList, framework.NodeScore{Name: n.Name, Score: score})
+			}
+			if status := nn.NormalizeScore(ctx, state, tt.pod, gotList); !status.IsSuccess() {
+				t.Fatalf("unexpected NormalizeScore status: %v", status)
+			}
+			if !reflect.DeepEqual(tt.expected, gotList) {
+				t.Errorf("expected %v, got %v", tt.expected, gotList)
+			}
+		})
+	}
+}
+
+func TestConflictPenalties(t *testing.T) {
+	policies := []*v1alpha1.InterferencePolicy{
+		makePolicy("p1", v1alpha1.InterferenceRule{Class: "a", ConflictingClasses: []string{"b", "c"}, Penalty: 2}),
+		makePolicy("p2", v1alpha1.InterferenceRule{Class: "c", ConflictingClasses: []string{"a"}, Penalty: 5}),
+	}
+	tests := []struct {
+		class    string
+		expected map[string]int64
+	}{
+		{class: "a", expected: map[string]int64{"b": 2, "c": 5}},
+		{class: "b", expected: map[string]int64{"a": 2}},
+		{class: "d", expected: map[string]int64{}},
+		{class: "", expected: map[string]int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			if got := conflictPenalties(tt.class, policies); !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func makePolicy(name string, rules ...v1alpha1.InterferenceRule) *v1alpha1.InterferencePolicy {
+	return &v1alpha1.InterferencePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.InterferencePolicySpec{Rules: rules},
+	}
+}
+
+func makePod(name, nodeName, class string) *v1.Pod {
+	pod := st.MakePod().Name(name).Namespace("default").UID(name).Node(nodeName).Obj()
+	if class != "" {
+		pod.Labels = map[string]string{v1alpha1.InterferenceClassLabel: class}
+	}
+	return pod
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"k8s.io/kubernetes/cmd/kube-scheduler/app"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/backfill"
+	"sigs.k8s.io/scheduler-plugins/pkg/capacityscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/celpolicy"
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/imagelocality"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/crossregionlimit"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/linkheadroom"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/nodebandwidth"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/placementlabels"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/topologicalsort"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/warmpeers"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/zonelimit"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesources"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology"
+	"sigs.k8s.io/scheduler-plugins/pkg/noisyneighbor"
+	"sigs.k8s.io/scheduler-plugins/pkg/podstate"
+	"sigs.k8s.io/scheduler-plugins/pkg/preemptiontoleration"
+	"sigs.k8s.io/scheduler-plugins/pkg/qos"
+	"sigs.k8s.io/scheduler-plugins/pkg/spotawareness"
+	"sigs.k8s.io/scheduler-plugins/pkg/statefulsetzone"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/targetloadpacking"
+
+	// Ensure scheme package is initialized.
+	_ "sigs.k8s.io/scheduler-plugins/apis/config/scheme"
+)
+
+// NewInTreeRegistry returns the factories of all the plugins of this repository, keyed by
+// plugin name. Downstream scheduler builders can merge it with their own plugins.
+func NewInTreeRegistry() runtime.Registry {
+	return runtime.Registry{
+		backfill.Name:                   backfill.New,
+		capacityscheduling.Name:         capacityscheduling.New,
+		celpolicy.Name:                  celpolicy.New,
+		coscheduling.Name:               coscheduling.New,
+		crossregionlimit.Name:           crossregionlimit.New,
+		imagelocality.Name:              imagelocality.New,
+		linkheadroom.Name:               linkheadroom.New,
+		loadvariationriskbalancing.Name: loadvariationriskbalancing.New,
+		nodebandwidth.Name:              nodebandwidth.New,
+		noderesources.AllocatableName:   noderesources.NewAllocatable,
+		noderesourcetopology.Name:       noderesourcetopology.New,
+		noisyneighbor.Name:              noisyneighbor.New,
+		placementlabels.Name:            placementlabels.New,
+		preemptiontoleration.Name:       preemptiontoleration.New,
+		spotawareness.Name:              spotawareness.New,
+		statefulsetzone.Name:            statefulsetzone.New,
+		targetloadpacking.Name:          targetloadpacking.New,
+		topologicalsort.Name:            topologicalsort.New,
+		warmpeers.Name:                  warmpeers.New,
+		zonelimit.Name:                  zonelimit.New,
+		// Sample plugins below.
+		// crossnodepreemption.Name: crossnodepreemption.New,
+		podstate.Name: podstate.New,
+		qos.Name:      qos.New,
+	}
+}
+
+// NewSchedulerOptions returns the options registering the plugins of the given registry
+// to a kube-scheduler command built with app.NewSchedulerCommand.
+func NewSchedulerOptions(r runtime.Registry) []app.Option {
+	opts := make([]app.Option, 0, len(r))
+	for name, factory := range r {
+		opts = append(opts, app.WithPlugin(name, factory))
+	}
+	return opts
+}
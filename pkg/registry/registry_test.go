@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+)
+
+func TestNewInTreeRegistry(t *testing.T) {
+	r := NewInTreeRegistry()
+	if _, ok := r[coscheduling.Name]; !ok {
+		t.Errorf("expected %v to be registered", coscheduling.Name)
+	}
+
+	custom := frameworkruntime.Registry{
+		"Custom": func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) { return nil, nil },
+	}
+	if err := r.Merge(custom); err != nil {
+		t.Fatalf("unexpected error merging a custom plugin: %v", err)
+	}
+	if err := r.Merge(frameworkruntime.Registry{coscheduling.Name: coscheduling.New}); err == nil {
+		t.Errorf("expected an error merging an already registered plugin")
+	}
+
+	if opts := NewSchedulerOptions(r); len(opts) != len(r) {
+		t.Errorf("expected %v options, got %v", len(r), len(opts))
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotawareness
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// SpotAwareness is a score plugin steering the pods tolerating interruptions to spot nodes, and the
+// others away from them, based on the interruption risk of the nodes.
+type SpotAwareness struct {
+	handle                      framework.Handle
+	spotNodeLabels              map[string]string
+	defaultSpotInterruptionRisk int64
+	priorityClassPreferences    map[string]config.CapacityPreference
+}
+
+var _ framework.ScorePlugin = &SpotAwareness{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "SpotAwareness"
+)
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.SpotAwarenessArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type SpotAwarenessArgs, got %T", obj)
+	}
+	if args.DefaultSpotInterruptionRisk < 0 || args.DefaultSpotInterruptionRisk > 100 {
+		return nil, fmt.Errorf("defaultSpotInterruptionRisk must be in [0, 100], got %v", args.DefaultSpotInterruptionRisk)
+	}
+	preferences := make(map[string]config.CapacityPreference, len(args.PriorityClassPreferences))
+	for _, p := range args.PriorityClassPreferences {
+		switch p.Preference {
+		case config.CapacityPreferenceSpot, config.CapacityPreferenceOnDemand, config.CapacityPreferenceNone:
+		default:
+			return nil, fmt.Errorf("preference of priority class %q must be %s, %s or %s, got %q", p.PriorityClassName,
+				config.CapacityPreferenceSpot, config.CapacityPreferenceOnDemand, config.CapacityPreferenceNone, p.Preference)
+		}
+		preferences[p.PriorityClassName] = p.Preference
+	}
+
+	return &SpotAwareness{
+		handle:                      handle,
+		spotNodeLabels:              args.SpotNodeLabels,
+		defaultSpotInterruptionRisk: args.DefaultSpotInterruptionRisk,
+		priorityClassPreferences:    preferences,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (sa *SpotAwareness) Name() string {
+	return Name
+}
+
+// Score invoked at the score extension point.
+// Pods preferring spot capacity score spot nodes MaxNodeScore minus their interruption risk, and other
+// nodes MinNodeScore. Pods preferring on-demand capacity score every node MaxNodeScore minus its risk.
+func (sa *SpotAwareness) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	preference := sa.preference(pod)
+	if preference == config.CapacityPreferenceNone {
+		return framework.MinNodeScore, nil
+	}
+
+	nodeInfo, err := sa.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	node := nodeInfo.Node()
+	spot := sa.isSpot(node)
+	risk := sa.interruptionRisk(node, spot)
+	if preference == config.CapacityPreferenceSpot && !spot {
+		return framework.MinNodeScore, nil
+	}
+	return framework.MaxNodeScore - risk, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (sa *SpotAwareness) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// preference returns the capacity preferred by the pod: the one configured for its priority class,
+// else on-demand capacity for the members of a PodGroup and the pods without replicas, and spot
+// capacity for the replicated stateless pods.
+func (sa *SpotAwareness) preference(pod *v1.Pod) config.CapacityPreference {
+	if p, ok := sa.priorityClassPreferences[pod.Spec.PriorityClassName]; ok {
+		return p
+	}
+	if len(util.GetPodGroupLabel(pod)) != 0 {
+		return config.CapacityPreferenceOnDemand
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || (owner.Kind != "ReplicaSet" && owner.Kind != "ReplicationController") {
+		return config.CapacityPreferenceOnDemand
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return config.CapacityPreferenceOnDemand
+		}
+	}
+	return config.CapacityPreferenceSpot
+}
+
+// isSpot returns whether the node carries one of the spot node labels.
+func (sa *SpotAwareness) isSpot(node *v1.Node) bool {
+	for key, value := range sa.spotNodeLabels {
+		if v, ok := node.Labels[key]; ok && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// interruptionRisk returns the risk annotated on the node, else the default risk of spot nodes, and
+// no risk for other nodes.
+func (sa *SpotAwareness) interruptionRisk(node *v1.Node, spot bool) int64 {
+	if value, ok := node.Annotations[v1alpha1.NodeInterruptionRiskAnnotation]; ok {
+		risk, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && risk >= 0 && risk <= 100 {
+			return risk
+		}
+		klog.V(4).InfoS("Ignoring invalid interruption risk", "node", klog.KObj(node), "risk", value)
+	}
+	if spot {
+		return sa.defaultSpotInterruptionRisk
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotawareness
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestSpotAwarenessScore(t *testing.T) {
+	riskyNode := st.MakeNode().Name("risky-spot").Label("karpenter.sh/capacity-type", "spot").Obj()
+	riskyNode.Annotations = map[string]string{v1alpha1.NodeInterruptionRiskAnnotation: "80"}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("on-demand").Obj(),
+		st.MakeNode().Name("spot").Label("karpenter.sh/capacity-type", "spot").Obj(),
+		riskyNode,
+	}
+	args := &config.SpotAwarenessArgs{
+		SpotNodeLabels:              map[string]string{"karpenter.sh/capacity-type": "spot"},
+		DefaultSpotInterruptionRisk: 50,
+		PriorityClassPreferences: []config.SpotPriorityClassPreference{
+			{PriorityClassName: "batch", Preference: config.CapacityPreferenceSpot},
+			{PriorityClassName: "indifferent", Preference: config.CapacityPreferenceNone},
+		},
+	}
+
+	replicated := st.MakePod().Name("web").OwnerReference("web-7d9f", appsv1.SchemeGroupVersion.WithKind("ReplicaSet")).Obj()
+	controller := true
+	replicated.OwnerReferences[0].Controller = &controller
+	withClaim := replicated.DeepCopy()
+	withClaim.Spec.Volumes = []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}}}
+	batch := st.MakePod().Name("report").Obj()
+	batch.Spec.PriorityClassName = "batch"
+	indifferent := replicated.DeepCopy()
+	indifferent.Spec.PriorityClassName = "indifferent"
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected framework.NodeScoreList
+	}{
+		{
+			name:     "singleton pod prefers on-demand capacity",
+			pod:      st.MakePod().Name("singleton").Obj(),
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 100}, {Name: "spot", Score: 50}, {Name: "risky-spot", Score: 20}},
+		},
+		{
+			name:     "gang member prefers on-demand capacity",
+			pod:      st.MakePod().Name("worker").Label(v1alpha1.PodGroupLabel, "train").Obj(),
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 100}, {Name: "spot", Score: 50}, {Name: "risky-spot", Score: 20}},
+		},
+		{
+			name:     "replicated stateless pod prefers spot capacity",
+			pod:      replicated,
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 0}, {Name: "spot", Score: 50}, {Name: "risky-spot", Score: 20}},
+		},
+		{
+			name:     "replicated pod with a volume claim prefers on-demand capacity",
+			pod:      withClaim,
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 100}, {Name: "spot", Score: 50}, {Name: "risky-spot", Score: 20}},
+		},
+		{
+			name:     "priority class preferring spot capacity",
+			pod:      batch,
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 0}, {Name: "spot", Score: 50}, {Name: "risky-spot", Score: 20}},
+		},
+		{
+			name:     "priority class without preference",
+			pod:      indifferent,
+			expected: []framework.NodeScore{{Name: "on-demand", Score: 0}, {Name: "spot", Score: 0}, {Name: "risky-spot", Score: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeClient := clientsetfake.NewSimpleClientset()
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fh, err := st.NewFramework(registeredPlugins, "",
+				frameworkruntime.WithClientSet(fakeClient),
+				frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(fakeClient, 0)),
+				frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nodes)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := New(args, fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotList framework.NodeScoreList
+			for _, n := range nodes {
+				score, status := p.(*SpotAwareness).Score(ctx, framework.NewCycleState(), tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status: %v", status)
+				}
+				gotList = append(gotList, framework.NodeScore{Name: n.Name, Score: score})
+			}
+			if !reflect.DeepEqual(tt.expected, gotList) {
+				t.Errorf("expected %v, got %v", tt.expected, gotList)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *config.SpotAwarenessArgs
+		wantErr bool
+	}{
+		{name: "valid", args: &config.SpotAwarenessArgs{DefaultSpotInterruptionRisk: 50}},
+		{name: "risk out of range", args: &config.SpotAwarenessArgs{DefaultSpotInterruptionRisk: 101}, wantErr: true},
+		{
+			name: "unknown preference",
+			args: &config.SpotAwarenessArgs{PriorityClassPreferences: []config.SpotPriorityClassPreference{
+				{PriorityClassName: "batch", Preference: "Reserved"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, nil); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
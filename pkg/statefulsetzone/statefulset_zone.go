@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulsetzone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// StatefulSetZone is a plugin keeping the replicas of a StatefulSet in the zone they were first
+// placed in, so that they don't migrate across zones away from their data. The zone of every
+// ordinal is recorded in an annotation of the StatefulSet when the replica is first bound.
+// Enabled at Filter, the recorded zone is required; enabled at Score, it is preferred.
+type StatefulSetZone struct {
+	handle    framework.Handle
+	stsLister appslisters.StatefulSetLister
+}
+
+var _ framework.FilterPlugin = &StatefulSetZone{}
+var _ framework.ScorePlugin = &StatefulSetZone{}
+var _ framework.PostBindPlugin = &StatefulSetZone{}
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "StatefulSetZone"
+
+	// ZoneAnnotationPrefix prefixes the StatefulSet annotations recording the zone of each
+	// ordinal, e.g. zone.statefulset.scheduling.sigs.k8s.io/0: us-east-1a.
+	ZoneAnnotationPrefix = "zone.statefulset.scheduling.sigs.k8s.io/"
+
+	// ErrReasonZoneMismatch is the reason for a node not in the recorded zone of the replica.
+	ErrReasonZoneMismatch = "node(s) didn't match the recorded zone of the StatefulSet replica"
+)
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	return &StatefulSetZone{
+		handle:    handle,
+		stsLister: handle.SharedInformerFactory().Apps().V1().StatefulSets().Lister(),
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (sz *StatefulSetZone) Name() string {
+	return Name
+}
+
+// Filter invoked at the filter extension point.
+// It rejects the nodes outside of the recorded zone of the replica, if any.
+func (sz *StatefulSetZone) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	zone, ok := sz.recordedZone(pod)
+	if !ok || node.Labels[v1.LabelTopologyZone] == zone {
+		return nil
+	}
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonZoneMismatch)
+}
+
+// Score invoked at the score extension point.
+// It favors the nodes in the recorded zone of the replica, if any.
+func (sz *StatefulSetZone) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	zone, ok := sz.recordedZone(pod)
+	if !ok {
+		return 0, nil
+	}
+	nodeInfo, err := sz.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	if nodeInfo.Node() == nil || nodeInfo.Node().Labels[v1.LabelTopologyZone] != zone {
+		return framework.MinNodeScore, nil
+	}
+	return framework.MaxNodeScore, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (sz *StatefulSetZone) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// PostBind records the zone of the node the replica has been bound to, if none is recorded yet.
+func (sz *StatefulSetZone) PostBind(ctx context.Context, _ *framework.CycleState, pod *v1.Pod, nodeName string) {
+	stsName, ordinal, ok := statefulSetOrdinal(pod)
+	if !ok {
+		return
+	}
+	if _, ok := sz.recordedZone(pod); ok {
+		return
+	}
+	nodeInfo, err := sz.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil || nodeInfo.Node() == nil {
+		klog.ErrorS(err, "Cannot get node to record the zone of the StatefulSet replica", "pod", klog.KObj(pod), "node", nodeName)
+		return
+	}
+	zone, ok := nodeInfo.Node().Labels[v1.LabelTopologyZone]
+	if !ok {
+		return
+	}
+
+	// Every ordinal has its own annotation so that concurrent patches don't overwrite each other.
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{zoneAnnotation(ordinal): zone},
+		},
+	})
+	if err != nil {
+		klog.ErrorS(err, "Cannot build StatefulSet patch", "pod", klog.KObj(pod))
+		return
+	}
+	_, err = sz.handle.ClientSet().AppsV1().StatefulSets(pod.Namespace).Patch(ctx, stsName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Cannot record the zone of the StatefulSet replica", "pod", klog.KObj(pod), "zone", zone)
+		return
+	}
+	klog.V(4).InfoS("Recorded the zone of the StatefulSet replica", "pod", klog.KObj(pod), "zone", zone)
+}
+
+// recordedZone returns the zone recorded for the replica pod, if any.
+func (sz *StatefulSetZone) recordedZone(pod *v1.Pod) (string, bool) {
+	stsName, ordinal, ok := statefulSetOrdinal(pod)
+	if !ok {
+		return "", false
+	}
+	sts, err := sz.stsLister.StatefulSets(pod.Namespace).Get(stsName)
+	if err != nil {
+		return "", false
+	}
+	zone, ok := sts.Annotations[zoneAnnotation(ordinal)]
+	return zone, ok
+}
+
+// statefulSetOrdinal returns the name of the StatefulSet controlling pod and the ordinal of pod.
+func statefulSetOrdinal(pod *v1.Pod) (string, int, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "StatefulSet" {
+		return "", 0, false
+	}
+	suffix := strings.TrimPrefix(pod.Name, owner.Name+"-")
+	if suffix == pod.Name {
+		return "", 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 {
+		return "", 0, false
+	}
+	return owner.Name, ordinal, true
+}
+
+func zoneAnnotation(ordinal int) string {
+	return ZoneAnnotationPrefix + strconv.Itoa(ordinal)
+}
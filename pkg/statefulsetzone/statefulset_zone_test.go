@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulsetzone
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestStatefulSetZone(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-a").Label(v1.LabelTopologyZone, "zone-a").Obj(),
+		st.MakeNode().Name("node-b").Label(v1.LabelTopologyZone, "zone-b").Obj(),
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{zoneAnnotation(0): "zone-b"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		pod            *v1.Pod
+		expectedFilter map[string]framework.Code
+		expectedScore  map[string]int64
+	}{
+		{
+			name:           "replica with recorded zone",
+			pod:            makePod("db-0", "db"),
+			expectedFilter: map[string]framework.Code{"node-a": framework.UnschedulableAndUnresolvable, "node-b": framework.Success},
+			expectedScore:  map[string]int64{"node-a": framework.MinNodeScore, "node-b": framework.MaxNodeScore},
+		},
+		{
+			name:           "replica without recorded zone",
+			pod:            makePod("db-1", "db"),
+			expectedFilter: map[string]framework.Code{"node-a": framework.Success, "node-b": framework.Success},
+			expectedScore:  map[string]int64{"node-a": 0, "node-b": 0},
+		},
+		{
+			name:           "pod not owned by a StatefulSet",
+			pod:            st.MakePod().Name("db-0").Namespace("default").Obj(),
+			expectedFilter: map[string]framework.Code{"node-a": framework.Success, "node-b": framework.Success},
+			expectedScore:  map[string]int64{"node-a": 0, "node-b": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sz, _ := newTestPlugin(t, nodes, sts)
+			for _, n := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(n)
+				if got := sz.Filter(ctx, framework.NewCycleState(), tt.pod, nodeInfo).Code(); got != tt.expectedFilter[n.Name] {
+					t.Errorf("node %v: expected Filter code %v, got %v", n.Name, tt.expectedFilter[n.Name], got)
+				}
+				score, status := sz.Score(ctx, framework.NewCycleState(), tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status: %v", status)
+				}
+				if score != tt.expectedScore[n.Name] {
+					t.Errorf("node %v: expected score %v, got %v", n.Name, tt.expectedScore[n.Name], score)
+				}
+			}
+		})
+	}
+}
+
+func TestPostBind(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-a").Label(v1.LabelTopologyZone, "zone-a").Obj(),
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{zoneAnnotation(0): "zone-b"},
+		},
+	}
+
+	ctx := context.Background()
+	sz, cs := newTestPlugin(t, nodes, sts)
+	sz.PostBind(ctx, framework.NewCycleState(), makePod("db-0", "db"), "node-a")
+	sz.PostBind(ctx, framework.NewCycleState(), makePod("db-1", "db"), "node-a")
+
+	got, err := cs.AppsV1().StatefulSets("default").Get(ctx, "db", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone := got.Annotations[zoneAnnotation(0)]; zone != "zone-b" {
+		t.Errorf("expected the recorded zone of ordinal 0 to be kept, got %q", zone)
+	}
+	if zone := got.Annotations[zoneAnnotation(1)]; zone != "zone-a" {
+		t.Errorf("expected ordinal 1 to be recorded in zone-a, got %q", zone)
+	}
+}
+
+func TestStatefulSetOrdinal(t *testing.T) {
+	tests := []struct {
+		pod             *v1.Pod
+		expectedName    string
+		expectedOrdinal int
+		expectedOk      bool
+	}{
+		{pod: makePod("db-12", "db"), expectedName: "db", expectedOrdinal: 12, expectedOk: true},
+		{pod: makePod("db-x", "db"), expectedOk: false},
+		{pod: makePod("other-0", "db"), expectedOk: false},
+		{pod: st.MakePod().Name("db-0").Obj(), expectedOk: false},
+	}
+	for _, tt := range tests {
+		name, ordinal, ok := statefulSetOrdinal(tt.pod)
+		if name != tt.expectedName || ordinal != tt.expectedOrdinal || ok != tt.expectedOk {
+			t.Errorf("pod %v: expected (%q, %v, %v), got (%q, %v, %v)", tt.pod.Name,
+				tt.expectedName, tt.expectedOrdinal, tt.expectedOk, name, ordinal, ok)
+		}
+	}
+}
+
+func newTestPlugin(t *testing.T, nodes []*v1.Node, sts *appsv1.StatefulSet) (*StatefulSetZone, *clientsetfake.Clientset) {
+	cs := clientsetfake.NewSimpleClientset(sts)
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	informerFactory.Apps().V1().StatefulSets().Informer().GetStore().Add(sts)
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(registeredPlugins, "",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl, err := New(nil, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pl.(*StatefulSetZone), cs
+}
+
+func makePod(name, stsName string) *v1.Pod {
+	pod := st.MakePod().Name(name).Namespace("default").UID(name).Obj()
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: stsName, Controller: &controller}}
+	return pod
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+var (
+	lowConfidenceScores = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "trimaran_low_confidence_scores_total",
+			Help:           "Number of nodes scored by a Trimaran plugin from the requests of their pods, for lack of metrics history.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin"})
+
+	registerColdStartMetrics sync.Once
+)
+
+// ColdStart scores the nodes without metrics history, as configured by the cold start policy of a plugin.
+type ColdStart struct {
+	plugin string
+	policy pluginConfig.ColdStartPolicy
+}
+
+// NewColdStart returns the cold start of plugin, giving the minimum score to the nodes without
+// metrics history if policy is empty.
+func NewColdStart(plugin string, policy pluginConfig.ColdStartPolicy) (*ColdStart, error) {
+	switch policy {
+	case "":
+		policy = pluginConfig.ColdStartMinScore
+	case pluginConfig.ColdStartMinScore, pluginConfig.ColdStartRequestsBased:
+	default:
+		return nil, fmt.Errorf("invalid ColdStartPolicy, got %q", policy)
+	}
+	registerColdStartMetrics.Do(func() {
+		legacyregistry.MustRegister(lowConfidenceScores)
+	})
+	return &ColdStart{plugin: plugin, policy: policy}, nil
+}
+
+// NodeMetrics returns the metrics estimated from the requests of the pods of a node without
+// metrics history, nil if the node is to get the minimum score. The score computed from them is
+// recorded as low-confidence.
+func (c *ColdStart) NodeMetrics(nodeInfo *framework.NodeInfo) []watcher.Metric {
+	if c == nil || c.policy != pluginConfig.ColdStartRequestsBased || nodeInfo.Node() == nil {
+		return nil
+	}
+	lowConfidenceScores.WithLabelValues(c.plugin).Inc()
+	return RequestsBasedMetrics(nodeInfo)
+}
+
+// RequestsBasedMetrics estimates the CPU and memory utilization of a node, in percent of its
+// allocatable, as the requests of the pods assigned to it, without any variation.
+func RequestsBasedMetrics(nodeInfo *framework.NodeInfo) []watcher.Metric {
+	allocatable := nodeInfo.Node().Status.Allocatable
+	return []watcher.Metric{
+		{Type: watcher.CPU, Operator: watcher.Average, Value: percent(nodeInfo.Requested.MilliCPU, allocatable.Cpu().MilliValue())},
+		{Type: watcher.Memory, Operator: watcher.Average, Value: percent(nodeInfo.Requested.Memory, allocatable.Memory().Value())},
+	}
+}
+
+func percent(requested, allocatable int64) float64 {
+	if allocatable <= 0 {
+		return 0
+	}
+	return 100 * float64(requested) / float64(allocatable)
+}
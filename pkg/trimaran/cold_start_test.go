@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func TestColdStart(t *testing.T) {
+	_, err := NewColdStart("test", "Random")
+	assert.NotNil(t, err)
+
+	nodeInfo := framework.NewNodeInfo(
+		st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "250m", v1.ResourceMemory: "1Gi"}).Obj())
+	nodeInfo.SetNode(st.MakeNode().Name("n").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "4Gi"}).Obj())
+
+	minScore, err := NewColdStart("test", "")
+	assert.Nil(t, err)
+	assert.Nil(t, minScore.NodeMetrics(nodeInfo))
+
+	requestsBased, err := NewColdStart("test", pluginConfig.ColdStartRequestsBased)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []watcher.Metric{
+		{Type: watcher.CPU, Operator: watcher.Average, Value: 25},
+		{Type: watcher.Memory, Operator: watcher.Average, Value: 25},
+	}, requestsBased.NodeMetrics(nodeInfo))
+}
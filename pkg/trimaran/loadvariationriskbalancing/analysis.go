@@ -46,9 +46,19 @@ type resourceStats struct {
 // - risk = [ average + margin * stDev^{1/sensitivity} ] / 2
 // - score = ( 1 - risk ) * maxScore
 func (rs *resourceStats) computeScore(margin float64, sensitivity float64) float64 {
+	risk, ok := rs.computeRisk(margin, sensitivity)
+	if !ok {
+		return 0
+	}
+	return (1. - risk) * float64(framework.MaxNodeScore)
+}
+
+// computeRisk : compute risk, in [0, 1], given usage statistics, false if the capacity is invalid
+// - risk = [ average + margin * stDev^{1/sensitivity} ] / 2
+func (rs *resourceStats) computeRisk(margin float64, sensitivity float64) (float64, bool) {
 	if rs.capacity <= 0 {
 		klog.ErrorS(nil, "Invalid resource capacity", "capacity", rs.capacity)
-		return 0
+		return 0, false
 	}
 
 	// make sure values are within bounds
@@ -74,7 +84,7 @@ func (rs *resourceStats) computeScore(margin float64, sensitivity float64) float
 	// evaluate overall risk factor
 	risk := (mu + sigma) / 2
 	klog.V(6).InfoS("Evaluating risk factor", "mu", mu, "sigma", sigma, "margin", margin, "sensitivity", sensitivity, "risk", risk)
-	return (1. - risk) * float64(framework.MaxNodeScore)
+	return risk, true
 }
 
 // createResourceStats : get resource statistics data from measurements for a node
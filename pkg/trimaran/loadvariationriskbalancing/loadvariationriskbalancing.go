@@ -34,6 +34,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
 )
 
@@ -47,6 +48,10 @@ type LoadVariationRiskBalancing struct {
 	handle       framework.Handle
 	eventHandler *trimaran.PodAssignEventHandler
 	collector    *Collector
+	// osPolicy selects the nodes scored by their operating system.
+	osPolicy *trimaran.OperatingSystemPolicy
+	// coldStart scores the nodes without metrics history.
+	coldStart *trimaran.ColdStart
 }
 
 // New : create an instance of a LoadVariationRiskBalancing plugin
@@ -56,6 +61,18 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	if err != nil {
 		return nil, err
 	}
+	coldStart, err := trimaran.NewColdStart(Name, collector.args.ColdStartPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if softEvictionArgs := collector.args.SoftEviction; softEvictionArgs != nil {
+		softEviction, err := newSoftEviction(*softEvictionArgs, collector, handle)
+		if err != nil {
+			return nil, err
+		}
+		go softEviction.run()
+	}
 
 	podAssignEventHandler := trimaran.New()
 	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(
@@ -83,7 +100,11 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		handle:       handle,
 		eventHandler: podAssignEventHandler,
 		collector:    collector,
+		osPolicy: trimaran.NewOperatingSystemPolicy(collector.args.IncludedOperatingSystems,
+			collector.args.ExcludedOperatingSystems),
+		coldStart: coldStart,
 	}
+	debug.Register(Name, pl)
 	return pl, nil
 }
 
@@ -95,11 +116,20 @@ func (pl *LoadVariationRiskBalancing) Score(ctx context.Context, cycleState *fra
 	if err != nil {
 		return score, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
+	if !pl.osPolicy.Scores(nodeInfo.Node()) {
+		klog.V(6).InfoS("Node operating system not scored, using minimum score", "nodeName", nodeName,
+			"operatingSystem", trimaran.NodeOperatingSystem(nodeInfo.Node()))
+		return score, nil
+	}
 	// get node metrics
 	metrics := pl.collector.getNodeMetrics(nodeName)
 	if metrics == nil {
-		klog.InfoS("Failed to get metrics for node; using minimum score", "nodeName", nodeName)
-		return score, nil
+		// estimate the metrics of a node without history from the requests, depending on the cold start policy
+		if metrics = pl.coldStart.NodeMetrics(nodeInfo); metrics == nil {
+			klog.InfoS("Failed to get metrics for node; using minimum score", "nodeName", nodeName)
+			return score, nil
+		}
+		klog.V(6).InfoS("Using requests based metrics for node without history", "nodeName", nodeName)
 	}
 	podRequest := getResourceRequested(pod)
 	node := nodeInfo.Node()
@@ -134,6 +164,11 @@ func (pl *LoadVariationRiskBalancing) Name() string {
 	return Name
 }
 
+// Dump returns the latest metrics fetched from the load watcher.
+func (pl *LoadVariationRiskBalancing) Dump() interface{} {
+	return pl.collector.getAllMetrics()
+}
+
 // ScoreExtensions : an interface for Score extended functionality
 func (pl *LoadVariationRiskBalancing) ScoreExtensions() framework.ScoreExtensions {
 	return pl
@@ -134,7 +134,9 @@ func TestScore(t *testing.T) {
 		test            string
 		pod             *v1.Pod
 		nodes           []*v1.Node
+		pods            []*v1.Pod
 		watcherResponse watcher.WatcherMetrics
+		coldStartPolicy pluginConfig.ColdStartPolicy
 		expected        framework.NodeScoreList
 	}{
 		{
@@ -314,6 +316,21 @@ func TestScore(t *testing.T) {
 				{Name: "node-1", Score: framework.MinNodeScore},
 			},
 		},
+		{
+			test: "404 resp from watcher with requests based cold start",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			pods: []*v1.Pod{
+				st.MakePod().Name("assigned").Node("node-1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+			},
+			watcherResponse: watcher.WatcherMetrics{},
+			coldStartPolicy: pluginConfig.ColdStartRequestsBased,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 75},
+			},
+		},
 	}
 
 	registeredPlugins := []st.RegisterPluginFunc{
@@ -337,6 +354,7 @@ func TestScore(t *testing.T) {
 				WatcherAddress:          server.URL,
 				SafeVarianceMargin:      v1beta2.DefaultSafeVarianceMargin,
 				SafeVarianceSensitivity: v1beta2.DefaultSafeVarianceSensitivity,
+				ColdStartPolicy:         tt.coldStartPolicy,
 			}
 			loadVariationRiskBalancingConfig := config.PluginConfig{
 				Name: Name,
@@ -345,7 +363,7 @@ func TestScore(t *testing.T) {
 
 			cs := testClientSet.NewSimpleClientset()
 			informerFactory := informers.NewSharedInformerFactory(cs, 0)
-			snapshot := newTestSharedLister(nil, nodes)
+			snapshot := newTestSharedLister(tt.pods, nodes)
 
 			fh, err := testutil.NewFramework(registeredPlugins, []config.PluginConfig{loadVariationRiskBalancingConfig},
 				"default-scheduler", runtime.WithClientSet(cs),
@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+const (
+	// defaultSoftEvictionRiskThreshold is the default risk above which a node is overloaded
+	defaultSoftEvictionRiskThreshold = 0.8
+	// defaultSoftEvictionConsecutiveUpdates is the default number of metrics updates a node stays overloaded for
+	defaultSoftEvictionConsecutiveUpdates = 3
+	// defaultSoftEvictionMaxPods is the default number of pods annotated per overloaded node
+	defaultSoftEvictionMaxPods = 1
+)
+
+// softEviction : annotates the pods most likely to over-consume on the nodes whose risk stays above a threshold
+// with a soft eviction hint, and removes the hint once their node is no longer overloaded
+type softEviction struct {
+	args        pluginConfig.SoftEvictionArgs
+	margin      float64
+	sensitivity float64
+	collector   *Collector
+	client      kubernetes.Interface
+	nodeLister  corelisters.NodeLister
+	podLister   corelisters.PodLister
+	// timestamp of the last metrics evaluated
+	timestamp int64
+	// number of consecutive metrics updates every node has been overloaded for
+	overloaded map[string]int32
+}
+
+// newSoftEviction : create the soft eviction of the plugin, the defaults replacing the unset arguments
+func newSoftEviction(args pluginConfig.SoftEvictionArgs, collector *Collector, handle framework.Handle) (*softEviction, error) {
+	if args.RiskThreshold == 0 {
+		args.RiskThreshold = defaultSoftEvictionRiskThreshold
+	}
+	if args.ConsecutiveUpdates == 0 {
+		args.ConsecutiveUpdates = defaultSoftEvictionConsecutiveUpdates
+	}
+	if args.MaxPods == 0 {
+		args.MaxPods = defaultSoftEvictionMaxPods
+	}
+	if args.RiskThreshold < 0 || args.RiskThreshold > 1 {
+		return nil, fmt.Errorf("invalid SoftEviction.RiskThreshold, want in [0, 1], got %v", args.RiskThreshold)
+	}
+	if args.ConsecutiveUpdates < 0 || args.MaxPods < 0 {
+		return nil, fmt.Errorf("invalid SoftEviction, want positive ConsecutiveUpdates and MaxPods, got %v and %v",
+			args.ConsecutiveUpdates, args.MaxPods)
+	}
+	return &softEviction{
+		args:        args,
+		margin:      collector.args.SafeVarianceMargin,
+		sensitivity: collector.args.SafeVarianceSensitivity,
+		collector:   collector,
+		client:      handle.ClientSet(),
+		nodeLister:  handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
+		podLister:   handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		overloaded:  make(map[string]int32),
+	}, nil
+}
+
+// run : evaluates the nodes at every metrics update
+func (se *softEviction) run() {
+	ticker := time.NewTicker(time.Second * metricsUpdateIntervalSeconds)
+	for range ticker.C {
+		se.evaluate(context.Background())
+	}
+}
+
+// evaluate : updates the overloaded nodes from the latest metrics, if not evaluated yet, and their annotated pods
+func (se *softEviction) evaluate(ctx context.Context) {
+	metrics := se.collector.getAllMetrics()
+	if metrics.Data.NodeMetricsMap == nil || metrics.Timestamp == se.timestamp {
+		return
+	}
+	se.timestamp = metrics.Timestamp
+
+	nodes, err := se.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list nodes for soft eviction")
+		return
+	}
+	for _, node := range nodes {
+		nodeMetrics, ok := metrics.Data.NodeMetricsMap[node.Name]
+		resourceName, risk := se.nodeRisk(nodeMetrics.Metrics, node)
+		if !ok || risk <= se.args.RiskThreshold {
+			if se.overloaded[node.Name] != 0 {
+				delete(se.overloaded, node.Name)
+				se.unannotatePods(ctx, node.Name)
+			}
+			continue
+		}
+		se.overloaded[node.Name]++
+		if se.overloaded[node.Name] < se.args.ConsecutiveUpdates {
+			continue
+		}
+		reason := fmt.Sprintf("%s: %s risk %.2f of node %s above %.2f for %d metrics updates",
+			Name, resourceName, risk, node.Name, se.args.RiskThreshold, se.overloaded[node.Name])
+		se.annotatePods(ctx, node, resourceName, reason)
+	}
+	for name := range se.overloaded {
+		if _, err := se.nodeLister.Get(name); err != nil {
+			delete(se.overloaded, name)
+		}
+	}
+}
+
+// nodeRisk : returns the riskiest resource of a node and its risk, regardless of any incoming pod
+func (se *softEviction) nodeRisk(metrics []watcher.Metric, node *v1.Node) (v1.ResourceName, float64) {
+	var riskiest v1.ResourceName
+	maxRisk := 0.
+	for resourceName, watcherType := range map[v1.ResourceName]string{v1.ResourceCPU: watcher.CPU, v1.ResourceMemory: watcher.Memory} {
+		stats, ok := createResourceStats(metrics, node, &framework.Resource{}, resourceName, watcherType)
+		if !ok {
+			continue
+		}
+		if risk, ok := stats.computeRisk(se.margin, se.sensitivity); ok && risk > maxRisk {
+			riskiest, maxRisk = resourceName, risk
+		}
+	}
+	return riskiest, maxRisk
+}
+
+// annotatePods : annotates the pods of an overloaded node most likely to over-consume resourceName with reason
+func (se *softEviction) annotatePods(ctx context.Context, node *v1.Node, resourceName v1.ResourceName, reason string) {
+	pods, err := se.nodePods(node.Name)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list the pods of the overloaded node", "node", klog.KObj(node))
+		return
+	}
+	candidates := softEvictionCandidates(pods, resourceName)
+	if len(candidates) > int(se.args.MaxPods) {
+		candidates = candidates[:se.args.MaxPods]
+	}
+	for _, pod := range candidates {
+		if _, ok := pod.Annotations[v1alpha1.PodSoftEvictionAnnotation]; ok {
+			continue
+		}
+		if err := se.patchAnnotation(ctx, pod, &reason); err != nil {
+			klog.ErrorS(err, "Failed to annotate the pod with a soft eviction hint", "pod", klog.KObj(pod))
+			continue
+		}
+		klog.V(4).InfoS("Annotated the pod with a soft eviction hint", "pod", klog.KObj(pod), "node", klog.KObj(node), "reason", reason)
+	}
+}
+
+// unannotatePods : removes the soft eviction hints of the pods of a node no longer overloaded
+func (se *softEviction) unannotatePods(ctx context.Context, nodeName string) {
+	pods, err := se.nodePods(nodeName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list the pods of the node", "node", nodeName)
+		return
+	}
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[v1alpha1.PodSoftEvictionAnnotation]; !ok {
+			continue
+		}
+		if err := se.patchAnnotation(ctx, pod, nil); err != nil {
+			klog.ErrorS(err, "Failed to remove the soft eviction hint of the pod", "pod", klog.KObj(pod))
+		}
+	}
+}
+
+// patchAnnotation : sets the soft eviction annotation of a pod to reason, or removes it if reason is nil
+func (se *softEviction) patchAnnotation(ctx context.Context, pod *v1.Pod, reason *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{v1alpha1.PodSoftEvictionAnnotation: reason},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = se.client.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// nodePods : returns the pods assigned to a node
+func (se *softEviction) nodePods(nodeName string) ([]*v1.Pod, error) {
+	pods, err := se.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var nodePods []*v1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == nodeName {
+			nodePods = append(nodePods, pod)
+		}
+	}
+	return nodePods, nil
+}
+
+// softEvictionCandidates : returns the pods that may over-consume resourceName, the ones without limit first, then
+// by decreasing room between their limit and their request, then by increasing priority. The Guaranteed pods,
+// bounded by their requests, the terminating pods and the pods of DaemonSets, recreated on the node, are left out
+func softEvictionCandidates(pods []*v1.Pod, resourceName v1.ResourceName) []*v1.Pod {
+	type candidate struct {
+		pod       *v1.Pod
+		unbounded bool
+		room      int64
+	}
+	var candidates []candidate
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || v1qos.GetPodQOS(pod) == v1.PodQOSGuaranteed {
+			continue
+		}
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		unbounded, room := overConsumptionRoom(pod, resourceName)
+		if !unbounded && room <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{pod: pod, unbounded: unbounded, room: room})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].unbounded != candidates[j].unbounded {
+			return candidates[i].unbounded
+		}
+		if candidates[i].room != candidates[j].room {
+			return candidates[i].room > candidates[j].room
+		}
+		return podPriority(candidates[i].pod) < podPriority(candidates[j].pod)
+	})
+	result := make([]*v1.Pod, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.pod
+	}
+	return result
+}
+
+// overConsumptionRoom : returns whether a container of a pod has no limit of resourceName, and the sum of the
+// limits minus the requests of resourceName of its containers, in milli-units for the CPU
+func overConsumptionRoom(pod *v1.Pod, resourceName v1.ResourceName) (bool, int64) {
+	unbounded := false
+	var room int64
+	for _, container := range pod.Spec.Containers {
+		limit, ok := container.Resources.Limits[resourceName]
+		if !ok {
+			unbounded = true
+			continue
+		}
+		request := container.Resources.Requests[resourceName]
+		if resourceName == v1.ResourceCPU {
+			room += limit.MilliValue() - request.MilliValue()
+		} else {
+			room += limit.Value() - request.Value()
+		}
+	}
+	return unbounded, room
+}
+
+// podPriority : returns the priority of a pod, 0 if not set
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
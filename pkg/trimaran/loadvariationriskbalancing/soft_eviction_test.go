@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestSoftEvictionEvaluate(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("4"),
+			v1.ResourceMemory: resource.MustParse("16Gi"),
+		}},
+	}
+	makePod := func(name string, request, limit string, priority int32) *v1.Pod {
+		container := v1.Container{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(request)},
+		}}
+		if limit != "" {
+			container.Resources.Limits = v1.ResourceList{v1.ResourceCPU: resource.MustParse(limit)}
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1.PodSpec{NodeName: "node-1", Priority: pointer.Int32(priority), Containers: []v1.Container{container}},
+		}
+	}
+	pods := []*v1.Pod{
+		makePod("bounded", "100m", "1", 0),
+		makePod("unbounded", "100m", "", 100),
+		makePod("guaranteed", "1", "1", 0),
+	}
+
+	cs := testClientSet.NewSimpleClientset(node, pods[0], pods[1], pods[2])
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	informerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+	for _, pod := range pods {
+		informerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+	}
+	collector := &Collector{args: &pluginConfig.LoadVariationRiskBalancingArgs{SafeVarianceMargin: 1, SafeVarianceSensitivity: 1}}
+	setCPU := func(timestamp int64, avg, std float64) {
+		collector.metrics = watcher.WatcherMetrics{
+			Timestamp: timestamp,
+			Data: watcher.Data{NodeMetricsMap: map[string]watcher.NodeMetrics{"node-1": {Metrics: []watcher.Metric{
+				{Type: watcher.CPU, Operator: watcher.Average, Value: avg},
+				{Type: watcher.CPU, Operator: watcher.Std, Value: std},
+			}}}},
+		}
+	}
+	se := &softEviction{
+		args:        pluginConfig.SoftEvictionArgs{RiskThreshold: 0.8, ConsecutiveUpdates: 2, MaxPods: 2},
+		margin:      1,
+		sensitivity: 1,
+		collector:   collector,
+		client:      cs,
+		nodeLister:  informerFactory.Core().V1().Nodes().Lister(),
+		podLister:   informerFactory.Core().V1().Pods().Lister(),
+		overloaded:  make(map[string]int32),
+	}
+	annotated := func() map[string]bool {
+		result := make(map[string]bool)
+		for _, pod := range pods {
+			got, err := cs.CoreV1().Pods("default").Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			assert.Nil(t, err)
+			if _, ok := got.Annotations[v1alpha1.PodSoftEvictionAnnotation]; ok {
+				result[pod.Name] = true
+			}
+		}
+		return result
+	}
+	ctx := context.Background()
+
+	// risk (0.9 + 0.8) / 2 = 0.85, overloaded once
+	setCPU(1, 90, 80)
+	se.evaluate(ctx)
+	assert.Empty(t, annotated())
+	// same metrics, not counted twice
+	se.evaluate(ctx)
+	assert.Empty(t, annotated())
+
+	setCPU(2, 90, 80)
+	se.evaluate(ctx)
+	assert.Equal(t, map[string]bool{"unbounded": true, "bounded": true}, annotated())
+
+	// sync the annotations in the cache, then cool down the node
+	for _, pod := range pods {
+		got, _ := cs.CoreV1().Pods("default").Get(ctx, pod.Name, metav1.GetOptions{})
+		informerFactory.Core().V1().Pods().Informer().GetIndexer().Update(got)
+	}
+	setCPU(3, 20, 10)
+	se.evaluate(ctx)
+	assert.Empty(t, annotated())
+}
+
+func TestSoftEvictionCandidates(t *testing.T) {
+	makePod := func(name string, requests, limits v1.ResourceList, priority int32, owner string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.PodSpec{Priority: pointer.Int32(priority), Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: requests, Limits: limits},
+			}}},
+		}
+		if owner != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: owner, Name: "owner", Controller: pointer.Bool(true)}}
+		}
+		return pod
+	}
+	cpu := func(q string) v1.ResourceList {
+		return v1.ResourceList{v1.ResourceCPU: resource.MustParse(q)}
+	}
+	pods := []*v1.Pod{
+		makePod("small-room", cpu("1"), cpu("1500m"), 0, ""),
+		makePod("large-room", cpu("1"), cpu("3"), 0, ""),
+		makePod("no-limit-high", cpu("1"), nil, 1000, ""),
+		makePod("no-limit-low", cpu("1"), nil, 0, ""),
+		makePod("daemon", nil, nil, 0, "DaemonSet"),
+		makePod("guaranteed", cpu("1"), cpu("1"), 0, ""),
+	}
+	var got []string
+	for _, pod := range softEvictionCandidates(pods, v1.ResourceCPU) {
+		got = append(got, pod.Name)
+	}
+	assert.Equal(t, []string{"no-limit-low", "no-limit-high", "large-room", "small-room"}, got)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// OperatingSystemPolicy selects the nodes scored by a load aware plugin by their operating system, as in
+// the kubernetes.io/os label of the nodes, e.g. to leave out the windows nodes of a mixed cluster for
+// which no load is measured.
+type OperatingSystemPolicy struct {
+	// included is empty if the nodes of all the operating systems are included.
+	included sets.String
+	excluded sets.String
+}
+
+// NewOperatingSystemPolicy returns the policy scoring the nodes of the included operating systems, all if
+// empty, except those of the excluded ones.
+func NewOperatingSystemPolicy(included, excluded []string) *OperatingSystemPolicy {
+	return &OperatingSystemPolicy{included: sets.NewString(included...), excluded: sets.NewString(excluded...)}
+}
+
+// Scores returns whether node is scored by the plugin.
+func (p *OperatingSystemPolicy) Scores(node *v1.Node) bool {
+	os := NodeOperatingSystem(node)
+	if p.included.Len() != 0 && !p.included.Has(os) {
+		return false
+	}
+	return !p.excluded.Has(os)
+}
+
+// NodeOperatingSystem returns the operating system of node, empty if unknown.
+func NodeOperatingSystem(node *v1.Node) string {
+	return node.Labels[v1.LabelOSStable]
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestOperatingSystemPolicy(t *testing.T) {
+	linux := st.MakeNode().Name("linux").Label(v1.LabelOSStable, "linux").Obj()
+	windows := st.MakeNode().Name("windows").Label(v1.LabelOSStable, "windows").Obj()
+	unlabeled := st.MakeNode().Name("unlabeled").Obj()
+
+	tests := []struct {
+		name     string
+		included []string
+		excluded []string
+		want     map[*v1.Node]bool
+	}{
+		{
+			name: "all scored by default",
+			want: map[*v1.Node]bool{linux: true, windows: true, unlabeled: true},
+		},
+		{
+			name:     "only included scored",
+			included: []string{"linux"},
+			want:     map[*v1.Node]bool{linux: true, windows: false, unlabeled: false},
+		},
+		{
+			name:     "excluded not scored",
+			excluded: []string{"windows"},
+			want:     map[*v1.Node]bool{linux: true, windows: false, unlabeled: true},
+		},
+		{
+			name:     "exclusion wins over inclusion",
+			included: []string{"linux", "windows"},
+			excluded: []string{"windows"},
+			want:     map[*v1.Node]bool{linux: true, windows: false, unlabeled: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewOperatingSystemPolicy(tt.included, tt.excluded)
+			for node, want := range tt.want {
+				if got := p.Scores(node); got != want {
+					t.Errorf("Scores(%s) = %v, want %v", node.Name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -42,7 +42,10 @@ import (
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta2"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/debug"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
 
 const (
@@ -53,6 +56,21 @@ const (
 	Name                                 = "TargetLoadPacking"
 )
 
+// Types of the load watcher metrics reporting the pressure stall information of a node, in percent,
+// as an alternative to the pressure annotations of the nodes.
+const (
+	CPUPressure    = "CPUPressure"
+	MemoryPressure = "MemoryPressure"
+	IOPressure     = "IOPressure"
+)
+
+// pressureAnnotations are the node annotations holding the pressure stall information of the node.
+var pressureAnnotations = []string{
+	v1alpha1.NodeCPUPressureAnnotation,
+	v1alpha1.NodeMemoryPressureAnnotation,
+	v1alpha1.NodeIOPressureAnnotation,
+}
+
 var (
 	requestsMilliCores           = v1beta2.DefaultRequestsMilliCores
 	hostTargetUtilizationPercent = v1beta2.DefaultTargetUtilizationPercent
@@ -64,6 +82,15 @@ type TargetLoadPacking struct {
 	client       loadwatcherapi.Client
 	metrics      watcher.WatcherMetrics
 	eventHandler *trimaran.PodAssignEventHandler
+	scoreBudget  *util.LatencyBudget
+	// pressureThreshold is the pressure above which nodes are penalized, 0 if disabled.
+	pressureThreshold float64
+	// osPolicy selects the nodes scored by their operating system.
+	osPolicy *trimaran.OperatingSystemPolicy
+	// osTargetUtilization overrides the target utilization per operating system.
+	osTargetUtilization map[string]int64
+	// coldStart scores the nodes without metrics history.
+	coldStart *trimaran.ColdStart
 	// For safe access to metrics
 	mu sync.RWMutex
 }
@@ -77,6 +104,10 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	requestsMilliCores = args.DefaultRequests.Cpu().MilliValue()
 	requestsMultiplier, _ = strconv.ParseFloat(args.DefaultRequestsMultiplier, 64)
 
+	coldStart, err := trimaran.NewColdStart(Name, args.ColdStartPolicy)
+	if err != nil {
+		return nil, err
+	}
 	podAssignEventHandler := trimaran.New()
 
 	var client loadwatcherapi.Client
@@ -99,6 +130,12 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		handle:       handle,
 		client:       client,
 		eventHandler: podAssignEventHandler,
+		scoreBudget:  util.NewLatencyBudget(Name, "Score", time.Duration(args.ScoreBudgetMilliseconds)*time.Millisecond),
+
+		pressureThreshold:   float64(args.PressureThresholdPercent),
+		osPolicy:            trimaran.NewOperatingSystemPolicy(args.IncludedOperatingSystems, args.ExcludedOperatingSystems),
+		osTargetUtilization: args.TargetUtilizationPerOperatingSystem,
+		coldStart:           coldStart,
 	}
 
 	pl.handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(
@@ -137,6 +174,7 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		}
 	}()
 
+	debug.Register(Name, pl)
 	return pl, nil
 }
 
@@ -157,6 +195,13 @@ func (pl *TargetLoadPacking) Name() string {
 	return Name
 }
 
+// Dump returns the latest metrics fetched from the load watcher.
+func (pl *TargetLoadPacking) Dump() interface{} {
+	pl.mu.RLock()
+	defer pl.mu.RUnlock()
+	return pl.metrics
+}
+
 func getArgs(obj runtime.Object) (*pluginConfig.TargetLoadPackingArgs, error) {
 	args, ok := obj.(*pluginConfig.TargetLoadPackingArgs)
 	if !ok {
@@ -175,31 +220,62 @@ func getArgs(obj runtime.Object) (*pluginConfig.TargetLoadPackingArgs, error) {
 	if err != nil {
 		return nil, errors.New("unable to parse DefaultRequestsMultiplier: " + err.Error())
 	}
+	if args.ScoreBudgetMilliseconds < 0 {
+		return nil, fmt.Errorf("ScoreBudgetMilliseconds should not be negative, got %d", args.ScoreBudgetMilliseconds)
+	}
+	if args.PressureThresholdPercent < 0 || args.PressureThresholdPercent >= 100 {
+		return nil, fmt.Errorf("PressureThresholdPercent should be in [0, 100), got %d", args.PressureThresholdPercent)
+	}
+	for os, target := range args.TargetUtilizationPerOperatingSystem {
+		if target <= 0 || target > 100 {
+			return nil, fmt.Errorf("TargetUtilizationPerOperatingSystem should be in (0, 100], got %d for %q", target, os)
+		}
+	}
 	return args, nil
 }
 
 func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	// Past the budget, NormalizeScore gives every node the same score anyway.
+	if pl.scoreBudget.Exhausted(cycleState) {
+		return framework.MinNodeScore, nil
+	}
+	defer pl.scoreBudget.Observe(cycleState, time.Now())
+
 	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
 	if err != nil {
 		return framework.MinNodeScore, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
+	if !pl.osPolicy.Scores(nodeInfo.Node()) {
+		klog.V(6).InfoS("Node operating system not scored, assigning min score", "nodeName", nodeName,
+			"operatingSystem", trimaran.NodeOperatingSystem(nodeInfo.Node()))
+		return framework.MinNodeScore, nil
+	}
+	targetUtilization := pl.targetUtilization(nodeInfo.Node())
+
 	// copy value lest updateMetrics() updates it and to avoid locking for rest of the function
 	pl.mu.RLock()
 	metrics := pl.metrics
 	pl.mu.RUnlock()
 
-	// This happens if metrics were never populated since scheduler started
+	var nodeMetrics []watcher.Metric
+	coldStart := true
 	if metrics.Data.NodeMetricsMap == nil {
-		klog.ErrorS(nil, "Metrics not available from watcher, assigning 0 score to node", "nodeName", nodeName)
-		return framework.MinNodeScore, nil
-	}
-	// This means the node is new (no metrics yet) or metrics are unavailable due to 404 or 500
-	if _, ok := metrics.Data.NodeMetricsMap[nodeName]; !ok {
+		// This happens if metrics were never populated since scheduler started
+		klog.ErrorS(nil, "Metrics not available from watcher", "nodeName", nodeName)
+	} else if m, ok := metrics.Data.NodeMetricsMap[nodeName]; !ok {
+		// This means the node is new (no metrics yet) or metrics are unavailable due to 404 or 500
 		klog.InfoS("Unable to find metrics for node", "nodeName", nodeName)
-		// Avoid the node by scoring minimum
-		return framework.MinNodeScore, nil
-		// TODO(aqadeer): If this happens for a long time, fall back to allocation based packing. This could mean maintaining failure state across cycles if scheduler doesn't provide this state
+	} else {
+		nodeMetrics, coldStart = m.Metrics, false
+	}
+	if coldStart {
+		// Estimate the utilization from the requests, or avoid the node by scoring minimum, depending
+		// on the cold start policy.
+		if nodeMetrics = pl.coldStart.NodeMetrics(nodeInfo); nodeMetrics == nil {
+			return framework.MinNodeScore, nil
+		}
+		klog.V(6).InfoS("Using requests based utilization for node without metrics", "nodeName", nodeName)
 	}
 
 	var curPodCPUUsage int64
@@ -213,7 +289,7 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 
 	var nodeCPUUtilPercent float64
 	var cpuMetricFound bool
-	for _, metric := range metrics.Data.NodeMetricsMap[nodeName].Metrics {
+	for _, metric := range nodeMetrics {
 		if metric.Type == watcher.CPU {
 			if metric.Operator == watcher.Average || metric.Operator == watcher.Latest {
 				nodeCPUUtilPercent = metric.Value
@@ -223,7 +299,7 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	}
 
 	if !cpuMetricFound {
-		klog.ErrorS(nil, "Cpu metric not found in node metrics", "nodeName", nodeName, "nodeMetrics", metrics.Data.NodeMetricsMap[nodeName].Metrics)
+		klog.ErrorS(nil, "Cpu metric not found in node metrics", "nodeName", nodeName, "nodeMetrics", nodeMetrics)
 		return framework.MinNodeScore, nil
 	}
 	nodeCPUCapMillis := float64(nodeInfo.Node().Status.Capacity.Cpu().MilliValue())
@@ -234,6 +310,10 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	var missingCPUUtilMillis int64 = 0
 	pl.eventHandler.RLock()
 	for _, info := range pl.eventHandler.ScheduledPodsCache[nodeName] {
+		// The requests based utilization already accounts for the scheduled pods.
+		if coldStart {
+			break
+		}
 		// If the time stamp of the scheduled pod is outside fetched metrics window, or it is within metrics reporting interval seconds, we predict util.
 		// Note that the second condition doesn't guarantee metrics for that pod are not reported yet as the 0 <= t <= 2*metricsAgentReportingIntervalSeconds
 		// t = metricsAgentReportingIntervalSeconds is taken as average case and it doesn't hurt us much if we are
@@ -254,26 +334,88 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	if nodeCPUCapMillis != 0 {
 		predictedCPUUsage = 100 * (nodeCPUUtilMillis + float64(curPodCPUUsage) + float64(missingCPUUtilMillis)) / nodeCPUCapMillis
 	}
-	if predictedCPUUsage > float64(hostTargetUtilizationPercent) {
+	if predictedCPUUsage > float64(targetUtilization) {
 		if predictedCPUUsage > 100 {
 			return framework.MinNodeScore, framework.NewStatus(framework.Success, "")
 		}
-		penalisedScore := int64(math.Round(50 * (100 - predictedCPUUsage) / (100 - float64(hostTargetUtilizationPercent))))
+		penalisedScore := int64(math.Round(50 * (100 - predictedCPUUsage) / (100 - float64(targetUtilization))))
 		klog.V(6).InfoS("Penalised score for host", "nodeName", nodeName, "penalisedScore", penalisedScore)
-		return penalisedScore, framework.NewStatus(framework.Success, "")
+		return pl.penalizePressure(penalisedScore, nodeInfo.Node(), nodeMetrics),
+			framework.NewStatus(framework.Success, "")
 	}
 
-	score := int64(math.Round((100-float64(hostTargetUtilizationPercent))*
-		predictedCPUUsage/float64(hostTargetUtilizationPercent) + float64(hostTargetUtilizationPercent)))
+	score := int64(math.Round((100-float64(targetUtilization))*
+		predictedCPUUsage/float64(targetUtilization) + float64(targetUtilization)))
 	klog.V(6).InfoS("Score for host", "nodeName", nodeName, "score", score)
-	return score, framework.NewStatus(framework.Success, "")
+	return pl.penalizePressure(score, nodeInfo.Node(), nodeMetrics),
+		framework.NewStatus(framework.Success, "")
+}
+
+// targetUtilization returns the target CPU utilization of node, depending on its operating system.
+func (pl *TargetLoadPacking) targetUtilization(node *v1.Node) int64 {
+	if target, ok := pl.osTargetUtilization[trimaran.NodeOperatingSystem(node)]; ok {
+		return target
+	}
+	return hostTargetUtilizationPercent
+}
+
+// penalizePressure scales the score of a node down when its pressure exceeds the threshold, as CPU
+// utilization alone misses the contention on the memory or the IO. The score reaches the minimum
+// at a pressure of 100%.
+func (pl *TargetLoadPacking) penalizePressure(score int64, node *v1.Node, nodeMetrics []watcher.Metric) int64 {
+	if pl.pressureThreshold == 0 {
+		return score
+	}
+	pressure := nodePressure(node, nodeMetrics)
+	if pressure <= pl.pressureThreshold {
+		return score
+	}
+	penalisedScore := int64(math.Round(float64(score) * (100 - pressure) / (100 - pl.pressureThreshold)))
+	klog.V(6).InfoS("Penalised score for host under pressure", "nodeName", node.Name, "pressure", pressure, "penalisedScore", penalisedScore)
+	return penalisedScore
+}
+
+// nodePressure returns the highest pressure of the node, in percent, from its pressure annotations
+// and from the pressure metrics of the load watcher.
+func nodePressure(node *v1.Node, nodeMetrics []watcher.Metric) float64 {
+	var pressure float64
+	for _, metric := range nodeMetrics {
+		switch metric.Type {
+		case CPUPressure, MemoryPressure, IOPressure:
+			if metric.Value > pressure {
+				pressure = metric.Value
+			}
+		}
+	}
+	for _, annotation := range pressureAnnotations {
+		value, ok := node.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil || p < 0 || p > 100 {
+			klog.V(4).InfoS("Ignoring invalid pressure", "node", klog.KObj(node), "annotation", annotation, "pressure", value)
+			continue
+		}
+		if p > pressure {
+			pressure = p
+		}
+	}
+	return math.Min(pressure, 100)
 }
 
 func (pl *TargetLoadPacking) ScoreExtensions() framework.ScoreExtensions {
 	return pl
 }
 
-func (pl *TargetLoadPacking) NormalizeScore(context.Context, *framework.CycleState, *v1.Pod, framework.NodeScoreList) *framework.Status {
+// NormalizeScore gives every node the same score if the score budget of the cycle was exceeded, so that
+// the nodes scored before and after exceeding it are not told apart.
+func (pl *TargetLoadPacking) NormalizeScore(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	if pl.scoreBudget.Exhausted(cycleState) {
+		for i := range scores {
+			scores[i].Score = framework.MinNodeScore
+		}
+	}
 	return nil
 }
 
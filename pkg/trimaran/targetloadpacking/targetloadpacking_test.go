@@ -45,6 +45,7 @@ import (
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/config/v1beta2"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -126,12 +127,37 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 		v1.ResourceMemory: "1Gi",
 	}
 
+	annotatedNode := func(key, value string) *v1.Node {
+		node := st.MakeNode().Name("node-1").Capacity(nodeResources).Obj()
+		node.Annotations = map[string]string{key: value}
+		return node
+	}
+	idleNodeMetrics := func(metrics ...watcher.Metric) watcher.WatcherMetrics {
+		return watcher.WatcherMetrics{
+			Data: watcher.Data{
+				NodeMetricsMap: map[string]watcher.NodeMetrics{
+					"node-1": {
+						Metrics: append([]watcher.Metric{{Type: watcher.CPU, Value: 0, Operator: watcher.Latest}}, metrics...),
+					},
+				},
+			},
+		}
+	}
+
+	assignedPod := getPodWithContainersAndOverhead(0, 200)
+	assignedPod.Spec.NodeName = "node-1"
+
 	tests := []struct {
-		test            string
-		pod             *v1.Pod
-		nodes           []*v1.Node
-		watcherResponse watcher.WatcherMetrics
-		expected        framework.NodeScoreList
+		test              string
+		pod               *v1.Pod
+		nodes             []*v1.Node
+		pods              []*v1.Pod
+		watcherResponse   watcher.WatcherMetrics
+		pressureThreshold int64
+		osTargets         map[string]int64
+		excludedOS        []string
+		coldStartPolicy   pluginConfig.ColdStartPolicy
+		expected          framework.NodeScoreList
 	}{
 		{
 			test: "new node",
@@ -212,6 +238,77 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 				{Name: "node-1", Score: framework.MinNodeScore},
 			},
 		},
+		{
+			test: "node with pressure below the threshold",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				annotatedNode(v1alpha1.NodeCPUPressureAnnotation, "10.5"),
+			},
+			watcherResponse:   idleNodeMetrics(),
+			pressureThreshold: 20,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: v1beta2.DefaultTargetUtilizationPercent},
+			},
+		},
+		{
+			test: "node with memory pressure annotation penalized",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				annotatedNode(v1alpha1.NodeMemoryPressureAnnotation, "60"),
+			},
+			watcherResponse:   idleNodeMetrics(),
+			pressureThreshold: 20,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 20},
+			},
+		},
+		{
+			test: "node with IO pressure metric penalized",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			watcherResponse:   idleNodeMetrics(watcher.Metric{Type: IOPressure, Value: 100, Operator: watcher.Average}),
+			pressureThreshold: 20,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: framework.MinNodeScore},
+			},
+		},
+		{
+			test: "pressure ignored without threshold",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				annotatedNode(v1alpha1.NodeIOPressureAnnotation, "60"),
+			},
+			watcherResponse: idleNodeMetrics(),
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: v1beta2.DefaultTargetUtilizationPercent},
+			},
+		},
+		{
+			test: "target utilization of the node operating system",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Label(v1.LabelOSStable, "windows").Capacity(nodeResources).Obj(),
+			},
+			watcherResponse: idleNodeMetrics(),
+			osTargets:       map[string]int64{"windows": 60},
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 60},
+			},
+		},
+		{
+			test: "excluded operating system returns min score",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Label(v1.LabelOSStable, "windows").Capacity(nodeResources).Obj(),
+			},
+			watcherResponse: idleNodeMetrics(),
+			excludedOS:      []string{"windows"},
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: framework.MinNodeScore},
+			},
+		},
 		{
 			test: "404 resp from watcher",
 			pod:  st.MakePod().Name("p").Obj(),
@@ -223,6 +320,37 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 				{Name: "node-1", Score: framework.MinNodeScore},
 			},
 		},
+		{
+			test: "404 resp from watcher with requests based cold start",
+			pod:  getPodWithContainersAndOverhead(0, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			pods:            []*v1.Pod{assignedPod},
+			watcherResponse: watcher.WatcherMetrics{},
+			coldStartPolicy: pluginConfig.ColdStartRequestsBased,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 85},
+			},
+		},
+		{
+			test: "new node with requests based cold start",
+			pod:  getPodWithContainersAndOverhead(0, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			watcherResponse: watcher.WatcherMetrics{
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-2": {Metrics: []watcher.Metric{{Type: watcher.CPU, Value: 80, Operator: watcher.Latest}}},
+					},
+				},
+			},
+			coldStartPolicy: pluginConfig.ColdStartRequestsBased,
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 55},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,7 +368,7 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 
 			cs := testClientSet.NewSimpleClientset()
 			informerFactory := informers.NewSharedInformerFactory(cs, 0)
-			snapshot := newTestSharedLister(nil, nodes)
+			snapshot := newTestSharedLister(tt.pods, nodes)
 			fh, err := testutil.NewFramework(registeredPlugins, []config.PluginConfig{targetLoadPackingConfig},
 				"default-scheduler", runtime.WithClientSet(cs),
 				runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(snapshot))
@@ -249,6 +377,11 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 				TargetUtilization:         v1beta2.DefaultTargetUtilizationPercent,
 				WatcherAddress:            server.URL,
 				DefaultRequestsMultiplier: v1beta2.DefaultRequestsMultiplier,
+				PressureThresholdPercent:  tt.pressureThreshold,
+
+				TargetUtilizationPerOperatingSystem: tt.osTargets,
+				ExcludedOperatingSystems:            tt.excludedOS,
+				ColdStartPolicy:                     tt.coldStartPolicy,
 			}
 			p, err := New(&targetLoadPackingArgs, fh)
 			scorePlugin := p.(framework.ScorePlugin)
@@ -19,12 +19,23 @@ package util
 import (
 	"fmt"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 	"sort"
 	"strings"
 )
 
+const (
+	// AppGroupPodIndex is the name of the index of the pods by AppGroup added to pod informers by AddAppGroupPodIndex.
+	AppGroupPodIndex = "appGroup"
+
+	// DefaultCanaryWeightPercent is the share of the dependencies of its primary given to a canary workload
+	// not specifying any.
+	DefaultCanaryWeightPercent = 10
+)
+
 // Sort AppGroupTopologyList by Workload.Selector
 type ByWorkloadSelector v1alpha1.AppGroupTopologyList
 
@@ -84,6 +95,114 @@ func GetPodAppGroupSelector(pod *v1.Pod) string {
 	return pod.Labels[v1alpha1.AppGroupSelectorLabel]
 }
 
+// AppGroupPodIndexFunc : indexes the pods by the namespace/name key of their AppGroup
+func AppGroupPodIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected *v1.Pod, got %T", obj)
+	}
+	agName := GetPodAppGroupLabel(pod)
+	if agName == "" {
+		return nil, nil
+	}
+	return []string{pod.Namespace + "/" + agName}, nil
+}
+
+// AddAppGroupPodIndex : indexes the pods of a shared pod informer by AppGroup, unless another component
+// sharing the informer already did. Must be called before the informer is started.
+func AddAppGroupPodIndex(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[AppGroupPodIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{AppGroupPodIndex: AppGroupPodIndexFunc})
+}
+
+// GetAppGroupPods : returns the pods of an AppGroup from the indexer of a pod informer set up with AddAppGroupPodIndex,
+// without listing all the pods of the cluster
+func GetAppGroupPods(indexer cache.Indexer, namespace, name string) ([]*v1.Pod, error) {
+	objs, err := indexer.ByIndex(AppGroupPodIndex, namespace+"/"+name)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*v1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pods = append(pods, obj.(*v1.Pod))
+	}
+	return pods, nil
+}
+
+// WithCanaryDependencies : returns the workloads with the canary ones given the dependencies of their primary they do
+// not declare themselves, the weight and the minimum bandwidths of the dependencies discounted by the weight percent of
+// the canary. Canaries of unknown workloads, or of other canaries, are left as they are. The workloads are returned
+// unchanged if there is no canary among them.
+func WithCanaryDependencies(workloads v1alpha1.AppGroupWorkloadList) v1alpha1.AppGroupWorkloadList {
+	primaries := make(map[string]v1alpha1.AppGroupWorkload, len(workloads))
+	hasCanary := false
+	for _, w := range workloads {
+		primaries[w.Workload.Name] = w
+		hasCanary = hasCanary || w.Canary != nil
+	}
+	if !hasCanary {
+		return workloads
+	}
+
+	expanded := make(v1alpha1.AppGroupWorkloadList, 0, len(workloads))
+	for _, w := range workloads {
+		primary, ok := v1alpha1.AppGroupWorkload{}, false
+		if w.Canary != nil {
+			primary, ok = primaries[w.Canary.Primary]
+		}
+		if !ok || primary.Canary != nil || primary.Workload.Name == w.Workload.Name {
+			expanded = append(expanded, w)
+			continue
+		}
+
+		percent := int64(DefaultCanaryWeightPercent)
+		if w.Canary.WeightPercent != nil {
+			percent = int64(*w.Canary.WeightPercent)
+		}
+		declared := make(map[string]bool, len(w.Dependencies))
+		for _, d := range w.Dependencies {
+			declared[d.Workload.Name] = true
+		}
+		canary := *w.DeepCopy()
+		for _, d := range primary.Dependencies {
+			if declared[d.Workload.Name] {
+				continue
+			}
+			canary.Dependencies = append(canary.Dependencies, discountDependency(d, percent))
+		}
+		expanded = append(expanded, canary)
+	}
+	return expanded
+}
+
+// discountDependency : returns a copy of d with its weight and minimum bandwidths scaled by percent. Weights are
+// rounded up, so that only a percent of 0 makes the dependency ignored for scoring.
+func discountDependency(d v1alpha1.DependenciesInfo, percent int64) v1alpha1.DependenciesInfo {
+	discounted := *d.DeepCopy()
+	weight := int64(1)
+	if d.Weight != nil {
+		weight = int64(*d.Weight)
+	}
+	discountedWeight := int32((weight*percent + 99) / 100)
+	discounted.Weight = &discountedWeight
+
+	scale := func(q resource.Quantity) resource.Quantity {
+		return *resource.NewQuantity(q.Value()*percent/100, q.Format)
+	}
+	discounted.MinBandwidth = scale(d.MinBandwidth)
+	if d.MinIngressBandwidth != nil {
+		q := scale(*d.MinIngressBandwidth)
+		discounted.MinIngressBandwidth = &q
+	}
+	if d.MinEgressBandwidth != nil {
+		q := scale(*d.MinEgressBandwidth)
+		discounted.MinEgressBandwidth = &q
+	}
+	return discounted
+}
+
 // Implementation of Topology Sorting algorithms based on https://github.com/otaviokr/topological-sort
 // KahnSort : receives a tree (AppGroup Service Topology) and returns an array with the pods sorted.
 func KahnSort(tree map[string][]string) ([]string, error) {
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestGetAppGroupPods(t *testing.T) {
+	podInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Pods()
+	// Adding the index twice, as components sharing the informer do, is harmless.
+	for i := 0; i < 2; i++ {
+		if err := AddAppGroupPodIndex(podInformer.Informer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	indexer := podInformer.Informer().GetIndexer()
+	for _, pod := range []interface{}{
+		st.MakePod().Namespace("default").Name("p1").Label(v1alpha1.AppGroupLabel, "shop").Obj(),
+		st.MakePod().Namespace("default").Name("p2").Label(v1alpha1.AppGroupLabel, "shop").Obj(),
+		// Same AppGroup name in another namespace.
+		st.MakePod().Namespace("other").Name("p3").Label(v1alpha1.AppGroupLabel, "shop").Obj(),
+		st.MakePod().Namespace("default").Name("p4").Label(v1alpha1.AppGroupLabel, "blog").Obj(),
+		st.MakePod().Namespace("default").Name("p5").Obj(),
+	} {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pods, err := GetAppGroupPods(indexer, "default", "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "p1" || names[1] != "p2" {
+		t.Errorf("expected pods [p1 p2], got %v", names)
+	}
+}
+
+func TestWithCanaryDependencies(t *testing.T) {
+	workload := func(name string) v1alpha1.AppGroupWorkloadInfo {
+		return v1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: name, Selector: name, APIVersion: "apps/v1"}
+	}
+	weight, percent := int32(4), int32(50)
+	cache := resource.MustParse("20Mi")
+	workloads := v1alpha1.AppGroupWorkloadList{
+		{Workload: workload("api"), Dependencies: v1alpha1.DependenciesList{
+			{Workload: workload("db"), MinBandwidth: resource.MustParse("100Mi"), Weight: &weight},
+			{Workload: workload("cache"), MinBandwidth: resource.MustParse("10Mi"), MinEgressBandwidth: &cache},
+		}},
+		// The canary declares its own dependency to the cache, which is kept.
+		{Workload: workload("api-canary"), Canary: &v1alpha1.AppGroupCanary{Primary: "api", WeightPercent: &percent},
+			Dependencies: v1alpha1.DependenciesList{{Workload: workload("cache"), MinBandwidth: resource.MustParse("1Mi")}}},
+		{Workload: workload("api-blue"), Canary: &v1alpha1.AppGroupCanary{Primary: "api"}},
+		{Workload: workload("orphan"), Canary: &v1alpha1.AppGroupCanary{Primary: "unknown"}},
+		{Workload: workload("db")},
+		{Workload: workload("cache")},
+	}
+
+	got := WithCanaryDependencies(workloads)
+	if len(got) != len(workloads) {
+		t.Fatalf("expected %d workloads, got %d", len(workloads), len(got))
+	}
+	if len(workloads[1].Dependencies) != 1 || len(workloads[2].Dependencies) != 0 {
+		t.Errorf("expected the workloads given to be left untouched, got %v", workloads)
+	}
+
+	type dependency struct {
+		name         string
+		weight       int32
+		minBandwidth string
+	}
+	expected := map[string][]dependency{
+		"api-canary": {{name: "cache", minBandwidth: "1Mi"}, {name: "db", weight: 2, minBandwidth: "50Mi"}},
+		// The default percent is 10, weights being rounded up.
+		"api-blue": {{name: "db", weight: 1, minBandwidth: "10Mi"}, {name: "cache", weight: 1, minBandwidth: "1Mi"}},
+		"orphan":   nil,
+	}
+	for _, w := range got {
+		want, ok := expected[w.Workload.Name]
+		if !ok {
+			continue
+		}
+		if len(w.Dependencies) != len(want) {
+			t.Errorf("%s: expected %d dependencies, got %v", w.Workload.Name, len(want), w.Dependencies)
+			continue
+		}
+		for i, d := range w.Dependencies {
+			var gotWeight int32
+			if d.Weight != nil {
+				gotWeight = *d.Weight
+			}
+			if d.Workload.Name != want[i].name || gotWeight != want[i].weight || d.MinBandwidth.Cmp(resource.MustParse(want[i].minBandwidth)) != 0 {
+				t.Errorf("%s: expected dependency %v, got %s of weight %d and %v", w.Workload.Name, want[i], d.Workload.Name, gotWeight, d.MinBandwidth.String())
+			}
+		}
+	}
+	if egress := got[2].Dependencies[1].MinEgressBandwidth; egress == nil || egress.Cmp(resource.MustParse("2Mi")) != 0 {
+		t.Errorf("expected the minimum egress bandwidth to the cache discounted to 2Mi, got %v", egress)
+	}
+}
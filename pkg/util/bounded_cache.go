@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// EvictionReasonSize is the reason of the evictions making room for a new entry.
+	EvictionReasonSize = "size"
+	// EvictionReasonExpired is the reason of the evictions of expired entries.
+	EvictionReasonExpired = "expired"
+)
+
+var (
+	boundedCacheEvictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "bounded_cache_evictions_total",
+			Help:           "Number of entries evicted from the bounded caches of the plugins and controllers, by cache and reason.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"cache", "reason"})
+
+	registerBoundedCacheMetrics sync.Once
+)
+
+// BoundedCache is a cache holding at most a given number of entries, each expiring after a TTL,
+// so that the state kept per object does not grow with cluster churn. Once full, the least
+// recently used entry is evicted to make room for a new one. It is safe for concurrent use.
+type BoundedCache struct {
+	name       string
+	maxSize    int
+	defaultTTL time.Duration
+	clock      clock.PassiveClock
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds the entries, the most recently used first.
+	lru *list.List
+}
+
+type boundedCacheEntry struct {
+	key   string
+	value interface{}
+	// expiry is zero for an entry never expiring.
+	expiry time.Time
+}
+
+// NewBoundedCache returns a cache named name in the metrics, holding at most maxSize entries,
+// expiring after defaultTTL unless added with another TTL. A maxSize or a TTL of 0 means no bound.
+func NewBoundedCache(name string, maxSize int, defaultTTL time.Duration) *BoundedCache {
+	return newBoundedCacheWithClock(name, maxSize, defaultTTL, clock.RealClock{})
+}
+
+func newBoundedCacheWithClock(name string, maxSize int, defaultTTL time.Duration, clock clock.PassiveClock) *BoundedCache {
+	registerBoundedCacheMetrics.Do(func() {
+		legacyregistry.MustRegister(boundedCacheEvictions)
+	})
+	return &BoundedCache{
+		name:       name,
+		maxSize:    maxSize,
+		defaultTTL: defaultTTL,
+		clock:      clock,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Add sets the value of key, expiring after ttl, 0 meaning never.
+func (c *BoundedCache) Add(key string, value interface{}, ttl time.Duration) {
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = c.clock.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*boundedCacheEntry)
+		entry.value, entry.expiry = value, expiry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&boundedCacheEntry{key: key, value: value, expiry: expiry})
+	if c.maxSize > 0 && c.lru.Len() > c.maxSize {
+		c.evictLocked(c.lru.Back(), EvictionReasonSize)
+	}
+}
+
+// SetDefault sets the value of key, expiring after the default TTL of the cache.
+func (c *BoundedCache) SetDefault(key string, value interface{}) {
+	c.Add(key, value, c.defaultTTL)
+}
+
+// Get returns the value of key, and whether it is in the cache and not expired.
+func (c *BoundedCache) Get(key string) (interface{}, bool) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*boundedCacheEntry)
+	if entry.expired(now) {
+		c.evictLocked(e, EvictionReasonExpired)
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return entry.value, true
+}
+
+// Delete removes key from the cache.
+func (c *BoundedCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Keys returns the keys of the entries not expired, the most recently used first. The expired
+// entries are evicted.
+func (c *BoundedCache) Keys() []string {
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, c.lru.Len())
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*boundedCacheEntry); entry.expired(now) {
+			c.evictLocked(e, EvictionReasonExpired)
+		} else {
+			keys = append(keys, entry.key)
+		}
+		e = next
+	}
+	return keys
+}
+
+// Len returns the number of entries in the cache, expired ones included until they are evicted.
+func (c *BoundedCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *BoundedCache) evictLocked(e *list.Element, reason string) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*boundedCacheEntry).key)
+	boundedCacheEvictions.WithLabelValues(c.name, reason).Inc()
+}
+
+func (e *boundedCacheEntry) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestBoundedCache(t *testing.T) {
+	clock := testingclock.NewFakePassiveClock(time.Now())
+	c := newBoundedCacheWithClock("test", 2, time.Minute, clock)
+
+	c.SetDefault("a", 1)
+	c.Add("b", 2, 0)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("want a=1, got %v, %v", v, ok)
+	}
+	// b is the least recently used entry, evicted to make room for c.
+	c.SetDefault("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("want b evicted")
+	}
+	if got, want := c.Keys(), []string{"c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want keys %v, got %v", want, got)
+	}
+
+	// a is evicted to make room for d.
+	c.Add("d", 4, 2*time.Minute)
+	clock.SetTime(clock.Now().Add(time.Minute))
+	if _, ok := c.Get("c"); ok {
+		t.Errorf("want c expired")
+	}
+	if got, want := c.Keys(), []string{"d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want keys %v, got %v", want, got)
+	}
+	c.Delete("d")
+	if c.Len() != 0 {
+		t.Errorf("want an empty cache, got %d entries", c.Len())
+	}
+
+	for reason, want := range map[string]float64{EvictionReasonSize: 2, EvictionReasonExpired: 1} {
+		count, err := testutil.GetCounterMetricValue(boundedCacheEvictions.WithLabelValues("test", reason))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("want %v evictions for %s, got %v", want, reason, count)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const clientSubsystem = "scheduler_plugins"
+
+var (
+	clientRequestDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      clientSubsystem,
+			Name:           "client_request_duration_seconds",
+			Help:           "API server request latency in seconds of the scheduler-plugins clients, broken down by client, verb and status code.",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"client", "verb", "code"})
+
+	clientRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      clientSubsystem,
+			Name:           "client_requests_total",
+			Help:           "Number of API server requests of the scheduler-plugins clients, broken down by client, verb and status code.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"client", "verb", "code"})
+
+	registerClientMetrics sync.Once
+)
+
+// NewClientConfig returns a copy of the given rest config for the client named name. The
+// QPS and burst of the copy are overridden if qps and burst are positive, otherwise the ones
+// of the given config are kept. Requests issued through the copy are recorded in the
+// scheduler_plugins_client_* metrics.
+func NewClientConfig(config *rest.Config, name string, qps, burst int32) *rest.Config {
+	registerClientMetrics.Do(func() {
+		legacyregistry.MustRegister(clientRequestDuration, clientRequests)
+	})
+
+	c := rest.CopyConfig(config)
+	if qps > 0 {
+		c.QPS = float32(qps)
+	}
+	if burst > 0 {
+		c.Burst = int(burst)
+	}
+	if len(c.UserAgent) == 0 {
+		c.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	c.UserAgent += "/" + name
+	c.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &instrumentedRoundTripper{name: name, delegate: rt}
+	})
+	return c
+}
+
+// instrumentedRoundTripper records the latency and the status code of the requests.
+type instrumentedRoundTripper struct {
+	name     string
+	delegate http.RoundTripper
+}
+
+func (rt *instrumentedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.delegate.RoundTrip(req)
+	code := "<error>"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	clientRequestDuration.WithLabelValues(rt.name, req.Method, code).Observe(time.Since(start).Seconds())
+	clientRequests.WithLabelValues(rt.name, req.Method, code).Inc()
+	return resp, err
+}
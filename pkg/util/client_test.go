@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestNewClientConfig(t *testing.T) {
+	base := &rest.Config{Host: "https://localhost", QPS: 5, Burst: 10}
+	tests := []struct {
+		name          string
+		qps           int32
+		burst         int32
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		{
+			name:          "zero values keep the base config",
+			expectedQPS:   5,
+			expectedBurst: 10,
+		},
+		{
+			name:          "positive values override the base config",
+			qps:           50,
+			burst:         100,
+			expectedQPS:   50,
+			expectedBurst: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientConfig(base, "test", tt.qps, tt.burst)
+			if c.QPS != tt.expectedQPS || c.Burst != tt.expectedBurst {
+				t.Errorf("expected QPS %v and burst %v, got %v and %v", tt.expectedQPS, tt.expectedBurst, c.QPS, c.Burst)
+			}
+			if base.QPS != 5 || base.Burst != 10 || base.WrapTransport != nil {
+				t.Errorf("base config must not be modified")
+			}
+		})
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := kubernetes.NewForConfigOrDie(NewClientConfig(&rest.Config{Host: server.URL}, "metrics-test", 0, 0))
+	client.CoreV1().Pods("default").Get(context.TODO(), "pod", metav1.GetOptions{})
+
+	count, err := testutil.GetCounterMetricValue(clientRequests.WithLabelValues("metrics-test", http.MethodGet, "404"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 request recorded, got %v", count)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	latencyBudgetViolations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "latency_budget_violations_total",
+			Help:           "Number of scheduling cycles in which a plugin exceeded its latency budget at an extension point and degraded to neutral results.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "extension_point"})
+
+	registerLatencyBudgetMetrics sync.Once
+)
+
+// LatencyBudget bounds the time a plugin spends at an extension point within a scheduling cycle.
+// Once the budget of a cycle is spent, the plugin is expected to degrade gracefully, e.g. by giving
+// the same score to every node, rather than slowing the whole cycle down.
+// A nil LatencyBudget, or one with a zero budget, is never exhausted.
+type LatencyBudget struct {
+	plugin         string
+	extensionPoint string
+	budget         time.Duration
+	stateKey       framework.StateKey
+	// mu serializes the creation of the state of a cycle by concurrent calls.
+	mu sync.Mutex
+}
+
+// latencyBudgetState records the time spent in a scheduling cycle.
+type latencyBudgetState struct {
+	// spent is in nanoseconds.
+	spent    int64
+	exceeded int32
+}
+
+// Clone the latency budget state. The state is shared, the time spent by a plugin
+// accounting for the whole cycle.
+func (s *latencyBudgetState) Clone() framework.StateData {
+	return s
+}
+
+// NewLatencyBudget returns the budget of plugin at extensionPoint, disabled if budget is 0.
+func NewLatencyBudget(plugin, extensionPoint string, budget time.Duration) *LatencyBudget {
+	registerLatencyBudgetMetrics.Do(func() {
+		legacyregistry.MustRegister(latencyBudgetViolations)
+	})
+	return &LatencyBudget{
+		plugin:         plugin,
+		extensionPoint: extensionPoint,
+		budget:         budget,
+		stateKey:       framework.StateKey("LatencyBudget" + plugin + extensionPoint),
+	}
+}
+
+func (b *LatencyBudget) enabled(state *framework.CycleState) bool {
+	return b != nil && b.budget > 0 && state != nil
+}
+
+func (b *LatencyBudget) state(state *framework.CycleState) *latencyBudgetState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, err := state.Read(b.stateKey); err == nil {
+		if s, ok := c.(*latencyBudgetState); ok {
+			return s
+		}
+	}
+	s := &latencyBudgetState{}
+	state.Write(b.stateKey, s)
+	return s
+}
+
+// Exhausted returns whether the budget of the scheduling cycle is spent.
+func (b *LatencyBudget) Exhausted(state *framework.CycleState) bool {
+	if !b.enabled(state) {
+		return false
+	}
+	return atomic.LoadInt32(&b.state(state).exceeded) != 0
+}
+
+// Observe accounts the time elapsed since start to the scheduling cycle, and reports a violation
+// the first time the budget of the cycle is exceeded.
+func (b *LatencyBudget) Observe(state *framework.CycleState, start time.Time) {
+	if !b.enabled(state) {
+		return
+	}
+	s := b.state(state)
+	spent := time.Duration(atomic.AddInt64(&s.spent, int64(time.Since(start))))
+	if spent > b.budget && atomic.CompareAndSwapInt32(&s.exceeded, 0, 1) {
+		latencyBudgetViolations.WithLabelValues(b.plugin, b.extensionPoint).Inc()
+		klog.V(4).InfoS("Plugin exceeded its latency budget, degrading for the rest of the cycle",
+			"plugin", b.plugin, "extensionPoint", b.extensionPoint, "budget", b.budget, "spent", spent)
+	}
+}
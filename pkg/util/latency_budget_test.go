@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func TestLatencyBudget(t *testing.T) {
+	var disabled *LatencyBudget
+	state := framework.NewCycleState()
+	disabled.Observe(state, time.Now().Add(-time.Hour))
+	if disabled.Exhausted(state) {
+		t.Errorf("nil budget should never be exhausted")
+	}
+	unlimited := NewLatencyBudget("Test", "Unlimited", 0)
+	unlimited.Observe(state, time.Now().Add(-time.Hour))
+	if unlimited.Exhausted(state) {
+		t.Errorf("zero budget should never be exhausted")
+	}
+
+	budget := NewLatencyBudget("Test", "Score", time.Minute)
+	budget.Observe(state, time.Now().Add(-40*time.Second))
+	if budget.Exhausted(state) {
+		t.Errorf("budget should not be exhausted after 40s of 1m")
+	}
+	budget.Observe(state.Clone(), time.Now().Add(-40*time.Second))
+	if !budget.Exhausted(state) {
+		t.Errorf("budget should be exhausted after 80s of 1m")
+	}
+	budget.Observe(state, time.Now().Add(-40*time.Second))
+	if budget.Exhausted(framework.NewCycleState()) {
+		t.Errorf("budget of a new cycle should not be exhausted")
+	}
+
+	count, err := testutil.GetCounterMetricValue(latencyBudgetViolations.WithLabelValues("Test", "Score"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 violation recorded, got %v", count)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NodeFilter selects the nodes counted in the topology of a cluster, so that the controller and the
+// plugins leave out the same NotReady, cordoned, tainted or unlabeled nodes. The zero value selects all nodes.
+type NodeFilter struct {
+	// IgnoreNotReady leaves out the nodes whose Ready condition is not true.
+	IgnoreNotReady bool
+	// IgnoreUnschedulable leaves out the cordoned nodes.
+	IgnoreUnschedulable bool
+	// IgnoredTaints leaves out the nodes with a taint of one of these keys.
+	IgnoredTaints []string
+	// Selector, if set, leaves out the nodes whose labels it does not match, e.g. the control-plane or
+	// virtual kubelet nodes.
+	Selector labels.Selector
+}
+
+// Selects tells whether node is counted in the topology.
+func (f NodeFilter) Selects(node *v1.Node) bool {
+	if node == nil {
+		return false
+	}
+	if f.IgnoreUnschedulable && node.Spec.Unschedulable {
+		return false
+	}
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if f.IgnoreNotReady && !IsNodeReady(node) {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range f.IgnoredTaints {
+			if taint.Key == key {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Filter returns the nodes selected by f, nodes itself if f selects all nodes.
+func (f NodeFilter) Filter(nodes []v1.Node) []v1.Node {
+	if !f.IgnoreNotReady && !f.IgnoreUnschedulable && len(f.IgnoredTaints) == 0 && (f.Selector == nil || f.Selector.Empty()) {
+		return nodes
+	}
+	selected := make([]v1.Node, 0, len(nodes))
+	for i := range nodes {
+		if f.Selects(&nodes[i]) {
+			selected = append(selected, nodes[i])
+		}
+	}
+	return selected
+}
+
+// IsNodeReady tells whether the Ready condition of node is true.
+func IsNodeReady(node *v1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
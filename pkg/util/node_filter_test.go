@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestNodeFilter(t *testing.T) {
+	ready := func(node *v1.Node, status v1.ConditionStatus) *v1.Node {
+		node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: status}}
+		return node
+	}
+	cordoned := st.MakeNode().Name("cordoned").Obj()
+	cordoned.Spec.Unschedulable = true
+	tainted := st.MakeNode().Name("tainted").Obj()
+	tainted.Spec.Taints = []v1.Taint{{Key: "maintenance", Effect: v1.TaintEffectNoSchedule}}
+	nodes := []v1.Node{
+		*ready(st.MakeNode().Name("ready").Obj(), v1.ConditionTrue),
+		*ready(st.MakeNode().Name("not-ready").Obj(), v1.ConditionFalse),
+		*ready(st.MakeNode().Name("unknown").Obj(), v1.ConditionUnknown),
+		*ready(cordoned, v1.ConditionTrue),
+		*ready(tainted, v1.ConditionTrue),
+		*ready(st.MakeNode().Name("control-plane").Label("node-role.kubernetes.io/control-plane", "").Obj(), v1.ConditionTrue),
+	}
+	workers, err := labels.Parse("!node-role.kubernetes.io/control-plane")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   NodeFilter
+		expected []string
+	}{
+		{
+			name:     "all nodes",
+			expected: []string{"ready", "not-ready", "unknown", "cordoned", "tainted", "control-plane"},
+		},
+		{
+			name:     "not ready nodes ignored",
+			filter:   NodeFilter{IgnoreNotReady: true},
+			expected: []string{"ready", "cordoned", "tainted", "control-plane"},
+		},
+		{
+			name:     "cordoned nodes ignored",
+			filter:   NodeFilter{IgnoreUnschedulable: true},
+			expected: []string{"ready", "not-ready", "unknown", "tainted", "control-plane"},
+		},
+		{
+			name:     "tainted nodes ignored",
+			filter:   NodeFilter{IgnoredTaints: []string{"other", "maintenance"}},
+			expected: []string{"ready", "not-ready", "unknown", "cordoned", "control-plane"},
+		},
+		{
+			name:     "unselected nodes ignored",
+			filter:   NodeFilter{Selector: workers},
+			expected: []string{"ready", "not-ready", "unknown", "cordoned", "tainted"},
+		},
+		{
+			name:     "all filters",
+			filter:   NodeFilter{IgnoreNotReady: true, IgnoreUnschedulable: true, IgnoredTaints: []string{"maintenance"}, Selector: workers},
+			expected: []string{"ready"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, n := range tt.filter.Filter(nodes) {
+				got = append(got, n.Name)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	listers "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+// IsVirtualNode tells whether node is a virtual or edge node standing for downstream capacity.
+func IsVirtualNode(node *v1.Node) bool {
+	return node.Labels[v1alpha1.VirtualNodeLabel] == "true"
+}
+
+// GetVirtualNodeProfile returns the VirtualNodeProfile exported by node, and false if node is not
+// a virtual node, lister is nil or the profile is not found.
+func GetVirtualNodeProfile(lister listers.VirtualNodeProfileLister, node *v1.Node) (*v1alpha1.VirtualNodeProfile, bool) {
+	if lister == nil || node == nil || !IsVirtualNode(node) {
+		return nil, false
+	}
+	profile, err := lister.Get(node.Name)
+	if err != nil {
+		return nil, false
+	}
+	return profile, true
+}
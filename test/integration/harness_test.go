@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/scheduler"
+	schedapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/controller"
+	"sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	"sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// The helpers below make up an end-to-end test of a plugin, run against the envtest API server
+// started by TestMain with the CRDs of all the plugins installed:
+//
+//	testCtx := newTestContext(t)
+//	defer cleanupTest(t, testCtx)
+//	startControllers(testCtx)
+//	profile := newDefaultProfile(t)
+//	profile.Plugins.Filter.Enabled = append(profile.Plugins.Filter.Enabled, schedapi.Plugin{Name: myplugin.Name})
+//	testCtx = startScheduler(t, testCtx, fwkruntime.Registry{myplugin.Name: myplugin.New}, profile)
+//
+// then create nodes, custom resources and pods with testCtx.ClientSet and testCtx.ExtClientSet,
+// and wait for the outcome with podScheduled or podUnschedulable.
+
+// newTestContext returns a testContext connected to the envtest API server, once the
+// scheduling.sigs.k8s.io CRDs are served.
+func newTestContext(t *testing.T) *testContext {
+	testCtx := &testContext{
+		ClientSet:    clientset.NewForConfigOrDie(globalKubeConfig),
+		ExtClientSet: versioned.NewForConfigOrDie(globalKubeConfig),
+		KubeConfig:   globalKubeConfig,
+	}
+	testCtx.Ctx, testCtx.CancelFn = context.WithCancel(context.Background())
+
+	if err := wait.Poll(100*time.Millisecond, 3*time.Second, func() (done bool, err error) {
+		groupList, _, err := testCtx.ClientSet.Discovery().ServerGroupsAndResources()
+		if err != nil {
+			return false, nil
+		}
+		for _, group := range groupList {
+			if group.Name == scheduling.GroupName {
+				return true, nil
+			}
+		}
+		return false, nil
+	}); err != nil {
+		t.Fatalf("Timed out waiting for CRD to be ready: %v", err)
+	}
+	return testCtx
+}
+
+// startControllers runs the controllers of the scheduler-plugins controller, as cmd/controller
+// does, until testCtx is cancelled.
+func startControllers(testCtx *testContext) {
+	cs, extClient := testCtx.ClientSet, testCtx.ExtClientSet
+	schedInformerFactory := schedformers.NewSharedInformerFactory(extClient, 0)
+	pgInformer := schedInformerFactory.Scheduling().V1alpha1().PodGroups()
+	eqInformer := schedInformerFactory.Scheduling().V1alpha1().ElasticQuotas()
+	agInformer := schedInformerFactory.Scheduling().V1alpha1().AppGroups()
+	ntInformer := schedInformerFactory.Scheduling().V1alpha1().NetworkTopologies()
+	nbpInformer := schedInformerFactory.Scheduling().V1alpha1().NodeBandwidthProfiles()
+	lhInformer := schedInformerFactory.Scheduling().V1alpha1().LinkHotspots()
+
+	coreInformerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := coreInformerFactory.Core().V1().Pods()
+	jobInformer := coreInformerFactory.Batch().V1().Jobs()
+	nodeInformer := coreInformerFactory.Core().V1().Nodes()
+	rsInformer := coreInformerFactory.Apps().V1().ReplicaSets()
+
+	controllers := []interface {
+		Run(workers int, stopCh <-chan struct{})
+	}{
+		controller.NewPodGroupController(cs, pgInformer, podInformer, jobInformer, extClient),
+		controller.NewElasticQuotaController(cs, eqInformer, podInformer, extClient),
+		controller.NewAppGroupController(cs, agInformer, podInformer, ntInformer, extClient, controller.CostSmoothing{}),
+		controller.NewNodeBandwidthController(cs, nbpInformer, nodeInformer),
+		controller.NewAppGroupMembershipController(cs, agInformer, rsInformer, jobInformer, podInformer),
+		controller.NewLinkHotspotController(extClient, ntInformer, lhInformer, 90),
+	}
+	for _, c := range controllers {
+		go c.Run(1, testCtx.Ctx.Done())
+	}
+	schedInformerFactory.Start(testCtx.Ctx.Done())
+	coreInformerFactory.Start(testCtx.Ctx.Done())
+}
+
+// newDefaultProfile returns the default scheduler profile, for the test to enable its plugins in.
+func newDefaultProfile(t *testing.T) schedapi.KubeSchedulerProfile {
+	cfg, err := util.NewDefaultSchedulerComponentConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.Profiles[0]
+}
+
+// startScheduler runs a scheduler with the plugins of registry and the given profiles, the default
+// one if none is given, until testCtx is cancelled.
+func startScheduler(t *testing.T, testCtx *testContext, registry fwkruntime.Registry, profiles ...schedapi.KubeSchedulerProfile) *testContext {
+	opts := []scheduler.Option{scheduler.WithFrameworkOutOfTreeRegistry(registry)}
+	if len(profiles) > 0 {
+		opts = append(opts, scheduler.WithProfiles(profiles...))
+	}
+	testCtx = initTestSchedulerWithOptions(t, testCtx, opts...)
+	syncInformerFactory(testCtx)
+	go testCtx.Scheduler.Run(testCtx.Ctx)
+	return testCtx
+}
+
+// podUnschedulable returns true if the scheduler failed to find a node for the given pod.
+func podUnschedulable(c clientset.Interface, podNamespace, podName string) bool {
+	pod, err := c.CoreV1().Pods(podNamespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse && cond.Reason == v1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
@@ -29,10 +29,13 @@ import (
 var globalKubeConfig *rest.Config
 
 func TestMain(m *testing.M) {
+	// The CRDs of all the plugins, so that tests can exercise any plugin or controller.
+	crds, err := filepath.Glob(filepath.Join("..", "..", "manifests", "*", "crd.yaml"))
+	if err != nil {
+		log.Fatal(err)
+	}
 	testEnv := &envtest.Environment{
-		CRDDirectoryPaths: []string{
-			filepath.Join("..", "..", "manifests", "crds"),
-		},
+		CRDInstallOptions: envtest.CRDInstallOptions{Paths: crds},
 	}
 	apiServerArgs := testEnv.ControlPlane.GetAPIServer().Configure()
 	apiServerArgs.Append("disable-admission-plugins", "TaintNodesByCondition", "Priority")
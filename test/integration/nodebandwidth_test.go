@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	schedapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/nodebandwidth"
+)
+
+func TestNodeBandwidthPlugin(t *testing.T) {
+	testCtx := newTestContext(t)
+	startControllers(testCtx)
+
+	profile := newDefaultProfile(t)
+	profile.Plugins.Filter.Enabled = append(profile.Plugins.Filter.Enabled, schedapi.Plugin{Name: nodebandwidth.Name})
+	testCtx = startScheduler(t, testCtx, fwkruntime.Registry{nodebandwidth.Name: nodebandwidth.New}, profile)
+	t.Log("Init scheduler success")
+	defer cleanupTest(t, testCtx)
+
+	nbp := &v1alpha1.NodeBandwidthProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "m5-large"},
+		Spec:       v1alpha1.NodeBandwidthProfileSpec{InstanceType: "m5.large", EgressBandwidth: resource.MustParse("10G")},
+	}
+	if _, err := testCtx.ExtClientSet.SchedulingV1alpha1().NodeBandwidthProfiles().Create(testCtx.Ctx, nbp, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create NodeBandwidthProfile: %v", err)
+	}
+	defer testCtx.ExtClientSet.SchedulingV1alpha1().NodeBandwidthProfiles().Delete(testCtx.Ctx, nbp.Name, metav1.DeleteOptions{})
+
+	nodeName := "fake-node"
+	node := st.MakeNode().Name(nodeName).Label(v1.LabelInstanceTypeStable, "m5.large").Capacity(map[v1.ResourceName]string{
+		v1.ResourcePods:   "32",
+		v1.ResourceCPU:    "4",
+		v1.ResourceMemory: "16Gi",
+	}).Obj()
+	if _, err := testCtx.ClientSet.CoreV1().Nodes().Create(testCtx.Ctx, node, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create Node %q: %v", nodeName, err)
+	}
+	// The controller derives the egress capacity of the node from the profile of its instance type.
+	if err := wait.Poll(100*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		n, err := testCtx.ClientSet.CoreV1().Nodes().Get(testCtx.Ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return n.Annotations[v1alpha1.NodeEgressBandwidthCapacityAnnotation] == "10G", nil
+	}); err != nil {
+		t.Fatalf("Node %q not annotated with its egress capacity: %v", nodeName, err)
+	}
+
+	ns := fmt.Sprintf("integration-test-%v", string(uuid.NewUUID()))
+	createNamespace(t, testCtx, ns)
+	pause := imageutils.GetPauseImageName()
+	makePod := func(name, egress string) *v1.Pod {
+		pod := WithContainer(st.MakePod().Namespace(ns).Name(name).ZeroTerminationGracePeriod().Obj(), pause)
+		pod.Annotations = map[string]string{nodebandwidth.EgressBandwidthAnnotation: egress}
+		return pod
+	}
+	fitting, tooLarge := makePod("fitting", "4G"), makePod("too-large", "25G")
+	for _, pod := range []*v1.Pod{fitting, tooLarge} {
+		if _, err := testCtx.ClientSet.CoreV1().Pods(ns).Create(testCtx.Ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create Pod %q: %v", pod.Name, err)
+		}
+	}
+	defer cleanupPods(t, testCtx, []*v1.Pod{fitting, tooLarge})
+
+	if err := wait.Poll(100*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return podScheduled(testCtx.ClientSet, ns, fitting.Name), nil
+	}); err != nil {
+		t.Errorf("Pod %q to be scheduled, error: %v", fitting.Name, err)
+	}
+	if err := wait.Poll(100*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return podUnschedulable(testCtx.ClientSet, ns, tooLarge.Name), nil
+	}); err != nil {
+		t.Errorf("Pod %q to be unschedulable, error: %v", tooLarge.Name, err)
+	}
+}
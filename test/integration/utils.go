@@ -39,6 +39,7 @@ import (
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
 )
 
 var lowPriority, midPriority, highPriority = int32(0), int32(100), int32(1000)
@@ -114,6 +115,11 @@ func (p *podWrapper) InitContainerRequest(request v1.ResourceList) *podWrapper {
 	return p
 }
 
+func (p *podWrapper) Overhead(overhead v1.ResourceList) *podWrapper {
+	p.Pod.Spec.Overhead = overhead
+	return p
+}
+
 func (p *podWrapper) Node(name string) *podWrapper {
 	p.Pod.Spec.NodeName = name
 	return p
@@ -157,6 +163,7 @@ func (e *eqWrapper) Obj() *v1alpha1.ElasticQuota {
 
 type testContext struct {
 	ClientSet          clientset.Interface
+	ExtClientSet       versioned.Interface
 	KubeConfig         *restclient.Config
 	InformerFactory    informers.SharedInformerFactory
 	DynInformerFactory dynamicinformer.DynamicSharedInformerFactory